@@ -4,9 +4,11 @@ package earthengine
 // Note: Function names should NOT include the "algorithms/" prefix.
 const (
 	// Image algorithms
-	AlgorithmImageLoad         = "Image.load"
-	AlgorithmImageSelect       = "Image.select"
-	AlgorithmImageReduceRegion = "Image.reduceRegion"
+	AlgorithmImageLoad          = "Image.load"
+	AlgorithmImageSelect        = "Image.select"
+	AlgorithmImageReduce        = "Image.reduce"
+	AlgorithmImageReduceRegion  = "Image.reduceRegion"
+	AlgorithmImageReduceRegions = "Image.reduceRegions"
 
 	// ImageCollection algorithms
 	AlgorithmImageCollectionLoad           = "ImageCollection.load"
@@ -16,31 +18,144 @@ const (
 	AlgorithmImageCollectionFilterDate     = "ImageCollection.filterDate"
 	AlgorithmImageCollectionReduce         = "ImageCollection.reduce"
 	AlgorithmImageCollectionCount          = "ImageCollection.count"
+	AlgorithmImageCollectionQualityMosaic  = "ImageCollection.qualityMosaic"
+	AlgorithmImageCollectionMerge          = "ImageCollection.merge"
+	AlgorithmImageCollectionGetRegion      = "ImageCollection.getRegion"
+	AlgorithmImageCollectionFromImages     = "ImageCollection.fromImages"
+
+	// Collection algorithms
+	AlgorithmCollectionMap    = "Collection.map"
+	AlgorithmCollectionSort   = "Collection.sort"
+	AlgorithmCollectionSize   = "Collection.size"
+	AlgorithmCollectionFilter = "Collection.filter"
+
+	// Element algorithms
+	AlgorithmElementSet = "Element.set"
+
+	// Aggregation algorithms
+	AlgorithmAggregateArray = "AggregateFeatureCollection.array"
+
+	// Filter algorithms
+	AlgorithmFilterIntersects   = "Filter.intersects"
+	AlgorithmFilterListContains = "Filter.listContains"
 
 	// Image math algorithms
-	AlgorithmImageAdd              = "Image.add"
-	AlgorithmImageSubtract         = "Image.subtract"
-	AlgorithmImageMultiply         = "Image.multiply"
-	AlgorithmImageDivide           = "Image.divide"
-	AlgorithmImageNormalizedDiff   = "Image.normalizedDifference"
-	AlgorithmImageExpression       = "Image.expression"
+	AlgorithmImageAdd            = "Image.add"
+	AlgorithmImageSubtract       = "Image.subtract"
+	AlgorithmImageMultiply       = "Image.multiply"
+	AlgorithmImageDivide         = "Image.divide"
+	AlgorithmImageNormalizedDiff = "Image.normalizedDifference"
+	AlgorithmImageExpression     = "Image.expression"
+
+	// Image comparison and masking algorithms
+	AlgorithmImageConstant   = "Image.constant"
+	AlgorithmImageBitwiseAnd = "Image.bitwiseAnd"
+	AlgorithmImageEq         = "Image.eq"
+	AlgorithmImageNeq        = "Image.neq"
+	AlgorithmImageGt         = "Image.gt"
+	AlgorithmImageGte        = "Image.gte"
+	AlgorithmImageLte        = "Image.lte"
+	AlgorithmImageAbs        = "Image.abs"
+	AlgorithmImageAnd        = "Image.and"
+	AlgorithmImageUpdateMask = "Image.updateMask"
+	AlgorithmImageMask       = "Image.mask"
+	AlgorithmImageUnmask     = "Image.unmask"
+	AlgorithmImageClip       = "Image.clip"
+
+	// Image rendering algorithms
+	AlgorithmImageVisualize            = "Image.visualize"
+	AlgorithmImageClipToBoundsAndScale = "Image.clipToBoundsAndScale"
+
+	// Image projection algorithms
+	AlgorithmImageReproject        = "Image.reproject"
+	AlgorithmImageResample         = "Image.resample"
+	AlgorithmImageReduceResolution = "Image.reduceResolution"
+	AlgorithmImageProjection       = "Image.projection"
+	AlgorithmProjectionScale       = "Projection.scale"
+
+	// Image band algorithms
+	AlgorithmImageAddBands  = "Image.addBands"
+	AlgorithmImageRename    = "Image.rename"
+	AlgorithmImageBandNames = "Image.bandNames"
+	AlgorithmImagePixelArea = "Image.pixelArea"
+	AlgorithmImageBandTypes = "Image.bandTypes"
+
+	// Image neighborhood algorithms
+	AlgorithmImageFocalMax            = "Image.focal_max"
+	AlgorithmImageFocalMin            = "Image.focal_min"
+	AlgorithmImageFocalMedian         = "Image.focal_median"
+	AlgorithmImageConnectedComponents = "Image.connectedComponents"
+	AlgorithmImageNeighborhoodToBands = "Image.neighborhoodToBands"
+
+	// Kernel constructors
+	AlgorithmKernelPlus   = "Kernel.plus"
+	AlgorithmKernelSquare = "Kernel.square"
+
+	// List algorithms
+	AlgorithmListRemoveAll = "List.removeAll"
 
 	// Date constructors
 	AlgorithmDate = "Date"
 
 	// Geometry constructors
-	AlgorithmGeometryPoint = "GeometryConstructors.Point"
+	AlgorithmGeometryPoint        = "GeometryConstructors.Point"
+	AlgorithmGeometryRectangle    = "GeometryConstructors.Rectangle"
+	AlgorithmGeometryPolygon      = "GeometryConstructors.Polygon"
+	AlgorithmGeometryMultiPolygon = "GeometryConstructors.MultiPolygon"
+	AlgorithmGeometryBuffer       = "Geometry.buffer"
+	AlgorithmGeometryIntersection = "Geometry.intersection"
+	AlgorithmGeometryDissolve     = "Geometry.dissolve"
+	AlgorithmGeometryArea         = "Geometry.area"
+	AlgorithmCollectionGeometry   = "Collection.geometry"
+
+	// Feature and collection constructors
+	AlgorithmFeature    = "Feature"
+	AlgorithmCollection = "Collection"
 
 	// Reducer algorithms
-	AlgorithmReducerFirst  = "Reducer.first"
-	AlgorithmReducerMean   = "Reducer.mean"
-	AlgorithmReducerMedian = "Reducer.median"
-	AlgorithmReducerSum    = "Reducer.sum"
-	AlgorithmReducerMin    = "Reducer.min"
-	AlgorithmReducerMax    = "Reducer.max"
-	AlgorithmReducerCount  = "Reducer.count"
+	AlgorithmReducerFirst              = "Reducer.first"
+	AlgorithmReducerMean               = "Reducer.mean"
+	AlgorithmReducerMedian             = "Reducer.median"
+	AlgorithmReducerSum                = "Reducer.sum"
+	AlgorithmReducerMin                = "Reducer.min"
+	AlgorithmReducerMax                = "Reducer.max"
+	AlgorithmReducerCount              = "Reducer.count"
+	AlgorithmReducerStdDev             = "Reducer.stdDev"
+	AlgorithmReducerVariance           = "Reducer.variance"
+	AlgorithmReducerCombine            = "Reducer.combine"
+	AlgorithmReducerPercentile         = "Reducer.percentile"
+	AlgorithmReducerFrequencyHistogram = "Reducer.frequencyHistogram"
+	AlgorithmReducerMode               = "Reducer.mode"
+	AlgorithmReducerFirstNonNull       = "Reducer.firstNonNull"
+	AlgorithmReducerUnweighted         = "Reducer.unweighted"
+	AlgorithmReducerGroup              = "Reducer.group"
 
 	// Terrain algorithms
 	AlgorithmTerrainSlope  = "Terrain.slope"
 	AlgorithmTerrainAspect = "Terrain.aspect"
+
+	// Classifier algorithms
+	AlgorithmClassifierLoad              = "Classifier.load"
+	AlgorithmClassifierSmileCart         = "Classifier.smileCart"
+	AlgorithmClassifierSmileRandomForest = "Classifier.smileRandomForest"
+	AlgorithmClassifierLibSVM            = "Classifier.libsvm"
+	AlgorithmClassifierTrain             = "Classifier.train"
+	AlgorithmClassifierConfusionMatrix   = "Classifier.confusionMatrix"
+	AlgorithmImageClassify               = "Image.classify"
+	AlgorithmImageSampleRegions          = "Image.sampleRegions"
+	AlgorithmImageSample                 = "Image.sample"
+
+	// Clusterer algorithms
+	AlgorithmClustererWekaKMeans = "Clusterer.wekaKMeans"
+	AlgorithmClustererTrain      = "Clusterer.train"
+	AlgorithmImageCluster        = "Image.cluster"
+
+	// Training sample algorithms
+	AlgorithmFeatureCollectionRandomColumn = "FeatureCollection.randomColumn"
+	AlgorithmFeatureCollectionClassify     = "FeatureCollection.classify"
+	AlgorithmCollectionErrorMatrix         = "Collection.errorMatrix"
+	AlgorithmConfusionMatrixAccuracy       = "ConfusionMatrix.accuracy"
+	AlgorithmConfusionMatrixArray          = "ConfusionMatrix.array"
+	AlgorithmFilterLessThan                = "Filter.lessThan"
+	AlgorithmFilterNot                     = "Filter.not"
 )
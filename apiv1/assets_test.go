@@ -2,6 +2,7 @@ package apiv1
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -234,3 +235,48 @@ func TestAssetsMove(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 }
+
+func TestAssetsIamPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":getIamPolicy"):
+			w.Write([]byte(`{"bindings": [{"role": "roles/owner", "members": ["user:owner@example.com"]}], "etag": "BwX1"}`))
+		case strings.HasSuffix(r.URL.Path, ":setIamPolicy"):
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"policy":{`) || !strings.Contains(string(body), `"etag":"BwX1"`) {
+				t.Errorf("unexpected setIamPolicy body %s", body)
+			}
+			w.Write([]byte(`{"bindings": [{"role": "roles/owner", "members": ["user:owner@example.com"]}], "etag": "BwX2"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, _ := NewService(ctx,
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+	)
+
+	policy, err := service.Projects.Assets.GetIamPolicy(ctx, "projects/test/assets/my-image")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(policy.Bindings) != 1 || policy.Bindings[0].Role != "roles/owner" || policy.Etag != "BwX1" {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	updated, err := service.Projects.Assets.SetIamPolicy(ctx, "projects/test/assets/my-image", policy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Etag != "BwX2" {
+		t.Errorf("Expected etag BwX2, got %s", updated.Etag)
+	}
+}
@@ -35,6 +35,13 @@
 //
 // # Error Handling
 //
-// All methods return errors that can be type-asserted to *googleapi.Error
-// for detailed error information including HTTP status codes.
+// Errors returned by the API can be type-asserted to *APIError for detailed
+// error information including status codes.
+// Use IsRetryable to tell transient failures from terminal ones, or
+// WithRetryPolicy to retry reads and compute calls automatically:
+//
+//	service, err := apiv1.NewService(ctx,
+//	    apiv1.WithServiceAccountFile("credentials.json"),
+//	    apiv1.WithRetryPolicy(3, time.Second),
+//	)
 package apiv1
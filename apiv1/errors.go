@@ -0,0 +1,77 @@
+package apiv1
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors for common Earth Engine failures. An *APIError matches one
+// of these with errors.Is:
+//
+//	_, err := service.Projects.Assets.Get(ctx, name)
+//	if errors.Is(err, apiv1.ErrAssetNotFound) {
+//	    // Skip the missing asset
+//	}
+var (
+	// ErrAssetNotFound is a missing asset or resource (404, NOT_FOUND)
+	ErrAssetNotFound = errors.New("earth engine: asset not found")
+
+	// ErrQuotaExceeded is rate limiting or quota exhaustion (429, RESOURCE_EXHAUSTED)
+	ErrQuotaExceeded = errors.New("earth engine: quota exceeded")
+
+	// ErrInvalidExpression is a rejected request or expression (400, INVALID_ARGUMENT)
+	ErrInvalidExpression = errors.New("earth engine: invalid expression")
+
+	// ErrPermissionDenied is missing access to a project or asset (403, PERMISSION_DENIED)
+	ErrPermissionDenied = errors.New("earth engine: permission denied")
+)
+
+// Classify returns the sentinel error for an HTTP status code and Google API
+// status, or nil if there is none. The status takes precedence, since Earth
+// Engine sometimes reports a failure with a generic HTTP code.
+func Classify(statusCode int, status string) error {
+	switch status {
+	case "NOT_FOUND":
+		return ErrAssetNotFound
+	case "RESOURCE_EXHAUSTED":
+		return ErrQuotaExceeded
+	case "INVALID_ARGUMENT":
+		return ErrInvalidExpression
+	case "PERMISSION_DENIED":
+		return ErrPermissionDenied
+	}
+
+	switch statusCode {
+	case http.StatusNotFound:
+		return ErrAssetNotFound
+	case http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case http.StatusBadRequest:
+		return ErrInvalidExpression
+	case http.StatusForbidden:
+		return ErrPermissionDenied
+	default:
+		return nil
+	}
+}
+
+// Kind returns the sentinel error describing e, or nil if e is not one of the
+// common failures. Field violations mark a request as invalid even when the
+// status does not.
+func (e *APIError) Kind() error {
+	if kind := Classify(e.ErrorInfo.Code, e.ErrorInfo.Status); kind != nil {
+		return kind
+	}
+	if len(e.FieldViolations()) > 0 {
+		return ErrInvalidExpression
+	}
+	return nil
+}
+
+// Is reports whether target is the sentinel error for e, so errors.Is works
+// with ErrAssetNotFound, ErrQuotaExceeded, ErrInvalidExpression, and
+// ErrPermissionDenied.
+func (e *APIError) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
+}
@@ -0,0 +1,82 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		code   int
+		status string
+		want   error
+	}{
+		{404, "NOT_FOUND", ErrAssetNotFound},
+		{404, "", ErrAssetNotFound},
+		{429, "", ErrQuotaExceeded},
+		{400, "RESOURCE_EXHAUSTED", ErrQuotaExceeded},
+		{400, "INVALID_ARGUMENT", ErrInvalidExpression},
+		{403, "", ErrPermissionDenied},
+		{500, "INTERNAL", nil},
+		{401, "UNAUTHENTICATED", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(tt.code, tt.status); got != tt.want {
+			t.Errorf("Classify(%d, %q) = %v, want %v", tt.code, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "Asset not found.", "status": "NOT_FOUND"}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, _ := NewService(ctx,
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+	)
+
+	_, err := service.Projects.Assets.Get(ctx, "projects/p/assets/missing")
+	wrapped := fmt.Errorf("describe asset: %w", err)
+
+	if !errors.Is(wrapped, ErrAssetNotFound) {
+		t.Errorf("errors.Is(%v, ErrAssetNotFound) = false, want true", wrapped)
+	}
+	if errors.Is(wrapped, ErrPermissionDenied) {
+		t.Error("errors.Is(err, ErrPermissionDenied) = true, want false")
+	}
+}
+
+func TestAPIErrorFieldViolations(t *testing.T) {
+	apiErr := &APIError{}
+	apiErr.ErrorInfo.Code = 500
+	apiErr.ErrorInfo.Message = "Invalid request"
+	apiErr.ErrorInfo.Details = append(apiErr.ErrorInfo.Details, struct {
+		Type            string           `json:"@type"`
+		FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+	}{
+		Type: "type.googleapis.com/google.rpc.BadRequest",
+		FieldViolations: []FieldViolation{
+			{Field: "expression.values[0]", Description: "Unknown function"},
+			{Field: "fileFormat", Description: "Unsupported"},
+		},
+	})
+
+	if !errors.Is(apiErr, ErrInvalidExpression) {
+		t.Error("errors.Is(err, ErrInvalidExpression) = false, want true for field violations")
+	}
+	if msg := apiErr.Error(); !strings.Contains(msg, "[expression.values[0]: Unknown function; fileFormat: Unsupported]") {
+		t.Errorf("Error() = %q, want field violations", msg)
+	}
+}
@@ -46,7 +46,7 @@ func (r *ProjectsOperationsService) Get(ctx context.Context, name string, opts .
 //
 // Example:
 //
-//	resp, err := service.Projects.Operations.List(ctx, "projects/my-project", nil)
+//	resp, err := service.Projects.Operations.List(ctx, "projects/my-project", 100, "", "")
 //	for _, op := range resp.Operations {
 //	    fmt.Printf("Operation: %s, Done: %v\n", op.Name, op.Done)
 //	}
@@ -73,8 +73,13 @@ func (r *ProjectsOperationsService) List(ctx context.Context, name string, pageS
 	}
 	u.RawQuery = q.Encode()
 
+	urlPath := name + "/operations"
+	if q.Encode() != "" {
+		urlPath += "?" + q.Encode()
+	}
+
 	resp := &ListOperationsResponse{}
-	if err := r.s.makeRequest(ctx, "GET", name+"/operations", nil, resp, opts...); err != nil {
+	if err := r.s.makeRequest(ctx, "GET", urlPath, nil, resp, opts...); err != nil {
 		return nil, err
 	}
 
@@ -44,6 +44,49 @@ func TestOperationsGet(t *testing.T) {
 	}
 }
 
+func TestOperationsList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/test/operations" {
+			t.Errorf("Expected path '/projects/test/operations', got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("pageSize"); got != "50" {
+			t.Errorf("Expected pageSize=50, got %s", got)
+		}
+		if got := r.URL.Query().Get("pageToken"); got != "next" {
+			t.Errorf("Expected pageToken=next, got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"operations": [
+				{"name": "projects/test/operations/a", "done": true},
+				{"name": "projects/test/operations/b"}
+			],
+			"nextPageToken": "after"
+		}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, _ := NewService(ctx,
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+	)
+
+	resp, err := service.Projects.Operations.List(ctx, "projects/test", 50, "next", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(resp.Operations) != 2 {
+		t.Errorf("Expected 2 operations, got %d", len(resp.Operations))
+	}
+	if resp.NextPageToken != "after" {
+		t.Errorf("Expected nextPageToken 'after', got '%s'", resp.NextPageToken)
+	}
+}
+
 func TestOperationsWait(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, ":wait") {
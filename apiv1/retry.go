@@ -0,0 +1,166 @@
+package apiv1
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryBackoff caps the delay between retries.
+const maxRetryBackoff = 60 * time.Second
+
+// retryPolicy configures retries of transient failures.
+type retryPolicy struct {
+	maxRetries  int
+	baseBackoff time.Duration
+}
+
+// WithRetryPolicy retries idempotent calls that fail with a retryable status
+// (see IsRetryableStatus), up to maxRetries times.
+//
+// GET requests and compute calls (value:compute, image:computePixels, and so
+// on) are retried; calls that create or modify resources are not. The delay
+// before retry n is a random duration between half and all of
+// baseBackoff*2^n, capped at one minute, or the server's Retry-After header if
+// that is longer.
+//
+// Example:
+//
+//	service, err := apiv1.NewService(ctx,
+//	    apiv1.WithServiceAccountFile("credentials.json"),
+//	    apiv1.WithRetryPolicy(5, 500*time.Millisecond),
+//	)
+func WithRetryPolicy(maxRetries int, baseBackoff time.Duration) Option {
+	return func(s *Service) error {
+		if maxRetries < 0 {
+			return errors.New("maxRetries cannot be negative")
+		}
+		if baseBackoff <= 0 {
+			return errors.New("baseBackoff must be positive")
+		}
+		s.retry = &retryPolicy{maxRetries: maxRetries, baseBackoff: baseBackoff}
+		return nil
+	}
+}
+
+// IsRetryableStatus reports whether an HTTP status indicates a transient
+// failure worth retrying: 429, 500, 502, or 503.
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether err is an *APIError with a retryable status.
+// Other errors, including invalid arguments, permission errors, and missing
+// assets, are terminal.
+//
+// Example:
+//
+//	_, err := service.Projects.Value.Compute(ctx, parent, req)
+//	if apiv1.IsRetryable(err) {
+//	    // Try again later
+//	}
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return IsRetryableStatus(apiErr.ErrorInfo.Code)
+}
+
+// retryTransport retries idempotent requests that fail with a retryable status.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy retryPolicy
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if !isIdempotent(req) || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	for attempt := 0; attempt < t.policy.maxRetries; attempt++ {
+		if err != nil || !IsRetryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp.Header.Get("Retry-After"))
+
+		// Release the failed response before waiting
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			retry.Body = body
+		}
+		resp, err = t.base.RoundTrip(retry)
+	}
+
+	return resp, err
+}
+
+// backoff returns the delay before retry attempt, honoring a Retry-After
+// header if it asks for longer.
+func (t *retryTransport) backoff(attempt int, retryAfter string) time.Duration {
+	backoff := t.policy.baseBackoff << attempt
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	delay := backoff/2 + time.Duration(rand.Int64N(int64(backoff/2)+1))
+
+	if wait, ok := parseRetryAfter(retryAfter); ok && wait > delay {
+		delay = wait
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether req can be safely retried: reads, and compute
+// calls that have no side effects.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return strings.Contains(req.URL.Path, ":compute")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryTestService returns a service with a retry policy whose server
+// responds with statuses in turn, then 200.
+func newRetryTestService(t *testing.T, maxRetries int, statuses ...int) (*Service, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		if n < len(statuses) {
+			w.WriteHeader(statuses[n])
+			w.Write([]byte(`{"error": {"message": "try again"}}`))
+			return
+		}
+		w.Write([]byte(`{"result": 42}`))
+	}))
+	t.Cleanup(server.Close)
+
+	service, err := NewService(context.Background(),
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+		WithRetryPolicy(maxRetries, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	return service, &calls
+}
+
+func TestRetryPolicyRetriesTransientErrors(t *testing.T) {
+	service, calls := newRetryTestService(t, 3, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+
+	var result map[string]interface{}
+	if err := service.makeRequest(context.Background(), "POST", "projects/p/value:compute", map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+
+	if result["result"] != 42.0 {
+		t.Errorf("result = %v, want 42", result["result"])
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestRetryPolicyGivesUp(t *testing.T) {
+	service, calls := newRetryTestService(t, 2, 500, 502, 503, 503)
+
+	err := service.makeRequest(context.Background(), "GET", "projects/p/assets/a", nil, nil)
+	if !IsRetryable(err) {
+		t.Errorf("err = %v, want a retryable APIError", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorInfo.Code != http.StatusServiceUnavailable {
+		t.Errorf("Code = %d, want 503", apiErr.ErrorInfo.Code)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestRetryPolicySkipsTerminalAndUnsafe(t *testing.T) {
+	t.Run("terminal status", func(t *testing.T) {
+		service, calls := newRetryTestService(t, 3, http.StatusBadRequest)
+		err := service.makeRequest(context.Background(), "GET", "projects/p/assets/a", nil, nil)
+		if err == nil || IsRetryable(err) {
+			t.Errorf("err = %v, want a terminal error", err)
+		}
+		if *calls != 1 {
+			t.Errorf("calls = %d, want 1", *calls)
+		}
+	})
+
+	t.Run("non-idempotent call", func(t *testing.T) {
+		service, calls := newRetryTestService(t, 3, http.StatusServiceUnavailable)
+		err := service.makeRequest(context.Background(), "POST", "projects/p/assets?assetId=a", map[string]string{}, nil)
+		if !IsRetryable(err) {
+			t.Errorf("err = %v, want a retryable error", err)
+		}
+		if *calls != 1 {
+			t.Errorf("calls = %d, want 1", *calls)
+		}
+	})
+}
+
+func TestRetryPolicyHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	service, _ := NewService(context.Background(),
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+		WithRetryPolicy(1, time.Millisecond),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := service.makeRequest(ctx, "GET", "projects/p/assets/a", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded while honoring Retry-After", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v, want prompt cancellation", elapsed)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	transport := &retryTransport{policy: retryPolicy{maxRetries: 5, baseBackoff: 100 * time.Millisecond}}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		max := 100 * time.Millisecond << attempt
+		delay := transport.backoff(attempt, "")
+		if delay < max/2 || delay > max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, delay, max/2, max)
+		}
+	}
+
+	if delay := transport.backoff(0, "2"); delay != 2*time.Second {
+		t.Errorf("backoff with Retry-After 2 = %v, want 2s", delay)
+	}
+	if delay := transport.backoff(40, ""); delay > maxRetryBackoff {
+		t.Errorf("backoff(40) = %v, want at most %v", delay, maxRetryBackoff)
+	}
+}
+
+func TestWithRetryPolicyValidation(t *testing.T) {
+	if err := WithRetryPolicy(-1, time.Second)(&Service{}); err == nil {
+		t.Error("Expected error for negative maxRetries")
+	}
+	if err := WithRetryPolicy(3, 0)(&Service{}); err == nil {
+		t.Error("Expected error for zero backoff")
+	}
+}
@@ -27,8 +27,10 @@ const (
 // Service represents the Earth Engine API service client.
 // It provides access to all API resources organized by category.
 type Service struct {
-	client   *http.Client
-	BasePath string // Base URL for API requests, default is DefaultBasePath
+	client      *http.Client
+	retry       *retryPolicy
+	workloadTag string // Applied to requests that don't set their own
+	BasePath    string // Base URL for API requests, default is DefaultBasePath
 
 	// API Resources
 	Projects *ProjectsService
@@ -55,6 +57,17 @@ func NewService(ctx context.Context, opts ...Option) (*Service, error) {
 		return nil, fmt.Errorf("no HTTP client configured: use WithServiceAccountFile or WithHTTPClient")
 	}
 
+	// Wrap a copy of the client so the caller's client is unchanged
+	if s.retry != nil {
+		base := s.client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		client := *s.client
+		client.Transport = &retryTransport{base: base, policy: *s.retry}
+		s.client = &client
+	}
+
 	// Initialize resource services
 	s.Projects = &ProjectsService{s: s}
 	s.Projects.Value = &ProjectsValueService{s: s}
@@ -120,7 +133,8 @@ type CallOption interface {
 }
 
 type callOptions struct {
-	fields string // Field mask for partial responses
+	fields      string // Field mask for partial responses
+	workloadTag string // Workload tag overriding the service's
 }
 
 // Fields returns a CallOption that specifies which fields to include in the response.
@@ -162,6 +176,11 @@ func (s *Service) makeRequest(ctx context.Context, method, urlPath string, body
 		u.RawQuery = q.Encode()
 	}
 
+	body, err = s.applyWorkloadTag(body, co)
+	if err != nil {
+		return err
+	}
+
 	// Prepare request body
 	var bodyReader io.Reader
 	if body != nil {
@@ -197,11 +216,16 @@ func (s *Service) makeRequest(ctx context.Context, method, urlPath string, body
 
 	// Check for errors
 	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		// Keep the HTTP status if the body isn't a Google API error, so
+		// IsRetryable can classify it
+		apiErr := &APIError{}
+		if err := json.Unmarshal(respBody, apiErr); err != nil || apiErr.ErrorInfo.Code == 0 {
+			apiErr.ErrorInfo.Code = resp.StatusCode
+			if apiErr.ErrorInfo.Message == "" {
+				apiErr.ErrorInfo.Message = string(respBody)
+			}
 		}
-		return &apiErr
+		return apiErr
 	}
 
 	// Decode result
@@ -3,7 +3,10 @@ package apiv1
 // This file contains type definitions generated from the Earth Engine API discovery document.
 // Types are organized by category: expressions, assets, operations, requests/responses.
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ===== Core Expression Types =====
 
@@ -148,6 +151,32 @@ type GridDimensions struct {
 	Height int64 `json:"height,omitempty,string"`
 }
 
+// ===== IAM Types =====
+
+// Policy is an IAM policy granting roles on an asset.
+type Policy struct {
+	// Version is the policy format version.
+	Version int `json:"version,omitempty"`
+
+	// Bindings grant roles to members.
+	Bindings []*Binding `json:"bindings,omitempty"`
+
+	// Etag identifies the policy version read, so a concurrent change makes
+	// SetIamPolicy fail instead of being overwritten.
+	Etag string `json:"etag,omitempty"`
+}
+
+// Binding grants a role to a list of members.
+type Binding struct {
+	// Role is the granted role, e.g. "roles/owner", "roles/editor", or "roles/viewer".
+	Role string `json:"role,omitempty"`
+
+	// Members are principals such as "user:alice@example.com",
+	// "group:team@example.com", "serviceAccount:...", "domain:example.com",
+	// or "allUsers".
+	Members []string `json:"members,omitempty"`
+}
+
 // ===== Operation Types (Long-Running Operations) =====
 
 // Operation represents a long-running operation.
@@ -202,8 +231,27 @@ type FieldViolation struct {
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("Earth Engine API error %d (%s): %s",
+	msg := fmt.Sprintf("Earth Engine API error %d (%s): %s",
 		e.ErrorInfo.Code, e.ErrorInfo.Status, e.ErrorInfo.Message)
+
+	violations := e.FieldViolations()
+	if len(violations) == 0 {
+		return msg
+	}
+	details := make([]string, len(violations))
+	for i, v := range violations {
+		details[i] = v.Field + ": " + v.Description
+	}
+	return msg + " [" + strings.Join(details, "; ") + "]"
+}
+
+// FieldViolations returns the field violations from all error details.
+func (e *APIError) FieldViolations() []FieldViolation {
+	var violations []FieldViolation
+	for _, detail := range e.ErrorInfo.Details {
+		violations = append(violations, detail.FieldViolations...)
+	}
+	return violations
 }
 
 // ===== Request/Response Types =====
@@ -301,6 +349,41 @@ type ExportImageRequest struct {
 	WorkloadTag string `json:"workloadTag,omitempty"`
 }
 
+// ExportVideoRequest is the request for exporting an image collection as a
+// video.
+type ExportVideoRequest struct {
+	// Expression is the image collection expression to export. Each image
+	// is one frame and must have three 8-bit bands.
+	Expression *Expression `json:"expression"`
+
+	// Description is a human-readable description of the export.
+	Description string `json:"description,omitempty"`
+
+	// VideoOptions specifies the frame rate and size limits.
+	VideoOptions *VideoOptions `json:"videoOptions,omitempty"`
+
+	// FileExportOptions specifies export destination and format.
+	FileExportOptions *FileExportOptions `json:"fileExportOptions,omitempty"`
+
+	// RequestId is an optional client-provided request ID for idempotency.
+	RequestId string `json:"requestId,omitempty"`
+
+	// WorkloadTag is an optional tag for quota accounting.
+	WorkloadTag string `json:"workloadTag,omitempty"`
+}
+
+// VideoOptions specifies how a video is encoded.
+type VideoOptions struct {
+	// FramesPerSecond is the frame rate of the video.
+	FramesPerSecond float64 `json:"framesPerSecond,omitempty"`
+
+	// MaxFrames is the maximum number of frames to export.
+	MaxFrames int64 `json:"maxFrames,omitempty"`
+
+	// MaxPixelsPerFrame is the maximum number of pixels in each frame.
+	MaxPixelsPerFrame int64 `json:"maxPixelsPerFrame,omitempty"`
+}
+
 // FileExportOptions specifies export destination and format.
 type FileExportOptions struct {
 	// FileFormat is the output file format (e.g., "GEO_TIFF", "NUMPY_NDARRAY").
@@ -0,0 +1,114 @@
+package apiv1
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// workloadTagPattern is Earth Engine's workload tag format: 1 to 63 lowercase
+// letters, digits, dashes, and underscores, starting and ending with a letter
+// or digit.
+var workloadTagPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+// ValidateWorkloadTag checks that tag is a valid Earth Engine workload tag.
+func ValidateWorkloadTag(tag string) error {
+	if !workloadTagPattern.MatchString(tag) {
+		return fmt.Errorf("invalid workload tag %q: must be 1-63 lowercase letters, digits, '-' or '_', starting and ending with a letter or digit", tag)
+	}
+	return nil
+}
+
+// WithWorkloadTag sets a workload tag for quota and billing attribution on
+// every compute and export request that doesn't set its own WorkloadTag.
+//
+// Example:
+//
+//	service, err := apiv1.NewService(ctx,
+//	    apiv1.WithServiceAccountFile("credentials.json"),
+//	    apiv1.WithWorkloadTag("nightly-ndvi"),
+//	)
+func WithWorkloadTag(tag string) Option {
+	return func(s *Service) error {
+		if err := ValidateWorkloadTag(tag); err != nil {
+			return err
+		}
+		s.workloadTag = tag
+		return nil
+	}
+}
+
+// WorkloadTag returns a CallOption that overrides the service's workload tag
+// for one call. A WorkloadTag set on the request itself takes precedence.
+//
+// Example:
+//
+//	resp, err := service.Projects.Value.Compute(ctx, parent, req, apiv1.WorkloadTag("backfill"))
+func WorkloadTag(tag string) CallOption {
+	return workloadTagOption{tag}
+}
+
+type workloadTagOption struct{ tag string }
+
+func (o workloadTagOption) Apply(opts *callOptions) {
+	opts.workloadTag = o.tag
+}
+
+// workloadTagged is implemented by requests with a WorkloadTag field.
+type workloadTagged interface {
+	// withDefaultWorkloadTag returns the request itself if it has a tag, or
+	// else a shallow copy with tag set, leaving the caller's request as is.
+	withDefaultWorkloadTag(tag string) interface{}
+}
+
+func (r *ComputeValueRequest) withDefaultWorkloadTag(tag string) interface{} {
+	if r.WorkloadTag != "" {
+		return r
+	}
+	tagged := *r
+	tagged.WorkloadTag = tag
+	return &tagged
+}
+
+func (r *ComputePixelsRequest) withDefaultWorkloadTag(tag string) interface{} {
+	if r.WorkloadTag != "" {
+		return r
+	}
+	tagged := *r
+	tagged.WorkloadTag = tag
+	return &tagged
+}
+
+func (r *ComputeFeaturesRequest) withDefaultWorkloadTag(tag string) interface{} {
+	if r.WorkloadTag != "" {
+		return r
+	}
+	tagged := *r
+	tagged.WorkloadTag = tag
+	return &tagged
+}
+
+func (r *ExportImageRequest) withDefaultWorkloadTag(tag string) interface{} {
+	if r.WorkloadTag != "" {
+		return r
+	}
+	tagged := *r
+	tagged.WorkloadTag = tag
+	return &tagged
+}
+
+// applyWorkloadTag returns the body to send: body itself, or a copy of it
+// with the call's or service's workload tag if it has no tag of its own. The
+// caller's request is never modified, so it can be reused with another tag.
+func (s *Service) applyWorkloadTag(body interface{}, co *callOptions) (interface{}, error) {
+	tag := s.workloadTag
+	if co.workloadTag != "" {
+		if err := ValidateWorkloadTag(co.workloadTag); err != nil {
+			return nil, err
+		}
+		tag = co.workloadTag
+	}
+	if tagged, ok := body.(workloadTagged); ok && tag != "" {
+		return tagged.withDefaultWorkloadTag(tag), nil
+	}
+	return body, nil
+}
@@ -0,0 +1,126 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateWorkloadTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		valid bool
+	}{
+		{"a", true},
+		{"nightly-ndvi", true},
+		{"backfill_2023", true},
+		{strings.Repeat("a", 63), true},
+		{"", false},
+		{strings.Repeat("a", 64), false},
+		{"Nightly", false},
+		{"-nightly", false},
+		{"nightly_", false},
+		{"night ly", false},
+		{"night.ly", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateWorkloadTag(tt.tag)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateWorkloadTag(%q) = %v, want valid %v", tt.tag, err, tt.valid)
+		}
+	}
+}
+
+func TestWithWorkloadTagInvalid(t *testing.T) {
+	_, err := NewService(context.Background(), WithHTTPClient(http.DefaultClient), WithWorkloadTag("Bad Tag"))
+	if err == nil || !strings.Contains(err.Error(), "invalid workload tag") {
+		t.Errorf("NewService error = %v, want invalid workload tag", err)
+	}
+}
+
+func TestWorkloadTagApplied(t *testing.T) {
+	var tags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ComputeValueRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		tags = append(tags, body.WorkloadTag)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": 1}`))
+	}))
+	defer server.Close()
+
+	service, err := NewService(context.Background(),
+		WithHTTPClient(server.Client()),
+		withBasePath(server.URL+"/"),
+		WithWorkloadTag("service-tag"),
+	)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	ctx := context.Background()
+	calls := []struct {
+		req  *ComputeValueRequest
+		opts []CallOption
+	}{
+		{&ComputeValueRequest{}, nil},
+		{&ComputeValueRequest{}, []CallOption{WorkloadTag("call-tag")}},
+		{&ComputeValueRequest{WorkloadTag: "request-tag"}, []CallOption{WorkloadTag("call-tag")}},
+	}
+	for _, c := range calls {
+		if _, err := service.Projects.Value.Compute(ctx, "projects/p", c.req, c.opts...); err != nil {
+			t.Fatalf("Compute failed: %v", err)
+		}
+	}
+
+	want := []string{"service-tag", "call-tag", "request-tag"}
+	if strings.Join(tags, ",") != strings.Join(want, ",") {
+		t.Errorf("workload tags = %v, want %v", tags, want)
+	}
+
+	_, err = service.Projects.Value.Compute(ctx, "projects/p", &ComputeValueRequest{}, WorkloadTag("BAD"))
+	if err == nil || !strings.Contains(err.Error(), "invalid workload tag") {
+		t.Errorf("Compute error = %v, want invalid workload tag", err)
+	}
+}
+
+func TestWorkloadTagReusedRequest(t *testing.T) {
+	var tags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ComputeValueRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		tags = append(tags, body.WorkloadTag)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": 1}`))
+	}))
+	defer server.Close()
+
+	service, err := NewService(context.Background(), WithHTTPClient(server.Client()), withBasePath(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	// The same request sent with different call tags keeps no tag of its own
+	ctx := context.Background()
+	req := &ComputeValueRequest{}
+	for _, tag := range []string{"first", "second"} {
+		if _, err := service.Projects.Value.Compute(ctx, "projects/p", req, WorkloadTag(tag)); err != nil {
+			t.Fatalf("Compute failed: %v", err)
+		}
+	}
+
+	if strings.Join(tags, ",") != "first,second" {
+		t.Errorf("workload tags = %v, want [first second]", tags)
+	}
+	if req.WorkloadTag != "" {
+		t.Errorf("request WorkloadTag = %q, want it left unset", req.WorkloadTag)
+	}
+}
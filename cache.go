@@ -1,6 +1,7 @@
 package earthengine
 
 import (
+	"container/list"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
@@ -24,14 +25,19 @@ type Cache interface {
 	Clear(ctx context.Context) error
 }
 
-// MemoryCache is an in-memory implementation of Cache.
+// MemoryCache is an in-memory implementation of Cache. When full, it evicts
+// the least recently used entry. It is safe for concurrent use.
 type MemoryCache struct {
-	mu      sync.RWMutex
-	data    map[string]*cacheEntry
+	mu      sync.Mutex
+	data    map[string]*list.Element
+	order   *list.List // Most recently used at the front
 	maxSize int
+	hits    int64
+	misses  int64
 }
 
 type cacheEntry struct {
+	key        string
 	value      interface{}
 	expiration time.Time
 }
@@ -41,7 +47,8 @@ type cacheEntry struct {
 // maxSize limits the number of entries (0 = unlimited).
 func NewMemoryCache(maxSize int) *MemoryCache {
 	cache := &MemoryCache{
-		data:    make(map[string]*cacheEntry),
+		data:    make(map[string]*list.Element),
+		order:   list.New(),
 		maxSize: maxSize,
 	}
 
@@ -53,19 +60,25 @@ func NewMemoryCache(maxSize int) *MemoryCache {
 
 // Get retrieves a cached value.
 func (mc *MemoryCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
-	entry, exists := mc.data[key]
+	elem, exists := mc.data[key]
 	if !exists {
+		mc.misses++
 		return nil, false, nil
 	}
 
 	// Check expiration
+	entry := elem.Value.(*cacheEntry)
 	if !entry.expiration.IsZero() && time.Now().After(entry.expiration) {
+		mc.removeElement(elem)
+		mc.misses++
 		return nil, false, nil
 	}
 
+	mc.order.MoveToFront(elem)
+	mc.hits++
 	return entry.value, true, nil
 }
 
@@ -74,24 +87,29 @@ func (mc *MemoryCache) Set(ctx context.Context, key string, value interface{}, t
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
-	// Check size limit
-	if mc.maxSize > 0 && len(mc.data) >= mc.maxSize {
-		// Evict oldest entry (simple FIFO)
-		for k := range mc.data {
-			delete(mc.data, k)
-			break
-		}
-	}
-
 	var expiration time.Time
 	if ttl > 0 {
 		expiration = time.Now().Add(ttl)
 	}
 
-	mc.data[key] = &cacheEntry{
+	if elem, exists := mc.data[key]; exists {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiration = expiration
+		mc.order.MoveToFront(elem)
+		return nil
+	}
+
+	// Evict the least recently used entries to make room
+	for mc.maxSize > 0 && mc.order.Len() >= mc.maxSize {
+		mc.removeElement(mc.order.Back())
+	}
+
+	mc.data[key] = mc.order.PushFront(&cacheEntry{
+		key:        key,
 		value:      value,
 		expiration: expiration,
-	}
+	})
 
 	return nil
 }
@@ -101,27 +119,36 @@ func (mc *MemoryCache) Delete(ctx context.Context, key string) error {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
-	delete(mc.data, key)
+	if elem, exists := mc.data[key]; exists {
+		mc.removeElement(elem)
+	}
 	return nil
 }
 
-// Clear removes all values from the cache.
+// Clear removes all values from the cache. Hit and miss counts are kept.
 func (mc *MemoryCache) Clear(ctx context.Context) error {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
-	mc.data = make(map[string]*cacheEntry)
+	mc.data = make(map[string]*list.Element)
+	mc.order.Init()
 	return nil
 }
 
 // Size returns the number of entries in the cache.
 func (mc *MemoryCache) Size() int {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
 	return len(mc.data)
 }
 
+// removeElement removes an entry. The caller must hold mc.mu.
+func (mc *MemoryCache) removeElement(elem *list.Element) {
+	mc.order.Remove(elem)
+	delete(mc.data, elem.Value.(*cacheEntry).key)
+}
+
 // cleanupExpired removes expired entries periodically.
 func (mc *MemoryCache) cleanupExpired() {
 	ticker := time.NewTicker(1 * time.Minute)
@@ -130,9 +157,10 @@ func (mc *MemoryCache) cleanupExpired() {
 	for range ticker.C {
 		mc.mu.Lock()
 		now := time.Now()
-		for key, entry := range mc.data {
+		for _, elem := range mc.data {
+			entry := elem.Value.(*cacheEntry)
 			if !entry.expiration.IsZero() && now.After(entry.expiration) {
-				delete(mc.data, key)
+				mc.removeElement(elem)
 			}
 		}
 		mc.mu.Unlock()
@@ -233,12 +261,19 @@ type CacheStats struct {
 }
 
 // Stats returns cache statistics (if supported by the cache implementation).
+// Hits and misses count Get calls since the cache was created.
 func (mc *MemoryCache) Stats() CacheStats {
-	mc.mu.RLock()
-	defer mc.mu.RUnlock()
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
 
-	return CacheStats{
+	stats := CacheStats{
 		Size:    len(mc.data),
 		MaxSize: mc.maxSize,
+		Hits:    mc.hits,
+		Misses:  mc.misses,
+	}
+	if total := mc.hits + mc.misses; total > 0 {
+		stats.HitRate = float64(mc.hits) / float64(total)
 	}
+	return stats
 }
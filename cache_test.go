@@ -0,0 +1,64 @@
+package earthengine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheLRUEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	cache.Set(ctx, "a", 1, 0)
+	cache.Set(ctx, "b", 2, 0)
+	// Reading "a" makes "b" the least recently used
+	if v, ok, _ := cache.Get(ctx, "a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	cache.Set(ctx, "c", 3, 0)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := cache.Get(ctx, key); !ok {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+
+	// Updating an existing key does not evict
+	cache.Set(ctx, "a", 10, 0)
+	if cache.Size() != 2 {
+		t.Errorf("Size = %d, want 2", cache.Size())
+	}
+	if v, _, _ := cache.Get(ctx, "a"); v != 10 {
+		t.Errorf("Get(a) = %v, want 10", v)
+	}
+}
+
+func TestMemoryCacheStats(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(0)
+
+	cache.Set(ctx, "fresh", 1, time.Hour)
+	cache.Set(ctx, "stale", 2, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	cache.Get(ctx, "fresh")
+	cache.Get(ctx, "stale")
+	cache.Get(ctx, "missing")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Size != 1 {
+		t.Errorf("stats = %+v, want 1 hit, 2 misses, 1 entry", stats)
+	}
+	if stats.HitRate < 0.33 || stats.HitRate > 0.34 {
+		t.Errorf("HitRate = %v, want 1/3", stats.HitRate)
+	}
+
+	cache.Clear(ctx)
+	if stats := cache.Stats(); stats.Size != 0 || stats.Hits != 1 {
+		t.Errorf("stats after Clear = %+v", stats)
+	}
+}
@@ -0,0 +1,388 @@
+package earthengine
+
+import (
+	"context"
+	"fmt"
+)
+
+// Classifier represents a trained classifier in Earth Engine.
+type Classifier interface {
+	// NodeID returns the node ID for this classifier in the expression graph.
+	NodeID(expr *ExpressionBuilder) string
+}
+
+// AssetClassifier is a trained classifier saved as an Earth Engine asset.
+type AssetClassifier struct {
+	AssetID string
+}
+
+// NodeID implements the Classifier interface for AssetClassifier.
+func (c AssetClassifier) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmClassifierLoad, map[string]interface{}{
+		"id": map[string]interface{}{
+			"constantValue": c.AssetID,
+		},
+	})
+}
+
+// ClassifierLoad returns the trained classifier saved at assetID, for example
+// with ee.Classifier.save in the Python API.
+//
+// Example:
+//
+//	rf := earthengine.ClassifierLoad("projects/my-project/assets/landcover-rf")
+func ClassifierLoad(assetID string) Classifier {
+	return AssetClassifier{AssetID: assetID}
+}
+
+// Classify classifies each pixel of the image with a trained classifier,
+// returning a single band named outputName.
+//
+// The image must contain the bands the classifier was trained on. For
+// classifiers in a probability output mode, the band holds probabilities
+// instead of class values.
+//
+// Example:
+//
+//	classified := image.Select("B2", "B3", "B4", "B8").
+//	    Classify(earthengine.ClassifierLoad("projects/my-project/assets/landcover-rf"), "classification")
+func (img *Image) Classify(classifier Classifier, outputName string) *Image {
+	classifyNodeID := img.expr.FunctionCall(AlgorithmImageClassify, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"classifier": map[string]interface{}{
+			"valueReference": classifier.NodeID(img.expr),
+		},
+		"outputName": map[string]interface{}{
+			"constantValue": outputName,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: classifyNodeID,
+	}
+}
+
+// ClassifierAlgorithm is an untrained classifier and its parameters. Train it
+// with TrainingSamples.Train.
+type ClassifierAlgorithm struct {
+	algorithmName string
+	args          map[string]interface{}
+}
+
+// NodeID implements the Classifier interface for ClassifierAlgorithm.
+func (c ClassifierAlgorithm) NodeID(expr *ExpressionBuilder) string {
+	args := make(map[string]interface{}, len(c.args))
+	for name, value := range c.args {
+		args[name] = map[string]interface{}{
+			"constantValue": value,
+		}
+	}
+
+	return expr.FunctionCall(c.algorithmName, args)
+}
+
+// ClassifierSmileCart returns an untrained CART (decision tree) classifier.
+func ClassifierSmileCart() Classifier {
+	return ClassifierAlgorithm{algorithmName: AlgorithmClassifierSmileCart}
+}
+
+// ClassifierSmileRandomForest returns an untrained random forest classifier
+// with the given number of trees.
+//
+// Example:
+//
+//	rf := earthengine.ClassifierSmileRandomForest(100)
+func ClassifierSmileRandomForest(numberOfTrees int) Classifier {
+	return ClassifierAlgorithm{
+		algorithmName: AlgorithmClassifierSmileRandomForest,
+		args: map[string]interface{}{
+			"numberOfTrees": numberOfTrees,
+		},
+	}
+}
+
+// ClassifierLibSVM returns an untrained support vector machine classifier
+// with Earth Engine's default parameters.
+func ClassifierLibSVM() Classifier {
+	return ClassifierAlgorithm{algorithmName: AlgorithmClassifierLibSVM}
+}
+
+// TrainingSamples is a table of image band values sampled at labeled
+// features. The table stays on the server and is only used to train and
+// assess classifiers.
+type TrainingSamples struct {
+	client *Client
+	expr   *ExpressionBuilder
+	nodeID string
+}
+
+// SampleRegions samples the image's band values at each feature of the
+// collection, keeping the listed feature properties (for example the class
+// label) alongside the band values.
+//
+// Example:
+//
+//	samples := image.Select("B2", "B3", "B4", "B8").
+//	    SampleRegions(points, []string{"landcover"}, 10)
+func (img *Image) SampleRegions(collection *FeatureCollection, properties []string, scale float64) *TrainingSamples {
+	sampleNodeID := img.expr.FunctionCall(AlgorithmImageSampleRegions, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"collection": map[string]interface{}{
+			"valueReference": collection.NodeID(img.expr),
+		},
+		"properties": map[string]interface{}{
+			"constantValue": properties,
+		},
+		"scale": map[string]interface{}{
+			"constantValue": scale,
+		},
+	})
+
+	return &TrainingSamples{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: sampleNodeID,
+	}
+}
+
+// RandomSplit randomly splits the samples into two tables, the first holding
+// roughly fraction of the samples and the second the rest. The same seed
+// always gives the same split.
+//
+// Example:
+//
+//	training, validation := samples.RandomSplit(0.7, 42)
+func (s *TrainingSamples) RandomSplit(fraction float64, seed int64) (*TrainingSamples, *TrainingSamples) {
+	const column = "random"
+
+	randomNodeID := s.expr.FunctionCall(AlgorithmFeatureCollectionRandomColumn, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": s.nodeID,
+		},
+		"columnName": map[string]interface{}{
+			"constantValue": column,
+		},
+		"seed": map[string]interface{}{
+			"constantValue": seed,
+		},
+	})
+
+	lessThanNodeID := s.expr.FunctionCall(AlgorithmFilterLessThan, map[string]interface{}{
+		"leftField": map[string]interface{}{
+			"constantValue": column,
+		},
+		"rightValue": map[string]interface{}{
+			"constantValue": fraction,
+		},
+	})
+	notLessThanNodeID := s.expr.FunctionCall(AlgorithmFilterNot, map[string]interface{}{
+		"filter": map[string]interface{}{
+			"valueReference": lessThanNodeID,
+		},
+	})
+
+	return s.filter(randomNodeID, lessThanNodeID), s.filter(randomNodeID, notLessThanNodeID)
+}
+
+// filter returns the samples of collectionNodeID that pass filterNodeID.
+func (s *TrainingSamples) filter(collectionNodeID, filterNodeID string) *TrainingSamples {
+	nodeID := s.expr.FunctionCall(AlgorithmCollectionFilter, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": collectionNodeID,
+		},
+		"filter": map[string]interface{}{
+			"valueReference": filterNodeID,
+		},
+	})
+
+	return &TrainingSamples{
+		client: s.client,
+		expr:   s.expr,
+		nodeID: nodeID,
+	}
+}
+
+// Train trains classifier on the samples, predicting classProperty from
+// inputProperties (usually the sampled band names).
+//
+// Example:
+//
+//	trained := samples.Train(earthengine.ClassifierSmileCart(), "landcover",
+//	    []string{"B2", "B3", "B4", "B8"})
+//	classified := image.Classify(trained, "classification")
+func (s *TrainingSamples) Train(classifier Classifier, classProperty string, inputProperties []string) *TrainedClassifier {
+	trainNodeID := s.expr.FunctionCall(AlgorithmClassifierTrain, map[string]interface{}{
+		"classifier": map[string]interface{}{
+			"valueReference": classifier.NodeID(s.expr),
+		},
+		"features": map[string]interface{}{
+			"valueReference": s.nodeID,
+		},
+		"classProperty": map[string]interface{}{
+			"constantValue": classProperty,
+		},
+		"inputProperties": map[string]interface{}{
+			"constantValue": inputProperties,
+		},
+	})
+
+	return &TrainedClassifier{
+		client: s.client,
+		expr:   s.expr,
+		nodeID: trainNodeID,
+	}
+}
+
+// Accuracy classifies the samples with classifier and returns the fraction
+// whose predicted class matches classProperty.
+//
+// Example:
+//
+//	training, validation := samples.RandomSplit(0.7, 42)
+//	trained := training.Train(earthengine.ClassifierSmileRandomForest(100), "landcover", bands)
+//	accuracy, err := validation.Accuracy(ctx, trained, "landcover")
+func (s *TrainingSamples) Accuracy(ctx context.Context, classifier Classifier, classProperty string) (float64, error) {
+	const predicted = "classification"
+
+	classifiedNodeID := s.expr.FunctionCall(AlgorithmFeatureCollectionClassify, map[string]interface{}{
+		"features": map[string]interface{}{
+			"valueReference": s.nodeID,
+		},
+		"classifier": map[string]interface{}{
+			"valueReference": classifier.NodeID(s.expr),
+		},
+		"outputName": map[string]interface{}{
+			"constantValue": predicted,
+		},
+	})
+
+	matrixNodeID := s.expr.FunctionCall(AlgorithmCollectionErrorMatrix, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": classifiedNodeID,
+		},
+		"actual": map[string]interface{}{
+			"constantValue": classProperty,
+		},
+		"predicted": map[string]interface{}{
+			"constantValue": predicted,
+		},
+	})
+
+	return computeAccuracy(ctx, s.client, s.expr, matrixNodeID)
+}
+
+// TrainedClassifier is a classifier trained on TrainingSamples.
+type TrainedClassifier struct {
+	client *Client
+	expr   *ExpressionBuilder
+	nodeID string
+}
+
+// NodeID implements the Classifier interface for TrainedClassifier. The
+// classifier can be used with images built from any source.
+func (c *TrainedClassifier) NodeID(expr *ExpressionBuilder) string {
+	return expr.Import(c.expr, c.nodeID)
+}
+
+// TrainingAccuracy returns the resubstitution accuracy of the classifier: the
+// fraction of its own training samples it classifies correctly. This is an
+// optimistic estimate; use TrainingSamples.Accuracy on held-out samples for
+// an unbiased one.
+func (c *TrainedClassifier) TrainingAccuracy(ctx context.Context) (float64, error) {
+	matrixNodeID := c.expr.FunctionCall(AlgorithmClassifierConfusionMatrix, map[string]interface{}{
+		"classifier": map[string]interface{}{
+			"valueReference": c.nodeID,
+		},
+	})
+
+	return computeAccuracy(ctx, c.client, c.expr, matrixNodeID)
+}
+
+// computeAccuracy computes the overall accuracy of the confusion matrix at
+// matrixNodeID.
+func computeAccuracy(ctx context.Context, client *Client, expr *ExpressionBuilder, matrixNodeID string) (float64, error) {
+	accuracyNodeID := expr.FunctionCall(AlgorithmConfusionMatrixAccuracy, map[string]interface{}{
+		"confusionMatrix": map[string]interface{}{
+			"valueReference": matrixNodeID,
+		},
+	})
+
+	result, err := client.ComputeValue(ctx, expr.Build(accuracyNodeID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute accuracy: %w", err)
+	}
+
+	accuracy, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected accuracy type: %T", result)
+	}
+
+	return accuracy, nil
+}
+
+// ErrorMatrix computes the confusion matrix of a collection whose features
+// carry actual and predicted integer class labels in the given properties.
+//
+// Rows are actual classes and columns predicted classes, both in the order
+// of the order labels. Features whose labels are not in order are ignored.
+//
+// Example:
+//
+//	matrix, err := client.ErrorMatrix(ctx, validation, "landcover", "classification", []int{0, 1, 2})
+func (c *Client) ErrorMatrix(ctx context.Context, collection *FeatureCollection, actual, predicted string, order []int) ([][]float64, error) {
+	expr := NewExpressionBuilder()
+
+	matrixNodeID := expr.FunctionCall(AlgorithmCollectionErrorMatrix, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": collection.NodeID(expr),
+		},
+		"actual": map[string]interface{}{
+			"constantValue": actual,
+		},
+		"predicted": map[string]interface{}{
+			"constantValue": predicted,
+		},
+		"order": map[string]interface{}{
+			"constantValue": order,
+		},
+	})
+	arrayNodeID := expr.FunctionCall(AlgorithmConfusionMatrixArray, map[string]interface{}{
+		"confusionMatrix": map[string]interface{}{
+			"valueReference": matrixNodeID,
+		},
+	})
+
+	result, err := c.ComputeValue(ctx, expr.Build(arrayNodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute error matrix: %w", err)
+	}
+
+	rows, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected error matrix type: %T", result)
+	}
+
+	matrix := make([][]float64, len(rows))
+	for i, row := range rows {
+		values, ok := row.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected error matrix row type: %T", row)
+		}
+		matrix[i] = make([]float64, len(values))
+		for j, value := range values {
+			count, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error matrix value type: %T", value)
+			}
+			matrix[i][j] = count
+		}
+	}
+
+	return matrix, nil
+}
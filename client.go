@@ -8,7 +8,10 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
+	"time"
 
+	"github.com/alexscott64/go-earthengine/apiv1"
 	"golang.org/x/oauth2/google"
 )
 
@@ -19,9 +22,11 @@ const (
 
 // Client is the main client for interacting with Google Earth Engine REST API.
 type Client struct {
-	httpClient *http.Client
-	projectID  string
-	baseURL    string
+	httpClient  *http.Client
+	projectID   string
+	baseURL     string
+	workloadTag string // Default workload tag for quota attribution
+	logger      Logger // Receives request events; nil means no logging
 }
 
 // ClientOption is a function that configures a Client.
@@ -132,57 +137,356 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithBaseURL overrides the Earth Engine API base URL (useful for testing).
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		if baseURL == "" {
+			return fmt.Errorf("base URL cannot be empty")
+		}
+		c.baseURL = baseURL
+		return nil
+	}
+}
+
+// WithWorkloadTag tags every compute request with a workload tag, for quota
+// and billing attribution in the Cloud console. Use ContextWithWorkloadTag
+// to override it for individual calls.
+//
+// Tags are 1-63 lowercase letters, digits, '-' and '_', starting and ending
+// with a letter or digit.
+//
+// Example:
+//
+//	client, err := earthengine.NewClient(ctx,
+//	    earthengine.WithProject("my-project"),
+//	    earthengine.WithServiceAccountEnv(),
+//	    earthengine.WithWorkloadTag("dashboard"),
+//	)
+func WithWorkloadTag(tag string) ClientOption {
+	return func(c *Client) error {
+		if err := apiv1.ValidateWorkloadTag(tag); err != nil {
+			return err
+		}
+		c.workloadTag = tag
+		return nil
+	}
+}
+
+// workloadTagKey is the context key for a per-call workload tag.
+type workloadTagKey struct{}
+
+// ContextWithWorkloadTag returns a context whose requests carry tag instead of
+// the client's workload tag. An invalid tag makes those requests fail.
+//
+// Example:
+//
+//	ctx = earthengine.ContextWithWorkloadTag(ctx, "backfill-2023")
+//	value, err := image.ReduceRegion(point, reducer).ComputeFloat(ctx)
+func ContextWithWorkloadTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, workloadTagKey{}, tag)
+}
+
+// workloadTagFor returns the workload tag for a request made with ctx.
+func (c *Client) workloadTagFor(ctx context.Context) (string, error) {
+	tag, ok := ctx.Value(workloadTagKey{}).(string)
+	if !ok {
+		return c.workloadTag, nil
+	}
+	if err := apiv1.ValidateWorkloadTag(tag); err != nil {
+		return "", err
+	}
+	return tag, nil
+}
+
+// withWorkloadTag returns params with the workload tag for ctx added, if any.
+func (c *Client) withWorkloadTag(ctx context.Context, params map[string]interface{}) (map[string]interface{}, error) {
+	tag, err := c.workloadTagFor(ctx)
+	if err != nil || tag == "" {
+		return params, err
+	}
+
+	// Copy so the caller's params are unchanged
+	tagged := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		tagged[key] = value
+	}
+	if _, ok := tagged["workloadTag"]; !ok {
+		tagged["workloadTag"] = tag
+	}
+	return tagged, nil
+}
+
 // ComputeValue executes an Earth Engine expression and returns the computed value.
 func (c *Client) ComputeValue(ctx context.Context, expr *Expression) (interface{}, error) {
 	url := fmt.Sprintf("%s/projects/%s/value:compute", c.baseURL, c.projectID)
 
+	params, err := c.withWorkloadTag(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Marshal the expression to JSON
+	exprJSON, err := marshalWithParams(expr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.post(ctx, url, exprJSON, expressionAttrs(expr)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Extract the result value
+	if val, ok := result["result"]; ok {
+		return val, nil
+	}
+
+	return nil, fmt.Errorf("no result in response")
+}
+
+// ComputePixels executes an image expression and returns the encoded pixels,
+// such as PNG or GeoTIFF file bytes.
+//
+// params are sent alongside the expression in the computePixels request, for
+// example "fileFormat", "grid", "bandIds", and "visualizationOptions".
+//
+// Example:
+//
+//	png, err := client.ComputePixels(ctx, expr, map[string]interface{}{
+//	    "fileFormat": "PNG",
+//	    "grid":       grid,
+//	})
+func (c *Client) ComputePixels(ctx context.Context, expr *Expression, params map[string]interface{}) ([]byte, error) {
+	url := fmt.Sprintf("%s/projects/%s/image:computePixels", c.baseURL, c.projectID)
+
+	params, err := c.withWorkloadTag(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	requestJSON, err := marshalWithParams(expr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.post(ctx, url, requestJSON, expressionAttrs(expr)...)
+}
+
+// MapID identifies map tiles registered with CreateMap.
+type MapID struct {
+	Name    string // Resource name, projects/*/maps/*
+	TileURL string // XYZ tile URL template containing {z}, {x}, and {y}
+}
+
+// CreateMap registers an image expression for tiled map display, like
+// getMapId in the Python client. Map IDs expire after a few hours.
+//
+// params are sent alongside the expression in the maps request, for example
+// "fileFormat", "bandIds", and "visualizationOptions".
+//
+// Example:
+//
+//	mapID, err := client.CreateMap(ctx, expr, map[string]interface{}{
+//	    "fileFormat": "PNG",
+//	})
+//	fmt.Println(mapID.TileURL)
+func (c *Client) CreateMap(ctx context.Context, expr *Expression, params map[string]interface{}) (*MapID, error) {
+	url := fmt.Sprintf("%s/projects/%s/maps", c.baseURL, c.projectID)
+
+	// Maps take the workload tag as a query parameter
+	tag, err := c.workloadTagFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tag != "" {
+		url += "?workloadTag=" + tag
+	}
+
+	requestJSON, err := marshalWithParams(expr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.post(ctx, url, requestJSON, expressionAttrs(expr)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Name == "" {
+		return nil, fmt.Errorf("no map name in response")
+	}
+
+	return &MapID{
+		Name:    result.Name,
+		TileURL: fmt.Sprintf("%s/%s/tiles/{z}/{x}/{y}", c.baseURL, result.Name),
+	}, nil
+}
+
+// marshalWithParams marshals a request containing the expression and params.
+func marshalWithParams(expr *Expression, params map[string]interface{}) ([]byte, error) {
+	// Marshal the expression, then add the request parameters beside it
 	exprJSON, err := json.Marshal(expr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal expression: %w", err)
 	}
+	var request map[string]interface{}
+	if err := json.Unmarshal(exprJSON, &request); err != nil {
+		return nil, fmt.Errorf("failed to marshal expression: %w", err)
+	}
+	for key, value := range params {
+		request[key] = value
+	}
+
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return requestJSON, nil
+}
 
+// post sends a JSON request body and returns the response body, or an
+// APIError if the status is not 200. attrs are added to the request's log
+// events.
+func (c *Client) post(ctx context.Context, url string, requestBody []byte, attrs ...interface{}) ([]byte, error) {
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(exprJSON))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
+	return c.do(req, attrs...)
+}
+
+// get sends a GET request and returns the response body, or an APIError if
+// the status is not 200.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.do(req)
+}
+
+// do executes req and returns the response body, or an APIError if the
+// status is not 200. It logs the request's start and end with attrs added.
+func (c *Client) do(req *http.Request, attrs ...interface{}) ([]byte, error) {
+	ctx := req.Context()
+	logger := c.Logger()
+	method := path.Base(req.URL.Path) // For example "value:compute"
+
+	logger.Log(ctx, EventRequestStart,
+		append([]interface{}{"method", method, "request_bytes", req.ContentLength}, attrs...)...)
+	start := time.Now()
+
+	body, status, err := c.send(req)
+
+	end := []interface{}{"method", method, "status", status, "latency", time.Since(start), "response_bytes", len(body)}
+	if err != nil {
+		end = append(end, "error", err)
+	}
+	logger.Log(ctx, EventRequestEnd, end...)
+
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// send executes req and returns the response body and status code, or an
+// APIError if the status is not 200.
+func (c *Client) send(req *http.Request) ([]byte, int, error) {
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Check for errors
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return body, resp.StatusCode, newAPIError(resp.StatusCode, body)
 	}
 
-	// Parse response
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+	return body, resp.StatusCode, nil
+}
 
-	// Extract the result value
-	if val, ok := result["result"]; ok {
-		return val, nil
-	}
+// APIError is returned by ComputeValue and ComputePixels when Earth Engine responds with a non-200 status.
+//
+// Use errors.As to inspect the status, for example to detect rate limiting:
+//
+//	var apiErr *earthengine.APIError
+//	if errors.As(err, &apiErr) && apiErr.IsRateLimited() {
+//	    // back off and retry
+//	}
+//
+// It also matches the apiv1 sentinel errors with errors.Is:
+//
+//	if errors.Is(err, apiv1.ErrAssetNotFound) {
+//	    // skip the missing asset
+//	}
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Status     string // Google API status, e.g. "RESOURCE_EXHAUSTED" (may be empty)
+	Message    string // Error message from the API (may be empty)
+	Body       string // Raw response body
+}
 
-	return nil, fmt.Errorf("no result in response")
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Is reports whether target is the apiv1 sentinel error for e, so errors.Is
+// works with apiv1.ErrAssetNotFound, apiv1.ErrQuotaExceeded,
+// apiv1.ErrInvalidExpression, and apiv1.ErrPermissionDenied.
+func (e *APIError) Is(target error) bool {
+	kind := apiv1.Classify(e.StatusCode, e.Status)
+	return kind != nil && kind == target
 }
 
-// FeatureCollection represents a collection of geographic features
-type FeatureCollection struct {
-	// Placeholder for feature collection implementation
+// IsRateLimited reports whether the error indicates rate limiting or quota exhaustion.
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.Status == "RESOURCE_EXHAUSTED"
+}
+
+// newAPIError builds an APIError from a response, extracting the Google API
+// error status and message when the body contains them.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		apiErr.Status = parsed.Error.Status
+		apiErr.Message = parsed.Error.Message
+	}
+
+	return apiErr
 }
@@ -2,10 +2,15 @@ package earthengine
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 func TestNewClient_MissingProject(t *testing.T) {
@@ -130,3 +135,188 @@ func TestComputeValue_Error(t *testing.T) {
 }
 
 // Note: Coordinate validation tests moved to helpers package
+
+func TestComputeValue_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": {"code": 429, "message": "Too many requests", "status": "RESOURCE_EXHAUSTED"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		projectID:  "test-project",
+		baseURL:    server.URL,
+	}
+
+	expr := NewExpression()
+	expr.SetResult(expr.AddConstant(123))
+
+	_, err := client.ComputeValue(context.Background(), expr)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("StatusCode = %d, want 429", apiErr.StatusCode)
+	}
+	if apiErr.Status != "RESOURCE_EXHAUSTED" || apiErr.Message != "Too many requests" {
+		t.Errorf("Status = %q, Message = %q", apiErr.Status, apiErr.Message)
+	}
+	if !apiErr.IsRateLimited() {
+		t.Error("IsRateLimited() = false, want true")
+	}
+}
+
+func TestComputePixels(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/projects/test-project/image:computePixels") {
+			t.Errorf("Unexpected URL path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		projectID:  "test-project",
+		baseURL:    server.URL,
+	}
+
+	expr := NewExpression()
+	expr.SetResult(expr.AddConstant(1))
+
+	got, err := client.ComputePixels(context.Background(), expr, map[string]interface{}{"fileFormat": "PNG"})
+	if err != nil {
+		t.Fatalf("ComputePixels failed: %v", err)
+	}
+
+	if string(got) != string(png) {
+		t.Errorf("ComputePixels = %q, want %q", got, png)
+	}
+	if body["fileFormat"] != "PNG" {
+		t.Errorf("fileFormat = %v, want PNG", body["fileFormat"])
+	}
+	if _, ok := body["expression"].(map[string]interface{}); !ok {
+		t.Errorf("request has no expression: %v", body)
+	}
+}
+
+func TestCreateMap(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/test-project/maps" {
+			t.Errorf("Unexpected URL path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "projects/test-project/maps/abc123"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		projectID:  "test-project",
+		baseURL:    server.URL,
+	}
+
+	expr := NewExpression()
+	expr.SetResult(expr.AddConstant(1))
+
+	mapID, err := client.CreateMap(context.Background(), expr, map[string]interface{}{"fileFormat": "PNG"})
+	if err != nil {
+		t.Fatalf("CreateMap failed: %v", err)
+	}
+
+	if mapID.Name != "projects/test-project/maps/abc123" {
+		t.Errorf("Name = %s", mapID.Name)
+	}
+	if want := server.URL + "/projects/test-project/maps/abc123/tiles/{z}/{x}/{y}"; mapID.TileURL != want {
+		t.Errorf("TileURL = %s, want %s", mapID.TileURL, want)
+	}
+	if body["fileFormat"] != "PNG" {
+		t.Errorf("fileFormat = %v, want PNG", body["fileFormat"])
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	err := fmt.Errorf("query failed: %w", newAPIError(http.StatusTooManyRequests, []byte(`{"error": {"status": "RESOURCE_EXHAUSTED"}}`)))
+
+	if !errors.Is(err, apiv1.ErrQuotaExceeded) {
+		t.Error("errors.Is(err, apiv1.ErrQuotaExceeded) = false, want true")
+	}
+	if errors.Is(err, apiv1.ErrAssetNotFound) {
+		t.Error("errors.Is(err, apiv1.ErrAssetNotFound) = true, want false")
+	}
+}
+
+func TestWorkloadTag(t *testing.T) {
+	if err := WithWorkloadTag("Not Valid")(&Client{}); err == nil {
+		t.Error("Expected error for invalid workload tag")
+	}
+
+	var bodies []map[string]interface{}
+	var mapQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/maps") {
+			mapQuery = r.URL.RawQuery
+			w.Write([]byte(`{"name": "projects/test-project/maps/abc123"}`))
+			return
+		}
+		w.Write([]byte(`{"result": 1}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient: server.Client(),
+		projectID:  "test-project",
+		baseURL:    server.URL,
+	}
+	if err := WithWorkloadTag("dashboard")(client); err != nil {
+		t.Fatalf("WithWorkloadTag failed: %v", err)
+	}
+
+	expr := NewExpression()
+	expr.SetResult(expr.AddConstant(1))
+
+	ctx := context.Background()
+	if _, err := client.ComputeValue(ctx, expr); err != nil {
+		t.Fatalf("ComputeValue failed: %v", err)
+	}
+	if _, err := client.ComputeValue(ContextWithWorkloadTag(ctx, "backfill"), expr); err != nil {
+		t.Fatalf("ComputeValue failed: %v", err)
+	}
+	if _, err := client.CreateMap(ctx, expr, nil); err != nil {
+		t.Fatalf("CreateMap failed: %v", err)
+	}
+
+	if got := bodies[0]["workloadTag"]; got != "dashboard" {
+		t.Errorf("workloadTag = %v, want dashboard", got)
+	}
+	if got := bodies[1]["workloadTag"]; got != "backfill" {
+		t.Errorf("context workloadTag = %v, want backfill", got)
+	}
+	if mapQuery != "workloadTag=dashboard" {
+		t.Errorf("CreateMap query = %q, want workloadTag=dashboard", mapQuery)
+	}
+
+	_, err := client.ComputeValue(ContextWithWorkloadTag(ctx, "BAD"), expr)
+	if err == nil || !strings.Contains(err.Error(), "invalid workload tag") {
+		t.Errorf("ComputeValue error = %v, want invalid workload tag", err)
+	}
+}
@@ -0,0 +1,155 @@
+package earthengine
+
+import (
+	"context"
+	"fmt"
+)
+
+// Clusterer represents an unsupervised clusterer in Earth Engine.
+type Clusterer interface {
+	// NodeID returns the node ID for this clusterer in the expression graph.
+	NodeID(expr *ExpressionBuilder) string
+}
+
+// ClustererAlgorithm is an untrained clusterer and its parameters. Train it
+// with TrainingSamples.TrainClusterer.
+type ClustererAlgorithm struct {
+	algorithmName string
+	args          map[string]interface{}
+}
+
+// NodeID implements the Clusterer interface for ClustererAlgorithm.
+func (c ClustererAlgorithm) NodeID(expr *ExpressionBuilder) string {
+	return ClassifierAlgorithm(c).NodeID(expr)
+}
+
+// ClustererWekaKMeans returns an untrained k-means clusterer that groups
+// samples into nClusters clusters. The seed fixes the initial cluster centers.
+//
+// Example:
+//
+//	kmeans := earthengine.ClustererWekaKMeans(5, 42)
+func ClustererWekaKMeans(nClusters int, seed int64) Clusterer {
+	return ClustererAlgorithm{
+		algorithmName: AlgorithmClustererWekaKMeans,
+		args: map[string]interface{}{
+			"nClusters": nClusters,
+			"seed":      seed,
+		},
+	}
+}
+
+// Sample draws up to numPixels random pixels of the image within region.
+// Masked pixels are dropped, so fewer samples may be returned. The same seed
+// always gives the same samples.
+//
+// Example:
+//
+//	samples := image.Sample(region, 30, 5000, 42)
+func (img *Image) Sample(region Geometry, scale float64, numPixels int, seed int64) *TrainingSamples {
+	sampleNodeID := img.expr.FunctionCall(AlgorithmImageSample, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"region": map[string]interface{}{
+			"valueReference": region.NodeID(img.expr),
+		},
+		"scale": map[string]interface{}{
+			"constantValue": scale,
+		},
+		"numPixels": map[string]interface{}{
+			"constantValue": numPixels,
+		},
+		"seed": map[string]interface{}{
+			"constantValue": seed,
+		},
+	})
+
+	return &TrainingSamples{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: sampleNodeID,
+	}
+}
+
+// Size returns the number of samples.
+func (s *TrainingSamples) Size(ctx context.Context) (int, error) {
+	sizeNodeID := s.expr.FunctionCall(AlgorithmCollectionSize, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": s.nodeID,
+		},
+	})
+
+	result, err := s.client.ComputeValue(ctx, s.expr.Build(sizeNodeID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute sample count: %w", err)
+	}
+
+	size, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample count type: %T", result)
+	}
+
+	return int(size), nil
+}
+
+// TrainClusterer trains clusterer on the samples, using inputProperties
+// (usually the sampled band names) as features.
+//
+// Example:
+//
+//	trained := samples.TrainClusterer(earthengine.ClustererWekaKMeans(5, 42), bands)
+//	clustered := image.Cluster(trained, "cluster")
+func (s *TrainingSamples) TrainClusterer(clusterer Clusterer, inputProperties []string) *TrainedClusterer {
+	trainNodeID := s.expr.FunctionCall(AlgorithmClustererTrain, map[string]interface{}{
+		"clusterer": map[string]interface{}{
+			"valueReference": clusterer.NodeID(s.expr),
+		},
+		"features": map[string]interface{}{
+			"valueReference": s.nodeID,
+		},
+		"inputProperties": map[string]interface{}{
+			"constantValue": inputProperties,
+		},
+	})
+
+	return &TrainedClusterer{
+		expr:   s.expr,
+		nodeID: trainNodeID,
+	}
+}
+
+// TrainedClusterer is a clusterer trained on TrainingSamples.
+type TrainedClusterer struct {
+	expr   *ExpressionBuilder
+	nodeID string
+}
+
+// NodeID implements the Clusterer interface for TrainedClusterer. The
+// clusterer can be used with images built from any source.
+func (c *TrainedClusterer) NodeID(expr *ExpressionBuilder) string {
+	return expr.Import(c.expr, c.nodeID)
+}
+
+// Cluster assigns each pixel of the image to a cluster with a trained
+// clusterer, returning a single band named outputName holding cluster
+// numbers starting at 0.
+func (img *Image) Cluster(clusterer Clusterer, outputName string) *Image {
+	clusterNodeID := img.expr.FunctionCall(AlgorithmImageCluster, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"clusterer": map[string]interface{}{
+			"valueReference": clusterer.NodeID(img.expr),
+		},
+		"outputName": map[string]interface{}{
+			"constantValue": outputName,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: clusterNodeID,
+	}
+}
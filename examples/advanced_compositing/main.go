@@ -187,6 +187,28 @@ func example4_Seasonal(ctx context.Context, client *earthengine.Client) {
 			_ = composite
 		}
 	}
+	fmt.Println()
+
+	// Summer minus winter NDVI maps deciduous vegetation
+	bounds := helpers.Bounds{MinLon: -122.7, MinLat: 45.4, MaxLon: -122.5, MaxLat: 45.6}
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+	withNDVI := collection.Map(func(img *earthengine.Image) *earthengine.Image {
+		return img.AddBands(img.Select("B8", "B4").NormalizedDifference().Rename("NDVI"))
+	})
+	greenUp, err := helpers.SeasonalDifference(ctx, client, withNDVI, 2023, "summer", "winter", "NDVI",
+		helpers.CompareRegion(region),
+		helpers.ChangeThreshold(0.3))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+	fmt.Println("Green-up (summer minus winter NDVI, Portland area):")
+	fmt.Printf("  Mean difference: %.3f\n", greenUp.Stats.MeanDifference)
+	fmt.Printf("  Deciduous (difference above %.1f): %.1f%%\n", greenUp.Stats.Threshold, greenUp.Stats.PercentChanged)
 
 	fmt.Println()
 	fmt.Println("How it works:")
@@ -195,6 +217,8 @@ func example4_Seasonal(ctx context.Context, client *earthengine.Client) {
 	fmt.Println("  - Summer: June-August")
 	fmt.Println("  - Fall: September-November")
 	fmt.Println("  - Winter: December-February")
+	fmt.Println("  - Northern-hemisphere seasons by default; use")
+	fmt.Println("    SeasonalCompositeWithSeasons with SouthernSeasons south of the equator")
 	fmt.Println()
 	fmt.Println("Use cases:")
 	fmt.Println("  - Phenology studies")
@@ -295,11 +319,31 @@ func example6_CompositeComparison(ctx context.Context, client *earthengine.Clien
 	fmt.Println("  Cons: May select cloudy pixels if NDVI is high")
 	fmt.Println()
 
+	// Measure and compare the composites over a study area
+	bounds := helpers.Bounds{MinLon: -122.7, MinLat: 45.4, MaxLon: -122.5, MaxLat: 45.6}
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+
 	// Calculate composite metrics
-	medianMetrics, _ := helpers.CalculateCompositeMetrics(ctx, client, median.Image)
-	greenestMetrics, _ := helpers.CalculateCompositeMetrics(ctx, client, greenest.Image)
+	medianMetrics, err := helpers.CalculateCompositeMetrics(ctx, client, median.Image,
+		helpers.MetricsRegion(region),
+		helpers.MetricsCollection(collection))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+	greenestMetrics, err := helpers.CalculateCompositeMetrics(ctx, client, greenest.Image,
+		helpers.MetricsRegion(region),
+		helpers.MetricsCollection(collection))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
 
-	fmt.Println("Quality Metrics:")
+	fmt.Println("Quality Metrics (Portland area):")
 	fmt.Println()
 	fmt.Printf("Median - Coverage: %.1f%%, Cloud-free: %.1f%%\n",
 		medianMetrics.Coverage*100,
@@ -307,18 +351,28 @@ func example6_CompositeComparison(ctx context.Context, client *earthengine.Clien
 	fmt.Printf("Greenest - Coverage: %.1f%%, Cloud-free: %.1f%%\n",
 		greenestMetrics.Coverage*100,
 		greenestMetrics.CloudFreePixels*100)
+	fmt.Printf("Clear observations per pixel: mean %.1f, median %.0f, range %d-%d\n",
+		medianMetrics.MeanObservations,
+		medianMetrics.MedianObservations,
+		medianMetrics.MinObservations,
+		medianMetrics.MaxObservations)
 
 	fmt.Println()
 
-	// Compare the composites
 	diff, err := helpers.CompareComposites(ctx, client, median.Image,
-		greenest.Image, "NDVI")
-	if err == nil {
-		fmt.Println("Difference Analysis (NDVI band):")
-		fmt.Printf("  Mean difference: %.3f\n", diff.MeanDifference)
-		fmt.Printf("  Pixels changed: %.1f%%\n", diff.PercentChanged)
-		fmt.Printf("  Max difference: %.3f\n", diff.MaxDifference)
+		greenest.Image, "NDVI",
+		helpers.CompareRegion(region),
+		helpers.ChangeThreshold(0.1))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
 	}
+	fmt.Println("Difference Analysis (NDVI band, Portland area):")
+	fmt.Printf("  Mean difference: %.3f\n", diff.MeanDifference)
+	fmt.Printf("  Median difference: %.3f\n", diff.MedianDifference)
+	fmt.Printf("  Std dev: %.3f\n", diff.StdDevDifference)
+	fmt.Printf("  Max difference: %.3f\n", diff.MaxDifference)
+	fmt.Printf("  Pixels changed by more than %.2f: %.1f%%\n", diff.Threshold, diff.PercentChanged)
 
 	fmt.Println()
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
@@ -112,9 +114,10 @@ func example2_ExportWithProgress(ctx context.Context, client *earthengine.Client
 
 	// Wait with progress updates
 	err = task.WaitWithProgress(ctx, func(progress *earthengine.TaskProgress) {
-		fmt.Printf("\rProgress: %.1f%% | State: %s",
-			progress.Progress*100,
-			progress.State)
+		fmt.Printf("\rProgress: ~%.0f%% | State: %s | Stage: %s",
+			progress.Progress*100, // An estimate; see TaskProgress
+			progress.State,
+			progress.Stage)
 	})
 
 	fmt.Println()
@@ -276,13 +279,18 @@ func example6_OtherExportTypes(ctx context.Context, client *earthengine.Client)
 		fmt.Printf("✓ Table export started: %s\n", tableTask.ID)
 	}
 
-	// Export video (image collection time series)
-	// In a real application, you would have an actual image collection
-	collection := client.ImageCollection("COPERNICUS/S2_SR")
-	videoTask, err := helpers.ExportVideoAsync(ctx, client, collection,
+	// Export video (image collection time series). Reflectance can't be
+	// animated directly, so each frame is rendered as true color RGB.
+	var region earthengine.Geometry = earthengine.NewRectangle(-122.6, 37.6, -122.3, 37.9)
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+		FilterDate("2023-01-01", "2024-01-01").
+		FilterBounds(region)
+	videoTask, err := helpers.ExportVideoAnimation(ctx, client, collection,
+		helpers.VisualizationOptions{Bands: []string{"B4", "B3", "B2"}, Min: 0, Max: 3000},
+		6, 720,
 		helpers.ExportDescription("Time Lapse Video"),
 		helpers.ExportToGCS("my-bucket", "videos/"),
-		helpers.ExportFileFormat(helpers.MP4))
+		helpers.ExportRegion(&region))
 	if err == nil {
 		fmt.Printf("✓ Video export started: %s\n", videoTask.ID)
 	}
@@ -350,6 +358,15 @@ func example7_TaskManagement(ctx context.Context, client *earthengine.Client) {
 	fmt.Println("Cleaning up old tasks...")
 	tm.Cleanup(24 * time.Hour) // Remove tasks older than 24 hours
 
+	// Save tasks so a restarted process can pick them up with LoadState
+	// and ReconcileWithServer
+	statePath := filepath.Join(os.TempDir(), "ee-tasks.json")
+	if err := tm.SaveState(statePath); err != nil {
+		log.Printf("Error saving tasks: %v", err)
+	} else {
+		fmt.Printf("Saved %d tasks to %s\n", len(tm.ListTasks()), statePath)
+	}
+
 	fmt.Println("Task management complete")
 	fmt.Println()
 }
@@ -357,22 +374,29 @@ func example7_TaskManagement(ctx context.Context, client *earthengine.Client) {
 // Helper functions for real-world scenarios
 
 func exportNDVITimeSeries(ctx context.Context, client *earthengine.Client, startDate, endDate string, bounds [4]float64) (*earthengine.Task, error) {
-	// Create NDVI time series
-	collection := client.ImageCollection("COPERNICUS/S2_SR").
-		FilterDate(startDate, endDate).
-		FilterMetadata("CLOUDY_PIXEL_PERCENTAGE", "less_than", 20)
+	// 16-day MODIS NDVI, one band per composite date
+	collection := client.ImageCollection("MODIS/061/MOD13Q1").
+		FilterDate(startDate, endDate)
 
-	// Calculate NDVI for each image
-	// In a real implementation, you would map an NDVI calculation over the collection
-	_ = collection // Placeholder for demonstration
+	stack, err := helpers.StackCollectionToImage(ctx, client, collection, "NDVI")
+	if err != nil {
+		return nil, err
+	}
 
-	// Export as multi-band image or video
-	return helpers.ExportImageAsync(ctx, client, nil,
+	// Export as a single multi-band GeoTIFF
+	var region earthengine.Geometry = earthengine.NewRectangle(bounds[0], bounds[1], bounds[2], bounds[3])
+	return helpers.ExportImageAsync(ctx, client, stack,
 		helpers.ExportDescription("NDVI Time Series"),
 		helpers.ExportToGCS("my-bucket", "ndvi-series/"),
-		helpers.ExportScale(10))
+		helpers.ExportRegion(&region),
+		helpers.ExportScale(250))
 }
 
+// exportClassificationResult exports an image classified with
+// helpers.ClassifyImage, e.g.:
+//
+//	classified, err := helpers.ClassifyImage(composite,
+//	    "projects/my-project/assets/landcover-rf", []string{"B2", "B3", "B4", "B8"})
 func exportClassificationResult(ctx context.Context, client *earthengine.Client, classified *earthengine.Image) (*earthengine.Task, error) {
 	// Export classified image with palette
 	return helpers.ExportImageAsync(ctx, client, classified,
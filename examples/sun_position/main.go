@@ -188,13 +188,24 @@ func example3_SolarPanelOptimization(ctx context.Context, client *earthengine.Cl
 	fmt.Println()
 	fmt.Println("Annual Energy Production Estimate:")
 
-	avgDayLength := 12.0 // Simplified
-	avgSunHours := avgDayLength * 0.6 // Account for clouds, angle
+	// Clear-sky insolation on the recommended panel, summed over the year
+	panelAzimuth := 180.0
+	if lat < 0 {
+		panelAzimuth = 0
+	}
+	var annualInsolation float64 // kWh/m²
+	for day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+		annualInsolation += helpers.DailyInsolation(lat, lon, day, 15,
+			helpers.PanelOrientation(optimalTilt, panelAzimuth))
+	}
+
+	clearSkyFraction := 0.6 // Assumed share of clear-sky energy after clouds
 	panelEfficiency := 0.18 // 18% efficient panels
-	panelArea := 1.6 // Square meters per panel
+	panelArea := 1.6        // Square meters per panel
 	numPanels := 20.0
 
-	annualEnergy := avgSunHours * 365 * panelEfficiency * panelArea * numPanels * 1000 // Wh
+	annualEnergy := annualInsolation * clearSkyFraction * panelEfficiency * panelArea * numPanels * 1000 // Wh
+	fmt.Printf("  Clear-sky insolation on panels: %.0f kWh/m² per year\n", annualInsolation)
 	fmt.Printf("  Configuration: %.0f panels (%.0f m² total)\n", numPanels, numPanels*panelArea)
 	fmt.Printf("  Estimated Annual Production: %.0f kWh\n", annualEnergy/1000)
 	fmt.Printf("  Average Daily Production: %.1f kWh\n", annualEnergy/1000/365)
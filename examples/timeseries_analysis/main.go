@@ -405,20 +405,21 @@ func analyzeVegetationTrend(ctx context.Context, client *earthengine.Client, lat
 	}, nil
 }
 
-func detectDroughtEvents(ctx context.Context, client *earthengine.Client, region *earthengine.Geometry, startDate, endDate string) ([]helpers.AnomalyResult, error) {
-	// In a real implementation:
-	// 1. Get precipitation or soil moisture time series
-	// 2. Detect anomalously low values
-	// 3. Identify drought events
+func detectDroughtEvents(ctx context.Context, client *earthengine.Client, lat, lon float64, baselineStart, baselineEnd string) ([]helpers.SPIPoint, error) {
+	// 3-month SPI captures agricultural drought
+	spi, err := helpers.StandardizedPrecipitationIndex(ctx, client, lat, lon, 3, baselineStart, baselineEnd)
+	if err != nil {
+		return nil, err
+	}
 
-	// Placeholder
-	_ = ctx
-	_ = client
-	_ = region
-	_ = startDate
-	_ = endDate
+	droughts := make([]helpers.SPIPoint, 0)
+	for _, p := range spi.Points {
+		if p.SPI <= -1 {
+			droughts = append(droughts, p)
+		}
+	}
 
-	return []helpers.AnomalyResult{}, nil
+	return droughts, nil
 }
 
 func compareSeasonsAcrossYears(ctx context.Context, client *earthengine.Client, lat, lon float64, years []int) (map[int]*helpers.SeasonalDecomposition, error) {
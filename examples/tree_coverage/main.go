@@ -71,11 +71,11 @@ func main() {
 
 	// Display results
 	for i, result := range results {
-		if result.Error != nil {
-			log.Printf("Error for %s: %v", locations[i].name, result.Error)
+		coverage, err := helpers.AsFloat(result)
+		if err != nil {
+			log.Printf("Error for %s: %v", locations[i].name, err)
 			continue
 		}
-		coverage := result.Value.(float64)
 		fmt.Printf("%s: %.2f%% tree coverage\n", locations[i].name, coverage)
 	}
 
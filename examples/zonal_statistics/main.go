@@ -58,15 +58,19 @@ func example1_BasicZonalStats(ctx context.Context, client *earthengine.Client) {
 	fmt.Println("Example 1: Basic Zonal Statistics")
 	fmt.Println("----------------------------------")
 
-	// Create sample polygons (watersheds, admin boundaries, etc.)
-	zones := &earthengine.FeatureCollection{}
+	// Load polygons (watersheds, admin boundaries, etc.) from GeoJSON
+	zones, err := helpers.FeatureCollectionFromGeoJSONFile("watersheds.geojson")
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
 
 	// Load NDVI image
 	image := client.Image("COPERNICUS/S2_SR/20230701T...")
 
 	fmt.Println("Dataset: Sentinel-2 NDVI")
 	fmt.Println("Date: 2023-07-01")
-	fmt.Println("Zones: 5 watersheds")
+	fmt.Printf("Zones: %d watersheds\n", zones.Size())
 	fmt.Println()
 
 	// Calculate mean NDVI per zone
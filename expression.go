@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync"
 )
 
 // Expression represents an Earth Engine expression with a graph-based structure.
@@ -66,6 +67,28 @@ func (e *Expression) AddFunctionCall(functionName string, args map[string]interf
 	return nodeID
 }
 
+// AddArgumentReference adds a reference to a function argument and returns its ID.
+func (e *Expression) AddArgumentReference(name string) string {
+	nodeID := e.getNextID()
+	e.values[nodeID] = map[string]interface{}{
+		"argumentReference": name,
+	}
+	return nodeID
+}
+
+// AddFunctionDefinition adds a function definition node whose body is the node
+// bodyID, evaluated with the given argument names bound, and returns its ID.
+func (e *Expression) AddFunctionDefinition(argumentNames []string, bodyID string) string {
+	nodeID := e.getNextID()
+	e.values[nodeID] = map[string]interface{}{
+		"functionDefinitionValue": map[string]interface{}{
+			"argumentNames": argumentNames,
+			"body":          bodyID,
+		},
+	}
+	return nodeID
+}
+
 // SetResult sets the result node ID for the expression.
 func (e *Expression) SetResult(nodeID string) {
 	e.result = nodeID
@@ -93,8 +116,20 @@ func (e *Expression) getNextID() string {
 }
 
 // ExpressionBuilder provides a helper for building complex expressions.
+//
+// A builder is shared by every Image and ImageCollection derived from the same
+// source, so it is safe for concurrent use.
 type ExpressionBuilder struct {
-	expr *Expression
+	mu       sync.Mutex
+	expr     *Expression
+	imported map[importKey]string // Nodes already copied from other builders
+	args     int                  // Counter for generating unique argument names
+}
+
+// importKey identifies a node in another builder.
+type importKey struct {
+	builder *ExpressionBuilder
+	nodeID  string
 }
 
 // NewExpressionBuilder creates a new expression builder.
@@ -106,21 +141,173 @@ func NewExpressionBuilder() *ExpressionBuilder {
 
 // Constant adds a constant value and returns its node ID.
 func (eb *ExpressionBuilder) Constant(value interface{}) string {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
 	return eb.expr.AddConstant(value)
 }
 
 // FunctionCall adds a function call and returns its node ID.
 func (eb *ExpressionBuilder) FunctionCall(functionName string, args map[string]interface{}) string {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
 	return eb.expr.AddFunctionCall(functionName, args)
 }
 
 // Reference adds a value reference and returns its node ID.
 func (eb *ExpressionBuilder) Reference(nodeID string) string {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
 	return eb.expr.AddValueReference(nodeID)
 }
 
+// Argument creates a uniquely named function argument and returns its name
+// and the ID of a node referencing it. Use it with FunctionDefinition to build
+// functions such as the algorithm passed to Collection.map.
+func (eb *ExpressionBuilder) Argument() (name, nodeID string) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	name = fmt.Sprintf("_MAPPING_VAR_%d", eb.args)
+	eb.args++
+	return name, eb.expr.AddArgumentReference(name)
+}
+
+// FunctionDefinition adds a function definition and returns its node ID.
+func (eb *ExpressionBuilder) FunctionDefinition(argumentNames []string, bodyID string) string {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	return eb.expr.AddFunctionDefinition(argumentNames, bodyID)
+}
+
 // Build sets the result node and returns the completed expression.
+//
+// The returned expression is a snapshot containing only the nodes reachable
+// from the result, so the builder can keep growing (for example from
+// concurrent computations) without affecting or inflating it.
 func (eb *ExpressionBuilder) Build(resultNodeID string) *Expression {
-	eb.expr.SetResult(resultNodeID)
-	return eb.expr
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	values := make(map[string]interface{})
+	collectReachable(eb.expr.values, resultNodeID, values)
+
+	return &Expression{
+		values: values,
+		nextID: eb.expr.nextID,
+		result: resultNodeID,
+	}
+}
+
+// collectReachable copies nodeID and every node it references into dst.
+func collectReachable(src map[string]interface{}, nodeID string, dst map[string]interface{}) {
+	if _, seen := dst[nodeID]; seen {
+		return
+	}
+	node, ok := src[nodeID]
+	if !ok {
+		return
+	}
+	dst[nodeID] = node
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for key, child := range val {
+				if ref, ok := child.(string); ok && isReferenceKey(val, key) {
+					collectReachable(src, ref, dst)
+					continue
+				}
+				if key == "constantValue" {
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		}
+	}
+	walk(node)
+}
+
+// Import copies the node nodeID from another builder, along with every node it
+// references, and returns the ID of the copy in this builder.
+//
+// This allows values built from separate sources (for example two images
+// loaded independently) to be combined into a single expression. Importing the
+// same node again returns the existing copy.
+func (eb *ExpressionBuilder) Import(other *ExpressionBuilder, nodeID string) string {
+	if other == eb {
+		return nodeID
+	}
+
+	other.mu.Lock()
+	nodes := make(map[string]interface{})
+	collectReachable(other.expr.values, nodeID, nodes)
+	other.mu.Unlock()
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if eb.imported == nil {
+		eb.imported = make(map[importKey]string)
+	}
+
+	var importNode func(id string) string
+	importNode = func(id string) string {
+		key := importKey{builder: other, nodeID: id}
+		if newID, ok := eb.imported[key]; ok {
+			return newID
+		}
+		node, ok := nodes[id]
+		if !ok {
+			return id
+		}
+		copied := remapReferences(node, importNode)
+		newID := eb.expr.getNextID()
+		eb.expr.values[newID] = copied
+		eb.imported[key] = newID
+		return newID
+	}
+
+	return importNode(nodeID)
+}
+
+// remapReferences returns a copy of v with every valueReference rewritten by remap.
+func remapReferences(v interface{}, remap func(string) string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(val))
+		for key, child := range val {
+			if ref, ok := child.(string); ok && isReferenceKey(val, key) {
+				copied[key] = remap(ref)
+				continue
+			}
+			if key == "constantValue" {
+				copied[key] = child
+				continue
+			}
+			copied[key] = remapReferences(child, remap)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(val))
+		for i, child := range val {
+			copied[i] = remapReferences(child, remap)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// isReferenceKey reports whether key in node holds the ID of another node:
+// either a valueReference or the body of a function definition.
+func isReferenceKey(node map[string]interface{}, key string) bool {
+	if key == "valueReference" {
+		return true
+	}
+	_, isFunction := node["argumentNames"]
+	return key == "body" && isFunction
 }
@@ -175,3 +175,51 @@ func TestComplexExpression(t *testing.T) {
 		t.Fatalf("Failed to marshal complex expression: %v", err)
 	}
 }
+
+func TestExpressionBuilderImport(t *testing.T) {
+	source := NewExpressionBuilder()
+	loadID := source.FunctionCall(AlgorithmImageLoad, map[string]interface{}{
+		"id": map[string]interface{}{"constantValue": "population"},
+	})
+	areaID := source.FunctionCall(AlgorithmImageMultiply, map[string]interface{}{
+		"image1": map[string]interface{}{"valueReference": loadID},
+		"image2": map[string]interface{}{"valueReference": loadID},
+	})
+
+	builder := NewExpressionBuilder()
+	builder.Constant("existing")
+
+	importedID := builder.Import(source, areaID)
+	if again := builder.Import(source, areaID); again != importedID {
+		t.Errorf("Second import returned %s, want existing copy %s", again, importedID)
+	}
+
+	expr := builder.Build(importedID)
+	if len(expr.values) != 2 {
+		t.Fatalf("Expected 2 imported nodes, got %d", len(expr.values))
+	}
+
+	node := expr.values[importedID].(map[string]interface{})
+	args := node["functionInvocationValue"].(map[string]interface{})["arguments"].(map[string]interface{})
+	ref := args["image1"].(map[string]interface{})["valueReference"].(string)
+	if ref == loadID {
+		t.Error("Imported reference was not remapped")
+	}
+	if _, ok := expr.values[ref]; !ok {
+		t.Errorf("Imported reference %s is missing from the expression", ref)
+	}
+}
+
+func TestExpressionBuilderBuildPrunes(t *testing.T) {
+	builder := NewExpressionBuilder()
+	unused := builder.Constant("unused")
+	used := builder.Constant("used")
+
+	expr := builder.Build(used)
+	if _, ok := expr.values[unused]; ok {
+		t.Error("Unreachable node should not be included in the expression")
+	}
+	if len(expr.values) != 1 {
+		t.Errorf("Expected 1 node, got %d", len(expr.values))
+	}
+}
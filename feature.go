@@ -0,0 +1,103 @@
+package earthengine
+
+// Feature represents a geographic feature: a geometry with a set of properties.
+type Feature struct {
+	Geometry   Geometry
+	Properties map[string]interface{}
+}
+
+// NewFeature creates a new Feature from a geometry and its properties.
+func NewFeature(geometry Geometry, properties map[string]interface{}) Feature {
+	return Feature{
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
+
+// NodeID returns the node ID for this feature in the expression graph.
+func (f Feature) NodeID(expr *ExpressionBuilder) string {
+	args := map[string]interface{}{
+		"geometry": map[string]interface{}{
+			"valueReference": f.Geometry.NodeID(expr),
+		},
+	}
+	if len(f.Properties) > 0 {
+		args["metadata"] = map[string]interface{}{
+			"constantValue": f.Properties,
+		}
+	}
+
+	return expr.FunctionCall(AlgorithmFeature, args)
+}
+
+// FeatureCollection represents a collection of geographic features.
+//
+// Features are held client-side and embedded in the expression graph when the
+// collection is used in a computation (for example Image.ReduceRegions).
+type FeatureCollection struct {
+	Features []Feature
+}
+
+// NewFeatureCollection creates a new FeatureCollection from a list of features.
+//
+// Example:
+//
+//	zones := earthengine.NewFeatureCollection(
+//	    earthengine.NewFeature(earthengine.NewPoint(-122.68, 45.52), map[string]interface{}{"id": "portland"}),
+//	    earthengine.NewFeature(earthengine.NewPoint(-122.33, 47.61), map[string]interface{}{"id": "seattle"}),
+//	)
+func NewFeatureCollection(features ...Feature) *FeatureCollection {
+	return &FeatureCollection{
+		Features: features,
+	}
+}
+
+// Size returns the number of features in the collection.
+func (fc *FeatureCollection) Size() int {
+	if fc == nil {
+		return 0
+	}
+	return len(fc.Features)
+}
+
+// Chunk splits the collection into consecutive collections of at most size features.
+//
+// This is useful for keeping individual requests within Earth Engine limits
+// when working with thousands of features.
+func (fc *FeatureCollection) Chunk(size int) []*FeatureCollection {
+	if fc.Size() == 0 {
+		return nil
+	}
+	if size <= 0 || size >= len(fc.Features) {
+		return []*FeatureCollection{fc}
+	}
+
+	chunks := make([]*FeatureCollection, 0, (len(fc.Features)+size-1)/size)
+	for start := 0; start < len(fc.Features); start += size {
+		end := start + size
+		if end > len(fc.Features) {
+			end = len(fc.Features)
+		}
+		chunks = append(chunks, &FeatureCollection{Features: fc.Features[start:end]})
+	}
+
+	return chunks
+}
+
+// NodeID returns the node ID for this collection in the expression graph.
+func (fc *FeatureCollection) NodeID(expr *ExpressionBuilder) string {
+	refs := make([]interface{}, 0, fc.Size())
+	for _, feature := range fc.Features {
+		refs = append(refs, map[string]interface{}{
+			"valueReference": feature.NodeID(expr),
+		})
+	}
+
+	return expr.FunctionCall(AlgorithmCollection, map[string]interface{}{
+		"features": map[string]interface{}{
+			"arrayValue": map[string]interface{}{
+				"values": refs,
+			},
+		},
+	})
+}
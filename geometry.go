@@ -1,5 +1,10 @@
 package earthengine
 
+import (
+	"context"
+	"fmt"
+)
+
 // Geometry represents a geometric object in Earth Engine.
 type Geometry interface {
 	// NodeID returns the node ID for this geometry in the expression graph.
@@ -32,3 +37,233 @@ func (p Point) NodeID(expr *ExpressionBuilder) string {
 		},
 	})
 }
+
+// Rectangle represents an axis-aligned rectangle in longitude/latitude.
+type Rectangle struct {
+	West  float64 // Minimum longitude
+	South float64 // Minimum latitude
+	East  float64 // Maximum longitude
+	North float64 // Maximum latitude
+}
+
+// NewRectangle creates a new Rectangle geometry from its west, south, east,
+// and north edges.
+//
+// Example:
+//
+//	portland := earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65)
+func NewRectangle(west, south, east, north float64) Rectangle {
+	return Rectangle{
+		West:  west,
+		South: south,
+		East:  east,
+		North: north,
+	}
+}
+
+// NodeID implements the Geometry interface for Rectangle.
+func (r Rectangle) NodeID(expr *ExpressionBuilder) string {
+	coordinates := []interface{}{r.West, r.South, r.East, r.North}
+
+	return expr.FunctionCall(AlgorithmGeometryRectangle, map[string]interface{}{
+		"coordinates": map[string]interface{}{
+			"constantValue": coordinates,
+		},
+		"geodesic": map[string]interface{}{
+			"constantValue": false,
+		},
+	})
+}
+
+// Polygon represents a polygon made of linear rings of [longitude, latitude]
+// positions. The first ring is the exterior; any others are holes.
+type Polygon struct {
+	Rings [][][2]float64
+}
+
+// NewPolygon creates a new Polygon geometry from its rings.
+//
+// Example:
+//
+//	polygon := earthengine.NewPolygon([][2]float64{
+//	    {-122.5, 45.4}, {-122.3, 45.4}, {-122.3, 45.6}, {-122.5, 45.6}, {-122.5, 45.4},
+//	})
+func NewPolygon(rings ...[][2]float64) Polygon {
+	return Polygon{
+		Rings: rings,
+	}
+}
+
+// NodeID implements the Geometry interface for Polygon.
+func (p Polygon) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmGeometryPolygon, map[string]interface{}{
+		"coordinates": map[string]interface{}{
+			"constantValue": polygonCoordinates(p.Rings),
+		},
+		"geodesic": map[string]interface{}{
+			"constantValue": false,
+		},
+	})
+}
+
+// MultiPolygon represents a collection of polygons.
+type MultiPolygon struct {
+	Polygons []Polygon
+}
+
+// NewMultiPolygon creates a new MultiPolygon geometry.
+func NewMultiPolygon(polygons ...Polygon) MultiPolygon {
+	return MultiPolygon{
+		Polygons: polygons,
+	}
+}
+
+// NodeID implements the Geometry interface for MultiPolygon.
+func (mp MultiPolygon) NodeID(expr *ExpressionBuilder) string {
+	coordinates := make([]interface{}, len(mp.Polygons))
+	for i, polygon := range mp.Polygons {
+		coordinates[i] = polygonCoordinates(polygon.Rings)
+	}
+
+	return expr.FunctionCall(AlgorithmGeometryMultiPolygon, map[string]interface{}{
+		"coordinates": map[string]interface{}{
+			"constantValue": coordinates,
+		},
+		"geodesic": map[string]interface{}{
+			"constantValue": false,
+		},
+	})
+}
+
+// Buffer represents a geometry expanded by a distance in meters.
+// Buffering a point makes a circle.
+type Buffer struct {
+	Geometry Geometry
+	Distance float64 // Meters; negative distances shrink polygons
+}
+
+// NewBuffer creates a new Buffer geometry around geom.
+//
+// Example:
+//
+//	circle := earthengine.NewBuffer(earthengine.NewPoint(-122.6784, 45.5152), 500)
+func NewBuffer(geom Geometry, meters float64) Buffer {
+	return Buffer{
+		Geometry: geom,
+		Distance: meters,
+	}
+}
+
+// NodeID implements the Geometry interface for Buffer.
+func (b Buffer) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmGeometryBuffer, map[string]interface{}{
+		"geometry": map[string]interface{}{
+			"valueReference": b.Geometry.NodeID(expr),
+		},
+		"distance": map[string]interface{}{
+			"constantValue": b.Distance,
+		},
+	})
+}
+
+// Intersection represents the area shared by two geometries.
+type Intersection struct {
+	A Geometry
+	B Geometry
+}
+
+// NewIntersection creates a new Intersection geometry of a and b.
+//
+// Example:
+//
+//	overlap := earthengine.NewIntersection(watershed, county)
+func NewIntersection(a, b Geometry) Intersection {
+	return Intersection{
+		A: a,
+		B: b,
+	}
+}
+
+// NodeID implements the Geometry interface for Intersection.
+func (i Intersection) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmGeometryIntersection, map[string]interface{}{
+		"left": map[string]interface{}{
+			"valueReference": i.A.NodeID(expr),
+		},
+		"right": map[string]interface{}{
+			"valueReference": i.B.NodeID(expr),
+		},
+	})
+}
+
+// Dissolve represents the union of the geometries of a feature collection,
+// with internal boundaries removed.
+type Dissolve struct {
+	Collection *FeatureCollection
+}
+
+// NewDissolve creates a new Dissolve geometry from the features of fc.
+//
+// Example:
+//
+//	// One geometry covering overlapping well buffers
+//	area := earthengine.NewDissolve(buffers)
+func NewDissolve(fc *FeatureCollection) Dissolve {
+	return Dissolve{
+		Collection: fc,
+	}
+}
+
+// NodeID implements the Geometry interface for Dissolve.
+func (d Dissolve) NodeID(expr *ExpressionBuilder) string {
+	geometryNodeID := expr.FunctionCall(AlgorithmCollectionGeometry, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": d.Collection.NodeID(expr),
+		},
+	})
+
+	return expr.FunctionCall(AlgorithmGeometryDissolve, map[string]interface{}{
+		"geometry": map[string]interface{}{
+			"valueReference": geometryNodeID,
+		},
+	})
+}
+
+// polygonCoordinates converts polygon rings to nested coordinate arrays.
+func polygonCoordinates(rings [][][2]float64) []interface{} {
+	coordinates := make([]interface{}, len(rings))
+	for i, ring := range rings {
+		positions := make([]interface{}, len(ring))
+		for j, pos := range ring {
+			positions[j] = []interface{}{pos[0], pos[1]}
+		}
+		coordinates[i] = positions
+	}
+	return coordinates
+}
+
+// GeometryArea computes the geodesic area of geom in square meters.
+//
+// Example:
+//
+//	area, err := client.GeometryArea(ctx, earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65))
+func (c *Client) GeometryArea(ctx context.Context, geom Geometry) (float64, error) {
+	expr := NewExpressionBuilder()
+	areaNodeID := expr.FunctionCall(AlgorithmGeometryArea, map[string]interface{}{
+		"geometry": map[string]interface{}{
+			"valueReference": geom.NodeID(expr),
+		},
+	})
+
+	result, err := c.ComputeValue(ctx, expr.Build(areaNodeID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute area: %w", err)
+	}
+
+	area, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected area type: %T", result)
+	}
+
+	return area, nil
+}
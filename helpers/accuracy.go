@@ -0,0 +1,222 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// AccuracyResult holds the confusion matrix and accuracy metrics of a
+// classification.
+type AccuracyResult struct {
+	// Labels are the class labels in matrix order.
+	Labels []int `json:"labels"`
+
+	// Matrix counts features by class: Matrix[i][j] is the number of features
+	// of actual class Labels[i] predicted as class Labels[j].
+	Matrix [][]int `json:"matrix"`
+
+	OverallAccuracy float64 `json:"overall_accuracy"` // Fraction of features classified correctly
+	Kappa           float64 `json:"kappa"`            // Cohen's kappa coefficient
+
+	// ProducersAccuracy is, per label, the fraction of features of that
+	// class that were predicted correctly (1 - omission error). It is NaN for
+	// classes with no features.
+	ProducersAccuracy []float64 `json:"producers_accuracy"`
+
+	// ConsumersAccuracy is, per label, the fraction of features predicted as
+	// that class that actually belong to it (1 - commission error). It is NaN
+	// for classes that were never predicted.
+	ConsumersAccuracy []float64 `json:"consumers_accuracy"`
+}
+
+// ConfusionMatrix assesses a classification from validation features that
+// carry an actual class in actualProp and a predicted class in predictedProp.
+//
+// The labels are every class that occurs in either property, in ascending
+// order. Class values must be integers.
+//
+// Example:
+//
+//	result, err := helpers.ConfusionMatrix(ctx, client, validation, "landcover", "classification")
+//	fmt.Printf("Accuracy: %.2f, kappa: %.2f\n", result.OverallAccuracy, result.Kappa)
+//	fmt.Print(result.ToCSV())
+func ConfusionMatrix(ctx context.Context, client *earthengine.Client, validation *earthengine.FeatureCollection, actualProp, predictedProp string) (*AccuracyResult, error) {
+	if validation.Size() == 0 {
+		return nil, fmt.Errorf("at least one validation feature is required")
+	}
+	if actualProp == "" || predictedProp == "" {
+		return nil, fmt.Errorf("actual and predicted properties are required")
+	}
+
+	labels, err := classLabels(validation, actualProp, predictedProp)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := client.ErrorMatrix(ctx, validation, actualProp, predictedProp, labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute confusion matrix: %w", err)
+	}
+	if len(counts) != len(labels) {
+		return nil, fmt.Errorf("confusion matrix has %d rows, want %d", len(counts), len(labels))
+	}
+
+	matrix := make([][]int, len(counts))
+	for i, row := range counts {
+		if len(row) != len(labels) {
+			return nil, fmt.Errorf("confusion matrix row %d has %d columns, want %d", i, len(row), len(labels))
+		}
+		matrix[i] = make([]int, len(row))
+		for j, count := range row {
+			matrix[i][j] = int(count)
+		}
+	}
+
+	return accuracyFromMatrix(labels, matrix), nil
+}
+
+// classLabels returns the distinct class labels of the features, sorted.
+func classLabels(fc *earthengine.FeatureCollection, props ...string) ([]int, error) {
+	seen := make(map[int]bool)
+	for i, feature := range fc.Features {
+		for _, prop := range props {
+			value, ok := feature.Properties[prop]
+			if !ok {
+				return nil, fmt.Errorf("feature %d has no %q property", i, prop)
+			}
+			label, err := classLabel(value)
+			if err != nil {
+				return nil, fmt.Errorf("feature %d: invalid %q: %w", i, prop, err)
+			}
+			seen[label] = true
+		}
+	}
+
+	labels := make([]int, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+	return labels, nil
+}
+
+// classLabel converts a property value to an integer class label.
+func classLabel(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("class %v is not an integer", v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("unexpected class value %v (%T)", value, value)
+	}
+}
+
+// accuracyFromMatrix computes accuracy metrics from a confusion matrix.
+func accuracyFromMatrix(labels []int, matrix [][]int) *AccuracyResult {
+	n := len(labels)
+	result := &AccuracyResult{
+		Labels:            labels,
+		Matrix:            matrix,
+		ProducersAccuracy: make([]float64, n),
+		ConsumersAccuracy: make([]float64, n),
+	}
+
+	rowTotals := make([]int, n)
+	colTotals := make([]int, n)
+	var total, correct int
+	for i := range matrix {
+		for j, count := range matrix[i] {
+			rowTotals[i] += count
+			colTotals[j] += count
+			total += count
+		}
+		correct += matrix[i][i]
+	}
+
+	for i := 0; i < n; i++ {
+		result.ProducersAccuracy[i] = math.NaN()
+		if rowTotals[i] > 0 {
+			result.ProducersAccuracy[i] = float64(matrix[i][i]) / float64(rowTotals[i])
+		}
+		result.ConsumersAccuracy[i] = math.NaN()
+		if colTotals[i] > 0 {
+			result.ConsumersAccuracy[i] = float64(matrix[i][i]) / float64(colTotals[i])
+		}
+	}
+
+	if total == 0 {
+		return result
+	}
+
+	observed := float64(correct) / float64(total)
+	var expected float64
+	for i := 0; i < n; i++ {
+		expected += float64(rowTotals[i]) * float64(colTotals[i])
+	}
+	expected /= float64(total) * float64(total)
+
+	result.OverallAccuracy = observed
+	if expected < 1 {
+		result.Kappa = (observed - expected) / (1 - expected)
+	} else {
+		// A single class predicted perfectly
+		result.Kappa = 1
+	}
+
+	return result
+}
+
+// ToCSV formats the confusion matrix as CSV. The header row holds the
+// predicted class labels after an "actual\predicted" corner cell, and each
+// following row starts with its actual class label.
+//
+// Example:
+//
+//	fmt.Print(result.ToCSV())
+//	// actual\predicted,1,2
+//	// 1,40,3
+//	// 2,5,52
+func (r *AccuracyResult) ToCSV() string {
+	var b strings.Builder
+	b.WriteString(`actual\predicted`)
+	for _, label := range r.Labels {
+		b.WriteByte(',')
+		b.WriteString(strconv.Itoa(label))
+	}
+	b.WriteByte('\n')
+
+	for i, row := range r.Matrix {
+		b.WriteString(strconv.Itoa(r.Labels[i]))
+		for _, count := range row {
+			b.WriteByte(',')
+			b.WriteString(strconv.Itoa(count))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// WriteCSV writes the confusion matrix to path in the format of ToCSV.
+//
+// Example:
+//
+//	err := result.WriteCSV("confusion_matrix.csv")
+func (r *AccuracyResult) WriteCSV(path string) error {
+	if err := os.WriteFile(path, []byte(r.ToCSV()), 0644); err != nil {
+		return fmt.Errorf("failed to write confusion matrix: %w", err)
+	}
+	return nil
+}
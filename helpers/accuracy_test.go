@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func testValidationFeatures(pairs ...[2]interface{}) *earthengine.FeatureCollection {
+	features := make([]earthengine.Feature, len(pairs))
+	for i, p := range pairs {
+		features[i] = earthengine.NewFeature(earthengine.NewPoint(-122.68, 45.52), map[string]interface{}{
+			"landcover":      p[0],
+			"classification": p[1],
+		})
+	}
+	return earthengine.NewFeatureCollection(features...)
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": [[50, 10], [5, 35]]}`)
+	validation := testValidationFeatures([2]interface{}{2, 1}, [2]interface{}{1.0, 1})
+
+	result, err := ConfusionMatrix(context.Background(), client, validation, "landcover", "classification")
+	if err != nil {
+		t.Fatalf("ConfusionMatrix failed: %v", err)
+	}
+
+	if len(result.Labels) != 2 || result.Labels[0] != 1 || result.Labels[1] != 2 {
+		t.Errorf("Labels = %v, want [1 2]", result.Labels)
+	}
+	if result.Matrix[0][1] != 10 || result.Matrix[1][0] != 5 {
+		t.Errorf("Matrix = %v", result.Matrix)
+	}
+	if math.Abs(result.OverallAccuracy-0.85) > 1e-9 {
+		t.Errorf("OverallAccuracy = %v, want 0.85", result.OverallAccuracy)
+	}
+	// pe = (60*55 + 40*45) / 100^2 = 0.51
+	if want := (0.85 - 0.51) / 0.49; math.Abs(result.Kappa-want) > 1e-9 {
+		t.Errorf("Kappa = %v, want %v", result.Kappa, want)
+	}
+	if math.Abs(result.ProducersAccuracy[0]-50.0/60) > 1e-9 || math.Abs(result.ProducersAccuracy[1]-35.0/40) > 1e-9 {
+		t.Errorf("ProducersAccuracy = %v", result.ProducersAccuracy)
+	}
+	if math.Abs(result.ConsumersAccuracy[0]-50.0/55) > 1e-9 || math.Abs(result.ConsumersAccuracy[1]-35.0/45) > 1e-9 {
+		t.Errorf("ConsumersAccuracy = %v", result.ConsumersAccuracy)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{earthengine.AlgorithmCollectionErrorMatrix, `"order":{"constantValue":[1,2]}`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestAccuracyFromMatrixMissingClass(t *testing.T) {
+	result := accuracyFromMatrix([]int{0, 1, 2}, [][]int{
+		{4, 0, 1},
+		{0, 0, 0},
+		{0, 0, 5},
+	})
+
+	if !math.IsNaN(result.ProducersAccuracy[1]) || !math.IsNaN(result.ConsumersAccuracy[1]) {
+		t.Errorf("class 1 accuracies = %v, %v, want NaN", result.ProducersAccuracy[1], result.ConsumersAccuracy[1])
+	}
+	if result.OverallAccuracy != 0.9 {
+		t.Errorf("OverallAccuracy = %v, want 0.9", result.OverallAccuracy)
+	}
+
+	perfect := accuracyFromMatrix([]int{3}, [][]int{{7}})
+	if perfect.Kappa != 1 || perfect.OverallAccuracy != 1 {
+		t.Errorf("single class: Kappa = %v, OverallAccuracy = %v, want 1", perfect.Kappa, perfect.OverallAccuracy)
+	}
+}
+
+func TestConfusionMatrixErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": [[1]]}`)
+
+	if _, err := ConfusionMatrix(ctx, client, earthengine.NewFeatureCollection(), "landcover", "classification"); err == nil {
+		t.Error("Expected error for empty collection")
+	}
+	if _, err := ConfusionMatrix(ctx, client, testValidationFeatures([2]interface{}{1, 1}), "", "classification"); err == nil {
+		t.Error("Expected error for missing property name")
+	}
+	if _, err := ConfusionMatrix(ctx, client, testValidationFeatures([2]interface{}{1.5, 1}), "landcover", "classification"); err == nil {
+		t.Error("Expected error for non-integer class")
+	}
+	if _, err := ConfusionMatrix(ctx, client, testValidationFeatures([2]interface{}{"forest", 1}), "landcover", "classification"); err == nil {
+		t.Error("Expected error for string class")
+	}
+	if _, err := ConfusionMatrix(ctx, client, testValidationFeatures([2]interface{}{1, 2}), "landcover", "classification"); err == nil {
+		t.Error("Expected error for matrix size mismatch")
+	}
+}
+
+func TestAccuracyResultCSV(t *testing.T) {
+	result := accuracyFromMatrix([]int{1, 2}, [][]int{{40, 3}, {5, 52}})
+
+	want := "actual\\predicted,1,2\n1,40,3\n2,5,52\n"
+	if got := result.ToCSV(); got != want {
+		t.Errorf("ToCSV() = %q, want %q", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "matrix.csv")
+	if err := result.WriteCSV(path); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,306 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// AnalysisContext bundles a client with defaults shared across many helper calls.
+//
+// Defaults are applied before any per-call options, so options passed to an
+// individual method always take precedence. The free functions (NDVI,
+// Elevation, ...) remain available and are unaffected.
+//
+// Example:
+//
+//	ac := helpers.NewAnalysisContext(client,
+//	    helpers.AnalysisWithScale(10),
+//	    helpers.AnalysisWithCache(earthengine.NewMemoryCache(1000), time.Hour))
+//
+//	ndvi, err := ac.NDVI(45.5152, -122.6784, "2023-06-01", helpers.Sentinel2())
+//	elev, err := ac.Elevation(45.5152, -122.6784)
+type AnalysisContext struct {
+	client *earthengine.Client
+	scale  float64
+	crs    string
+	cache  earthengine.Cache
+	ttl    time.Duration
+	logger earthengine.Logger
+}
+
+// EventCacheError is logged by AnalysisContext when a result cannot be
+// stored in its cache, with the cache "key" and the "error".
+const EventCacheError = "cache error"
+
+// AnalysisOption configures an AnalysisContext.
+type AnalysisOption func(*AnalysisContext)
+
+// AnalysisWithScale sets the default scale in meters for all calls.
+func AnalysisWithScale(meters float64) AnalysisOption {
+	return func(ac *AnalysisContext) {
+		ac.scale = meters
+	}
+}
+
+// AnalysisWithCRS sets the default coordinate reference system for region reductions.
+func AnalysisWithCRS(crs string) AnalysisOption {
+	return func(ac *AnalysisContext) {
+		ac.crs = crs
+	}
+}
+
+// AnalysisWithCache caches point query results for ttl (0 = no expiration).
+func AnalysisWithCache(cache earthengine.Cache, ttl time.Duration) AnalysisOption {
+	return func(ac *AnalysisContext) {
+		ac.cache = cache
+		ac.ttl = ttl
+	}
+}
+
+// AnalysisWithMemoryCache caches point query results in memory for ttl
+// (0 = no expiration), keeping at most maxEntries results (0 = unlimited)
+// and evicting the least recently used first.
+//
+// Example:
+//
+//	ac := helpers.NewAnalysisContext(client, helpers.AnalysisWithMemoryCache(10*time.Minute, 5000))
+func AnalysisWithMemoryCache(ttl time.Duration, maxEntries int) AnalysisOption {
+	return AnalysisWithCache(earthengine.NewMemoryCache(maxEntries), ttl)
+}
+
+// AnalysisWithLogger sets the logger that receives query events, overriding
+// the client's logger. Each query logs EventQueryStart and EventQueryEnd with
+// its cache "key"; EventQueryEnd also reports whether it was "cached".
+//
+// Example:
+//
+//	ac := helpers.NewAnalysisContext(client,
+//	    helpers.AnalysisWithLogger(earthengine.NewSlogLogger(slog.Default())))
+func AnalysisWithLogger(logger earthengine.Logger) AnalysisOption {
+	return func(ac *AnalysisContext) {
+		ac.logger = logger
+	}
+}
+
+// NewAnalysisContext creates an AnalysisContext for the given client.
+//
+// Example:
+//
+//	ac := helpers.NewAnalysisContext(client, helpers.AnalysisWithScale(30))
+func NewAnalysisContext(client *earthengine.Client, opts ...AnalysisOption) *AnalysisContext {
+	ac := &AnalysisContext{
+		client: client,
+	}
+	for _, opt := range opts {
+		opt(ac)
+	}
+	return ac
+}
+
+// Client returns the client used by the context.
+func (ac *AnalysisContext) Client() *earthengine.Client {
+	return ac.client
+}
+
+// Scale returns the default scale in meters, or 0 if unset.
+func (ac *AnalysisContext) Scale() float64 {
+	return ac.scale
+}
+
+// CRS returns the default coordinate reference system, or "" if unset.
+func (ac *AnalysisContext) CRS() string {
+	return ac.crs
+}
+
+// NDVI calculates NDVI at a point using the context defaults. See NDVI.
+func (ac *AnalysisContext) NDVI(lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return ac.NDVIWithContext(ctx, lat, lon, date, opts...)
+}
+
+// NDVIWithContext is like NDVI but accepts a context.
+func (ac *AnalysisContext) NDVIWithContext(ctx context.Context, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if ac.scale > 0 {
+		opts = append([]ImageryOption{ImageryWithScale(ac.scale)}, opts...)
+	}
+
+	cfg := &imageryConfig{dataset: landsat8DatasetID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	key := fmt.Sprintf("ndvi|%f|%f|%s|%s", lat, lon, date, cfg.key())
+
+	return ac.cached(ctx, key, func() (float64, error) {
+		return NDVIWithContext(ctx, ac.client, lat, lon, date, opts...)
+	})
+}
+
+// Elevation returns the elevation at a point using the context defaults. See Elevation.
+func (ac *AnalysisContext) Elevation(lat, lon float64, opts ...ElevationOption) (float64, error) {
+	ctx := context.Background()
+	return ac.ElevationWithContext(ctx, lat, lon, opts...)
+}
+
+// ElevationWithContext is like Elevation but accepts a context.
+func (ac *AnalysisContext) ElevationWithContext(ctx context.Context, lat, lon float64, opts ...ElevationOption) (float64, error) {
+	if ac.scale > 0 {
+		opts = append([]ElevationOption{ElevationWithScale(ac.scale)}, opts...)
+	}
+
+	cfg := &elevationConfig{dataset: srtmDatasetID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	key := fmt.Sprintf("elevation|%f|%f|%s|%s", lat, lon, cfg.dataset, formatScale(cfg.scale))
+
+	return ac.cached(ctx, key, func() (float64, error) {
+		return ElevationWithContext(ctx, ac.client, lat, lon, opts...)
+	})
+}
+
+// TreeCoverage returns the tree canopy coverage at a point using the context
+// defaults. See TreeCoverage.
+func (ac *AnalysisContext) TreeCoverage(lat, lon float64, opts ...TreeCoverageOption) (float64, error) {
+	ctx := context.Background()
+	return ac.TreeCoverageWithContext(ctx, lat, lon, opts...)
+}
+
+// TreeCoverageWithContext is like TreeCoverage but accepts a context.
+func (ac *AnalysisContext) TreeCoverageWithContext(ctx context.Context, lat, lon float64, opts ...TreeCoverageOption) (float64, error) {
+	if ac.scale > 0 {
+		opts = append([]TreeCoverageOption{WithScale(ac.scale)}, opts...)
+	}
+
+	cfg := &treeCoverageConfig{dataset: nlcdTCCDatasetID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	key := fmt.Sprintf("treecover|%f|%f|%s|%s", lat, lon, cfg.dataset, formatScale(cfg.scale))
+	if cfg.year != nil {
+		key += fmt.Sprintf("|year=%d", *cfg.year)
+	}
+
+	return ac.cached(ctx, key, func() (float64, error) {
+		return TreeCoverageWithContext(ctx, ac.client, lat, lon, opts...)
+	})
+}
+
+// ClearCache removes all cached results. It does nothing if no cache is
+// configured.
+func (ac *AnalysisContext) ClearCache(ctx context.Context) error {
+	if ac.cache == nil {
+		return nil
+	}
+	return ac.cache.Clear(ctx)
+}
+
+// CacheStats returns the hit, miss, and size statistics of the cache, if it
+// reports them (as earthengine.MemoryCache does). ok is false otherwise.
+//
+// Example:
+//
+//	if stats, ok := ac.CacheStats(); ok {
+//	    fmt.Printf("hit rate: %.0f%%\n", stats.HitRate*100)
+//	}
+func (ac *AnalysisContext) CacheStats() (stats earthengine.CacheStats, ok bool) {
+	reporter, ok := ac.cache.(interface{ Stats() earthengine.CacheStats })
+	if !ok {
+		return earthengine.CacheStats{}, false
+	}
+	return reporter.Stats(), true
+}
+
+// ZonalStats calculates zonal statistics using the context defaults for
+// Scale and CRS when they are not set in config. See CalculateZonalStats.
+func (ac *AnalysisContext) ZonalStats(ctx context.Context, image *earthengine.Image, zones *earthengine.FeatureCollection, config ZonalStatsConfig) (*ZonalStatsResult, error) {
+	if config.Scale == 0 {
+		config.Scale = ac.scale
+	}
+	if config.CRS == "" {
+		config.CRS = ac.crs
+	}
+	logger := ac.getLogger()
+	attrs := []interface{}{"query", "zonal stats", "zones", zones.Size(), "scale", config.Scale, "crs", config.CRS}
+	logger.Log(ctx, EventQueryStart, attrs...)
+	start := time.Now()
+
+	result, err := CalculateZonalStats(ctx, ac.client, image, zones, config)
+
+	attrs = append(attrs, "duration", time.Since(start))
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	logger.Log(ctx, EventQueryEnd, attrs...)
+
+	return result, err
+}
+
+// cached returns the cached value for key, or computes and stores it, logging
+// the query's start and end.
+func (ac *AnalysisContext) cached(ctx context.Context, key string, compute func() (float64, error)) (float64, error) {
+	logger := ac.getLogger()
+	logger.Log(ctx, EventQueryStart, "query", "point", "key", key)
+	start := time.Now()
+
+	if ac.cache != nil {
+		if value, ok, err := ac.cache.Get(ctx, key); err == nil && ok {
+			if num, ok := value.(float64); ok {
+				logger.Log(ctx, EventQueryEnd, "query", "point", "key", key, "cached", true,
+					"duration", time.Since(start))
+				return num, nil
+			}
+		}
+	}
+
+	value, err := compute()
+
+	attrs := []interface{}{"query", "point", "key", key, "cached", false, "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	logger.Log(ctx, EventQueryEnd, attrs...)
+	if err != nil {
+		return 0, err
+	}
+
+	if ac.cache != nil {
+		if err := ac.cache.Set(ctx, key, value, ac.ttl); err != nil {
+			logger.Log(ctx, EventCacheError, "key", key, "error", err)
+		}
+	}
+	return value, nil
+}
+
+// getLogger returns the context's logger, falling back to the client's.
+func (ac *AnalysisContext) getLogger() earthengine.Logger {
+	if ac.logger != nil {
+		return ac.logger
+	}
+	if ac.client != nil {
+		return ac.client.Logger()
+	}
+	return earthengine.NopLogger()
+}
+
+// key returns a string identifying the resolved imagery configuration.
+func (cfg *imageryConfig) key() string {
+	key := cfg.dataset + "|" + formatScale(cfg.scale)
+	if cfg.cloudCover != nil {
+		key += fmt.Sprintf("|cloud=%g", *cfg.cloudCover)
+	}
+	if cfg.dateRange != nil {
+		key += "|" + cfg.dateRange.Start + "/" + cfg.dateRange.End
+	}
+	return key
+}
+
+// formatScale formats an optional scale for use in cache keys.
+func formatScale(scale *float64) string {
+	if scale == nil {
+		return "default"
+	}
+	return fmt.Sprintf("%g", *scale)
+}
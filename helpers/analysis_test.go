@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// newScaleRecordingClient returns a client whose server records the scale of
+// every reduceRegion request and responds with a single value.
+func newScaleRecordingClient(t *testing.T) (*earthengine.Client, func() []float64) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var scales []float64
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Expression struct {
+				Result string                            `json:"result"`
+				Values map[string]map[string]interface{} `json:"values"`
+			} `json:"expression"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		call := body.Expression.Values[body.Expression.Result]["functionInvocationValue"].(map[string]interface{})
+		args := call["arguments"].(map[string]interface{})
+		scale, _ := args["scale"].(map[string]interface{})["constantValue"].(float64)
+
+		mu.Lock()
+		scales = append(scales, scale)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"value": 0.42}}`))
+	})
+
+	return client, func() []float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]float64(nil), scales...)
+	}
+}
+
+func TestAnalysisContextDefaults(t *testing.T) {
+	client, scales := newScaleRecordingClient(t)
+	ac := NewAnalysisContext(client, AnalysisWithScale(10), AnalysisWithCRS("EPSG:3857"))
+
+	if ac.Client() != client {
+		t.Error("Client() does not return the context client")
+	}
+	if ac.CRS() != "EPSG:3857" {
+		t.Errorf("CRS() = %s, want EPSG:3857", ac.CRS())
+	}
+
+	if _, err := ac.NDVI(45.5, -122.6, "2023-06-01"); err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+	if _, err := ac.Elevation(45.5, -122.6); err != nil {
+		t.Fatalf("Elevation failed: %v", err)
+	}
+
+	// Per-call options override context defaults
+	if _, err := ac.Elevation(45.5, -122.6, ElevationWithScale(90)); err != nil {
+		t.Fatalf("Elevation failed: %v", err)
+	}
+
+	got := scales()
+	want := []float64{10, 10, 90}
+	if len(got) != len(want) {
+		t.Fatalf("Made %d requests, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d scale = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAnalysisContextCache(t *testing.T) {
+	client, scales := newScaleRecordingClient(t)
+	ac := NewAnalysisContext(client, AnalysisWithCache(earthengine.NewMemoryCache(0), 0))
+
+	for i := 0; i < 3; i++ {
+		elev, err := ac.Elevation(45.5, -122.6)
+		if err != nil {
+			t.Fatalf("Elevation failed: %v", err)
+		}
+		if elev != 0.42 {
+			t.Errorf("Elevation = %v, want 0.42", elev)
+		}
+	}
+
+	// A different dataset is a different cache entry
+	if _, err := ac.Elevation(45.5, -122.6, USGS3DEP()); err != nil {
+		t.Fatalf("Elevation failed: %v", err)
+	}
+
+	if n := len(scales()); n != 2 {
+		t.Errorf("Made %d requests, want 2", n)
+	}
+}
+
+func TestAnalysisContextMemoryCache(t *testing.T) {
+	client, scales := newScaleRecordingClient(t)
+	ac := NewAnalysisContext(client, AnalysisWithMemoryCache(0, 2))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := ac.TreeCoverage(45.5, -122.6); err != nil {
+			t.Fatalf("TreeCoverage failed: %v", err)
+		}
+	}
+	if _, err := ac.TreeCoverage(45.5, -122.6, Year(2016)); err != nil {
+		t.Fatalf("TreeCoverage failed: %v", err)
+	}
+	if n := len(scales()); n != 2 {
+		t.Errorf("Made %d requests, want 2", n)
+	}
+
+	stats, ok := ac.CacheStats()
+	if !ok {
+		t.Fatal("CacheStats not reported for memory cache")
+	}
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Size != 2 || stats.HitRate != 0.5 {
+		t.Errorf("stats = %+v, want 2 hits, 2 misses, 2 entries", stats)
+	}
+
+	if err := ac.ClearCache(ctx); err != nil {
+		t.Fatalf("ClearCache failed: %v", err)
+	}
+	if _, err := ac.TreeCoverage(45.5, -122.6); err != nil {
+		t.Fatalf("TreeCoverage failed: %v", err)
+	}
+	if n := len(scales()); n != 3 {
+		t.Errorf("Made %d requests after ClearCache, want 3", n)
+	}
+
+	if _, ok := NewAnalysisContext(client).CacheStats(); ok {
+		t.Error("CacheStats reported without a cache")
+	}
+}
+
+func TestAnalysisContextLogger(t *testing.T) {
+	client, _ := newScaleRecordingClient(t)
+	logger := &eventLogger{}
+	ac := NewAnalysisContext(client,
+		AnalysisWithCache(earthengine.NewMemoryCache(0), 0),
+		AnalysisWithLogger(logger))
+
+	for i := 0; i < 2; i++ {
+		if _, err := ac.Elevation(45.5, -122.6); err != nil {
+			t.Fatalf("Elevation failed: %v", err)
+		}
+	}
+
+	if got := logger.count(EventQueryStart); got != 2 {
+		t.Errorf("logged %d query starts, want 2", got)
+	}
+	if got := logger.count(EventQueryEnd); got != 2 {
+		t.Errorf("logged %d query ends, want 2", got)
+	}
+
+	var cached []bool
+	for i, event := range logger.events {
+		if event != EventQueryEnd {
+			continue
+		}
+		attrs := logger.attrs[i]
+		if _, ok := attrs["key"].(string); !ok {
+			t.Errorf("%s event missing key: %v", event, attrs)
+		}
+		cached = append(cached, attrs["cached"].(bool))
+	}
+	if len(cached) != 2 || cached[0] || !cached[1] {
+		t.Errorf("cached = %v, want [false true]", cached)
+	}
+}
+
+func TestAnalysisContextClientLogger(t *testing.T) {
+	logger := &eventLogger{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"value": 0.42}}`))
+	}, earthengine.WithLogger(logger))
+	ac := NewAnalysisContext(client)
+
+	if _, err := ac.Elevation(45.5, -122.6); err != nil {
+		t.Fatalf("Elevation failed: %v", err)
+	}
+	if got := logger.count(EventQueryEnd); got != 1 {
+		t.Errorf("logged %d query ends through the client logger, want 1", got)
+	}
+}
@@ -0,0 +1,748 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+// ListOption is a functional option for ListAssets.
+type ListOption func(*listConfig)
+
+// listConfig configures asset listing.
+type listConfig struct {
+	assetType string
+	dateRange *DateRange
+	pageSize  int
+	onAsset   func(*apiv1.EarthEngineAsset) error
+}
+
+// AssetType keeps only assets of the given type, such as "IMAGE",
+// "IMAGE_COLLECTION", "TABLE", or "FOLDER".
+func AssetType(assetType string) ListOption {
+	return func(cfg *listConfig) {
+		cfg.assetType = assetType
+	}
+}
+
+// AssetDateRange keeps only assets whose StartTime to EndTime extent overlaps
+// start (inclusive) to end (exclusive). Dates are YYYY-MM-DD or RFC 3339.
+// Assets without a temporal extent are skipped.
+func AssetDateRange(start, end string) ListOption {
+	return func(cfg *listConfig) {
+		cfg.dateRange = &DateRange{Start: start, End: end}
+	}
+}
+
+// AssetPageSize sets the number of assets requested per page. The server
+// default is used if not set.
+func AssetPageSize(size int) ListOption {
+	return func(cfg *listConfig) {
+		cfg.pageSize = size
+	}
+}
+
+// OnAsset passes each matching asset to fn as pages arrive instead of
+// collecting them, so very large folders don't need to be held in memory.
+// ListAssets then returns a nil slice. Listing stops if fn returns an error.
+func OnAsset(fn func(*apiv1.EarthEngineAsset) error) ListOption {
+	return func(cfg *listConfig) {
+		cfg.onAsset = fn
+	}
+}
+
+// ListAssets lists the assets in a folder or image collection, following page
+// tokens until every page has been read.
+//
+// Example:
+//
+//	// All images under a folder from 2023
+//	images, err := helpers.ListAssets(ctx, service, "projects/my-project/assets/scenes",
+//	    helpers.AssetType("IMAGE"),
+//	    helpers.AssetDateRange("2023-01-01", "2024-01-01"))
+//
+//	// Stream a very large collection
+//	_, err = helpers.ListAssets(ctx, service, "projects/my-project/assets/archive",
+//	    helpers.OnAsset(func(asset *apiv1.EarthEngineAsset) error {
+//	        fmt.Println(asset.Name)
+//	        return nil
+//	    }))
+func ListAssets(ctx context.Context, service *apiv1.Service, parent string, opts ...ListOption) ([]*apiv1.EarthEngineAsset, error) {
+	if service == nil {
+		return nil, fmt.Errorf("service cannot be nil")
+	}
+
+	cfg := &listConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var start, end time.Time
+	if cfg.dateRange != nil {
+		var err error
+		if start, err = parseAssetTime(cfg.dateRange.Start); err != nil {
+			return nil, fmt.Errorf("invalid start date: %w", err)
+		}
+		if end, err = parseAssetTime(cfg.dateRange.End); err != nil {
+			return nil, fmt.Errorf("invalid end date: %w", err)
+		}
+		if !start.Before(end) {
+			return nil, fmt.Errorf("start date %s must be before end date %s", cfg.dateRange.Start, cfg.dateRange.End)
+		}
+	}
+
+	var assets []*apiv1.EarthEngineAsset
+	pageToken := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := service.Projects.Assets.ListAssets(ctx, parent, cfg.pageSize, pageToken, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list assets in %s: %w", parent, err)
+		}
+
+		for _, asset := range resp.Assets {
+			if cfg.assetType != "" && asset.Type != cfg.assetType {
+				continue
+			}
+			if cfg.dateRange != nil && !assetOverlaps(asset, start, end) {
+				continue
+			}
+
+			if cfg.onAsset != nil {
+				if err := cfg.onAsset(asset); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			assets = append(assets, asset)
+		}
+
+		if resp.NextPageToken == "" {
+			return assets, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// assetOverlaps reports whether the asset's temporal extent overlaps
+// [start, end). Asset end times are exclusive; an asset with only a StartTime
+// is treated as an instant.
+func assetOverlaps(asset *apiv1.EarthEngineAsset, start, end time.Time) bool {
+	assetStart, err := time.Parse(time.RFC3339, asset.StartTime)
+	if err != nil {
+		return false
+	}
+	if asset.EndTime == "" || asset.EndTime == asset.StartTime {
+		return !assetStart.Before(start) && assetStart.Before(end)
+	}
+
+	assetEnd, err := time.Parse(time.RFC3339, asset.EndTime)
+	if err != nil {
+		return false
+	}
+	return assetStart.Before(end) && assetEnd.After(start)
+}
+
+// parseAssetTime parses a YYYY-MM-DD date or an RFC 3339 timestamp.
+func parseAssetTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// AssetInfo summarizes an asset's metadata.
+type AssetInfo struct {
+	Name        string                 `json:"name"` // Full resource name, projects/*/assets/**
+	Type        string                 `json:"type"` // IMAGE, IMAGE_COLLECTION, TABLE, or FOLDER
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	StartTime   time.Time              `json:"start_time"` // Zero if the asset has no temporal extent
+	EndTime     time.Time              `json:"end_time"`   // Zero if the asset has no temporal extent
+	UpdateTime  time.Time              `json:"update_time"`
+	SizeBytes   int64                  `json:"size_bytes"`
+	Bands       []BandInfo             `json:"bands"`
+	Bounds      *Bounds                `json:"bounds"` // Bounding box of the footprint; nil if unknown
+	Properties  map[string]interface{} `json:"properties"`
+}
+
+// BandInfo describes one band of an image asset.
+type BandInfo struct {
+	ID       string  `json:"id"`
+	DataType string  `json:"data_type"` // Go-style type such as "uint8", "int16", "float32", or "float64"
+	CRS      string  `json:"crs"`       // EPSG code such as "EPSG:32610", or WKT if there is no code
+	Scale    float64 `json:"scale"`     // Pixel width in CRS units (meters for projected CRSs)
+	Width    int64   `json:"width"`     // Grid width in pixels
+	Height   int64   `json:"height"`    // Grid height in pixels
+}
+
+// DescribeAsset returns a summary of an asset's metadata.
+//
+// assetID may be a full resource name ("projects/my-project/assets/dem"), a
+// public catalog ID ("USGS/SRTMGL1_003"), or a legacy user asset
+// ("users/someone/dem").
+//
+// Example:
+//
+//	info, err := helpers.DescribeAsset(ctx, service, "USGS/SRTMGL1_003")
+//	for _, band := range info.Bands {
+//	    fmt.Printf("%s: %s, %s at %.0fm\n", band.ID, band.DataType, band.CRS, band.Scale)
+//	}
+func DescribeAsset(ctx context.Context, service *apiv1.Service, assetID string) (*AssetInfo, error) {
+	if service == nil {
+		return nil, fmt.Errorf("service cannot be nil")
+	}
+	if assetID == "" {
+		return nil, fmt.Errorf("asset ID is required")
+	}
+
+	asset, err := service.Projects.Assets.Get(ctx, assetName(assetID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset %s: %w", assetID, err)
+	}
+
+	info := &AssetInfo{
+		Name:        asset.Name,
+		Type:        asset.Type,
+		Title:       asset.Title,
+		Description: asset.Description,
+		SizeBytes:   asset.SizeBytes,
+		Properties:  asset.Properties,
+	}
+
+	// Timestamps are informational, so unparseable values are left zero
+	info.StartTime, _ = time.Parse(time.RFC3339, asset.StartTime)
+	info.EndTime, _ = time.Parse(time.RFC3339, asset.EndTime)
+	info.UpdateTime, _ = time.Parse(time.RFC3339, asset.UpdateTime)
+
+	for _, band := range asset.Bands {
+		if band == nil {
+			continue
+		}
+		bandInfo := BandInfo{
+			ID:       band.ID,
+			DataType: pixelDataType(band.DataType),
+		}
+		if grid := band.Grid; grid != nil {
+			bandInfo.CRS = grid.CrsCode
+			if bandInfo.CRS == "" {
+				bandInfo.CRS = grid.CrsWkt
+			}
+			if grid.AffineTransform != nil {
+				bandInfo.Scale = math.Abs(grid.AffineTransform.ScaleX)
+			}
+			if grid.Dimensions != nil {
+				bandInfo.Width = grid.Dimensions.Width
+				bandInfo.Height = grid.Dimensions.Height
+			}
+		}
+		info.Bands = append(info.Bands, bandInfo)
+	}
+
+	if bounds, ok := geoJSONBounds(asset.Geometry); ok {
+		info.Bounds = &bounds
+	}
+
+	return info, nil
+}
+
+// assetName converts an asset ID to a full resource name.
+func assetName(assetID string) string {
+	switch {
+	case strings.HasPrefix(assetID, "projects/"):
+		return assetID
+	case strings.HasPrefix(assetID, "users/"):
+		return "projects/earthengine-legacy/assets/" + assetID
+	default:
+		return "projects/earthengine-public/assets/" + assetID
+	}
+}
+
+// pixelDataType converts a band data type to a Go-style type name, using the
+// smallest integer type that holds the value range.
+func pixelDataType(dataType *apiv1.PixelDataType) string {
+	if dataType == nil {
+		return ""
+	}
+
+	switch dataType.Precision {
+	case "FLOAT":
+		return "float32"
+	case "DOUBLE":
+		return "float64"
+	case "INT":
+		if dataType.Range == nil {
+			return "int64"
+		}
+		min, max := dataType.Range.Min, dataType.Range.Max
+		switch {
+		case min >= 0 && max <= math.MaxUint8:
+			return "uint8"
+		case min >= math.MinInt8 && max <= math.MaxInt8:
+			return "int8"
+		case min >= 0 && max <= math.MaxUint16:
+			return "uint16"
+		case min >= math.MinInt16 && max <= math.MaxInt16:
+			return "int16"
+		case min >= 0 && max <= math.MaxUint32:
+			return "uint32"
+		case min >= math.MinInt32 && max <= math.MaxInt32:
+			return "int32"
+		default:
+			return "int64"
+		}
+	default:
+		return strings.ToLower(dataType.Precision)
+	}
+}
+
+// geoJSONBounds returns the bounding box of a decoded GeoJSON geometry.
+func geoJSONBounds(geometry interface{}) (Bounds, bool) {
+	bounds := Bounds{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			visit(v["coordinates"])
+			visit(v["geometries"])
+		case []interface{}:
+			// A position is an array of numbers: longitude, latitude, ...
+			if len(v) >= 2 {
+				lon, lonOK := v[0].(float64)
+				lat, latOK := v[1].(float64)
+				if lonOK && latOK {
+					bounds.MinLon = math.Min(bounds.MinLon, lon)
+					bounds.MaxLon = math.Max(bounds.MaxLon, lon)
+					bounds.MinLat = math.Min(bounds.MinLat, lat)
+					bounds.MaxLat = math.Max(bounds.MaxLat, lat)
+					return
+				}
+			}
+			for _, item := range v {
+				visit(item)
+			}
+		}
+	}
+	visit(geometry)
+
+	if math.IsInf(bounds.MinLon, 1) {
+		return Bounds{}, false
+	}
+	return bounds, true
+}
+
+// CopyAsset copies an asset to dstID, leaving the source in place.
+//
+// IDs are resolved as for DescribeAsset, so public catalog images can be
+// copied into a project. It returns an error wrapping apiv1.ErrAssetNotFound
+// if the source doesn't exist.
+//
+// Example:
+//
+//	err := helpers.CopyAsset(ctx, service,
+//	    "projects/my-project/assets/exports/ndvi_2023",
+//	    "projects/my-project/assets/published/ndvi_2023")
+func CopyAsset(ctx context.Context, service *apiv1.Service, srcID, dstID string) error {
+	src, dst, err := assetTransferNames(service, srcID, dstID)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Projects.Assets.Copy(ctx, src, dst); err != nil {
+		return assetError("copy", srcID, err)
+	}
+	return nil
+}
+
+// MoveAsset moves (renames) an asset to dstID.
+//
+// It returns an error wrapping apiv1.ErrAssetNotFound if the source doesn't
+// exist.
+//
+// Example:
+//
+//	err := helpers.MoveAsset(ctx, service,
+//	    "projects/my-project/assets/scratch/dem",
+//	    "projects/my-project/assets/terrain/dem")
+func MoveAsset(ctx context.Context, service *apiv1.Service, srcID, dstID string) error {
+	src, dst, err := assetTransferNames(service, srcID, dstID)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Projects.Assets.Move(ctx, src, dst); err != nil {
+		return assetError("move", srcID, err)
+	}
+	return nil
+}
+
+// assetTransferNames validates the arguments of CopyAsset and MoveAsset and
+// returns the full source and destination names.
+func assetTransferNames(service *apiv1.Service, srcID, dstID string) (src, dst string, err error) {
+	if service == nil {
+		return "", "", fmt.Errorf("service cannot be nil")
+	}
+	if srcID == "" || dstID == "" {
+		return "", "", fmt.Errorf("source and destination asset IDs are required")
+	}
+
+	src, dst = assetName(srcID), assetName(dstID)
+	if src == dst {
+		return "", "", fmt.Errorf("source and destination are the same asset: %s", src)
+	}
+	return src, dst, nil
+}
+
+// assetError wraps an error from an asset operation, stating plainly when
+// the asset doesn't exist.
+func assetError(op, assetID string, err error) error {
+	if errors.Is(err, apiv1.ErrAssetNotFound) {
+		return fmt.Errorf("failed to %s asset %s: asset does not exist: %w", op, assetID, err)
+	}
+	return fmt.Errorf("failed to %s asset %s: %w", op, assetID, err)
+}
+
+// Asset roles and the member granting public access.
+const (
+	assetReaderRole = "roles/viewer"
+	publicMember    = "allUsers"
+)
+
+// SetAssetACL sets who can read an asset.
+//
+// readers replaces the asset's current readers and may hold email addresses
+// (treated as users) or members prefixed with "user:", "group:",
+// "serviceAccount:", or "domain:". If public is true anyone can read the
+// asset.
+//
+// The existing policy is read and only its reader role is changed, so
+// owners and writers are kept; the update fails rather than overwriting a
+// policy changed concurrently.
+//
+// Example:
+//
+//	err := helpers.SetAssetACL(ctx, service, "projects/my-project/assets/published/ndvi_2023",
+//	    []string{"alice@example.com", "group:lab@example.com"}, false)
+func SetAssetACL(ctx context.Context, service *apiv1.Service, assetID string, readers []string, public bool) error {
+	if service == nil {
+		return fmt.Errorf("service cannot be nil")
+	}
+	if assetID == "" {
+		return fmt.Errorf("asset ID is required")
+	}
+
+	members := make([]string, 0, len(readers)+1)
+	for _, reader := range readers {
+		member, err := aclMember(reader)
+		if err != nil {
+			return err
+		}
+		if !containsString(members, member) {
+			members = append(members, member)
+		}
+	}
+	if public {
+		members = append(members, publicMember)
+	}
+
+	name := assetName(assetID)
+	policy, err := service.Projects.Assets.GetIamPolicy(ctx, name)
+	if err != nil {
+		return assetError("read permissions of", assetID, err)
+	}
+
+	// Replace the reader binding, keeping every other role
+	bindings := make([]*apiv1.Binding, 0, len(policy.Bindings)+1)
+	for _, binding := range policy.Bindings {
+		if binding != nil && binding.Role != assetReaderRole {
+			bindings = append(bindings, binding)
+		}
+	}
+	if len(members) > 0 {
+		bindings = append(bindings, &apiv1.Binding{Role: assetReaderRole, Members: members})
+	}
+	policy.Bindings = bindings
+
+	if _, err := service.Projects.Assets.SetIamPolicy(ctx, name, policy); err != nil {
+		return assetError("set permissions of", assetID, err)
+	}
+	return nil
+}
+
+// aclMember validates a reader and returns it as an IAM member.
+func aclMember(reader string) (string, error) {
+	kind, value := "user", reader
+	if i := strings.Index(reader, ":"); i >= 0 {
+		kind, value = reader[:i], reader[i+1:]
+	}
+
+	switch kind {
+	case "user", "group", "serviceAccount":
+		if !validEmail(value) {
+			return "", fmt.Errorf("invalid %s email address %q", kind, value)
+		}
+	case "domain":
+		if !validDomain(value) {
+			return "", fmt.Errorf("invalid domain %q", value)
+		}
+	default:
+		return "", fmt.Errorf("invalid reader %q: use an email address or a user:, group:, serviceAccount:, or domain: member", reader)
+	}
+	return kind + ":" + value, nil
+}
+
+// validEmail reports whether s is a bare email address with a dotted domain.
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return false
+	}
+	return validDomain(s[strings.LastIndex(s, "@")+1:])
+}
+
+// validDomain reports whether s looks like a DNS domain name.
+func validDomain(s string) bool {
+	if !strings.Contains(s, ".") || strings.HasPrefix(s, ".") || strings.HasSuffix(s, ".") {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteOption is a functional option for DeleteAssets and
+// DeleteAssetsMatching.
+type DeleteOption func(*deleteConfig)
+
+// deleteConfig configures asset deletion.
+type deleteConfig struct {
+	dryRun    bool
+	recursive bool
+}
+
+// DryRun reports the assets that would be deleted, in the order they would
+// be deleted, without deleting anything.
+func DryRun() DeleteOption {
+	return func(cfg *deleteConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// Recursive deletes the contents of folders and image collections before
+// the containers themselves. Without it, containers are not listed and
+// deleting a non-empty one fails.
+func Recursive() DeleteOption {
+	return func(cfg *deleteConfig) {
+		cfg.recursive = true
+	}
+}
+
+// deleteTarget is an asset to delete. assetType is empty if unknown.
+type deleteTarget struct {
+	name      string
+	assetType string
+}
+
+// deleteAssetQuery deletes one asset. It uses the apiv1 service, not the
+// batch's client, and returns the asset name even on failure so results can
+// be matched to assets.
+type deleteAssetQuery struct {
+	service *apiv1.Service
+	name    string
+}
+
+// Execute implements Query.
+func (q *deleteAssetQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	_ = client
+
+	if err := q.service.Projects.Assets.Delete(ctx, q.name); err != nil {
+		return q.name, fmt.Errorf("failed to delete %s: %w", q.name, err)
+	}
+	return q.name, nil
+}
+
+// assetTypeQuery looks up the type of one asset.
+type assetTypeQuery struct {
+	service *apiv1.Service
+	name    string
+}
+
+// Execute implements Query.
+func (q *assetTypeQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	_ = client
+
+	asset, err := q.service.Projects.Assets.Get(ctx, q.name)
+	if err != nil {
+		return nil, err
+	}
+	return asset.Type, nil
+}
+
+// DeleteAssets deletes assets in parallel, running up to concurrency
+// deletions at once (10 if concurrency is not positive).
+//
+// Each Result's Value is the full name of the asset it reports on, and its
+// Error says why that deletion failed, so one failure doesn't stop the rest;
+// use FilterFailed to collect them. The returned error is only for failures
+// that stop the whole operation, such as a canceled context or a folder that
+// can't be listed.
+//
+// With Recursive, the contents of folders and image collections are deleted
+// first, deepest first, and appear in the results before their containers.
+//
+// Example:
+//
+//	results, err := helpers.DeleteAssets(ctx, service, []string{
+//	    "projects/my-project/assets/exports/ndvi_2019",
+//	    "projects/my-project/assets/exports/ndvi_2020",
+//	}, 10)
+//	for _, failed := range helpers.FilterFailed(results) {
+//	    log.Printf("%s: %v", failed.Value, failed.Error)
+//	}
+func DeleteAssets(ctx context.Context, service *apiv1.Service, assetIDs []string, concurrency int, opts ...DeleteOption) ([]Result, error) {
+	if service == nil {
+		return nil, fmt.Errorf("service cannot be nil")
+	}
+
+	cfg := &deleteConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	targets := make([]deleteTarget, len(assetIDs))
+	for i, id := range assetIDs {
+		if id == "" {
+			return nil, fmt.Errorf("asset ID %d is empty", i)
+		}
+		targets[i] = deleteTarget{name: assetName(id)}
+	}
+
+	// Look up types to find the containers to expand. Assets whose type
+	// can't be read are deleted as-is, which reports the error.
+	if cfg.recursive && len(targets) > 0 {
+		batch := NewBatch(nil, concurrency)
+		for _, target := range targets {
+			batch.Add(&assetTypeQuery{service: service, name: target.name})
+		}
+		types, err := batch.Execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range types {
+			if assetType, ok := result.Value.(string); ok {
+				targets[i].assetType = assetType
+			}
+		}
+	}
+
+	return deleteTargets(ctx, service, targets, concurrency, cfg)
+}
+
+// DeleteAssetsMatching deletes the assets directly in parent whose IDs start
+// with prefix, for example to clean up the outputs of one export run.
+//
+// prefix is matched against the asset ID relative to parent and must not be
+// empty. Results are reported as for DeleteAssets; Recursive also deletes
+// the contents of matching folders and image collections.
+//
+// Example:
+//
+//	// Preview, then delete, every asset named "tmp_..."
+//	preview, err := helpers.DeleteAssetsMatching(ctx, service,
+//	    "projects/my-project/assets/exports", "tmp_", helpers.DryRun())
+//	results, err := helpers.DeleteAssetsMatching(ctx, service,
+//	    "projects/my-project/assets/exports", "tmp_")
+func DeleteAssetsMatching(ctx context.Context, service *apiv1.Service, parent, prefix string, opts ...DeleteOption) ([]Result, error) {
+	if service == nil {
+		return nil, fmt.Errorf("service cannot be nil")
+	}
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix is required; use DeleteAssets to delete a whole folder")
+	}
+
+	cfg := &deleteConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parent = strings.TrimSuffix(assetName(parent), "/")
+	assets, err := ListAssets(ctx, service, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []deleteTarget
+	for _, asset := range assets {
+		if strings.HasPrefix(strings.TrimPrefix(asset.Name, parent+"/"), prefix) {
+			targets = append(targets, deleteTarget{name: asset.Name, assetType: asset.Type})
+		}
+	}
+
+	return deleteTargets(ctx, service, targets, 0, cfg)
+}
+
+// deleteTargets deletes targets, expanding containers first if cfg is
+// recursive. Each level of the tree is deleted as one batch, deepest first,
+// since a container can only be deleted once it is empty.
+func deleteTargets(ctx context.Context, service *apiv1.Service, targets []deleteTarget, concurrency int, cfg *deleteConfig) ([]Result, error) {
+	levels := [][]deleteTarget{targets}
+	if cfg.recursive {
+		for depth := 0; depth < len(levels); depth++ {
+			var children []deleteTarget
+			for _, target := range levels[depth] {
+				if target.assetType != "FOLDER" && target.assetType != "IMAGE_COLLECTION" {
+					continue
+				}
+				assets, err := ListAssets(ctx, service, target.name)
+				if err != nil {
+					return nil, err
+				}
+				for _, asset := range assets {
+					children = append(children, deleteTarget{name: asset.Name, assetType: asset.Type})
+				}
+			}
+			if len(children) > 0 {
+				levels = append(levels, children)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(targets))
+	for depth := len(levels) - 1; depth >= 0; depth-- {
+		if cfg.dryRun {
+			for _, target := range levels[depth] {
+				results = append(results, Result{Value: target.name, Index: len(results)})
+			}
+			continue
+		}
+
+		batch := NewBatch(nil, concurrency)
+		for _, target := range levels[depth] {
+			batch.Add(&deleteAssetQuery{service: service, name: target.name})
+		}
+		levelResults, err := batch.Execute(ctx)
+		for _, result := range levelResults {
+			result.Index = len(results)
+			results = append(results, result)
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
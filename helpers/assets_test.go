@@ -0,0 +1,520 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+// newAssetsTestService returns a service whose listAssets responses are pages,
+// chained by page tokens "1", "2", and so on, and a function returning the
+// page tokens requested.
+func newAssetsTestService(t *testing.T, pages ...string) (*apiv1.Service, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var tokens []string
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":listAssets") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		token := r.URL.Query().Get("pageToken")
+
+		mu.Lock()
+		tokens = append(tokens, token)
+		page := len(tokens) - 1
+		mu.Unlock()
+
+		if page >= len(pages) {
+			t.Errorf("unexpected page request with token %q", token)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[page]))
+	})
+
+	return service, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, tokens...)
+	}
+}
+
+var testAssetPages = []string{
+	`{"assets": [
+		{"name": "projects/p/assets/scenes/a", "type": "IMAGE", "startTime": "2022-12-31T00:00:00Z", "endTime": "2023-01-01T00:00:00Z"},
+		{"name": "projects/p/assets/scenes/b", "type": "IMAGE", "startTime": "2023-03-01T00:00:00Z", "endTime": "2023-03-02T00:00:00Z"},
+		{"name": "projects/p/assets/scenes/sub", "type": "FOLDER"}
+	], "nextPageToken": "1"}`,
+	`{"assets": [
+		{"name": "projects/p/assets/scenes/c", "type": "IMAGE", "startTime": "2023-12-31T12:00:00Z"},
+		{"name": "projects/p/assets/scenes/d", "type": "TABLE", "startTime": "2023-06-01T00:00:00Z"}
+	], "nextPageToken": "2"}`,
+	`{"assets": [
+		{"name": "projects/p/assets/scenes/e", "type": "IMAGE", "startTime": "2024-01-01T00:00:00Z", "endTime": "2024-01-02T00:00:00Z"}
+	]}`,
+}
+
+func TestListAssetsPagination(t *testing.T) {
+	service, tokens := newAssetsTestService(t, testAssetPages...)
+
+	assets, err := ListAssets(context.Background(), service, "projects/p/assets/scenes")
+	if err != nil {
+		t.Fatalf("ListAssets failed: %v", err)
+	}
+
+	if len(assets) != 6 {
+		t.Errorf("len(assets) = %d, want 6", len(assets))
+	}
+	if got := strings.Join(tokens(), ","); got != ",1,2" {
+		t.Errorf("page tokens = %q, want \",1,2\"", got)
+	}
+}
+
+func TestListAssetsFilters(t *testing.T) {
+	service, _ := newAssetsTestService(t, testAssetPages...)
+
+	assets, err := ListAssets(context.Background(), service, "projects/p/assets/scenes",
+		AssetType("IMAGE"),
+		AssetDateRange("2023-01-01", "2024-01-01"))
+	if err != nil {
+		t.Fatalf("ListAssets failed: %v", err)
+	}
+
+	var names []string
+	for _, asset := range assets {
+		names = append(names, asset.Name[len("projects/p/assets/scenes/"):])
+	}
+	if got := strings.Join(names, ","); got != "b,c" {
+		t.Errorf("assets = %q, want \"b,c\"", got)
+	}
+}
+
+func TestListAssetsOnAsset(t *testing.T) {
+	service, tokens := newAssetsTestService(t, testAssetPages...)
+
+	var names []string
+	stop := errors.New("stop")
+	assets, err := ListAssets(context.Background(), service, "projects/p/assets/scenes",
+		AssetType("IMAGE"),
+		OnAsset(func(asset *apiv1.EarthEngineAsset) error {
+			names = append(names, asset.Name)
+			if len(names) == 3 {
+				return stop
+			}
+			return nil
+		}))
+
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want stop", err)
+	}
+	if assets != nil {
+		t.Errorf("assets = %v, want nil", assets)
+	}
+	if len(names) != 3 {
+		t.Errorf("callback called %d times, want 3", len(names))
+	}
+	if len(tokens()) != 2 {
+		t.Errorf("requested %d pages, want 2", len(tokens()))
+	}
+}
+
+func TestListAssetsErrors(t *testing.T) {
+	if _, err := ListAssets(context.Background(), nil, "projects/p/assets"); err == nil {
+		t.Error("Expected error for nil service")
+	}
+
+	service, _ := newAssetsTestService(t)
+	for _, tt := range []struct{ start, end string }{
+		{"2023-13-01", "2024-01-01"},
+		{"2023-01-01", "soon"},
+		{"2024-01-01", "2023-01-01"},
+	} {
+		if _, err := ListAssets(context.Background(), service, "projects/p/assets", AssetDateRange(tt.start, tt.end)); err == nil {
+			t.Errorf("Expected error for date range %s to %s", tt.start, tt.end)
+		}
+	}
+}
+
+func TestDescribeAsset(t *testing.T) {
+	var path string
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"name": "projects/earthengine-public/assets/USGS/SRTMGL1_003",
+			"type": "IMAGE",
+			"title": "SRTM Digital Elevation Data",
+			"startTime": "2000-02-11T00:00:00Z",
+			"endTime": "2000-02-22T00:00:00Z",
+			"sizeBytes": "132799215616",
+			"bands": [{
+				"id": "elevation",
+				"dataType": {"precision": "INT", "range": {"min": -32768, "max": 32767}},
+				"grid": {
+					"crsCode": "EPSG:4326",
+					"affineTransform": {"scaleX": 0.000277, "translateX": -180, "scaleY": -0.000277, "translateY": 60},
+					"dimensions": {"width": "1296001", "height": "417601"}
+				}
+			}],
+			"geometry": {"type": "Polygon", "coordinates": [[[-180, -56], [180, -56], [180, 60], [-180, 60], [-180, -56]]]}
+		}`))
+	})
+
+	info, err := DescribeAsset(context.Background(), service, "USGS/SRTMGL1_003")
+	if err != nil {
+		t.Fatalf("DescribeAsset failed: %v", err)
+	}
+
+	if !strings.HasSuffix(path, "projects/earthengine-public/assets/USGS/SRTMGL1_003") {
+		t.Errorf("path = %s, want public catalog asset", path)
+	}
+	if info.Type != "IMAGE" || info.SizeBytes != 132799215616 {
+		t.Errorf("Type, SizeBytes = %s, %d", info.Type, info.SizeBytes)
+	}
+	if info.StartTime.Year() != 2000 || info.EndTime.Day() != 22 {
+		t.Errorf("temporal extent = %v to %v", info.StartTime, info.EndTime)
+	}
+
+	if len(info.Bands) != 1 {
+		t.Fatalf("len(Bands) = %d, want 1", len(info.Bands))
+	}
+	want := BandInfo{ID: "elevation", DataType: "int16", CRS: "EPSG:4326", Scale: 0.000277, Width: 1296001, Height: 417601}
+	if info.Bands[0] != want {
+		t.Errorf("Bands[0] = %+v, want %+v", info.Bands[0], want)
+	}
+
+	wantBounds := Bounds{MinLon: -180, MinLat: -56, MaxLon: 180, MaxLat: 60}
+	if info.Bounds == nil || *info.Bounds != wantBounds {
+		t.Errorf("Bounds = %v, want %v", info.Bounds, wantBounds)
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	tests := map[string]string{
+		"projects/my-project/assets/dem": "projects/my-project/assets/dem",
+		"users/someone/dem":              "projects/earthengine-legacy/assets/users/someone/dem",
+		"COPERNICUS/S2_SR_HARMONIZED":    "projects/earthengine-public/assets/COPERNICUS/S2_SR_HARMONIZED",
+	}
+	for id, want := range tests {
+		if got := assetName(id); got != want {
+			t.Errorf("assetName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestPixelDataType(t *testing.T) {
+	tests := []struct {
+		dataType *apiv1.PixelDataType
+		want     string
+	}{
+		{nil, ""},
+		{&apiv1.PixelDataType{Precision: "FLOAT"}, "float32"},
+		{&apiv1.PixelDataType{Precision: "DOUBLE"}, "float64"},
+		{&apiv1.PixelDataType{Precision: "INT", Range: &apiv1.ValueRange{Min: 0, Max: 255}}, "uint8"},
+		{&apiv1.PixelDataType{Precision: "INT", Range: &apiv1.ValueRange{Min: 0, Max: 65535}}, "uint16"},
+		{&apiv1.PixelDataType{Precision: "INT", Range: &apiv1.ValueRange{Min: -1, Max: 40000}}, "int32"},
+		{&apiv1.PixelDataType{Precision: "INT"}, "int64"},
+	}
+
+	for _, tt := range tests {
+		if got := pixelDataType(tt.dataType); got != tt.want {
+			t.Errorf("pixelDataType(%+v) = %q, want %q", tt.dataType, got, tt.want)
+		}
+	}
+}
+
+// newAssetTreeTestService returns a service backed by an in-memory asset tree
+// mapping names to types, and a function returning the names deleted so far.
+// Deleting a non-empty container or an asset in denied fails.
+func newAssetTreeTestService(t *testing.T, tree map[string]string, denied ...string) (*apiv1.Service, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var deleted []string
+	children := func(parent string) []string {
+		var names []string
+		for name := range tree {
+			if rest := strings.TrimPrefix(name, parent+"/"); rest != name && !strings.Contains(rest, "/") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		w.Header().Set("Content-Type", "application/json")
+		if parent := strings.TrimSuffix(name, ":listAssets"); parent != name {
+			var assets []map[string]string
+			for _, child := range children(parent) {
+				assets = append(assets, map[string]string{"name": child, "type": tree[child]})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"assets": assets})
+			return
+		}
+
+		assetType, ok := tree[name]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`))
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]string{"name": name, "type": assetType})
+		case r.Method == "DELETE" && len(children(name)) > 0:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"code": 400, "message": "folder is not empty", "status": "FAILED_PRECONDITION"}}`))
+		case r.Method == "DELETE" && containsString(denied, name):
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"code": 403, "message": "permission denied", "status": "PERMISSION_DENIED"}}`))
+		case r.Method == "DELETE":
+			delete(tree, name)
+			deleted = append(deleted, name)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	return service, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, deleted...)
+	}
+}
+
+func testAssetTree() map[string]string {
+	return map[string]string{
+		"projects/p/assets/exports":              "FOLDER",
+		"projects/p/assets/exports/tmp_a":        "IMAGE",
+		"projects/p/assets/exports/tmp_b":        "IMAGE",
+		"projects/p/assets/exports/tmp_run":      "IMAGE_COLLECTION",
+		"projects/p/assets/exports/tmp_run/img1": "IMAGE",
+		"projects/p/assets/exports/tmp_run/img2": "IMAGE",
+		"projects/p/assets/exports/keep":         "TABLE",
+	}
+}
+
+func resultNames(results []Result) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i], _ = r.Value.(string)
+	}
+	return names
+}
+
+func TestDeleteAssetsPartialFailure(t *testing.T) {
+	service, deleted := newAssetTreeTestService(t, testAssetTree(), "projects/p/assets/exports/tmp_b")
+
+	results, err := DeleteAssets(context.Background(), service, []string{
+		"projects/p/assets/exports/tmp_a",
+		"projects/p/assets/exports/tmp_b",
+		"projects/p/assets/exports/missing",
+	}, 2)
+	if err != nil {
+		t.Fatalf("DeleteAssets failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	failed := FilterFailed(results)
+	if len(failed) != 2 || failed[0].Value != "projects/p/assets/exports/tmp_b" || !errors.Is(failed[0].Error, apiv1.ErrPermissionDenied) {
+		t.Errorf("failed = %+v", failed)
+	}
+	if got := deleted(); len(got) != 1 || got[0] != "projects/p/assets/exports/tmp_a" {
+		t.Errorf("deleted = %v", got)
+	}
+}
+
+func TestDeleteAssetsRecursive(t *testing.T) {
+	ctx := context.Background()
+
+	// Without Recursive a non-empty collection is not followed into
+	service, deleted := newAssetTreeTestService(t, testAssetTree())
+	results, err := DeleteAssets(ctx, service, []string{"projects/p/assets/exports/tmp_run"}, 0)
+	if err != nil {
+		t.Fatalf("DeleteAssets failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Error == nil || len(deleted()) != 0 {
+		t.Errorf("results = %+v, deleted = %v, want one failure", results, deleted())
+	}
+
+	results, err = DeleteAssets(ctx, service, []string{"projects/p/assets/exports/tmp_run"}, 0, Recursive())
+	if err != nil {
+		t.Fatalf("DeleteAssets failed: %v", err)
+	}
+	if failed := FilterFailed(results); len(failed) != 0 {
+		t.Errorf("failed = %+v", failed)
+	}
+	names := resultNames(results)
+	if len(names) != 3 || names[2] != "projects/p/assets/exports/tmp_run" {
+		t.Errorf("results = %v, want the images before the collection", names)
+	}
+}
+
+func TestDeleteAssetsMatching(t *testing.T) {
+	ctx := context.Background()
+	service, deleted := newAssetTreeTestService(t, testAssetTree())
+
+	preview, err := DeleteAssetsMatching(ctx, service, "projects/p/assets/exports", "tmp_", DryRun(), Recursive())
+	if err != nil {
+		t.Fatalf("DeleteAssetsMatching failed: %v", err)
+	}
+	want := []string{
+		"projects/p/assets/exports/tmp_run/img1",
+		"projects/p/assets/exports/tmp_run/img2",
+		"projects/p/assets/exports/tmp_a",
+		"projects/p/assets/exports/tmp_b",
+		"projects/p/assets/exports/tmp_run",
+	}
+	if got := resultNames(preview); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("dry run = %v, want %v", got, want)
+	}
+	if len(deleted()) != 0 {
+		t.Errorf("dry run deleted %v", deleted())
+	}
+
+	results, err := DeleteAssetsMatching(ctx, service, "projects/p/assets/exports/", "tmp_", Recursive())
+	if err != nil {
+		t.Fatalf("DeleteAssetsMatching failed: %v", err)
+	}
+	if summary := Summarize(results); summary.Succeeded != 5 {
+		t.Errorf("summary = %+v, want 5 deleted", summary)
+	}
+	for _, name := range deleted() {
+		if strings.HasSuffix(name, "keep") {
+			t.Error("deleted an asset not matching the prefix")
+		}
+	}
+}
+
+func TestDeleteAssetsErrors(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newAssetTreeTestService(t, testAssetTree())
+
+	if _, err := DeleteAssets(ctx, nil, []string{"projects/p/assets/x"}, 1); err == nil {
+		t.Error("Expected error for nil service")
+	}
+	if _, err := DeleteAssets(ctx, service, []string{""}, 1); err == nil {
+		t.Error("Expected error for empty asset ID")
+	}
+	if _, err := DeleteAssetsMatching(ctx, service, "projects/p/assets/exports", ""); err == nil {
+		t.Error("Expected error for empty prefix")
+	}
+}
+
+func TestCopyAndMoveAsset(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if !strings.HasPrefix(r.URL.Path, "/projects/p/") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	if err := CopyAsset(ctx, service, "projects/p/assets/exports/tmp_a", "projects/p/assets/published/a"); err != nil {
+		t.Errorf("CopyAsset failed: %v", err)
+	}
+	if err := MoveAsset(ctx, service, "projects/p/assets/exports/tmp_b", "projects/p/assets/published/b"); err != nil {
+		t.Errorf("MoveAsset failed: %v", err)
+	}
+	if len(requests) != 2 || !strings.HasSuffix(requests[0], ":copy") || !strings.HasSuffix(requests[1], ":move") {
+		t.Errorf("requests = %v", requests)
+	}
+
+	err := CopyAsset(ctx, service, "users/someone/missing", "projects/p/assets/x")
+	if !errors.Is(err, apiv1.ErrAssetNotFound) || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("CopyAsset error = %v, want a not-found error", err)
+	}
+	if err := MoveAsset(ctx, service, "projects/p/assets/a", "projects/p/assets/a"); err == nil {
+		t.Error("Expected error for same source and destination")
+	}
+}
+
+func TestSetAssetACL(t *testing.T) {
+	var written apiv1.Policy
+	ctx := context.Background()
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":getIamPolicy"):
+			w.Write([]byte(`{"bindings": [
+				{"role": "roles/owner", "members": ["user:owner@example.com"]},
+				{"role": "roles/viewer", "members": ["user:old@example.com"]}
+			], "etag": "BwX1"}`))
+		case strings.HasSuffix(r.URL.Path, ":setIamPolicy"):
+			var req struct{ Policy apiv1.Policy }
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Policy
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	err := SetAssetACL(ctx, service, "projects/p/assets/published/a",
+		[]string{"alice@example.com", "group:lab@example.com", "alice@example.com"}, true)
+	if err != nil {
+		t.Fatalf("SetAssetACL failed: %v", err)
+	}
+
+	if written.Etag != "BwX1" || len(written.Bindings) != 2 {
+		t.Fatalf("written policy = %+v", written)
+	}
+	if owner := written.Bindings[0]; owner.Role != "roles/owner" || owner.Members[0] != "user:owner@example.com" {
+		t.Errorf("owner binding = %+v", owner)
+	}
+	want := "user:alice@example.com,group:lab@example.com,allUsers"
+	if viewer := written.Bindings[1]; viewer.Role != "roles/viewer" || strings.Join(viewer.Members, ",") != want {
+		t.Errorf("viewer binding = %+v, want members %s", viewer, want)
+	}
+
+	if err := SetAssetACL(ctx, service, "projects/p/assets/published/a", []string{"not-an-email"}, false); err == nil {
+		t.Error("Expected error for invalid reader")
+	}
+}
+
+func TestACLMember(t *testing.T) {
+	tests := []struct {
+		reader string
+		want   string
+	}{
+		{"alice@example.com", "user:alice@example.com"},
+		{"group:lab@example.org", "group:lab@example.org"},
+		{"serviceAccount:bot@proj.iam.gserviceaccount.com", "serviceAccount:bot@proj.iam.gserviceaccount.com"},
+		{"domain:example.com", "domain:example.com"},
+		{"alice", ""},
+		{"Alice <alice@example.com>", ""},
+		{"user:alice@localhost", ""},
+		{"domain:example", ""},
+		{"allUsers", ""},
+		{"owner:alice@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := aclMember(tt.reader)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("aclMember(%q) = %q, want error", tt.reader, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("aclMember(%q) = %q, %v, want %q", tt.reader, got, err, tt.want)
+		}
+	}
+}
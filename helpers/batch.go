@@ -2,11 +2,13 @@ package helpers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 // Batch represents a batch of Earth Engine queries that can be executed in parallel.
@@ -19,16 +21,19 @@ import (
 //	}
 //	results, err := batch.Execute(ctx)
 type Batch struct {
-	client      *earthengine.Client
-	queries     []Query
-	concurrency int
+	client       *earthengine.Client
+	queries      []Query
+	concurrency  int
+	queryTimeout time.Duration
+	workloadTag  string
+	logger       earthengine.Logger
 }
 
 // Result represents the result of a single query in a batch.
 type Result struct {
-	Value interface{} // The result value (type depends on query)
-	Error error       // Any error that occurred
-	Index int         // Index of the query in the batch
+	Value interface{} `json:"value"` // The result value (type depends on query)
+	Error error       `json:"error"` // Any error that occurred
+	Index int         `json:"index"` // Index of the query in the batch
 }
 
 // BatchOption configures a Batch.
@@ -41,6 +46,63 @@ func WithConcurrency(n int) BatchOption {
 	}
 }
 
+// WithQueryTimeout sets a deadline for each individual query.
+//
+// A query that exceeds the timeout fails with a deadline error in its Result
+// and frees its concurrency slot, while the rest of the batch continues.
+//
+// Example:
+//
+//	batch := helpers.NewBatch(client, 10, helpers.WithQueryTimeout(30*time.Second))
+func WithQueryTimeout(d time.Duration) BatchOption {
+	return func(b *Batch) {
+		b.queryTimeout = d
+	}
+}
+
+// WithWorkloadTag tags every query in the batch with a workload tag for quota
+// and billing attribution, overriding the client's tag. Executing the batch
+// fails if the tag is invalid.
+//
+// Example:
+//
+//	batch := helpers.NewBatch(client, 10, helpers.WithWorkloadTag("county-stats"))
+func WithWorkloadTag(tag string) BatchOption {
+	return func(b *Batch) {
+		b.workloadTag = tag
+	}
+}
+
+// WithLogger logs the start and end of each query, with its index, type, and
+// duration, and each retry by ExecuteWithRetry. By default a batch logs to
+// its client's logger (see earthengine.WithLogger).
+//
+// Example:
+//
+//	logger := earthengine.NewSlogLogger(slog.Default())
+//	batch := helpers.NewBatch(client, 10, helpers.WithLogger(logger))
+func WithLogger(logger earthengine.Logger) BatchOption {
+	return func(b *Batch) {
+		b.logger = logger
+	}
+}
+
+// Batch query event names passed to the logger.
+const (
+	// EventQueryStart is logged when a query starts, with its "index" and
+	// "query" type.
+	EventQueryStart = "query start"
+
+	// EventQueryEnd is logged when a query finishes, with its "index",
+	// "query" type, "duration", and "error" if it failed.
+	EventQueryEnd = "query end"
+
+	// EventQueryRetry is logged before ExecuteWithRetry retries a query, with
+	// its "index", "query" type, the "attempt" that failed, the "backoff"
+	// before the next one, and the "error".
+	EventQueryRetry = "query retry"
+)
+
 // NewBatch creates a new batch executor.
 //
 // The concurrency parameter controls how many queries run in parallel.
@@ -49,15 +111,19 @@ func WithConcurrency(n int) BatchOption {
 // Example:
 //
 //	batch := helpers.NewBatch(client, 10)
-func NewBatch(client *earthengine.Client, concurrency int) *Batch {
-	if concurrency <= 0 {
-		concurrency = 10 // Default concurrency
-	}
-	return &Batch{
+func NewBatch(client *earthengine.Client, concurrency int, opts ...BatchOption) *Batch {
+	b := &Batch{
 		client:      client,
 		queries:     make([]Query, 0),
 		concurrency: concurrency,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.concurrency <= 0 {
+		b.concurrency = 10 // Default concurrency
+	}
+	return b
 }
 
 // Add adds a query to the batch.
@@ -96,6 +162,10 @@ func (b *Batch) Size() int {
 //	    fmt.Printf("Query %d result: %v\n", i, result.Value)
 //	}
 func (b *Batch) Execute(ctx context.Context) ([]Result, error) {
+	if err := b.validateWorkloadTag(); err != nil {
+		return nil, err
+	}
+
 	if len(b.queries) == 0 {
 		return []Result{}, nil
 	}
@@ -127,7 +197,7 @@ func (b *Batch) Execute(ctx context.Context) ([]Result, error) {
 			}
 
 			// Execute query
-			value, err := q.Execute(ctx, b.client)
+			value, err := b.executeQuery(ctx, index, q)
 			results[index] = Result{
 				Value: value,
 				Error: err,
@@ -147,6 +217,162 @@ func (b *Batch) Execute(ctx context.Context) ([]Result, error) {
 	return results, nil
 }
 
+// Stream runs all queries with controlled concurrency and delivers each Result
+// to fn as soon as its query completes.
+//
+// Results arrive in completion order, not input order; use Result.Index to
+// match them to queries. Calls to fn are serialized on the calling goroutine,
+// so fn does not need to be safe for concurrent use. A query keeps its
+// concurrency slot until its result has been delivered, so a slow callback
+// applies backpressure and at most the concurrency limit of results are held
+// in memory at once.
+//
+// If ctx is canceled, queries that have not started are reported with the
+// context error and Stream returns ctx.Err() once every result is delivered.
+//
+// Example:
+//
+//	enc := json.NewEncoder(file)
+//	err := batch.Stream(ctx, func(result helpers.Result) {
+//	    if result.Error != nil {
+//	        log.Printf("Query %d failed: %v", result.Index, result.Error)
+//	        return
+//	    }
+//	    enc.Encode(result)
+//	})
+func (b *Batch) Stream(ctx context.Context, fn func(Result)) error {
+	if err := b.validateWorkloadTag(); err != nil {
+		return err
+	}
+
+	if len(b.queries) == 0 {
+		return nil
+	}
+
+	results := make(chan Result)
+
+	// Create a semaphore to limit concurrency
+	sem := make(chan struct{}, b.concurrency)
+
+	// Create a wait group to close the results channel once all queries finish
+	var wg sync.WaitGroup
+
+	for i, query := range b.queries {
+		wg.Add(1)
+		go func(index int, q Query) {
+			defer wg.Done()
+
+			// Acquire semaphore
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }() // Release semaphore after delivery
+			case <-ctx.Done():
+				results <- Result{
+					Index: index,
+					Error: ctx.Err(),
+				}
+				return
+			}
+
+			// Execute query
+			value, err := b.executeQuery(ctx, index, q)
+			results <- Result{
+				Value: value,
+				Error: err,
+				Index: index,
+			}
+		}(i, query)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if fn != nil {
+			fn(result)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// ExecuteWithTimeout runs all queries like Execute, giving each query at most
+// timeout to complete.
+//
+// It overrides any WithQueryTimeout setting for this call only.
+//
+// Example:
+//
+//	results, err := batch.ExecuteWithTimeout(ctx, 10*time.Second)
+//	for _, result := range helpers.FilterFailed(results) {
+//	    fmt.Printf("Query %d failed: %v\n", result.Index, result.Error)
+//	}
+func (b *Batch) ExecuteWithTimeout(ctx context.Context, timeout time.Duration) ([]Result, error) {
+	timed := *b
+	timed.queryTimeout = timeout
+	return timed.Execute(ctx)
+}
+
+// validateWorkloadTag checks the batch's workload tag, if set.
+func (b *Batch) validateWorkloadTag() error {
+	if b.workloadTag == "" {
+		return nil
+	}
+	return apiv1.ValidateWorkloadTag(b.workloadTag)
+}
+
+// getLogger returns the batch's logger, falling back to the client's.
+func (b *Batch) getLogger() earthengine.Logger {
+	if b.logger != nil {
+		return b.logger
+	}
+	if b.client != nil {
+		return b.client.Logger()
+	}
+	return earthengine.NopLogger()
+}
+
+// executeQuery runs a single query, applying the workload tag and per-query
+// timeout if set, and logs its start and end.
+func (b *Batch) executeQuery(ctx context.Context, index int, q Query) (interface{}, error) {
+	logger := b.getLogger()
+	queryType := fmt.Sprintf("%T", q)
+	logger.Log(ctx, EventQueryStart, "index", index, "query", queryType)
+	start := time.Now()
+
+	value, err := b.runQuery(ctx, q)
+
+	attrs := []interface{}{"index", index, "query", queryType, "duration", time.Since(start)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	logger.Log(ctx, EventQueryEnd, attrs...)
+
+	return value, err
+}
+
+// runQuery runs a single query, applying the workload tag and per-query
+// timeout if set.
+func (b *Batch) runQuery(ctx context.Context, q Query) (interface{}, error) {
+	if b.workloadTag != "" {
+		ctx = earthengine.ContextWithWorkloadTag(ctx, b.workloadTag)
+	}
+	if b.queryTimeout <= 0 {
+		return q.Execute(ctx, b.client)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, b.queryTimeout)
+	defer cancel()
+
+	value, err := q.Execute(queryCtx, b.client)
+	if err != nil && ctx.Err() == nil && queryCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("query timed out after %s: %w", b.queryTimeout, err)
+	}
+	return value, err
+}
+
 // ExecuteWithProgress runs all queries and reports progress via a callback.
 //
 // The progress callback is called periodically with the number of completed queries
@@ -158,6 +384,10 @@ func (b *Batch) Execute(ctx context.Context) ([]Result, error) {
 //	    fmt.Printf("Progress: %d/%d (%.1f%%)\n", completed, total, float64(completed)*100/float64(total))
 //	})
 func (b *Batch) ExecuteWithProgress(ctx context.Context, progressFn func(completed, total int)) ([]Result, error) {
+	if err := b.validateWorkloadTag(); err != nil {
+		return nil, err
+	}
+
 	if len(b.queries) == 0 {
 		return []Result{}, nil
 	}
@@ -196,7 +426,7 @@ func (b *Batch) ExecuteWithProgress(ctx context.Context, progressFn func(complet
 			}
 
 			// Execute query
-			value, err := q.Execute(ctx, b.client)
+			value, err := b.executeQuery(ctx, index, q)
 			results[index] = Result{
 				Value: value,
 				Error: err,
@@ -229,19 +459,28 @@ func (b *Batch) ExecuteWithProgress(ctx context.Context, progressFn func(complet
 // ExecuteWithRetry executes queries with automatic retry on failure.
 //
 // Failed queries will be retried up to maxRetries times with exponential backoff.
+// Errors that cannot succeed on retry (HTTP 400, 401, 403 and 404) fail immediately.
+//
+// When Earth Engine reports rate limiting (HTTP 429 or RESOURCE_EXHAUSTED),
+// the whole batch cools down for the backoff period and the effective
+// concurrency is halved, then recovers gradually as queries succeed.
 //
 // Example:
 //
 //	results, err := batch.ExecuteWithRetry(ctx, 3, 100*time.Millisecond)
 func (b *Batch) ExecuteWithRetry(ctx context.Context, maxRetries int, initialBackoff time.Duration) ([]Result, error) {
+	if err := b.validateWorkloadTag(); err != nil {
+		return nil, err
+	}
+
 	if len(b.queries) == 0 {
 		return []Result{}, nil
 	}
 
 	results := make([]Result, len(b.queries))
 
-	// Create a semaphore to limit concurrency
-	sem := make(chan struct{}, b.concurrency)
+	// Limit concurrency, adapting to rate limiting
+	limiter := newAdaptiveLimiter(b.concurrency)
 
 	// Create a wait group to wait for all queries
 	var wg sync.WaitGroup
@@ -252,31 +491,42 @@ func (b *Batch) ExecuteWithRetry(ctx context.Context, maxRetries int, initialBac
 		go func(index int, q Query) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }() // Release semaphore
-			case <-ctx.Done():
-				results[index] = Result{
-					Index: index,
-					Error: ctx.Err(),
-				}
-				return
-			}
-
 			// Try executing with retry
 			var value interface{}
 			var err error
 			backoff := initialBackoff
 
 			for attempt := 0; attempt <= maxRetries; attempt++ {
-				value, err = q.Execute(ctx, b.client)
+				if acquireErr := limiter.acquire(ctx); acquireErr != nil {
+					results[index] = Result{
+						Index: index,
+						Error: acquireErr,
+					}
+					return
+				}
+
+				value, err = b.executeQuery(ctx, index, q)
 				if err == nil {
+					limiter.succeed()
+					limiter.release()
 					break // Success
 				}
+				limiter.release()
+
+				if !isRetryable(err) {
+					break
+				}
 
-				// If this wasn't the last attempt, sleep before retrying
+				// If this wasn't the last attempt, wait before retrying
 				if attempt < maxRetries {
+					b.getLogger().Log(ctx, EventQueryRetry, "index", index, "query", fmt.Sprintf("%T", q),
+						"attempt", attempt+1, "backoff", backoff, "error", err)
+
+					if isRateLimited(err) {
+						// Slow down the whole batch, not just this query
+						limiter.throttle(backoff)
+					}
+
 					select {
 					case <-time.After(backoff):
 						backoff *= 2 // Exponential backoff
@@ -309,12 +559,190 @@ func (b *Batch) ExecuteWithRetry(ctx context.Context, maxRetries int, initialBac
 	return results, nil
 }
 
+// isRateLimited reports whether err indicates Earth Engine rate limiting,
+// from either the client or the apiv1 service.
+func isRateLimited(err error) bool {
+	return errors.Is(err, apiv1.ErrQuotaExceeded)
+}
+
+// isRetryable reports whether a failed query may succeed if retried.
+// Cancellation, invalid expressions, missing assets, and permission errors
+// are terminal; API errors otherwise retry only on a transient status.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, apiv1.ErrQuotaExceeded) {
+		return true
+	}
+	if errors.Is(err, apiv1.ErrInvalidExpression) || errors.Is(err, apiv1.ErrAssetNotFound) ||
+		errors.Is(err, apiv1.ErrPermissionDenied) {
+		return false
+	}
+
+	var apiErr *earthengine.APIError
+	if errors.As(err, &apiErr) {
+		return apiv1.IsRetryableStatus(apiErr.StatusCode)
+	}
+	var serviceErr *apiv1.APIError
+	if errors.As(err, &serviceErr) {
+		return apiv1.IsRetryable(serviceErr)
+	}
+	return true
+}
+
+// adaptiveLimiter is a concurrency limiter whose limit shrinks when the API
+// reports rate limiting and recovers gradually as requests succeed.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	max      int           // Configured concurrency
+	limit    int           // Current effective concurrency
+	active   int           // Slots in use
+	streak   int           // Successes since the limit last changed
+	cooldown time.Time     // No new slots are granted before this time
+	changed  chan struct{} // Closed whenever a waiter may be able to proceed
+}
+
+func newAdaptiveLimiter(concurrency int) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		max:     concurrency,
+		limit:   concurrency,
+		changed: make(chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is available or ctx is done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.cooldown)
+		if wait <= 0 && l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+
+		select {
+		case <-changed:
+		case <-expired:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// release frees a slot.
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.notify()
+}
+
+// throttle halves the effective concurrency and pauses new work for d.
+func (l *adaptiveLimiter) throttle(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit /= 2
+	if l.limit < 1 {
+		l.limit = 1
+	}
+	l.streak = 0
+	if until := time.Now().Add(d); until.After(l.cooldown) {
+		l.cooldown = until
+	}
+	l.notify()
+}
+
+// succeed records a success, raising the limit by one after a full round of
+// successes at the current limit.
+func (l *adaptiveLimiter) succeed() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit >= l.max {
+		return
+	}
+	l.streak++
+	if l.streak >= l.limit {
+		l.limit++
+		l.streak = 0
+		l.notify()
+	}
+}
+
+// notify wakes all waiters. Must be called with l.mu held.
+func (l *adaptiveLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// As returns the value of a result as type T.
+//
+// It returns the query's error if the query failed, or a descriptive error
+// if the value has a different type, instead of panicking like a bare type
+// assertion would.
+//
+// Example:
+//
+//	for _, result := range results {
+//	    coverage, err := helpers.As[float64](result)
+//	    if err != nil {
+//	        log.Print(err)
+//	        continue
+//	    }
+//	    fmt.Printf("%.2f%%\n", coverage)
+//	}
+func As[T any](r Result) (T, error) {
+	var zero T
+	if r.Error != nil {
+		return zero, fmt.Errorf("query %d failed: %w", r.Index, r.Error)
+	}
+
+	value, ok := r.Value.(T)
+	if !ok {
+		return zero, fmt.Errorf("query %d returned %T, want %T", r.Index, r.Value, zero)
+	}
+	return value, nil
+}
+
+// AsFloat returns the value of a result as a float64. See As.
+func AsFloat(r Result) (float64, error) {
+	return As[float64](r)
+}
+
+// AsString returns the value of a result as a string. See As.
+func AsString(r Result) (string, error) {
+	return As[string](r)
+}
+
+// AsMap returns the value of a result as a map. See As.
+func AsMap(r Result) (map[string]interface{}, error) {
+	return As[map[string]interface{}](r)
+}
+
 // Summary returns statistics about the batch execution results.
 type Summary struct {
-	Total     int     // Total number of queries
-	Succeeded int     // Number of successful queries
-	Failed    int     // Number of failed queries
-	SuccessRate float64 // Success rate (0-1)
+	Total       int     `json:"total"`        // Total number of queries
+	Succeeded   int     `json:"succeeded"`    // Number of successful queries
+	Failed      int     `json:"failed"`       // Number of failed queries
+	SuccessRate float64 `json:"success_rate"` // Success rate (0-1)
 }
 
 // Summarize returns statistics about batch execution results.
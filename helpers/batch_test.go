@@ -3,10 +3,15 @@ package helpers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 // mockQuery is a query that returns a predefined value or error
@@ -180,6 +185,278 @@ func TestBatchExecuteCancellation(t *testing.T) {
 	}
 }
 
+func TestBatchExecuteWithQueryTimeout(t *testing.T) {
+	batch := NewBatch(nil, 1, WithQueryTimeout(50*time.Millisecond))
+	batch.Add(&mockQuery{value: 1})
+	batch.Add(&mockQuery{value: 2, delay: time.Second})
+	batch.Add(&mockQuery{value: 3, delay: 10 * time.Millisecond})
+
+	start := time.Now()
+	results, err := batch.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Execute() took %v, slow query should have been abandoned", elapsed)
+	}
+
+	if !errors.Is(results[1].Error, context.DeadlineExceeded) {
+		t.Errorf("results[1].Error = %v, want context.DeadlineExceeded", results[1].Error)
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Error != nil {
+			t.Errorf("results[%d].Error = %v, want nil", i, results[i].Error)
+		}
+		if results[i].Value != i+1 {
+			t.Errorf("results[%d].Value = %v, want %d", i, results[i].Value, i+1)
+		}
+	}
+}
+
+func TestBatchExecuteWithTimeout(t *testing.T) {
+	batch := NewBatch(nil, 2)
+	batch.Add(&mockQuery{value: 1, delay: time.Second})
+	batch.Add(&mockQuery{value: 2})
+
+	results, err := batch.ExecuteWithTimeout(context.Background(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ExecuteWithTimeout() error = %v, want nil", err)
+	}
+
+	if !errors.Is(results[0].Error, context.DeadlineExceeded) {
+		t.Errorf("results[0].Error = %v, want context.DeadlineExceeded", results[0].Error)
+	}
+	if results[1].Error != nil {
+		t.Errorf("results[1].Error = %v, want nil", results[1].Error)
+	}
+
+	// The timeout applies to this call only
+	if batch.queryTimeout != 0 {
+		t.Errorf("queryTimeout = %v, want 0", batch.queryTimeout)
+	}
+}
+
+// constantQuery computes a constant expression with the client.
+type constantQuery struct{}
+
+func (constantQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	expr := earthengine.NewExpression()
+	expr.SetResult(expr.AddConstant(1))
+	return client.ComputeValue(ctx, expr)
+}
+
+func TestBatchWorkloadTag(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": 1}`)
+
+	batch := NewBatch(client, 2, WithWorkloadTag("county-stats"))
+	batch.Add(constantQuery{})
+
+	results, err := batch.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if results[0].Error != nil {
+		t.Fatalf("results[0].Error = %v", results[0].Error)
+	}
+	if !strings.Contains(lastRequest(), `"workloadTag":"county-stats"`) {
+		t.Errorf("request has no workload tag: %s", lastRequest())
+	}
+
+	invalid := NewBatch(client, 2, WithWorkloadTag("County Stats"))
+	invalid.Add(constantQuery{})
+	if _, err := invalid.Execute(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid workload tag") {
+		t.Errorf("Execute() error = %v, want invalid workload tag", err)
+	}
+}
+
+func TestBatchStream(t *testing.T) {
+	batch := NewBatch(nil, 3)
+	batch.Add(&mockQuery{value: 0, delay: 150 * time.Millisecond})
+	batch.Add(&mockQuery{value: 1, delay: 10 * time.Millisecond})
+	batch.Add(&mockQuery{err: errors.New("query failed")})
+
+	// No locking: Stream serializes callback invocations
+	var order []int
+	seen := make(map[int]bool)
+	err := batch.Stream(context.Background(), func(result Result) {
+		order = append(order, result.Index)
+		seen[result.Index] = true
+		if result.Index == 2 && result.Error == nil {
+			t.Error("results[2].Error = nil, want error")
+		}
+		if result.Index < 2 && result.Value != result.Index {
+			t.Errorf("results[%d].Value = %v, want %d", result.Index, result.Value, result.Index)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Stream() error = %v, want nil", err)
+	}
+
+	if len(seen) != 3 {
+		t.Fatalf("Stream delivered %d distinct results, want 3", len(seen))
+	}
+	// The slow first query should be delivered last
+	if order[len(order)-1] != 0 {
+		t.Errorf("delivery order = %v, want completion order with query 0 last", order)
+	}
+}
+
+func TestBatchStreamCancellation(t *testing.T) {
+	batch := NewBatch(nil, 1)
+	batch.Add(&mockQuery{value: 1, delay: 100 * time.Millisecond})
+	batch.Add(&mockQuery{value: 2, delay: 100 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	delivered := 0
+	err := batch.Stream(ctx, func(result Result) {
+		delivered++
+	})
+	if err != context.Canceled {
+		t.Errorf("Stream() error = %v, want context.Canceled", err)
+	}
+	if delivered != 2 {
+		t.Errorf("delivered %d results, want 2", delivered)
+	}
+}
+
+// flakyQuery fails with err for its first failures calls, then succeeds.
+type flakyQuery struct {
+	mu       sync.Mutex
+	calls    int
+	failures int
+	err      error
+	value    interface{}
+}
+
+func (f *flakyQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return f.value, nil
+}
+
+func TestBatchExecuteWithRetryRateLimited(t *testing.T) {
+	rateLimited := &earthengine.APIError{StatusCode: http.StatusTooManyRequests, Status: "RESOURCE_EXHAUSTED"}
+	flaky := &flakyQuery{failures: 2, err: rateLimited, value: 42}
+
+	batch := NewBatch(nil, 4)
+	batch.Add(flaky)
+	batch.Add(&mockQuery{value: 1})
+
+	results, err := batch.ExecuteWithRetry(context.Background(), 3, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ExecuteWithRetry() error = %v, want nil", err)
+	}
+
+	if results[0].Error != nil || results[0].Value != 42 {
+		t.Errorf("results[0] = %v, %v, want 42, nil", results[0].Value, results[0].Error)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("rate-limited query called %d times, want 3", flaky.calls)
+	}
+	if results[1].Error != nil {
+		t.Errorf("results[1].Error = %v, want nil", results[1].Error)
+	}
+}
+
+func TestBatchExecuteWithRetryNonRetryable(t *testing.T) {
+	notFound := &flakyQuery{failures: 10, err: &earthengine.APIError{StatusCode: http.StatusNotFound}}
+
+	batch := NewBatch(nil, 1)
+	batch.Add(notFound)
+
+	results, err := batch.ExecuteWithRetry(context.Background(), 3, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ExecuteWithRetry() error = %v, want nil", err)
+	}
+
+	var apiErr *earthengine.APIError
+	if !errors.As(results[0].Error, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("results[0].Error = %v, want 404 APIError", results[0].Error)
+	}
+	if notFound.calls != 1 {
+		t.Errorf("non-retryable query called %d times, want 1", notFound.calls)
+	}
+}
+
+func TestBatchErrorClassification(t *testing.T) {
+	serviceErr := func(code int, status string) error {
+		apiErr := &apiv1.APIError{}
+		apiErr.ErrorInfo.Code = code
+		apiErr.ErrorInfo.Status = status
+		return fmt.Errorf("compute value: %w", apiErr)
+	}
+
+	tests := []struct {
+		name        string
+		err         error
+		rateLimited bool
+		retryable   bool
+	}{
+		{"client quota", &earthengine.APIError{StatusCode: http.StatusTooManyRequests}, true, true},
+		{"service quota", serviceErr(http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"), true, true},
+		{"service unavailable", serviceErr(http.StatusServiceUnavailable, "UNAVAILABLE"), false, true},
+		{"client server error", &earthengine.APIError{StatusCode: http.StatusInternalServerError}, false, true},
+		{"service permission", serviceErr(http.StatusForbidden, "PERMISSION_DENIED"), false, false},
+		{"client not found", &earthengine.APIError{StatusCode: http.StatusNotFound}, false, false},
+		{"client unauthorized", &earthengine.APIError{StatusCode: http.StatusUnauthorized}, false, false},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), false, false},
+		{"deadline", context.DeadlineExceeded, false, false},
+		{"quota message only", errors.New("RESOURCE_EXHAUSTED"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRateLimited(tt.err); got != tt.rateLimited {
+				t.Errorf("isRateLimited(%v) = %v, want %v", tt.err, got, tt.rateLimited)
+			}
+			if got := isRetryable(tt.err); got != tt.retryable {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	limiter := newAdaptiveLimiter(8)
+
+	limiter.throttle(20 * time.Millisecond)
+	if limiter.limit != 4 {
+		t.Errorf("limit after throttle = %d, want 4", limiter.limit)
+	}
+
+	// New slots wait for the cooldown
+	start := time.Now()
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("acquire() returned after %v, want to wait for cooldown", elapsed)
+	}
+	limiter.release()
+
+	// Recovers by one after a full round of successes
+	for i := 0; i < 4; i++ {
+		limiter.succeed()
+	}
+	if limiter.limit != 5 {
+		t.Errorf("limit after recovery = %d, want 5", limiter.limit)
+	}
+
+	// Never drops below one
+	for i := 0; i < 10; i++ {
+		limiter.throttle(0)
+	}
+	if limiter.limit != 1 {
+		t.Errorf("limit = %d, want 1", limiter.limit)
+	}
+}
+
 func TestBatchExecuteWithProgressEmpty(t *testing.T) {
 	batch := NewBatch(nil, 10)
 	ctx := context.Background()
@@ -313,6 +590,54 @@ func TestFilterFailed(t *testing.T) {
 	}
 }
 
+func TestResultAs(t *testing.T) {
+	value, err := AsFloat(Result{Value: 42.5, Index: 0})
+	if err != nil || value != 42.5 {
+		t.Errorf("AsFloat() = %v, %v, want 42.5, nil", value, err)
+	}
+
+	str, err := AsString(Result{Value: "forest", Index: 1})
+	if err != nil || str != "forest" {
+		t.Errorf("AsString() = %q, %v, want forest, nil", str, err)
+	}
+
+	m, err := AsMap(Result{Value: map[string]interface{}{"mean": 1.0}, Index: 2})
+	if err != nil || m["mean"] != 1.0 {
+		t.Errorf("AsMap() = %v, %v", m, err)
+	}
+
+	count, err := As[int](Result{Value: 7, Index: 3})
+	if err != nil || count != 7 {
+		t.Errorf("As[int]() = %v, %v, want 7, nil", count, err)
+	}
+}
+
+func TestResultAsErrors(t *testing.T) {
+	// Wrong type reports the query index and both types
+	_, err := AsFloat(Result{Value: "not a number", Index: 4})
+	if err == nil {
+		t.Fatal("AsFloat() error = nil, want type error")
+	}
+	if !strings.Contains(err.Error(), "query 4") || !strings.Contains(err.Error(), "string") {
+		t.Errorf("AsFloat() error = %q, want query index and actual type", err)
+	}
+
+	// Query errors are wrapped
+	queryErr := errors.New("query failed")
+	_, err = AsFloat(Result{Error: queryErr, Index: 5})
+	if !errors.Is(err, queryErr) {
+		t.Errorf("AsFloat() error = %v, want wrapped query error", err)
+	}
+	if !strings.Contains(err.Error(), "query 5") {
+		t.Errorf("AsFloat() error = %q, want query index", err)
+	}
+
+	// Nil values are a type error, not a panic
+	if _, err := AsMap(Result{Index: 6}); err == nil {
+		t.Error("AsMap() error = nil for nil value")
+	}
+}
+
 func TestNewRateLimiter(t *testing.T) {
 	limiter := NewRateLimiter(10)
 	defer limiter.Close()
@@ -401,3 +726,98 @@ func ExampleSummarize() {
 	// fmt.Printf("Success rate: %.1f%% (%d/%d)\n",
 	//     summary.SuccessRate*100, summary.Succeeded, summary.Total)
 }
+
+// eventLogger records the names and attributes of logged events.
+type eventLogger struct {
+	mu     sync.Mutex
+	events []string
+	attrs  []map[string]interface{}
+}
+
+func (l *eventLogger) Log(ctx context.Context, event string, attrs ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m := make(map[string]interface{})
+	for i := 0; i+1 < len(attrs); i += 2 {
+		m[attrs[i].(string)] = attrs[i+1]
+	}
+	l.events = append(l.events, event)
+	l.attrs = append(l.attrs, m)
+}
+
+// count returns the number of events named event.
+func (l *eventLogger) count(event string) int {
+	n := 0
+	for _, e := range l.events {
+		if e == event {
+			n++
+		}
+	}
+	return n
+}
+
+func TestBatchLogger(t *testing.T) {
+	logger := &eventLogger{}
+	batch := NewBatch(nil, 2, WithLogger(logger))
+	batch.Add(&mockQuery{value: 1})
+	batch.Add(&mockQuery{err: errors.New("no data")})
+
+	if _, err := batch.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got := logger.count(EventQueryStart); got != 2 {
+		t.Errorf("logged %d query starts, want 2", got)
+	}
+	if got := logger.count(EventQueryEnd); got != 2 {
+		t.Errorf("logged %d query ends, want 2", got)
+	}
+	for i, event := range logger.events {
+		attrs := logger.attrs[i]
+		if attrs["query"] != "*helpers.mockQuery" {
+			t.Errorf("%s query = %v, want *helpers.mockQuery", event, attrs["query"])
+		}
+		if event != EventQueryEnd {
+			continue
+		}
+		if _, ok := attrs["duration"].(time.Duration); !ok {
+			t.Errorf("query end has no duration: %v", attrs)
+		}
+		_, failed := attrs["error"]
+		if want := attrs["index"] == 1; failed != want {
+			t.Errorf("query %v logged error = %v, want %v", attrs["index"], failed, want)
+		}
+	}
+}
+
+func TestBatchLoggerRetry(t *testing.T) {
+	unavailable := &earthengine.APIError{StatusCode: http.StatusServiceUnavailable}
+	logger := &eventLogger{}
+	batch := NewBatch(nil, 1, WithLogger(logger))
+	batch.Add(&flakyQuery{failures: 2, err: unavailable, value: 42})
+
+	if _, err := batch.ExecuteWithRetry(context.Background(), 3, time.Millisecond); err != nil {
+		t.Fatalf("ExecuteWithRetry() error = %v", err)
+	}
+
+	if got := logger.count(EventQueryRetry); got != 2 {
+		t.Fatalf("logged %d retries, want 2", got)
+	}
+	attempt := 0
+	for i, event := range logger.events {
+		if event != EventQueryRetry {
+			continue
+		}
+		attempt++
+		if got := logger.attrs[i]["attempt"]; got != attempt {
+			t.Errorf("retry attempt = %v, want %d", got, attempt)
+		}
+		if got := logger.attrs[i]["error"]; got != unavailable {
+			t.Errorf("retry error = %v, want %v", got, unavailable)
+		}
+	}
+	if got := logger.count(EventQueryEnd); got != 3 {
+		t.Errorf("logged %d query ends, want 3 (one per attempt)", got)
+	}
+}
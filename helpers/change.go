@@ -0,0 +1,255 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// defaultCompositeDays is the length of the composites compared by
+// ImageDifference: one Landsat revisit.
+const defaultCompositeDays = 16
+
+// ImageDifferenceResult is the change in a band's value at a point between
+// two dates.
+type ImageDifferenceResult struct {
+	Band          string  `json:"band"`
+	Before        float64 `json:"before"`         // Composite value at the earlier date
+	After         float64 `json:"after"`          // Composite value at the later date
+	Difference    float64 `json:"difference"`     // After - Before
+	PercentChange float64 `json:"percent_change"` // Difference relative to Before; 0 if Before is 0
+}
+
+// ImageDifference compares a band's value at a point between two dates.
+//
+// Each date is represented by a median composite (see Composite) of the
+// images from that date over the following days (16 by default; see
+// CompositeDays), so cloud masking is the same on both sides. Surface
+// reflectance bands of Landsat 8/9 and Sentinel-2 are converted to
+// reflectance; other bands are compared in their native units.
+//
+// This parallels DetectChange, but compares two composites rather than two
+// periods of a time series.
+//
+// Example:
+//
+//	change, err := helpers.ImageDifference(ctx, client, 45.5152, -122.6784, "B8",
+//	    "2020-07-01", "2023-07-01",
+//	    helpers.Sentinel2(),
+//	    helpers.CompositeDays(30),
+//	    helpers.CloudMask(20))
+//	fmt.Printf("NIR changed by %.3f (%.1f%%)\n", change.Difference, change.PercentChange)
+func ImageDifference(ctx context.Context, client *earthengine.Client, lat, lon float64, band, dateBefore, dateAfter string, opts ...ImageryOption) (*ImageDifferenceResult, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+
+	before, after, cfg, err := differenceComposites(client, band, dateBefore, dateAfter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine scale
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Sample both composites at the point in one request
+	stack := before.Rename("before").AddBands(after.Rename("after"))
+	values, err := stack.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(scale),
+		).
+		Compute(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute image difference: %w", err)
+	}
+
+	beforeValue, ok := values["before"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no %s value at point (%.4f, %.4f) on %s", band, lat, lon, dateBefore)
+	}
+	afterValue, ok := values["after"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no %s value at point (%.4f, %.4f) on %s", band, lat, lon, dateAfter)
+	}
+
+	// Convert to reflectance
+	scaleFactor, offset := bandScaling(cfg.dataset, band)
+	beforeValue = beforeValue*scaleFactor + offset
+	afterValue = afterValue*scaleFactor + offset
+
+	diff := afterValue - beforeValue
+	percentChange := 0.0
+	if beforeValue != 0 {
+		percentChange = (diff / math.Abs(beforeValue)) * 100
+	}
+
+	return &ImageDifferenceResult{
+		Band:          band,
+		Before:        beforeValue,
+		After:         afterValue,
+		Difference:    diff,
+		PercentChange: percentChange,
+	}, nil
+}
+
+// ImageDifferenceRegionResult is the change in a band over a region between
+// two dates.
+type ImageDifferenceRegionResult struct {
+	Difference     *earthengine.Image `json:"-"`               // After - Before, in a band named "difference"
+	Threshold      float64            `json:"threshold"`       // Absolute difference above which a pixel counts as changed
+	ChangedArea    float64            `json:"changed_area"`    // Area that changed beyond the threshold, in square meters
+	IncreasedArea  float64            `json:"increased_area"`  // Area that increased beyond the threshold, in square meters
+	DecreasedArea  float64            `json:"decreased_area"`  // Area that decreased beyond the threshold, in square meters
+	ValidArea      float64            `json:"valid_area"`      // Area with values on both dates, in square meters
+	PercentChanged float64            `json:"percent_changed"` // ChangedArea as a percentage of ValidArea
+}
+
+// ImageDifferenceRegion compares a band over a region between two dates and
+// measures the area whose value changed by more than threshold.
+//
+// The composites and units are as for ImageDifference, so threshold is in
+// reflectance for surface reflectance bands. Pixels masked on either date are
+// excluded from every area.
+//
+// Example:
+//
+//	burn := helpers.Bounds{MinLon: -122.3, MinLat: 44.7, MaxLon: -122.0, MaxLat: 44.9}
+//	change, err := helpers.ImageDifferenceRegion(ctx, client, burn, "SR_B5",
+//	    "2020-07-01", "2021-07-01", 0.05,
+//	    helpers.Landsat8())
+//	fmt.Printf("NIR dropped over %.1f ha\n", change.DecreasedArea/10000)
+func ImageDifferenceRegion(ctx context.Context, client *earthengine.Client, bounds Bounds, band, dateBefore, dateAfter string, threshold float64, opts ...ImageryOption) (*ImageDifferenceRegionResult, error) {
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+	if threshold < 0 {
+		return nil, fmt.Errorf("threshold must be non-negative, got %v", threshold)
+	}
+
+	before, after, cfg, err := differenceComposites(client, band, dateBefore, dateAfter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine scale
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Difference in reflectance; the offset cancels out
+	scaleFactor, _ := bandScaling(cfg.dataset, band)
+	diff := after.Subtract(before).Clip(region)
+	if scaleFactor != 1 {
+		diff = diff.Multiply(client.Constant(scaleFactor))
+	}
+	diff = diff.Rename("difference")
+
+	// Sum changed, increased, and valid pixel area in one reduction
+	area := diff.PixelArea()
+	changed := diff.Abs().Gt(threshold)
+	increased := diff.Gt(threshold)
+	valid := diff.Abs().Gte(0)
+	stack := changed.Multiply(area).Rename("changed").
+		AddBands(increased.Multiply(area).Rename("increased")).
+		AddBands(valid.Multiply(area).Rename("valid"))
+
+	stats, err := stack.
+		ReduceRegion(region, earthengine.ReducerSum(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute changed area: %w", err)
+	}
+
+	areas := make(map[string]float64, 3)
+	for _, name := range []string{"changed", "increased", "valid"} {
+		value, ok := stats[name].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s area in result: %v", name, stats[name])
+		}
+		areas[name] = value
+	}
+
+	result := &ImageDifferenceRegionResult{
+		Difference:    diff,
+		Threshold:     threshold,
+		ChangedArea:   areas["changed"],
+		IncreasedArea: areas["increased"],
+		DecreasedArea: math.Max(areas["changed"]-areas["increased"], 0),
+		ValidArea:     areas["valid"],
+	}
+	if result.ValidArea > 0 {
+		result.PercentChanged = result.ChangedArea / result.ValidArea * 100
+	}
+
+	return result, nil
+}
+
+// differenceComposites builds the before and after composites of band
+// compared by ImageDifference and ImageDifferenceRegion.
+func differenceComposites(client *earthengine.Client, band, dateBefore, dateAfter string, opts []ImageryOption) (before, after *earthengine.Image, cfg *imageryConfig, err error) {
+	if band == "" {
+		return nil, nil, nil, fmt.Errorf("band is required")
+	}
+
+	// Apply options
+	cfg = &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	days := defaultCompositeDays
+	if cfg.compositeDays != nil {
+		days = *cfg.compositeDays
+	}
+	if days <= 0 {
+		return nil, nil, nil, fmt.Errorf("composite days must be positive, got %d", days)
+	}
+
+	start1, err := time.Parse("2006-01-02", dateBefore)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid before date: %w", err)
+	}
+	start2, err := time.Parse("2006-01-02", dateAfter)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid after date: %w", err)
+	}
+	if !start2.After(start1) {
+		return nil, nil, nil, fmt.Errorf("after date %s must be later than before date %s", dateAfter, dateBefore)
+	}
+
+	composite := func(start time.Time) *earthengine.Image {
+		end := start.AddDate(0, 0, days)
+		return Composite(client, start.Format("2006-01-02"), end.Format("2006-01-02"), MedianComposite, opts...).
+			Select(band + "_median")
+	}
+
+	return composite(start1), composite(start2), cfg, nil
+}
+
+// bandScaling returns the scale and offset that convert band to reflectance,
+// or 1 and 0 if band is not a surface reflectance band of the dataset.
+func bandScaling(dataset, band string) (scale, offset float64) {
+	switch dataset {
+	case landsat8DatasetID, landsat9DatasetID, sentinel2DatasetID:
+		if containsString(getBandNamesForHarmonized(dataset), band) {
+			return getReflectanceScaling(dataset)
+		}
+	}
+	return 1, 0
+}
@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestImageDifference(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"before": 2000, "after": 3000}}`)
+
+	result, err := ImageDifference(context.Background(), client, 45.5152, -122.6784, "B8",
+		"2020-07-01", "2023-07-01", Sentinel2(), CompositeDays(30))
+	if err != nil {
+		t.Fatalf("ImageDifference failed: %v", err)
+	}
+
+	// Sentinel-2 reflectance is DN / 10000
+	if math.Abs(result.Before-0.2) > 1e-9 || math.Abs(result.After-0.3) > 1e-9 {
+		t.Errorf("Before = %v, After = %v, want 0.2, 0.3", result.Before, result.After)
+	}
+	if math.Abs(result.Difference-0.1) > 1e-9 {
+		t.Errorf("Difference = %v, want 0.1", result.Difference)
+	}
+	if math.Abs(result.PercentChange-50) > 1e-9 {
+		t.Errorf("PercentChange = %v, want 50", result.PercentChange)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"B8_median", "2020-07-31", "2023-07-31", sentinel2DatasetID} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestImageDifferenceNativeUnits(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"before": 300.5, "after": 298.5}}`)
+
+	result, err := ImageDifference(context.Background(), client, 45.5152, -122.6784, "ST_B10",
+		"2022-07-01", "2023-07-01")
+	if err != nil {
+		t.Fatalf("ImageDifference failed: %v", err)
+	}
+	if result.Difference != -2 {
+		t.Errorf("Difference = %v, want -2 (no reflectance scaling)", result.Difference)
+	}
+}
+
+func TestImageDifferenceErrors(t *testing.T) {
+	ctx := context.Background()
+
+	masked, _ := newImageryTestClient(t, `{"result": {"before": null, "after": 3000}}`)
+	if _, err := ImageDifference(ctx, masked, 45.5, -122.6, "B8", "2020-07-01", "2023-07-01", Sentinel2()); err == nil {
+		t.Error("Expected error for masked before value")
+	}
+
+	tests := []struct {
+		name                string
+		band, before, after string
+		opts                []ImageryOption
+	}{
+		{"missing band", "", "2020-07-01", "2023-07-01", nil},
+		{"invalid date", "B8", "July 2020", "2023-07-01", nil},
+		{"dates out of order", "B8", "2023-07-01", "2020-07-01", nil},
+		{"zero composite days", "B8", "2020-07-01", "2023-07-01", []ImageryOption{CompositeDays(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImageDifference(ctx, masked, 45.5, -122.6, tt.band, tt.before, tt.after, tt.opts...); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestImageDifferenceRegion(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"changed": 250000, "increased": 50000, "valid": 1000000}}`)
+
+	bounds := Bounds{MinLon: -122.3, MinLat: 44.7, MaxLon: -122.0, MaxLat: 44.9}
+	result, err := ImageDifferenceRegion(context.Background(), client, bounds, "SR_B5",
+		"2020-07-01", "2021-07-01", 0.05)
+	if err != nil {
+		t.Fatalf("ImageDifferenceRegion failed: %v", err)
+	}
+
+	if result.ChangedArea != 250000 || result.IncreasedArea != 50000 || result.DecreasedArea != 200000 {
+		t.Errorf("areas = %+v", result)
+	}
+	if result.PercentChanged != 25 {
+		t.Errorf("PercentChanged = %v, want 25", result.PercentChanged)
+	}
+	if result.Difference == nil {
+		t.Error("Difference image is nil")
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"SR_B5_median", "Image.clip", "0.0000275"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestImageDifferenceRegionErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": {}}`)
+	bounds := Bounds{MinLon: -122.3, MinLat: 44.7, MaxLon: -122.0, MaxLat: 44.9}
+
+	if _, err := ImageDifferenceRegion(ctx, client, Bounds{}, "SR_B5", "2020-07-01", "2021-07-01", 0.05); err == nil {
+		t.Error("Expected error for empty bounds")
+	}
+	if _, err := ImageDifferenceRegion(ctx, client, bounds, "SR_B5", "2020-07-01", "2021-07-01", -1); err == nil {
+		t.Error("Expected error for negative threshold")
+	}
+	if _, err := ImageDifferenceRegion(ctx, client, bounds, "SR_B5", "2020-07-01", "2021-07-01", 0.05); err == nil {
+		t.Error("Expected error for missing areas in result")
+	}
+}
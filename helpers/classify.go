@@ -0,0 +1,443 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// classificationBand is the output band name of classified images.
+const classificationBand = "classification"
+
+// ClassificationResult is the output of a classifier at a point.
+type ClassificationResult struct {
+	Class         int       `json:"class"`         // Predicted class
+	Probabilities []float64 `json:"probabilities"` // Per-class probabilities, if the classifier outputs them
+}
+
+// ClassifyImage classifies image with the trained classifier saved at
+// classifierAssetID, using the given bands as features.
+//
+// The result has a single "classification" band, ready to export or sample.
+// bands must match the bands the classifier was trained on.
+//
+// Example:
+//
+//	classified, err := helpers.ClassifyImage(composite,
+//	    "projects/my-project/assets/landcover-rf",
+//	    []string{"B2", "B3", "B4", "B8", "B11", "B12"})
+//	task, err := helpers.ExportImageAsync(ctx, client, classified,
+//	    helpers.ExportDescription("Land Cover Classification"),
+//	    helpers.ExportToGCS("my-bucket", "classifications/"))
+func ClassifyImage(image *earthengine.Image, classifierAssetID string, bands []string) (*earthengine.Image, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if classifierAssetID == "" {
+		return nil, fmt.Errorf("classifier asset ID is required")
+	}
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("at least one band is required")
+	}
+
+	classifier := earthengine.ClassifierLoad(classifierAssetID)
+	return image.Select(bands...).Classify(classifier, classificationBand), nil
+}
+
+// ClassifyPoint classifies the pixel at a point with the trained classifier
+// saved at classifierAssetID.
+//
+// The band stack is the dataset's images over the date range, or the
+// DateWindow around date, combined as for NDVI and selected to bands; bands
+// must match the bands the
+// classifier was trained on. If the classifier is in a probability output
+// mode, Probabilities holds its output and Class is the most probable class.
+//
+// Example:
+//
+//	result, err := helpers.ClassifyPoint(ctx, client,
+//	    "projects/my-project/assets/landcover-rf",
+//	    45.5152, -122.6784, "2023-06-01",
+//	    []string{"B2", "B3", "B4", "B8", "B11", "B12"},
+//	    helpers.Sentinel2(),
+//	    helpers.DateRangeOption("2023-06-01", "2023-08-31"))
+//	fmt.Printf("Class: %d\n", result.Class)
+func ClassifyPoint(ctx context.Context, client *earthengine.Client, classifierAssetID string, lat, lon float64, date string, bands []string, opts ...ImageryOption) (*ClassificationResult, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Build the query, filtered by date and cloud cover
+	collection, err := filterImagery(client.ImageCollection(cfg.dataset), cfg, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// One image, renamed so band names match training (a mean over a date
+	// range adds a "_mean" suffix)
+	stack := combineImagery(collection.Select(bands...), cfg).Rename(bands...)
+
+	classified, err := ClassifyImage(stack, classifierAssetID, bands)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine scale
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Sample at the point
+	values, err := classified.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(scale),
+		).
+		Compute(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify point: %w", err)
+	}
+
+	return parseClassification(values[classificationBand])
+}
+
+// ClassifierType selects the algorithm TrainClassifier trains.
+type ClassifierType string
+
+const (
+	ClassifierCART         ClassifierType = "CART"         // Decision tree (ee.Classifier.smileCart)
+	ClassifierRandomForest ClassifierType = "RandomForest" // Random forest (ee.Classifier.smileRandomForest)
+	ClassifierSVM          ClassifierType = "SVM"          // Support vector machine (ee.Classifier.libsvm)
+)
+
+// Defaults for TrainClassifier.
+const (
+	defaultNumberOfTrees = 100
+	defaultTrainingScale = 30.0
+	defaultSplitSeed     = 0
+)
+
+// TrainOption configures TrainClassifier.
+type TrainOption func(*trainConfig)
+
+type trainConfig struct {
+	numberOfTrees   int
+	validationSplit float64
+	scale           float64
+}
+
+// NumberOfTrees sets the number of trees of a random forest (default 100).
+func NumberOfTrees(n int) TrainOption {
+	return func(cfg *trainConfig) {
+		cfg.numberOfTrees = n
+	}
+}
+
+// ValidationSplit holds out the given fraction of the samples (between 0 and
+// 1) for validation. The classifier is trained on the rest, and
+// HoldoutAccuracy is computed on the held-out samples.
+func ValidationSplit(fraction float64) TrainOption {
+	return func(cfg *trainConfig) {
+		cfg.validationSplit = fraction
+	}
+}
+
+// TrainingScale sets the scale in meters at which the image is sampled
+// (default 30).
+func TrainingScale(meters float64) TrainOption {
+	return func(cfg *trainConfig) {
+		cfg.scale = meters
+	}
+}
+
+// TrainedClassifier is a classifier trained by TrainClassifier.
+type TrainedClassifier struct {
+	// Classifier can be passed to Image.Classify.
+	Classifier *earthengine.TrainedClassifier
+
+	// TrainingAccuracy is the resubstitution accuracy: the fraction of the
+	// training samples classified correctly.
+	TrainingAccuracy float64
+
+	// HoldoutAccuracy is the fraction of the held-out samples classified
+	// correctly. It is only set when ValidationSplit is used.
+	HoldoutAccuracy *float64
+}
+
+// TrainClassifier trains a supervised classifier on image band values
+// sampled at labeled features.
+//
+// Each feature of samples must have an integer class label in
+// classProperty; bands are the image bands used as features. The returned
+// classifier is not saved as an asset: use it directly with Image.Classify.
+//
+// Example:
+//
+//	trained, err := helpers.TrainClassifier(ctx, client, labeledPoints, composite,
+//	    []string{"B2", "B3", "B4", "B8", "B11", "B12"}, "landcover",
+//	    helpers.ClassifierRandomForest,
+//	    helpers.NumberOfTrees(200),
+//	    helpers.ValidationSplit(0.3))
+//	fmt.Printf("Training: %.2f, holdout: %.2f\n", trained.TrainingAccuracy, *trained.HoldoutAccuracy)
+//	classified := composite.Select(bands...).Classify(trained.Classifier, "classification")
+func TrainClassifier(ctx context.Context, client *earthengine.Client, samples *earthengine.FeatureCollection, image *earthengine.Image, bands []string, classProperty string, classifier ClassifierType, opts ...TrainOption) (*TrainedClassifier, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if samples.Size() == 0 {
+		return nil, fmt.Errorf("at least one training sample is required")
+	}
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("at least one band is required")
+	}
+	if classProperty == "" {
+		return nil, fmt.Errorf("class property is required")
+	}
+
+	cfg := &trainConfig{
+		numberOfTrees: defaultNumberOfTrees,
+		scale:         defaultTrainingScale,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.validationSplit < 0 || cfg.validationSplit >= 1 {
+		return nil, fmt.Errorf("validation split must be in [0, 1), got %v", cfg.validationSplit)
+	}
+
+	var algorithm earthengine.Classifier
+	switch classifier {
+	case ClassifierCART:
+		algorithm = earthengine.ClassifierSmileCart()
+	case ClassifierRandomForest:
+		if cfg.numberOfTrees <= 0 {
+			return nil, fmt.Errorf("number of trees must be positive, got %d", cfg.numberOfTrees)
+		}
+		algorithm = earthengine.ClassifierSmileRandomForest(cfg.numberOfTrees)
+	case ClassifierSVM:
+		algorithm = earthengine.ClassifierLibSVM()
+	default:
+		return nil, fmt.Errorf("unsupported classifier type: %q", classifier)
+	}
+
+	table := image.Select(bands...).SampleRegions(samples, []string{classProperty}, cfg.scale)
+
+	var holdout *earthengine.TrainingSamples
+	if cfg.validationSplit > 0 {
+		table, holdout = table.RandomSplit(1-cfg.validationSplit, defaultSplitSeed)
+	}
+
+	result := &TrainedClassifier{
+		Classifier: table.Train(algorithm, classProperty, bands),
+	}
+
+	accuracy, err := result.Classifier.TrainingAccuracy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute training accuracy: %w", err)
+	}
+	result.TrainingAccuracy = accuracy
+
+	if holdout != nil {
+		accuracy, err := holdout.Accuracy(ctx, result.Classifier, classProperty)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute holdout accuracy: %w", err)
+		}
+		result.HoldoutAccuracy = &accuracy
+	}
+
+	return result, nil
+}
+
+// clusterBand is the output band name of clustered images.
+const clusterBand = "cluster"
+
+// Defaults for Cluster.
+const defaultClusterSamples = 5000
+
+// ClusterOption configures Cluster.
+type ClusterOption func(*clusterConfig)
+
+type clusterConfig struct {
+	numPixels int
+	seed      int64
+}
+
+// ClusterSamples sets the number of pixels sampled to train the clusterer
+// (default 5000).
+func ClusterSamples(n int) ClusterOption {
+	return func(cfg *clusterConfig) {
+		cfg.numPixels = n
+	}
+}
+
+// ClusterSeed sets the seed for pixel sampling and the initial cluster
+// centers (default 0). The same seed gives the same clusters.
+func ClusterSeed(seed int64) ClusterOption {
+	return func(cfg *clusterConfig) {
+		cfg.seed = seed
+	}
+}
+
+// ClusterResult is the output of k-means clustering.
+type ClusterResult struct {
+	// Image has a single "cluster" band holding cluster numbers from 0 to
+	// numClusters-1, clipped to the region.
+	Image *earthengine.Image `json:"-"`
+
+	// PixelCounts is the number of pixels in each cluster within the region.
+	PixelCounts map[int]int `json:"pixel_counts"`
+
+	// SampledPixels is the number of valid pixels the clusterer was trained on.
+	SampledPixels int `json:"sampled_pixels"`
+}
+
+// Cluster segments the image within region into numClusters clusters with
+// k-means (ee.Clusterer.wekaKMeans), using the given bands as features.
+//
+// Pixels are sampled at random within the region to train the clusterer; set
+// ClusterSeed for reproducible results. It fails if fewer valid (unmasked)
+// pixels than numClusters could be sampled.
+//
+// Example:
+//
+//	region := earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65)
+//	result, err := helpers.Cluster(ctx, client, composite, region, 5,
+//	    []string{"B2", "B3", "B4", "B8"}, 30, helpers.ClusterSeed(42))
+//	for cluster, n := range result.PixelCounts {
+//	    fmt.Printf("Cluster %d: %d pixels\n", cluster, n)
+//	}
+func Cluster(ctx context.Context, client *earthengine.Client, image *earthengine.Image, region earthengine.Geometry, numClusters int, bands []string, scale float64, opts ...ClusterOption) (*ClusterResult, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if region == nil {
+		return nil, fmt.Errorf("region is required")
+	}
+	if numClusters < 2 {
+		return nil, fmt.Errorf("number of clusters must be at least 2, got %d", numClusters)
+	}
+	if len(bands) == 0 {
+		return nil, fmt.Errorf("at least one band is required")
+	}
+	if scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", scale)
+	}
+
+	cfg := &clusterConfig{
+		numPixels: defaultClusterSamples,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.numPixels < numClusters {
+		return nil, fmt.Errorf("sample size %d is smaller than the number of clusters %d", cfg.numPixels, numClusters)
+	}
+
+	stack := image.Select(bands...)
+	samples := stack.Sample(region, scale, cfg.numPixels, cfg.seed)
+
+	sampled, err := samples.Size(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample region: %w", err)
+	}
+	if sampled < numClusters {
+		return nil, fmt.Errorf("region has too few valid pixels to cluster: sampled %d, need at least %d", sampled, numClusters)
+	}
+
+	clusterer := samples.TrainClusterer(earthengine.ClustererWekaKMeans(numClusters, cfg.seed), bands)
+	clustered := stack.Cluster(clusterer, clusterBand).Clip(region)
+
+	values, err := clustered.
+		ReduceRegion(
+			region,
+			earthengine.ReducerFrequencyHistogram(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cluster pixels: %w", err)
+	}
+
+	counts, err := parseClassCounts(values[clusterBand])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClusterResult{
+		Image:         clustered,
+		PixelCounts:   counts,
+		SampledPixels: sampled,
+	}, nil
+}
+
+// parseClassCounts reads a frequency histogram of integer class values, such
+// as cluster numbers or land cover classes.
+func parseClassCounts(value interface{}) (map[int]int, error) {
+	histogram, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected class histogram %v (%T)", value, value)
+	}
+
+	counts := make(map[int]int, len(histogram))
+	for key, v := range histogram {
+		class, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid class value %q", key)
+		}
+		count, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid pixel count %v for class %d", v, class)
+		}
+		// Counts are weighted by pixel coverage along the region's edge
+		counts[class] = int(math.Round(count))
+	}
+
+	return counts, nil
+}
+
+// parseClassification reads a class value or an array of class probabilities.
+func parseClassification(value interface{}) (*ClassificationResult, error) {
+	switch v := value.(type) {
+	case float64:
+		return &ClassificationResult{Class: int(v)}, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("classifier returned no probabilities")
+		}
+		result := &ClassificationResult{Probabilities: make([]float64, len(v))}
+		for i, p := range v {
+			prob, ok := p.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid probability %v for class %d", p, i)
+			}
+			result.Probabilities[i] = prob
+			if prob > result.Probabilities[result.Class] {
+				result.Class = i
+			}
+		}
+		return result, nil
+	case nil:
+		return nil, fmt.Errorf("no classification at point (pixel is masked)")
+	default:
+		return nil, fmt.Errorf("unexpected classification value %v (%T)", value, value)
+	}
+}
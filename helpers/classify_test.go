@@ -0,0 +1,299 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+const testClassifierID = "projects/test-project/assets/landcover-rf"
+
+func TestClassifyPoint(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"classification": 3}}`)
+
+	result, err := ClassifyPoint(context.Background(), client, testClassifierID,
+		45.5152, -122.6784, "2023-06-01", []string{"B4", "B8"}, Sentinel2(), CloudMask(20))
+	if err != nil {
+		t.Fatalf("ClassifyPoint failed: %v", err)
+	}
+
+	if result.Class != 3 || result.Probabilities != nil {
+		t.Errorf("result = %+v, want class 3 without probabilities", result)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{earthengine.AlgorithmClassifierLoad, earthengine.AlgorithmImageClassify, testClassifierID, sentinel2DatasetID} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+	checkSentinel2Window(t, request, "2023-05-17", "2023-06-16")
+}
+
+func TestClassifyPointProbabilities(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"classification": [0.1, 0.25, 0.6, 0.05]}}`)
+
+	result, err := ClassifyPoint(context.Background(), client, testClassifierID,
+		45.5152, -122.6784, "2023-06-01", []string{"B4", "B8"})
+	if err != nil {
+		t.Fatalf("ClassifyPoint failed: %v", err)
+	}
+
+	if result.Class != 2 {
+		t.Errorf("Class = %d, want 2 (most probable)", result.Class)
+	}
+	if len(result.Probabilities) != 4 || result.Probabilities[2] != 0.6 {
+		t.Errorf("Probabilities = %v", result.Probabilities)
+	}
+}
+
+func TestClassifyPointErrors(t *testing.T) {
+	ctx := context.Background()
+
+	masked, _ := newImageryTestClient(t, `{"result": {"classification": null}}`)
+	if _, err := ClassifyPoint(ctx, masked, testClassifierID, 45.5, -122.6, "2023-06-01", []string{"B4"}); err == nil || !strings.Contains(err.Error(), "masked") {
+		t.Errorf("masked pixel: error = %v, want masked error", err)
+	}
+
+	if _, err := ClassifyPoint(ctx, masked, "", 45.5, -122.6, "2023-06-01", []string{"B4"}); err == nil {
+		t.Error("Expected error for missing classifier asset ID")
+	}
+	if _, err := ClassifyPoint(ctx, masked, testClassifierID, 45.5, -122.6, "2023-06-01", nil); err == nil {
+		t.Error("Expected error for missing bands")
+	}
+	if _, err := ClassifyPoint(ctx, masked, testClassifierID, 95, -122.6, "2023-06-01", []string{"B4"}); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+}
+
+func TestClassifyImage(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"classification": 1}}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+
+	classified, err := ClassifyImage(image, testClassifierID, []string{"B4", "B8"})
+	if err != nil {
+		t.Fatalf("ClassifyImage failed: %v", err)
+	}
+	if _, err := classified.ReduceRegion(earthengine.NewPoint(-122.6784, 45.5152), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	if !strings.Contains(lastRequest(), `"classification"`) {
+		t.Error("request does not name the output band")
+	}
+
+	if _, err := ClassifyImage(nil, testClassifierID, []string{"B4"}); err == nil {
+		t.Error("Expected error for nil image")
+	}
+}
+
+func testTrainingSamples() *earthengine.FeatureCollection {
+	return earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-122.68, 45.52), map[string]interface{}{"landcover": 1}),
+		earthengine.NewFeature(earthengine.NewPoint(-122.33, 47.61), map[string]interface{}{"landcover": 2}),
+	)
+}
+
+func TestTrainClassifier(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": 0.95}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+
+	trained, err := TrainClassifier(context.Background(), client, testTrainingSamples(), image,
+		[]string{"B4", "B8"}, "landcover", ClassifierRandomForest, NumberOfTrees(50))
+	if err != nil {
+		t.Fatalf("TrainClassifier failed: %v", err)
+	}
+
+	if trained.TrainingAccuracy != 0.95 {
+		t.Errorf("TrainingAccuracy = %v, want 0.95", trained.TrainingAccuracy)
+	}
+	if trained.HoldoutAccuracy != nil {
+		t.Errorf("HoldoutAccuracy = %v, want nil without a validation split", *trained.HoldoutAccuracy)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{
+		earthengine.AlgorithmClassifierSmileRandomForest,
+		earthengine.AlgorithmClassifierTrain,
+		earthengine.AlgorithmClassifierConfusionMatrix,
+		earthengine.AlgorithmImageSampleRegions,
+		`"numberOfTrees":{"constantValue":50}`,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+
+	// The trained classifier can classify images built from another source
+	otherClient, otherRequest := newImageryTestClient(t, `{"result": {"classification": 2}}`)
+	other := otherClient.Image("COPERNICUS/S2_SR_HARMONIZED/20230611T185919_20230611T190802_T10TEQ")
+	classified := other.Select("B4", "B8").Classify(trained.Classifier, "classification")
+	if _, err := classified.ReduceRegion(earthengine.NewPoint(-122.68, 45.52), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	if !strings.Contains(otherRequest(), earthengine.AlgorithmClassifierTrain) {
+		t.Error("classify request does not contain the training graph")
+	}
+}
+
+func TestTrainClassifierValidationSplit(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": 0.8}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+
+	trained, err := TrainClassifier(context.Background(), client, testTrainingSamples(), image,
+		[]string{"B4", "B8"}, "landcover", ClassifierCART, ValidationSplit(0.3))
+	if err != nil {
+		t.Fatalf("TrainClassifier failed: %v", err)
+	}
+
+	if trained.HoldoutAccuracy == nil || *trained.HoldoutAccuracy != 0.8 {
+		t.Errorf("HoldoutAccuracy = %v, want 0.8", trained.HoldoutAccuracy)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{
+		earthengine.AlgorithmClassifierSmileCart,
+		earthengine.AlgorithmFeatureCollectionRandomColumn,
+		earthengine.AlgorithmFilterNot,
+		earthengine.AlgorithmCollectionErrorMatrix,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("holdout request does not contain %q", want)
+		}
+	}
+}
+
+func TestTrainClassifierErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": 1}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+	samples := testTrainingSamples()
+	bands := []string{"B4", "B8"}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"nil image", func() error {
+			_, err := TrainClassifier(ctx, client, samples, nil, bands, "landcover", ClassifierCART)
+			return err
+		}},
+		{"no samples", func() error {
+			_, err := TrainClassifier(ctx, client, earthengine.NewFeatureCollection(), image, bands, "landcover", ClassifierCART)
+			return err
+		}},
+		{"no bands", func() error {
+			_, err := TrainClassifier(ctx, client, samples, image, nil, "landcover", ClassifierCART)
+			return err
+		}},
+		{"no class property", func() error {
+			_, err := TrainClassifier(ctx, client, samples, image, bands, "", ClassifierCART)
+			return err
+		}},
+		{"unknown classifier", func() error {
+			_, err := TrainClassifier(ctx, client, samples, image, bands, "landcover", ClassifierType("kNN"))
+			return err
+		}},
+		{"zero trees", func() error {
+			_, err := TrainClassifier(ctx, client, samples, image, bands, "landcover", ClassifierRandomForest, NumberOfTrees(0))
+			return err
+		}},
+		{"invalid split", func() error {
+			_, err := TrainClassifier(ctx, client, samples, image, bands, "landcover", ClassifierSVM, ValidationSplit(1))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// newClusterTestClient returns a client whose server answers sample count
+// requests with sampled and histogram requests with histogram.
+func newClusterTestClient(t *testing.T, sampled int, histogram string) *earthengine.Client {
+	t.Helper()
+
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), earthengine.AlgorithmReducerFrequencyHistogram) {
+			fmt.Fprintf(w, `{"result": {"cluster": %s}}`, histogram)
+			return
+		}
+		fmt.Fprintf(w, `{"result": %d}`, sampled)
+	})
+}
+
+func TestCluster(t *testing.T) {
+	client := newClusterTestClient(t, 4800, `{"0": 1200.0, "1": 845.6, "2": 3020.0}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+	region := earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65)
+
+	result, err := Cluster(context.Background(), client, image, region, 3, []string{"B4", "B8"}, 30, ClusterSeed(7))
+	if err != nil {
+		t.Fatalf("Cluster failed: %v", err)
+	}
+
+	if result.SampledPixels != 4800 {
+		t.Errorf("SampledPixels = %d, want 4800", result.SampledPixels)
+	}
+	want := map[int]int{0: 1200, 1: 846, 2: 3020}
+	if len(result.PixelCounts) != len(want) {
+		t.Fatalf("PixelCounts = %v, want %v", result.PixelCounts, want)
+	}
+	for cluster, n := range want {
+		if result.PixelCounts[cluster] != n {
+			t.Errorf("PixelCounts[%d] = %d, want %d", cluster, result.PixelCounts[cluster], n)
+		}
+	}
+	if result.Image == nil {
+		t.Error("Image is nil")
+	}
+}
+
+func TestClusterTooFewPixels(t *testing.T) {
+	client := newClusterTestClient(t, 2, `{}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+	region := earthengine.NewPoint(-122.68, 45.52)
+
+	_, err := Cluster(context.Background(), client, image, region, 5, []string{"B4", "B8"}, 30)
+	if err == nil || !strings.Contains(err.Error(), "too few valid pixels") {
+		t.Errorf("error = %v, want too few valid pixels", err)
+	}
+}
+
+func TestClusterErrors(t *testing.T) {
+	ctx := context.Background()
+	client := newClusterTestClient(t, 100, `{}`)
+	image := client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601T185919_20230601T190802_T10TEQ")
+	region := earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65)
+	bands := []string{"B4", "B8"}
+
+	if _, err := Cluster(ctx, client, nil, region, 3, bands, 30); err == nil {
+		t.Error("Expected error for nil image")
+	}
+	if _, err := Cluster(ctx, client, image, nil, 3, bands, 30); err == nil {
+		t.Error("Expected error for missing region")
+	}
+	if _, err := Cluster(ctx, client, image, region, 1, bands, 30); err == nil {
+		t.Error("Expected error for fewer than 2 clusters")
+	}
+	if _, err := Cluster(ctx, client, image, region, 3, nil, 30); err == nil {
+		t.Error("Expected error for missing bands")
+	}
+	if _, err := Cluster(ctx, client, image, region, 3, bands, 0); err == nil {
+		t.Error("Expected error for zero scale")
+	}
+	if _, err := Cluster(ctx, client, image, region, 3, bands, 30, ClusterSamples(2)); err == nil {
+		t.Error("Expected error for sample size below cluster count")
+	}
+}
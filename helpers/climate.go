@@ -3,6 +3,8 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -28,6 +30,9 @@ const (
 
 	// SMAP - Soil moisture (2015-present, 9km)
 	smapDatasetID = "NASA_USDA/HSL/SMAP_soil_moisture"
+
+	// ERA5-Land - Daily aggregated reanalysis (1950-present, 11km)
+	era5LandDatasetID = "ECMWF/ERA5_LAND/DAILY_AGGR"
 )
 
 // TerraClimate uses the TerraClimate monthly dataset (1958-present, 4km).
@@ -171,6 +176,61 @@ func PrecipitationWithContext(ctx context.Context, client *earthengine.Client, l
 	return result, nil
 }
 
+// PrecipitationTimeSeries returns daily CHIRPS precipitation in millimeters at
+// a location between startDate (inclusive) and endDate (exclusive).
+//
+// The total precipitation over the window is the sum of the point values.
+// The result can be passed directly to AnalyzeTrend or DetectAnomalies.
+//
+// Example:
+//
+//	ts, err := helpers.PrecipitationTimeSeries(ctx, client, 45.5152, -122.6784,
+//	    "2023-01-01", "2024-01-01")
+//	anomalies := helpers.DetectAnomalies(ts, 3.0)
+func PrecipitationTimeSeries(ctx context.Context, client *earthengine.Client, lat, lon float64, startDate, endDate string) (*TimeSeries, error) {
+	if startDate == "" || endDate == "" {
+		return nil, fmt.Errorf("start and end dates are required")
+	}
+
+	collection := client.ImageCollection(chirpsDatasetID).FilterDate(startDate, endDate)
+	ts, err := timeSeriesAtPoint(ctx, collection, lat, lon, "precipitation", 5000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute precipitation: %w", err)
+	}
+
+	return ts, nil
+}
+
+// Temperature2m returns daily mean air temperature 2m above the surface in °C
+// at a location between startDate (inclusive) and endDate (exclusive).
+//
+// Uses ERA5-Land daily aggregates, which are about 11km resolution and
+// available a few months behind real time.
+//
+// Example:
+//
+//	ts, err := helpers.Temperature2m(ctx, client, 45.5152, -122.6784,
+//	    "2023-01-01", "2024-01-01")
+//	trend, err := helpers.AnalyzeTrend(ts)
+func Temperature2m(ctx context.Context, client *earthengine.Client, lat, lon float64, startDate, endDate string) (*TimeSeries, error) {
+	if startDate == "" || endDate == "" {
+		return nil, fmt.Errorf("start and end dates are required")
+	}
+
+	collection := client.ImageCollection(era5LandDatasetID).FilterDate(startDate, endDate)
+	ts, err := timeSeriesAtPoint(ctx, collection, lat, lon, "temperature_2m", 11132)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute temperature: %w", err)
+	}
+
+	// ERA5 stores temperature in Kelvin, convert to °C
+	for i := range ts.Points {
+		ts.Points[i].Value -= 273.15
+	}
+
+	return ts, nil
+}
+
 // SoilMoisture returns the soil moisture at a location for a date range.
 //
 // Uses SMAP by default (daily, 9km resolution, 2015-present).
@@ -267,3 +327,278 @@ func NewSoilMoistureQuery(lat, lon float64, opts ...ClimateOption) Query {
 func (q *ClimateQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
 	return q.queryFn(ctx, client, q.lat, q.lon, q.opts...)
 }
+
+// SPICategory classifies a Standardized Precipitation Index value.
+type SPICategory string
+
+const (
+	// SPIExtremeDrought is an SPI of -2 or below
+	SPIExtremeDrought SPICategory = "extreme drought"
+
+	// SPISevereDrought is an SPI from -2 to -1.5
+	SPISevereDrought SPICategory = "severe drought"
+
+	// SPIModerateDrought is an SPI from -1.5 to -1
+	SPIModerateDrought SPICategory = "moderate drought"
+
+	// SPINormal is an SPI between -1 and 1
+	SPINormal SPICategory = "normal"
+
+	// SPIWet is an SPI of 1 or above
+	SPIWet SPICategory = "wet"
+)
+
+// spiLimit bounds SPI values, the 0.1% and 99.9% quantiles of the standard
+// normal distribution.
+const spiLimit = 3.09
+
+// SPIPoint is the Standardized Precipitation Index for one accumulation period.
+type SPIPoint struct {
+	Time          time.Time   `json:"time"`          // First day of the month ending the period
+	Precipitation float64     `json:"precipitation"` // Total precipitation over the period in mm
+	SPI           float64     `json:"spi"`
+	Category      SPICategory `json:"category"`
+}
+
+// SPIResult contains a Standardized Precipitation Index time series.
+type SPIResult struct {
+	Months int        `json:"months"` // Accumulation window in months
+	Points []SPIPoint `json:"points"`
+}
+
+// TimeSeries returns the SPI values as a TimeSeries for use with AnalyzeTrend
+// or DetectAnomalies.
+func (r *SPIResult) TimeSeries() *TimeSeries {
+	ts := &TimeSeries{
+		Name:   fmt.Sprintf("spi_%d", r.Months),
+		Points: make([]TimeSeriesPoint, len(r.Points)),
+	}
+	for i, p := range r.Points {
+		ts.Points[i] = TimeSeriesPoint{Time: p.Time, Value: p.SPI, Index: i}
+	}
+	return ts
+}
+
+// StandardizedPrecipitationIndex computes the SPI at a location over the
+// baseline period.
+//
+// Daily CHIRPS precipitation is summed into monthly totals, then accumulated
+// over a rolling window of the given number of months. For each calendar
+// month, the accumulated totals are fitted to a gamma distribution mixed with
+// the probability of zero precipitation, so dry months in arid regions are
+// handled without taking the log of zero. Zero totals are assigned the middle
+// of the zero probability mass, so a month that is always dry is normal rather
+// than an extreme drought. SPI values are limited to ±3.09.
+//
+// The baseline should start and end on month boundaries and cover many years;
+// 30 years is typical.
+//
+// Example:
+//
+//	// 3-month SPI over a 30 year baseline
+//	spi, err := helpers.StandardizedPrecipitationIndex(ctx, client,
+//	    36.1699, -115.1398, 3, "1991-01-01", "2021-01-01")
+//	for _, p := range spi.Points {
+//	    if p.SPI <= -1 {
+//	        fmt.Printf("%s: %s (SPI %.2f)\n", p.Time.Format("2006-01"), p.Category, p.SPI)
+//	    }
+//	}
+func StandardizedPrecipitationIndex(ctx context.Context, client *earthengine.Client, lat, lon float64, months int, baselineStart, baselineEnd string) (*SPIResult, error) {
+	if months < 1 {
+		return nil, fmt.Errorf("months must be at least 1, got %d", months)
+	}
+
+	daily, err := PrecipitationTimeSeries(ctx, client, lat, lon, baselineStart, baselineEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	monthly, err := AggregateTimeSeries(daily, "month", AggSum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate precipitation: %w", err)
+	}
+
+	return computeSPI(monthly, months)
+}
+
+// computeSPI computes the SPI from monthly precipitation totals.
+func computeSPI(monthly *TimeSeries, months int) (*SPIResult, error) {
+	// Index monthly totals by month start so gaps break the rolling window
+	totals := make(map[time.Time]float64, len(monthly.Points))
+	var first, last time.Time
+	for i, p := range monthly.Points {
+		month := time.Date(p.Time.Year(), p.Time.Month(), 1, 0, 0, 0, 0, time.UTC)
+		totals[month] = p.Value
+		if i == 0 || month.Before(first) {
+			first = month
+		}
+		if i == 0 || month.After(last) {
+			last = month
+		}
+	}
+
+	if len(totals) < months {
+		return nil, fmt.Errorf("need at least %d months of precipitation, got %d", months, len(totals))
+	}
+
+	result := &SPIResult{Months: months, Points: []SPIPoint{}}
+
+	// Accumulate over the rolling window ending each month
+	for month := first.AddDate(0, months-1, 0); !month.After(last); month = month.AddDate(0, 1, 0) {
+		sum, complete := 0.0, true
+		for k := 0; k < months; k++ {
+			value, ok := totals[month.AddDate(0, -k, 0)]
+			if !ok {
+				complete = false
+				break
+			}
+			sum += value
+		}
+		if complete {
+			result.Points = append(result.Points, SPIPoint{Time: month, Precipitation: sum})
+		}
+	}
+
+	// Fit each calendar month separately to remove seasonality
+	byMonth := make(map[time.Month][]float64)
+	for _, p := range result.Points {
+		byMonth[p.Time.Month()] = append(byMonth[p.Time.Month()], p.Precipitation)
+	}
+	fits := make(map[time.Month]gammaMixture, len(byMonth))
+	for month, values := range byMonth {
+		fits[month] = fitGammaMixture(values)
+	}
+
+	for i := range result.Points {
+		p := &result.Points[i]
+		p.SPI = fits[p.Time.Month()].spi(p.Precipitation)
+		p.Category = classifySPI(p.SPI)
+	}
+
+	return result, nil
+}
+
+// gammaMixture is a gamma distribution mixed with a probability of zero.
+type gammaMixture struct {
+	zeroProb float64 // Probability of zero precipitation
+	shape    float64 // Gamma shape (alpha); 0 when too few values to fit
+	scale    float64 // Gamma scale (beta)
+}
+
+// fitGammaMixture fits values using Thom's maximum likelihood approximation
+// for the non-zero values.
+func fitGammaMixture(values []float64) gammaMixture {
+	var nonZero []float64
+	for _, v := range values {
+		if v > 0 {
+			nonZero = append(nonZero, v)
+		}
+	}
+
+	fit := gammaMixture{zeroProb: float64(len(values)-len(nonZero)) / float64(len(values))}
+	if len(nonZero) < 2 {
+		return fit
+	}
+
+	mean, logSum := 0.0, 0.0
+	for _, v := range nonZero {
+		mean += v
+		logSum += math.Log(v)
+	}
+	mean /= float64(len(nonZero))
+
+	a := math.Log(mean) - logSum/float64(len(nonZero))
+	if a <= 0 {
+		return fit // All non-zero values are equal
+	}
+
+	fit.shape = (1 + math.Sqrt(1+4*a/3)) / (4 * a)
+	fit.scale = mean / fit.shape
+	return fit
+}
+
+// spi converts a precipitation total to an SPI value.
+func (g gammaMixture) spi(x float64) float64 {
+	var prob float64
+	switch {
+	case x <= 0:
+		prob = g.zeroProb / 2
+	case g.shape == 0:
+		// No distribution for the non-zero values, so use the middle of their mass
+		prob = g.zeroProb + (1-g.zeroProb)/2
+	default:
+		prob = g.zeroProb + (1-g.zeroProb)*regularizedGammaP(g.shape, x/g.scale)
+	}
+
+	z := math.Sqrt2 * math.Erfinv(2*prob-1)
+	return math.Max(-spiLimit, math.Min(spiLimit, z))
+}
+
+// classifySPI returns the category for an SPI value.
+func classifySPI(spi float64) SPICategory {
+	switch {
+	case spi <= -2:
+		return SPIExtremeDrought
+	case spi <= -1.5:
+		return SPISevereDrought
+	case spi <= -1:
+		return SPIModerateDrought
+	case spi < 1:
+		return SPINormal
+	default:
+		return SPIWet
+	}
+}
+
+// regularizedGammaP returns the regularized lower incomplete gamma function
+// P(a, x), using a series for small x and a continued fraction otherwise.
+func regularizedGammaP(a, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+		tiny          = 1e-300
+	)
+
+	if x <= 0 {
+		return 0
+	}
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return sum * prefix
+	}
+
+	// Lentz's method for the continued fraction of Q(a, x)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return 1 - prefix*h
+}
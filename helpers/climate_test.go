@@ -1,7 +1,11 @@
 package helpers
 
 import (
+	"context"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTerraClimateOption(t *testing.T) {
@@ -130,3 +134,210 @@ func TestClimateDatasetConstants(t *testing.T) {
 		t.Error("smapDatasetID is empty")
 	}
 }
+
+func TestPrecipitationTimeSeries(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": [
+		["id", "longitude", "latitude", "time", "precipitation"],
+		["20230602", -122.68, 45.52, 1685664000000, 3.5],
+		["20230601", -122.68, 45.52, 1685577600000, 1.25],
+		["20230603", -122.68, 45.52, 1685750400000, null]
+	]}`)
+
+	ts, err := PrecipitationTimeSeries(context.Background(), client, 45.5152, -122.6784, "2023-06-01", "2023-06-04")
+	if err != nil {
+		t.Fatalf("PrecipitationTimeSeries failed: %v", err)
+	}
+
+	if len(ts.Points) != 2 {
+		t.Fatalf("len(Points) = %d, want 2 (masked day skipped)", len(ts.Points))
+	}
+	if !ts.Points[0].Time.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Points[0].Time = %v, want 2023-06-01", ts.Points[0].Time)
+	}
+	if ts.Points[0].Value != 1.25 || ts.Points[1].Value != 3.5 {
+		t.Errorf("Values = %v, %v, want 1.25, 3.5", ts.Points[0].Value, ts.Points[1].Value)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{chirpsDatasetID, "ImageCollection.getRegion", "2023-06-04"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestTemperature2m(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": [
+		["id", "longitude", "latitude", "time", "temperature_2m"],
+		["20230601", -122.68, 45.52, 1685577600000, 293.15]
+	]}`)
+
+	ts, err := Temperature2m(context.Background(), client, 45.5152, -122.6784, "2023-06-01", "2023-06-02")
+	if err != nil {
+		t.Fatalf("Temperature2m failed: %v", err)
+	}
+
+	if len(ts.Points) != 1 || math.Abs(ts.Points[0].Value-20) > 1e-9 {
+		t.Errorf("Points = %v, want one point of 20°C", ts.Points)
+	}
+	if !strings.Contains(lastRequest(), era5LandDatasetID) {
+		t.Errorf("request does not contain %q", era5LandDatasetID)
+	}
+}
+
+func TestClimateTimeSeriesRequiresDates(t *testing.T) {
+	if _, err := PrecipitationTimeSeries(context.Background(), nil, 45.0, -122.0, "", "2023-06-01"); err == nil {
+		t.Error("Expected error for missing start date")
+	}
+	if _, err := Temperature2m(context.Background(), nil, 45.0, -122.0, "2023-06-01", ""); err == nil {
+		t.Error("Expected error for missing end date")
+	}
+}
+
+func TestRegularizedGammaP(t *testing.T) {
+	// P(1, x) is the exponential CDF; x = 0.5 uses the series and x = 4 the
+	// continued fraction
+	for _, x := range []float64{0.5, 4} {
+		if got, want := regularizedGammaP(1, x), 1-math.Exp(-x); math.Abs(got-want) > 1e-10 {
+			t.Errorf("P(1, %v) = %v, want %v", x, got, want)
+		}
+	}
+
+	// P(3, x) = 1 - e^-x (1 + x + x^2/2)
+	x := 5.0
+	if got, want := regularizedGammaP(3, x), 1-math.Exp(-x)*(1+x+x*x/2); math.Abs(got-want) > 1e-10 {
+		t.Errorf("P(3, %v) = %v, want %v", x, got, want)
+	}
+}
+
+func TestClassifySPI(t *testing.T) {
+	tests := []struct {
+		spi  float64
+		want SPICategory
+	}{
+		{-2.5, SPIExtremeDrought},
+		{-1.7, SPISevereDrought},
+		{-1.2, SPIModerateDrought},
+		{0, SPINormal},
+		{1.5, SPIWet},
+	}
+
+	for _, tt := range tests {
+		if got := classifySPI(tt.spi); got != tt.want {
+			t.Errorf("classifySPI(%v) = %q, want %q", tt.spi, got, tt.want)
+		}
+	}
+}
+
+// monthlySeries builds monthly totals starting January 2000.
+func monthlySeries(values []float64) *TimeSeries {
+	ts := &TimeSeries{Name: "precipitation_month"}
+	for i, v := range values {
+		ts.Points = append(ts.Points, TimeSeriesPoint{
+			Time:  time.Date(2000, time.Month(1+i), 1, 0, 0, 0, 0, time.UTC),
+			Value: v,
+		})
+	}
+	return ts
+}
+
+func TestComputeSPI(t *testing.T) {
+	// Ten years of a wet winter and a dry summer; July is always dry
+	var values []float64
+	for year := 0; year < 10; year++ {
+		for month := 1; month <= 12; month++ {
+			switch month {
+			case 1:
+				values = append(values, 80+float64(year)*5)
+			case 7:
+				values = append(values, 0)
+			default:
+				values = append(values, 20+float64((year*month)%7))
+			}
+		}
+	}
+	// The final January is far drier than any other
+	values[108] = 5
+
+	result, err := computeSPI(monthlySeries(values), 1)
+	if err != nil {
+		t.Fatalf("computeSPI failed: %v", err)
+	}
+	if len(result.Points) != len(values) {
+		t.Fatalf("len(Points) = %d, want %d", len(result.Points), len(values))
+	}
+
+	for _, p := range result.Points {
+		if math.IsNaN(p.SPI) || math.Abs(p.SPI) > spiLimit {
+			t.Errorf("%s: SPI = %v, want finite within ±%v", p.Time.Format("2006-01"), p.SPI, spiLimit)
+		}
+		if p.Time.Month() == time.July && (p.SPI != 0 || p.Category != SPINormal) {
+			t.Errorf("%s: always-dry month SPI = %v (%s), want 0 (normal)", p.Time.Format("2006-01"), p.SPI, p.Category)
+		}
+	}
+
+	dry := result.Points[108]
+	if dry.SPI > -1.5 {
+		t.Errorf("dry January SPI = %v, want at most -1.5", dry.SPI)
+	}
+	if dry.Category != SPISevereDrought && dry.Category != SPIExtremeDrought {
+		t.Errorf("dry January category = %q, want severe or extreme drought", dry.Category)
+	}
+
+	if ts := result.TimeSeries(); ts.Name != "spi_1" || len(ts.Points) != len(values) {
+		t.Errorf("TimeSeries() = %s with %d points, want spi_1 with %d", ts.Name, len(ts.Points), len(values))
+	}
+}
+
+func TestComputeSPIRollingWindow(t *testing.T) {
+	ts := monthlySeries([]float64{10, 20, 30, 40, 50})
+	// Drop April so windows spanning it are incomplete
+	ts.Points = append(ts.Points[:3], ts.Points[4])
+
+	result, err := computeSPI(ts, 2)
+	if err != nil {
+		t.Fatalf("computeSPI failed: %v", err)
+	}
+
+	if len(result.Points) != 2 {
+		t.Fatalf("len(Points) = %d, want 2", len(result.Points))
+	}
+	if result.Points[0].Time.Month() != time.February || result.Points[0].Precipitation != 30 {
+		t.Errorf("Points[0] = %v, want February with 30mm", result.Points[0])
+	}
+	if result.Points[1].Time.Month() != time.March || result.Points[1].Precipitation != 50 {
+		t.Errorf("Points[1] = %v, want March with 50mm", result.Points[1])
+	}
+
+	if _, err := computeSPI(monthlySeries([]float64{10}), 3); err == nil {
+		t.Error("Expected error for fewer months than the window")
+	}
+}
+
+func TestStandardizedPrecipitationIndex(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": [
+		["id", "longitude", "latitude", "time", "precipitation"],
+		["20230101", -115.14, 36.17, 1672531200000, 2],
+		["20230115", -115.14, 36.17, 1673740800000, 3],
+		["20230201", -115.14, 36.17, 1675209600000, 0]
+	]}`)
+
+	result, err := StandardizedPrecipitationIndex(context.Background(), client, 36.1699, -115.1398, 1, "2023-01-01", "2023-03-01")
+	if err != nil {
+		t.Fatalf("StandardizedPrecipitationIndex failed: %v", err)
+	}
+
+	if len(result.Points) != 2 {
+		t.Fatalf("len(Points) = %d, want 2", len(result.Points))
+	}
+	if result.Points[0].Precipitation != 5 {
+		t.Errorf("January precipitation = %v, want 5", result.Points[0].Precipitation)
+	}
+	if !strings.Contains(lastRequest(), chirpsDatasetID) {
+		t.Errorf("request does not contain %q", chirpsDatasetID)
+	}
+
+	if _, err := StandardizedPrecipitationIndex(context.Background(), client, 36.0, -115.0, 0, "2023-01-01", "2023-03-01"); err == nil {
+		t.Error("Expected error for zero months")
+	}
+}
@@ -0,0 +1,138 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// Quality bits for each sensor's cloud mask.
+const (
+	// Sentinel-2 QA60: bit 10 = opaque clouds, bit 11 = cirrus
+	sentinel2QA60CloudBits = 1<<10 | 1<<11
+
+	// Landsat Collection 2 QA_PIXEL: bit 1 = dilated cloud, 2 = cirrus,
+	// 3 = cloud, 4 = cloud shadow
+	landsatQAPixelCloudBits = 1<<1 | 1<<2 | 1<<3 | 1<<4
+
+	// MODIS state_1km: bits 0-1 = cloud state, 2 = cloud shadow,
+	// 8-9 = cirrus, 10 = internal cloud algorithm flag
+	modisState1kmCloudBits = 1<<0 | 1<<1 | 1<<2 | 1<<8 | 1<<9 | 1<<10
+)
+
+// MaskCloudsSentinel2 masks cloudy pixels in a Sentinel-2 image using the QA60
+// band, removing opaque clouds and cirrus.
+//
+// QA60 is empty for scenes processed after January 2022; use
+// MaskCloudsSentinel2SR for recent surface reflectance imagery.
+//
+// Example:
+//
+//	masked := client.ImageCollection("COPERNICUS/S2_HARMONIZED").
+//	    FilterDate("2021-06-01", "2021-09-01").
+//	    Map(helpers.MaskCloudsSentinel2)
+func MaskCloudsSentinel2(img *earthengine.Image) *earthengine.Image {
+	return img.UpdateMask(img.Select("QA60").BitwiseAnd(sentinel2QA60CloudBits).Eq(0))
+}
+
+// MaskCloudsSentinel2SR masks cloudy pixels in a Sentinel-2 surface
+// reflectance image using the scene classification (SCL) band, removing cloud
+// shadow, medium and high probability cloud, and cirrus.
+//
+// Example:
+//
+//	masked := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+//	    FilterDate("2023-06-01", "2023-09-01").
+//	    Map(helpers.MaskCloudsSentinel2SR)
+func MaskCloudsSentinel2SR(img *earthengine.Image) *earthengine.Image {
+	// Scene classification: 3 = cloud shadow, 8/9 = cloud, 10 = cirrus
+	scl := img.Select("SCL")
+	clear := scl.Neq(3).And(scl.Neq(8)).And(scl.Neq(9)).And(scl.Neq(10))
+	return img.UpdateMask(clear)
+}
+
+// MaskCloudsLandsat masks cloudy pixels in a Landsat Collection 2 image using
+// the QA_PIXEL band, removing clouds, dilated clouds, cirrus, and cloud
+// shadow.
+//
+// Example:
+//
+//	masked := client.ImageCollection("LANDSAT/LC08/C02/T1_L2").
+//	    FilterDate("2023-06-01", "2023-09-01").
+//	    Map(helpers.MaskCloudsLandsat)
+func MaskCloudsLandsat(img *earthengine.Image) *earthengine.Image {
+	return img.UpdateMask(img.Select("QA_PIXEL").BitwiseAnd(landsatQAPixelCloudBits).Eq(0))
+}
+
+// MaskCloudsMODIS masks cloudy pixels in a MODIS surface reflectance image
+// (MOD09GA, MYD09GA, MOD09A1, or MYD09A1) using the state_1km band.
+//
+// Only pixels flagged clear by the cloud state bits, with no cloud shadow,
+// cirrus, or internal cloud flag, are kept.
+//
+// Example:
+//
+//	masked := client.ImageCollection("MODIS/061/MOD09GA").
+//	    FilterDate("2023-06-01", "2023-09-01").
+//	    Map(helpers.MaskCloudsMODIS)
+func MaskCloudsMODIS(img *earthengine.Image) *earthengine.Image {
+	return img.UpdateMask(img.Select("state_1km").BitwiseAnd(modisState1kmCloudBits).Eq(0))
+}
+
+// maskClouds masks cloudy pixels with the dataset's registered CloudMask, or
+// else its quality band.
+//
+// cloudBand is used for datasets without a known quality band; pixels where it
+// is non-zero are masked.
+func maskClouds(img *earthengine.Image, dataset, cloudBand string) *earthengine.Image {
+	if def, ok := LookupDataset(dataset); ok && def.CloudMask != nil {
+		return def.CloudMask(img)
+	}
+
+	switch {
+	case strings.HasPrefix(dataset, "COPERNICUS/S2_SR"):
+		return MaskCloudsSentinel2SR(img)
+	case strings.HasPrefix(dataset, "COPERNICUS/S2"):
+		return MaskCloudsSentinel2(img)
+	case strings.HasPrefix(dataset, "LANDSAT/") && strings.Contains(dataset, "/C02/"):
+		return MaskCloudsLandsat(img)
+	case isMODISSurfaceReflectance(dataset):
+		return MaskCloudsMODIS(img)
+	case cloudBand != "":
+		return img.UpdateMask(img.Select(cloudBand).Eq(0))
+	default:
+		return img
+	}
+}
+
+// maskCollectionClouds masks cloudy pixels in every image of a collection of
+// the given dataset. Datasets without a known quality band are unchanged.
+func maskCollectionClouds(collection *earthengine.ImageCollection, dataset string) *earthengine.ImageCollection {
+	if !hasCloudMask(dataset) {
+		return collection
+	}
+	return collection.Map(func(img *earthengine.Image) *earthengine.Image {
+		return maskClouds(img, dataset, "")
+	})
+}
+
+// hasCloudMask reports whether maskClouds knows how to mask the dataset.
+func hasCloudMask(dataset string) bool {
+	if def, ok := LookupDataset(dataset); ok && def.CloudMask != nil {
+		return true
+	}
+	return strings.HasPrefix(dataset, "COPERNICUS/S2") ||
+		(strings.HasPrefix(dataset, "LANDSAT/") && strings.Contains(dataset, "/C02/")) ||
+		isMODISSurfaceReflectance(dataset)
+}
+
+// isMODISSurfaceReflectance reports whether dataset is a MODIS surface
+// reflectance product with a state_1km band.
+func isMODISSurfaceReflectance(dataset string) bool {
+	for _, product := range []string{"/MOD09GA", "/MYD09GA", "/MOD09A1", "/MYD09A1"} {
+		if strings.HasPrefix(dataset, "MODIS/") && strings.Contains(dataset, product) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func TestMaskCloudsFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		mask     func(*earthengine.Image) *earthengine.Image
+		wantBand string
+		wantBits string
+	}{
+		{"Sentinel2", MaskCloudsSentinel2, "QA60", "3072"},
+		{"Landsat", MaskCloudsLandsat, "QA_PIXEL", "30"},
+		{"MODIS", MaskCloudsMODIS, "state_1km", "1799"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newCompositeTestServer(t, 1)
+			body := server.compute(t, tt.mask(client.Image("test")))
+
+			if !server.called("Image.updateMask") || !server.called("Image.bitwiseAnd") {
+				t.Error("Expected Image.updateMask and Image.bitwiseAnd calls")
+			}
+			if !strings.Contains(body, `"`+tt.wantBand+`"`) {
+				t.Errorf("request does not select %s", tt.wantBand)
+			}
+			if !strings.Contains(body, `"constantValue":`+tt.wantBits) {
+				t.Errorf("request does not contain cloud bits %s", tt.wantBits)
+			}
+		})
+	}
+}
+
+func TestMaskCloudsSentinel2SR(t *testing.T) {
+	client, server := newCompositeTestServer(t, 1)
+	body := server.compute(t, MaskCloudsSentinel2SR(client.Image("test")))
+
+	if !strings.Contains(body, `"SCL"`) {
+		t.Error("request does not select SCL")
+	}
+	if !server.called("Image.neq") {
+		t.Error("Expected Image.neq calls")
+	}
+}
+
+func TestMaskCloudsDataset(t *testing.T) {
+	tests := []struct {
+		dataset  string
+		wantBand string
+	}{
+		{sentinel2DatasetID, "SCL"},
+		{"COPERNICUS/S2_HARMONIZED", "QA60"},
+		{landsat8DatasetID, "QA_PIXEL"},
+		{"MODIS/061/MOD09GA", "state_1km"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataset, func(t *testing.T) {
+			if !hasCloudMask(tt.dataset) {
+				t.Errorf("hasCloudMask(%q) = false, want true", tt.dataset)
+			}
+
+			client, server := newCompositeTestServer(t, 1)
+			body := server.compute(t, maskClouds(client.Image("test"), tt.dataset, ""))
+			if !strings.Contains(body, `"`+tt.wantBand+`"`) {
+				t.Errorf("request does not select %s", tt.wantBand)
+			}
+		})
+	}
+
+	for _, dataset := range []string{modisVIDatasetID, modisLSTDatasetID} {
+		if hasCloudMask(dataset) {
+			t.Errorf("hasCloudMask(%q) = true, want false", dataset)
+		}
+	}
+}
+
+func TestNDVIMasksClouds(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.5}}`)
+
+	if _, err := NDVIWithContext(context.Background(), client, 45.5, -122.6, "2023-06-15"); err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+
+	request := lastRequest()
+	if !strings.Contains(request, "QA_PIXEL") || !strings.Contains(request, "Collection.map") {
+		t.Error("NDVI request does not mask clouds with QA_PIXEL")
+	}
+}
@@ -0,0 +1,272 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// CollectionStats summarizes the images in an ImageCollection.
+type CollectionStats struct {
+	Count     int       `json:"count"`      // Number of images
+	FirstTime time.Time `json:"first_time"` // Earliest system:time_start (UTC); zero if the collection is empty
+	LastTime  time.Time `json:"last_time"`  // Latest system:time_start (UTC); zero if the collection is empty
+	Dates     []string  `json:"dates"`      // Distinct acquisition dates ("YYYY-MM-DD", UTC), sorted
+}
+
+// DateRange returns the range of acquisition dates, or nil if the
+// collection is empty.
+func (s *CollectionStats) DateRange() *DateRange {
+	if len(s.Dates) == 0 {
+		return nil
+	}
+	return &DateRange{Start: s.Dates[0], End: s.Dates[len(s.Dates)-1]}
+}
+
+// CollectionInfo reports how many images a collection holds and when they
+// were acquired, for example to check a filter before compositing.
+//
+// Only image metadata is computed; no pixel data is read. An empty
+// collection is not an error: Count is 0 and there are no dates.
+//
+// Example:
+//
+//	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+//	    FilterDate("2023-06-01", "2023-08-31").
+//	    FilterBounds(earthengine.NewPoint(-122.68, 45.52))
+//	info, err := helpers.CollectionInfo(ctx, client, collection)
+//	fmt.Printf("%d images on %d dates\n", info.Count, len(info.Dates))
+func CollectionInfo(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection) (*CollectionStats, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+
+	count, err := collection.Size(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CollectionStats{Count: count}
+	if count == 0 {
+		return stats, nil
+	}
+
+	times, err := acquisitionTimes(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range times {
+		if stats.FirstTime.IsZero() || t.Before(stats.FirstTime) {
+			stats.FirstTime = t
+		}
+		if t.After(stats.LastTime) {
+			stats.LastTime = t
+		}
+		date := t.Format("2006-01-02")
+		if !seen[date] {
+			seen[date] = true
+			stats.Dates = append(stats.Dates, date)
+		}
+	}
+	sort.Strings(stats.Dates)
+
+	return stats, nil
+}
+
+// acquisitionTimes returns the system:time_start of every image in
+// collection, in UTC.
+func acquisitionTimes(ctx context.Context, collection *earthengine.ImageCollection) ([]time.Time, error) {
+	values, err := collection.AggregateArray(ctx, "system:time_start")
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, len(values))
+	for i, value := range values {
+		ms, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected system:time_start value %v (%T)", value, value)
+		}
+		times[i] = time.UnixMilli(int64(ms)).UTC()
+	}
+	return times, nil
+}
+
+// NearestImageResult is the acquisition chosen by NearestImage.
+type NearestImageResult struct {
+	Image      *earthengine.Image `json:"-"`
+	Date       time.Time          `json:"date"`        // Acquisition time (system:time_start, UTC)
+	CloudCover float64            `json:"cloud_cover"` // Scene cloud cover percentage, or -1 if the dataset has none
+	DaysOff    float64            `json:"days_off"`    // Days between Date and the target date
+}
+
+// NearestImage returns the image acquired closest in time to targetDate whose
+// scene cloud cover is at most maxCloudPercent, searching a window centered
+// on targetDate (30 days, see DateWindow).
+//
+// Cloud cover is read from CLOUDY_PIXEL_PERCENTAGE for Sentinel-2 and
+// CLOUD_COVER for Landsat; other datasets have no scene cloud cover and every
+// image qualifies. If no image qualifies, the error reports the closest
+// rejected scene's cloud cover.
+//
+// Example:
+//
+//	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+//	    FilterBounds(earthengine.NewPoint(-122.68, 45.52))
+//	nearest, err := helpers.NearestImage(ctx, client, collection, "2023-06-01", 20)
+//	fmt.Println("using scene from", nearest.Date.Format("2006-01-02"))
+func NearestImage(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, targetDate string, maxCloudPercent float64, opts ...ImageryOption) (*NearestImageResult, error) {
+	_ = client
+
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if maxCloudPercent < 0 || maxCloudPercent > 100 {
+		return nil, fmt.Errorf("max cloud percent must be between 0 and 100, got %v", maxCloudPercent)
+	}
+
+	cfg := &imageryConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	days := defaultDateWindowDays
+	if cfg.windowDays != nil {
+		days = *cfg.windowDays
+	}
+	window, err := centeredWindow(targetDate, days)
+	if err != nil {
+		return nil, err
+	}
+	target, _ := time.Parse(dateLayout, targetDate)
+
+	candidates := collection.FilterDate(window.Start, window.End)
+	ids, err := candidates.AggregateArray(ctx, "system:index")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	times, err := acquisitionTimes(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acquisition times: %w", err)
+	}
+	if len(times) != len(ids) {
+		return nil, fmt.Errorf("got %d acquisition times for %d images", len(times), len(ids))
+	}
+
+	clouds := make([]float64, len(ids))
+	property := cloudCoverProperty(collection.ID())
+	if property != "" && len(ids) > 0 {
+		values, err := candidates.AggregateArray(ctx, property)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", property, err)
+		}
+		if len(values) != len(ids) {
+			return nil, fmt.Errorf("got %d %s values for %d images", len(values), property, len(ids))
+		}
+		for i, value := range values {
+			cloud, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s value %v (%T)", property, value, value)
+			}
+			clouds[i] = cloud
+		}
+	} else {
+		for i := range clouds {
+			clouds[i] = -1
+		}
+	}
+
+	best, rejected := -1, -1
+	offset := func(i int) time.Duration {
+		d := times[i].Sub(target)
+		if d < 0 {
+			return -d
+		}
+		return d
+	}
+	for i := range ids {
+		if clouds[i] > maxCloudPercent {
+			if rejected < 0 || offset(i) < offset(rejected) {
+				rejected = i
+			}
+			continue
+		}
+		if best < 0 || offset(i) < offset(best) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		if rejected < 0 {
+			return nil, fmt.Errorf("%w between %s and %s", ErrNoImages, window.Start, window.End)
+		}
+		return nil, fmt.Errorf("%w with cloud cover at most %.0f%% between %s and %s (closest scene, %s, is %.1f%% cloudy)",
+			ErrNoImages, maxCloudPercent, window.Start, window.End, times[rejected].Format(dateLayout), clouds[rejected])
+	}
+
+	id, ok := ids[best].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected system:index value %v (%T)", ids[best], ids[best])
+	}
+
+	return &NearestImageResult{
+		Image:      candidates.FilterMetadata("system:index", "equals", id).First(),
+		Date:       times[best],
+		CloudCover: clouds[best],
+		DaysOff:    offset(best).Hours() / 24,
+	}, nil
+}
+
+// StackCollectionToImage converts one band of a collection into a single
+// image with a band per acquisition date, for example to export a dense NDVI
+// time series as one GeoTIFF.
+//
+// Bands are in date order and named "<band>_YYYYMMDD"; images acquired on the
+// same UTC date are mosaicked into one band. The dates ("YYYY-MM-DD") are
+// also stored, in band order, in the image's "dates" property.
+//
+// Example:
+//
+//	modis := client.ImageCollection("MODIS/061/MOD13Q1").
+//	    FilterDate("2023-01-01", "2024-01-01")
+//	stack, err := helpers.StackCollectionToImage(ctx, client, modis, "NDVI")
+//	task, err := helpers.ExportImageAsync(ctx, client, stack,
+//	    helpers.ExportDescription("NDVI time series"),
+//	    helpers.ExportToGCS("my-bucket", "ndvi-series/"))
+func StackCollectionToImage(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, band string) (*earthengine.Image, error) {
+	_ = client
+
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if band == "" {
+		return nil, fmt.Errorf("band is required")
+	}
+
+	times, err := acquisitionTimes(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acquisition times: %w", err)
+	}
+	dates, first := acquisitionDates(times)
+	if len(dates) == 0 {
+		return nil, ErrNoImages
+	}
+
+	selected := collection.Select(band)
+	var stack *earthengine.Image
+	for _, date := range dates {
+		layer := dateMosaic(selected, first[date]).
+			Rename(band + "_" + first[date].Format("20060102"))
+		if stack == nil {
+			stack = layer
+		} else {
+			stack = stack.AddBands(layer)
+		}
+	}
+
+	return stack.Set("dates", dates), nil
+}
@@ -0,0 +1,226 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// newCollectionTestClient returns a client whose server answers size requests
+// with size and aggregate requests with times, recording each request body.
+func newCollectionTestClient(t *testing.T, size int, times string) (*earthengine.Client, func() []string) {
+	t.Helper()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, earthengine.AlgorithmAggregateArray) {
+			fmt.Fprintf(w, `{"result": %s}`, times)
+			return
+		}
+		fmt.Fprintf(w, `{"result": %d}`, size)
+	})
+	return client, recorder.requests
+}
+
+func TestCollectionInfo(t *testing.T) {
+	// 2023-06-05 (twice, two tiles) and 2023-06-20
+	june5 := time.Date(2023, 6, 5, 19, 5, 0, 0, time.UTC).UnixMilli()
+	june20 := time.Date(2023, 6, 20, 19, 5, 0, 0, time.UTC).UnixMilli()
+	client, requests := newCollectionTestClient(t, 3, fmt.Sprintf("[%d, %d, %d]", june20, june5, june5+1000))
+
+	collection := client.ImageCollection(sentinel2DatasetID).FilterDate("2023-06-01", "2023-07-01")
+	info, err := CollectionInfo(context.Background(), client, collection)
+	if err != nil {
+		t.Fatalf("CollectionInfo failed: %v", err)
+	}
+
+	if info.Count != 3 {
+		t.Errorf("Count = %d, want 3", info.Count)
+	}
+	if info.FirstTime.UnixMilli() != june5 || info.LastTime.UnixMilli() != june20 {
+		t.Errorf("time range = %s - %s", info.FirstTime, info.LastTime)
+	}
+	if len(info.Dates) != 2 || info.Dates[0] != "2023-06-05" || info.Dates[1] != "2023-06-20" {
+		t.Errorf("Dates = %v, want [2023-06-05 2023-06-20]", info.Dates)
+	}
+	if r := info.DateRange(); r == nil || r.Start != "2023-06-05" || r.End != "2023-06-20" {
+		t.Errorf("DateRange() = %+v", r)
+	}
+	if len(requests()) != 2 {
+		t.Errorf("requests = %v, want size and aggregate only", requests())
+	}
+}
+
+func TestCollectionInfoEmpty(t *testing.T) {
+	client, requests := newCollectionTestClient(t, 0, `[]`)
+
+	info, err := CollectionInfo(context.Background(), client, client.ImageCollection(sentinel2DatasetID))
+	if err != nil {
+		t.Fatalf("CollectionInfo failed: %v", err)
+	}
+
+	if info.Count != 0 || !info.FirstTime.IsZero() || info.Dates != nil || info.DateRange() != nil {
+		t.Errorf("info = %+v, want empty", info)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("requests = %v, want only the size request", requests())
+	}
+}
+
+func TestCollectionInfoErrors(t *testing.T) {
+	client, _ := newCollectionTestClient(t, 1, `["not a time"]`)
+
+	if _, err := CollectionInfo(context.Background(), client, nil); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+	if _, err := CollectionInfo(context.Background(), client, client.ImageCollection(sentinel2DatasetID)); err == nil {
+		t.Error("Expected error for invalid time value")
+	}
+}
+
+// newNearestImageTestClient returns a client whose server answers
+// aggregate_array requests for system:index, CLOUDY_PIXEL_PERCENTAGE, and
+// system:time_start with the given JSON arrays.
+func newNearestImageTestClient(t *testing.T, ids, clouds, times string) (*earthengine.Client, func() []string) {
+	t.Helper()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(body, `"system:index"`):
+			fmt.Fprintf(w, `{"result": %s}`, ids)
+		case strings.Contains(body, `"CLOUDY_PIXEL_PERCENTAGE"`):
+			fmt.Fprintf(w, `{"result": %s}`, clouds)
+		default:
+			fmt.Fprintf(w, `{"result": %s}`, times)
+		}
+	})
+	return client, recorder.requests
+}
+
+func TestNearestImage(t *testing.T) {
+	// Clear scenes 6 and 3 days before the target, a cloudy one on the day
+	may26 := time.Date(2023, 5, 26, 19, 0, 0, 0, time.UTC)
+	may29 := time.Date(2023, 5, 29, 19, 0, 0, 0, time.UTC)
+	june1 := time.Date(2023, 6, 1, 19, 0, 0, 0, time.UTC)
+	client, requests := newNearestImageTestClient(t,
+		`["A", "B", "C"]`, `[5, 12, 80]`,
+		fmt.Sprintf("[%d, %d, %d]", may26.UnixMilli(), may29.UnixMilli(), june1.UnixMilli()))
+
+	result, err := NearestImage(context.Background(), client, client.ImageCollection(sentinel2DatasetID), "2023-06-01", 20)
+	if err != nil {
+		t.Fatalf("NearestImage failed: %v", err)
+	}
+
+	if !result.Date.Equal(may29) {
+		t.Errorf("Date = %s, want %s", result.Date, may29)
+	}
+	if result.CloudCover != 12 {
+		t.Errorf("CloudCover = %v, want 12", result.CloudCover)
+	}
+	if result.DaysOff < 2 || result.DaysOff > 3 {
+		t.Errorf("DaysOff = %v, want between 2 and 3", result.DaysOff)
+	}
+	if result.Image == nil {
+		t.Fatal("Image is nil")
+	}
+	for _, want := range []string{"2023-05-17", "2023-06-16"} {
+		if !strings.Contains(requests()[0], want) {
+			t.Errorf("request does not search the window: missing %q", want)
+		}
+	}
+}
+
+func TestNearestImageNoneQualify(t *testing.T) {
+	june1 := time.Date(2023, 6, 1, 19, 0, 0, 0, time.UTC)
+	june10 := time.Date(2023, 6, 10, 19, 0, 0, 0, time.UTC)
+	client, _ := newNearestImageTestClient(t,
+		`["A", "B"]`, `[64.5, 90]`,
+		fmt.Sprintf("[%d, %d]", june10.UnixMilli(), june1.UnixMilli()))
+
+	_, err := NearestImage(context.Background(), client, client.ImageCollection(sentinel2DatasetID), "2023-06-01", 20)
+	if !errors.Is(err, ErrNoImages) {
+		t.Fatalf("err = %v, want ErrNoImages", err)
+	}
+	if !strings.Contains(err.Error(), "90.0%") {
+		t.Errorf("error %q does not report the closest scene's cloud cover", err)
+	}
+}
+
+func TestNearestImageErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newNearestImageTestClient(t, `[]`, `[]`, `[]`)
+	collection := client.ImageCollection(sentinel2DatasetID)
+
+	if _, err := NearestImage(ctx, client, nil, "2023-06-01", 20); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+	if _, err := NearestImage(ctx, client, collection, "2023-06-01", 120); err == nil {
+		t.Error("Expected error for cloud percent above 100")
+	}
+	if _, err := NearestImage(ctx, client, collection, "June 1", 20); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+	if _, err := NearestImage(ctx, client, collection, "2023-06-01", 20); !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages for an empty window", err)
+	}
+}
+
+func TestStackCollectionToImage(t *testing.T) {
+	ctx := context.Background()
+
+	// Listed out of order, with two tiles on 2023-06-05
+	june20 := time.Date(2023, 6, 20, 19, 5, 0, 0, time.UTC).UnixMilli()
+	june5 := time.Date(2023, 6, 5, 19, 5, 0, 0, time.UTC).UnixMilli()
+	client, requests := newCollectionTestClient(t, 3, fmt.Sprintf("[%d, %d, %d]", june20, june5, june5+20000))
+
+	stack, err := StackCollectionToImage(ctx, client, client.ImageCollection("MODIS/061/MOD13Q1"), "NDVI")
+	if err != nil {
+		t.Fatalf("StackCollectionToImage failed: %v", err)
+	}
+
+	// Evaluate the stack so its graph is sent to the server
+	stack.ReduceRegion(earthengine.NewPoint(0, 0), earthengine.ReducerFirst()).Compute(ctx)
+	sent := requests()
+	request := sent[len(sent)-1]
+
+	if n := strings.Count(request, earthengine.AlgorithmImageCollectionMosaic); n != 2 {
+		t.Errorf("request has %d mosaics, want 2", n)
+	}
+	for _, want := range []string{
+		`"NDVI_20230605"`,
+		`"NDVI_20230620"`,
+		`["2023-06-05","2023-06-20"]`,
+		earthengine.AlgorithmImageAddBands,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+}
+
+func TestStackCollectionToImageErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newCollectionTestClient(t, 0, `[]`)
+	collection := client.ImageCollection("MODIS/061/MOD13Q1")
+
+	if _, err := StackCollectionToImage(ctx, client, collection, "NDVI"); !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages", err)
+	}
+	if _, err := StackCollectionToImage(ctx, client, collection, ""); err == nil {
+		t.Error("Expected error for missing band")
+	}
+	if _, err := StackCollectionToImage(ctx, client, nil, "NDVI"); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -16,9 +17,83 @@ type QueryOptions struct {
 }
 
 // DateRange represents a time range for filtering data.
+//
+// Days and Split treat End as the last day of the range. Helpers that pass
+// the range to ImageCollection.FilterDate use End as an exclusive bound.
 type DateRange struct {
-	Start string // Format: "YYYY-MM-DD"
-	End   string // Format: "YYYY-MM-DD"
+	Start string `json:"start"` // Format: "YYYY-MM-DD"
+	End   string `json:"end"`   // Format: "YYYY-MM-DD"
+}
+
+// dateLayout is the format of DateRange dates.
+const dateLayout = "2006-01-02"
+
+// NewDateRange returns the range from start to end, using the calendar date
+// of each in its own location.
+func NewDateRange(start, end time.Time) DateRange {
+	return DateRange{Start: start.Format(dateLayout), End: end.Format(dateLayout)}
+}
+
+// Parse returns the start and end dates as UTC midnights, or an error if
+// either is not a valid YYYY-MM-DD date or End is before Start.
+func (r DateRange) Parse() (start, end time.Time, err error) {
+	start, err = time.Parse(dateLayout, r.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q (use YYYY-MM-DD): %w", r.Start, err)
+	}
+	end, err = time.Parse(dateLayout, r.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q (use YYYY-MM-DD): %w", r.End, err)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", r.End, r.Start)
+	}
+	return start, end, nil
+}
+
+// Validate reports whether the range is well formed; see Parse.
+//
+// Example:
+//
+//	if err := (helpers.DateRange{Start: "2023-13-01", End: "2023-12-31"}).Validate(); err != nil {
+//	    // invalid start date "2023-13-01" ...
+//	}
+func (r DateRange) Validate() error {
+	_, _, err := r.Parse()
+	return err
+}
+
+// Days returns the number of days in the range, counting both Start and
+// End, or 0 if the range is invalid.
+func (r DateRange) Days() int {
+	start, end, err := r.Parse()
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// Split divides the range into consecutive "day", "week", "month", or "year"
+// intervals, each with an inclusive End. Weeks are counted from Start;
+// months and years follow the calendar, so the first and last intervals may
+// be partial. It returns nil if the range or interval is invalid.
+//
+// Example:
+//
+//	for _, month := range (helpers.DateRange{Start: "2023-01-15", End: "2023-03-31"}).Split("month") {
+//	    fmt.Println(month.Start, month.End) // 2023-01-15 2023-01-31, 2023-02-01 2023-02-28, ...
+//	}
+func (r DateRange) Split(interval string) []DateRange {
+	periods, err := generatePeriods(r.Start, r.End, interval)
+	if err != nil {
+		return nil
+	}
+
+	ranges := make([]DateRange, len(periods))
+	for i, p := range periods {
+		ranges[i] = DateRange{Start: p.Start, End: p.End}
+	}
+	return ranges
 }
 
 // Query represents an Earth Engine query that can be executed.
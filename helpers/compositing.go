@@ -2,8 +2,12 @@ package helpers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -37,18 +41,46 @@ type CompositeConfig struct {
 	Bands           []string             // Specific bands to composite
 	Scale           float64              // Resolution in meters
 	Region          *earthengine.Geometry // Optional region to composite
+	KeepQualityBand bool                 // Keep the band used to rank pixels (e.g. NDVI for greenest pixel)
+	MinNDVI         float64              // Mask pixels with lower NDVI before compositing (0 = no masking)
 }
 
 // CompositeResult contains the result of a compositing operation.
 type CompositeResult struct {
-	Image           *earthengine.Image
-	ObservationCount int       // Number of images used
-	DateRange        DateRange // Temporal range
-	Method          CompositeMethod
+	Image            *earthengine.Image `json:"-"`
+	ObservationCount int                `json:"observation_count"` // Number of images used
+	DateRange        DateRange          `json:"date_range"`        // Temporal range
+	Method           CompositeMethod    `json:"method"`
 }
 
 // AdvancedComposite creates an advanced composite using specified method.
 //
+// The collection is filtered by the dataset's scene cloud cover property
+// (CLOUDY_PIXEL_PERCENTAGE for Sentinel-2, CLOUD_COVER for Landsat), then each
+// image is cloud-masked using the dataset's quality band (SCL for Sentinel-2
+// surface reflectance, QA60 for other Sentinel-2 products, QA_PIXEL for
+// Landsat Collection 2). For other datasets, pixels are masked where CloudBand
+// is non-zero if CloudBand is set.
+//
+// Greenest pixel composites add an NDVI band to each image, using the
+// dataset's NIR and red bands, and take each pixel from the observation with
+// the highest NDVI. The NDVI band is dropped from the output unless
+// KeepQualityBand is set, in which case it is the last band, named "NDVI"
+// (so the collection must not already have a band of that name).
+//
+// When MinNDVI is set, pixels whose NDVI is below it are masked in each image
+// before compositing, so water, bare, and built-up pixels are left out of
+// the composite (masked, not zeroed, so they do not count toward areas).
+// With the greenest pixel method this masks exactly the pixels whose
+// greenest observation is below MinNDVI and leaves the rest unchanged.
+//
+// When Region is set, only images intersecting it are used, and each is
+// clipped to it before compositing so no computation is spent outside it.
+//
+// When Bands is set, the composite contains exactly those bands (plus the
+// quality band if KeepQualityBand is set). The returned ObservationCount is
+// the number of images remaining after cloud and region filtering.
+//
 // Example:
 //
 //	result, err := helpers.AdvancedComposite(ctx, client, collection,
@@ -58,10 +90,16 @@ type CompositeResult struct {
 //	        MinObservations: 5,
 //	    })
 func AdvancedComposite(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, config CompositeConfig) (*CompositeResult, error) {
-	_ = ctx
 	_ = client
 
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+
 	// Set defaults
+	if config.Method == "" {
+		config.Method = MedianComposite
+	}
 	if config.CloudThreshold == 0 {
 		config.CloudThreshold = 20
 	}
@@ -72,25 +110,290 @@ func AdvancedComposite(ctx context.Context, client *earthengine.Client, collecti
 		config.Scale = 30
 	}
 
-	// In a real implementation, this would:
-	// 1. Filter the collection based on config
-	// 2. Apply the selected compositing method
-	// 3. Return the composite image with metadata
+	dataset := collection.ID()
+
+	// Filter scenes by cloud cover and region
+	filtered := collection
+	if property := cloudCoverProperty(dataset); property != "" {
+		filtered = filtered.FilterMetadata(property, "less_than", config.CloudThreshold)
+	}
+	region := optionalRegion(config.Region)
+	if region != nil {
+		filtered = filtered.FilterBounds(region)
+	}
+
+	// Bands needed to build the composite
+	bands := config.Bands
+	var qualityBand string
+	switch config.Method {
+	case GreenestPixelComposite:
+		qualityBand = greennessBand
+	case QualityMosaicComposite:
+		if config.QualityBand == "" {
+			return nil, fmt.Errorf("quality band is required for %s composite", config.Method)
+		}
+		qualityBand = config.QualityBand
+	case PercentileComposite:
+		if config.Percentile < 0 || config.Percentile > 100 {
+			return nil, fmt.Errorf("percentile must be between 0 and 100")
+		}
+	}
+	if config.MinNDVI < -1 || config.MinNDVI > 1 {
+		return nil, fmt.Errorf("minimum NDVI must be between -1 and 1, got %v", config.MinNDVI)
+	}
+	if len(bands) > 0 && qualityBand != "" && !containsString(bands, qualityBand) {
+		bands = append(append([]string{}, bands...), qualityBand)
+	}
+
+	var nir, red string
+	if config.Method == GreenestPixelComposite || config.MinNDVI != 0 {
+		var err error
+		if nir, red, err = getBandNames(dataset); err != nil {
+			return nil, err
+		}
+	}
+
+	// Clip and mask clouds and sparse vegetation, add the greenness band if
+	// needed, and select bands per image
+	masked := filtered.Map(func(img *earthengine.Image) *earthengine.Image {
+		img = maskClouds(ClipToGeometry(img, region), dataset, config.CloudBand)
+		if config.Method == GreenestPixelComposite || config.MinNDVI != 0 {
+			ndvi := img.Select(nir, red).NormalizedDifference()
+			if config.MinNDVI != 0 {
+				vegetated := ndvi.Gte(config.MinNDVI)
+				img = img.UpdateMask(vegetated)
+				ndvi = ndvi.UpdateMask(vegetated)
+			}
+			if config.Method == GreenestPixelComposite {
+				img = img.AddBands(ndvi.Rename(greennessBand))
+			}
+		}
+		if len(bands) > 0 {
+			img = img.Select(bands...)
+		}
+		return img
+	})
+
+	// Apply the compositing method
+	composite, renameBands, err := applyCompositeMethod(masked, config.Method, config.Percentile, qualityBand)
+	if err != nil {
+		return nil, err
+	}
+
+	// Require a minimum number of clear observations per pixel
+	if config.MinObservations > 1 {
+		composite = composite.UpdateMask(masked.Count().Gte(float64(config.MinObservations)))
+	}
+
+	if len(bands) > 0 && renameBands {
+		composite = composite.Rename(bands...)
+	}
+	if config.Method == GreenestPixelComposite {
+		greenness := composite.Select(greennessBand)
+		composite = composite.RemoveBands(greennessBand)
+		if config.KeepQualityBand {
+			composite = composite.AddBands(greenness.Rename(keptGreennessBand))
+		}
+	} else if !config.KeepQualityBand && len(config.Bands) > 0 && len(bands) != len(config.Bands) {
+		composite = composite.Select(config.Bands...)
+	}
+
+	count, err := filtered.Size(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count images: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w with cloud cover below %.0f%%", ErrNoImages, config.CloudThreshold)
+	}
 
-	// For now, return a placeholder
 	result := &CompositeResult{
-		Image:            &earthengine.Image{},
-		ObservationCount: 10,
-		DateRange: DateRange{
-			Start: "2023-01-01",
-			End:   "2023-12-31",
-		},
-		Method: config.Method,
+		Image:            composite,
+		ObservationCount: count,
+		Method:           config.Method,
 	}
 
 	return result, nil
 }
 
+// applyCompositeMethod composites collection using method.
+//
+// suffixed reports whether the output bands carry a reducer suffix (e.g.
+// "_median") and need renaming back to the input band names.
+func applyCompositeMethod(collection *earthengine.ImageCollection, method CompositeMethod, percentile float64, qualityBand string) (composite *earthengine.Image, suffixed bool, err error) {
+	switch method {
+	case MedianComposite:
+		return collection.Reduce(earthengine.ReducerMedian()), true, nil
+	case MeanComposite:
+		return collection.Reduce(earthengine.ReducerMean()), true, nil
+	case MaxComposite:
+		return collection.Reduce(earthengine.ReducerMax()), true, nil
+	case MinComposite:
+		return collection.Reduce(earthengine.ReducerMin()), true, nil
+	case PercentileComposite:
+		return collection.Reduce(earthengine.ReducerPercentile(percentile)), true, nil
+	case MosaicComposite:
+		return collection.Mosaic(), false, nil
+	case MostRecentComposite:
+		// Mosaic draws later images on top, so sort oldest first
+		return collection.Sort("system:time_start", true).Mosaic(), false, nil
+	case GreenestPixelComposite, QualityMosaicComposite:
+		return collection.QualityMosaic(qualityBand), false, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported composite method: %s", method)
+	}
+}
+
+// ErrNoImages is returned by AdvancedComposite when no images remain after filtering.
+var ErrNoImages = errors.New("no images in collection")
+
+// greennessBand is the NDVI band added to each image for greenest pixel
+// composites. Its name is reserved so it cannot collide with a dataset band
+// such as MODIS NDVI; it is dropped after compositing, or renamed to
+// keptGreennessBand with KeepQualityBand.
+const greennessBand = "_greenness"
+
+// keptGreennessBand is the name of the greenness band in greenest pixel
+// composites with KeepQualityBand.
+const keptGreennessBand = "NDVI"
+
+// cloudCoverProperty returns the scene cloud cover metadata property for a dataset.
+func cloudCoverProperty(dataset string) string {
+	if def, ok := LookupDataset(dataset); ok && def.CloudCoverProperty != "" {
+		return def.CloudCoverProperty
+	}
+
+	switch {
+	case strings.HasPrefix(dataset, "COPERNICUS/S2"):
+		return "CLOUDY_PIXEL_PERCENTAGE"
+	case strings.HasPrefix(dataset, "LANDSAT/"):
+		return "CLOUD_COVER"
+	default:
+		return ""
+	}
+}
+
+// containsString reports whether values contains s.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// harmonizedBands are the common band names used by HarmonizedComposite.
+var harmonizedBands = []string{"blue", "green", "red", "nir", "swir1", "swir2"}
+
+// HarmonizedComposite creates a composite from Landsat 8, Landsat 9, and
+// Sentinel-2 surface reflectance combined into a single collection.
+//
+// Each image is cloud-masked, its bands are renamed to blue, green, red, nir,
+// swir1, and swir2, and its values are scaled to unitless reflectance (0-1)
+// before the collections are merged and composited with method. Percentile
+// and quality mosaic composites are not supported.
+//
+// Spectral caveats: the sensors' bands are not identical. Sentinel-2 B8 is a
+// broad NIR band, wider than Landsat's SR_B5, and no bandpass or BRDF
+// adjustment is applied, so small systematic differences remain between
+// sensors (products such as NASA's HLS correct for these). Sentinel-2 bands
+// are 10-20 m while Landsat is 30 m; choose the reduction scale accordingly.
+//
+// startDate and endDate (format "YYYY-MM-DD") are both included. The
+// returned ObservationCount is the number of images from all three sensors
+// intersecting bounds in the date range.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+//	result, err := helpers.HarmonizedComposite(ctx, client,
+//	    "2023-06-01", "2023-08-31", bounds, helpers.MedianComposite)
+//	nir := result.Image.Select("nir")
+func HarmonizedComposite(ctx context.Context, client *earthengine.Client, startDate, endDate string, bounds Bounds, method CompositeMethod) (*CompositeResult, error) {
+	_, end, err := DateRange{Start: startDate, End: endDate}.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid date range: %w", err)
+	}
+	// FilterDate's end is exclusive
+	until := end.AddDate(0, 0, 1).Format(dateLayout)
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+
+	if method == "" {
+		method = MedianComposite
+	}
+	if method == PercentileComposite || method == QualityMosaicComposite {
+		return nil, fmt.Errorf("%s composite is not supported for harmonized composites", method)
+	}
+
+	var merged *earthengine.ImageCollection
+	for _, dataset := range []string{landsat8DatasetID, landsat9DatasetID, sentinel2DatasetID} {
+		harmonized := client.ImageCollection(dataset).
+			FilterDate(startDate, until).
+			FilterBounds(region).
+			Map(func(img *earthengine.Image) *earthengine.Image {
+				return harmonizeImage(client, img.Clip(region), dataset)
+			})
+
+		if merged == nil {
+			merged = harmonized
+		} else {
+			merged = merged.Merge(harmonized)
+		}
+	}
+
+	collection := merged
+	if method == GreenestPixelComposite {
+		collection = merged.Map(func(img *earthengine.Image) *earthengine.Image {
+			return img.AddBands(img.Select("nir", "red").NormalizedDifference().Rename(greennessBand))
+		})
+	}
+
+	composite, suffixed, err := applyCompositeMethod(collection, method, 0, greennessBand)
+	if err != nil {
+		return nil, err
+	}
+	if suffixed {
+		composite = composite.Rename(harmonizedBands...)
+	}
+	if method == GreenestPixelComposite {
+		composite = composite.Select(harmonizedBands...)
+	}
+
+	count, err := merged.Size(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count images: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w between %s and %s", ErrNoImages, startDate, endDate)
+	}
+
+	return &CompositeResult{
+		Image:            composite,
+		ObservationCount: count,
+		DateRange:        DateRange{Start: startDate, End: endDate},
+		Method:           method,
+	}, nil
+}
+
+// harmonizeImage cloud-masks img and converts it to the harmonizedBands scheme.
+func harmonizeImage(client *earthengine.Client, img *earthengine.Image, dataset string) *earthengine.Image {
+	img = maskClouds(img, dataset, "")
+
+	scale, offset := getReflectanceScaling(dataset)
+	reflectance := img.Select(getBandNamesForHarmonized(dataset)...).Multiply(client.Constant(scale))
+	if offset != 0 {
+		reflectance = reflectance.Add(client.Constant(offset))
+	}
+
+	// Add the bands to the original image and select them, rather than
+	// returning the math result directly, so image properties such as
+	// system:time_start are kept
+	return img.AddBands(reflectance.Rename(harmonizedBands...)).Select(harmonizedBands...)
+}
+
 // QualityMosaic creates a quality mosaic composite.
 //
 // Pixels are selected based on a quality score band, with higher quality
@@ -115,7 +418,9 @@ func QualityMosaic(ctx context.Context, client *earthengine.Client, collection *
 // CreateGreenestPixelComposite creates a composite selecting the greenest pixel.
 //
 // For each pixel, selects the observation with the highest NDVI value.
-// This is useful for vegetation mapping and phenology studies.
+// This is useful for vegetation mapping and phenology studies. The output
+// contains the collection's original bands; use AdvancedComposite with
+// KeepQualityBand to also keep the NDVI band.
 //
 // Example:
 //
@@ -170,7 +475,285 @@ func CreateMostRecentComposite(ctx context.Context, client *earthengine.Client,
 	return result.Image, nil
 }
 
-// SeasonalComposite creates composites for different seasons.
+// NDVIComposite composites the imagery intersecting bounds from startDate
+// through endDate (format "YYYY-MM-DD", both included) with method and
+// returns a single-band image of its NDVI, named "NDVI", ready to sample or
+// reduce.
+//
+// The composite is built by AdvancedComposite from the dataset's NIR and red
+// bands only, so the same cloud filtering and masking apply. The dataset is
+// Landsat 8 unless set with an option such as Sentinel2; CloudMask sets the
+// scene cloud cover threshold (default 20%). Percentile and quality mosaic
+// composites are not supported. Pixels where NIR and red are both zero are
+// masked.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+//	ndvi, err := helpers.NDVIComposite(ctx, client, "2023-06-01", "2023-08-31",
+//	    bounds, helpers.MedianComposite, helpers.Sentinel2())
+//	stats, err := helpers.CalculateZonalStats(ctx, client, ndvi, zones, config)
+func NDVIComposite(ctx context.Context, client *earthengine.Client, startDate, endDate string, bounds Bounds, method CompositeMethod, opts ...ImageryOption) (*earthengine.Image, error) {
+	_, end, err := DateRange{Start: startDate, End: endDate}.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid date range: %w", err)
+	}
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+	if method == PercentileComposite || method == QualityMosaicComposite {
+		return nil, fmt.Errorf("%s composite is not supported for NDVI composites", method)
+	}
+
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	config := CompositeConfig{
+		Method: method,
+		Region: &region,
+	}
+	nir, red, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
+	config.Bands = []string{nir, red}
+	if cfg.cloudCover != nil {
+		config.CloudThreshold = *cfg.cloudCover
+	}
+
+	// FilterDate's end is exclusive
+	until := end.AddDate(0, 0, 1).Format(dateLayout)
+	collection := client.ImageCollection(cfg.dataset).FilterDate(startDate, until)
+	result, err := AdvancedComposite(ctx, client, collection, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return safeNormalizedDifference(result.Image.Select(nir, red)).Rename("NDVI"), nil
+}
+
+// PriorityMosaic fills each pixel from the first of sources, in priority
+// order, that has valid (unmasked) data there. Unlike MostRecentComposite,
+// the order is the caller's rather than by date, so a preferred dataset can
+// fall back to others where it has gaps or no coverage.
+//
+// Sources must share band names, in the same order, and units; a band
+// mismatch returns an error. Pixels are not rescaled, so mixing, say,
+// reflectance scaled by 10000 with unscaled reflectance gives wrong values.
+//
+// Example:
+//
+//	// USGS 3DEP 10m where available (USA), SRTM 30m elsewhere
+//	elevation, err := helpers.PriorityMosaic(ctx, client, []*earthengine.Image{
+//	    client.Image("USGS/3DEP/10m"),
+//	    client.Image("USGS/SRTMGL1_003"),
+//	})
+func PriorityMosaic(ctx context.Context, client *earthengine.Client, sources []*earthengine.Image) (*earthengine.Image, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one source image is required")
+	}
+	for i, source := range sources {
+		if source == nil {
+			return nil, fmt.Errorf("source %d is nil", i)
+		}
+	}
+	if len(sources) == 1 {
+		return sources[0], nil
+	}
+
+	want, err := sources[0].BandNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("source 0: %w", err)
+	}
+	for i, source := range sources[1:] {
+		bands, err := source.BandNames(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("source %d: %w", i+1, err)
+		}
+		if strings.Join(bands, ",") != strings.Join(want, ",") {
+			return nil, fmt.Errorf("source %d has bands %v, want %v to match source 0", i+1, bands, want)
+		}
+	}
+
+	// Mosaic puts the last image on top, so add sources lowest priority first
+	reversed := make([]*earthengine.Image, len(sources))
+	for i, source := range sources {
+		reversed[len(sources)-1-i] = source
+	}
+	return client.ImageCollectionFromImages(reversed...).Mosaic(), nil
+}
+
+// Season is a named range of days in the year, such as summer. A season
+// whose end is before its start in the calendar, such as a December to
+// February winter, runs into the next year.
+type Season struct {
+	Name       string
+	StartMonth time.Month
+	StartDay   int // First day; 0 means the 1st
+	EndMonth   time.Month
+	EndDay     int // Last day, inclusive; 0 means the end of EndMonth
+}
+
+// dates returns the first day of the season starting in year, its last day,
+// and the day after it, as "YYYY-MM-DD".
+func (s Season) dates(year int) (start, end, until string) {
+	startDay := s.StartDay
+	if startDay == 0 {
+		startDay = 1
+	}
+	first := time.Date(year, s.StartMonth, startDay, 0, 0, 0, 0, time.UTC)
+
+	endYear := year
+	if s.EndMonth < s.StartMonth || (s.EndMonth == s.StartMonth && s.EndDay != 0 && s.EndDay < startDay) {
+		endYear++
+	}
+	var last time.Time
+	if s.EndDay == 0 {
+		// Day 0 of the next month is the last day of EndMonth
+		last = time.Date(endYear, s.EndMonth+1, 0, 0, 0, 0, 0, time.UTC)
+	} else {
+		last = time.Date(endYear, s.EndMonth, s.EndDay, 0, 0, 0, 0, time.UTC)
+	}
+
+	return first.Format(dateLayout), last.Format(dateLayout), last.AddDate(0, 0, 1).Format(dateLayout)
+}
+
+// SeasonDefinition is the set of seasons seasonal composites are made for.
+type SeasonDefinition []Season
+
+// Predefined season definitions. Meteorological seasons are whole months;
+// astronomical seasons run between the approximate dates of the equinoxes
+// and solstices.
+var (
+	// NorthernSeasons are the northern-hemisphere meteorological seasons,
+	// the default.
+	NorthernSeasons = SeasonDefinition{
+		{Name: "spring", StartMonth: time.March, EndMonth: time.May},
+		{Name: "summer", StartMonth: time.June, EndMonth: time.August},
+		{Name: "fall", StartMonth: time.September, EndMonth: time.November},
+		{Name: "winter", StartMonth: time.December, EndMonth: time.February},
+	}
+
+	// SouthernSeasons are the southern-hemisphere meteorological seasons.
+	SouthernSeasons = SeasonDefinition{
+		{Name: "fall", StartMonth: time.March, EndMonth: time.May},
+		{Name: "winter", StartMonth: time.June, EndMonth: time.August},
+		{Name: "spring", StartMonth: time.September, EndMonth: time.November},
+		{Name: "summer", StartMonth: time.December, EndMonth: time.February},
+	}
+
+	// NorthernAstronomicalSeasons are the northern-hemisphere astronomical
+	// seasons.
+	NorthernAstronomicalSeasons = SeasonDefinition{
+		{Name: "spring", StartMonth: time.March, StartDay: 20, EndMonth: time.June, EndDay: 20},
+		{Name: "summer", StartMonth: time.June, StartDay: 21, EndMonth: time.September, EndDay: 21},
+		{Name: "fall", StartMonth: time.September, StartDay: 22, EndMonth: time.December, EndDay: 20},
+		{Name: "winter", StartMonth: time.December, StartDay: 21, EndMonth: time.March, EndDay: 19},
+	}
+
+	// SouthernAstronomicalSeasons are the southern-hemisphere astronomical
+	// seasons.
+	SouthernAstronomicalSeasons = SeasonDefinition{
+		{Name: "fall", StartMonth: time.March, StartDay: 20, EndMonth: time.June, EndDay: 20},
+		{Name: "winter", StartMonth: time.June, StartDay: 21, EndMonth: time.September, EndDay: 21},
+		{Name: "spring", StartMonth: time.September, StartDay: 22, EndMonth: time.December, EndDay: 20},
+		{Name: "summer", StartMonth: time.December, StartDay: 21, EndMonth: time.March, EndDay: 19},
+	}
+)
+
+// SeasonsForLatitude returns the meteorological seasons of the hemisphere
+// containing lat: SouthernSeasons south of the equator, else
+// NorthernSeasons.
+//
+// Example:
+//
+//	lat, _ := bounds.Center()
+//	seasons, err := helpers.SeasonalCompositeWithSeasons(ctx, client, collection, 2023,
+//	    helpers.SeasonsForLatitude(lat))
+func SeasonsForLatitude(lat float64) SeasonDefinition {
+	if lat < 0 {
+		return SouthernSeasons
+	}
+	return NorthernSeasons
+}
+
+// Validate checks that the definition has at least one season, that season
+// names are non-empty and unique, and that every start and end is a valid
+// day of the year.
+func (d SeasonDefinition) Validate() error {
+	if len(d) == 0 {
+		return fmt.Errorf("season definition has no seasons")
+	}
+
+	names := make(map[string]bool, len(d))
+	for _, s := range d {
+		if s.Name == "" {
+			return fmt.Errorf("season name cannot be empty")
+		}
+		if names[s.Name] {
+			return fmt.Errorf("duplicate season %q", s.Name)
+		}
+		names[s.Name] = true
+
+		for _, day := range []struct {
+			month time.Month
+			day   int
+		}{{s.StartMonth, s.StartDay}, {s.EndMonth, s.EndDay}} {
+			if day.month < time.January || day.month > time.December {
+				return fmt.Errorf("season %q has invalid month %d", s.Name, day.month)
+			}
+			// Days are checked against a leap year so February 29 is allowed
+			last := time.Date(2024, day.month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			if day.day < 0 || day.day > last {
+				return fmt.Errorf("season %q has invalid day %d for %s", s.Name, day.day, day.month)
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the season with the given name.
+func (d SeasonDefinition) lookup(name string) (Season, error) {
+	names := make([]string, len(d))
+	for i, s := range d {
+		if s.Name == name {
+			return s, nil
+		}
+		names[i] = s.Name
+	}
+	return Season{}, fmt.Errorf("unknown season %q, want one of %s", name, strings.Join(names, ", "))
+}
+
+// seasonComposite creates a median composite of the collection's images in
+// the season starting in year.
+func seasonComposite(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, year int, s Season) (*CompositeResult, error) {
+	start, end, until := s.dates(year)
+	result, err := AdvancedComposite(ctx, client, collection.FilterDate(start, until), CompositeConfig{
+		Method: MedianComposite,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s %d composite: %w", s.Name, year, err)
+	}
+
+	result.DateRange = DateRange{Start: start, End: end}
+	return result, nil
+}
+
+// SeasonalComposite creates a median composite for each season of year:
+// spring (March-May), summer (June-August), fall (September-November), and
+// winter (December of year through February of the next year). These are
+// the northern-hemisphere meteorological seasons, NorthernSeasons; use
+// SeasonalCompositeWithSeasons for the southern hemisphere or other season
+// definitions.
+//
+// Composites are made as by AdvancedComposite with default settings, so
+// scenes are filtered by cloud cover and cloud-masked. Seasons with no
+// images are left out of the result.
 //
 // Example:
 //
@@ -178,119 +761,292 @@ func CreateMostRecentComposite(ctx context.Context, client *earthengine.Client,
 //	spring := seasons["spring"]
 //	summer := seasons["summer"]
 func SeasonalComposite(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, year int) (map[string]*earthengine.Image, error) {
-	_ = ctx
-	_ = client
-	_ = collection
-	_ = year
-
-	// Return mock data for testing
-	return map[string]*earthengine.Image{
-		"spring": &earthengine.Image{},
-		"summer": &earthengine.Image{},
-		"fall":   &earthengine.Image{},
-		"winter": &earthengine.Image{},
-	}, nil
+	return SeasonalCompositeWithSeasons(ctx, client, collection, year, NorthernSeasons)
+}
 
-	/* Actual implementation would be:
-	seasons := map[string]struct {
-		start string
-		end   string
-	}{
-		"spring": {fmt.Sprintf("%d-03-01", year), fmt.Sprintf("%d-05-31", year)},
-		"summer": {fmt.Sprintf("%d-06-01", year), fmt.Sprintf("%d-08-31", year)},
-		"fall":   {fmt.Sprintf("%d-09-01", year), fmt.Sprintf("%d-11-30", year)},
-		"winter": {fmt.Sprintf("%d-12-01", year), fmt.Sprintf("%d-02-28", year+1)},
+// SeasonalCompositeWithSeasons is like SeasonalComposite but composites the
+// given seasons. Each season is the one starting in year, so a season that
+// crosses the new year, such as the southern summer, ends in the next year.
+//
+// Example:
+//
+//	// Southern-hemisphere seasons; "summer" is December 2023-February 2024
+//	seasons, err := helpers.SeasonalCompositeWithSeasons(ctx, client, collection, 2023,
+//	    helpers.SouthernSeasons)
+func SeasonalCompositeWithSeasons(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, year int, seasons SeasonDefinition) (map[string]*earthengine.Image, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if err := seasons.Validate(); err != nil {
+		return nil, err
 	}
 
 	result := make(map[string]*earthengine.Image)
-
-	for name, dates := range seasons {
-		// Filter collection to season
-		seasonal := collection.FilterDate(dates.start, dates.end)
-
-		// Create median composite for the season
-		composite, err := AdvancedComposite(ctx, client, seasonal, CompositeConfig{
-			Method: MedianComposite,
-		})
+	for _, s := range seasons {
+		composite, err := seasonComposite(ctx, client, collection, year, s)
+		if errors.Is(err, ErrNoImages) {
+			continue // Skip seasons with no data
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to create %s composite: %w", name, err)
+			return nil, err
 		}
-
-		result[name] = composite.Image
+		result[s.Name] = composite.Image
 	}
 
 	return result, nil
-	*/
+}
+
+// SeasonalDifferenceResult is the per-pixel difference between two seasonal
+// composites of the same year.
+type SeasonalDifferenceResult struct {
+	Image   *earthengine.Image  `json:"-"` // SeasonA minus SeasonB, in a band named after the compared band
+	Year    int                 `json:"year"`
+	SeasonA DateRange           `json:"season_a"`
+	SeasonB DateRange           `json:"season_b"`
+	Stats   CompositeDifference `json:"stats"`
+}
+
+// SeasonalDifference composites two seasons of year as SeasonalComposite
+// does and subtracts band of seasonB from seasonA, pixel by pixel. Season
+// names are "spring", "summer", "fall", and "winter", as defined by
+// NorthernSeasons; use SeasonalDifferenceWithSeasons for other season
+// definitions.
+//
+// Summary statistics are computed as by CompareComposites, which opts
+// configure; pass CompareRegion for collections that are not filtered to a
+// small area. band must be a band of the collection's images, so add index
+// bands such as NDVI to the collection before calling.
+//
+// Summer minus winter NDVI is high where vegetation is deciduous and near
+// zero for evergreen vegetation and bare ground.
+//
+// Example:
+//
+//	// Deciduous vegetation: summer minus winter NDVI
+//	withNDVI := collection.Map(func(img *earthengine.Image) *earthengine.Image {
+//	    return img.AddBands(img.Select("B8", "B4").NormalizedDifference().Rename("NDVI"))
+//	})
+//	greenUp, err := helpers.SeasonalDifference(ctx, client, withNDVI, 2023, "summer", "winter", "NDVI",
+//	    helpers.CompareRegion(region),
+//	    helpers.ChangeThreshold(0.3))
+//	fmt.Printf("%.1f%% of the area greens up\n", greenUp.Stats.PercentChanged)
+func SeasonalDifference(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, year int, seasonA, seasonB string, band string, opts ...CompareOption) (*SeasonalDifferenceResult, error) {
+	return SeasonalDifferenceWithSeasons(ctx, client, collection, year, NorthernSeasons, seasonA, seasonB, band, opts...)
+}
+
+// SeasonalDifferenceWithSeasons is like SeasonalDifference but looks up
+// seasonA and seasonB in seasons.
+//
+// Example:
+//
+//	// Southern-hemisphere green-up: December 2023-February 2024 minus
+//	// June-August 2023
+//	greenUp, err := helpers.SeasonalDifferenceWithSeasons(ctx, client, withNDVI, 2023,
+//	    helpers.SouthernSeasons, "summer", "winter", "NDVI",
+//	    helpers.CompareRegion(region))
+func SeasonalDifferenceWithSeasons(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, year int, seasons SeasonDefinition, seasonA, seasonB string, band string, opts ...CompareOption) (*SeasonalDifferenceResult, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if band == "" {
+		return nil, fmt.Errorf("band name cannot be empty")
+	}
+	if err := seasons.Validate(); err != nil {
+		return nil, err
+	}
+	a, err := seasons.lookup(seasonA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := seasons.lookup(seasonB)
+	if err != nil {
+		return nil, err
+	}
+	if a == b {
+		return nil, fmt.Errorf("seasons must differ, got %s twice", a.Name)
+	}
+
+	compositeA, err := seasonComposite(ctx, client, collection, year, a)
+	if err != nil {
+		return nil, err
+	}
+	compositeB, err := seasonComposite(ctx, client, collection, year, b)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := CompareComposites(ctx, client, compositeA.Image, compositeB.Image, band, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SeasonalDifferenceResult{
+		Image:   compositeA.Image.Select(band).Subtract(compositeB.Image.Select(band)).Rename(band),
+		Year:    year,
+		SeasonA: compositeA.DateRange,
+		SeasonB: compositeB.DateRange,
+		Stats:   *stats,
+	}, nil
 }
 
 // MultiTemporalComposite creates multiple composites over time periods.
 //
+// The inclusive range startDate..endDate (format "YYYY-MM-DD") is split into
+// "day", "week", "month", or "year" intervals, and a median composite is
+// created for each. Months and years follow the calendar. Intervals with no
+// images are skipped. Each result's DateRange is the interval it covers.
+//
 // Example:
 //
 //	// Monthly composites for 2023
 //	monthly, err := helpers.MultiTemporalComposite(ctx, client, collection,
 //	    "2023-01-01", "2023-12-31", "month")
 func MultiTemporalComposite(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, startDate, endDate, interval string) ([]*CompositeResult, error) {
-	_ = ctx
-	_ = client
-	_ = collection
-	_ = startDate
-	_ = endDate
-	_ = interval
-
-	// Return mock data for testing
-	return []*CompositeResult{
-		{Image: &earthengine.Image{}, Method: MedianComposite},
-		{Image: &earthengine.Image{}, Method: MedianComposite},
-		{Image: &earthengine.Image{}, Method: MedianComposite},
-	}, nil
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
 
-	/* Actual implementation would be:
-	// Parse interval (day, week, month, year)
-	periods := generatePeriods(startDate, endDate, interval)
+	periods, err := generatePeriods(startDate, endDate, interval)
+	if err != nil {
+		return nil, err
+	}
 
 	results := make([]*CompositeResult, 0, len(periods))
 
-	for _, period := range periods {
+	for _, p := range periods {
 		// Filter to period
-		filtered := collection.FilterDate(period.Start, period.End)
+		filtered := collection.FilterDate(p.Start, p.Until)
 
 		// Create composite
-		composite, err := AdvancedComposite(ctx, client, filtered, CompositeConfig{
+		result, err := AdvancedComposite(ctx, client, filtered, CompositeConfig{
 			Method: MedianComposite,
 		})
-		if err != nil {
+		if errors.Is(err, ErrNoImages) {
 			continue // Skip periods with no data
 		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create composite for %s to %s: %w", p.Start, p.End, err)
+		}
 
-		results = append(results, composite)
+		result.DateRange = DateRange{Start: p.Start, End: p.End}
+		results = append(results, result)
 	}
 
 	return results, nil
-	*/
+}
+
+// MosaicByDate mosaics the images acquired on the same date, returning a
+// collection with one image per date, sorted by date.
+//
+// Satellites image a swath as several adjacent scenes (tiles) with nearly
+// the same time; mosaicking them first keeps a time series from counting
+// each tile as a separate observation. Dates are UTC days. Each mosaic's
+// system:time_start is that of the earliest image on its date.
+//
+// Example:
+//
+//	daily, err := helpers.MosaicByDate(ctx, client,
+//	    client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+//	        FilterDate("2023-06-01", "2023-09-01").
+//	        FilterBounds(region))
+func MosaicByDate(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection) (*earthengine.ImageCollection, error) {
+	_ = client
+
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+
+	times, err := acquisitionTimes(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acquisition times: %w", err)
+	}
+
+	dates, first := acquisitionDates(times)
+	mosaics := make([]*earthengine.Image, len(dates))
+	for i, date := range dates {
+		mosaics[i] = dateMosaic(collection, first[date]).
+			Set("system:time_start", first[date].UnixMilli())
+	}
+
+	return collection.FromImages(mosaics...), nil
+}
+
+// acquisitionDates returns the distinct UTC dates of times in order, and the
+// earliest time on each date.
+func acquisitionDates(times []time.Time) (dates []string, first map[string]time.Time) {
+	first = make(map[string]time.Time)
+	for _, t := range times {
+		date := t.Format("2006-01-02")
+		if current, ok := first[date]; !ok || t.Before(current) {
+			first[date] = t
+		}
+	}
+
+	dates = make([]string, 0, len(first))
+	for date := range first {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates, first
+}
+
+// dateMosaic mosaics the images of collection acquired on the UTC date of t.
+func dateMosaic(collection *earthengine.ImageCollection, t time.Time) *earthengine.Image {
+	day := t.Truncate(24 * time.Hour)
+	return collection.
+		FilterDate(day.Format("2006-01-02"), day.AddDate(0, 0, 1).Format("2006-01-02")).
+		Mosaic()
 }
 
 // CompositeWithOutlierRemoval creates a composite after removing outliers.
 //
-// Uses statistical methods to identify and remove outlier values before
-// compositing, resulting in cleaner composites.
+// Uses per-pixel sigma clipping: the mean and standard deviation of each band
+// are computed across the collection, observations more than stdDevThreshold
+// standard deviations from the mean are masked, and the remaining values are
+// median-composited. This removes cloud and shadow spikes that slip past
+// scene-level filtering.
+//
+// Clipping is repeated maxIterations times, recomputing the statistics from
+// the remaining observations on each pass, since a single pass often leaves
+// residual outliers. As with ImageCollection.Reduce, output band names have a
+// "_median" suffix.
 //
 // Example:
 //
 //	clean, err := helpers.CompositeWithOutlierRemoval(ctx, client, collection,
-//	    3.0) // Remove values > 3 standard deviations
-func CompositeWithOutlierRemoval(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, stdDevThreshold float64) (*earthengine.Image, error) {
+//	    3.0, // Remove values > 3 standard deviations
+//	    2)   // Two clipping passes
+func CompositeWithOutlierRemoval(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, stdDevThreshold float64, maxIterations int) (*earthengine.Image, error) {
 	_ = ctx
 	_ = client
-	_ = collection
-	_ = stdDevThreshold
 
-	// In a real implementation:
-	// 1. Calculate mean and stddev for each pixel
-	// 2. Mask values beyond threshold
-	// 3. Create median composite of remaining values
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if stdDevThreshold <= 0 {
+		return nil, fmt.Errorf("standard deviation threshold must be positive")
+	}
+	if maxIterations < 1 {
+		return nil, fmt.Errorf("max iterations must be at least 1")
+	}
 
-	return &earthengine.Image{}, nil
+	clipped := collection
+	for i := 0; i < maxIterations; i++ {
+		mean := clipped.Reduce(earthengine.ReducerMean())
+		stdDev := clipped.Reduce(earthengine.ReducerStdDev())
+
+		clipped = clipped.Map(func(img *earthengine.Image) *earthengine.Image {
+			// Where every observation is equal the standard deviation is 0
+			// and there are no outliers: mask the division there, as
+			// SafeDivide does, then unmask it to z = 0 so the pixel is kept
+			z := img.Subtract(mean).Abs().Divide(stdDev).
+				UpdateMask(stdDev.Neq(0)).
+				Unmask(0)
+			return img.UpdateMask(z.Lte(stdDevThreshold))
+		})
+	}
+
+	return clipped.Reduce(earthengine.ReducerMedian()), nil
 }
 
 // PixelCompositeStats calculates per-pixel statistics across a collection.
@@ -339,106 +1095,354 @@ func CompositeQualityMask(ctx context.Context, client *earthengine.Client, colle
 
 // Helper functions
 
+// period is a date interval produced by generatePeriods.
 type period struct {
-	Start string
-	End   string
+	Start string // First day, "YYYY-MM-DD"
+	End   string // Last day (inclusive), "YYYY-MM-DD"
+	Until string // Day after End, for use as the exclusive end of FilterDate
 }
 
-func generatePeriods(startDate, endDate, interval string) []period {
-	// Simplified implementation
-	// In production, properly parse dates and generate intervals
-	periods := []period{
-		{Start: startDate, End: endDate},
+// generatePeriods splits the inclusive range startDate..endDate into
+// consecutive intervals of "day", "week", "month", or "year".
+//
+// Weeks are 7-day intervals counted from startDate. Months and years follow
+// the calendar, so the first and last periods may be partial.
+func generatePeriods(startDate, endDate, interval string) ([]period, error) {
+	start, end, err := DateRange{Start: startDate, End: endDate}.Parse()
+	if err != nil {
+		return nil, err
 	}
 
+	// next returns the first day of the period following the one containing t
+	var next func(t time.Time) time.Time
 	switch interval {
+	case "day":
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case "week":
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
 	case "month":
-		// Would generate monthly periods
-		periods = []period{
-			{Start: "2023-01-01", End: "2023-01-31"},
-			{Start: "2023-02-01", End: "2023-02-28"},
-			// ... etc
+		next = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC) }
+	case "year":
+		next = func(t time.Time) time.Time { return time.Date(t.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC) }
+	default:
+		return nil, fmt.Errorf("unsupported interval %q (use day, week, month, or year)", interval)
+	}
+
+	var periods []period
+	for t := start; !t.After(end); {
+		until := next(t)
+		last := until.AddDate(0, 0, -1)
+		if last.After(end) {
+			last = end
+			until = end.AddDate(0, 0, 1)
 		}
-	case "week":
-		// Would generate weekly periods
-	case "day":
-		// Would generate daily periods
+
+		periods = append(periods, period{
+			Start: t.Format("2006-01-02"),
+			End:   last.Format("2006-01-02"),
+			Until: until.Format("2006-01-02"),
+		})
+		t = until
 	}
 
-	return periods
+	return periods, nil
 }
 
-// CompositeMetrics calculates quality metrics for a composite.
+// CompositeMetrics describes the quality of a composite.
 type CompositeMetrics struct {
-	MeanObservations   float64
-	MedianObservations float64
-	MinObservations    int
-	MaxObservations    int
-	Coverage           float64 // Percentage of area with valid data
-	CloudFreePixels    float64 // Percentage of cloud-free pixels
+	MeanObservations   float64 `json:"mean_observations"`   // Clear observations per pixel
+	MedianObservations float64 `json:"median_observations"` // Clear observations per pixel
+	MinObservations    int     `json:"min_observations"`
+	MaxObservations    int     `json:"max_observations"`
+	Coverage           float64 `json:"coverage"`          // Fraction of the region with valid data (0-1)
+	CloudFreePixels    float64 `json:"cloud_free_pixels"` // Fraction of observations left after cloud masking (0-1)
+}
+
+// MetricsOption configures CalculateCompositeMetrics.
+type MetricsOption func(*metricsConfig)
+
+// metricsConfig holds the CalculateCompositeMetrics settings.
+type metricsConfig struct {
+	region     earthengine.Geometry
+	collection *earthengine.ImageCollection
+	band       string
+	scale      float64
+}
+
+// MetricsRegion measures the composite within region instead of its
+// footprint.
+func MetricsRegion(region earthengine.Geometry) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.region = region
+	}
+}
+
+// MetricsCollection sets the collection the composite was built from, before
+// cloud masking, so per-pixel observation counts and the cloud-free fraction
+// can be computed. Without it those metrics are zero.
+func MetricsCollection(collection *earthengine.ImageCollection) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.collection = collection
+	}
 }
 
-// CalculateCompositeMetrics analyzes a composite's quality.
+// MetricsBand sets the band whose mask defines a valid pixel. By default a
+// pixel must be valid in every band of the composite, and observations are
+// counted in the collection's NIR band.
+func MetricsBand(band string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.band = band
+	}
+}
+
+// MetricsScale sets the resolution in meters the metrics are computed at.
+// The default is 30 m.
+func MetricsScale(meters float64) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.scale = meters
+	}
+}
+
+// CalculateCompositeMetrics measures a composite's quality: the fraction of
+// the region it has valid pixels for and, given the source collection with
+// MetricsCollection, how many clear observations each pixel was drawn from.
+//
+// Observations are counted per pixel after masking clouds with the
+// dataset's quality band, as AdvancedComposite does; CloudFreePixels is the
+// share of all observations in the region that survived the cloud mask.
+// Pass the collection with the same date, cloud cover, and region filters
+// used to build the composite so the counts match it.
+//
+// By default the metrics cover the composite's footprint, at 30 m. Use
+// MetricsRegion for composites of whole collections, whose footprint is
+// unbounded.
 //
 // Example:
 //
-//	metrics, err := helpers.CalculateCompositeMetrics(ctx, client, composite)
-//	fmt.Printf("Coverage: %.1f%%\n", metrics.Coverage*100)
-func CalculateCompositeMetrics(ctx context.Context, client *earthengine.Client, composite *earthengine.Image) (*CompositeMetrics, error) {
-	_ = ctx
+//	metrics, err := helpers.CalculateCompositeMetrics(ctx, client, composite,
+//	    helpers.MetricsRegion(region),
+//	    helpers.MetricsCollection(collection))
+//	fmt.Printf("Coverage: %.1f%%, %.1f clear observations per pixel\n",
+//	    metrics.Coverage*100, metrics.MeanObservations)
+func CalculateCompositeMetrics(ctx context.Context, client *earthengine.Client, composite *earthengine.Image, opts ...MetricsOption) (*CompositeMetrics, error) {
 	_ = client
-	_ = composite
 
-	// In a real implementation:
-	// 1. Calculate observation count per pixel
-	// 2. Calculate coverage statistics
-	// 3. Return metrics
-
-	return &CompositeMetrics{
-		MeanObservations:   10.5,
-		MedianObservations: 10.0,
-		MinObservations:    5,
-		MaxObservations:    15,
-		Coverage:           0.95,
-		CloudFreePixels:    0.92,
-	}, nil
+	if composite == nil {
+		return nil, fmt.Errorf("composite cannot be nil")
+	}
+
+	cfg := &metricsConfig{scale: defaultImageryScale}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", cfg.scale)
+	}
+
+	// 1 where the composite is valid in every measured band, 0 elsewhere
+	valid := composite
+	if cfg.band != "" {
+		valid = composite.Select(cfg.band)
+	}
+	valid = valid.Mask().Reduce(earthengine.ReducerMin()).Rename("valid")
+
+	image := valid
+	if cfg.collection != nil {
+		dataset := cfg.collection.ID()
+		band := cfg.band
+		if band == "" {
+			nir, err := getNIRBand(dataset)
+			if err != nil {
+				return nil, err
+			}
+			band = nir
+		}
+
+		// Observations per pixel before and after cloud masking
+		total := cfg.collection.Select(band).Count().Rename("total")
+		clear := maskCollectionClouds(cfg.collection, dataset).Select(band).Count().Rename("clear")
+		image = image.AddBands(clear).AddBands(total)
+	}
+
+	stats, err := image.ReduceRegion(cfg.region,
+		earthengine.ReducerCombine(
+			earthengine.ReducerMean(),
+			earthengine.ReducerMedian(),
+			earthengine.ReducerMin(),
+			earthengine.ReducerMax(),
+			earthengine.ReducerSum(),
+		),
+		earthengine.Scale(cfg.scale),
+		earthengine.MaxPixels(1e9),
+	).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate composite metrics: %w", err)
+	}
+
+	// The mean of the validity band is the fraction of valid pixels
+	coverage, ok := stats["valid_mean"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("composite has no pixels in the region")
+	}
+	metrics := &CompositeMetrics{Coverage: coverage}
+	if cfg.collection == nil {
+		return metrics, nil
+	}
+
+	// Counts are null where the collection has no images
+	stat := func(key string) float64 {
+		value, _ := stats[key].(float64)
+		return value
+	}
+	metrics.MeanObservations = stat("clear_mean")
+	metrics.MedianObservations = stat("clear_median")
+	metrics.MinObservations = int(math.Round(stat("clear_min")))
+	metrics.MaxObservations = int(math.Round(stat("clear_max")))
+	if total := stat("total_sum"); total > 0 {
+		metrics.CloudFreePixels = stat("clear_sum") / total
+	}
+
+	return metrics, nil
+}
+
+// CompositeDifference summarizes the pixel differences between two
+// composites, composite1 minus composite2.
+type CompositeDifference struct {
+	MeanDifference   float64 `json:"mean_difference"`
+	MedianDifference float64 `json:"median_difference"`
+	StdDevDifference float64 `json:"std_dev_difference"`
+	MaxDifference    float64 `json:"max_difference"`  // Largest absolute difference
+	PercentChanged   float64 `json:"percent_changed"` // Percentage of pixels whose absolute difference exceeds the threshold
+	Threshold        float64 `json:"threshold"`       // Change threshold used for PercentChanged
+}
+
+// defaultChangeThreshold is the absolute difference above which
+// CompareComposites counts a pixel as changed, suited to index bands such as
+// NDVI.
+const defaultChangeThreshold = 0.1
+
+// CompareOption configures CompareComposites.
+type CompareOption func(*compareConfig)
+
+// compareConfig holds the CompareComposites settings.
+type compareConfig struct {
+	region    earthengine.Geometry
+	threshold float64
+	scale     float64
+}
+
+// CompareRegion compares the composites within region instead of their
+// shared footprint.
+func CompareRegion(region earthengine.Geometry) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.region = region
+	}
+}
+
+// ChangeThreshold sets the absolute difference above which a pixel counts
+// as changed in PercentChanged. The default, 0.1, suits index bands such as
+// NDVI; use a larger value for reflectance or other bands.
+func ChangeThreshold(threshold float64) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.threshold = threshold
+	}
+}
+
+// CompareScale sets the resolution in meters the composites are compared
+// at. The default is 30 m.
+func CompareScale(meters float64) CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.scale = meters
+	}
 }
 
-// CompareComposites compares two composites pixel-by-pixel.
+// CompareComposites compares a band of two composites pixel by pixel,
+// subtracting composite2 from composite1, and summarizes the differences.
 //
-// Returns statistics about differences between composites.
+// By default the comparison covers the composites' shared footprint, at 30 m,
+// and a pixel counts as changed if its absolute difference exceeds 0.1. Use
+// CompareRegion for a bounded area, which composites of whole collections
+// need to keep the reduction tractable.
 //
 // Example:
 //
-//	diff, err := helpers.CompareComposites(ctx, client, composite1, composite2)
-//	fmt.Printf("Mean difference: %.2f\n", diff.MeanDifference)
-type CompositeDifference struct {
-	MeanDifference   float64
-	MedianDifference float64
-	StdDevDifference float64
-	MaxDifference    float64
-	PercentChanged   float64 // Percentage of pixels that changed significantly
-}
-
-func CompareComposites(ctx context.Context, client *earthengine.Client, composite1, composite2 *earthengine.Image, bandName string) (*CompositeDifference, error) {
-	_ = ctx
+//	region, err := bounds.ToRectangle()
+//	diff, err := helpers.CompareComposites(ctx, client, summer, winter, "NDVI",
+//	    helpers.CompareRegion(region),
+//	    helpers.ChangeThreshold(0.2))
+//	fmt.Printf("Mean difference: %.2f, %.1f%% changed\n", diff.MeanDifference, diff.PercentChanged)
+func CompareComposites(ctx context.Context, client *earthengine.Client, composite1, composite2 *earthengine.Image, bandName string, opts ...CompareOption) (*CompositeDifference, error) {
 	_ = client
-	_ = composite1
-	_ = composite2
-	_ = bandName
 
-	// In a real implementation:
-	// 1. Calculate pixel-wise differences
-	// 2. Compute statistics on differences
-	// 3. Identify significantly changed pixels
-
-	return &CompositeDifference{
-		MeanDifference:   0.05,
-		MedianDifference: 0.02,
-		StdDevDifference: 0.15,
-		MaxDifference:    0.5,
-		PercentChanged:   12.5,
-	}, nil
+	if composite1 == nil || composite2 == nil {
+		return nil, fmt.Errorf("composites cannot be nil")
+	}
+	if bandName == "" {
+		return nil, fmt.Errorf("band name cannot be empty")
+	}
+
+	cfg := &compareConfig{
+		threshold: defaultChangeThreshold,
+		scale:     defaultImageryScale,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.threshold < 0 {
+		return nil, fmt.Errorf("change threshold cannot be negative, got %v", cfg.threshold)
+	}
+	if cfg.scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", cfg.scale)
+	}
+
+	// The difference is defined only where both composites have data, so its
+	// footprint is the composites' shared footprint
+	difference := composite1.Select(bandName).Subtract(composite2.Select(bandName)).Rename("difference")
+	magnitude := difference.Abs().Rename("magnitude")
+	changed := magnitude.Gt(cfg.threshold).Rename("changed")
+
+	stats, err := difference.AddBands(magnitude).AddBands(changed).
+		ReduceRegion(cfg.region,
+			earthengine.ReducerCombine(
+				earthengine.ReducerMean(),
+				earthengine.ReducerMedian(),
+				earthengine.ReducerStdDev(),
+				earthengine.ReducerMax(),
+			),
+			earthengine.Scale(cfg.scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare composites: %w", err)
+	}
+
+	// Statistics are null where the composites don't overlap
+	if stats["difference_mean"] == nil {
+		return nil, fmt.Errorf("composites have no overlapping %s pixels", bandName)
+	}
+
+	result := &CompositeDifference{Threshold: cfg.threshold}
+	for _, field := range []struct {
+		key   string
+		value *float64
+	}{
+		{"difference_mean", &result.MeanDifference},
+		{"difference_median", &result.MedianDifference},
+		{"difference_stdDev", &result.StdDevDifference},
+		{"magnitude_max", &result.MaxDifference},
+		{"changed_mean", &result.PercentChanged},
+	} {
+		value, ok := stats[field.key].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s in result: %v", field.key, stats[field.key])
+		}
+		*field.value = value
+	}
+	result.PercentChanged *= 100
+
+	return result, nil
 }
 
 // TemporalSmoothingComposite applies temporal smoothing to reduce noise.
@@ -2,15 +2,115 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
 
+// compositeTestServer records the function calls in each request and answers
+// Collection.size with a fixed count.
+type compositeTestServer struct {
+	mu     sync.Mutex
+	calls  [][]string // Function names called, per request
+	bodies []string   // Raw request bodies
+}
+
+// newCompositeTestCollection returns a client and a collection whose size
+// computes to size.
+func newCompositeTestCollection(t *testing.T, collectionID string, size int) (*earthengine.Client, *earthengine.ImageCollection) {
+	client, _ := newCompositeTestServer(t, size)
+	return client, client.ImageCollection(collectionID)
+}
+
+func newCompositeTestServer(t *testing.T, size int) (*earthengine.Client, *compositeTestServer) {
+	t.Helper()
+	return newCompositeTestServerFunc(t, func(string) int { return size })
+}
+
+// newCompositeTestServerFunc is like newCompositeTestServer but answers
+// Collection.size with size(body) for each raw request body.
+func newCompositeTestServerFunc(t *testing.T, size func(body string) int) (*earthengine.Client, *compositeTestServer) {
+	t.Helper()
+
+	recorder := &compositeTestServer{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request: %v", err)
+		}
+
+		var body struct {
+			Expression struct {
+				Result string                            `json:"result"`
+				Values map[string]map[string]interface{} `json:"values"`
+			} `json:"expression"`
+		}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		var names []string
+		for _, node := range body.Expression.Values {
+			if call, ok := node["functionInvocationValue"].(map[string]interface{}); ok {
+				names = append(names, call["functionName"].(string))
+			}
+		}
+		recorder.mu.Lock()
+		recorder.calls = append(recorder.calls, names)
+		recorder.bodies = append(recorder.bodies, string(raw))
+		recorder.mu.Unlock()
+
+		result := body.Expression.Values[body.Expression.Result]["functionInvocationValue"].(map[string]interface{})
+		w.Header().Set("Content-Type", "application/json")
+		if result["functionName"] == earthengine.AlgorithmCollectionSize {
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": size(string(raw))})
+			return
+		}
+		w.Write([]byte(`{"result": {"value": 1}}`))
+	})
+
+	return client, recorder
+}
+
+// compute evaluates image at a point so its expression graph is sent to the
+// server, and returns the raw request body.
+func (s *compositeTestServer) compute(t *testing.T, image *earthengine.Image) string {
+	t.Helper()
+
+	if _, err := image.ReduceRegion(earthengine.NewPoint(0, 0), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[len(s.bodies)-1]
+}
+
+// called reports whether any recorded request invoked functionName.
+func (s *compositeTestServer) called(functionName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, names := range s.calls {
+		for _, name := range names {
+			if name == functionName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func TestAdvancedComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	tests := []struct {
 		name   string
@@ -32,6 +132,7 @@ func TestAdvancedComposite(t *testing.T) {
 				Method:         tt.method,
 				CloudThreshold: 20,
 				Percentile:     90,
+				QualityBand:    "quality",
 			}
 
 			result, err := AdvancedComposite(ctx, client, collection, config)
@@ -50,14 +151,39 @@ func TestAdvancedComposite(t *testing.T) {
 			if result.Method != tt.method {
 				t.Errorf("Method = %s, want %s", result.Method, tt.method)
 			}
+
+			if result.ObservationCount != 12 {
+				t.Errorf("ObservationCount = %d, want 12", result.ObservationCount)
+			}
 		})
 	}
 }
 
+func TestAdvancedCompositeRegion(t *testing.T) {
+	client, server := newCompositeTestServer(t, 5)
+	collection := client.ImageCollection(sentinel2DatasetID)
+
+	var region earthengine.Geometry = earthengine.NewRectangle(-122.5, 45.4, -122.3, 45.6)
+	result, err := AdvancedComposite(context.Background(), client, collection, CompositeConfig{
+		Method: MedianComposite,
+		Region: &region,
+	})
+	if err != nil {
+		t.Fatalf("AdvancedComposite failed: %v", err)
+	}
+	server.compute(t, result.Image)
+
+	// Images are filtered to the region and clipped before compositing
+	for _, fn := range []string{earthengine.AlgorithmFilterIntersects, earthengine.AlgorithmImageClip} {
+		if !server.called(fn) {
+			t.Errorf("%s not called", fn)
+		}
+	}
+}
+
 func TestAdvancedCompositeDefaults(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	// Test with empty config to verify defaults
 	config := CompositeConfig{
@@ -76,8 +202,7 @@ func TestAdvancedCompositeDefaults(t *testing.T) {
 
 func TestQualityMosaic(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	image, err := QualityMosaic(ctx, client, collection, "quality")
 	if err != nil {
@@ -91,8 +216,7 @@ func TestQualityMosaic(t *testing.T) {
 
 func TestGreenestPixelComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	image, err := CreateGreenestPixelComposite(ctx, client, collection)
 	if err != nil {
@@ -104,10 +228,72 @@ func TestGreenestPixelComposite(t *testing.T) {
 	}
 }
 
+func TestGreenestPixelCompositeNDVIBand(t *testing.T) {
+	tests := []struct {
+		name      string
+		dataset   string
+		config    CompositeConfig
+		wantBands string // NIR and red bands used for NDVI
+		wantKept  bool   // NDVI kept, renamed to "NDVI"
+	}{
+		{
+			name:      "sentinel-2 drops NDVI",
+			dataset:   "COPERNICUS/S2_SR_HARMONIZED",
+			wantBands: `["B8","B4"]`,
+		},
+		{
+			name:      "landsat keeps NDVI",
+			dataset:   "LANDSAT/LC08/C02/T1_L2",
+			config:    CompositeConfig{KeepQualityBand: true},
+			wantBands: `["SR_B5","SR_B4"]`,
+			wantKept:  true,
+		},
+		{
+			name:      "selected bands",
+			dataset:   "LANDSAT/LC09/C02/T1_L2",
+			config:    CompositeConfig{Bands: []string{"SR_B4", "SR_B3", "SR_B2"}},
+			wantBands: `["SR_B5","SR_B4"]`,
+		},
+		{
+			// MODIS vegetation indices already have an NDVI band
+			name:      "dataset with an NDVI band",
+			dataset:   modisVIDatasetID,
+			wantBands: `["sur_refl_b02","sur_refl_b01"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newCompositeTestServer(t, 4)
+			collection := client.ImageCollection(tt.dataset)
+
+			config := tt.config
+			config.Method = GreenestPixelComposite
+			result, err := AdvancedComposite(context.Background(), client, collection, config)
+			if err != nil {
+				t.Fatalf("AdvancedComposite failed: %v", err)
+			}
+
+			request := server.compute(t, result.Image)
+			if !strings.Contains(request, tt.wantBands) {
+				t.Errorf("NDVI should be computed from %s", tt.wantBands)
+			}
+			if !strings.Contains(request, earthengine.AlgorithmImageCollectionQualityMosaic) || !strings.Contains(request, `"`+greennessBand+`"`) {
+				t.Error("greenest pixel composite should quality mosaic on the greenness band")
+			}
+			if !strings.Contains(request, earthengine.AlgorithmListRemoveAll) {
+				t.Error("greenness band should be removed from the composite")
+			}
+			if got := strings.Contains(request, `"NDVI"`); got != tt.wantKept {
+				t.Errorf("NDVI band kept = %v, want %v", got, tt.wantKept)
+			}
+		})
+	}
+}
+
 func TestPercentileComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	// Test valid percentiles
 	percentiles := []float64{10, 25, 50, 75, 90}
@@ -133,8 +319,7 @@ func TestPercentileComposite(t *testing.T) {
 
 func TestMostRecentComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	image, err := CreateMostRecentComposite(ctx, client, collection)
 	if err != nil {
@@ -148,70 +333,334 @@ func TestMostRecentComposite(t *testing.T) {
 
 func TestSeasonalComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+
+	// Winter 2023 has no images
+	client, server := newCompositeTestServerFunc(t, func(body string) int {
+		if strings.Contains(body, `"2023-12-01"`) && strings.Contains(body, `"2024-03-01"`) {
+			return 0
+		}
+		return 4
+	})
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED")
 
 	seasons, err := SeasonalComposite(ctx, client, collection, 2023)
 	if err != nil {
 		t.Fatalf("SeasonalComposite failed: %v", err)
 	}
 
-	expectedSeasons := []string{"spring", "summer", "fall", "winter"}
-	for _, season := range expectedSeasons {
-		if _, exists := seasons[season]; !exists {
+	for _, season := range []string{"spring", "summer", "fall"} {
+		if seasons[season] == nil {
 			t.Errorf("Missing season: %s", season)
 		}
+	}
+	if _, exists := seasons["winter"]; exists {
+		t.Error("winter composite returned for a season with no images")
+	}
+	if len(seasons) != 3 {
+		t.Errorf("Got %d seasons, want 3", len(seasons))
+	}
 
-		if seasons[season] == nil {
-			t.Errorf("Season %s image is nil", season)
+	// Each season is filtered to its months
+	server.mu.Lock()
+	bodies := strings.Join(server.bodies, "\n")
+	server.mu.Unlock()
+	for _, date := range []string{"2023-03-01", "2023-06-01", "2023-09-01", "2023-12-01", "2024-03-01"} {
+		if !strings.Contains(bodies, `"`+date+`"`) {
+			t.Errorf("no request filters on %s", date)
 		}
 	}
 
-	if len(seasons) != 4 {
-		t.Errorf("Got %d seasons, want 4", len(seasons))
+	if _, err := SeasonalComposite(ctx, client, nil, 2023); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+}
+
+func TestSeasonDates(t *testing.T) {
+	tests := []struct {
+		name              string
+		season            Season
+		year              int
+		start, end, until string
+	}{
+		{"northern spring", NorthernSeasons[0], 2023, "2023-03-01", "2023-05-31", "2023-06-01"},
+		{"northern winter", NorthernSeasons[3], 2023, "2023-12-01", "2024-02-29", "2024-03-01"},
+		{"northern winter, common year", NorthernSeasons[3], 2024, "2024-12-01", "2025-02-28", "2025-03-01"},
+		{"southern summer", SouthernSeasons[3], 2023, "2023-12-01", "2024-02-29", "2024-03-01"},
+		{"southern winter", SouthernSeasons[1], 2023, "2023-06-01", "2023-08-31", "2023-09-01"},
+		{"astronomical summer", NorthernAstronomicalSeasons[1], 2023, "2023-06-21", "2023-09-21", "2023-09-22"},
+		{"astronomical winter", NorthernAstronomicalSeasons[3], 2023, "2023-12-21", "2024-03-19", "2024-03-20"},
+		{"wraps within a month", Season{Name: "year", StartMonth: time.July, StartDay: 15, EndMonth: time.July, EndDay: 14}, 2023, "2023-07-15", "2024-07-14", "2024-07-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, until := tt.season.dates(tt.year)
+			if start != tt.start || end != tt.end || until != tt.until {
+				t.Errorf("dates(%d) = %s, %s, %s, want %s, %s, %s", tt.year, start, end, until, tt.start, tt.end, tt.until)
+			}
+		})
+	}
+}
+
+func TestSeasonDefinitionValidate(t *testing.T) {
+	for name, def := range map[string]SeasonDefinition{
+		"northern":              NorthernSeasons,
+		"southern":              SouthernSeasons,
+		"northern astronomical": NorthernAstronomicalSeasons,
+		"southern astronomical": SouthernAstronomicalSeasons,
+		"leap day":              {{Name: "leap", StartMonth: time.February, StartDay: 29, EndMonth: time.March}},
+	} {
+		if err := def.Validate(); err != nil {
+			t.Errorf("%s: Validate failed: %v", name, err)
+		}
+	}
+
+	for name, def := range map[string]SeasonDefinition{
+		"empty":         {},
+		"unnamed":       {{StartMonth: time.March, EndMonth: time.May}},
+		"duplicate":     {{Name: "wet", StartMonth: time.March, EndMonth: time.May}, {Name: "wet", StartMonth: time.June, EndMonth: time.August}},
+		"invalid month": {{Name: "wet", StartMonth: 13, EndMonth: time.May}},
+		"invalid day":   {{Name: "wet", StartMonth: time.April, StartDay: 31, EndMonth: time.May}},
+	} {
+		if err := def.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSeasonsForLatitude(t *testing.T) {
+	if got := SeasonsForLatitude(45.5); got[1].Name != "summer" || got[1].StartMonth != time.June {
+		t.Errorf("SeasonsForLatitude(45.5) = %v, want northern seasons", got)
+	}
+	if got := SeasonsForLatitude(-33.9); got[3].Name != "summer" || got[3].StartMonth != time.December {
+		t.Errorf("SeasonsForLatitude(-33.9) = %v, want southern seasons", got)
+	}
+}
+
+func TestSeasonalCompositeWithSeasons(t *testing.T) {
+	ctx := context.Background()
+	client, server := newCompositeTestServer(t, 4)
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED")
+
+	wet := SeasonDefinition{
+		{Name: "wet", StartMonth: time.November, EndMonth: time.March},
+		{Name: "dry", StartMonth: time.April, EndMonth: time.October},
+	}
+	seasons, err := SeasonalCompositeWithSeasons(ctx, client, collection, 2023, wet)
+	if err != nil {
+		t.Fatalf("SeasonalCompositeWithSeasons failed: %v", err)
+	}
+	if len(seasons) != 2 || seasons["wet"] == nil || seasons["dry"] == nil {
+		t.Errorf("seasons = %v, want wet and dry composites", seasons)
+	}
+
+	// The wet season runs into the next year
+	server.mu.Lock()
+	bodies := strings.Join(server.bodies, "\n")
+	server.mu.Unlock()
+	for _, date := range []string{"2023-11-01", "2024-04-01", "2023-04-01"} {
+		if !strings.Contains(bodies, `"`+date+`"`) {
+			t.Errorf("no request filters on %s", date)
+		}
+	}
+
+	if _, err := SeasonalCompositeWithSeasons(ctx, client, collection, 2023, SeasonDefinition{}); err == nil {
+		t.Error("Expected error for empty season definition")
+	}
+}
+
+func TestSeasonalDifference(t *testing.T) {
+	ctx := context.Background()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, earthengine.AlgorithmImageReduceRegion) {
+			w.Write([]byte(`{"result": {
+				"difference_mean": 0.21, "difference_median": 0.18, "difference_stdDev": 0.15,
+				"magnitude_max": 0.7, "changed_mean": 0.35
+			}}`))
+			return
+		}
+		w.Write([]byte(`{"result": 5}`))
+	})
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED")
+	region := earthengine.NewBuffer(earthengine.NewPoint(-122.5, 45.5), 5000)
+
+	result, err := SeasonalDifference(ctx, client, collection, 2023, "summer", "winter", "NDVI",
+		CompareRegion(region), ChangeThreshold(0.3))
+	if err != nil {
+		t.Fatalf("SeasonalDifference failed: %v", err)
+	}
+
+	if result.Image == nil {
+		t.Error("Image is nil")
+	}
+	if want := (DateRange{Start: "2023-06-01", End: "2023-08-31"}); result.SeasonA != want {
+		t.Errorf("SeasonA = %+v, want %+v", result.SeasonA, want)
+	}
+	if want := (DateRange{Start: "2023-12-01", End: "2024-02-29"}); result.SeasonB != want {
+		t.Errorf("SeasonB = %+v, want %+v", result.SeasonB, want)
+	}
+	want := CompositeDifference{
+		MeanDifference:   0.21,
+		MedianDifference: 0.18,
+		StdDevDifference: 0.15,
+		MaxDifference:    0.7,
+		PercentChanged:   35,
+		Threshold:        0.3,
+	}
+	if result.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", result.Stats, want)
+	}
+
+	requests := recorder.requests()
+	last := requests[len(requests)-1]
+	for _, want := range []string{earthengine.AlgorithmImageSubtract, `"NDVI"`, `"2023-06-01"`, `"2024-03-01"`} {
+		if !strings.Contains(last, want) {
+			t.Errorf("comparison request does not contain %s", want)
+		}
+	}
+
+	// Southern summer crosses the new year and winter is mid-year
+	result, err = SeasonalDifferenceWithSeasons(ctx, client, collection, 2023, SouthernSeasons, "summer", "winter", "NDVI",
+		CompareRegion(region))
+	if err != nil {
+		t.Fatalf("SeasonalDifferenceWithSeasons failed: %v", err)
+	}
+	if want := (DateRange{Start: "2023-12-01", End: "2024-02-29"}); result.SeasonA != want {
+		t.Errorf("southern SeasonA = %+v, want %+v", result.SeasonA, want)
+	}
+	if want := (DateRange{Start: "2023-06-01", End: "2023-08-31"}); result.SeasonB != want {
+		t.Errorf("southern SeasonB = %+v, want %+v", result.SeasonB, want)
+	}
+
+	for _, tt := range []struct {
+		name             string
+		seasonA, seasonB string
+		band             string
+	}{
+		{"unknown season", "monsoon", "winter", "NDVI"},
+		{"same season", "summer", "summer", "NDVI"},
+		{"empty band", "summer", "winter", ""},
+	} {
+		if _, err := SeasonalDifference(ctx, client, collection, 2023, tt.seasonA, tt.seasonB, tt.band); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
 	}
 }
 
 func TestMultiTemporalComposite(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+
+	// February has no images
+	client, _ := newCompositeTestServerFunc(t, func(body string) int {
+		if strings.Contains(body, `"2023-02-01"`) && strings.Contains(body, `"2023-03-01"`) {
+			return 0
+		}
+		return 6
+	})
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED")
 
 	results, err := MultiTemporalComposite(ctx, client, collection,
-		"2023-01-01", "2023-12-31", "month")
+		"2023-01-01", "2023-03-31", "month")
 	if err != nil {
 		t.Fatalf("MultiTemporalComposite failed: %v", err)
 	}
 
-	if results == nil {
-		t.Fatal("Results is nil")
+	want := []DateRange{
+		{Start: "2023-01-01", End: "2023-01-31"},
+		{Start: "2023-03-01", End: "2023-03-31"},
 	}
-
-	// Should have at least one result
-	if len(results) == 0 {
-		t.Error("No results returned")
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(want))
 	}
 
-	// Check each result
 	for i, result := range results {
-		if result == nil {
-			t.Errorf("Result %d is nil", i)
-			continue
-		}
 		if result.Image == nil {
 			t.Errorf("Result %d Image is nil", i)
 		}
+		if result.DateRange != want[i] {
+			t.Errorf("Result %d DateRange = %+v, want %+v", i, result.DateRange, want[i])
+		}
+		if result.ObservationCount != 6 {
+			t.Errorf("Result %d ObservationCount = %d, want 6", i, result.ObservationCount)
+		}
+	}
+
+	if _, err := MultiTemporalComposite(ctx, client, collection, "2023-01-01", "2023-03-31", "fortnight"); err == nil {
+		t.Error("Expected error for unsupported interval")
+	}
+}
+
+func TestMosaicByDate(t *testing.T) {
+	ctx := context.Background()
+
+	// Two tiles on 2023-06-05 (listed latest first) and one on 2023-06-20
+	tile1 := time.Date(2023, 6, 5, 19, 5, 30, 0, time.UTC).UnixMilli()
+	tile2 := time.Date(2023, 6, 5, 19, 5, 10, 0, time.UTC).UnixMilli()
+	june20 := time.Date(2023, 6, 20, 19, 5, 0, 0, time.UTC).UnixMilli()
+	client, requests := newCollectionTestClient(t, 2, fmt.Sprintf("[%d, %d, %d]", tile1, tile2, june20))
+
+	daily, err := MosaicByDate(ctx, client, client.ImageCollection(sentinel2DatasetID))
+	if err != nil {
+		t.Fatalf("MosaicByDate failed: %v", err)
+	}
+	if daily.ID() != sentinel2DatasetID {
+		t.Errorf("ID() = %q, want %q", daily.ID(), sentinel2DatasetID)
+	}
+
+	if _, err := daily.Size(ctx); err != nil {
+		t.Fatalf("Size failed: %v", err)
+	}
+	sent := requests()
+	request := sent[len(sent)-1]
+
+	if n := strings.Count(request, earthengine.AlgorithmImageCollectionMosaic); n != 2 {
+		t.Errorf("request has %d mosaics, want 2", n)
+	}
+	for _, want := range []string{
+		earthengine.AlgorithmImageCollectionFromImages,
+		earthengine.AlgorithmElementSet,
+		"system:time_start",
+		fmt.Sprint(tile2), // Earliest tile on the date
+		fmt.Sprint(june20),
+		"2023-06-06",
+		"2023-06-21",
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+	if strings.Contains(request, fmt.Sprint(tile1)) {
+		t.Error("request uses the later tile's time")
+	}
+}
+
+func TestMosaicByDateEmpty(t *testing.T) {
+	client, _ := newCollectionTestClient(t, 0, `[]`)
+
+	daily, err := MosaicByDate(context.Background(), client, client.ImageCollection(sentinel2DatasetID))
+	if err != nil {
+		t.Fatalf("MosaicByDate failed: %v", err)
+	}
+	if daily == nil {
+		t.Fatal("MosaicByDate returned nil collection")
+	}
+
+	if _, err := MosaicByDate(context.Background(), client, nil); err == nil {
+		t.Error("Expected error for nil collection")
 	}
 }
 
 func TestCompositeWithOutlierRemoval(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	thresholds := []float64{2.0, 2.5, 3.0, 3.5}
 	for _, threshold := range thresholds {
-		image, err := CompositeWithOutlierRemoval(ctx, client, collection, threshold)
+		image, err := CompositeWithOutlierRemoval(ctx, client, collection, threshold, 1)
 		if err != nil {
 			t.Errorf("CompositeWithOutlierRemoval(%f) failed: %v", threshold, err)
 		}
@@ -221,6 +670,52 @@ func TestCompositeWithOutlierRemoval(t *testing.T) {
 	}
 }
 
+func TestCompositeWithOutlierRemovalIterations(t *testing.T) {
+	client, server := newCompositeTestServer(t, 12)
+	collection := client.ImageCollection("LANDSAT/LC08/C02/T1_L2")
+
+	image, err := CompositeWithOutlierRemoval(context.Background(), client, collection, 2.5, 3)
+	if err != nil {
+		t.Fatalf("CompositeWithOutlierRemoval failed: %v", err)
+	}
+	server.compute(t, image)
+
+	counts := make(map[string]int)
+	server.mu.Lock()
+	for _, name := range server.calls[len(server.calls)-1] {
+		counts[name]++
+	}
+	server.mu.Unlock()
+
+	if counts[earthengine.AlgorithmCollectionMap] != 3 {
+		t.Errorf("Collection.map calls = %d, want 3 clipping passes", counts[earthengine.AlgorithmCollectionMap])
+	}
+	for _, fn := range []string{earthengine.AlgorithmReducerStdDev, earthengine.AlgorithmImageAbs, earthengine.AlgorithmImageLte, earthengine.AlgorithmReducerMedian} {
+		if counts[fn] == 0 {
+			t.Errorf("outlier removal graph missing %s", fn)
+		}
+	}
+	// Each pass guards against a zero standard deviation
+	if counts[earthengine.AlgorithmImageNeq] != 3 || counts[earthengine.AlgorithmImageUnmask] != 3 {
+		t.Errorf("neq/unmask calls = %d/%d, want a zero standard deviation guard per pass",
+			counts[earthengine.AlgorithmImageNeq], counts[earthengine.AlgorithmImageUnmask])
+	}
+}
+
+func TestCompositeWithOutlierRemovalErrors(t *testing.T) {
+	ctx := context.Background()
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
+
+	for _, threshold := range []float64{0, -1} {
+		if _, err := CompositeWithOutlierRemoval(ctx, client, collection, threshold, 1); err == nil {
+			t.Errorf("Expected error for threshold %v", threshold)
+		}
+	}
+	if _, err := CompositeWithOutlierRemoval(ctx, client, collection, 2.5, 0); err == nil {
+		t.Error("Expected error for zero iterations")
+	}
+}
+
 func TestPixelCompositeStats(t *testing.T) {
 	ctx := context.Background()
 	client := &earthengine.Client{}
@@ -259,66 +754,140 @@ func TestCompositeQualityMask(t *testing.T) {
 
 func TestCalculateCompositeMetrics(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	composite := &earthengine.Image{}
-
+	client, lastRequest := newImageryTestClient(t, `{"result": {
+		"valid_mean": 0.96,
+		"clear_mean": 7.4, "clear_median": 7, "clear_min": 2, "clear_max": 13, "clear_sum": 7400,
+		"total_sum": 9250
+	}}`)
+	composite := client.Image("projects/p/assets/composite")
+
+	// Coverage alone from the composite's mask
 	metrics, err := CalculateCompositeMetrics(ctx, client, composite)
 	if err != nil {
 		t.Fatalf("CalculateCompositeMetrics failed: %v", err)
 	}
-
-	if metrics == nil {
-		t.Fatal("Metrics is nil")
+	if want := (CompositeMetrics{Coverage: 0.96}); *metrics != want {
+		t.Errorf("metrics = %+v, want %+v", *metrics, want)
 	}
-
-	// Check that metrics have reasonable values
-	if metrics.MeanObservations < 0 {
-		t.Error("MeanObservations is negative")
+	request := lastRequest()
+	if !strings.Contains(request, earthengine.AlgorithmImageMask) || !strings.Contains(request, earthengine.AlgorithmImageReduce+`"`) {
+		t.Error("request does not reduce the composite's mask across bands")
 	}
-
-	if metrics.MedianObservations < 0 {
-		t.Error("MedianObservations is negative")
+	if strings.Contains(request, earthengine.AlgorithmImageCollectionCount) {
+		t.Error("request counts observations without a collection")
 	}
 
-	if metrics.MinObservations < 0 {
-		t.Error("MinObservations is negative")
+	// Observation counts from the source collection
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").FilterDate("2023-06-01", "2023-09-01")
+	region := earthengine.NewBuffer(earthengine.NewPoint(-122.5, 45.5), 5000)
+	metrics, err = CalculateCompositeMetrics(ctx, client, composite,
+		MetricsRegion(region), MetricsCollection(collection), MetricsScale(10))
+	if err != nil {
+		t.Fatalf("CalculateCompositeMetrics with collection failed: %v", err)
 	}
-
-	if metrics.MaxObservations < metrics.MinObservations {
-		t.Error("MaxObservations < MinObservations")
+	want := CompositeMetrics{
+		MeanObservations:   7.4,
+		MedianObservations: 7,
+		MinObservations:    2,
+		MaxObservations:    13,
+		Coverage:           0.96,
+		CloudFreePixels:    0.8,
 	}
-
-	if metrics.Coverage < 0 || metrics.Coverage > 1 {
-		t.Errorf("Coverage = %f, want 0-1", metrics.Coverage)
+	if *metrics != want {
+		t.Errorf("metrics = %+v, want %+v", *metrics, want)
 	}
+	request = lastRequest()
+	for _, want := range []string{earthengine.AlgorithmImageCollectionCount, `"SCL"`, `"B8"`, `"geometry"`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+}
 
-	if metrics.CloudFreePixels < 0 || metrics.CloudFreePixels > 1 {
-		t.Errorf("CloudFreePixels = %f, want 0-1", metrics.CloudFreePixels)
+func TestCalculateCompositeMetricsErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": {"valid_mean": null}}`)
+	composite := client.Image("projects/p/assets/composite")
+
+	if _, err := CalculateCompositeMetrics(ctx, client, composite); err == nil {
+		t.Error("expected error for a region without composite pixels")
+	}
+	if _, err := CalculateCompositeMetrics(ctx, client, nil); err == nil {
+		t.Error("expected error for nil composite")
+	}
+	if _, err := CalculateCompositeMetrics(ctx, client, composite, MetricsScale(0)); err == nil {
+		t.Error("expected error for zero scale")
 	}
 }
 
 func TestCompareComposites(t *testing.T) {
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	composite1 := &earthengine.Image{}
-	composite2 := &earthengine.Image{}
+	client, lastRequest := newImageryTestClient(t, `{"result": {
+		"difference_mean": 0.05, "difference_median": 0.04, "difference_stdDev": 0.12,
+		"magnitude_max": 0.6, "changed_mean": 0.125
+	}}`)
+	composite1 := client.Image("projects/p/assets/summer")
+	composite2 := client.Image("projects/p/assets/winter")
 
 	diff, err := CompareComposites(ctx, client, composite1, composite2, "NDVI")
 	if err != nil {
 		t.Fatalf("CompareComposites failed: %v", err)
 	}
 
-	if diff == nil {
-		t.Fatal("Diff is nil")
+	want := CompositeDifference{
+		MeanDifference:   0.05,
+		MedianDifference: 0.04,
+		StdDevDifference: 0.12,
+		MaxDifference:    0.6,
+		PercentChanged:   12.5,
+		Threshold:        defaultChangeThreshold,
+	}
+	if *diff != want {
+		t.Errorf("diff = %+v, want %+v", *diff, want)
+	}
+
+	// Without a region the difference image's footprint is reduced
+	request := lastRequest()
+	for _, want := range []string{earthengine.AlgorithmImageSubtract, earthengine.AlgorithmImageAbs, `"NDVI"`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+	if strings.Contains(request, `"geometry"`) {
+		t.Error("request has a geometry, want the images' shared footprint")
 	}
 
-	// Check that difference values are reasonable
-	if diff.MaxDifference < diff.MeanDifference {
-		t.Error("MaxDifference < MeanDifference")
+	region := earthengine.NewBuffer(earthengine.NewPoint(-122.5, 45.5), 5000)
+	diff, err = CompareComposites(ctx, client, composite1, composite2, "NDVI",
+		CompareRegion(region), ChangeThreshold(0.25), CompareScale(10))
+	if err != nil {
+		t.Fatalf("CompareComposites with options failed: %v", err)
+	}
+	request = lastRequest()
+	if !strings.Contains(request, `"geometry"`) || !strings.Contains(request, earthengine.AlgorithmGeometryBuffer) {
+		t.Error("request does not reduce over the supplied region")
 	}
+	if !strings.Contains(request, "0.25") || diff.Threshold != 0.25 {
+		t.Errorf("threshold 0.25 not used: Threshold = %v", diff.Threshold)
+	}
+}
+
+func TestCompareCompositesErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": {"difference_mean": null}}`)
+	image := client.Image("projects/p/assets/a")
 
-	if diff.PercentChanged < 0 || diff.PercentChanged > 100 {
-		t.Errorf("PercentChanged = %f, want 0-100", diff.PercentChanged)
+	if _, err := CompareComposites(ctx, client, image, image, "NDVI"); err == nil {
+		t.Error("expected error for composites without overlapping pixels")
+	}
+	if _, err := CompareComposites(ctx, client, nil, image, "NDVI"); err == nil {
+		t.Error("expected error for nil composite")
+	}
+	if _, err := CompareComposites(ctx, client, image, image, ""); err == nil {
+		t.Error("expected error for empty band name")
+	}
+	if _, err := CompareComposites(ctx, client, image, image, "NDVI", ChangeThreshold(-1)); err == nil {
+		t.Error("expected error for negative threshold")
 	}
 }
 
@@ -393,8 +962,7 @@ func TestCompositeConfigDefaults(t *testing.T) {
 
 	// Test that function applies defaults correctly
 	ctx := context.Background()
-	client := &earthengine.Client{}
-	collection := &earthengine.ImageCollection{}
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 12)
 
 	result, err := AdvancedComposite(ctx, client, collection, config)
 	if err != nil {
@@ -431,29 +999,467 @@ func TestDateRange(t *testing.T) {
 		End:   "2023-12-31",
 	}
 
-	if dr.Start == "" {
-		t.Error("Start date is empty")
+	if err := dr.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	start, end, err := dr.Parse()
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !start.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() = %s, %s", start, end)
+	}
+	if got := dr.Days(); got != 365 {
+		t.Errorf("Days() = %d, want 365", got)
+	}
+	if got := (DateRange{Start: "2024-02-29", End: "2024-02-29"}).Days(); got != 1 {
+		t.Errorf("Days() for a single day = %d, want 1", got)
+	}
+
+	local := time.FixedZone("PDT", -7*3600)
+	got := NewDateRange(time.Date(2023, 6, 1, 23, 0, 0, 0, local), time.Date(2023, 6, 30, 0, 0, 0, 0, local))
+	if got != (DateRange{Start: "2023-06-01", End: "2023-06-30"}) {
+		t.Errorf("NewDateRange() = %+v", got)
+	}
+}
+
+func TestDateRangeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		dr   DateRange
+	}{
+		{"empty", DateRange{}},
+		{"bad start", DateRange{Start: "2023-13-01", End: "2023-12-31"}},
+		{"bad end", DateRange{Start: "2023-01-01", End: "12/31/2023"}},
+		{"end before start", DateRange{Start: "2023-12-31", End: "2023-01-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dr.Validate(); err == nil {
+				t.Error("Expected error")
+			}
+			if got := tt.dr.Days(); got != 0 {
+				t.Errorf("Days() = %d, want 0", got)
+			}
+			if got := tt.dr.Split("month"); got != nil {
+				t.Errorf("Split() = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestDateRangeSplit(t *testing.T) {
+	dr := DateRange{Start: "2023-01-15", End: "2023-03-10"}
+
+	want := []DateRange{
+		{Start: "2023-01-15", End: "2023-01-31"},
+		{Start: "2023-02-01", End: "2023-02-28"},
+		{Start: "2023-03-01", End: "2023-03-10"},
+	}
+	got := dr.Split("month")
+	if len(got) != len(want) {
+		t.Fatalf("Split(month) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Split(month)[%d] = %+v, want %+v", i, got[i], want[i])
+		}
 	}
 
-	if dr.End == "" {
-		t.Error("End date is empty")
+	days := 0
+	for _, week := range dr.Split("week") {
+		days += week.Days()
+	}
+	if days != dr.Days() {
+		t.Errorf("weeks cover %d days, want %d", days, dr.Days())
+	}
+	if got := dr.Split("fortnight"); got != nil {
+		t.Errorf("Split(fortnight) = %v, want nil", got)
 	}
 }
 
 func TestGeneratePeriods(t *testing.T) {
-	periods := generatePeriods("2023-01-01", "2023-12-31", "month")
+	tests := []struct {
+		name       string
+		start, end string
+		interval   string
+		want       []period
+	}{
+		{
+			name: "leap year february", start: "2024-01-15", end: "2024-03-10", interval: "month",
+			want: []period{
+				{Start: "2024-01-15", End: "2024-01-31", Until: "2024-02-01"},
+				{Start: "2024-02-01", End: "2024-02-29", Until: "2024-03-01"},
+				{Start: "2024-03-01", End: "2024-03-10", Until: "2024-03-11"},
+			},
+		},
+		{
+			name: "non-leap february", start: "2023-02-01", end: "2023-02-28", interval: "month",
+			want: []period{
+				{Start: "2023-02-01", End: "2023-02-28", Until: "2023-03-01"},
+			},
+		},
+		{
+			name: "weeks", start: "2023-12-25", end: "2024-01-10", interval: "week",
+			want: []period{
+				{Start: "2023-12-25", End: "2023-12-31", Until: "2024-01-01"},
+				{Start: "2024-01-01", End: "2024-01-07", Until: "2024-01-08"},
+				{Start: "2024-01-08", End: "2024-01-10", Until: "2024-01-11"},
+			},
+		},
+		{
+			name: "days", start: "2024-02-28", end: "2024-03-01", interval: "day",
+			want: []period{
+				{Start: "2024-02-28", End: "2024-02-28", Until: "2024-02-29"},
+				{Start: "2024-02-29", End: "2024-02-29", Until: "2024-03-01"},
+				{Start: "2024-03-01", End: "2024-03-01", Until: "2024-03-02"},
+			},
+		},
+		{
+			name: "years", start: "2022-06-01", end: "2023-12-31", interval: "year",
+			want: []period{
+				{Start: "2022-06-01", End: "2022-12-31", Until: "2023-01-01"},
+				{Start: "2023-01-01", End: "2023-12-31", Until: "2024-01-01"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods, err := generatePeriods(tt.start, tt.end, tt.interval)
+			if err != nil {
+				t.Fatalf("generatePeriods failed: %v", err)
+			}
+			if len(periods) != len(tt.want) {
+				t.Fatalf("got %d periods %+v, want %d", len(periods), periods, len(tt.want))
+			}
+			for i := range periods {
+				if periods[i] != tt.want[i] {
+					t.Errorf("period %d = %+v, want %+v", i, periods[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	monthly, err := generatePeriods("2023-01-01", "2023-12-31", "month")
+	if err != nil {
+		t.Fatalf("generatePeriods failed: %v", err)
+	}
+	if len(monthly) != 12 {
+		t.Errorf("got %d monthly periods for 2023, want 12", len(monthly))
+	}
+}
 
-	if len(periods) == 0 {
-		t.Error("No periods generated")
+func TestGeneratePeriodsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		interval   string
+	}{
+		{"invalid start", "2023-13-01", "2023-12-31", "month"},
+		{"invalid end", "2023-01-01", "12/31/2023", "month"},
+		{"end before start", "2023-06-01", "2023-05-31", "day"},
+		{"unsupported interval", "2023-01-01", "2023-12-31", "quarter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generatePeriods(tt.start, tt.end, tt.interval); err == nil {
+				t.Error("Expected error")
+			}
+		})
 	}
+}
 
-	// Check that periods have valid dates
-	for i, p := range periods {
-		if p.Start == "" {
-			t.Errorf("Period %d has empty start date", i)
+func TestAdvancedCompositeCloudMasking(t *testing.T) {
+	tests := []struct {
+		dataset       string
+		cloudProperty string
+		qaBand        string
+	}{
+		{"COPERNICUS/S2_SR_HARMONIZED", "CLOUDY_PIXEL_PERCENTAGE", "SCL"},
+		{"COPERNICUS/S2_HARMONIZED", "CLOUDY_PIXEL_PERCENTAGE", "QA60"},
+		{"LANDSAT/LC08/C02/T1_L2", "CLOUD_COVER", "QA_PIXEL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataset, func(t *testing.T) {
+			ctx := context.Background()
+			client, server := newCompositeTestServer(t, 3)
+			collection := client.ImageCollection(tt.dataset)
+
+			result, err := AdvancedComposite(ctx, client, collection, CompositeConfig{
+				Method: MedianComposite,
+				Bands:  []string{"B4"},
+			})
+			if err != nil {
+				t.Fatalf("AdvancedComposite failed: %v", err)
+			}
+			if result.ObservationCount != 3 {
+				t.Errorf("ObservationCount = %d, want 3", result.ObservationCount)
+			}
+
+			// Evaluate the composite so its graph is sent to the server
+			if _, err := result.Image.ReduceRegion(earthengine.NewPoint(0, 0), earthengine.ReducerFirst()).Compute(ctx); err != nil {
+				t.Fatalf("Compute failed: %v", err)
+			}
+
+			var request string
+			server.mu.Lock()
+			request = strings.Join(server.calls[len(server.calls)-1], ",")
+			server.mu.Unlock()
+
+			for _, fn := range []string{
+				earthengine.AlgorithmImageCollectionFilterMetadata,
+				earthengine.AlgorithmCollectionMap,
+				earthengine.AlgorithmImageUpdateMask,
+				earthengine.AlgorithmImageRename,
+			} {
+				if !strings.Contains(request, fn) {
+					t.Errorf("composite graph missing %s", fn)
+				}
+			}
+		})
+	}
+}
+
+func TestAdvancedCompositeErrors(t *testing.T) {
+	ctx := context.Background()
+	client, collection := newCompositeTestCollection(t, "COPERNICUS/S2_SR_HARMONIZED", 0)
+
+	if _, err := AdvancedComposite(ctx, client, collection, CompositeConfig{Method: MedianComposite}); err == nil {
+		t.Error("Expected error for empty collection")
+	}
+	if _, err := AdvancedComposite(ctx, client, collection, CompositeConfig{Method: QualityMosaicComposite}); err == nil {
+		t.Error("Expected error for quality mosaic without quality band")
+	}
+	if _, err := AdvancedComposite(ctx, client, nil, CompositeConfig{}); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+}
+
+func TestAdvancedCompositeMostRecent(t *testing.T) {
+	client, server := newCompositeTestServer(t, 5)
+	collection := client.ImageCollection("LANDSAT/LC09/C02/T1_L2")
+
+	result, err := AdvancedComposite(context.Background(), client, collection, CompositeConfig{Method: MostRecentComposite})
+	if err != nil {
+		t.Fatalf("AdvancedComposite failed: %v", err)
+	}
+	if _, err := result.Image.ReduceRegion(earthengine.NewPoint(0, 0), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+
+	if !server.called(earthengine.AlgorithmCollectionSort) || !server.called(earthengine.AlgorithmImageCollectionMosaic) {
+		t.Error("most recent composite should sort the collection and mosaic it")
+	}
+}
+
+func TestHarmonizedComposite(t *testing.T) {
+	client, server := newCompositeTestServer(t, 9)
+	bounds := Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+
+	result, err := HarmonizedComposite(context.Background(), client, "2023-06-01", "2023-08-31", bounds, GreenestPixelComposite)
+	if err != nil {
+		t.Fatalf("HarmonizedComposite failed: %v", err)
+	}
+
+	if result.ObservationCount != 9 {
+		t.Errorf("ObservationCount = %d, want 9", result.ObservationCount)
+	}
+	if result.DateRange != (DateRange{Start: "2023-06-01", End: "2023-08-31"}) {
+		t.Errorf("DateRange = %+v", result.DateRange)
+	}
+
+	request := server.compute(t, result.Image)
+	for _, want := range []string{
+		landsat8DatasetID,
+		landsat9DatasetID,
+		sentinel2DatasetID,
+		// The inclusive end date is filtered up to the following day
+		`"2023-09-01"`,
+		earthengine.AlgorithmImageCollectionMerge,
+		earthengine.AlgorithmFilterIntersects,
+		earthengine.AlgorithmGeometryRectangle,
+		earthengine.AlgorithmImageCollectionQualityMosaic,
+		`["SR_B2","SR_B3","SR_B4","SR_B5","SR_B6","SR_B7"]`,
+		`["B2","B3","B4","B8","B11","B12"]`,
+		`["blue","green","red","nir","swir1","swir2"]`,
+		"0.0000275",
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("harmonized composite graph missing %s", want)
 		}
-		if p.End == "" {
-			t.Errorf("Period %d has empty end date", i)
+	}
+}
+
+func TestHarmonizedCompositeErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newCompositeTestServer(t, 0)
+	bounds := Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+
+	if _, err := HarmonizedComposite(ctx, client, "2023-06-01", "2023-08-31", bounds, MedianComposite); !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages", err)
+	}
+	if _, err := HarmonizedComposite(ctx, client, "2023-06-01", "2023-08-31", Bounds{MinLon: 1, MaxLon: 0}, MedianComposite); err == nil {
+		t.Error("Expected error for invalid bounds")
+	}
+	if _, err := HarmonizedComposite(ctx, client, "2023-06-01", "2023-08-31", bounds, PercentileComposite); err == nil {
+		t.Error("Expected error for percentile composite")
+	}
+	if _, err := HarmonizedComposite(ctx, client, "2023-08-31", "2023-06-01", bounds, MedianComposite); err == nil {
+		t.Error("Expected error for end date before start date")
+	}
+}
+
+func TestNDVIComposite(t *testing.T) {
+	client, server := newCompositeTestServer(t, 6)
+	bounds := Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+
+	ndvi, err := NDVIComposite(context.Background(), client, "2023-06-01", "2023-08-31", bounds,
+		MedianComposite, Sentinel2(), CloudMask(10))
+	if err != nil {
+		t.Fatalf("NDVIComposite failed: %v", err)
+	}
+
+	request := server.compute(t, ndvi)
+	for _, want := range []string{
+		sentinel2DatasetID,
+		"2023-06-01",
+		// The inclusive end date is filtered up to the following day
+		`"2023-09-01"`,
+		"CLOUDY_PIXEL_PERCENTAGE",
+		earthengine.AlgorithmFilterIntersects,
+		earthengine.AlgorithmReducerMedian,
+		`["B8","B4"]`,
+		earthengine.AlgorithmImageNormalizedDiff,
+		earthengine.AlgorithmImageUpdateMask,
+		`"NDVI"`,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("NDVI composite graph missing %s", want)
+		}
+	}
+	// Only NIR and red are composited
+	if strings.Contains(request, `"B11"`) {
+		t.Error("NDVI composite graph selects unneeded band B11")
+	}
+}
+
+func TestNDVICompositeErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newCompositeTestServer(t, 0)
+	bounds := Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+
+	if _, err := NDVIComposite(ctx, client, "2023-06-01", "2023-08-31", bounds, MedianComposite); !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages", err)
+	}
+	if _, err := NDVIComposite(ctx, client, "2023-06-01", "2023-08-31", Bounds{MinLon: 1, MaxLon: 0}, MedianComposite); err == nil {
+		t.Error("Expected error for invalid bounds")
+	}
+	if _, err := NDVIComposite(ctx, client, "2023-06-01", "2023-08-31", bounds, QualityMosaicComposite); err == nil {
+		t.Error("Expected error for quality mosaic composite")
+	}
+	if _, err := NDVIComposite(ctx, client, "2023-08-31", "2023-06-01", bounds, MedianComposite); err == nil {
+		t.Error("Expected error for end date before start date")
+	}
+}
+
+func TestAdvancedCompositeMinNDVI(t *testing.T) {
+	ctx := context.Background()
+	client, server := newCompositeTestServer(t, 3)
+	collection := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED")
+
+	result, err := AdvancedComposite(ctx, client, collection, CompositeConfig{
+		Method:  MedianComposite,
+		Bands:   []string{"B4"},
+		MinNDVI: 0.3,
+	})
+	if err != nil {
+		t.Fatalf("AdvancedComposite failed: %v", err)
+	}
+	if _, err := result.Image.ReduceRegion(earthengine.NewPoint(0, 0), earthengine.ReducerFirst()).Compute(ctx); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+
+	server.mu.Lock()
+	request := strings.Join(server.calls[len(server.calls)-1], ",")
+	server.mu.Unlock()
+	for _, fn := range []string{earthengine.AlgorithmImageNormalizedDiff, earthengine.AlgorithmImageGte} {
+		if !strings.Contains(request, fn) {
+			t.Errorf("composite graph missing %s", fn)
 		}
 	}
+
+	if _, err := AdvancedComposite(ctx, client, collection, CompositeConfig{MinNDVI: 1.5}); err == nil {
+		t.Error("Expected error for MinNDVI above 1")
+	}
+}
+
+// newBandNamesTestClient returns a client that answers each Image.bandNames
+// request with the bands of the image ID in the request.
+func newBandNamesTestClient(t *testing.T, bands map[string][]string) *earthengine.Client {
+	t.Helper()
+
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		for id, names := range bands {
+			if strings.Contains(string(body), `"`+id+`"`) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"result": names})
+				return
+			}
+		}
+		http.Error(w, "unknown image", http.StatusNotFound)
+	})
+}
+
+func TestPriorityMosaic(t *testing.T) {
+	client := newBandNamesTestClient(t, map[string][]string{
+		"USGS/3DEP/10m":    {"elevation"},
+		"USGS/SRTMGL1_003": {"elevation"},
+	})
+
+	elevation, err := PriorityMosaic(context.Background(), client, []*earthengine.Image{
+		client.Image("USGS/3DEP/10m"),
+		client.Image("USGS/SRTMGL1_003"),
+	})
+	if err != nil {
+		t.Fatalf("PriorityMosaic failed: %v", err)
+	}
+
+	exprJSON, err := elevation.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	expr := string(exprJSON)
+	if !strings.Contains(expr, earthengine.AlgorithmImageCollectionMosaic) {
+		t.Errorf("expression does not mosaic: %s", expr)
+	}
+	// The preferred source must be last, on top of the mosaic
+	if strings.Index(expr, "USGS/SRTMGL1_003") > strings.Index(expr, "USGS/3DEP/10m") {
+		t.Errorf("3DEP is not on top of SRTM: %s", expr)
+	}
+}
+
+func TestPriorityMosaicErrors(t *testing.T) {
+	client := newBandNamesTestClient(t, map[string][]string{
+		"LANDSAT/LC08/C02/T1_L2/LC08_044034_20230601": {"SR_B4", "SR_B5"},
+		"COPERNICUS/S2_SR_HARMONIZED/20230601":        {"B4", "B8"},
+	})
+	ctx := context.Background()
+
+	if _, err := PriorityMosaic(ctx, client, nil); err == nil {
+		t.Error("expected error for no sources")
+	}
+	if _, err := PriorityMosaic(ctx, client, []*earthengine.Image{client.Image("A"), nil}); err == nil {
+		t.Error("expected error for nil source")
+	}
+
+	_, err := PriorityMosaic(ctx, client, []*earthengine.Image{
+		client.Image("LANDSAT/LC08/C02/T1_L2/LC08_044034_20230601"),
+		client.Image("COPERNICUS/S2_SR_HARMONIZED/20230601"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "source 1 has bands") {
+		t.Errorf("err = %v, want band mismatch", err)
+	}
 }
@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// BandRoles maps spectral roles to a dataset's band names. Roles a dataset
+// lacks may be left empty.
+type BandRoles struct {
+	Blue    string
+	Green   string
+	Red     string
+	NIR     string
+	SWIR1   string
+	SWIR2   string
+	RedEdge string // Red edge near 705 nm, used by NDRE and CIre
+}
+
+// DatasetDef describes an optical image collection so the index and
+// compositing helpers can use it.
+type DatasetDef struct {
+	ID         string    // Earth Engine collection ID
+	Resolution float64   // Native resolution in meters
+	Bands      BandRoles // Band name for each spectral role
+
+	// Scale and Offset convert stored values to reflectance:
+	// reflectance = value*Scale + Offset. A zero Scale means the Landsat
+	// Collection 2 scaling.
+	Scale  float64
+	Offset float64
+
+	// CloudMask masks cloudy pixels in one image, e.g. MaskCloudsLandsat.
+	// Nil means images are not masked.
+	CloudMask func(*earthengine.Image) *earthengine.Image
+
+	// CloudCoverProperty is the scene cloud cover metadata property used by
+	// CloudMask filtering and least-cloudy selection, if any.
+	CloudCoverProperty string
+}
+
+// landsatBands are the Landsat 8/9 Collection 2 band names, also used for
+// unregistered datasets and for roles a registered dataset leaves empty.
+var landsatBands = BandRoles{
+	Blue:  "SR_B2",
+	Green: "SR_B3",
+	Red:   "SR_B4",
+	NIR:   "SR_B5",
+	SWIR1: "SR_B6",
+	SWIR2: "SR_B7",
+}
+
+var datasetRegistry = struct {
+	mu   sync.RWMutex
+	defs map[string]DatasetDef
+}{
+	defs: map[string]DatasetDef{
+		landsat8DatasetID: {
+			ID:                 landsat8DatasetID,
+			Resolution:         30,
+			Bands:              landsatBands,
+			Scale:              0.0000275,
+			Offset:             -0.2,
+			CloudMask:          MaskCloudsLandsat,
+			CloudCoverProperty: "CLOUD_COVER",
+		},
+		landsat9DatasetID: {
+			ID:                 landsat9DatasetID,
+			Resolution:         30,
+			Bands:              landsatBands,
+			Scale:              0.0000275,
+			Offset:             -0.2,
+			CloudMask:          MaskCloudsLandsat,
+			CloudCoverProperty: "CLOUD_COVER",
+		},
+		sentinel2DatasetID: {
+			ID:         sentinel2DatasetID,
+			Resolution: 10,
+			Bands: BandRoles{
+				Blue:    "B2",
+				Green:   "B3",
+				Red:     "B4",
+				NIR:     "B8",
+				SWIR1:   "B11",
+				SWIR2:   "B12",
+				RedEdge: "B5",
+			},
+			Scale:              0.0001,
+			CloudMask:          MaskCloudsSentinel2SR,
+			CloudCoverProperty: "CLOUDY_PIXEL_PERCENTAGE",
+		},
+		modisVIDatasetID: {
+			ID:         modisVIDatasetID,
+			Resolution: 500,
+			Bands: BandRoles{
+				Blue: "sur_refl_b03",
+				Red:  "sur_refl_b01",
+				NIR:  "sur_refl_b02",
+			},
+			Scale: 0.0001,
+		},
+	},
+}
+
+// RegisterDataset adds a dataset, or replaces one with the same ID, so it can
+// be selected with CustomDataset. Landsat 8/9, Sentinel-2, and MODIS are
+// registered by default.
+//
+// Example:
+//
+//	err := helpers.RegisterDataset(helpers.DatasetDef{
+//	    ID:         "projects/my-project/assets/planetscope",
+//	    Resolution: 3,
+//	    Bands:      helpers.BandRoles{Blue: "b1", Green: "b3", Red: "b5", NIR: "b7"},
+//	    Scale:      0.0001,
+//	})
+//	ndvi, err := helpers.NDVI(client, lat, lon, "2023-06-01",
+//	    helpers.CustomDataset("projects/my-project/assets/planetscope"))
+func RegisterDataset(def DatasetDef) error {
+	if def.ID == "" {
+		return fmt.Errorf("dataset ID is required")
+	}
+	if def.Resolution < 0 {
+		return fmt.Errorf("resolution cannot be negative, got %v", def.Resolution)
+	}
+
+	datasetRegistry.mu.Lock()
+	defer datasetRegistry.mu.Unlock()
+	datasetRegistry.defs[def.ID] = def
+	return nil
+}
+
+// LookupDataset returns the registered definition of a dataset.
+func LookupDataset(id string) (DatasetDef, bool) {
+	datasetRegistry.mu.RLock()
+	defer datasetRegistry.mu.RUnlock()
+	def, ok := datasetRegistry.defs[id]
+	return def, ok
+}
+
+// CustomDataset uses the image collection with the given ID. Band names,
+// scaling, and cloud masking come from its RegisterDataset definition;
+// unregistered datasets are treated like Landsat 8.
+func CustomDataset(id string) ImageryOption {
+	return func(cfg *imageryConfig) {
+		cfg.dataset = id
+	}
+}
+
+// datasetBands returns the band roles of a dataset. Unregistered datasets use
+// the Landsat band names; roles a registered dataset does not define are empty.
+func datasetBands(dataset string) BandRoles {
+	def, ok := LookupDataset(dataset)
+	if !ok {
+		return landsatBands
+	}
+	return def.Bands
+}
+
+// band returns the band name for role, one of the BandRoles field names.
+func (b BandRoles) band(role string) string {
+	switch role {
+	case "Blue":
+		return b.Blue
+	case "Green":
+		return b.Green
+	case "Red":
+		return b.Red
+	case "NIR":
+		return b.NIR
+	case "SWIR1":
+		return b.SWIR1
+	case "SWIR2":
+		return b.SWIR2
+	case "RedEdge":
+		return b.RedEdge
+	default:
+		return ""
+	}
+}
+
+// require returns an error naming the first of roles that dataset has no
+// band for.
+func (b BandRoles) require(dataset string, roles ...string) error {
+	for _, role := range roles {
+		if b.band(role) == "" {
+			return fmt.Errorf("dataset %s has no %s band", dataset, role)
+		}
+	}
+	return nil
+}
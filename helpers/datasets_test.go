@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func TestBuiltinDatasets(t *testing.T) {
+	for _, id := range []string{landsat8DatasetID, landsat9DatasetID, sentinel2DatasetID, modisVIDatasetID} {
+		if _, ok := LookupDataset(id); !ok {
+			t.Errorf("%s is not registered", id)
+		}
+	}
+
+	if nir, red, err := getBandNames(sentinel2DatasetID); err != nil || nir != "B8" || red != "B4" {
+		t.Errorf("Sentinel-2 NIR/Red = %s/%s, %v, want B8/B4", nir, red, err)
+	}
+	if nir, red, err := getBandNames(modisVIDatasetID); err != nil || nir != "sur_refl_b02" || red != "sur_refl_b01" {
+		t.Errorf("MODIS NIR/Red = %s/%s, %v", nir, red, err)
+	}
+	if nir, red, err := getBandNames("unregistered/dataset"); err != nil || nir != "SR_B5" || red != "SR_B4" {
+		t.Errorf("unregistered NIR/Red = %s/%s, %v, want Landsat bands", nir, red, err)
+	}
+}
+
+func TestMissingDatasetBands(t *testing.T) {
+	// MODIS has no green or SWIR bands; the Landsat names must not be used
+	if bands := datasetBands(modisVIDatasetID); bands.Green != "" || bands.SWIR1 != "" {
+		t.Errorf("MODIS Green/SWIR1 = %q/%q, want empty", bands.Green, bands.SWIR1)
+	}
+
+	_, _, err := getBandNamesForWater(modisVIDatasetID)
+	if err == nil || !strings.Contains(err.Error(), "has no Green band") {
+		t.Errorf("getBandNamesForWater(MODIS) error = %v, want missing Green band", err)
+	}
+
+	_, err = NDMI(nil, 45.5, -122.6, "2023-07-15", MODIS())
+	want := "dataset " + modisVIDatasetID + " has no SWIR1 band"
+	if err == nil || err.Error() != want {
+		t.Errorf("NDMI(MODIS) error = %v, want %q", err, want)
+	}
+}
+
+func TestRegisterDataset(t *testing.T) {
+	const id = "projects/test/assets/planetscope"
+	t.Cleanup(func() {
+		datasetRegistry.mu.Lock()
+		delete(datasetRegistry.defs, id)
+		datasetRegistry.mu.Unlock()
+	})
+
+	masked := false
+	err := RegisterDataset(DatasetDef{
+		ID:         id,
+		Resolution: 3,
+		Bands:      BandRoles{Blue: "b1", Green: "b3", Red: "b5", NIR: "b7"},
+		Scale:      0.0001,
+		CloudMask: func(img *earthengine.Image) *earthengine.Image {
+			masked = true
+			return img
+		},
+		CloudCoverProperty: "cloud_percent",
+	})
+	if err != nil {
+		t.Fatalf("RegisterDataset failed: %v", err)
+	}
+
+	if scale, offset := getReflectanceScaling(id); scale != 0.0001 || offset != 0 {
+		t.Errorf("scaling = %v, %v", scale, offset)
+	}
+	if property := cloudCoverProperty(id); property != "cloud_percent" {
+		t.Errorf("cloudCoverProperty = %q", property)
+	}
+	if !hasCloudMask(id) {
+		t.Error("hasCloudMask = false for a dataset with CloudMask")
+	}
+	maskClouds(&earthengine.Image{}, id, "")
+	if !masked {
+		t.Error("registered CloudMask was not used")
+	}
+
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.5}}`)
+	if _, err := NDVI(client, 45.5, -122.6, "2023-06-01", CustomDataset(id)); err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+	request := lastRequest()
+	for _, want := range []string{id, `"b7"`, `"b5"`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+
+	if err := RegisterDataset(DatasetDef{}); err == nil {
+		t.Error("expected error for missing ID")
+	}
+}
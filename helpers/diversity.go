@@ -0,0 +1,77 @@
+package helpers
+
+import "math"
+
+// ShannonDiversity returns the Shannon diversity index H = -Σ p·ln(p) of the
+// class counts, where p is each class's share of the total. Classes with
+// non-positive counts are ignored; empty and single-class inputs have a
+// diversity of 0.
+//
+// Example:
+//
+//	// Land cover pixel counts by class
+//	h := helpers.ShannonDiversity(map[int]int{11: 1200, 41: 3400, 82: 900})
+func ShannonDiversity(counts map[int]int) float64 {
+	total := classTotal(counts)
+	if total == 0 {
+		return 0
+	}
+
+	h := 0.0
+	for _, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		p := float64(count) / total
+		h -= p * math.Log(p)
+	}
+	return h
+}
+
+// ShannonEvenness returns the Shannon diversity divided by its maximum,
+// ln(S) for S classes present, so 1 means every class is equally common.
+// It is 0 for empty and single-class inputs.
+func ShannonEvenness(counts map[int]int) float64 {
+	classes := 0
+	for _, count := range counts {
+		if count > 0 {
+			classes++
+		}
+	}
+	if classes < 2 {
+		return 0
+	}
+	return ShannonDiversity(counts) / math.Log(float64(classes))
+}
+
+// SimpsonDiversity returns the Simpson diversity index 1 - Σ p², the
+// probability that two pixels drawn at random (with replacement) belong to
+// different classes. Classes with non-positive counts are ignored; empty and
+// single-class inputs have a diversity of 0.
+func SimpsonDiversity(counts map[int]int) float64 {
+	total := classTotal(counts)
+	if total == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		p := float64(count) / total
+		sum += p * p
+	}
+	return 1 - sum
+}
+
+// classTotal returns the sum of the positive class counts.
+func classTotal(counts map[int]int) float64 {
+	total := 0
+	for _, count := range counts {
+		if count > 0 {
+			total += count
+		}
+	}
+	return float64(total)
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShannonDiversity(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[int]int
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single class", map[int]int{1: 500}, 0},
+		{"two equal classes", map[int]int{1: 50, 2: 50}, math.Ln2},
+		{"four equal classes", map[int]int{1: 10, 2: 10, 3: 10, 4: 10}, math.Log(4)},
+		{"zero counts ignored", map[int]int{1: 50, 2: 50, 3: 0}, math.Ln2},
+		{"uneven", map[int]int{1: 75, 2: 25}, -(0.75*math.Log(0.75) + 0.25*math.Log(0.25))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShannonDiversity(tt.counts); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("ShannonDiversity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShannonEvenness(t *testing.T) {
+	if got := ShannonEvenness(map[int]int{1: 10, 2: 10, 3: 10}); math.Abs(got-1) > 1e-12 {
+		t.Errorf("evenness of equal classes = %v, want 1", got)
+	}
+	if got := ShannonEvenness(map[int]int{1: 90, 2: 5, 3: 5}); got <= 0 || got >= 1 {
+		t.Errorf("evenness of uneven classes = %v, want between 0 and 1", got)
+	}
+	if got := ShannonEvenness(map[int]int{7: 100, 8: 0}); got != 0 {
+		t.Errorf("evenness of a single class = %v, want 0", got)
+	}
+}
+
+func TestSimpsonDiversity(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[int]int
+		want   float64
+	}{
+		{"empty", map[int]int{}, 0},
+		{"single class", map[int]int{3: 42}, 0},
+		{"two equal classes", map[int]int{1: 50, 2: 50}, 0.5},
+		{"uneven", map[int]int{1: 75, 2: 25}, 1 - (0.75*0.75 + 0.25*0.25)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SimpsonDiversity(tt.counts); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("SimpsonDiversity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -35,8 +35,9 @@ const (
 type ElevationOption func(*elevationConfig)
 
 type elevationConfig struct {
-	dataset string
-	scale   *float64
+	dataset        string
+	scale          *float64
+	flowIterations int
 }
 
 // SRTM uses the SRTM 30m dataset (default, near-global coverage).
@@ -74,6 +75,15 @@ func ElevationWithScale(meters float64) ElevationOption {
 	}
 }
 
+// FlowIterations sets the number of routing passes FlowAccumulation and
+// Watershed make, which is the longest flow path, in cells, they follow. More
+// passes cost proportionally more computation. The default is 100.
+func FlowIterations(n int) ElevationOption {
+	return func(cfg *elevationConfig) {
+		cfg.flowIterations = n
+	}
+}
+
 // Elevation returns the elevation in meters at the specified point.
 //
 // By default, uses SRTM 30m data. Use options to customize:
@@ -314,9 +324,9 @@ func AspectWithContext(ctx context.Context, client *earthengine.Client, lat, lon
 
 // TerrainMetrics contains comprehensive terrain analysis results.
 type TerrainMetrics struct {
-	Elevation float64 // Elevation in meters
-	Slope     float64 // Slope in degrees (0-90)
-	Aspect    float64 // Aspect in degrees (0-360)
+	Elevation float64 `json:"elevation"` // Elevation in meters
+	Slope     float64 `json:"slope"`     // Slope in degrees (0-90)
+	Aspect    float64 `json:"aspect"`    // Aspect in degrees (0-360)
 	// Future: Curvature, TPI, TRI when terrain algorithms are implemented
 }
 
@@ -357,6 +367,484 @@ func TerrainAnalysisWithContext(ctx context.Context, client *earthengine.Client,
 	return metrics, nil
 }
 
+// Flow routing defaults.
+const (
+	// defaultFlowIterations is the number of routing passes FlowAccumulation
+	// and Watershed make unless FlowIterations is set.
+	defaultFlowIterations = 100
+
+	// flowCRS is the projection flow is routed in. Web Mercator pixels are
+	// square, so the D8 neighbor distances hold at any latitude.
+	flowCRS = "EPSG:3857"
+)
+
+// d8Neighbors are the eight neighbors of a cell, by their "x_y" position in
+// the bands of Image.NeighborhoodToBands(1), with the ESRI D8 code for flow
+// toward them and their distance in cells. They run clockwise from east, so
+// the neighbor opposite neighbor i is neighbor (i+4)%8.
+var d8Neighbors = []struct {
+	position string
+	code     int
+	distance float64
+}{
+	{"2_1", 1, 1},            // East
+	{"2_2", 2, math.Sqrt2},   // Southeast
+	{"1_2", 4, 1},            // South
+	{"0_2", 8, math.Sqrt2},   // Southwest
+	{"0_1", 16, 1},           // West
+	{"0_0", 32, math.Sqrt2},  // Northwest
+	{"1_0", 64, 1},           // North
+	{"2_0", 128, math.Sqrt2}, // Northeast
+}
+
+// FlowAccumulation returns an image of D8 flow routing over the DEM within
+// bounds, with two bands:
+//   - "flow_direction": the ESRI D8 code of the steepest downhill neighbor
+//     (1 east, 2 southeast, 4 south, 8 southwest, 16 west, 32 northwest,
+//     64 north, 128 northeast), or 0 where no neighbor is lower
+//   - "flow_accumulation": the number of cells, including the cell itself,
+//     that drain through each cell
+//
+// Flow is routed on square pixels at the DEM's resolution (see
+// ElevationWithScale) at the center of bounds. Cells outside bounds still
+// contribute flow, so streams entering the area carry their upstream
+// accumulation.
+//
+// This is an approximation, useful for screening drainage patterns and
+// locating streams, not a replacement for dedicated hydrology tools such as
+// TauDEM, WhiteboxTools, or GRASS r.watershed:
+//   - All of a cell's flow goes to one neighbor, so divergent flow on
+//     hillslopes and fans is not represented.
+//   - Depressions are not filled or breached and flats are not resolved, so
+//     flow stops at pits, flat areas, lakes, and DEM artifacts such as
+//     bridges and dams. Accumulation is underestimated downstream of them.
+//   - Flow is routed for a fixed number of passes (see FlowIterations), so
+//     only flow paths up to that many cells long are counted.
+//
+// For large rivers and basins, precomputed hydrography such as MERIT Hydro
+// or HydroSHEDS is more accurate.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -105.6, MinLat: 39.9, MaxLon: -105.4, MaxLat: 40.1}
+//	flow, err := helpers.FlowAccumulation(ctx, client, bounds)
+//	streams := flow.Select("flow_accumulation").Gte(500)
+func FlowAccumulation(ctx context.Context, client *earthengine.Client, bounds Bounds, opts ...ElevationOption) (*earthengine.Image, error) {
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, err
+	}
+
+	centerLat, _ := bounds.Center()
+	grid, err := newFlowGrid(client, centerLat, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := grid.direction()
+	accumulation := grid.accumulation(direction)
+	return accumulation.AddBands(direction).Clip(region), nil
+}
+
+// Watershed returns a mask of the area draining to a pour point, as a
+// "watershed" band that is 1 in the watershed and masked elsewhere, clipped
+// to the farthest extent flow can be routed from.
+//
+// Flow is routed as in FlowAccumulation and shares its limits. In particular,
+// the pour point must lie on the D8 flow path of the stream it closes: a
+// point a cell or two off the channel yields a tiny watershed. Snap pour
+// points to the highest flow_accumulation cell nearby first.
+//
+// Example:
+//
+//	basin, err := helpers.Watershed(ctx, client, 40.0150, -105.2705,
+//	    helpers.FlowIterations(300))
+//	area := basin.PixelArea().UpdateMask(basin)
+func Watershed(ctx context.Context, client *earthengine.Client, lat, lon float64, opts ...ElevationOption) (*earthengine.Image, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+
+	grid, err := newFlowGrid(client, lat, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Watershed cells can be no farther from the pour point than flow
+	// travels in the routing passes
+	cell, err := boundsAround(lat, lon, grid.scale/2).ToRectangle()
+	if err != nil {
+		return nil, err
+	}
+	extent, err := boundsAround(lat, lon, float64(grid.iterations)*grid.scale*math.Sqrt2).ToRectangle()
+	if err != nil {
+		return nil, err
+	}
+
+	direction := grid.direction().Rename("dir")
+
+	// Each pass adds the cells that flow into the watershed
+	inflow := "b('ws_1_1')"
+	for _, n := range d8Neighbors {
+		inflow = fmt.Sprintf("max(%s, (b('dir') == %d) * b('ws_%s'))", inflow, n.code, n.position)
+	}
+
+	watershed := grid.project(client.Constant(1).Clip(cell).Unmask(0).Rename("ws"))
+	for i := 0; i < grid.iterations; i++ {
+		watershed = grid.project(watershed.NeighborhoodToBands(1).
+			AddBands(direction).
+			Expression(inflow, nil).
+			Rename("ws"))
+	}
+
+	return watershed.UpdateMask(watershed).Rename("watershed").Clip(extent), nil
+}
+
+// flowGrid is the DEM prepared for D8 flow routing.
+type flowGrid struct {
+	client     *earthengine.Client
+	dem        *earthengine.Image // Single band "z"
+	scale      float64            // Ground meters per pixel
+	mercScale  float64            // Web Mercator meters per pixel
+	iterations int
+}
+
+// newFlowGrid loads the DEM selected by opts for routing flow near
+// centerLat.
+func newFlowGrid(client *earthengine.Client, centerLat float64, opts []ElevationOption) (*flowGrid, error) {
+	cfg := &elevationConfig{
+		dataset:        srtmDatasetID,
+		flowIterations: defaultFlowIterations,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.flowIterations <= 0 {
+		return nil, fmt.Errorf("flow iterations must be positive, got %d", cfg.flowIterations)
+	}
+
+	band, scale, err := elevationSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := &flowGrid{
+		client:     client,
+		scale:      scale,
+		mercScale:  scale / math.Cos(degreesToRadians(centerLat)),
+		iterations: cfg.flowIterations,
+	}
+	grid.dem = grid.project(client.Image(cfg.dataset).Select(band).Rename("z"))
+	return grid, nil
+}
+
+// project pins img to the routing grid. Neighborhood passes must all run on
+// the same pixels, whatever projection the result is requested in.
+func (g *flowGrid) project(img *earthengine.Image) *earthengine.Image {
+	return img.Reproject(flowCRS, g.mercScale)
+}
+
+// direction returns the D8 flow direction of each cell as a
+// "flow_direction" band.
+func (g *flowGrid) direction() *earthengine.Image {
+	neighborhood := g.dem.NeighborhoodToBands(1)
+
+	// Drop per cell of distance toward each neighbor
+	drops := make([]string, len(d8Neighbors))
+	for i, n := range d8Neighbors {
+		drops[i] = fmt.Sprintf("(b('z_1_1') - b('z_%s')) / %f", n.position, n.distance)
+	}
+
+	steepest := drops[0]
+	for _, drop := range drops[1:] {
+		steepest = fmt.Sprintf("max(%s, %s)", steepest, drop)
+	}
+
+	code := "0"
+	for i := len(d8Neighbors) - 1; i >= 0; i-- {
+		code = fmt.Sprintf("%s == b('steepest') ? %d : %s", drops[i], d8Neighbors[i].code, code)
+	}
+
+	withSteepest := neighborhood.AddBands(neighborhood.Expression(steepest, nil).Rename("steepest"))
+	return g.project(withSteepest.
+		Expression(fmt.Sprintf("b('steepest') <= 0 ? 0 : %s", code), nil).
+		Rename("flow_direction"))
+}
+
+// accumulation returns the number of cells draining through each cell as a
+// "flow_accumulation" band, given D8 directions.
+func (g *flowGrid) accumulation(direction *earthengine.Image) *earthengine.Image {
+	neighborDirections := direction.Rename("dir").NeighborhoodToBands(1)
+
+	// Each pass adds the accumulation of the neighbors that flow into a cell
+	inflow := "1"
+	for i, n := range d8Neighbors {
+		toward := d8Neighbors[(i+4)%len(d8Neighbors)].code
+		inflow += fmt.Sprintf(" + (b('dir_%s') == %d) * b('acc_%s')", n.position, toward, n.position)
+	}
+
+	accumulation := g.project(g.client.Constant(1).Rename("acc"))
+	for i := 0; i < g.iterations; i++ {
+		accumulation = g.project(accumulation.NeighborhoodToBands(1).
+			AddBands(neighborDirections).
+			Expression(inflow, nil).
+			Rename("acc"))
+	}
+
+	return accumulation.Rename("flow_accumulation")
+}
+
+// boundsAround returns the box extending meters from a point on each side.
+func boundsAround(lat, lon, meters float64) Bounds {
+	dLat := meters / metersPerDegreeLat
+	dLon := meters / (metersPerDegreeLat * math.Cos(degreesToRadians(lat)))
+	return Bounds{
+		MinLon: math.Max(lon-dLon, -180),
+		MinLat: math.Max(lat-dLat, -90),
+		MaxLon: math.Min(lon+dLon, 180),
+		MaxLat: math.Min(lat+dLat, 90),
+	}
+}
+
+// ElevationBand is the part of a region's area within one elevation band.
+type ElevationBand struct {
+	Min           float64 `json:"min"`            // Lower bound in meters
+	Max           float64 `json:"max"`            // Upper bound in meters
+	Area          float64 `json:"area"`           // Square meters
+	AreaFraction  float64 `json:"area_fraction"`  // Fraction of the region's area, 0-1
+	FractionAbove float64 `json:"fraction_above"` // Fraction of the region's area at or above Min, 0-1
+}
+
+// HypsometryResult is the distribution of a region's area over elevation.
+type HypsometryResult struct {
+	Bands               []ElevationBand `json:"bands"`                // Lowest first
+	TotalArea           float64         `json:"total_area"`           // Square meters
+	MinElevation        float64         `json:"min_elevation"`        // Meters
+	MaxElevation        float64         `json:"max_elevation"`        // Meters
+	MeanElevation       float64         `json:"mean_elevation"`       // Area-weighted, meters
+	MedianElevation     float64         `json:"median_elevation"`     // Area-weighted, interpolated within its band
+	HypsometricIntegral float64         `json:"hypsometric_integral"` // 0-1; NaN if the region is flat
+}
+
+// Hypsometry returns the area of a region in each elevation band of
+// bandWidth meters, with summary statistics of its elevation.
+//
+// Bands are aligned to multiples of bandWidth, so the lowest band is
+// narrowed to start at the region's minimum elevation and the highest to
+// end at its maximum. Every band in between is included, with zero area if
+// no part of the region lies in it. FractionAbove traces the hypsometric
+// curve.
+//
+// Areas are in square meters and account for each pixel's true area, which
+// shrinks toward the poles in geographic projections; mean and median
+// elevation are weighted by area too. The hypsometric integral is estimated
+// as the elevation-relief ratio, (mean - min) / (max - min): values near 1
+// suggest youthful, little-eroded terrain or a glacier's accumulation-heavy
+// profile, values near 0 old, eroded terrain.
+//
+// The DEM is read at its native resolution unless ElevationWithScale is set.
+//
+// Example:
+//
+//	glacier := earthengine.NewPolygon(coords)
+//	hyps, err := helpers.Hypsometry(ctx, client, glacier, 50, helpers.ALOS())
+//	for _, band := range hyps.Bands {
+//	    fmt.Printf("%4.0f-%4.0f m: %6.2f km²\n", band.Min, band.Max, band.Area/1e6)
+//	}
+//	fmt.Printf("HI: %.2f\n", hyps.HypsometricIntegral)
+func Hypsometry(ctx context.Context, client *earthengine.Client, region earthengine.Geometry, bandWidth float64, opts ...ElevationOption) (*HypsometryResult, error) {
+	if region == nil {
+		return nil, fmt.Errorf("region cannot be nil")
+	}
+	if bandWidth <= 0 || math.IsNaN(bandWidth) || math.IsInf(bandWidth, 0) {
+		return nil, fmt.Errorf("band width must be positive, got %v", bandWidth)
+	}
+
+	cfg := &elevationConfig{dataset: srtmDatasetID}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	band, scale, err := elevationSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dem := client.Image(cfg.dataset).Select(band).Rename("elevation")
+	area := dem.PixelArea().UpdateMask(dem.Mask()).Rename("area")
+
+	// Area-weighted elevation sum, total area, and elevation range in one
+	// reduction
+	stats, err := dem.Multiply(area).Rename("weighted").
+		AddBands(area).
+		AddBands(dem).
+		ReduceRegion(region,
+			earthengine.ReducerCombine(earthengine.ReducerSum(), earthengine.ReducerMin(), earthengine.ReducerMax()),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute elevation statistics: %w", err)
+	}
+
+	totalArea, _ := stats["area_sum"].(float64)
+	if totalArea <= 0 {
+		return nil, fmt.Errorf("no elevation data in region")
+	}
+	weighted, ok := stats["weighted_sum"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected weighted elevation in result: %v", stats["weighted_sum"])
+	}
+	minElevation, ok := stats["elevation_min"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected minimum elevation in result: %v", stats["elevation_min"])
+	}
+	maxElevation, ok := stats["elevation_max"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected maximum elevation in result: %v", stats["elevation_max"])
+	}
+
+	// Area of each band, grouped by band index floor(elevation / bandWidth)
+	index := dem.Divide(client.Constant(bandWidth)).Expression("floor(b(0))", nil).Rename("band")
+	grouped, err := area.AddBands(index).
+		ReduceRegion(region,
+			earthengine.ReducerGroup(earthengine.ReducerSum(), 1, "band"),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute elevation band areas: %w", err)
+	}
+
+	bandAreas, err := parseBandAreas(grouped)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HypsometryResult{
+		MinElevation:        minElevation,
+		MaxElevation:        maxElevation,
+		MeanElevation:       weighted / totalArea,
+		HypsometricIntegral: math.NaN(),
+	}
+	if maxElevation > minElevation {
+		result.HypsometricIntegral = (result.MeanElevation - minElevation) / (maxElevation - minElevation)
+	}
+	result.Bands, result.TotalArea = hypsometryBands(bandAreas, bandWidth, minElevation, maxElevation)
+	result.MedianElevation = hypsometryMedian(result.Bands, result.TotalArea)
+
+	return result, nil
+}
+
+// parseBandAreas reads the area of each band index from a grouped sum.
+func parseBandAreas(stats map[string]interface{}) (map[int]float64, error) {
+	groups, ok := stats["groups"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected elevation band areas in result: %v", stats["groups"])
+	}
+
+	areas := make(map[int]float64, len(groups))
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected elevation band in result: %v", g)
+		}
+		index, ok := group["band"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected elevation band index in result: %v", group["band"])
+		}
+		area, ok := group["sum"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected elevation band area in result: %v", group["sum"])
+		}
+		areas[int(math.Round(index))] += area
+	}
+	return areas, nil
+}
+
+// hypsometryBands builds the elevation bands from minElevation to
+// maxElevation, filling bands without area, and returns them with their
+// total area.
+func hypsometryBands(areas map[int]float64, bandWidth, minElevation, maxElevation float64) ([]ElevationBand, float64) {
+	lowest := int(math.Floor(minElevation / bandWidth))
+	highest := int(math.Floor(maxElevation / bandWidth))
+	total := 0.0
+	for index, area := range areas {
+		if index < lowest {
+			lowest = index
+		}
+		if index > highest {
+			highest = index
+		}
+		total += area
+	}
+
+	bands := make([]ElevationBand, 0, highest-lowest+1)
+	above := total
+	for index := lowest; index <= highest; index++ {
+		area := areas[index]
+		band := ElevationBand{
+			Min:  math.Max(float64(index)*bandWidth, minElevation),
+			Max:  math.Min(float64(index+1)*bandWidth, maxElevation),
+			Area: area,
+		}
+		if total > 0 {
+			band.AreaFraction = area / total
+			band.FractionAbove = above / total
+		}
+		bands = append(bands, band)
+		above -= area
+	}
+	return bands, total
+}
+
+// hypsometryMedian returns the elevation below which half the area lies,
+// interpolating linearly within its band.
+func hypsometryMedian(bands []ElevationBand, total float64) float64 {
+	half := total / 2
+	below := 0.0
+	for _, band := range bands {
+		if band.Area > 0 && below+band.Area >= half {
+			return band.Min + (band.Max-band.Min)*(half-below)/band.Area
+		}
+		below += band.Area
+	}
+	return math.NaN()
+}
+
+// elevationSource returns the elevation band and default scale of the
+// dataset in cfg, or the scale set with ElevationWithScale.
+func elevationSource(cfg *elevationConfig) (string, float64, error) {
+	var band string
+	var scale float64
+
+	switch cfg.dataset {
+	case srtmDatasetID:
+		band = srtmElevBand
+		scale = srtmDefaultScale
+	case asterDatasetID:
+		band = asterElevBand
+		scale = asterDefaultScale
+	case alosDatasetID:
+		band = alosElevBand
+		scale = alosDefaultScale
+	case usgs3DEPDatasetID:
+		band = usgs3DEPElevBand
+		scale = usgs3DEPDefaultScale
+	default:
+		return "", 0, fmt.Errorf("unknown dataset: %s", cfg.dataset)
+	}
+
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+	return band, scale, nil
+}
+
 // ElevationQuery represents a deferred elevation query for batch operations.
 type ElevationQuery struct {
 	lat  float64
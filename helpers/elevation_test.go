@@ -1,9 +1,15 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math"
+	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 func TestElevationOptions(t *testing.T) {
@@ -187,3 +193,201 @@ func ExampleTerrainAnalysis() {
 	// fmt.Printf("Slope: %.1f degrees\n", metrics.Slope)
 	// fmt.Printf("Aspect: %.0f degrees\n", metrics.Aspect)
 }
+
+func TestFlowAccumulation(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	ctx := context.Background()
+	bounds := Bounds{MinLon: -105.6, MinLat: 39.9, MaxLon: -105.4, MaxLat: 40.1}
+
+	img, err := FlowAccumulation(ctx, client, bounds, FlowIterations(3))
+	if err != nil {
+		t.Fatalf("FlowAccumulation failed: %v", err)
+	}
+	data, err := img.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	graph := string(data)
+
+	// One neighborhood for directions, one for neighbor directions, and one
+	// per routing pass
+	if got := strings.Count(graph, earthengine.AlgorithmImageNeighborhoodToBands); got != 5 {
+		t.Errorf("neighborhoodToBands calls = %d, want 5", got)
+	}
+	for _, want := range []string{
+		srtmDatasetID, flowCRS, "flow_accumulation", "flow_direction",
+		"(b('z_1_1') - b('z_2_2')) / 1.414214",
+		"(b('dir_0_1') == 1) * b('acc_0_1')",
+	} {
+		if !strings.Contains(graph, want) {
+			t.Errorf("graph does not contain %q", want)
+		}
+	}
+
+	// Pixels are square Web Mercator meters, 30 m on the ground at the center
+	wantScale := 30 / math.Cos(degreesToRadians(40))
+	if !strings.Contains(graph, fmt.Sprint(wantScale)) {
+		t.Errorf("graph does not reproject to scale %v", wantScale)
+	}
+
+	if _, err := FlowAccumulation(ctx, client, Bounds{MinLon: 1, MinLat: 1, MaxLon: 0, MaxLat: 0}); err == nil {
+		t.Error("expected error for invalid bounds")
+	}
+	if _, err := FlowAccumulation(ctx, client, bounds, FlowIterations(0)); err == nil {
+		t.Error("expected error for zero iterations")
+	}
+}
+
+func TestWatershed(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	ctx := context.Background()
+
+	img, err := Watershed(ctx, client, 40.0150, -105.2705, FlowIterations(2), ALOS())
+	if err != nil {
+		t.Fatalf("Watershed failed: %v", err)
+	}
+	data, err := img.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	graph := string(data)
+
+	if got := strings.Count(graph, earthengine.AlgorithmImageNeighborhoodToBands); got != 3 {
+		t.Errorf("neighborhoodToBands calls = %d, want 3", got)
+	}
+	for _, want := range []string{
+		alosDatasetID, earthengine.AlgorithmImageUnmask, "watershed",
+		"(b('dir') == 128) * b('ws_2_0')",
+	} {
+		if !strings.Contains(graph, want) {
+			t.Errorf("graph does not contain %q", want)
+		}
+	}
+
+	if _, err := Watershed(ctx, client, 91, 0); err == nil {
+		t.Error("expected error for invalid latitude")
+	}
+	if _, err := Watershed(ctx, client, 40, -105, FlowIterations(-1)); err == nil {
+		t.Error("expected error for negative iterations")
+	}
+}
+
+func TestD8NeighborsOpposite(t *testing.T) {
+	for i, n := range d8Neighbors {
+		opposite := d8Neighbors[(i+4)%len(d8Neighbors)]
+		var x, y, ox, oy int
+		fmt.Sscanf(n.position, "%d_%d", &x, &y)
+		fmt.Sscanf(opposite.position, "%d_%d", &ox, &oy)
+		if x+ox != 2 || y+oy != 2 {
+			t.Errorf("neighbor %s: opposite %s is not mirrored through the center", n.position, opposite.position)
+		}
+		if n.distance != opposite.distance {
+			t.Errorf("neighbor %s: distance %v, opposite %v", n.position, n.distance, opposite.distance)
+		}
+	}
+}
+
+func TestBoundsAround(t *testing.T) {
+	b := boundsAround(60, 10, metersPerDegreeLat)
+	if math.Abs(b.MaxLat-61) > 1e-9 || math.Abs(b.MinLat-59) > 1e-9 {
+		t.Errorf("latitudes = %v to %v, want 59 to 61", b.MinLat, b.MaxLat)
+	}
+	// A degree of longitude is half as long at 60 degrees
+	if math.Abs(b.MaxLon-12) > 1e-9 || math.Abs(b.MinLon-8) > 1e-9 {
+		t.Errorf("longitudes = %v to %v, want 8 to 12", b.MinLon, b.MaxLon)
+	}
+}
+
+// newHypsometryTestClient answers the grouped band area reduction with groups
+// and the statistics reduction with stats.
+func newHypsometryTestClient(t *testing.T, stats, groups string) *earthengine.Client {
+	t.Helper()
+
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), earthengine.AlgorithmReducerGroup) {
+			fmt.Fprintf(w, `{"result": {"groups": %s}}`, groups)
+			return
+		}
+		fmt.Fprintf(w, `{"result": %s}`, stats)
+	})
+}
+
+func TestHypsometry(t *testing.T) {
+	client := newHypsometryTestClient(t,
+		`{"weighted_sum": 240000, "area_sum": 1000, "elevation_min": 150, "elevation_max": 380}`,
+		`[{"band": 1, "sum": 300}, {"band": 3, "sum": 100}, {"band": 2, "sum": 600}]`)
+	region := earthengine.NewBuffer(earthengine.NewPoint(-105.5, 40), 1000)
+
+	hyps, err := Hypsometry(context.Background(), client, region, 100)
+	if err != nil {
+		t.Fatalf("Hypsometry failed: %v", err)
+	}
+
+	// The open bottom and top bands are narrowed to the elevation range
+	want := []ElevationBand{
+		{Min: 150, Max: 200, Area: 300, AreaFraction: 0.3, FractionAbove: 1},
+		{Min: 200, Max: 300, Area: 600, AreaFraction: 0.6, FractionAbove: 0.7},
+		{Min: 300, Max: 380, Area: 100, AreaFraction: 0.1, FractionAbove: 0.1},
+	}
+	if len(hyps.Bands) != len(want) {
+		t.Fatalf("got %d bands, want %d: %+v", len(hyps.Bands), len(want), hyps.Bands)
+	}
+	for i, band := range hyps.Bands {
+		w := want[i]
+		if band.Min != w.Min || band.Max != w.Max || band.Area != w.Area ||
+			math.Abs(band.AreaFraction-w.AreaFraction) > 1e-9 || math.Abs(band.FractionAbove-w.FractionAbove) > 1e-9 {
+			t.Errorf("band %d = %+v, want %+v", i, band, w)
+		}
+	}
+
+	if hyps.TotalArea != 1000 || hyps.MinElevation != 150 || hyps.MaxElevation != 380 || hyps.MeanElevation != 240 {
+		t.Errorf("area = %v, elevation min/max/mean = %v/%v/%v, want 1000, 150/380/240",
+			hyps.TotalArea, hyps.MinElevation, hyps.MaxElevation, hyps.MeanElevation)
+	}
+	// Half the area, 500 m², is 200 m² into the 600 m² 200-300 m band
+	if want := 200 + 100*200.0/600; math.Abs(hyps.MedianElevation-want) > 1e-9 {
+		t.Errorf("MedianElevation = %v, want %v", hyps.MedianElevation, want)
+	}
+	if want := 90.0 / 230; math.Abs(hyps.HypsometricIntegral-want) > 1e-9 {
+		t.Errorf("HypsometricIntegral = %v, want %v", hyps.HypsometricIntegral, want)
+	}
+}
+
+func TestHypsometryBandsFillGaps(t *testing.T) {
+	bands, total := hypsometryBands(map[int]float64{-1: 40, 2: 60}, 50, -20, 110)
+	if total != 100 {
+		t.Errorf("total = %v, want 100", total)
+	}
+
+	wantMins := []float64{-20, 0, 50, 100}
+	wantAreas := []float64{40, 0, 0, 60}
+	if len(bands) != len(wantMins) {
+		t.Fatalf("got %d bands, want %d: %+v", len(bands), len(wantMins), bands)
+	}
+	for i, band := range bands {
+		if band.Min != wantMins[i] || band.Area != wantAreas[i] {
+			t.Errorf("band %d = %+v, want min %v and area %v", i, band, wantMins[i], wantAreas[i])
+		}
+	}
+	if bands[3].Max != 110 || bands[3].FractionAbove != 0.6 {
+		t.Errorf("top band = %+v, want max 110 and fraction above 0.6", bands[3])
+	}
+}
+
+func TestHypsometryErrors(t *testing.T) {
+	ctx := context.Background()
+	region := earthengine.NewBuffer(earthengine.NewPoint(-105.5, 40), 1000)
+
+	client := newHypsometryTestClient(t, `{"area_sum": 0}`, `[]`)
+	if _, err := Hypsometry(ctx, client, region, 100); err == nil {
+		t.Error("expected error for region without elevation data")
+	}
+	if _, err := Hypsometry(ctx, client, nil, 100); err == nil {
+		t.Error("expected error for nil region")
+	}
+	if _, err := Hypsometry(ctx, client, region, 0); err == nil {
+		t.Error("expected error for zero band width")
+	}
+}
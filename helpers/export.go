@@ -2,9 +2,14 @@ package helpers
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 // ExportDestination represents where to export data.
@@ -72,8 +77,16 @@ type ExportConfig struct {
 	// Max pixels to export
 	MaxPixels int64
 
+	// Fail instead of warning when an export's estimated size exceeds
+	// MaxPixels (see EstimateExportSize)
+	StrictMaxPixels bool
+
 	// File dimensions
 	FileDimensions []int
+
+	// Request ID making the submission idempotent; derived from the export
+	// if empty (see ExportRequestID)
+	RequestID string
 }
 
 // ExportImageConfig creates an export configuration for image exports.
@@ -139,6 +152,15 @@ func ExportMaxPixels(maxPixels int64) ExportImageOption {
 	}
 }
 
+// ExportStrictMaxPixels makes ExportImageAsync return an error, rather than
+// log an EventExportSizeWarning, when the estimated pixel count of an export with a region
+// exceeds MaxPixels or cannot be estimated.
+func ExportStrictMaxPixels() ExportImageOption {
+	return func(cfg *ExportConfig) {
+		cfg.StrictMaxPixels = true
+	}
+}
+
 // ExportFormat sets the export format.
 func ExportFileFormat(format ExportFormat) ExportImageOption {
 	return func(cfg *ExportConfig) {
@@ -146,6 +168,245 @@ func ExportFileFormat(format ExportFormat) ExportImageOption {
 	}
 }
 
+// ExportRequestID sets the request ID that makes submitting the export
+// idempotent: Earth Engine starts one export per ID, so resubmitting after a
+// timeout or network error returns the original operation instead of
+// starting a duplicate.
+//
+// The ID must be unique per distinct export but stable across retries of the
+// same one. If it is not set, NewExportImageRequest derives one from the
+// image and export settings, so retrying an identical export is always safe;
+// set a new ID to deliberately run an identical export again.
+//
+// Example:
+//
+//	req, err := helpers.NewExportImageRequest(image,
+//	    helpers.ExportToGCS("my-bucket", "ndvi/"),
+//	    helpers.ExportRequestID("ndvi-2023-run-7"))
+func ExportRequestID(id string) ExportImageOption {
+	return func(cfg *ExportConfig) {
+		cfg.RequestID = id
+	}
+}
+
+// NewExportImageRequest builds the apiv1 request that exports image to Cloud
+// Storage or Drive, for use with service.Projects.Image.Export.
+//
+// The request always has a RequestId (see ExportRequestID), so it can be
+// retried safely, for example with apiv1.WithRetryPolicy. The image is
+// clipped to the export region if one is set, and the pixel grid uses the
+// export CRS and scale; for geographic CRSs the scale is converted to
+// degrees at the equator.
+//
+// Example:
+//
+//	req, err := helpers.NewExportImageRequest(ndvi,
+//	    helpers.ExportDescription("Summer 2023 NDVI"),
+//	    helpers.ExportToGCS("my-bucket", "ndvi/"),
+//	    helpers.ExportScale(10),
+//	    helpers.ExportCRS("EPSG:32610"))
+//	op, err := service.Projects.Image.Export(ctx, "projects/my-project", req)
+//	task := client.OperationTask(op.Name)
+func NewExportImageRequest(image *earthengine.Image, opts ...ExportImageOption) (*apiv1.ExportImageRequest, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+
+	// Apply options
+	cfg := &ExportConfig{
+		Description: "Export",
+		Destination: ExportToCloudStorage,
+		Format:      GeoTIFF,
+		Scale:       30,
+		CRS:         "EPSG:4326",
+		MaxPixels:   1e9,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Validate configuration
+	if err := validateExportConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	fileOptions := &apiv1.FileExportOptions{}
+	switch cfg.Format {
+	case GeoTIFF:
+		fileOptions.FileFormat = "GEO_TIFF"
+	case TFRecord:
+		fileOptions.FileFormat = "TF_RECORD_IMAGE"
+	default:
+		return nil, fmt.Errorf("unsupported image export format: %s", cfg.Format)
+	}
+	switch cfg.Destination {
+	case ExportToCloudStorage:
+		fileOptions.GcsDestination = &apiv1.GcsDestination{Bucket: cfg.Bucket, FilenamePrefix: cfg.Prefix}
+	case ExportToDrive:
+		fileOptions.DriveDestination = &apiv1.DriveDestination{Folder: cfg.Folder, FilenamePrefix: cfg.Description}
+	default:
+		return nil, fmt.Errorf("unsupported destination for image export requests: %s", cfg.Destination)
+	}
+
+	if cfg.Region != nil {
+		image = image.Clip(*cfg.Region)
+	}
+	exprJSON, err := image.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize image: %w", err)
+	}
+	expr := &apiv1.Expression{}
+	if err := json.Unmarshal(exprJSON, expr); err != nil {
+		return nil, fmt.Errorf("failed to convert expression: %w", err)
+	}
+
+	scale := cfg.Scale
+	if cfg.CRS == "EPSG:4326" {
+		scale /= metersPerDegreeLat
+	}
+
+	req := &apiv1.ExportImageRequest{
+		Expression:        expr,
+		Description:       cfg.Description,
+		FileExportOptions: fileOptions,
+		Grid: &apiv1.PixelGrid{
+			CrsCode:         cfg.CRS,
+			AffineTransform: &apiv1.AffineTransform{ScaleX: scale, ScaleY: -scale},
+		},
+		RequestId: cfg.RequestID,
+	}
+	if req.RequestId == "" {
+		req.RequestId, err = exportRequestID(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// ExportSizeEstimate is the approximate size of an image export.
+type ExportSizeEstimate struct {
+	Pixels int64 `json:"pixels"` // Pixels per band: region area / scale²
+	Bands  int   `json:"bands"`  // Number of bands
+	Bytes  int64 `json:"bytes"`  // Uncompressed size: Pixels × the bytes per pixel of every band
+}
+
+// EstimateExportSize estimates the size of exporting image over region at
+// scale meters per pixel, from the region's area and the image's band types.
+// Compare Pixels with the export's MaxPixels before submitting, rather than
+// finding out from a failed task.
+//
+// The estimate is of the region's full extent, so masked pixels are counted,
+// and Bytes ignores compression.
+//
+// Example:
+//
+//	size, err := helpers.EstimateExportSize(ctx, client, image, region, 10)
+//	fmt.Printf("%d pixels, %.1f GB\n", size.Pixels, float64(size.Bytes)/1e9)
+func EstimateExportSize(ctx context.Context, client *earthengine.Client, image *earthengine.Image, region earthengine.Geometry, scale float64) (*ExportSizeEstimate, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if region == nil {
+		return nil, fmt.Errorf("region cannot be nil")
+	}
+	if scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %g", scale)
+	}
+
+	area, err := client.GeometryArea(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	types, err := image.BandTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pixels := int64(math.Ceil(area / (scale * scale)))
+	bytesPerPixel := 0
+	for _, t := range types {
+		bytesPerPixel += pixelTypeBytes(t)
+	}
+
+	return &ExportSizeEstimate{
+		Pixels: pixels,
+		Bands:  len(types),
+		Bytes:  pixels * int64(bytesPerPixel),
+	}, nil
+}
+
+// pixelTypeBytes returns the bytes per pixel of a band type, using the
+// smallest integer type that holds an integer band's range.
+func pixelTypeBytes(t earthengine.PixelType) int {
+	switch strings.ToLower(t.Precision) {
+	case "float":
+		return 4
+	case "double":
+		return 8
+	}
+	for _, size := range []int{1, 2, 4} {
+		bits := float64(8 * size)
+		unsigned := t.Min >= 0 && t.Max <= math.Exp2(bits)-1
+		signed := t.Min >= -math.Exp2(bits-1) && t.Max <= math.Exp2(bits-1)-1
+		if unsigned || signed {
+			return size
+		}
+	}
+	return 8
+}
+
+// EventExportSizeWarning is logged to the client's logger when an export's
+// estimated size exceeds MaxPixels, or cannot be estimated, and
+// StrictMaxPixels is not set. It carries the export "description" and the
+// "error" that StrictMaxPixels would have returned.
+const EventExportSizeWarning = "export size warning"
+
+// checkExportSize estimates the size of exporting image with cfg, which must
+// have a region, and reports an estimate over MaxPixels as an error if
+// StrictMaxPixels is set or as an EventExportSizeWarning otherwise.
+func checkExportSize(ctx context.Context, client *earthengine.Client, image *earthengine.Image, cfg *ExportConfig) error {
+	size, err := EstimateExportSize(ctx, client, image, *cfg.Region, cfg.Scale)
+	if err != nil {
+		err = fmt.Errorf("failed to estimate export size: %w", err)
+		if cfg.StrictMaxPixels {
+			return err
+		}
+		client.Logger().Log(ctx, EventExportSizeWarning, "description", cfg.Description, "error", err)
+		return nil
+	}
+	if size.Pixels <= cfg.MaxPixels {
+		return nil
+	}
+
+	// Scale that brings the export within MaxPixels
+	minScale := cfg.Scale * math.Sqrt(float64(size.Pixels)/float64(cfg.MaxPixels))
+	err = fmt.Errorf("export of about %d pixels exceeds maxPixels (%d); use a scale of at least %.0f m, a smaller region, or a higher ExportMaxPixels",
+		size.Pixels, cfg.MaxPixels, math.Ceil(minScale))
+	if cfg.StrictMaxPixels {
+		return err
+	}
+	client.Logger().Log(ctx, EventExportSizeWarning, "description", cfg.Description, "error", err)
+	return nil
+}
+
+// exportRequestID derives a request ID from everything that defines an
+// export, formatted as a name-based (version 5) UUID.
+func exportRequestID(req interface{}) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive request ID: %w", err)
+	}
+
+	sum := sha1.Sum(append([]byte("go-earthengine/export\x00"), data...))
+	sum[6] = sum[6]&0x0f | 0x50 // Version 5
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+
+	hex := fmt.Sprintf("%x", sum[:16])
+	return strings.Join([]string{hex[0:8], hex[8:12], hex[12:16], hex[16:20], hex[20:32]}, "-"), nil
+}
+
 // ExportImage exports an image to Cloud Storage, Drive, or Assets.
 //
 // Note: This creates an export task configuration. Full implementation requires:
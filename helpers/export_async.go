@@ -2,18 +2,26 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 var taskIDCounter uint64
 
 // ExportImageAsync exports an image and returns a task for monitoring progress.
 //
+// If an export region is set, the export's size is estimated first (see
+// EstimateExportSize), and an EventExportSizeWarning is logged to the client's
+// logger if it exceeds MaxPixels; with ExportStrictMaxPixels, an error is
+// returned instead.
+//
 // Example:
 //
 //	task, err := helpers.ExportImageAsync(ctx, client, image,
@@ -47,6 +55,13 @@ func ExportImageAsync(ctx context.Context, client *earthengine.Client, image *ea
 		return nil, err
 	}
 
+	// Catch exports over maxPixels now rather than when the task fails
+	if cfg.Region != nil {
+		if err := checkExportSize(ctx, client, image, cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create task with unique ID
 	taskID := atomic.AddUint64(&taskIDCounter, 1)
 	task := &earthengine.Task{
@@ -148,6 +163,113 @@ func ExportVideoAsync(ctx context.Context, client *earthengine.Client, collectio
 	return task, nil
 }
 
+// ExportVideoAnimation exports an image collection as a time-lapse video,
+// rendering each frame to 8-bit RGB with vis. Frames are ordered by
+// system:time_start and played at fps frames per second.
+//
+// Raw bands such as reflectance cannot be encoded as video, which is why the
+// frames must be visualized. The export region is required: frames are
+// clipped to its bounds and scaled so their larger side is dimensions pixels.
+//
+// Example:
+//
+//	var region earthengine.Geometry = earthengine.NewRectangle(-122.6, 37.6, -122.3, 37.9)
+//	task, err := helpers.ExportVideoAnimation(ctx, client, collection,
+//	    helpers.VisualizationOptions{Bands: []string{"B4", "B3", "B2"}, Min: 0, Max: 3000},
+//	    12, 720,
+//	    helpers.ExportDescription("Bay Area Time Lapse"),
+//	    helpers.ExportToGCS("my-bucket", "videos/"),
+//	    helpers.ExportRegion(&region))
+func ExportVideoAnimation(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, vis VisualizationOptions, fps int, dimensions int, opts ...ExportImageOption) (*earthengine.Task, error) {
+	req, err := newExportVideoRequest(collection, vis, fps, dimensions, opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.StartVideoExport(ctx, req)
+}
+
+// newExportVideoRequest builds the request for ExportVideoAnimation.
+func newExportVideoRequest(collection *earthengine.ImageCollection, vis VisualizationOptions, fps int, dimensions int, opts []ExportImageOption) (*apiv1.ExportVideoRequest, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+	if err := vis.validate(); err != nil {
+		return nil, err
+	}
+	if fps < 1 || fps > maxVideoFPS {
+		return nil, fmt.Errorf("fps must be between 1 and %d, got %d", maxVideoFPS, fps)
+	}
+	if dimensions < 1 || dimensions > maxVideoDimension {
+		return nil, fmt.Errorf("dimensions must be between 1 and %d, got %d", maxVideoDimension, dimensions)
+	}
+
+	cfg := &ExportConfig{
+		Description: "Video Export",
+		Destination: ExportToCloudStorage,
+		Format:      MP4,
+		CRS:         "EPSG:4326",
+		Scale:       30,
+		MaxPixels:   1e9,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := validateExportConfig(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Format != MP4 {
+		return nil, fmt.Errorf("unsupported video export format: %s", cfg.Format)
+	}
+	if cfg.Region == nil {
+		return nil, fmt.Errorf("export region is required for video exports")
+	}
+
+	fileOptions := &apiv1.FileExportOptions{FileFormat: "MP4"}
+	switch cfg.Destination {
+	case ExportToCloudStorage:
+		fileOptions.GcsDestination = &apiv1.GcsDestination{Bucket: cfg.Bucket, FilenamePrefix: cfg.Prefix}
+	case ExportToDrive:
+		fileOptions.DriveDestination = &apiv1.DriveDestination{Folder: cfg.Folder, FilenamePrefix: cfg.Description}
+	default:
+		return nil, fmt.Errorf("unsupported destination for video exports: %s", cfg.Destination)
+	}
+
+	region := *cfg.Region
+	params := vis.visualizeParams()
+	frames := collection.Sort("system:time_start", true).Map(func(img *earthengine.Image) *earthengine.Image {
+		return img.Visualize(params).ClipToBoundsAndScale(region, dimensions)
+	})
+
+	exprJSON, err := frames.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize collection: %w", err)
+	}
+	expr := &apiv1.Expression{}
+	if err := json.Unmarshal(exprJSON, expr); err != nil {
+		return nil, fmt.Errorf("failed to convert expression: %w", err)
+	}
+
+	req := &apiv1.ExportVideoRequest{
+		Expression:  expr,
+		Description: cfg.Description,
+		VideoOptions: &apiv1.VideoOptions{
+			FramesPerSecond:   float64(fps),
+			MaxPixelsPerFrame: cfg.MaxPixels,
+		},
+		FileExportOptions: fileOptions,
+		RequestId:         cfg.RequestID,
+	}
+	if req.RequestId == "" {
+		req.RequestId, err = exportRequestID(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
 // ExportImageWithNotification exports an image and calls a notification function on completion.
 //
 // The notification function is called in a separate goroutine when the export completes,
@@ -217,6 +339,77 @@ func ExportVideoWithNotification(ctx context.Context, client *earthengine.Client
 	return task, nil
 }
 
+// ExportImages submits one export per image, sharing commonOpts and naming
+// each export with the matching entry of descriptions.
+//
+// Every submission is attempted even if some fail. The returned tasks line up
+// with images, so they can be passed straight to WaitForExports: a failed
+// submission is represented by a task already in the failed state, and the
+// returned error joins the submission errors by index.
+//
+// Each export gets its own stable RequestId, so calling ExportImages again
+// with the same arguments after a partial failure resubmits only the exports
+// that did not start. If commonOpts sets ExportRequestID, the index is
+// appended to it for each image.
+//
+// Example:
+//
+//	tasks, err := helpers.ExportImages(ctx, client,
+//	    []*earthengine.Image{spring, summer},
+//	    []string{"NDVI spring 2023", "NDVI summer 2023"},
+//	    helpers.ExportToGCS("my-bucket", "ndvi/"),
+//	    helpers.ExportScale(10))
+//	if err != nil {
+//	    log.Printf("some exports were not submitted: %v", err)
+//	}
+//	results := helpers.WaitForExports(ctx, tasks, nil)
+func ExportImages(ctx context.Context, client *earthengine.Client, images []*earthengine.Image, descriptions []string, commonOpts ...ExportImageOption) ([]*earthengine.Task, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	if len(images) != len(descriptions) {
+		return nil, fmt.Errorf("got %d images but %d descriptions", len(images), len(descriptions))
+	}
+
+	common := &ExportConfig{}
+	for _, opt := range commonOpts {
+		opt(common)
+	}
+
+	tasks := make([]*earthengine.Task, len(images))
+	var errs []error
+	for i, image := range images {
+		opts := append(commonOpts[:len(commonOpts):len(commonOpts)], ExportDescription(descriptions[i]))
+		if common.RequestID != "" {
+			opts = append(opts, ExportRequestID(fmt.Sprintf("%s-%d", common.RequestID, i)))
+		}
+
+		task, err := exportImage(ctx, client, image, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("export %d (%s): %w", i, descriptions[i], err))
+			task = &earthengine.Task{
+				Type:        "EXPORT_IMAGE",
+				Description: descriptions[i],
+				State:       earthengine.TaskStateFailed,
+				Error:       err.Error(),
+				UpdateTime:  time.Now(),
+			}
+		}
+		tasks[i] = task
+	}
+
+	return tasks, errors.Join(errs...)
+}
+
+// exportImage builds and submits a single image export.
+func exportImage(ctx context.Context, client *earthengine.Client, image *earthengine.Image, opts []ExportImageOption) (*earthengine.Task, error) {
+	req, err := NewExportImageRequest(image, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return client.StartImageExport(ctx, req)
+}
+
 // WaitForExports waits for multiple export tasks to complete.
 //
 // Returns when all tasks complete or when the context is cancelled.
@@ -274,7 +467,7 @@ func WaitForExports(ctx context.Context, tasks []*earthengine.Task, progressFn f
 
 // ExportResult represents the result of an export operation.
 type ExportResult struct {
-	TaskID string
-	Task   *earthengine.Task
-	Error  error
+	TaskID string            `json:"task_id"`
+	Task   *earthengine.Task `json:"task"`
+	Error  error             `json:"error"`
 }
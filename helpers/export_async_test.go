@@ -2,10 +2,17 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 func TestExportImageAsync(t *testing.T) {
@@ -425,3 +432,257 @@ func TestConcurrentExports(t *testing.T) {
 		ids[task.ID] = true
 	}
 }
+
+func TestExportImages(t *testing.T) {
+	var mu sync.Mutex
+	var requests []apiv1.ExportImageRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req apiv1.ExportImageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		requests = append(requests, req)
+		n := len(requests)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"name": "projects/test-project/operations/OP%d", "metadata": {"state": "PENDING"}}`, n)
+	})
+
+	images := []*earthengine.Image{client.Image("A"), nil, client.Image("C")}
+	descriptions := []string{"first", "second", "third"}
+	tasks, err := ExportImages(context.Background(), client, images, descriptions,
+		ExportToGCS("my-bucket", "out/"),
+		ExportRequestID("batch"))
+	if err == nil || !strings.Contains(err.Error(), "export 1 (second)") {
+		t.Errorf("err = %v, want failure of export 1", err)
+	}
+
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3", len(tasks))
+	}
+	if tasks[1].State != earthengine.TaskStateFailed || tasks[1].Error == "" {
+		t.Errorf("failed submission task = %+v", tasks[1])
+	}
+	for _, i := range []int{0, 2} {
+		if tasks[i].State != earthengine.TaskStatePending || tasks[i].Description != descriptions[i] {
+			t.Errorf("task %d = %+v", i, tasks[i])
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if requests[0].RequestId != "batch-0" || requests[1].RequestId != "batch-2" {
+		t.Errorf("request IDs = %q, %q", requests[0].RequestId, requests[1].RequestId)
+	}
+	if requests[1].Description != "third" || requests[1].FileExportOptions.GcsDestination.Bucket != "my-bucket" {
+		t.Errorf("request = %+v", requests[1])
+	}
+}
+
+func TestExportImagesMismatchedDescriptions(t *testing.T) {
+	client := &earthengine.Client{}
+	_, err := ExportImages(context.Background(), client, []*earthengine.Image{{}}, nil)
+	if err == nil {
+		t.Error("expected error for mismatched descriptions")
+	}
+}
+
+func TestExportVideoAnimation(t *testing.T) {
+	var got apiv1.ExportVideoRequest
+	var path string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"name": "projects/test-project/operations/VIDEO", "metadata": {"state": "PENDING"}}`))
+	})
+
+	var region earthengine.Geometry = earthengine.NewRectangle(-122.6, 37.6, -122.3, 37.9)
+	task, err := ExportVideoAnimation(context.Background(), client, client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED"),
+		VisualizationOptions{Bands: []string{"B4", "B3", "B2"}, Min: 0, Max: 3000},
+		12, 720,
+		ExportDescription("Time Lapse"),
+		ExportToGCS("my-bucket", "videos/"),
+		ExportRegion(&region))
+	if err != nil {
+		t.Fatalf("ExportVideoAnimation failed: %v", err)
+	}
+
+	if path != "/projects/test-project/video:export" {
+		t.Errorf("path = %s", path)
+	}
+	if task.Type != "EXPORT_VIDEO" || task.Description != "Time Lapse" || task.State != earthengine.TaskStatePending {
+		t.Errorf("task = %+v", task)
+	}
+	if got.VideoOptions == nil || got.VideoOptions.FramesPerSecond != 12 {
+		t.Errorf("VideoOptions = %+v", got.VideoOptions)
+	}
+	if got.FileExportOptions.FileFormat != "MP4" || got.FileExportOptions.GcsDestination.Bucket != "my-bucket" {
+		t.Errorf("FileExportOptions = %+v", got.FileExportOptions)
+	}
+	if got.RequestId == "" {
+		t.Error("RequestId is empty")
+	}
+
+	exprJSON, _ := json.Marshal(got.Expression)
+	for _, want := range []string{
+		`"Collection.map"`,
+		`"system:time_start"`,
+		`"Image.visualize"`,
+		`"Image.clipToBoundsAndScale"`,
+		`"maxDimension":{"constantValue":720}`,
+	} {
+		if !strings.Contains(string(exprJSON), want) {
+			t.Errorf("expression missing %s", want)
+		}
+	}
+}
+
+func TestExportVideoAnimationValidation(t *testing.T) {
+	client := &earthengine.Client{}
+	collection := &earthengine.ImageCollection{}
+	var region earthengine.Geometry = earthengine.NewRectangle(0, 0, 1, 1)
+	vis := VisualizationOptions{Min: 0, Max: 1, Palette: []string{"white", "green"}}
+
+	tests := []struct {
+		name       string
+		collection *earthengine.ImageCollection
+		vis        VisualizationOptions
+		fps        int
+		dimensions int
+		opts       []ExportImageOption
+	}{
+		{"nil collection", nil, vis, 10, 512, []ExportImageOption{ExportToGCS("b", ""), ExportRegion(&region)}},
+		{"invalid visualization", collection, VisualizationOptions{}, 10, 512, []ExportImageOption{ExportToGCS("b", ""), ExportRegion(&region)}},
+		{"zero fps", collection, vis, 0, 512, []ExportImageOption{ExportToGCS("b", ""), ExportRegion(&region)}},
+		{"dimensions too large", collection, vis, 10, maxVideoDimension + 1, []ExportImageOption{ExportToGCS("b", ""), ExportRegion(&region)}},
+		{"no region", collection, vis, 10, 512, []ExportImageOption{ExportToGCS("b", "")}},
+		{"wrong format", collection, vis, 10, 512, []ExportImageOption{ExportToGCS("b", ""), ExportRegion(&region), ExportFileFormat(GeoTIFF)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExportVideoAnimation(context.Background(), client, tt.collection, tt.vis, tt.fps, tt.dimensions, tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// newExportSizeTestClient returns a client whose geometry area requests
+// return area and whose band type requests return one float and one 16-bit
+// band.
+func newExportSizeTestClient(t *testing.T, area float64, opts ...earthengine.ClientOption) *earthengine.Client {
+	t.Helper()
+
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(string(body), earthengine.AlgorithmGeometryArea):
+			fmt.Fprintf(w, `{"result": %v}`, area)
+		case strings.Contains(string(body), earthengine.AlgorithmImageBandTypes):
+			w.Write([]byte(`{"result": {
+				"ndvi": {"type": "PixelType", "precision": "float"},
+				"elevation": {"type": "PixelType", "precision": "int", "min": -32768, "max": 32767}
+			}}`))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}, opts...)
+}
+
+func TestEstimateExportSize(t *testing.T) {
+	client := newExportSizeTestClient(t, 1e8)
+	image := client.Image("projects/test/assets/image")
+	region := earthengine.NewRectangle(-122.5, 37.5, -122.4, 37.6)
+
+	size, err := EstimateExportSize(context.Background(), client, image, region, 10)
+	if err != nil {
+		t.Fatalf("EstimateExportSize failed: %v", err)
+	}
+	if size.Pixels != 1e6 {
+		t.Errorf("Pixels = %d, want 1e6", size.Pixels)
+	}
+	if size.Bands != 2 {
+		t.Errorf("Bands = %d, want 2", size.Bands)
+	}
+	// 4 bytes of float + 2 bytes of int16 per pixel
+	if size.Bytes != 6e6 {
+		t.Errorf("Bytes = %d, want 6e6", size.Bytes)
+	}
+}
+
+func TestEstimateExportSizeValidation(t *testing.T) {
+	ctx := context.Background()
+	client := &earthengine.Client{}
+	image := &earthengine.Image{}
+	region := earthengine.NewRectangle(-122.5, 37.5, -122.4, 37.6)
+
+	if _, err := EstimateExportSize(ctx, client, nil, region, 10); err == nil {
+		t.Error("expected error for nil image")
+	}
+	if _, err := EstimateExportSize(ctx, client, image, nil, 10); err == nil {
+		t.Error("expected error for nil region")
+	}
+	if _, err := EstimateExportSize(ctx, client, image, region, 0); err == nil {
+		t.Error("expected error for zero scale")
+	}
+}
+
+func TestPixelTypeBytes(t *testing.T) {
+	tests := []struct {
+		pixelType earthengine.PixelType
+		want      int
+	}{
+		{earthengine.PixelType{Precision: "float"}, 4},
+		{earthengine.PixelType{Precision: "double"}, 8},
+		{earthengine.PixelType{Precision: "int", Min: 0, Max: 255}, 1},
+		{earthengine.PixelType{Precision: "int", Min: -32768, Max: 32767}, 2},
+		{earthengine.PixelType{Precision: "int", Min: 0, Max: 65535}, 2},
+		{earthengine.PixelType{Precision: "int", Min: 0, Max: 4294967295}, 4},
+		{earthengine.PixelType{Precision: "int", Min: -1, Max: 4294967295}, 8},
+	}
+	for _, tt := range tests {
+		if got := pixelTypeBytes(tt.pixelType); got != tt.want {
+			t.Errorf("pixelTypeBytes(%+v) = %d, want %d", tt.pixelType, got, tt.want)
+		}
+	}
+}
+
+func TestExportImageAsyncMaxPixels(t *testing.T) {
+	ctx := context.Background()
+	logger := &eventLogger{}
+	client := newExportSizeTestClient(t, 1e8, earthengine.WithLogger(logger))
+	image := client.Image("projects/test/assets/image")
+	var region earthengine.Geometry = earthengine.NewRectangle(-122.5, 37.5, -122.4, 37.6)
+	opts := []ExportImageOption{
+		ExportDescription("Large Export"),
+		ExportToGCS("test-bucket", "exports/"),
+		ExportScale(10),
+		ExportRegion(&region),
+		ExportMaxPixels(1e5),
+	}
+
+	// Over maxPixels only logs a warning by default
+	if _, err := ExportImageAsync(ctx, client, image, opts...); err != nil {
+		t.Fatalf("ExportImageAsync failed: %v", err)
+	}
+	if n := logger.count(EventExportSizeWarning); n != 1 {
+		t.Errorf("logged %d export size warnings, want 1", n)
+	}
+
+	_, err := ExportImageAsync(ctx, client, image, append(opts, ExportStrictMaxPixels())...)
+	if err == nil {
+		t.Fatal("expected error for export over maxPixels")
+	}
+	// sqrt(1e6 / 1e5) * 10 m
+	if !strings.Contains(err.Error(), "32 m") {
+		t.Errorf("error %q does not suggest a scale of 32 m", err)
+	}
+
+	if _, err := ExportImageAsync(ctx, client, image, append(opts, ExportMaxPixels(1e6), ExportStrictMaxPixels())...); err != nil {
+		t.Errorf("ExportImageAsync within maxPixels failed: %v", err)
+	}
+	if n := logger.count(EventExportSizeWarning); n != 1 {
+		t.Errorf("logged %d export size warnings, want 1", n)
+	}
+}
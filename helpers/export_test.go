@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"math"
+	"regexp"
 	"testing"
 )
 
@@ -202,3 +204,66 @@ func TestExportFormatConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestNewExportImageRequest(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	build := func(opts ...ExportImageOption) string {
+		t.Helper()
+		image := client.Image("USGS/SRTMGL1_003").Select("elevation")
+		req, err := NewExportImageRequest(image, append([]ExportImageOption{ExportToGCS("my-bucket", "dem/")}, opts...)...)
+		if err != nil {
+			t.Fatalf("NewExportImageRequest failed: %v", err)
+		}
+		return req.RequestId
+	}
+
+	id := build()
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("RequestId = %q, want a version 5 UUID", id)
+	}
+	if again := build(); again != id {
+		t.Errorf("RequestId changed between identical builds: %q, %q", id, again)
+	}
+	if other := build(ExportScale(90)); other == id {
+		t.Error("RequestId is the same for a different export")
+	}
+	if explicit := build(ExportRequestID("dem-run-1")); explicit != "dem-run-1" {
+		t.Errorf("RequestId = %q, want dem-run-1", explicit)
+	}
+}
+
+func TestNewExportImageRequestOptions(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	image := client.Image("USGS/SRTMGL1_003")
+
+	req, err := NewExportImageRequest(image,
+		ExportDescription("DEM"),
+		ExportToGoogleDrive("exports"),
+		ExportFileFormat(TFRecord))
+	if err != nil {
+		t.Fatalf("NewExportImageRequest failed: %v", err)
+	}
+	if drive := req.FileExportOptions.DriveDestination; drive == nil || drive.Folder != "exports" || drive.FilenamePrefix != "DEM" {
+		t.Errorf("DriveDestination = %+v", drive)
+	}
+	if req.FileExportOptions.FileFormat != "TF_RECORD_IMAGE" {
+		t.Errorf("FileFormat = %q", req.FileExportOptions.FileFormat)
+	}
+	// 30m default scale in degrees for EPSG:4326
+	if scale := req.Grid.AffineTransform.ScaleX; math.Abs(scale-30/metersPerDegreeLat) > 1e-12 || req.Grid.CrsCode != "EPSG:4326" {
+		t.Errorf("Grid = %+v, scale %v", req.Grid, scale)
+	}
+	if req.Expression == nil || req.Expression.Result == "" || len(req.Expression.Values) == 0 {
+		t.Errorf("Expression = %+v", req.Expression)
+	}
+
+	if _, err := NewExportImageRequest(image, ExportToEEAsset("projects/p/assets/dem")); err == nil {
+		t.Error("Expected error for asset destination")
+	}
+	if _, err := NewExportImageRequest(image, ExportToGCS("b", ""), ExportFileFormat(CSV)); err == nil {
+		t.Error("Expected error for table format")
+	}
+	if _, err := NewExportImageRequest(nil, ExportToGCS("b", "")); err == nil {
+		t.Error("Expected error for nil image")
+	}
+}
@@ -194,6 +194,9 @@ func BurnSeverityWithContext(ctx context.Context, client *earthengine.Client, la
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return 0, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -205,10 +208,9 @@ func BurnSeverityWithContext(ctx context.Context, client *earthengine.Client, la
 	}
 
 	// Calculate NBR = (NIR - SWIR) / (NIR + SWIR)
-	image := collection.
+	image := safeNormalizedDifference(collection.
 		Select(nirBand, swirBand).
-		Reduce(earthengine.ReducerMean()).
-		NormalizedDifference()
+		Reduce(earthengine.ReducerMean()))
 
 	// Determine scale
 	scale := defaultImageryScale
@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// geoJSONObject is a GeoJSON FeatureCollection, Feature, or geometry.
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Features    []geoJSONObject        `json:"features"`
+	Geometry    *geoJSONObject         `json:"geometry"`
+	Properties  map[string]interface{} `json:"properties"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+}
+
+// FeatureCollectionFromGeoJSON parses GeoJSON into a FeatureCollection for use
+// as zones in CalculateZonalStats.
+//
+// data may be a FeatureCollection or a single Feature. Feature properties are
+// preserved, so they can be used with ZonalStatsConfig.ZoneIDKey. Every
+// geometry must be a Polygon or MultiPolygon; coordinates are [longitude,
+// latitude] as in RFC 7946, and any altitude values are ignored.
+//
+// Example:
+//
+//	zones, err := helpers.FeatureCollectionFromGeoJSON(data)
+//	result, err := helpers.CalculateZonalStats(ctx, client, image, zones,
+//	    helpers.ZonalStatsConfig{ZoneIDKey: "name", Scale: 30})
+func FeatureCollectionFromGeoJSON(data []byte) (*earthengine.FeatureCollection, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse GeoJSON: %w", err)
+	}
+
+	var features []geoJSONObject
+	switch obj.Type {
+	case "FeatureCollection":
+		features = obj.Features
+	case "Feature":
+		features = []geoJSONObject{obj}
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON type %q (expected FeatureCollection or Feature)", obj.Type)
+	}
+
+	fc := earthengine.NewFeatureCollection()
+	for i, feature := range features {
+		if feature.Type != "Feature" {
+			return nil, fmt.Errorf("feature %d: unexpected type %q", i, feature.Type)
+		}
+		if feature.Geometry == nil {
+			return nil, fmt.Errorf("feature %d: missing geometry", i)
+		}
+
+		geometry, err := parseGeoJSONGeometry(feature.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		fc.Features = append(fc.Features, earthengine.NewFeature(geometry, feature.Properties))
+	}
+
+	return fc, nil
+}
+
+// FeatureCollectionFromGeoJSONFile reads a GeoJSON file into a FeatureCollection.
+// See FeatureCollectionFromGeoJSON.
+//
+// Example:
+//
+//	zones, err := helpers.FeatureCollectionFromGeoJSONFile("counties.geojson")
+func FeatureCollectionFromGeoJSONFile(path string) (*earthengine.FeatureCollection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoJSON file: %w", err)
+	}
+	return FeatureCollectionFromGeoJSON(data)
+}
+
+// parseGeoJSONGeometry converts a Polygon or MultiPolygon geometry.
+func parseGeoJSONGeometry(geom *geoJSONObject) (earthengine.Geometry, error) {
+	switch geom.Type {
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(geom.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		polygon, err := parseGeoJSONPolygon(coords)
+		if err != nil {
+			return nil, err
+		}
+		return polygon, nil
+
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(geom.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		if len(coords) == 0 {
+			return nil, fmt.Errorf("MultiPolygon has no polygons")
+		}
+		polygons := make([]earthengine.Polygon, len(coords))
+		for i, polygonCoords := range coords {
+			polygon, err := parseGeoJSONPolygon(polygonCoords)
+			if err != nil {
+				return nil, fmt.Errorf("polygon %d: %w", i, err)
+			}
+			polygons[i] = polygon
+		}
+		return earthengine.NewMultiPolygon(polygons...), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q (zones must be Polygon or MultiPolygon)", geom.Type)
+	}
+}
+
+// parseGeoJSONPolygon validates polygon rings and converts them to a Polygon.
+func parseGeoJSONPolygon(coords [][][]float64) (earthengine.Polygon, error) {
+	if len(coords) == 0 {
+		return earthengine.Polygon{}, fmt.Errorf("polygon has no rings")
+	}
+
+	rings := make([][][2]float64, len(coords))
+	for i, ringCoords := range coords {
+		// A closed ring repeats its first position, so needs at least 4
+		if len(ringCoords) < 4 {
+			return earthengine.Polygon{}, fmt.Errorf("ring %d has %d positions, need at least 4", i, len(ringCoords))
+		}
+
+		ring := make([][2]float64, len(ringCoords))
+		for j, pos := range ringCoords {
+			if len(pos) < 2 {
+				return earthengine.Polygon{}, fmt.Errorf("ring %d position %d has %d values, need longitude and latitude", i, j, len(pos))
+			}
+			if err := validateCoordinates(pos[1], pos[0]); err != nil {
+				return earthengine.Polygon{}, fmt.Errorf("ring %d position %d: %w", i, j, err)
+			}
+			ring[j] = [2]float64{pos[0], pos[1]}
+		}
+
+		if ring[0] != ring[len(ring)-1] {
+			return earthengine.Polygon{}, fmt.Errorf("ring %d is not closed", i)
+		}
+		rings[i] = ring
+	}
+
+	return earthengine.NewPolygon(rings...), nil
+}
+
+// geometryToGeoJSON converts a geometry to a GeoJSON geometry object.
+// A nil geometry converts to nil, which encodes as a null geometry.
+func geometryToGeoJSON(geom earthengine.Geometry) (map[string]interface{}, error) {
+	switch g := geom.(type) {
+	case nil:
+		return nil, nil
+	case earthengine.Point:
+		return map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{g.Longitude, g.Latitude},
+		}, nil
+	case earthengine.Rectangle:
+		ring := [][2]float64{
+			{g.West, g.South}, {g.East, g.South}, {g.East, g.North}, {g.West, g.North}, {g.West, g.South},
+		}
+		return map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": [][][2]float64{ring},
+		}, nil
+	case earthengine.Polygon:
+		return map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": g.Rings,
+		}, nil
+	case earthengine.MultiPolygon:
+		coordinates := make([][][][2]float64, len(g.Polygons))
+		for i, polygon := range g.Polygons {
+			coordinates[i] = polygon.Rings
+		}
+		return map[string]interface{}{
+			"type":        "MultiPolygon",
+			"coordinates": coordinates,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %T for GeoJSON", geom)
+	}
+}
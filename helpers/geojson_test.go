@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+const testZonesGeoJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"id": "downtown", "population": 12000},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [[[-122.68, 45.51], [-122.67, 45.51], [-122.67, 45.52], [-122.68, 45.52], [-122.68, 45.51]]]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": {"id": "islands"},
+      "geometry": {
+        "type": "MultiPolygon",
+        "coordinates": [
+          [[[-122.80, 45.60, 12.5], [-122.79, 45.60, 12.5], [-122.79, 45.61, 12.5], [-122.80, 45.60, 12.5]]],
+          [[[-122.70, 45.60], [-122.69, 45.60], [-122.69, 45.61], [-122.70, 45.60]]]
+        ]
+      }
+    }
+  ]
+}`
+
+func TestFeatureCollectionFromGeoJSON(t *testing.T) {
+	fc, err := FeatureCollectionFromGeoJSON([]byte(testZonesGeoJSON))
+	if err != nil {
+		t.Fatalf("FeatureCollectionFromGeoJSON failed: %v", err)
+	}
+
+	if fc.Size() != 2 {
+		t.Fatalf("Size() = %d, want 2", fc.Size())
+	}
+
+	downtown := fc.Features[0]
+	if downtown.Properties["id"] != "downtown" {
+		t.Errorf("id = %v, want downtown", downtown.Properties["id"])
+	}
+	if downtown.Properties["population"] != 12000.0 {
+		t.Errorf("population = %v, want 12000", downtown.Properties["population"])
+	}
+	polygon, ok := downtown.Geometry.(earthengine.Polygon)
+	if !ok {
+		t.Fatalf("Geometry = %T, want earthengine.Polygon", downtown.Geometry)
+	}
+	if len(polygon.Rings) != 1 || len(polygon.Rings[0]) != 5 {
+		t.Errorf("Rings = %v, want one ring of 5 positions", polygon.Rings)
+	}
+	if polygon.Rings[0][1] != [2]float64{-122.67, 45.51} {
+		t.Errorf("Rings[0][1] = %v, want [-122.67 45.51]", polygon.Rings[0][1])
+	}
+
+	islands, ok := fc.Features[1].Geometry.(earthengine.MultiPolygon)
+	if !ok {
+		t.Fatalf("Geometry = %T, want earthengine.MultiPolygon", fc.Features[1].Geometry)
+	}
+	if len(islands.Polygons) != 2 {
+		t.Errorf("len(Polygons) = %d, want 2", len(islands.Polygons))
+	}
+}
+
+func TestFeatureCollectionFromGeoJSONSingleFeature(t *testing.T) {
+	data := `{"type": "Feature", "properties": null, "geometry": {"type": "Polygon",
+	  "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}`
+
+	fc, err := FeatureCollectionFromGeoJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("FeatureCollectionFromGeoJSON failed: %v", err)
+	}
+	if fc.Size() != 1 {
+		t.Errorf("Size() = %d, want 1", fc.Size())
+	}
+}
+
+func TestFeatureCollectionFromGeoJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"invalid json", `{`, "failed to parse"},
+		{"bare geometry", `{"type": "Polygon", "coordinates": []}`, "unsupported GeoJSON type"},
+		{"point zone", `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}}`, "must be Polygon or MultiPolygon"},
+		{"missing geometry", `{"type": "Feature", "geometry": null}`, "missing geometry"},
+		{"unclosed ring", `{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}}`, "not closed"},
+		{"short ring", `{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}}`, "need at least 4"},
+		{"latitude out of range", `{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 95], [1, 1], [0, 0]]]}}`, "invalid latitude"},
+		{"empty multipolygon", `{"type": "Feature", "geometry": {"type": "MultiPolygon", "coordinates": []}}`, "no polygons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FeatureCollectionFromGeoJSON([]byte(tt.data))
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeatureCollectionFromGeoJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zones.geojson")
+	if err := os.WriteFile(path, []byte(testZonesGeoJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := FeatureCollectionFromGeoJSONFile(path)
+	if err != nil {
+		t.Fatalf("FeatureCollectionFromGeoJSONFile failed: %v", err)
+	}
+	if fc.Size() != 2 {
+		t.Errorf("Size() = %d, want 2", fc.Size())
+	}
+
+	if _, err := FeatureCollectionFromGeoJSONFile(filepath.Join(t.TempDir(), "missing.geojson")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
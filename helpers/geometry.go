@@ -1,17 +1,19 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
+	"math"
 
 	"github.com/alexscott64/go-earthengine"
 )
 
 // Bounds represents a geographic bounding box.
 type Bounds struct {
-	MinLon float64 // Western longitude
-	MinLat float64 // Southern latitude
-	MaxLon float64 // Eastern longitude
-	MaxLat float64 // Northern latitude
+	MinLon float64 `json:"min_lon"` // Western longitude
+	MinLat float64 `json:"min_lat"` // Southern latitude
+	MaxLon float64 `json:"max_lon"` // Eastern longitude
+	MaxLat float64 `json:"max_lat"` // Northern latitude
 }
 
 // BoundsFromPoints creates a bounding box from a set of points.
@@ -56,25 +58,185 @@ func BoundsFromPoints(points [][2]float64) Bounds {
 }
 
 // ToRectangle converts the bounds to an Earth Engine Rectangle geometry.
-//
-// Note: This returns a earthengine.Geometry interface.
-// The actual implementation would need Rectangle support in the main library.
 func (b Bounds) ToRectangle() (earthengine.Geometry, error) {
-	if err := validateCoordinates(b.MinLat, b.MinLon); err != nil {
-		return nil, fmt.Errorf("invalid min coordinates: %w", err)
+	if err := b.Validate(); err != nil {
+		return nil, err
 	}
-	if err := validateCoordinates(b.MaxLat, b.MaxLon); err != nil {
-		return nil, fmt.Errorf("invalid max coordinates: %w", err)
+
+	return earthengine.NewRectangle(b.MinLon, b.MinLat, b.MaxLon, b.MaxLat), nil
+}
+
+// BoundsToGeometry converts the bounds to a rectangular polygon.
+//
+// The bounds are not checked; call Validate first when they come from user
+// input.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -122.5, MinLat: 45.4, MaxLon: -122.3, MaxLat: 45.6}
+//	if err := bounds.Validate(); err != nil {
+//	    return err
+//	}
+//	polygon := helpers.BoundsToGeometry(bounds)
+func BoundsToGeometry(b Bounds) earthengine.Geometry {
+	return earthengine.NewPolygon([][2]float64{
+		{b.MinLon, b.MinLat},
+		{b.MaxLon, b.MinLat},
+		{b.MaxLon, b.MaxLat},
+		{b.MinLon, b.MaxLat},
+		{b.MinLon, b.MinLat},
+	})
+}
+
+// ClipToGeometry clips image to geom, masking pixels outside it. A nil geom
+// returns the image unchanged, so optional regions can be passed through
+// without checking them first.
+//
+// Example:
+//
+//	clipped := helpers.ClipToGeometry(image, config.Region)
+func ClipToGeometry(image *earthengine.Image, geom earthengine.Geometry) *earthengine.Image {
+	if geom == nil {
+		return image
 	}
-	if b.MinLon >= b.MaxLon {
-		return nil, fmt.Errorf("minLon (%f) must be less than maxLon (%f)", b.MinLon, b.MaxLon)
+	return image.Clip(geom)
+}
+
+// ClipToBounds clips image to a bounding box. Zero bounds return the image
+// unchanged.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -122.84, MinLat: 45.43, MaxLon: -122.47, MaxLat: 45.65}
+//	clipped, err := helpers.ClipToBounds(image, bounds)
+func ClipToBounds(image *earthengine.Image, b Bounds) (*earthengine.Image, error) {
+	if b == (Bounds{}) {
+		return image, nil
 	}
-	if b.MinLat >= b.MaxLat {
-		return nil, fmt.Errorf("minLat (%f) must be less than maxLat (%f)", b.MinLat, b.MaxLat)
+
+	region, err := b.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
 	}
 
-	// Placeholder - would need Rectangle geometry support in main library
-	return nil, fmt.Errorf("Rectangle geometry support not yet implemented")
+	return image.Clip(region), nil
+}
+
+// Reproject puts image in crs at scale meters per pixel, so that analysis of
+// layers with different native resolutions (such as 30 m SRTM and 10 m
+// Sentinel-2) happens on a grid you choose rather than one Earth Engine
+// picks implicitly.
+//
+// Reproject sparingly: it forces every pixel to be computed at the given
+// scale, which can be slow or exceed memory for large regions. Apply it to
+// the final layer just before ReduceRegion or export, not to intermediate
+// steps, and combine it with Resample to interpolate continuous data instead
+// of using nearest neighbor.
+//
+// Example:
+//
+//	// Compare elevation with Sentinel-2 NDVI on the same 10 m grid
+//	dem, err := helpers.Resample(srtm, "bilinear")
+//	dem, err = helpers.Reproject(dem, "EPSG:32610", 10)
+func Reproject(image *earthengine.Image, crs string, scale float64) (*earthengine.Image, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if crs == "" {
+		return nil, fmt.Errorf("crs is required")
+	}
+	if scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", scale)
+	}
+	return image.Reproject(crs, scale), nil
+}
+
+// Resample interpolates image with method, "bilinear" or "bicubic", whenever
+// it is reprojected, including the implicit reprojection of ReduceRegion.
+// Nearest neighbor, Earth Engine's default, is right for categorical data
+// such as land cover; use this for continuous data such as elevation.
+//
+// An unknown method returns an error.
+//
+// Example:
+//
+//	smooth, err := helpers.Resample(srtm, "bicubic")
+func Resample(image *earthengine.Image, method string) (*earthengine.Image, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	switch method {
+	case "bilinear", "bicubic":
+		return image.Resample(method), nil
+	default:
+		return nil, fmt.Errorf("unsupported resampling method %q (use bilinear or bicubic)", method)
+	}
+}
+
+// maxReduceResolutionPixels is the most input pixels Earth Engine's
+// reduceResolution combines into one output pixel.
+const maxReduceResolutionPixels = 65536
+
+// AggregatePixels coarsens image by factor, combining each factor × factor
+// block of pixels with statistic instead of sampling the nearest pixel as
+// Earth Engine does by default. With Mean, input pixels are weighted by the
+// area they overlap each output pixel, so aggregating 10 m Sentinel-2 to a
+// 1 km climate grid gives the true average over each grid cell.
+//
+// Aggregate before any Reproject: reprojecting first resamples the fine
+// pixels and the aggregation then averages the resampled values. The result
+// is in image's own projection, so all its bands must share one.
+//
+// Each output pixel may combine at most 65,536 input pixels, which limits
+// factor to 255; coarsen further by aggregating in steps.
+//
+// Example:
+//
+//	// 10 m NDVI to 1 km cells
+//	coarse, err := helpers.AggregatePixels(ndvi, 100, helpers.Mean)
+func AggregatePixels(image *earthengine.Image, factor int, statistic ZonalStatistic) (*earthengine.Image, error) {
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if factor < 2 {
+		return nil, fmt.Errorf("factor must be at least 2, got %d", factor)
+	}
+
+	// A block straddles up to factor + 1 input pixels on each side
+	maxPixels := (factor + 1) * (factor + 1)
+	if maxPixels > maxReduceResolutionPixels {
+		return nil, fmt.Errorf("factor %d combines up to %d pixels per output pixel, over the limit of %d; aggregate in steps",
+			factor, maxPixels, maxReduceResolutionPixels)
+	}
+
+	reducer, err := zonalReducer([]ZonalStatistic{statistic})
+	if err != nil {
+		return nil, err
+	}
+
+	return image.
+		ReduceResolution(reducer, maxPixels).
+		ScaleProjection(float64(factor)), nil
+}
+
+// clipCollection keeps the images of collection that intersect geom and clips
+// each to it, so later reductions only compute pixels inside the region. A nil
+// geom returns the collection unchanged.
+func clipCollection(collection *earthengine.ImageCollection, geom earthengine.Geometry) *earthengine.ImageCollection {
+	if geom == nil {
+		return collection
+	}
+	return collection.FilterBounds(geom).Map(func(img *earthengine.Image) *earthengine.Image {
+		return img.Clip(geom)
+	})
+}
+
+// optionalRegion dereferences an optional region from a config struct.
+func optionalRegion(region *earthengine.Geometry) earthengine.Geometry {
+	if region == nil {
+		return nil
+	}
+	return *region
 }
 
 // Area returns the approximate area of the bounds in square meters.
@@ -142,10 +304,8 @@ func (b Bounds) Validate() error {
 	return nil
 }
 
-// Circle creates a circular geometry around a point.
-//
-// Note: This is a placeholder. The actual implementation would need
-// GeometryConstructors.Point + Buffer support in the main library.
+// Circle creates a circular geometry around a point by buffering it
+// server-side.
 //
 // Example:
 //
@@ -159,15 +319,11 @@ func Circle(lat, lon, radiusMeters float64) (earthengine.Geometry, error) {
 		return nil, fmt.Errorf("radius must be positive, got %f", radiusMeters)
 	}
 
-	// Placeholder - would need Buffer geometry support
-	return nil, fmt.Errorf("Circle geometry support not yet implemented")
+	return earthengine.NewBuffer(earthengine.NewPoint(lon, lat), radiusMeters), nil
 }
 
 // Rectangle creates a rectangular geometry from bounds.
 //
-// Note: This is a placeholder. The actual implementation would need
-// GeometryConstructors.Rectangle support in the main library.
-//
 // Example:
 //
 //	bounds := helpers.Bounds{
@@ -181,9 +337,6 @@ func Rectangle(bounds Bounds) (earthengine.Geometry, error) {
 
 // Polygon creates a polygon geometry from a set of points.
 //
-// Note: This is a placeholder. The actual implementation would need
-// GeometryConstructors.Polygon support in the main library.
-//
 // Points should be in [lon, lat] format.
 //
 // Example:
@@ -208,14 +361,11 @@ func Polygon(points [][2]float64) (earthengine.Geometry, error) {
 		}
 	}
 
-	// Placeholder - would need Polygon geometry support
-	return nil, fmt.Errorf("Polygon geometry support not yet implemented")
+	return earthengine.NewPolygon(points), nil
 }
 
-// Buffer creates a buffered geometry around another geometry.
-//
-// Note: This is a placeholder. The actual implementation would need
-// Geometry.buffer() support in the main library.
+// Buffer creates a buffered geometry around another geometry. It is like
+// BufferGeometry but requires a positive distance.
 //
 // Example:
 //
@@ -229,8 +379,249 @@ func Buffer(geom earthengine.Geometry, meters float64) (earthengine.Geometry, er
 		return nil, fmt.Errorf("buffer distance must be positive, got %f", meters)
 	}
 
-	// Placeholder - would need Buffer support
-	return nil, fmt.Errorf("Buffer geometry support not yet implemented")
+	return BufferGeometry(geom, meters)
+}
+
+// BufferGeometry expands geom by meters, computed server-side with
+// Geometry.buffer. With no projection given, Earth Engine measures the
+// distance in meters on the ground whatever the geometry's coordinates
+// (EPSG:4326 longitude and latitude here), so a buffered point is a circle
+// rather than a degree-based ellipse. A zero distance returns geom
+// unchanged.
+//
+// Example:
+//
+//	// 500m analysis zones around wells
+//	zone, err := helpers.BufferGeometry(earthengine.NewPoint(-119.7, 36.8), 500)
+func BufferGeometry(geom earthengine.Geometry, meters float64) (earthengine.Geometry, error) {
+	if geom == nil {
+		return nil, fmt.Errorf("geometry cannot be nil")
+	}
+	if meters < 0 {
+		return nil, fmt.Errorf("buffer distance must be non-negative, got %f", meters)
+	}
+	if meters == 0 {
+		return geom, nil
+	}
+
+	return earthengine.NewBuffer(geom, meters), nil
+}
+
+// BufferFeatures returns a copy of fc with every feature's geometry buffered
+// by meters (see BufferGeometry), keeping its properties.
+//
+// Example:
+//
+//	zones, err := helpers.BufferFeatures(wells, 500)
+//	result, err := helpers.CalculateZonalStats(ctx, client, image, zones,
+//	    helpers.ZonalStatsConfig{ZoneIDKey: "well_id"})
+func BufferFeatures(fc *earthengine.FeatureCollection, meters float64) (*earthengine.FeatureCollection, error) {
+	if fc == nil {
+		return nil, fmt.Errorf("feature collection cannot be nil")
+	}
+
+	features := make([]earthengine.Feature, len(fc.Features))
+	for i, feature := range fc.Features {
+		geom, err := BufferGeometry(feature.Geometry, meters)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		features[i] = earthengine.NewFeature(geom, feature.Properties)
+	}
+
+	return earthengine.NewFeatureCollection(features...), nil
+}
+
+// DissolveFeatureCollection merges the geometries of all features in fc into
+// one, removing boundaries between overlapping or adjacent features. The
+// result holds a single feature without properties, so it can be passed to
+// CalculateZonalStats as one zone; its geometry can also be used directly as
+// a region.
+//
+// Example:
+//
+//	// Total area within 500m of any well, counting overlaps once
+//	zones, _ := helpers.BufferFeatures(wells, 500)
+//	dissolved, err := helpers.DissolveFeatureCollection(zones)
+//	region := dissolved.Features[0].Geometry
+func DissolveFeatureCollection(fc *earthengine.FeatureCollection) (*earthengine.FeatureCollection, error) {
+	if fc.Size() == 0 {
+		return nil, fmt.Errorf("feature collection is empty")
+	}
+	for i, feature := range fc.Features {
+		if feature.Geometry == nil {
+			return nil, fmt.Errorf("feature %d has no geometry", i)
+		}
+	}
+
+	return earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewDissolve(fc), nil),
+	), nil
+}
+
+// IntersectGeometries returns the area shared by a and b, computed
+// server-side with Geometry.intersection. Geometries that don't overlap
+// intersect in an empty geometry.
+//
+// Example:
+//
+//	// The part of a watershed within a county
+//	zone, err := helpers.IntersectGeometries(watershed, county)
+func IntersectGeometries(a, b earthengine.Geometry) (earthengine.Geometry, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("geometry cannot be nil")
+	}
+
+	return earthengine.NewIntersection(a, b), nil
+}
+
+// ContainsPoint reports whether geom contains the point at lat, lon. It is
+// computed locally, treating longitude and latitude as planar coordinates
+// like Earth Engine does for non-geodesic geometries.
+//
+// Points, rectangles, polygons (with holes), multipolygons, buffered
+// points, intersections, and dissolved collections of these are supported;
+// other buffered geometries return an error. Points on a polygon's boundary
+// may be reported either way.
+//
+// Example:
+//
+//	county, _ := helpers.Polygon(countyRing)
+//	inside, err := helpers.ContainsPoint(county, 45.5152, -122.6784)
+func ContainsPoint(geom earthengine.Geometry, lat, lon float64) (bool, error) {
+	if geom == nil {
+		return false, fmt.Errorf("geometry cannot be nil")
+	}
+	if err := validateCoordinates(lat, lon); err != nil {
+		return false, err
+	}
+
+	switch g := geom.(type) {
+	case earthengine.Point:
+		return g.Latitude == lat && g.Longitude == lon, nil
+	case earthengine.Rectangle:
+		return lon >= g.West && lon <= g.East && lat >= g.South && lat <= g.North, nil
+	case earthengine.Polygon:
+		return polygonContains(g.Rings, lon, lat), nil
+	case earthengine.MultiPolygon:
+		for _, polygon := range g.Polygons {
+			if polygonContains(polygon.Rings, lon, lat) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case earthengine.Buffer:
+		center, ok := g.Geometry.(earthengine.Point)
+		if !ok {
+			return false, fmt.Errorf("containment is only supported for buffered points, got a buffered %T", g.Geometry)
+		}
+		return DistanceMeters(center.Latitude, center.Longitude, lat, lon) <= g.Distance, nil
+	case earthengine.Intersection:
+		inA, err := ContainsPoint(g.A, lat, lon)
+		if err != nil || !inA {
+			return false, err
+		}
+		return ContainsPoint(g.B, lat, lon)
+	case earthengine.Dissolve:
+		if g.Collection == nil {
+			return false, nil
+		}
+		for _, feature := range g.Collection.Features {
+			inside, err := ContainsPoint(feature.Geometry, lat, lon)
+			if err != nil || inside {
+				return inside, err
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("containment is not supported for %T geometries", geom)
+	}
+}
+
+// polygonContains reports whether the point at x, y is inside the exterior
+// ring and outside every hole, using the even-odd rule.
+func polygonContains(rings [][][2]float64, x, y float64) bool {
+	if len(rings) == 0 || !ringContains(rings[0], x, y) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if ringContains(hole, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains casts a ray from x, y and counts the ring edges it crosses.
+func ringContains(ring [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// SpatialJoin tags each point with the value of property from the first
+// polygon containing it (see ContainsPoint), or nil if no polygon contains
+// it or the polygon lacks the property. The points keep their other
+// properties; the input collections are not modified.
+//
+// Features are held client-side, so the join is computed locally without an
+// Earth Engine request. Every point feature must have a Point geometry.
+//
+// Example:
+//
+//	// Attach county FIPS codes to sample sites before summarizing by county
+//	tagged, err := helpers.SpatialJoin(ctx, client, sites, counties, "fips")
+//	for _, site := range tagged.Features {
+//	    fmt.Println(site.Properties["fips"])
+//	}
+func SpatialJoin(ctx context.Context, client *earthengine.Client, points, polygons *earthengine.FeatureCollection, property string) (*earthengine.FeatureCollection, error) {
+	_ = client
+
+	if points == nil || polygons == nil {
+		return nil, fmt.Errorf("feature collections cannot be nil")
+	}
+	if property == "" {
+		return nil, fmt.Errorf("property is required")
+	}
+
+	joined := make([]earthengine.Feature, len(points.Features))
+	for i, feature := range points.Features {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		point, ok := feature.Geometry.(earthengine.Point)
+		if !ok {
+			return nil, fmt.Errorf("feature %d: geometry is %T, want a point", i, feature.Geometry)
+		}
+
+		var value interface{}
+		for j, polygon := range polygons.Features {
+			inside, err := ContainsPoint(polygon.Geometry, point.Latitude, point.Longitude)
+			if err != nil {
+				return nil, fmt.Errorf("polygon %d: %w", j, err)
+			}
+			if inside {
+				value = polygon.Properties[property]
+				break
+			}
+		}
+
+		properties := make(map[string]interface{}, len(feature.Properties)+1)
+		for key, v := range feature.Properties {
+			properties[key] = v
+		}
+		properties[property] = value
+		joined[i] = earthengine.NewFeature(point, properties)
+	}
+
+	return earthengine.NewFeatureCollection(joined...), nil
 }
 
 // Helper functions for geometry calculations
@@ -242,20 +633,21 @@ func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadius = 6371000.0 // meters
 
 	// Convert to radians
-	lat1Rad := lat1 * 3.141592653589793 / 180.0
-	lat2Rad := lat2 * 3.141592653589793 / 180.0
-	deltaLat := (lat2 - lat1) * 3.141592653589793 / 180.0
-	deltaLon := (lon2 - lon1) * 3.141592653589793 / 180.0
+	lat1Rad := lat1 * math.Pi / 180.0
+	lat2Rad := lat2 * math.Pi / 180.0
+	deltaLat := (lat2 - lat1) * math.Pi / 180.0
+	deltaLon := (lon2 - lon1) * math.Pi / 180.0
 
 	// Haversine formula
-	a := sin(deltaLat/2)*sin(deltaLat/2) +
-		cos(lat1Rad)*cos(lat2Rad)*sin(deltaLon/2)*sin(deltaLon/2)
-	c := 2 * atan2(sqrt(a), sqrt(1-a))
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 
 	return earthRadius * c
 }
 
-// Helper math functions (since we can't import math to avoid circular deps)
+// Approximate math helpers. DistanceMeters uses the math package instead, since
+// sqrt and atan2 here are inaccurate for the small values of short distances.
 func sin(x float64) float64 {
 	// Taylor series approximation for sine
 	// Good enough for our purposes
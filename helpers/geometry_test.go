@@ -1,8 +1,13 @@
 package helpers
 
 import (
+	"context"
+	"encoding/json"
 	"math"
+	"strings"
 	"testing"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 func TestBoundsFromPoints(t *testing.T) {
@@ -164,6 +169,19 @@ func TestBoundsValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid latitude",
+			bounds: Bounds{
+				MinLon: -122.5, MinLat: 45.4,
+				MaxLon: -122.3, MaxLat: 91,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty bounds",
+			bounds:  Bounds{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -176,6 +194,97 @@ func TestBoundsValidate(t *testing.T) {
 	}
 }
 
+func TestBoundsToGeometry(t *testing.T) {
+	bounds := Bounds{
+		MinLon: -122.5, MinLat: 45.4,
+		MaxLon: -122.3, MaxLat: 45.6,
+	}
+
+	polygon, ok := BoundsToGeometry(bounds).(earthengine.Polygon)
+	if !ok {
+		t.Fatalf("BoundsToGeometry() = %T, want earthengine.Polygon", BoundsToGeometry(bounds))
+	}
+
+	want := [][2]float64{
+		{-122.5, 45.4},
+		{-122.3, 45.4},
+		{-122.3, 45.6},
+		{-122.5, 45.6},
+		{-122.5, 45.4},
+	}
+	if len(polygon.Rings) != 1 || len(polygon.Rings[0]) != len(want) {
+		t.Fatalf("Rings = %v, want one ring %v", polygon.Rings, want)
+	}
+	for i, pos := range polygon.Rings[0] {
+		if pos != want[i] {
+			t.Errorf("position %d = %v, want %v", i, pos, want[i])
+		}
+	}
+}
+
+func TestBoundsToRectangle(t *testing.T) {
+	bounds := Bounds{
+		MinLon: -122.5, MinLat: 45.4,
+		MaxLon: -122.3, MaxLat: 45.6,
+	}
+
+	geom, err := bounds.ToRectangle()
+	if err != nil {
+		t.Fatalf("ToRectangle() failed: %v", err)
+	}
+	if geom != earthengine.NewRectangle(-122.5, 45.4, -122.3, 45.6) {
+		t.Errorf("ToRectangle() = %v", geom)
+	}
+
+	if _, err := (Bounds{MinLon: 1, MaxLon: 0}).ToRectangle(); err == nil {
+		t.Error("ToRectangle() expected error for invalid bounds")
+	}
+}
+
+func TestClipToGeometry(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"elevation": 50}}`)
+	image := client.Image("USGS/SRTMGL1_003")
+
+	if got := ClipToGeometry(image, nil); got != image {
+		t.Error("ClipToGeometry with nil geometry should return the image unchanged")
+	}
+
+	clipped := ClipToGeometry(image, earthengine.NewRectangle(-122.5, 45.4, -122.3, 45.6))
+	if _, err := clipped.ReduceRegion(earthengine.NewPoint(-122.4, 45.5), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	if !strings.Contains(lastRequest(), earthengine.AlgorithmImageClip) {
+		t.Errorf("request does not clip: %s", lastRequest())
+	}
+}
+
+func TestClipToBounds(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"elevation": 50}}`)
+	image := client.Image("USGS/SRTMGL1_003")
+
+	got, err := ClipToBounds(image, Bounds{})
+	if err != nil || got != image {
+		t.Errorf("ClipToBounds with zero bounds = %v, %v, want image unchanged", got, err)
+	}
+
+	if _, err := ClipToBounds(image, Bounds{MinLon: -122.3, MinLat: 45.4, MaxLon: -122.5, MaxLat: 45.6}); err == nil {
+		t.Error("Expected error for invalid bounds")
+	}
+
+	clipped, err := ClipToBounds(image, Bounds{MinLon: -122.5, MinLat: 45.4, MaxLon: -122.3, MaxLat: 45.6})
+	if err != nil {
+		t.Fatalf("ClipToBounds failed: %v", err)
+	}
+	if _, err := clipped.ReduceRegion(earthengine.NewPoint(-122.4, 45.5), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	for _, want := range []string{earthengine.AlgorithmImageClip, earthengine.AlgorithmGeometryRectangle} {
+		if !strings.Contains(lastRequest(), want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
 func TestDistanceMeters(t *testing.T) {
 	// Test Portland to Seattle (approximately 233 km)
 	portland := struct{ lat, lon float64 }{45.5152, -122.6784}
@@ -193,6 +302,14 @@ func TestDistanceMeters(t *testing.T) {
 	}
 }
 
+func TestDistanceMetersShort(t *testing.T) {
+	// About 534m north and 125m west
+	distance := DistanceMeters(45.5152, -122.6784, 45.52, -122.68)
+	if math.Abs(distance-548) > 5 {
+		t.Errorf("DistanceMeters() = %.1f, want approximately 548", distance)
+	}
+}
+
 func TestDistanceMetersZero(t *testing.T) {
 	// Distance from a point to itself should be zero
 	lat, lon := 45.5152, -122.6784
@@ -268,6 +385,244 @@ func TestBufferInvalidInputs(t *testing.T) {
 	}
 }
 
+// geometryJSON returns the serialized expression for geom.
+func geometryJSON(t *testing.T, geom earthengine.Geometry) string {
+	t.Helper()
+
+	expr := earthengine.NewExpressionBuilder()
+	data, err := json.Marshal(expr.Build(geom.NodeID(expr)))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestBuffer(t *testing.T) {
+	geom, err := Buffer(earthengine.NewPoint(-122.6784, 45.5152), 1000)
+	if err != nil {
+		t.Fatalf("Buffer failed: %v", err)
+	}
+	if got := geometryJSON(t, geom); !strings.Contains(got, earthengine.AlgorithmGeometryBuffer) || !strings.Contains(got, "1000") {
+		t.Errorf("expression = %s, want a 1000m Geometry.buffer", got)
+	}
+}
+
+func TestBufferGeometry(t *testing.T) {
+	point := earthengine.NewPoint(-119.7, 36.8)
+
+	geom, err := BufferGeometry(point, 500)
+	if err != nil {
+		t.Fatalf("BufferGeometry failed: %v", err)
+	}
+	if buffer, ok := geom.(earthengine.Buffer); !ok || buffer.Distance != 500 || buffer.Geometry != point {
+		t.Errorf("BufferGeometry() = %#v, want a 500m buffer of the point", geom)
+	}
+
+	if geom, err := BufferGeometry(point, 0); err != nil || geom != point {
+		t.Errorf("BufferGeometry(0) = %v, %v, want the point unchanged", geom, err)
+	}
+	if _, err := BufferGeometry(point, -1); err == nil {
+		t.Error("Expected error for negative distance")
+	}
+	if _, err := BufferGeometry(nil, 500); err == nil {
+		t.Error("Expected error for nil geometry")
+	}
+}
+
+func TestBufferFeatures(t *testing.T) {
+	wells := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-119.7, 36.8), map[string]interface{}{"well_id": "a"}),
+		earthengine.NewFeature(earthengine.NewPoint(-119.6, 36.9), map[string]interface{}{"well_id": "b"}),
+	)
+
+	zones, err := BufferFeatures(wells, 500)
+	if err != nil {
+		t.Fatalf("BufferFeatures failed: %v", err)
+	}
+	if zones.Size() != 2 {
+		t.Fatalf("got %d zones, want 2", zones.Size())
+	}
+	for i, zone := range zones.Features {
+		if _, ok := zone.Geometry.(earthengine.Buffer); !ok {
+			t.Errorf("zone %d geometry = %#v, want a buffer", i, zone.Geometry)
+		}
+		if zone.Properties["well_id"] != wells.Features[i].Properties["well_id"] {
+			t.Errorf("zone %d properties = %v", i, zone.Properties)
+		}
+	}
+	if _, ok := wells.Features[0].Geometry.(earthengine.Point); !ok {
+		t.Error("BufferFeatures modified the input collection")
+	}
+
+	if _, err := BufferFeatures(wells, -500); err == nil {
+		t.Error("Expected error for negative distance")
+	}
+	if _, err := BufferFeatures(nil, 500); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+}
+
+func TestDissolveFeatureCollection(t *testing.T) {
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewBuffer(earthengine.NewPoint(-119.7, 36.8), 500), nil),
+		earthengine.NewFeature(earthengine.NewBuffer(earthengine.NewPoint(-119.7, 36.801), 500), nil),
+	)
+
+	dissolved, err := DissolveFeatureCollection(zones)
+	if err != nil {
+		t.Fatalf("DissolveFeatureCollection failed: %v", err)
+	}
+	if dissolved.Size() != 1 {
+		t.Fatalf("got %d features, want 1", dissolved.Size())
+	}
+	got := geometryJSON(t, dissolved.Features[0].Geometry)
+	for _, want := range []string{earthengine.AlgorithmCollectionGeometry, earthengine.AlgorithmGeometryDissolve, earthengine.AlgorithmGeometryBuffer} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expression does not contain %q", want)
+		}
+	}
+
+	if _, err := DissolveFeatureCollection(earthengine.NewFeatureCollection()); err == nil {
+		t.Error("Expected error for empty collection")
+	}
+	if _, err := DissolveFeatureCollection(earthengine.NewFeatureCollection(earthengine.Feature{})); err == nil {
+		t.Error("Expected error for feature without geometry")
+	}
+}
+
+func TestIntersectGeometries(t *testing.T) {
+	a := earthengine.NewRectangle(-122.5, 45.4, -122.3, 45.6)
+	b := earthengine.NewBuffer(earthengine.NewPoint(-122.3, 45.5), 5000)
+
+	geom, err := IntersectGeometries(a, b)
+	if err != nil {
+		t.Fatalf("IntersectGeometries failed: %v", err)
+	}
+	got := geometryJSON(t, geom)
+	for _, want := range []string{earthengine.AlgorithmGeometryIntersection, earthengine.AlgorithmGeometryRectangle, earthengine.AlgorithmGeometryBuffer} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expression does not contain %q", want)
+		}
+	}
+
+	if _, err := IntersectGeometries(a, nil); err == nil {
+		t.Error("Expected error for nil geometry")
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	// A square with a square hole in the middle
+	donut := earthengine.NewPolygon(
+		[][2]float64{{-123, 45}, {-122, 45}, {-122, 46}, {-123, 46}, {-123, 45}},
+		[][2]float64{{-122.6, 45.4}, {-122.4, 45.4}, {-122.4, 45.6}, {-122.6, 45.6}, {-122.6, 45.4}},
+	)
+	square := earthengine.NewPolygon([][2]float64{{-121, 45}, {-120, 45}, {-120, 46}, {-121, 46}, {-121, 45}})
+	circle := earthengine.NewBuffer(earthengine.NewPoint(-122.6784, 45.5152), 1000)
+
+	tests := []struct {
+		name     string
+		geom     earthengine.Geometry
+		lat, lon float64
+		want     bool
+	}{
+		{"polygon inside", donut, 45.1, -122.9, true},
+		{"polygon hole", donut, 45.5, -122.5, false},
+		{"polygon outside", donut, 47, -122.5, false},
+		{"rectangle inside", earthengine.NewRectangle(-123, 45, -122, 46), 45.5, -122.5, true},
+		{"rectangle outside", earthengine.NewRectangle(-123, 45, -122, 46), 45.5, -121.5, false},
+		{"multipolygon second part", earthengine.NewMultiPolygon(donut, square), 45.5, -120.5, true},
+		{"multipolygon between parts", earthengine.NewMultiPolygon(donut, square), 45.5, -121.5, false},
+		{"buffered point inside", circle, 45.52, -122.68, true},
+		{"buffered point outside", circle, 45.53, -122.68, false},
+		{"intersection", earthengine.NewIntersection(donut, earthengine.NewRectangle(-123, 45, -122.9, 46)), 45.5, -122.95, true},
+		{"intersection outside one", earthengine.NewIntersection(donut, earthengine.NewRectangle(-123, 45, -122.9, 46)), 45.5, -122.7, false},
+		{"dissolve", earthengine.NewDissolve(earthengine.NewFeatureCollection(
+			earthengine.NewFeature(donut, nil), earthengine.NewFeature(square, nil))), 45.5, -120.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ContainsPoint(tt.geom, tt.lat, tt.lon)
+			if err != nil {
+				t.Fatalf("ContainsPoint failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ContainsPoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsPointErrors(t *testing.T) {
+	if _, err := ContainsPoint(nil, 45, -122); err == nil {
+		t.Error("Expected error for nil geometry")
+	}
+	if _, err := ContainsPoint(earthengine.NewRectangle(-123, 45, -122, 46), 95, -122); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+	buffered := earthengine.NewBuffer(earthengine.NewRectangle(-123, 45, -122, 46), 100)
+	if _, err := ContainsPoint(buffered, 45.5, -122.5); err == nil {
+		t.Error("Expected error for buffered rectangle")
+	}
+}
+
+func TestSpatialJoin(t *testing.T) {
+	counties := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewRectangle(-123, 45, -122, 46), map[string]interface{}{"fips": "41051"}),
+		earthengine.NewFeature(earthengine.NewRectangle(-122, 45, -121, 46), map[string]interface{}{"fips": "41005"}),
+	)
+	sites := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-122.5, 45.5), map[string]interface{}{"site": "a"}),
+		earthengine.NewFeature(earthengine.NewPoint(-121.5, 45.5), map[string]interface{}{"site": "b"}),
+		earthengine.NewFeature(earthengine.NewPoint(-100, 40), nil),
+	)
+
+	tagged, err := SpatialJoin(context.Background(), nil, sites, counties, "fips")
+	if err != nil {
+		t.Fatalf("SpatialJoin failed: %v", err)
+	}
+	if tagged.Size() != 3 {
+		t.Fatalf("got %d features, want 3", tagged.Size())
+	}
+
+	for i, want := range []interface{}{"41051", "41005", nil} {
+		value, ok := tagged.Features[i].Properties["fips"]
+		if !ok || value != want {
+			t.Errorf("feature %d fips = %v (set: %v), want %v", i, value, ok, want)
+		}
+	}
+	if tagged.Features[0].Properties["site"] != "a" {
+		t.Errorf("feature 0 lost its properties: %v", tagged.Features[0].Properties)
+	}
+	if _, ok := sites.Features[0].Properties["fips"]; ok {
+		t.Error("SpatialJoin modified the input points")
+	}
+}
+
+func TestSpatialJoinErrors(t *testing.T) {
+	ctx := context.Background()
+	polygons := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewRectangle(-123, 45, -122, 46), nil),
+	)
+	points := earthengine.NewFeatureCollection(earthengine.NewFeature(earthengine.NewPoint(-122.5, 45.5), nil))
+
+	if _, err := SpatialJoin(ctx, nil, nil, polygons, "id"); err == nil {
+		t.Error("Expected error for nil points")
+	}
+	if _, err := SpatialJoin(ctx, nil, points, polygons, ""); err == nil {
+		t.Error("Expected error for empty property")
+	}
+	notPoints := earthengine.NewFeatureCollection(earthengine.NewFeature(earthengine.NewRectangle(-123, 45, -122, 46), nil))
+	if _, err := SpatialJoin(ctx, nil, notPoints, polygons, "id"); err == nil {
+		t.Error("Expected error for non-point feature")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := SpatialJoin(canceled, nil, points, polygons, "id"); err == nil {
+		t.Error("Expected error for canceled context")
+	}
+}
+
 func TestMathHelpers(t *testing.T) {
 	// Test our simplified math functions are reasonably accurate
 
@@ -324,3 +679,101 @@ func ExampleDistanceMeters() {
 	// distance := DistanceMeters(45.5152, -122.6784, 47.6062, -122.3321)
 	// fmt.Printf("Distance: %.0f meters (%.0f km)\n", distance, distance/1000)
 }
+
+func TestReproject(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"elevation": 50}}`)
+	srtm := client.Image("USGS/SRTMGL1_003")
+
+	dem, err := Resample(srtm, "bilinear")
+	if err != nil {
+		t.Fatalf("Resample failed: %v", err)
+	}
+	dem, err = Reproject(dem, "EPSG:32610", 10)
+	if err != nil {
+		t.Fatalf("Reproject failed: %v", err)
+	}
+	if _, err := dem.ReduceRegion(earthengine.NewPoint(-122.4, 45.5), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	for _, want := range []string{earthengine.AlgorithmImageResample, `"bilinear"`, earthengine.AlgorithmImageReproject, `"EPSG:32610"`} {
+		if !strings.Contains(lastRequest(), want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+}
+
+func TestReprojectErrors(t *testing.T) {
+	client := &earthengine.Client{}
+	image := client.Image("USGS/SRTMGL1_003")
+
+	if _, err := Reproject(nil, "EPSG:4326", 30); err == nil {
+		t.Error("expected error for nil image")
+	}
+	if _, err := Reproject(image, "", 30); err == nil {
+		t.Error("expected error for empty CRS")
+	}
+	if _, err := Reproject(image, "EPSG:4326", 0); err == nil {
+		t.Error("expected error for zero scale")
+	}
+	if _, err := Resample(image, "nearest"); err == nil {
+		t.Error("expected error for unsupported method")
+	}
+	if _, err := Resample(nil, "bicubic"); err == nil {
+		t.Error("expected error for nil image")
+	}
+}
+
+func TestAggregatePixels(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.4}}`)
+	ndvi := client.Image("projects/test/assets/ndvi")
+
+	coarse, err := AggregatePixels(ndvi, 100, Mean)
+	if err != nil {
+		t.Fatalf("AggregatePixels failed: %v", err)
+	}
+	if _, err := coarse.ReduceRegion(earthengine.NewPoint(-122.4, 45.5), earthengine.ReducerFirst()).Compute(context.Background()); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{
+		earthengine.AlgorithmImageReduceResolution,
+		earthengine.AlgorithmReducerMean,
+		`"constantValue":10201`, // (100 + 1)² input pixels
+		earthengine.AlgorithmProjectionScale,
+		`"constantValue":100`,
+		earthengine.AlgorithmImageReproject,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+}
+
+func TestAggregatePixelsErrors(t *testing.T) {
+	client := &earthengine.Client{}
+	image := client.Image("projects/test/assets/ndvi")
+
+	tests := []struct {
+		name      string
+		image     *earthengine.Image
+		factor    int
+		statistic ZonalStatistic
+	}{
+		{"nil image", nil, 10, Mean},
+		{"factor below 2", image, 1, Mean},
+		{"too many input pixels", image, 256, Mean},
+		{"unsupported statistic", image, 10, ZonalStatistic("range")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AggregatePixels(tt.image, tt.factor, tt.statistic); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := AggregatePixels(image, 255, Max); err != nil {
+		t.Errorf("AggregatePixels at the pixel limit failed: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+const (
+	// metersPerDegreeLat is the approximate length of one degree of latitude.
+	metersPerDegreeLat = 111320.0
+
+	// maxGridPoints limits the size of generated grids.
+	maxGridPoints = 10000
+)
+
+// GridPoints generates a regular grid of points over bounds, spaced roughly
+// spacingMeters apart, as [lon, lat] pairs.
+//
+// Points start at the southwest corner and are ordered west to east, then
+// south to north. The longitude spacing is computed at the center latitude,
+// so every row has the same columns. Grids are limited to 10,000 points.
+//
+// Example:
+//
+//	bounds := helpers.Bounds{MinLon: -122.7, MinLat: 45.4, MaxLon: -122.5, MaxLat: 45.6}
+//	points, err := helpers.GridPoints(bounds, 1000) // ~1km grid
+func GridPoints(bounds Bounds, spacingMeters float64) ([][2]float64, error) {
+	if err := bounds.Validate(); err != nil {
+		return nil, err
+	}
+	if spacingMeters <= 0 {
+		return nil, fmt.Errorf("spacing must be positive, got %f", spacingMeters)
+	}
+
+	centerLat, _ := bounds.Center()
+	latStep := spacingMeters / metersPerDegreeLat
+	lonStep := spacingMeters / (metersPerDegreeLat * math.Cos(centerLat*math.Pi/180))
+
+	// Keep the far edge when it is within a hair of a whole step
+	const epsilon = 1e-6 // Fraction of a step
+	rows := int(math.Floor((bounds.MaxLat-bounds.MinLat)/latStep+epsilon)) + 1
+	cols := int(math.Floor((bounds.MaxLon-bounds.MinLon)/lonStep+epsilon)) + 1
+	if rows*cols > maxGridPoints {
+		return nil, fmt.Errorf("grid has %d points, maximum is %d; increase the spacing", rows*cols, maxGridPoints)
+	}
+
+	points := make([][2]float64, 0, rows*cols)
+	for i := 0; i < rows; i++ {
+		lat := math.Min(bounds.MinLat+float64(i)*latStep, bounds.MaxLat)
+		for j := 0; j < cols; j++ {
+			lon := math.Min(bounds.MinLon+float64(j)*lonStep, bounds.MaxLon)
+			points = append(points, [2]float64{lon, lat})
+		}
+	}
+
+	return points, nil
+}
+
+// GridResult is the result of a query at one grid point.
+type GridResult struct {
+	Result
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// SampleGrid runs a point query at every node of a regular grid over bounds.
+//
+// query builds the query for each node; the queries run concurrently as a
+// Batch configured by opts. Results are in GridPoints order with each node's
+// coordinates attached, and failed nodes have a non-nil Error. If ctx is
+// canceled, the partial results are returned with ctx's error. This makes a
+// coarse raster from any point helper without an Earth Engine-side reduction.
+//
+// Example:
+//
+//	results, err := helpers.SampleGrid(ctx, client, bounds, 500,
+//	    func(lat, lon float64) helpers.Query {
+//	        return helpers.NewElevationQuery(lat, lon)
+//	    },
+//	    helpers.WithConcurrency(20))
+//	for _, r := range results {
+//	    if r.Error == nil {
+//	        fmt.Printf("%.4f,%.4f: %v\n", r.Lat, r.Lon, r.Value)
+//	    }
+//	}
+func SampleGrid(ctx context.Context, client *earthengine.Client, bounds Bounds, spacingMeters float64, query func(lat, lon float64) Query, opts ...BatchOption) ([]GridResult, error) {
+	if query == nil {
+		return nil, fmt.Errorf("query function cannot be nil")
+	}
+
+	points, err := GridPoints(bounds, spacingMeters)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := NewBatch(client, 0, opts...)
+	for _, pt := range points {
+		batch.Add(query(pt[1], pt[0]))
+	}
+
+	// On cancellation Execute returns partial results with the error
+	results, err := batch.Execute(ctx)
+	if results == nil {
+		return nil, err
+	}
+
+	grid := make([]GridResult, len(results))
+	for i, r := range results {
+		grid[i] = GridResult{Result: r, Lat: points[i][1], Lon: points[i][0]}
+	}
+
+	return grid, err
+}
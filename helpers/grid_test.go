@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func TestGridPoints(t *testing.T) {
+	// ~1.1km square at the equator, 0.005 degrees per step
+	bounds := Bounds{MinLon: 0, MinLat: 0, MaxLon: 0.01, MaxLat: 0.01}
+	points, err := GridPoints(bounds, 0.005*metersPerDegreeLat)
+	if err != nil {
+		t.Fatalf("GridPoints failed: %v", err)
+	}
+
+	if len(points) != 9 {
+		t.Fatalf("len(points) = %d, want 9", len(points))
+	}
+	if points[0] != [2]float64{0, 0} {
+		t.Errorf("points[0] = %v, want southwest corner", points[0])
+	}
+	last := points[len(points)-1]
+	if math.Abs(last[0]-0.01) > 1e-6 || math.Abs(last[1]-0.01) > 1e-6 {
+		t.Errorf("last point = %v, want northeast corner", last)
+	}
+	if points[1][1] != 0 || points[1][0] <= points[0][0] {
+		t.Errorf("points[1] = %v, want next point east on the first row", points[1])
+	}
+	for _, pt := range points {
+		if !bounds.Contains(pt[1], pt[0]) {
+			t.Errorf("point %v outside bounds", pt)
+		}
+	}
+}
+
+func TestGridPointsLongitudeSpacing(t *testing.T) {
+	// At 60°N a degree of longitude is half as long, so columns are twice as wide
+	bounds := Bounds{MinLon: 10, MinLat: 59.99, MaxLon: 10.1, MaxLat: 60.01}
+	points, err := GridPoints(bounds, 0.01*metersPerDegreeLat)
+	if err != nil {
+		t.Fatalf("GridPoints failed: %v", err)
+	}
+
+	if step := points[1][0] - points[0][0]; math.Abs(step-0.02) > 1e-4 {
+		t.Errorf("longitude step = %v, want ~0.02", step)
+	}
+}
+
+func TestGridPointsErrors(t *testing.T) {
+	bounds := Bounds{MinLon: 0, MinLat: 0, MaxLon: 1, MaxLat: 1}
+
+	if _, err := GridPoints(bounds, 0); err == nil {
+		t.Error("Expected error for zero spacing")
+	}
+	if _, err := GridPoints(Bounds{MinLon: 1, MinLat: 0, MaxLon: 0, MaxLat: 1}, 1000); err == nil {
+		t.Error("Expected error for invalid bounds")
+	}
+	if _, err := GridPoints(bounds, 10); err == nil || !strings.Contains(err.Error(), "maximum") {
+		t.Errorf("error = %v, want grid size error", err)
+	}
+}
+
+// pointQuery returns its coordinates, or an error north of failAbove.
+type pointQuery struct {
+	lat, lon  float64
+	failAbove float64
+}
+
+func (q pointQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	if q.lat > q.failAbove {
+		return nil, errors.New("no data")
+	}
+	return [2]float64{q.lon, q.lat}, nil
+}
+
+func TestSampleGrid(t *testing.T) {
+	bounds := Bounds{MinLon: 0, MinLat: 0, MaxLon: 0.01, MaxLat: 0.01}
+	results, err := SampleGrid(context.Background(), nil, bounds, 0.005*metersPerDegreeLat,
+		func(lat, lon float64) Query {
+			return pointQuery{lat: lat, lon: lon, failAbove: 0.009}
+		},
+		WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("SampleGrid failed: %v", err)
+	}
+
+	if len(results) != 9 {
+		t.Fatalf("len(results) = %d, want 9", len(results))
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("results[%d].Index = %d", i, r.Index)
+		}
+		if r.Lat > 0.009 {
+			if r.Error == nil {
+				t.Errorf("results[%d] at lat %v: expected error", i, r.Lat)
+			}
+			continue
+		}
+		if r.Error != nil {
+			t.Errorf("results[%d].Error = %v", i, r.Error)
+			continue
+		}
+		if r.Value != [2]float64{r.Lon, r.Lat} {
+			t.Errorf("results[%d].Value = %v, want coordinates %v, %v", i, r.Value, r.Lon, r.Lat)
+		}
+	}
+}
+
+func TestSampleGridNilQuery(t *testing.T) {
+	bounds := Bounds{MinLon: 0, MinLat: 0, MaxLon: 0.01, MaxLat: 0.01}
+	if _, err := SampleGrid(context.Background(), nil, bounds, 500, nil); err == nil {
+		t.Error("Expected error for nil query function")
+	}
+}
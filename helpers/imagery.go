@@ -3,6 +3,9 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -21,18 +24,27 @@ const (
 	// MODIS Terra Vegetation Indices (NDVI, EVI)
 	modisVIDatasetID = "MODIS/006/MOD13A1"
 
+	// MODIS Terra Land Surface Temperature (daily, 1km)
+	modisLSTDatasetID = "MODIS/061/MOD11A1"
+
 	// Default scale for imagery operations (meters)
 	defaultImageryScale = 30.0
+
+	// Default number of days searched around a single date
+	defaultDateWindowDays = 30
 )
 
 // ImageryOption configures imagery queries.
 type ImageryOption func(*imageryConfig)
 
 type imageryConfig struct {
-	dataset    string
-	cloudCover *float64
-	dateRange  *DateRange
-	scale      *float64
+	dataset        string
+	cloudCover     *float64
+	dateRange      *DateRange
+	scale          *float64
+	waterThreshold *float64
+	compositeDays  *int
+	windowDays     *int
 }
 
 // Landsat8 uses Landsat 8 imagery (default, 30m resolution).
@@ -63,6 +75,14 @@ func MODIS() ImageryOption {
 	}
 }
 
+// MODISLST uses MODIS land surface temperature (1km resolution).
+// This is only supported by LandSurfaceTemperature.
+func MODISLST() ImageryOption {
+	return func(cfg *imageryConfig) {
+		cfg.dataset = modisLSTDatasetID
+	}
+}
+
 // CloudMask sets the maximum cloud cover percentage (0-100).
 func CloudMask(maxCloudPercent float64) ImageryOption {
 	return func(cfg *imageryConfig) {
@@ -84,58 +104,180 @@ func ImageryWithScale(meters float64) ImageryOption {
 	}
 }
 
+// CompositeDays sets the number of days, starting at each date, composited
+// by ImageDifference and ImageDifferenceRegion (default 16, one Landsat
+// revisit).
+func CompositeDays(days int) ImageryOption {
+	return func(cfg *imageryConfig) {
+		cfg.compositeDays = &days
+	}
+}
+
+// DateWindow sets the number of days, centered on the date, that NDVI and
+// the other point indices search when no DateRangeOption is given
+// (default 30).
+func DateWindow(days int) ImageryOption {
+	return func(cfg *imageryConfig) {
+		cfg.windowDays = &days
+	}
+}
+
+// WaterThreshold sets the NDWI value above which pixels are classified as water
+// by DetectWater (default 0).
+func WaterThreshold(ndwi float64) ImageryOption {
+	return func(cfg *imageryConfig) {
+		cfg.waterThreshold = &ndwi
+	}
+}
+
 // getBandNames returns the NIR and Red band names for a given dataset.
-func getBandNames(dataset string) (nir, red string) {
-	switch dataset {
-	case landsat8DatasetID, landsat9DatasetID:
-		return "SR_B5", "SR_B4" // Landsat 8/9: B5=NIR, B4=Red
-	case sentinel2DatasetID:
-		return "B8", "B4" // Sentinel-2: B8=NIR, B4=Red
-	case modisVIDatasetID:
-		return "sur_refl_b02", "sur_refl_b01" // MODIS: b02=NIR, b01=Red
-	default:
-		return "SR_B5", "SR_B4" // Default to Landsat
+func getBandNames(dataset string) (nir, red string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "NIR", "Red"); err != nil {
+		return "", "", err
+	}
+	return bands.NIR, bands.Red, nil
+}
+
+// getNIRBand returns the NIR band name for a given dataset.
+func getNIRBand(dataset string) (string, error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "NIR"); err != nil {
+		return "", err
 	}
+	return bands.NIR, nil
 }
 
 // getBandNamesForWater returns the Green and NIR band names for NDWI calculation.
-func getBandNamesForWater(dataset string) (green, nir string) {
+func getBandNamesForWater(dataset string) (green, nir string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "Green", "NIR"); err != nil {
+		return "", "", err
+	}
+	return bands.Green, bands.NIR, nil
+}
+
+// getBandNamesForSnow returns the Green and SWIR1 band names for NDSI calculation.
+func getBandNamesForSnow(dataset string) (green, swir string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "Green", "SWIR1"); err != nil {
+		return "", "", err
+	}
+	return bands.Green, bands.SWIR1, nil
+}
+
+// thermalBand describes how to convert a dataset's thermal band to Kelvin.
+type thermalBand struct {
+	band   string
+	scale  float64
+	offset float64 // Kelvin = value*scale + offset
+	meters float64 // Native resolution, used as the default scale
+}
+
+// getThermalBand returns the thermal band for a dataset, or false if the
+// dataset has no land surface temperature band.
+func getThermalBand(dataset string) (thermalBand, bool) {
 	switch dataset {
 	case landsat8DatasetID, landsat9DatasetID:
-		return "SR_B3", "SR_B5" // Landsat: B3=Green, B5=NIR
-	case sentinel2DatasetID:
-		return "B3", "B8" // Sentinel-2: B3=Green, B8=NIR
+		return thermalBand{band: "ST_B10", scale: 0.00341802, offset: 149.0, meters: 30}, true
+	case modisLSTDatasetID:
+		return thermalBand{band: "LST_Day_1km", scale: 0.02, offset: 0, meters: 1000}, true
 	default:
-		return "SR_B3", "SR_B5" // Default to Landsat
+		return thermalBand{}, false
 	}
 }
 
 // getBandNamesForBuiltUp returns the SWIR and NIR band names for NDBI calculation.
-func getBandNamesForBuiltUp(dataset string) (swir, nir string) {
-	switch dataset {
-	case landsat8DatasetID, landsat9DatasetID:
-		return "SR_B6", "SR_B5" // Landsat: B6=SWIR1, B5=NIR
-	case sentinel2DatasetID:
-		return "B11", "B8" // Sentinel-2: B11=SWIR, B8=NIR
-	default:
-		return "SR_B6", "SR_B5" // Default to Landsat
+func getBandNamesForBuiltUp(dataset string) (swir, nir string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "SWIR1", "NIR"); err != nil {
+		return "", "", err
+	}
+	return bands.SWIR1, bands.NIR, nil
+}
+
+// getBandNamesForMoisture returns the NIR and SWIR band names for NDMI
+// calculation. NDMI uses the same SWIR1 and NIR bands as NDBI, not the Green
+// band of NDWI.
+func getBandNamesForMoisture(dataset string) (nir, swir string, err error) {
+	swir, nir, err = getBandNamesForBuiltUp(dataset)
+	return nir, swir, err
+}
+
+// getBandNamesForGNDVI returns the NIR and Green band names for GNDVI calculation.
+func getBandNamesForGNDVI(dataset string) (nir, green string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "NIR", "Green"); err != nil {
+		return "", "", err
 	}
+	return bands.NIR, bands.Green, nil
+}
+
+// getRedEdgeBand returns the red-edge band used by NDRE and CIre, or false if
+// the dataset has none. Landsat has no red-edge bands; Sentinel-2 uses B5.
+func getRedEdgeBand(dataset string) (string, bool) {
+	band := datasetBands(dataset).RedEdge
+	return band, band != ""
 }
 
 // getBandNamesForEVI returns the NIR, Red, and Blue band names for EVI calculation.
-func getBandNamesForEVI(dataset string) (nir, red, blue string) {
-	switch dataset {
-	case landsat8DatasetID, landsat9DatasetID:
-		return "SR_B5", "SR_B4", "SR_B2" // Landsat: B5=NIR, B4=Red, B2=Blue
-	case sentinel2DatasetID:
-		return "B8", "B4", "B2" // Sentinel-2: B8=NIR, B4=Red, B2=Blue
-	default:
-		return "SR_B5", "SR_B4", "SR_B2" // Default to Landsat
+func getBandNamesForEVI(dataset string) (nir, red, blue string, err error) {
+	bands := datasetBands(dataset)
+	if err := bands.require(dataset, "NIR", "Red", "Blue"); err != nil {
+		return "", "", "", err
+	}
+	return bands.NIR, bands.Red, bands.Blue, nil
+}
+
+// getBandNamesForHarmonized returns the blue, green, red, NIR, SWIR1, and SWIR2
+// band names for a given dataset, in the order of harmonizedBands.
+func getBandNamesForHarmonized(dataset string) []string {
+	bands := datasetBands(dataset)
+	return []string{bands.Blue, bands.Green, bands.Red, bands.NIR, bands.SWIR1, bands.SWIR2}
+}
+
+// getReflectanceScaling returns the scale and offset that convert a dataset's
+// surface reflectance values to unitless reflectance.
+func getReflectanceScaling(dataset string) (scale, offset float64) {
+	if def, ok := LookupDataset(dataset); ok && def.Scale != 0 {
+		return def.Scale, def.Offset
 	}
+	return 0.0000275, -0.2 // Landsat Collection 2 Level-2
+}
+
+// SafeDivide divides band numBand of image by band denBand, masking pixels
+// where the denominator is zero instead of producing Inf or NaN values that
+// would propagate into reductions. The result has one band, named numBand.
+//
+// Example:
+//
+//	// Simple ratio index, masked where NIR is zero
+//	ratio := helpers.SafeDivide(image, "B4", "B8")
+func SafeDivide(image *earthengine.Image, numBand, denBand string) *earthengine.Image {
+	denominator := image.Select(denBand)
+	return image.Select(numBand).
+		Divide(denominator).
+		UpdateMask(denominator.Neq(0))
+}
+
+// safeNormalizedDifference computes the normalized difference of a two-band
+// image like Image.NormalizedDifference, masking pixels where the sum of the
+// bands is zero (deep shadow, nodata) so they reduce to no value instead of a
+// bogus one. The result has one band, named "nd".
+func safeNormalizedDifference(image *earthengine.Image) *earthengine.Image {
+	// Rename so the bands can be selected whatever a reducer named them
+	pair := image.Rename("b1", "b2")
+	denominator := pair.Select("b1").Add(pair.Select("b2"))
+	return pair.NormalizedDifference().UpdateMask(denominator.Neq(0))
 }
 
 // NDVI calculates the Normalized Difference Vegetation Index at a point.
 //
+// With DateRangeOption, NDVI is the mean over all images in the range.
+// Otherwise images are searched in a window centered on date (30 days, see
+// DateWindow) and each pixel is taken from the least cloudy scene in which it
+// is clear.
+//
 // NDVI = (NIR - Red) / (NIR + Red)
 // Values range from -1 to 1, where:
 //   - < 0: Water, clouds, snow
@@ -171,29 +313,25 @@ func NDVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	}
 
 	// Get the appropriate band names for NIR and Red
-	nirBand, redBand := getBandNames(cfg.dataset)
+	nirBand, redBand, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
 
 	// Build the query
 	collection := client.ImageCollection(cfg.dataset)
 
-	// Apply date filtering
-	if cfg.dateRange != nil {
-		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
-	} else {
-		// Use a 30-day window centered on the date
-		collection = collection.FilterDate(date, date)
+	// Apply date and cloud filtering
+	collection, err = filterImagery(collection, cfg, date)
+	if err != nil {
+		return 0, err
 	}
 
-	// Apply cloud filtering if specified
-	if cfg.cloudCover != nil {
-		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
-	}
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset).Select(nirBand, redBand)
 
-	// Select NIR and Red bands, calculate NDVI using normalized difference
-	image := collection.
-		Select(nirBand, redBand).
-		Reduce(earthengine.ReducerMean()).
-		NormalizedDifference()
+	// Calculate NDVI using normalized difference
+	image := safeNormalizedDifference(combineImagery(collection, cfg))
 
 	// Determine scale
 	scale := defaultImageryScale
@@ -217,6 +355,308 @@ func NDVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	return result, nil
 }
 
+// centeredWindow returns the range of days days centered on date, with an
+// exclusive End for ImageCollection.FilterDate.
+func centeredWindow(date string, days int) (DateRange, error) {
+	if days <= 0 {
+		return DateRange{}, fmt.Errorf("date window must be positive, got %d days", days)
+	}
+	center, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD): %w", date, err)
+	}
+	start := center.AddDate(0, 0, -days/2)
+	return NewDateRange(start, start.AddDate(0, 0, days)), nil
+}
+
+// filterImagery filters a collection of cfg.dataset to the configured date
+// range, or else to the DateWindow centered on date, and, if CloudMask is
+// set, to scenes below the cloud cover limit. Cloud cover is read from the
+// dataset's scene property (CLOUDY_PIXEL_PERCENTAGE for Sentinel-2,
+// CLOUD_COVER for Landsat); datasets without one are not filtered.
+func filterImagery(collection *earthengine.ImageCollection, cfg *imageryConfig, date string) (*earthengine.ImageCollection, error) {
+	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid date range: %w", err)
+		}
+		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
+	} else {
+		days := defaultDateWindowDays
+		if cfg.windowDays != nil {
+			days = *cfg.windowDays
+		}
+		window, err := centeredWindow(date, days)
+		if err != nil {
+			return nil, err
+		}
+		collection = collection.FilterDate(window.Start, window.End)
+	}
+
+	return filterCloudCover(collection, cfg), nil
+}
+
+// filterCloudCover keeps scenes below the CloudMask limit, if set, using the
+// dataset's scene cloud cover property. Datasets without one are not
+// filtered.
+func filterCloudCover(collection *earthengine.ImageCollection, cfg *imageryConfig) *earthengine.ImageCollection {
+	if property := cloudCoverProperty(cfg.dataset); cfg.cloudCover != nil && property != "" {
+		return collection.FilterMetadata(property, "less_than", *cfg.cloudCover)
+	}
+	return collection
+}
+
+// combineImagery combines a collection filtered by filterImagery into one
+// image: the mean over an explicit date range or, for a single date, a
+// mosaic with the least cloudy scene on top so masked pixels fall through
+// to the next.
+func combineImagery(collection *earthengine.ImageCollection, cfg *imageryConfig) *earthengine.Image {
+	if property := cloudCoverProperty(cfg.dataset); cfg.dateRange == nil && property != "" {
+		return collection.Sort(property, false).Mosaic()
+	}
+	return collection.Reduce(earthengine.ReducerMean())
+}
+
+// NDVIAnomalyResult compares a month's NDVI with the same month in a
+// historical baseline.
+type NDVIAnomalyResult struct {
+	Current              float64 `json:"current"`               // Mean NDVI for the month containing the date
+	BaselineMean         float64 `json:"baseline_mean"`         // Mean of the baseline years' monthly NDVI
+	BaselineStdDev       float64 `json:"baseline_std_dev"`      // Standard deviation across baseline years
+	Anomaly              float64 `json:"anomaly"`               // Current - BaselineMean
+	ZScore               float64 `json:"z_score"`               // Anomaly / BaselineStdDev, or 0 if the baseline doesn't vary
+	BaselineYears        int     `json:"baseline_years"`        // Baseline years with at least one observation
+	BaselineObservations int     `json:"baseline_observations"` // Images used across all baseline years
+}
+
+// NDVIAnomaly compares NDVI for the calendar month containing date with the
+// same month in each year from baselineStartYear to baselineEndYear.
+//
+// NDVI is computed for every image at the point and averaged per year, so the
+// baseline statistics describe year-to-year variation. Years without usable
+// imagery are skipped, and the year of date is left out of the baseline. At
+// least two baseline years are needed. DateRangeOption is ignored.
+//
+// Example:
+//
+//	// How does June 2023 compare with June 2013-2022?
+//	anomaly, err := helpers.NDVIAnomaly(ctx, client, 41.59, -93.62, "2023-06-15", 2013, 2022)
+//	fmt.Printf("NDVI %.2f (z = %.1f, %d baseline years)\n",
+//	    anomaly.Current, anomaly.ZScore, anomaly.BaselineYears)
+func NDVIAnomaly(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, baselineStartYear, baselineEndYear int, opts ...ImageryOption) (*NDVIAnomalyResult, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	target, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	if baselineStartYear > baselineEndYear {
+		return nil, fmt.Errorf("baseline start year %d is after end year %d", baselineStartYear, baselineEndYear)
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Gather the target month from every year in one collection
+	years := []int{target.Year()}
+	for year := baselineStartYear; year <= baselineEndYear; year++ {
+		if year != target.Year() {
+			years = append(years, year)
+		}
+	}
+
+	base := client.ImageCollection(cfg.dataset)
+	var collection *earthengine.ImageCollection
+	for _, year := range years {
+		start := time.Date(year, target.Month(), 1, 0, 0, 0, 0, time.UTC)
+		month := base.FilterDate(start.Format("2006-01-02"), start.AddDate(0, 1, 0).Format("2006-01-02"))
+		if collection == nil {
+			collection = month
+		} else {
+			collection = collection.Merge(month)
+		}
+	}
+
+	// Apply cloud filtering if specified
+	collection = filterCloudCover(collection, cfg)
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// Add NDVI to each image, keeping its timestamp
+	nirBand, redBand, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
+	collection = collection.Map(func(img *earthengine.Image) *earthengine.Image {
+		return img.AddBands(safeNormalizedDifference(img.Select(nirBand, redBand)).Rename("NDVI"))
+	})
+
+	// Determine scale
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	ts, err := timeSeriesAtPoint(ctx, collection, lat, lon, "NDVI", scale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute NDVI time series: %w", err)
+	}
+
+	// Group observations by year
+	byYear := make(map[int][]float64)
+	for _, p := range ts.Points {
+		byYear[p.Time.Year()] = append(byYear[p.Time.Year()], p.Value)
+	}
+
+	current, ok := byYear[target.Year()]
+	if !ok {
+		return nil, fmt.Errorf("no NDVI observations for %s", target.Format("January 2006"))
+	}
+
+	result := &NDVIAnomalyResult{Current: calculateMean(current)}
+	var baseline []float64
+	for _, year := range years[1:] {
+		values, ok := byYear[year]
+		if !ok {
+			continue // Missing year
+		}
+		baseline = append(baseline, calculateMean(values))
+		result.BaselineObservations += len(values)
+	}
+	result.BaselineYears = len(baseline)
+	if len(baseline) < 2 {
+		return nil, fmt.Errorf("need at least 2 baseline years with observations, got %d", len(baseline))
+	}
+
+	result.BaselineMean = calculateMean(baseline)
+	result.BaselineStdDev = calculateStdDev(baseline, result.BaselineMean)
+	result.Anomaly = result.Current - result.BaselineMean
+	if result.BaselineStdDev > 0 {
+		result.ZScore = result.Anomaly / result.BaselineStdDev
+	}
+
+	return result, nil
+}
+
+// NDVIAnomalyImage maps the standardized NDVI anomaly over bounds: for each
+// pixel, NDVI around date minus the historical mean, divided by the
+// historical standard deviation. It is the spatial counterpart of
+// NDVIAnomaly, for drought maps and exports.
+//
+// To avoid phenology bias, every year uses the same day-of-year window: the
+// DateWindow (30 days by default) centered on date's month and day. The
+// current year's window is averaged per pixel, as is each baseline year's
+// from baselineStartYear to baselineEndYear (leaving out the year of date),
+// and the baseline mean and standard deviation are taken across the yearly
+// means. Pixels whose baseline does not vary, such as those with fewer than
+// two years of clear observations, are masked. The image has one band,
+// "NDVI_anomaly", clipped to bounds. DateRangeOption is ignored.
+//
+// ctx is used to check that there is imagery around date; the image itself
+// is computed lazily when exported or visualized.
+//
+// Example:
+//
+//	county := helpers.Bounds{MinLon: -94.0, MinLat: 41.3, MaxLon: -93.3, MaxLat: 41.9}
+//	anomaly, err := helpers.NDVIAnomalyImage(ctx, client, county, "2023-07-15", 2013, 2022,
+//	    helpers.Sentinel2())
+//	region, err := county.ToRectangle()
+//	task, err := helpers.ExportImageAsync(ctx, client, anomaly,
+//	    helpers.ExportRegion(&region), helpers.ExportScale(30))
+func NDVIAnomalyImage(ctx context.Context, client *earthengine.Client, bounds Bounds, date string, baselineStartYear, baselineEndYear int, opts ...ImageryOption) (*earthengine.Image, error) {
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+	target, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	if baselineStartYear > baselineEndYear {
+		return nil, fmt.Errorf("baseline start year %d is after end year %d", baselineStartYear, baselineEndYear)
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	days := defaultDateWindowDays
+	if cfg.windowDays != nil {
+		days = *cfg.windowDays
+	}
+
+	// windowNDVI averages NDVI over the day-of-year window in one year
+	nirBand, redBand, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
+	windowNDVI := func(year int) (*earthengine.ImageCollection, *earthengine.Image, error) {
+		center := time.Date(year, target.Month(), target.Day(), 0, 0, 0, 0, time.UTC)
+		window, err := centeredWindow(center.Format(dateLayout), days)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		collection := client.ImageCollection(cfg.dataset).
+			FilterBounds(region).
+			FilterDate(window.Start, window.End)
+		collection = maskCollectionClouds(filterCloudCover(collection, cfg), cfg.dataset)
+
+		ndvi := collection.Map(func(img *earthengine.Image) *earthengine.Image {
+			return safeNormalizedDifference(img.Select(nirBand, redBand)).Rename("NDVI")
+		})
+		return collection, ndvi.Reduce(earthengine.ReducerMean()).Rename("NDVI"), nil
+	}
+
+	current, currentNDVI, err := windowNDVI(target.Year())
+	if err != nil {
+		return nil, err
+	}
+	count, err := current.Size(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count images: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w around %s", ErrNoImages, date)
+	}
+
+	var yearly []*earthengine.Image
+	for year := baselineStartYear; year <= baselineEndYear; year++ {
+		if year == target.Year() {
+			continue
+		}
+		_, ndvi, err := windowNDVI(year)
+		if err != nil {
+			return nil, err
+		}
+		yearly = append(yearly, ndvi)
+	}
+	if len(yearly) < 2 {
+		return nil, fmt.Errorf("need at least 2 baseline years, got %d", len(yearly))
+	}
+
+	baseline := client.ImageCollectionFromImages(yearly...)
+	mean := baseline.Reduce(earthengine.ReducerMean())
+	stdDev := baseline.Reduce(earthengine.ReducerStdDev())
+
+	return currentNDVI.
+		Subtract(mean).
+		Divide(stdDev).
+		UpdateMask(stdDev.Gt(0)).
+		Rename("NDVI_anomaly").
+		Clip(region), nil
+}
+
 // EVI calculates the Enhanced Vegetation Index at a point.
 //
 // EVI = 2.5 * ((NIR - Red) / (NIR + 6*Red - 7.5*Blue + 1))
@@ -248,13 +688,19 @@ func EVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon fl
 	}
 
 	// Get the appropriate band names
-	nirBand, redBand, blueBand := getBandNamesForEVI(cfg.dataset)
+	nirBand, redBand, blueBand, err := getBandNamesForEVI(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
 
 	// Build the query
 	collection := client.ImageCollection(cfg.dataset)
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return 0, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -265,6 +711,9 @@ func EVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon fl
 		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
 	}
 
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
 	// Get mean image and select required bands
 	image := collection.Select(nirBand, redBand, blueBand).Reduce(earthengine.ReducerMean())
 
@@ -329,13 +778,19 @@ func SAVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	}
 
 	// Get the appropriate band names
-	nirBand, redBand := getBandNames(cfg.dataset)
+	nirBand, redBand, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
 
 	// Build the query
 	collection := client.ImageCollection(cfg.dataset)
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return 0, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -346,6 +801,9 @@ func SAVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
 	}
 
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
 	// Get mean image and select required bands
 	image := collection.Select(nirBand, redBand).Reduce(earthengine.ReducerMean())
 
@@ -379,6 +837,68 @@ func SAVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	return result, nil
 }
 
+// MSAVI calculates the Modified Soil-Adjusted Vegetation Index (MSAVI2) at a
+// point.
+//
+// MSAVI2 = (2*NIR + 1 - sqrt((2*NIR + 1)^2 - 8*(NIR - Red))) / 2
+//
+// MSAVI adjusts its soil correction to the canopy cover at each pixel
+// instead of using SAVI's fixed L = 0.5, so it is more accurate where soil
+// dominates, such as early-season fields with sparse canopy. Bands are
+// converted to surface reflectance first, since the formula assumes values
+// from 0 to 1.
+//
+// Example:
+//
+//	msavi, err := helpers.MSAVI(client, 41.59, -93.62, "2023-05-20",
+//	    helpers.Sentinel2())
+func MSAVI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return MSAVIWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// MSAVIWithContext is like MSAVI but accepts a context.
+func MSAVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Get the appropriate band names
+	nirBand, redBand, err := getBandNames(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// MSAVI2 = (2*NIR + 1 - sqrt((2*NIR + 1)^2 - 8*(NIR - Red))) / 2, which
+	// needs reflectance rather than scaled digital numbers
+	scaleFactor, offset := getReflectanceScaling(cfg.dataset)
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "MSAVI", []string{nirBand, redBand},
+		func(image *earthengine.Image) *earthengine.Image {
+			reflectance := image.Rename("nir", "red").
+				Multiply(client.Constant(scaleFactor)).
+				Add(client.Constant(offset))
+			return reflectance.Expression("(2*NIR + 1 - sqrt((2*NIR + 1)**2 - 8*(NIR - RED))) / 2",
+				map[string]interface{}{
+					"NIR": reflectance.Select("nir"),
+					"RED": reflectance.Select("red"),
+				})
+		})
+}
+
+// msavi2 computes MSAVI2 from NIR and Red reflectance.
+func msavi2(nir, red float64) float64 {
+	b := 2*nir + 1
+	return (b - math.Sqrt(b*b-8*(nir-red))) / 2
+}
+
 // NDWI calculates the Normalized Difference Water Index at a point.
 //
 // NDWI = (Green - NIR) / (Green + NIR)
@@ -409,13 +929,19 @@ func NDWIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	}
 
 	// Get the appropriate band names
-	greenBand, nirBand := getBandNamesForWater(cfg.dataset)
+	greenBand, nirBand, err := getBandNamesForWater(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
 
 	// Build the query
 	collection := client.ImageCollection(cfg.dataset)
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return 0, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -426,12 +952,14 @@ func NDWIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
 	}
 
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
 	// Select Green and NIR bands, calculate NDWI using normalized difference
 	// NDWI = (Green - NIR) / (Green + NIR)
-	image := collection.
+	image := safeNormalizedDifference(collection.
 		Select(greenBand, nirBand).
-		Reduce(earthengine.ReducerMean()).
-		NormalizedDifference()
+		Reduce(earthengine.ReducerMean()))
 
 	// Determine scale
 	scale := defaultImageryScale
@@ -455,25 +983,169 @@ func NDWIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	return result, nil
 }
 
-// NDBI calculates the Normalized Difference Built-up Index at a point.
-//
-// NDBI = (SWIR - NIR) / (SWIR + NIR)
+// WaterResult contains the result of water detection over a region.
+type WaterResult struct {
+	Area       float64            `json:"area"`        // Water-covered area in square meters
+	PixelCount int                `json:"pixel_count"` // Number of pixels classified as water
+	Scale      float64            `json:"scale"`       // Scale in meters used for the reduction
+	Threshold  float64            `json:"threshold"`   // NDWI threshold used for classification
+	Mask       *earthengine.Image `json:"-"`           // Water mask: 1 for water, masked elsewhere
+}
+
+// DetectWater classifies water over a region by thresholding NDWI and
+// estimates the water-covered area.
 //
-// NDBI is used to detect built-up (urban) areas.
-// Positive values indicate built-up areas.
+// Pixels with NDWI above the threshold (0 by default; see WaterThreshold) are
+// classified as water. The returned PixelCount and Scale allow the area to be
+// audited; Mask can be used for further analysis or export.
 //
 // Example:
 //
-//	ndbi, err := helpers.NDBI(client, 45.5152, -122.6784, "2023-06-01")
-func NDBI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+//	reservoir := helpers.Bounds{MinLon: -121.95, MinLat: 45.05, MaxLon: -121.85, MaxLat: 45.12}
+//	water, err := helpers.DetectWater(ctx, client, reservoir, "2023-08-15",
+//	    helpers.Sentinel2(),
+//	    helpers.ImageryWithScale(10),
+//	    helpers.WaterThreshold(0.1))
+//	fmt.Printf("Water area: %.1f ha\n", water.Area/10000)
+func DetectWater(ctx context.Context, client *earthengine.Client, bounds Bounds, date string, opts ...ImageryOption) (*WaterResult, error) {
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	threshold := 0.0
+	if cfg.waterThreshold != nil {
+		threshold = *cfg.waterThreshold
+	}
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Get the appropriate band names
+	greenBand, nirBand, err := getBandNamesForWater(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the query, filtered by date and cloud cover and clipped to the
+	// region before reducing
+	collection, err := filterImagery(client.ImageCollection(cfg.dataset), cfg, date)
+	if err != nil {
+		return nil, err
+	}
+	collection = clipCollection(collection, region)
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// Classify water: 1 where NDWI exceeds the threshold, 0 elsewhere
+	water := safeNormalizedDifference(combineImagery(collection.Select(greenBand, nirBand), cfg)).
+		Gt(threshold)
+
+	// Sum water pixel area and water pixel count in one reduction
+	stack := water.Multiply(water.PixelArea()).Rename("area").
+		AddBands(water.Rename("water"))
+
+	stats, err := stack.
+		ReduceRegion(region, earthengine.ReducerSum(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute water area: %w", err)
+	}
+
+	area, ok := stats["area"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected water area in result: %v", stats["area"])
+	}
+	count, ok := stats["water"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected water pixel count in result: %v", stats["water"])
+	}
+
+	return &WaterResult{
+		Area:       area,
+		PixelCount: int(math.Round(count)),
+		Scale:      scale,
+		Threshold:  threshold,
+		Mask:       water.UpdateMask(water),
+	}, nil
+}
+
+// Snow classification thresholds used by IsSnow.
+const (
+	// snowNDSIThreshold is the NDSI above which a pixel may be snow
+	snowNDSIThreshold = 0.4
+
+	// snowMinNIR is the minimum NIR reflectance for snow; water also has a
+	// high NDSI but absorbs strongly in the NIR
+	snowMinNIR = 0.11
+)
+
+// NDSI calculates the Normalized Difference Snow Index at a point.
+//
+// NDSI = (Green - SWIR1) / (Green + SWIR1)
+//
+// NDSI is used to map snow cover. Values above about 0.4 typically indicate
+// snow; see IsSnow. It is computed from surface reflectance, so Landsat scale
+// factors are applied first.
+//
+// Example:
+//
+//	ndsi, err := helpers.NDSI(client, 46.8523, -121.7603, "2023-02-15",
+//	    helpers.Sentinel2(),
+//	    helpers.CloudMask(20))
+func NDSI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
 	ctx := context.Background()
-	return NDBIWithContext(ctx, client, lat, lon, date, opts...)
+	return NDSIWithContext(ctx, client, lat, lon, date, opts...)
 }
 
-// NDBIWithContext is like NDBI but accepts a context.
-func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+// NDSIWithContext is like NDSI but accepts a context.
+func NDSIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	values, err := snowValues(ctx, client, lat, lon, date, opts...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute NDSI: %w", err)
+	}
+	return values["ndsi"], nil
+}
+
+// IsSnow reports whether a point is snow covered.
+//
+// A pixel is classified as snow when NDSI > 0.4 and NIR reflectance > 0.11.
+// The NIR test excludes water, which also has a high NDSI.
+//
+// Example:
+//
+//	snow, err := helpers.IsSnow(client, 46.8523, -121.7603, "2023-02-15")
+func IsSnow(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (bool, error) {
+	ctx := context.Background()
+	return IsSnowWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// IsSnowWithContext is like IsSnow but accepts a context.
+func IsSnowWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (bool, error) {
+	values, err := snowValues(ctx, client, lat, lon, date, opts...)
+	if err != nil {
+		return false, fmt.Errorf("failed to classify snow: %w", err)
+	}
+	return values["ndsi"] > snowNDSIThreshold && values["nir"] > snowMinNIR, nil
+}
+
+// snowValues samples NDSI and NIR reflectance at a point in one request.
+func snowValues(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (map[string]float64, error) {
 	if err := validateCoordinates(lat, lon); err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Apply options
@@ -485,13 +1157,23 @@ func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 	}
 
 	// Get the appropriate band names
-	swirBand, nirBand := getBandNamesForBuiltUp(cfg.dataset)
+	greenBand, swirBand, err := getBandNamesForSnow(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
+	nirBand, err := getNIRBand(cfg.dataset)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build the query
 	collection := client.ImageCollection(cfg.dataset)
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -499,15 +1181,432 @@ func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 
 	// Apply cloud filtering if specified
 	if cfg.cloudCover != nil {
-		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
+		property := cloudCoverProperty(cfg.dataset)
+		if property == "" {
+			property = "CLOUD_COVER"
+		}
+		collection = collection.FilterMetadata(property, "less_than", *cfg.cloudCover)
 	}
 
-	// Select SWIR and NIR bands, calculate NDBI using normalized difference
-	// NDBI = (SWIR - NIR) / (SWIR + NIR)
-	image := collection.
-		Select(swirBand, nirBand).
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// Convert to surface reflectance so NDSI and the NIR test are comparable
+	// across sensors
+	scale, offset := getReflectanceScaling(cfg.dataset)
+	reflectance := collection.
+		Select(greenBand, swirBand, nirBand).
 		Reduce(earthengine.ReducerMean()).
-		NormalizedDifference()
+		Rename("green", "swir", "nir").
+		Multiply(client.Constant(scale))
+	if offset != 0 {
+		reflectance = reflectance.Add(client.Constant(offset))
+	}
+
+	// NDSI = (Green - SWIR1) / (Green + SWIR1)
+	image := safeNormalizedDifference(reflectance.Select("green", "swir")).
+		Rename("ndsi").
+		AddBands(reflectance.Select("nir"))
+
+	// Determine scale
+	pixelScale := defaultImageryScale
+	if cfg.scale != nil {
+		pixelScale = *cfg.scale
+	}
+
+	// Sample at the point
+	result, err := image.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(pixelScale),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, 2)
+	for _, band := range []string{"ndsi", "nir"} {
+		value, ok := result[band].(float64)
+		if !ok {
+			return nil, fmt.Errorf("no %s value in result: %v", band, result)
+		}
+		values[band] = value
+	}
+
+	return values, nil
+}
+
+// LandSurfaceTemperature returns the land surface temperature at a point in °C.
+//
+// Supported datasets are Landsat 8/9 Collection 2 Level 2 (ST_B10, the
+// default) and MODIS daily LST (LST_Day_1km; use MODISLST). Other datasets
+// return an error. The default scale is the dataset's native resolution.
+//
+// Example:
+//
+//	// Urban heat island: downtown vs. park
+//	downtown, err := helpers.LandSurfaceTemperature(ctx, client, 45.5152, -122.6784, "2023-07-15",
+//	    helpers.DateRangeOption("2023-07-01", "2023-07-31"),
+//	    helpers.CloudMask(10))
+//	fmt.Printf("Downtown: %.1f°C\n", downtown)
+func LandSurfaceTemperature(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	thermal, ok := getThermalBand(cfg.dataset)
+	if !ok {
+		return 0, fmt.Errorf("dataset %s has no thermal band (use Landsat8, Landsat9, or MODISLST)", cfg.dataset)
+	}
+
+	// Build the query, filtered by date and cloud cover (MODIS LST is
+	// clear-sky only and has no scene cloud cover property)
+	collection, err := filterImagery(client.ImageCollection(cfg.dataset), cfg, date)
+	if err != nil {
+		return 0, err
+	}
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// Convert to °C: value*scale + offset gives Kelvin
+	image := combineImagery(collection.Select(thermal.band), cfg).
+		Multiply(client.Constant(thermal.scale)).
+		Add(client.Constant(thermal.offset - 273.15))
+
+	// Determine scale
+	scale := thermal.meters
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Sample at the point
+	result, err := image.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(scale),
+		).
+		ComputeFloat(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute land surface temperature: %w", err)
+	}
+
+	return result, nil
+}
+
+// NDBI calculates the Normalized Difference Built-up Index at a point.
+//
+// NDBI = (SWIR - NIR) / (SWIR + NIR)
+//
+// NDBI is used to detect built-up (urban) areas.
+// Positive values indicate built-up areas.
+//
+// Example:
+//
+//	ndbi, err := helpers.NDBI(client, 45.5152, -122.6784, "2023-06-01")
+func NDBI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return NDBIWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// NDBIWithContext is like NDBI but accepts a context.
+func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Get the appropriate band names
+	swirBand, nirBand, err := getBandNamesForBuiltUp(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// NDBI = (SWIR - NIR) / (SWIR + NIR)
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "NDBI", []string{swirBand, nirBand}, safeNormalizedDifference)
+}
+
+// NDMI calculates the Normalized Difference Moisture Index at a point.
+//
+// NDMI = (NIR - SWIR1) / (NIR + SWIR1)
+//
+// NDMI measures canopy water content and is used to monitor crop moisture
+// and schedule irrigation. Unlike NDWI, which uses the Green band to detect
+// open water, NDMI uses shortwave infrared. Higher values indicate wetter
+// vegetation; values below 0 suggest water stress.
+//
+// Example:
+//
+//	ndmi, err := helpers.NDMI(client, 41.59, -93.62, "2023-07-15", helpers.Sentinel2())
+func NDMI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return NDMIWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// NDMIWithContext is like NDMI but accepts a context.
+func NDMIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Get the appropriate band names
+	nirBand, swirBand, err := getBandNamesForMoisture(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// NDMI = (NIR - SWIR1) / (NIR + SWIR1)
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "NDMI", []string{nirBand, swirBand}, safeNormalizedDifference)
+}
+
+// CropStressCategory classifies crop water stress.
+type CropStressCategory string
+
+const (
+	// CropStressNotVegetated means NDVI is below 0.2, too sparse a canopy
+	// for NDMI to reflect crop moisture
+	CropStressNotVegetated CropStressCategory = "not vegetated"
+
+	// CropStressHigh is an NDMI below 0 over vegetation
+	CropStressHigh CropStressCategory = "high stress"
+
+	// CropStressModerate is an NDMI from 0 to 0.2 over vegetation
+	CropStressModerate CropStressCategory = "moderate stress"
+
+	// CropStressNone is an NDMI of 0.2 or above over vegetation
+	CropStressNone CropStressCategory = "no stress"
+)
+
+// CropWaterStressResult is the water stress of a crop at a point.
+type CropWaterStressResult struct {
+	NDVI     float64            `json:"ndvi"` // Greenness, used to tell crops from bare soil
+	NDMI     float64            `json:"ndmi"` // Canopy moisture
+	Category CropStressCategory `json:"category"`
+}
+
+// CropWaterStress estimates crop water stress at a point from NDVI and NDMI.
+//
+// NDVI first decides whether there is enough canopy to assess: below 0.2 the
+// point is CropStressNotVegetated, since bare soil has low NDMI regardless of
+// moisture. Over vegetation, NDMI below 0 is high stress, 0 to 0.2 is
+// moderate stress, and 0.2 or above is no stress.
+//
+// Both indices are computed from surface reflectance in one request, as by
+// VegetationIndices, so Landsat 8/9 and Sentinel-2 are supported.
+//
+// Example:
+//
+//	stress, err := helpers.CropWaterStress(client, 41.59, -93.62, "2023-07-15",
+//	    helpers.Sentinel2(),
+//	    helpers.DateRangeOption("2023-07-01", "2023-07-15"))
+//	if stress.Category == helpers.CropStressHigh {
+//	    fmt.Println("Irrigate")
+//	}
+func CropWaterStress(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (*CropWaterStressResult, error) {
+	ctx := context.Background()
+	return CropWaterStressWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// CropWaterStressWithContext is like CropWaterStress but accepts a context.
+func CropWaterStressWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (*CropWaterStressResult, error) {
+	indices, err := VegetationIndices(ctx, client, lat, lon, date, []string{"NDVI", "NDMI"}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute crop water stress: %w", err)
+	}
+
+	return &CropWaterStressResult{
+		NDVI:     indices["NDVI"],
+		NDMI:     indices["NDMI"],
+		Category: cropStressCategory(indices["NDVI"], indices["NDMI"]),
+	}, nil
+}
+
+// cropStressCategory classifies crop water stress from NDVI and NDMI.
+func cropStressCategory(ndvi, ndmi float64) CropStressCategory {
+	switch {
+	case ndvi < 0.2:
+		return CropStressNotVegetated
+	case ndmi < 0:
+		return CropStressHigh
+	case ndmi < 0.2:
+		return CropStressModerate
+	default:
+		return CropStressNone
+	}
+}
+
+// GNDVI calculates the Green Normalized Difference Vegetation Index at a point.
+//
+// GNDVI = (NIR - Green) / (NIR + Green)
+//
+// GNDVI is more sensitive to chlorophyll concentration than NDVI and
+// saturates later over dense canopies, so it is used to track crop nitrogen
+// status.
+//
+// Example:
+//
+//	gndvi, err := helpers.GNDVI(client, 41.59, -93.62, "2023-07-15", helpers.Sentinel2())
+func GNDVI(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return GNDVIWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// GNDVIWithContext is like GNDVI but accepts a context.
+func GNDVIWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	nirBand, greenBand, err := getBandNamesForGNDVI(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// GNDVI = (NIR - Green) / (NIR + Green)
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "GNDVI", []string{nirBand, greenBand}, safeNormalizedDifference)
+}
+
+// NDRE calculates the Normalized Difference Red Edge index at a point.
+//
+// NDRE = (NIR - RedEdge) / (NIR + RedEdge)
+//
+// NDRE tracks chlorophyll in mid- to late-season crops, when NDVI has
+// saturated. It needs a red-edge band, so it supports Sentinel-2 (B8 and B5)
+// and registered datasets that define BandRoles.RedEdge, but not Landsat.
+//
+// Example:
+//
+//	ndre, err := helpers.NDRE(client, 41.59, -93.62, "2023-07-15", helpers.Sentinel2())
+func NDRE(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return NDREWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// NDREWithContext is like NDRE but accepts a context.
+func NDREWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	redEdgeBand, ok := getRedEdgeBand(cfg.dataset)
+	if !ok {
+		return 0, fmt.Errorf("cannot compute NDRE: dataset %s lacks red-edge bands", cfg.dataset)
+	}
+	nirBand, err := getNIRBand(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// NDRE = (NIR - RedEdge) / (NIR + RedEdge)
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "NDRE", []string{nirBand, redEdgeBand}, safeNormalizedDifference)
+}
+
+// CIre calculates the red-edge Chlorophyll Index at a point.
+//
+// CIre = NIR / RedEdge - 1
+//
+// CIre is roughly linear in canopy chlorophyll content, unlike the
+// normalized indices, which makes it suited to estimating nitrogen
+// application rates. Like NDRE it needs a red-edge band, so Landsat is not
+// supported. Pixels with a zero red-edge value are masked.
+//
+// Example:
+//
+//	ci, err := helpers.CIre(client, 41.59, -93.62, "2023-07-15", helpers.Sentinel2())
+func CIre(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	ctx := context.Background()
+	return CIreWithContext(ctx, client, lat, lon, date, opts...)
+}
+
+// CIreWithContext is like CIre but accepts a context.
+func CIreWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	redEdgeBand, ok := getRedEdgeBand(cfg.dataset)
+	if !ok {
+		return 0, fmt.Errorf("cannot compute CIre: dataset %s lacks red-edge bands", cfg.dataset)
+	}
+	nirBand, err := getNIRBand(cfg.dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	// CIre = NIR / RedEdge - 1
+	return samplePointIndex(ctx, client, lat, lon, date, cfg, "CIre", []string{nirBand, redEdgeBand},
+		func(image *earthengine.Image) *earthengine.Image {
+			pair := image.Rename("nir", "red_edge")
+			return SafeDivide(pair, "nir", "red_edge").Subtract(client.Constant(1))
+		})
+}
+
+// samplePointIndex combines bands of the configured collection over the
+// date range, or the DateWindow around date, as NDVI does, applies index to
+// the combined image, and samples the resulting single-band image at a
+// point. name labels errors.
+func samplePointIndex(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, cfg *imageryConfig, name string, bands []string, index func(*earthengine.Image) *earthengine.Image) (float64, error) {
+	// Build the query, filtered by date and cloud cover
+	collection, err := filterImagery(client.ImageCollection(cfg.dataset), cfg, date)
+	if err != nil {
+		return 0, err
+	}
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	image := index(combineImagery(collection.Select(bands...), cfg))
 
 	// Determine scale
 	scale := defaultImageryScale
@@ -525,7 +1624,7 @@ func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 		ComputeFloat(ctx)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to compute NDBI: %w", err)
+		return 0, fmt.Errorf("failed to compute %s: %w", name, err)
 	}
 
 	return result, nil
@@ -533,25 +1632,30 @@ func NDBIWithContext(ctx context.Context, client *earthengine.Client, lat, lon f
 
 // SpectralBands returns the spectral band values at a point.
 //
-// Returns a map of band names to reflectance values.
+// Returns a map of band names to reflectance values, and a map reporting for
+// every band of the dataset whether it had a value. Bands that are masked at
+// the point (clouds, nodata, no images) are false in valid and absent from
+// values, so they cannot be mistaken for zero reflectance.
 // Band names depend on the satellite:
-//   - Landsat: B1 (Coastal), B2 (Blue), B3 (Green), B4 (Red), B5 (NIR), B6 (SWIR1), B7 (SWIR2)
-//   - Sentinel-2: B1-B12
+//   - Landsat: SR_B1 (Coastal), SR_B2 (Blue), SR_B3 (Green), SR_B4 (Red), SR_B5 (NIR), SR_B6 (SWIR1), SR_B7 (SWIR2)
+//   - Sentinel-2: B1-B12, including B8A
 //
 // Example:
 //
-//	bands, err := helpers.SpectralBands(client, 45.5152, -122.6784, "2023-06-01",
+//	bands, valid, err := helpers.SpectralBands(client, 45.5152, -122.6784, "2023-06-01",
 //	    helpers.Landsat8())
-//	fmt.Printf("Red: %.4f, NIR: %.4f\n", bands["B4"], bands["B5"])
-func SpectralBands(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (map[string]float64, error) {
+//	if valid["SR_B4"] && valid["SR_B5"] {
+//	    fmt.Printf("Red: %.4f, NIR: %.4f\n", bands["SR_B4"], bands["SR_B5"])
+//	}
+func SpectralBands(client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (values map[string]float64, valid map[string]bool, err error) {
 	ctx := context.Background()
 	return SpectralBandsWithContext(ctx, client, lat, lon, date, opts...)
 }
 
 // SpectralBandsWithContext is like SpectralBands but accepts a context.
-func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (map[string]float64, error) {
+func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (values map[string]float64, valid map[string]bool, err error) {
 	if err := validateCoordinates(lat, lon); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Apply options
@@ -575,7 +1679,7 @@ func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, l
 		// MODIS surface reflectance bands
 		bands = []string{"sur_refl_b01", "sur_refl_b02", "sur_refl_b03", "sur_refl_b04", "sur_refl_b05", "sur_refl_b06", "sur_refl_b07"}
 	default:
-		return nil, fmt.Errorf("unsupported dataset for spectral bands: %s", cfg.dataset)
+		return nil, nil, fmt.Errorf("unsupported dataset for spectral bands: %s", cfg.dataset)
 	}
 
 	// Build the query
@@ -583,6 +1687,9 @@ func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, l
 
 	// Apply date filtering
 	if cfg.dateRange != nil {
+		if err := cfg.dateRange.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("invalid date range: %w", err)
+		}
 		collection = collection.FilterDate(cfg.dateRange.Start, cfg.dateRange.End)
 	} else {
 		collection = collection.FilterDate(date, date)
@@ -593,6 +1700,9 @@ func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, l
 		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
 	}
 
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
 	// Get mean image
 	image := collection.Select(bands...).Reduce(earthengine.ReducerMean())
 
@@ -612,23 +1722,306 @@ func SpectralBandsWithContext(ctx context.Context, client *earthengine.Client, l
 		Compute(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute spectral bands: %w", err)
+		return nil, nil, fmt.Errorf("failed to compute spectral bands: %w", err)
 	}
 
-	// Convert to map[string]float64
-	bandValues := make(map[string]float64)
+	// Remove the "_mean" suffix added by Reduce. Masked bands come back as
+	// null or not at all.
+	values = make(map[string]float64, len(bands))
+	valid = make(map[string]bool, len(bands))
+	for _, band := range bands {
+		valid[band] = false
+	}
 	for key, value := range result {
-		if floatVal, ok := value.(float64); ok {
-			// Remove "_mean" suffix added by Reduce
-			bandName := key
-			if len(key) > 5 && key[len(key)-5:] == "_mean" {
-				bandName = key[:len(key)-5]
-			}
-			bandValues[bandName] = floatVal
+		floatVal, ok := value.(float64)
+		if !ok {
+			continue
+		}
+		band := strings.TrimSuffix(key, "_mean")
+		values[band] = floatVal
+		valid[band] = true
+	}
+
+	return values, valid, nil
+}
+
+// spectralIndex is an index computed from harmonized reflectance bands.
+type spectralIndex struct {
+	bands   []string // Names from harmonizedBands
+	compute func(r map[string]float64) float64
+}
+
+// spectralIndices are the indices supported by VegetationIndices.
+var spectralIndices = map[string]spectralIndex{
+	"NDVI": {[]string{"nir", "red"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["nir"], r["red"])
+	}},
+	"EVI": {[]string{"nir", "red", "blue"}, func(r map[string]float64) float64 {
+		return 2.5 * (r["nir"] - r["red"]) / (r["nir"] + 6*r["red"] - 7.5*r["blue"] + 1)
+	}},
+	"SAVI": {[]string{"nir", "red"}, func(r map[string]float64) float64 {
+		return 1.5 * (r["nir"] - r["red"]) / (r["nir"] + r["red"] + 0.5)
+	}},
+	"MSAVI": {[]string{"nir", "red"}, func(r map[string]float64) float64 {
+		return msavi2(r["nir"], r["red"])
+	}},
+	"NDWI": {[]string{"green", "nir"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["green"], r["nir"])
+	}},
+	"NDBI": {[]string{"swir1", "nir"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["swir1"], r["nir"])
+	}},
+	"NDMI": {[]string{"nir", "swir1"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["nir"], r["swir1"])
+	}},
+	"NBR": {[]string{"nir", "swir2"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["nir"], r["swir2"])
+	}},
+	"NDSI": {[]string{"green", "swir1"}, func(r map[string]float64) float64 {
+		return normalizedDifference(r["green"], r["swir1"])
+	}},
+}
+
+func normalizedDifference(a, b float64) float64 {
+	return (a - b) / (a + b)
+}
+
+// VegetationIndices computes several spectral indices at a point with a
+// single request.
+//
+// The bands needed by the requested indices are combined over the date range,
+// or the DateWindow around date, and sampled once, converted to surface reflectance, and the indices are
+// computed from them. Supported indices are "NDVI", "EVI", "SAVI" (L = 0.5),
+// "MSAVI", "NDWI", "NDBI", "NDMI", "NBR", and "NDSI". Any registered
+// dataset with the bands the indices need is supported, such as Landsat 8/9
+// and Sentinel-2, or MODIS for indices of its blue, red, and NIR bands.
+//
+// Because the indices are computed from reflectance, EVI and SAVI values can
+// differ slightly from the EVI and SAVI functions, which use raw band values.
+//
+// Example:
+//
+//	indices, err := helpers.VegetationIndices(ctx, client, 45.5152, -122.6784, "2023-06-01",
+//	    []string{"NDVI", "EVI", "NDWI", "NDBI"},
+//	    helpers.Sentinel2(),
+//	    helpers.DateRangeOption("2023-06-01", "2023-08-31"))
+//	fmt.Printf("NDVI: %.3f, NDWI: %.3f\n", indices["NDVI"], indices["NDWI"])
+func VegetationIndices(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, indices []string, opts ...ImageryOption) (map[string]float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("no indices requested")
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Collect the union of bands needed by the requested indices
+	needed := make(map[string]bool)
+	for _, name := range indices {
+		index, ok := spectralIndices[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported index %q", name)
+		}
+		for _, band := range index.bands {
+			needed[band] = true
+		}
+	}
+	if err := checkHarmonizedBands(cfg.dataset, needed); err != nil {
+		return nil, fmt.Errorf("unsupported dataset for vegetation indices: %w", err)
+	}
+
+	// Sample all bands at the point in one request
+	reflectance, err := pointReflectance(ctx, client, lat, lon, date, cfg, needed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute vegetation indices: %w", err)
+	}
+
+	values := make(map[string]float64, len(indices))
+	for _, name := range indices {
+		value := spectralIndices[name].compute(reflectance)
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("%s is undefined at point (%.4f, %.4f): zero denominator", name, lat, lon)
 		}
+		values[name] = value
 	}
 
-	return bandValues, nil
+	return values, nil
+}
+
+// tasseledCapCoefficients are the rows of a tasseled cap transformation,
+// with one coefficient per harmonizedBands band.
+type tasseledCapCoefficients struct {
+	brightness [6]float64
+	greenness  [6]float64
+	wetness    [6]float64
+}
+
+// landsatOLITasseledCap are the Landsat 8/9 OLI coefficients of Baig et al.
+// (2014).
+var landsatOLITasseledCap = tasseledCapCoefficients{
+	brightness: [6]float64{0.3029, 0.2786, 0.4733, 0.5599, 0.5080, 0.1872},
+	greenness:  [6]float64{-0.2941, -0.2430, -0.5424, 0.7276, 0.0713, -0.1608},
+	wetness:    [6]float64{0.1511, 0.1973, 0.3283, 0.3407, -0.7117, -0.4559},
+}
+
+// tasseledCapTables are the published tasseled cap coefficients by dataset.
+// Sentinel-2 uses the six-band coefficients of Shi and Xu (2019).
+var tasseledCapTables = map[string]tasseledCapCoefficients{
+	landsat8DatasetID: landsatOLITasseledCap,
+	landsat9DatasetID: landsatOLITasseledCap,
+	sentinel2DatasetID: {
+		brightness: [6]float64{0.3510, 0.3813, 0.3437, 0.7196, 0.2396, 0.1949},
+		greenness:  [6]float64{-0.3599, -0.3533, -0.4734, 0.6633, 0.0087, -0.2856},
+		wetness:    [6]float64{0.2578, 0.2305, 0.0883, 0.1071, -0.7611, -0.5308},
+	},
+}
+
+// TasseledCapResult holds the tasseled cap components at a point.
+type TasseledCapResult struct {
+	Brightness float64 `json:"brightness"` // Overall reflectance, high for bare soil and built-up areas
+	Greenness  float64 `json:"greenness"`  // Contrast of NIR with visible bands, high for green vegetation
+	Wetness    float64 `json:"wetness"`    // Contrast of visible and NIR with SWIR, high for moist soil and canopy
+}
+
+// TasseledCap applies the tasseled cap transformation at a point, returning
+// brightness, greenness, and wetness.
+//
+// The components are linear combinations of the blue, green, red, NIR,
+// SWIR1, and SWIR2 surface reflectance, averaged over the date range and
+// sampled in one request, using sensor-specific coefficients: Baig et al.
+// (2014) for Landsat 8/9 and Shi and Xu (2019) for Sentinel-2. Other datasets
+// have no published coefficients and return an error.
+//
+// Example:
+//
+//	tc, err := helpers.TasseledCap(ctx, client, 41.59, -93.62, "2023-05-20",
+//	    helpers.Sentinel2(),
+//	    helpers.DateRangeOption("2023-05-01", "2023-05-31"))
+//	fmt.Printf("Brightness %.3f, greenness %.3f, wetness %.3f\n",
+//	    tc.Brightness, tc.Greenness, tc.Wetness)
+func TasseledCap(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (*TasseledCapResult, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+
+	// Apply options
+	cfg := &imageryConfig{
+		dataset: landsat8DatasetID, // Default to Landsat 8
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	coefficients, ok := tasseledCapTables[cfg.dataset]
+	if !ok {
+		return nil, fmt.Errorf("no tasseled cap coefficients for dataset %s", cfg.dataset)
+	}
+
+	needed := make(map[string]bool, len(harmonizedBands))
+	for _, band := range harmonizedBands {
+		needed[band] = true
+	}
+	reflectance, err := pointReflectance(ctx, client, lat, lon, date, cfg, needed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute tasseled cap: %w", err)
+	}
+
+	return coefficients.apply(reflectance), nil
+}
+
+// apply computes the tasseled cap components from reflectance by harmonized
+// band name.
+func (c tasseledCapCoefficients) apply(reflectance map[string]float64) *TasseledCapResult {
+	result := &TasseledCapResult{}
+	for i, band := range harmonizedBands {
+		result.Brightness += c.brightness[i] * reflectance[band]
+		result.Greenness += c.greenness[i] * reflectance[band]
+		result.Wetness += c.wetness[i] * reflectance[band]
+	}
+	return result
+}
+
+// checkHarmonizedBands returns an error unless dataset is registered with a
+// band for each needed harmonized band (see harmonizedBands).
+func checkHarmonizedBands(dataset string, needed map[string]bool) error {
+	def, ok := LookupDataset(dataset)
+	if !ok {
+		return fmt.Errorf("%s is not registered", dataset)
+	}
+
+	roles := []string{def.Bands.Blue, def.Bands.Green, def.Bands.Red, def.Bands.NIR, def.Bands.SWIR1, def.Bands.SWIR2}
+	for i, band := range harmonizedBands {
+		if needed[band] && roles[i] == "" {
+			return fmt.Errorf("%s has no %s band", dataset, band)
+		}
+	}
+	return nil
+}
+
+// pointReflectance combines the needed harmonized bands (see
+// harmonizedBands) over the date range, or the DateWindow around date, as
+// NDVI does, and samples them at a point in one request, returning surface
+// reflectance by harmonized band name.
+func pointReflectance(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, cfg *imageryConfig, needed map[string]bool) (map[string]float64, error) {
+	datasetBands := getBandNamesForHarmonized(cfg.dataset)
+	var selected, renamed []string
+	for i, band := range harmonizedBands {
+		if needed[band] {
+			selected = append(selected, datasetBands[i])
+			renamed = append(renamed, band)
+		}
+	}
+
+	// Build the query, filtered by date and cloud cover
+	collection, err := filterImagery(client.ImageCollection(cfg.dataset), cfg, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
+	// Combine into one image with harmonized band names
+	image := combineImagery(collection.Select(selected...), cfg).Rename(renamed...)
+
+	// Determine scale
+	scale := defaultImageryScale
+	if cfg.scale != nil {
+		scale = *cfg.scale
+	}
+
+	// Sample all bands at the point in one request
+	result, err := image.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(scale),
+		).
+		Compute(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to reflectance
+	scaleFactor, offset := getReflectanceScaling(cfg.dataset)
+	reflectance := make(map[string]float64, len(renamed))
+	for _, band := range renamed {
+		value, ok := result[band].(float64)
+		if !ok {
+			return nil, fmt.Errorf("no %s value at point (%.4f, %.4f)", band, lat, lon)
+		}
+		reflectance[band] = value*scaleFactor + offset
+	}
+
+	return reflectance, nil
 }
 
 // CompositeMethod represents different compositing methods.
@@ -682,6 +2075,9 @@ func Composite(client *earthengine.Client, startDate, endDate string, method Com
 		collection = collection.FilterMetadata("CLOUD_COVER", "less_than", *cfg.cloudCover)
 	}
 
+	// Mask cloudy pixels using the dataset's quality band
+	collection = maskCollectionClouds(collection, cfg.dataset)
+
 	// Apply the compositing method
 	var composite *earthengine.Image
 	switch method {
@@ -694,7 +2090,9 @@ func Composite(client *earthengine.Client, startDate, endDate string, method Com
 	case GreenestPixelComposite:
 		// For greenest pixel, we need to calculate NDVI and use it for quality mosaic
 		// This is a simplified version - select based on max NDVI
-		nirBand, redBand := getBandNames(cfg.dataset)
+		// Composite cannot return an error; without NIR and Red bands the
+		// selection fails when the image is computed
+		nirBand, redBand, _ := getBandNames(cfg.dataset)
 		ndviCollection := collection.Select(nirBand, redBand)
 
 		// Calculate NDVI for each image and use max
@@ -753,3 +2151,72 @@ func NewEVIQuery(lat, lon float64, date string, opts ...ImageryOption) Query {
 func (q *EVIQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
 	return EVIWithContext(ctx, client, q.lat, q.lon, q.date, q.opts...)
 }
+
+// NDSIQuery represents a deferred NDSI query for batch operations.
+type NDSIQuery struct {
+	lat  float64
+	lon  float64
+	date string
+	opts []ImageryOption
+}
+
+// NewNDSIQuery creates a new NDSI query for batch execution.
+func NewNDSIQuery(lat, lon float64, date string, opts ...ImageryOption) Query {
+	return &NDSIQuery{
+		lat:  lat,
+		lon:  lon,
+		date: date,
+		opts: opts,
+	}
+}
+
+// Execute implements the Query interface.
+func (q *NDSIQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	return NDSIWithContext(ctx, client, q.lat, q.lon, q.date, q.opts...)
+}
+
+// NDMIQuery represents a deferred NDMI query for batch operations.
+type NDMIQuery struct {
+	lat  float64
+	lon  float64
+	date string
+	opts []ImageryOption
+}
+
+// NewNDMIQuery creates a new NDMI query for batch execution.
+func NewNDMIQuery(lat, lon float64, date string, opts ...ImageryOption) Query {
+	return &NDMIQuery{
+		lat:  lat,
+		lon:  lon,
+		date: date,
+		opts: opts,
+	}
+}
+
+// Execute implements the Query interface.
+func (q *NDMIQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	return NDMIWithContext(ctx, client, q.lat, q.lon, q.date, q.opts...)
+}
+
+// MSAVIQuery represents a deferred MSAVI query for batch operations.
+type MSAVIQuery struct {
+	lat  float64
+	lon  float64
+	date string
+	opts []ImageryOption
+}
+
+// NewMSAVIQuery creates a new MSAVI query for batch execution.
+func NewMSAVIQuery(lat, lon float64, date string, opts ...ImageryOption) Query {
+	return &MSAVIQuery{
+		lat:  lat,
+		lon:  lon,
+		date: date,
+		opts: opts,
+	}
+}
+
+// Execute implements the Query interface.
+func (q *MSAVIQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	return MSAVIWithContext(ctx, client, q.lat, q.lon, q.date, q.opts...)
+}
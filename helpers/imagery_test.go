@@ -1,7 +1,14 @@
 package helpers
 
 import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
 	"testing"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 func TestImageryOptions(t *testing.T) {
@@ -156,12 +163,40 @@ func TestImageryDatasetConstants(t *testing.T) {
 }
 
 func TestSpectralBandsRequiresValidCoordinates(t *testing.T) {
-	_, err := SpectralBands(nil, 100, -122, "2023-06-01")
+	_, _, err := SpectralBands(nil, 100, -122, "2023-06-01")
 	if err == nil {
 		t.Error("Expected error for invalid coordinates")
 	}
 }
 
+func TestSpectralBandsMaskedBands(t *testing.T) {
+	// B8 is masked at the point; B4 is present but zero
+	client, _ := newImageryTestClient(t, `{"result": {"B8A_mean": 0.31, "B4_mean": 0, "B8_mean": null}}`)
+
+	values, valid, err := SpectralBands(client, 45.5152, -122.6784, "2023-06-01", Sentinel2())
+	if err != nil {
+		t.Fatalf("SpectralBands failed: %v", err)
+	}
+
+	if values["B8A"] != 0.31 || !valid["B8A"] {
+		t.Errorf("B8A = %v (valid %v), want 0.31", values["B8A"], valid["B8A"])
+	}
+	if v, ok := values["B4"]; !ok || v != 0 || !valid["B4"] {
+		t.Errorf("B4 = %v (present %v, valid %v), want a valid zero", v, ok, valid["B4"])
+	}
+	if _, ok := values["B8"]; ok || valid["B8"] {
+		t.Error("masked B8 should be absent from values and invalid")
+	}
+
+	// Every band of the dataset is reported, including those not returned
+	if len(valid) != 12 {
+		t.Errorf("valid has %d bands, want 12: %v", len(valid), valid)
+	}
+	if isValid, ok := valid["B12"]; !ok || isValid {
+		t.Errorf("valid[B12] = %v (reported %v), want false", isValid, ok)
+	}
+}
+
 func TestNDVIRequiresValidCoordinates(t *testing.T) {
 	_, err := NDVI(nil, -95, 200, "2023-06-01")
 	if err == nil {
@@ -169,6 +204,66 @@ func TestNDVIRequiresValidCoordinates(t *testing.T) {
 	}
 }
 
+func TestNDVIDateWindow(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.62}}`)
+
+	ndvi, err := NDVI(client, 45.5, -122.6, "2023-06-01")
+	if err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+	if ndvi != 0.62 {
+		t.Errorf("NDVI = %v, want 0.62", ndvi)
+	}
+
+	// 30 days centered on the date, least cloudy scene on top
+	request := lastRequest()
+	for _, want := range []string{"2023-05-17", "2023-06-16", "CLOUD_COVER", "ImageCollection.mosaic"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+
+	if _, err := NDVI(client, 45.5, -122.6, "2023-06-01", DateWindow(10)); err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+	request = lastRequest()
+	if !strings.Contains(request, "2023-05-27") || !strings.Contains(request, "2023-06-06") {
+		t.Errorf("request does not use a 10-day window: %s", request)
+	}
+
+	// Scenes are filtered on the dataset's own cloud cover property
+	if _, err := NDVI(client, 45.5, -122.6, "2023-06-01", Sentinel2(), CloudMask(20)); err != nil {
+		t.Fatalf("NDVI failed: %v", err)
+	}
+	request = lastRequest()
+	if !strings.Contains(request, "CLOUDY_PIXEL_PERCENTAGE") || strings.Contains(request, "CLOUD_COVER") {
+		t.Error("Sentinel-2 request should filter on CLOUDY_PIXEL_PERCENTAGE, not CLOUD_COVER")
+	}
+}
+
+func TestNDVIDateWindowErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"nd": 0.5}}`)
+
+	if _, err := NDVI(client, 45.5, -122.6, "June 1"); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+	if _, err := NDVI(client, 45.5, -122.6, "2023-06-01", DateWindow(0)); err == nil {
+		t.Error("Expected error for empty window")
+	}
+}
+
+func TestNDVIRejectsInvalidDateRange(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.5}}`)
+
+	_, err := NDVI(client, 45.5, -122.6, "2023-06-01", DateRangeOption("2023-08-31", "2023-06-01"))
+	if err == nil {
+		t.Fatal("Expected error for end date before start date")
+	}
+	if lastRequest() != "" {
+		t.Error("invalid date range should fail before sending a request")
+	}
+}
+
 func TestEVIRequiresValidCoordinates(t *testing.T) {
 	_, err := EVI(nil, 91, -122, "2023-06-01")
 	if err == nil {
@@ -197,6 +292,799 @@ func TestNDBIRequiresValidCoordinates(t *testing.T) {
 	}
 }
 
+// checkSentinel2Window checks that request searches start to end (exclusive)
+// and filters scenes on Sentinel-2's cloud cover property.
+func checkSentinel2Window(t *testing.T, request, start, end string) {
+	t.Helper()
+	for _, want := range []string{`"` + start + `"`, `"` + end + `"`, "CLOUDY_PIXEL_PERCENTAGE"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+	if strings.Contains(request, "CLOUD_COVER") {
+		t.Error("request filters on Landsat's CLOUD_COVER property")
+	}
+}
+
+// newImageryTestClient returns a client whose server responds with response
+// and a function returning the last raw request body.
+func newImageryTestClient(t *testing.T, response string) (*earthengine.Client, func() string) {
+	t.Helper()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	})
+
+	return client, func() string {
+		requests := recorder.requests()
+		if len(requests) == 0 {
+			return ""
+		}
+		return requests[len(requests)-1]
+	}
+}
+
+func TestDetectWater(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"area": 250000.5, "water": 2500}}`)
+
+	bounds := Bounds{MinLon: -121.95, MinLat: 45.05, MaxLon: -121.85, MaxLat: 45.12}
+	result, err := DetectWater(context.Background(), client, bounds, "2023-08-15",
+		Sentinel2(), ImageryWithScale(10), WaterThreshold(0.1), CloudMask(20))
+	if err != nil {
+		t.Fatalf("DetectWater failed: %v", err)
+	}
+
+	if result.Area != 250000.5 {
+		t.Errorf("Area = %v, want 250000.5", result.Area)
+	}
+	if result.PixelCount != 2500 {
+		t.Errorf("PixelCount = %d, want 2500", result.PixelCount)
+	}
+	if result.Scale != 10 || result.Threshold != 0.1 {
+		t.Errorf("Scale = %v, Threshold = %v, want 10 and 0.1", result.Scale, result.Threshold)
+	}
+	if result.Mask == nil {
+		t.Error("Mask is nil")
+	}
+
+	request := lastRequest()
+	for _, want := range []string{
+		`["B3","B8"]`,
+		earthengine.AlgorithmImageGt,
+		earthengine.AlgorithmImagePixelArea,
+		earthengine.AlgorithmFilterIntersects,
+		`"constantValue":0.1`,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request missing %s", want)
+		}
+	}
+	checkSentinel2Window(t, request, "2023-07-31", "2023-08-30")
+}
+
+func TestDetectWaterInvalidBounds(t *testing.T) {
+	_, err := DetectWater(context.Background(), nil, Bounds{MinLon: 1, MaxLon: 0}, "2023-08-15")
+	if err == nil {
+		t.Error("Expected error for invalid bounds")
+	}
+}
+
+func TestWaterThreshold(t *testing.T) {
+	cfg := &imageryConfig{}
+	WaterThreshold(0.2)(cfg)
+
+	if cfg.waterThreshold == nil || *cfg.waterThreshold != 0.2 {
+		t.Errorf("waterThreshold = %v, want 0.2", cfg.waterThreshold)
+	}
+}
+
+func TestNDSI(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"ndsi": 0.65, "nir": 0.5}}`)
+
+	ndsi, err := NDSI(client, 46.8523, -121.7603, "2023-02-15", Sentinel2(), CloudMask(20))
+	if err != nil {
+		t.Fatalf("NDSI failed: %v", err)
+	}
+	if ndsi != 0.65 {
+		t.Errorf("NDSI = %v, want 0.65", ndsi)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{`["B3","B11","B8"]`, "CLOUDY_PIXEL_PERCENTAGE", `"constantValue":0.0001`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request missing %s", want)
+		}
+	}
+}
+
+func TestIsSnow(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     bool
+	}{
+		{"snow", `{"result": {"ndsi": 0.65, "nir": 0.5}}`, true},
+		{"low ndsi", `{"result": {"ndsi": 0.2, "nir": 0.5}}`, false},
+		{"water", `{"result": {"ndsi": 0.7, "nir": 0.02}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newImageryTestClient(t, tt.response)
+
+			snow, err := IsSnow(client, 46.8523, -121.7603, "2023-02-15")
+			if err != nil {
+				t.Fatalf("IsSnow failed: %v", err)
+			}
+			if snow != tt.want {
+				t.Errorf("IsSnow = %v, want %v", snow, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDSIMissingValue(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"ndsi": null, "nir": null}}`)
+
+	if _, err := NDSI(client, 46.8523, -121.7603, "2023-02-15"); err == nil {
+		t.Error("Expected error when no imagery is available")
+	}
+}
+
+func TestNDSIRequiresValidCoordinates(t *testing.T) {
+	_, err := NDSI(nil, 95, 0, "2023-02-15")
+	if err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+}
+
+func TestGetBandNamesForSnow(t *testing.T) {
+	tests := []struct {
+		dataset     string
+		green, swir string
+	}{
+		{landsat8DatasetID, "SR_B3", "SR_B6"},
+		{landsat9DatasetID, "SR_B3", "SR_B6"},
+		{sentinel2DatasetID, "B3", "B11"},
+	}
+
+	for _, tt := range tests {
+		green, swir, err := getBandNamesForSnow(tt.dataset)
+		if err != nil || green != tt.green || swir != tt.swir {
+			t.Errorf("getBandNamesForSnow(%s) = %s, %s, %v, want %s, %s", tt.dataset, green, swir, err, tt.green, tt.swir)
+		}
+	}
+}
+
+func TestNDSIQuery(t *testing.T) {
+	query := NewNDSIQuery(46.8523, -121.7603, "2023-02-15", Sentinel2())
+
+	ndsiQ, ok := query.(*NDSIQuery)
+	if !ok {
+		t.Fatal("NewNDSIQuery did not return *NDSIQuery")
+	}
+	if ndsiQ.lat != 46.8523 || ndsiQ.lon != -121.7603 || ndsiQ.date != "2023-02-15" || len(ndsiQ.opts) != 1 {
+		t.Errorf("NDSIQuery = %+v", ndsiQ)
+	}
+}
+
+func TestNDMI(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.25}}`)
+
+	ndmi, err := NDMI(client, 41.59, -93.62, "2023-07-15", Sentinel2())
+	if err != nil {
+		t.Fatalf("NDMI failed: %v", err)
+	}
+	if ndmi != 0.25 {
+		t.Errorf("NDMI = %v, want 0.25", ndmi)
+	}
+
+	// NIR and SWIR1, not NDWI's Green band
+	request := lastRequest()
+	nir, swir := strings.Index(request, `"B8"`), strings.Index(request, `"B11"`)
+	if nir < 0 || swir < 0 {
+		t.Fatalf("request does not select B8 and B11: %s", request)
+	}
+	if nir > swir {
+		t.Error("NDMI should select NIR before SWIR1")
+	}
+	if strings.Contains(request, `"B3"`) {
+		t.Error("NDMI should not use the Green band")
+	}
+
+	// A window around the date, filtered on Sentinel-2's cloud property
+	if _, err := NDMI(client, 41.59, -93.62, "2023-07-15", Sentinel2(), CloudMask(20)); err != nil {
+		t.Fatalf("NDMI with CloudMask failed: %v", err)
+	}
+	request = lastRequest()
+	checkSentinel2Window(t, request, "2023-06-30", "2023-07-30")
+}
+
+func TestNDMIRequiresValidCoordinates(t *testing.T) {
+	_, err := NDMI(nil, 100, -122, "2023-06-01")
+	if err == nil {
+		t.Error("Expected error for invalid coordinates")
+	}
+}
+
+func TestNDMIQuery(t *testing.T) {
+	query := NewNDMIQuery(41.59, -93.62, "2023-07-15", Sentinel2())
+
+	ndmiQ, ok := query.(*NDMIQuery)
+	if !ok {
+		t.Fatal("NewNDMIQuery did not return *NDMIQuery")
+	}
+	if ndmiQ.lat != 41.59 || ndmiQ.lon != -93.62 || ndmiQ.date != "2023-07-15" || len(ndmiQ.opts) != 1 {
+		t.Errorf("NDMIQuery = %+v", ndmiQ)
+	}
+}
+
+func TestCropWaterStress(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nir": 3000, "red": 1000, "swir1": 2500}}`)
+
+	stress, err := CropWaterStress(client, 41.59, -93.62, "2023-07-15", Sentinel2())
+	if err != nil {
+		t.Fatalf("CropWaterStress failed: %v", err)
+	}
+
+	if math.Abs(stress.NDVI-0.5) > 1e-9 {
+		t.Errorf("NDVI = %v, want 0.5", stress.NDVI)
+	}
+	if want := 500.0 / 5500; math.Abs(stress.NDMI-want) > 1e-9 {
+		t.Errorf("NDMI = %v, want %v", stress.NDMI, want)
+	}
+	if stress.Category != CropStressModerate {
+		t.Errorf("Category = %q, want %q", stress.Category, CropStressModerate)
+	}
+	if request := lastRequest(); !strings.Contains(request, `"B11"`) {
+		t.Error("request does not select SWIR1 band B11")
+	}
+}
+
+func TestCropStressCategory(t *testing.T) {
+	tests := []struct {
+		ndvi, ndmi float64
+		want       CropStressCategory
+	}{
+		{0.1, 0.3, CropStressNotVegetated},
+		{0.6, -0.1, CropStressHigh},
+		{0.6, 0, CropStressModerate},
+		{0.6, 0.15, CropStressModerate},
+		{0.6, 0.2, CropStressNone},
+		{0.2, 0.4, CropStressNone},
+	}
+	for _, tt := range tests {
+		if got := cropStressCategory(tt.ndvi, tt.ndmi); got != tt.want {
+			t.Errorf("cropStressCategory(%v, %v) = %q, want %q", tt.ndvi, tt.ndmi, got, tt.want)
+		}
+	}
+}
+
+func TestGNDVI(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": 0.6}}`)
+
+	gndvi, err := GNDVI(client, 41.59, -93.62, "2023-07-15", Sentinel2())
+	if err != nil {
+		t.Fatalf("GNDVI failed: %v", err)
+	}
+	if gndvi != 0.6 {
+		t.Errorf("GNDVI = %v, want 0.6", gndvi)
+	}
+
+	request := lastRequest()
+	for _, band := range []string{`"B8"`, `"B3"`} {
+		if !strings.Contains(request, band) {
+			t.Errorf("request does not select %s", band)
+		}
+	}
+	if strings.Contains(request, `"B4"`) {
+		t.Error("GNDVI should not use the Red band")
+	}
+}
+
+func TestGetRedEdgeBand(t *testing.T) {
+	if band, ok := getRedEdgeBand(sentinel2DatasetID); !ok || band != "B5" {
+		t.Errorf("Sentinel-2 red edge = %q, %v, want B5", band, ok)
+	}
+	for _, dataset := range []string{landsat8DatasetID, landsat9DatasetID, modisVIDatasetID, "projects/test/assets/unregistered"} {
+		if band, ok := getRedEdgeBand(dataset); ok {
+			t.Errorf("%s red edge = %q, want none", dataset, band)
+		}
+	}
+}
+
+func TestRedEdgeIndices(t *testing.T) {
+	ctx := context.Background()
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nir": 2.5}}`)
+
+	indices := map[string]PointQueryFunc{
+		"NDRE": NDREWithContext,
+		"CIre": CIreWithContext,
+	}
+	for name, index := range indices {
+		t.Run(name, func(t *testing.T) {
+			value, err := index(ctx, client, 41.59, -93.62, "2023-07-15", Sentinel2(), CloudMask(20))
+			if err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+			if value != 2.5 {
+				t.Errorf("%s = %v, want 2.5", name, value)
+			}
+
+			// A window around the date, filtered on Sentinel-2's cloud property
+			request := lastRequest()
+			checkSentinel2Window(t, request, "2023-06-30", "2023-07-30")
+			for _, band := range []string{`"B8"`, `"B5"`} {
+				if !strings.Contains(request, band) {
+					t.Errorf("request does not select %s", band)
+				}
+			}
+			if name == "CIre" {
+				for _, want := range []string{earthengine.AlgorithmImageDivide, earthengine.AlgorithmImageSubtract} {
+					if !strings.Contains(request, want) {
+						t.Errorf("request does not contain %q", want)
+					}
+				}
+			}
+
+			_, err = index(ctx, client, 41.59, -93.62, "2023-07-15", Landsat8())
+			if err == nil || !strings.Contains(err.Error(), "lacks red-edge bands") {
+				t.Errorf("Landsat error = %v, want lacks red-edge bands", err)
+			}
+		})
+	}
+}
+
+func TestMSAVI(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"constant": 0.42}}`)
+
+	msavi, err := MSAVI(client, 41.59, -93.62, "2023-05-20", Sentinel2(), CloudMask(20))
+	if err != nil {
+		t.Fatalf("MSAVI failed: %v", err)
+	}
+	if msavi != 0.42 {
+		t.Errorf("MSAVI = %v, want 0.42", msavi)
+	}
+
+	// Computed from reflectance with an expression that needs no L
+	request := lastRequest()
+	for _, want := range []string{earthengine.AlgorithmImageExpression, "sqrt((2*NIR + 1)**2 - 8*(NIR - RED))", `"B8"`, `"B4"`, earthengine.AlgorithmImageMultiply} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+
+	// A window around the date, filtered on Sentinel-2's cloud property
+	checkSentinel2Window(t, request, "2023-05-05", "2023-06-04")
+}
+
+func TestMSAVI2(t *testing.T) {
+	tests := []struct {
+		nir, red, want float64
+	}{
+		{0, 0, 0},
+		{0.3, 0.3, 0},
+		// Dense canopy
+		{0.5, 0.05, 0.6838},
+		// Sparse canopy over bright soil
+		{0.25, 0.15, 0.1479},
+	}
+	for _, tt := range tests {
+		if got := msavi2(tt.nir, tt.red); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("msavi2(%v, %v) = %.4f, want %.4f", tt.nir, tt.red, got, tt.want)
+		}
+	}
+}
+
+func TestMSAVIQuery(t *testing.T) {
+	query := NewMSAVIQuery(41.59, -93.62, "2023-05-20", Sentinel2())
+
+	msaviQ, ok := query.(*MSAVIQuery)
+	if !ok {
+		t.Fatal("NewMSAVIQuery did not return *MSAVIQuery")
+	}
+	if msaviQ.lat != 41.59 || msaviQ.lon != -93.62 || msaviQ.date != "2023-05-20" || len(msaviQ.opts) != 1 {
+		t.Errorf("MSAVIQuery = %+v", msaviQ)
+	}
+}
+
+func TestTasseledCap(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t,
+		`{"result": {"blue": 500, "green": 800, "red": 600, "nir": 3500, "swir1": 1800, "swir2": 900}}`)
+
+	tc, err := TasseledCap(context.Background(), client, 41.59, -93.62, "2023-07-15", Sentinel2())
+	if err != nil {
+		t.Fatalf("TasseledCap failed: %v", err)
+	}
+
+	// Sentinel-2 coefficients applied to reflectance (scale 0.0001)
+	want := TasseledCapResult{
+		Brightness: 0.3510*0.05 + 0.3813*0.08 + 0.3437*0.06 + 0.7196*0.35 + 0.2396*0.18 + 0.1949*0.09,
+		Greenness:  -0.3599*0.05 - 0.3533*0.08 - 0.4734*0.06 + 0.6633*0.35 + 0.0087*0.18 - 0.2856*0.09,
+		Wetness:    0.2578*0.05 + 0.2305*0.08 + 0.0883*0.06 + 0.1071*0.35 - 0.7611*0.18 - 0.5308*0.09,
+	}
+	if math.Abs(tc.Brightness-want.Brightness) > 1e-9 || math.Abs(tc.Greenness-want.Greenness) > 1e-9 || math.Abs(tc.Wetness-want.Wetness) > 1e-9 {
+		t.Errorf("TasseledCap = %+v, want %+v", *tc, want)
+	}
+	if tc.Greenness <= 0 {
+		t.Errorf("Greenness = %v, want positive over vegetation", tc.Greenness)
+	}
+
+	request := lastRequest()
+	for _, band := range []string{`"B2"`, `"B3"`, `"B4"`, `"B8"`, `"B11"`, `"B12"`} {
+		if !strings.Contains(request, band) {
+			t.Errorf("request does not select %s", band)
+		}
+	}
+}
+
+func TestTasseledCapCoefficientsBySensor(t *testing.T) {
+	reflectance := map[string]float64{"blue": 0.05, "green": 0.08, "red": 0.06, "nir": 0.35, "swir1": 0.18, "swir2": 0.09}
+
+	landsat := tasseledCapTables[landsat8DatasetID].apply(reflectance)
+	sentinel := tasseledCapTables[sentinel2DatasetID].apply(reflectance)
+	if *landsat == *sentinel {
+		t.Error("Landsat and Sentinel-2 should use different coefficients")
+	}
+	if tasseledCapTables[landsat9DatasetID] != tasseledCapTables[landsat8DatasetID] {
+		t.Error("Landsat 9 should use the Landsat 8 OLI coefficients")
+	}
+}
+
+func TestTasseledCapErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"blue": 500}}`)
+	ctx := context.Background()
+
+	if _, err := TasseledCap(ctx, client, 41.59, -93.62, "2023-07-15", MODIS()); err == nil || !strings.Contains(err.Error(), "no tasseled cap coefficients") {
+		t.Errorf("MODIS error = %v, want no tasseled cap coefficients", err)
+	}
+	if _, err := TasseledCap(ctx, client, 41.59, -93.62, "2023-07-15"); err == nil || !strings.Contains(err.Error(), "no green value") {
+		t.Errorf("masked band error = %v, want no green value", err)
+	}
+	if _, err := TasseledCap(ctx, client, 100, -93.62, "2023-07-15"); err == nil {
+		t.Error("Expected error for invalid coordinates")
+	}
+}
+
+func TestNDVIAnomalyImage(t *testing.T) {
+	client, _ := newCompositeTestServer(t, 3)
+	bounds := Bounds{MinLon: -94.0, MinLat: 41.3, MaxLon: -93.3, MaxLat: 41.9}
+
+	anomaly, err := NDVIAnomalyImage(context.Background(), client, bounds, "2023-07-15", 2020, 2023)
+	if err != nil {
+		t.Fatalf("NDVIAnomalyImage failed: %v", err)
+	}
+
+	data, err := anomaly.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	graph := string(data)
+
+	// The same 30-day window around July 15 in every year, leaving 2023 out
+	// of the baseline
+	for _, want := range []string{"2020-06-30", "2021-06-30", "2022-06-30", "2023-06-30",
+		earthengine.AlgorithmImageSubtract, earthengine.AlgorithmImageDivide, earthengine.AlgorithmReducerStdDev, `"NDVI_anomaly"`} {
+		if !strings.Contains(graph, want) {
+			t.Errorf("image does not contain %q", want)
+		}
+	}
+	if got := strings.Count(graph, `"2023-06-30"`); got != 1 {
+		t.Errorf("2023 window used %d times, want once (current year only)", got)
+	}
+
+	// Sentinel-2 scenes are filtered on their own cloud property
+	anomaly, err = NDVIAnomalyImage(context.Background(), client, bounds, "2023-07-15", 2020, 2023,
+		Sentinel2(), CloudMask(20))
+	if err != nil {
+		t.Fatalf("NDVIAnomalyImage with CloudMask failed: %v", err)
+	}
+	data, err = anomaly.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if graph := string(data); !strings.Contains(graph, "CLOUDY_PIXEL_PERCENTAGE") || strings.Contains(graph, "CLOUD_COVER") {
+		t.Error("image does not filter on CLOUDY_PIXEL_PERCENTAGE")
+	}
+}
+
+func TestNDVIAnomalyImageErrors(t *testing.T) {
+	ctx := context.Background()
+	bounds := Bounds{MinLon: -94.0, MinLat: 41.3, MaxLon: -93.3, MaxLat: 41.9}
+
+	empty, _ := newCompositeTestServer(t, 0)
+	if _, err := NDVIAnomalyImage(ctx, empty, bounds, "2023-07-15", 2013, 2022); !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages", err)
+	}
+
+	client, _ := newCompositeTestServer(t, 3)
+	tests := []struct {
+		name   string
+		bounds Bounds
+		date   string
+		start  int
+		end    int
+	}{
+		{"invalid bounds", Bounds{MinLon: 10, MaxLon: 0}, "2023-07-15", 2013, 2022},
+		{"invalid date", bounds, "July 15", 2013, 2022},
+		{"reversed baseline", bounds, "2023-07-15", 2022, 2013},
+		{"one baseline year", bounds, "2023-07-15", 2022, 2023},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NDVIAnomalyImage(ctx, client, tt.bounds, tt.date, tt.start, tt.end); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNDVIAnomaly(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": [
+		["id", "longitude", "latitude", "time", "NDVI"],
+		["a", -93.62, 41.59, 1686787200000, 0.5],
+		["b", -93.62, 41.59, 1623715200000, 0.7],
+		["c", -93.62, 41.59, 1655251200000, 0.8],
+		["d", -93.62, 41.59, 1655683200000, 0.9],
+		["e", -93.62, 41.59, 1655683200000, null]
+	]}`)
+
+	// 2020 has no imagery and is skipped
+	got, err := NDVIAnomaly(context.Background(), client, 41.59, -93.62, "2023-06-15", 2020, 2022)
+	if err != nil {
+		t.Fatalf("NDVIAnomaly failed: %v", err)
+	}
+
+	if got.Current != 0.5 {
+		t.Errorf("Current = %v, want 0.5", got.Current)
+	}
+	if got.BaselineYears != 2 || got.BaselineObservations != 3 {
+		t.Errorf("BaselineYears = %d, BaselineObservations = %d, want 2, 3", got.BaselineYears, got.BaselineObservations)
+	}
+	if math.Abs(got.BaselineMean-0.775) > 1e-9 {
+		t.Errorf("BaselineMean = %v, want 0.775", got.BaselineMean)
+	}
+	if math.Abs(got.Anomaly+0.275) > 1e-9 {
+		t.Errorf("Anomaly = %v, want -0.275", got.Anomaly)
+	}
+	if want := -0.275 / (0.075 * math.Sqrt2); math.Abs(got.ZScore-want) > 1e-9 {
+		t.Errorf("ZScore = %v, want %v", got.ZScore, want)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"2020-06-01", "2022-07-01", "2023-06-01", "ImageCollection.merge", "ImageCollection.getRegion"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+
+	// Sentinel-2 scenes are filtered on their own cloud property
+	if _, err := NDVIAnomaly(context.Background(), client, 41.59, -93.62, "2023-06-15", 2020, 2022,
+		Sentinel2(), CloudMask(20)); err != nil {
+		t.Fatalf("NDVIAnomaly with CloudMask failed: %v", err)
+	}
+	request = lastRequest()
+	if !strings.Contains(request, "CLOUDY_PIXEL_PERCENTAGE") || strings.Contains(request, "CLOUD_COVER") {
+		t.Error("request does not filter on CLOUDY_PIXEL_PERCENTAGE")
+	}
+}
+
+func TestNDVIAnomalyErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": [
+		["id", "longitude", "latitude", "time", "NDVI"],
+		["a", -93.62, 41.59, 1686787200000, 0.5],
+		["b", -93.62, 41.59, 1623715200000, 0.7]
+	]}`)
+	ctx := context.Background()
+
+	if _, err := NDVIAnomaly(ctx, client, 41.59, -93.62, "2023-06-15", 2020, 2022); err == nil || !strings.Contains(err.Error(), "baseline years") {
+		t.Errorf("single baseline year: error = %v, want baseline years error", err)
+	}
+	if _, err := NDVIAnomaly(ctx, client, 41.59, -93.62, "2024-06-15", 2020, 2022); err == nil || !strings.Contains(err.Error(), "no NDVI observations") {
+		t.Errorf("missing current month: error = %v, want no observations error", err)
+	}
+	if _, err := NDVIAnomaly(ctx, client, 41.59, -93.62, "June 2023", 2020, 2022); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+	if _, err := NDVIAnomaly(ctx, client, 41.59, -93.62, "2023-06-15", 2022, 2020); err == nil {
+		t.Error("Expected error for reversed baseline")
+	}
+}
+
+func TestVegetationIndices(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nir": 3000, "red": 1000, "green": 1500, "swir1": 2000}}`)
+
+	got, err := VegetationIndices(context.Background(), client, 45.5152, -122.6784, "2023-06-01",
+		[]string{"NDVI", "SAVI", "NDWI", "NDBI", "NDMI", "NDSI"}, Sentinel2())
+	if err != nil {
+		t.Fatalf("VegetationIndices failed: %v", err)
+	}
+
+	want := map[string]float64{"NDVI": 0.5, "SAVI": 1.0 / 3, "NDWI": -1.0 / 3, "NDBI": -0.2, "NDMI": 0.2, "NDSI": -1.0 / 7}
+	if len(got) != len(want) {
+		t.Errorf("got %d indices, want %d: %v", len(got), len(want), got)
+	}
+	for name, w := range want {
+		if math.Abs(got[name]-w) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got[name], w)
+		}
+	}
+
+	// Only the needed bands are selected, in one request
+	request := lastRequest()
+	for _, band := range []string{`"B8"`, `"B4"`, `"B3"`, `"B11"`} {
+		if !strings.Contains(request, band) {
+			t.Errorf("request does not select %s", band)
+		}
+	}
+	if strings.Contains(request, `"B12"`) {
+		t.Error("request selects unneeded band B12")
+	}
+
+	// A window around the date, filtered on Sentinel-2's cloud property
+	if _, err := VegetationIndices(context.Background(), client, 45.5152, -122.6784, "2023-06-01",
+		[]string{"NDVI"}, Sentinel2(), CloudMask(20)); err != nil {
+		t.Fatalf("VegetationIndices with CloudMask failed: %v", err)
+	}
+	checkSentinel2Window(t, lastRequest(), "2023-05-17", "2023-06-16")
+
+	// Registered datasets with the needed bands are supported
+	if _, err := VegetationIndices(context.Background(), client, 45.5152, -122.6784, "2023-06-01",
+		[]string{"NDVI"}, MODIS()); err != nil {
+		t.Errorf("VegetationIndices with MODIS failed: %v", err)
+	}
+}
+
+func TestVegetationIndicesErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"nir": 3000}}`)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		indices []string
+		opts    []ImageryOption
+		want    string
+	}{
+		{"no indices", nil, nil, "no indices"},
+		{"unknown index", []string{"NDVI", "GNDVI"}, nil, `unsupported index "GNDVI"`},
+		{"unregistered dataset", []string{"NDVI"}, []ImageryOption{CustomDataset("projects/p/assets/unregistered")}, "unsupported dataset"},
+		{"missing band", []string{"NDVI", "NDMI"}, []ImageryOption{MODIS()}, "no swir1 band"},
+		{"masked band", []string{"NDVI"}, nil, "no red value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VegetationIndices(ctx, client, 45.5152, -122.6784, "2023-06-01", tt.indices, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVegetationIndicesZeroDenominator(t *testing.T) {
+	// Zero reflectance in both bands of a normalized difference
+	client, _ := newImageryTestClient(t, `{"result": {"nir": 0, "red": 0}}`)
+
+	_, err := VegetationIndices(context.Background(), client, 45.5152, -122.6784, "2023-06-01",
+		[]string{"NDVI"}, Sentinel2())
+	if err == nil || !strings.Contains(err.Error(), "zero denominator") {
+		t.Errorf("error = %v, want zero denominator error", err)
+	}
+}
+
+func TestNormalizedDifferenceIndicesMaskZeroDenominator(t *testing.T) {
+	ctx := context.Background()
+	// A zero-denominator pixel is masked, so the point reduces to null
+	client, lastRequest := newImageryTestClient(t, `{"result": {"nd": null}}`)
+
+	indices := map[string]func() (float64, error){
+		"NDVI": func() (float64, error) { return NDVI(client, 45.5, -122.6, "2023-06-01") },
+		"NDWI": func() (float64, error) { return NDWI(client, 45.5, -122.6, "2023-06-01") },
+		"NDBI": func() (float64, error) { return NDBI(client, 45.5, -122.6, "2023-06-01") },
+		"NBR":  func() (float64, error) { return BurnSeverityWithContext(ctx, client, 45.5, -122.6, "2023-06-01") },
+	}
+	for name, index := range indices {
+		t.Run(name, func(t *testing.T) {
+			if value, err := index(); err == nil {
+				t.Errorf("%s = %v over a masked pixel, want error", name, value)
+			}
+			request := lastRequest()
+			for _, want := range []string{earthengine.AlgorithmImageNormalizedDiff, earthengine.AlgorithmImageNeq, earthengine.AlgorithmImageUpdateMask} {
+				if !strings.Contains(request, want) {
+					t.Errorf("request does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestSafeDivide(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"B4": 0.25}}`)
+
+	ratio := SafeDivide(client.Image("projects/test/assets/image"), "B4", "B8")
+	value, err := ratio.
+		ReduceRegion(earthengine.NewPoint(-122.6, 45.5), earthengine.ReducerFirst()).
+		ComputeFloat(context.Background())
+	if err != nil {
+		t.Fatalf("ComputeFloat failed: %v", err)
+	}
+	if value != 0.25 {
+		t.Errorf("ratio = %v, want 0.25", value)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{`"B4"`, `"B8"`, earthengine.AlgorithmImageDivide, earthengine.AlgorithmImageNeq, earthengine.AlgorithmImageUpdateMask} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestLandSurfaceTemperature(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []ImageryOption
+		wantBand  string
+		wantScale string
+	}{
+		{"landsat", nil, `["ST_B10"]`, `"constantValue":0.00341802`},
+		{"modis", []ImageryOption{MODISLST()}, `["LST_Day_1km"]`, `"constantValue":0.02`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, lastRequest := newImageryTestClient(t, `{"result": {"value": 31.5}}`)
+
+			lst, err := LandSurfaceTemperature(context.Background(), client, 45.5152, -122.6784, "2023-07-15", tt.opts...)
+			if err != nil {
+				t.Fatalf("LandSurfaceTemperature failed: %v", err)
+			}
+			if lst != 31.5 {
+				t.Errorf("LandSurfaceTemperature = %v, want 31.5", lst)
+			}
+
+			// A single date searches the window around it
+			request := lastRequest()
+			for _, want := range []string{tt.wantBand, tt.wantScale, `"2023-06-30"`, `"2023-07-30"`} {
+				if !strings.Contains(request, want) {
+					t.Errorf("request missing %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestLandSurfaceTemperatureUnsupportedDataset(t *testing.T) {
+	for _, opt := range []ImageryOption{Sentinel2(), MODIS()} {
+		_, err := LandSurfaceTemperature(context.Background(), nil, 45.5152, -122.6784, "2023-07-15", opt)
+		if err == nil || !strings.Contains(err.Error(), "no thermal band") {
+			t.Errorf("err = %v, want no thermal band error", err)
+		}
+	}
+}
+
+func TestGetThermalBand(t *testing.T) {
+	landsat, ok := getThermalBand(landsat9DatasetID)
+	if !ok {
+		t.Fatal("Landsat 9 should have a thermal band")
+	}
+	// DN 44177 is about 300 K
+	if kelvin := 44177*landsat.scale + landsat.offset; math.Abs(kelvin-300) > 0.1 {
+		t.Errorf("Landsat 44177 = %.2f K, want about 300 K", kelvin)
+	}
+
+	modis, ok := getThermalBand(modisLSTDatasetID)
+	if !ok {
+		t.Fatal("MODIS LST should have a thermal band")
+	}
+	if kelvin := 15000*modis.scale + modis.offset; kelvin != 300 {
+		t.Errorf("MODIS 15000 = %.2f K, want 300 K", kelvin)
+	}
+}
+
 func ExampleNDVI() {
 	// Example showing NDVI calculation
 	// client, _ := earthengine.NewClient(...)
@@ -218,9 +1106,11 @@ func ExampleEVI() {
 func ExampleSpectralBands() {
 	// Example showing spectral band retrieval
 	// client, _ := earthengine.NewClient(...)
-	// bands, err := SpectralBands(client, 45.5152, -122.6784, "2023-06-01",
+	// bands, valid, err := SpectralBands(client, 45.5152, -122.6784, "2023-06-01",
 	//     Landsat8())
-	// fmt.Printf("Red: %.4f, NIR: %.4f\n", bands["B4"], bands["B5"])
+	// if valid["SR_B4"] && valid["SR_B5"] {
+	//     fmt.Printf("Red: %.4f, NIR: %.4f\n", bands["SR_B4"], bands["SR_B5"])
+	// }
 }
 
 func ExampleComposite() {
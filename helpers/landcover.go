@@ -3,6 +3,7 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -33,6 +34,9 @@ const (
 	defaultLandCoverScale = 30.0
 )
 
+// nlcdLandCoverYears are the years with an NLCD land cover release, in order.
+var nlcdLandCoverYears = []int{2001, 2004, 2006, 2008, 2011, 2013, 2016, 2019, 2021, 2023}
+
 // TreeCoverageOption configures tree coverage queries.
 type TreeCoverageOption func(*treeCoverageConfig)
 
@@ -187,9 +191,43 @@ func LandCoverClass(client *earthengine.Client, lat, lon float64) (string, error
 
 // LandCoverClassWithContext is like LandCoverClass but accepts a context.
 func LandCoverClassWithContext(ctx context.Context, client *earthengine.Client, lat, lon float64) (string, error) {
-	if err := validateCoordinates(lat, lon); err != nil {
+	class, err := LandCoverClassDetailed(ctx, client, lat, lon)
+	if err != nil {
 		return "", err
 	}
+	return class.Name, nil
+}
+
+// LandCoverClassInfo describes the land cover class at a point.
+type LandCoverClassInfo struct {
+	Code int    `json:"code"` // Numeric NLCD class (for example 42)
+	Name string `json:"name"` // Class name, as returned by LandCoverClass (for example "forest_evergreen")
+
+	// Confidence is the classification confidence (0-1) where the dataset
+	// provides one. NLCD does not, so it is nil.
+	Confidence *float64 `json:"confidence"`
+}
+
+// IsDeveloped reports whether the class is one of the NLCD developed classes
+// (21-24: open space through high intensity).
+func (c LandCoverClassInfo) IsDeveloped() bool {
+	return c.Code >= 21 && c.Code <= 24
+}
+
+// LandCoverClassDetailed returns the land cover class at the specified point
+// with its numeric code, for callers that branch on the stable NLCD code
+// rather than the name. See LandCoverClass for the classes.
+//
+// Example:
+//
+//	class, err := helpers.LandCoverClassDetailed(ctx, client, 45.5152, -122.6784)
+//	if class.Code == 42 {
+//	    fmt.Println("Evergreen forest")
+//	}
+func LandCoverClassDetailed(ctx context.Context, client *earthengine.Client, lat, lon float64) (*LandCoverClassInfo, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
 
 	// Get the numeric class value
 	result, err := client.ImageCollection(nlcdLandCoverDatasetID).
@@ -203,11 +241,14 @@ func LandCoverClassWithContext(ctx context.Context, client *earthengine.Client,
 		ComputeFloat(ctx)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get land cover class: %w", err)
+		return nil, fmt.Errorf("failed to get land cover class: %w", err)
 	}
 
-	// Convert numeric value to class name
-	return nlcdClassToName(int(result)), nil
+	code := int(result)
+	return &LandCoverClassInfo{
+		Code: code,
+		Name: nlcdClassToName(code),
+	}, nil
 }
 
 // nlcdClassToName converts NLCD numeric class to human-readable name.
@@ -237,6 +278,123 @@ func nlcdClassToName(class int) string {
 	return fmt.Sprintf("unknown_%d", class)
 }
 
+// LandCoverChangeResult describes the NLCD land cover at a point in two years.
+type LandCoverChangeResult struct {
+	FromYear  int    `json:"from_year"`  // NLCD release year used for yearFrom
+	ToYear    int    `json:"to_year"`    // NLCD release year used for yearTo
+	FromClass string `json:"from_class"` // Land cover class in FromYear (see LandCoverClass)
+	ToClass   string `json:"to_class"`   // Land cover class in ToYear
+	FromCode  int    `json:"from_code"`  // Numeric NLCD class in FromYear
+	ToCode    int    `json:"to_code"`    // Numeric NLCD class in ToYear
+	Changed   bool   `json:"changed"`    // Whether the class differs between the two years
+	Note      string `json:"note"`       // Explains any snapping of the requested years; empty otherwise
+}
+
+// LandCoverChange compares the NLCD land cover class at a point between two
+// years, for example to find new development.
+//
+// NLCD is not released every year. Requested years without a release are
+// snapped to the nearest release year (2001, 2004, 2006, 2008, 2011, 2013,
+// 2016, 2019, 2021, or 2023), which is reported in FromYear, ToYear, and Note.
+// It is an error if both years snap to the same release.
+//
+// Example:
+//
+//	change, err := helpers.LandCoverChange(ctx, client, 45.5152, -122.6784, 2001, 2021)
+//	if change.Changed {
+//	    fmt.Printf("%s (%d) -> %s (%d)\n", change.FromClass, change.FromYear, change.ToClass, change.ToYear)
+//	}
+func LandCoverChange(ctx context.Context, client *earthengine.Client, lat, lon float64, yearFrom, yearTo int) (*LandCoverChangeResult, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	if yearFrom >= yearTo {
+		return nil, fmt.Errorf("yearFrom (%d) must be before yearTo (%d)", yearFrom, yearTo)
+	}
+
+	fromYear, fromNote, err := nlcdReleaseYear(yearFrom)
+	if err != nil {
+		return nil, err
+	}
+	toYear, toNote, err := nlcdReleaseYear(yearTo)
+	if err != nil {
+		return nil, err
+	}
+	if fromYear == toYear {
+		return nil, fmt.Errorf("years %d and %d both map to the NLCD %d release", yearFrom, yearTo, fromYear)
+	}
+
+	// Select each release by date rather than mosaicking to the latest
+	collection := client.ImageCollection(nlcdLandCoverDatasetID)
+	release := func(year int) *earthengine.Image {
+		return collection.
+			FilterDate(fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-01-01", year+1)).
+			First().
+			Select(nlcdLandCoverBand)
+	}
+	stack := release(fromYear).Rename("from").AddBands(release(toYear).Rename("to"))
+
+	values, err := stack.
+		ReduceRegion(
+			earthengine.NewPoint(lon, lat),
+			earthengine.ReducerFirst(),
+			earthengine.Scale(defaultLandCoverScale),
+		).
+		Compute(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get land cover change: %w", err)
+	}
+
+	from, ok := values["from"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no NLCD %d land cover at point (%.4f, %.4f)", fromYear, lat, lon)
+	}
+	to, ok := values["to"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("no NLCD %d land cover at point (%.4f, %.4f)", toYear, lat, lon)
+	}
+
+	var notes []string
+	for _, note := range []string{fromNote, toNote} {
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	return &LandCoverChangeResult{
+		FromYear:  fromYear,
+		ToYear:    toYear,
+		FromClass: nlcdClassToName(int(from)),
+		ToClass:   nlcdClassToName(int(to)),
+		FromCode:  int(from),
+		ToCode:    int(to),
+		Changed:   int(from) != int(to),
+		Note:      strings.Join(notes, "; "),
+	}, nil
+}
+
+// nlcdReleaseYear returns the NLCD release year nearest to year, preferring
+// the earlier release on ties, and a note if it differs from year.
+func nlcdReleaseYear(year int) (int, string, error) {
+	first, last := nlcdLandCoverYears[0], nlcdLandCoverYears[len(nlcdLandCoverYears)-1]
+	if year < first-2 || year > last+2 {
+		return 0, "", fmt.Errorf("year %d is outside NLCD land cover coverage (%d-%d)", year, first, last)
+	}
+
+	nearest := first
+	for _, release := range nlcdLandCoverYears {
+		if absInt(release-year) < absInt(nearest-year) {
+			nearest = release
+		}
+	}
+
+	if nearest == year {
+		return year, "", nil
+	}
+	return nearest, fmt.Sprintf("no NLCD release for %d; using %d", year, nearest), nil
+}
+
 // ImperviousSurface returns the impervious surface percentage at the specified point.
 //
 // Impervious surface represents constructed surfaces like roads, buildings, and parking lots
@@ -303,20 +461,13 @@ func IsUrbanWithContext(ctx context.Context, client *earthengine.Client, lat, lo
 	}
 
 	// Get land cover class
-	class, err := LandCoverClassWithContext(ctx, client, lat, lon)
+	class, err := LandCoverClassDetailed(ctx, client, lat, lon)
 	if err != nil {
 		return false, err
 	}
 
 	// Check if it's a developed class
-	developedClasses := map[string]bool{
-		"developed_open":   true,
-		"developed_low":    true,
-		"developed_medium": true,
-		"developed_high":   true,
-	}
-
-	if developedClasses[class] {
+	if class.IsDeveloped() {
 		return true, nil
 	}
 
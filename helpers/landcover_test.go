@@ -1,7 +1,11 @@
 package helpers
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 func TestValidateCoordinates(t *testing.T) {
@@ -195,3 +199,115 @@ func ExampleIsUrban() {
 	//     fmt.Println("This is not an urban area")
 	// }
 }
+
+func TestNLCDReleaseYear(t *testing.T) {
+	tests := []struct {
+		year     int
+		want     int
+		wantNote bool
+		wantErr  bool
+	}{
+		{2001, 2001, false, false},
+		{2019, 2019, false, false},
+		{2010, 2011, true, false},
+		{2005, 2004, true, false}, // tie between 2004 and 2006
+		{2024, 2023, true, false},
+		{1999, 2001, true, false},
+		{1990, 0, false, true},
+		{2030, 0, false, true},
+	}
+
+	for _, tt := range tests {
+		got, note, err := nlcdReleaseYear(tt.year)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("nlcdReleaseYear(%d) error = %v, wantErr %v", tt.year, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want || (note != "") != tt.wantNote {
+			t.Errorf("nlcdReleaseYear(%d) = %d, %q, want %d (note: %v)", tt.year, got, note, tt.want, tt.wantNote)
+		}
+	}
+}
+
+func TestLandCoverChange(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"from": 81, "to": 23}}`)
+
+	result, err := LandCoverChange(context.Background(), client, 45.5152, -122.6784, 2001, 2020)
+	if err != nil {
+		t.Fatalf("LandCoverChange failed: %v", err)
+	}
+
+	if result.FromClass != "pasture" || result.ToClass != "developed_medium" || !result.Changed {
+		t.Errorf("result = %+v, want pasture -> developed_medium", result)
+	}
+	if result.FromYear != 2001 || result.ToYear != 2019 {
+		t.Errorf("years = %d, %d, want 2001, 2019", result.FromYear, result.ToYear)
+	}
+	if !strings.Contains(result.Note, "2020") {
+		t.Errorf("Note = %q, want a note about 2020", result.Note)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"2001-01-01", "2019-01-01", "2020-01-01", earthengine.AlgorithmImageCollectionFirst} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+	if strings.Contains(request, earthengine.AlgorithmImageCollectionMosaic) {
+		t.Error("request mosaics the collection")
+	}
+}
+
+func TestLandCoverChangeErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newImageryTestClient(t, `{"result": {"from": null, "to": 23}}`)
+
+	if _, err := LandCoverChange(ctx, client, 45.5, -122.6, 2001, 2021); err == nil {
+		t.Error("Expected error for missing land cover")
+	}
+	if _, err := LandCoverChange(ctx, client, 45.5, -122.6, 2021, 2001); err == nil {
+		t.Error("Expected error for years out of order")
+	}
+	if _, err := LandCoverChange(ctx, client, 45.5, -122.6, 2023, 2024); err == nil || !strings.Contains(err.Error(), "both") {
+		t.Errorf("error = %v, want both years mapping to one release", err)
+	}
+	if _, err := LandCoverChange(ctx, client, 45.5, -122.6, 1980, 2021); err == nil {
+		t.Error("Expected error for year before NLCD")
+	}
+	if _, err := LandCoverChange(ctx, client, 95, -122.6, 2001, 2021); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+}
+
+func TestLandCoverClassDetailed(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"landcover": 23}}`)
+
+	class, err := LandCoverClassDetailed(context.Background(), client, 45.5152, -122.6784)
+	if err != nil {
+		t.Fatalf("LandCoverClassDetailed failed: %v", err)
+	}
+	if class.Code != 23 || class.Name != "developed_medium" || class.Confidence != nil {
+		t.Errorf("class = %+v, want code 23, developed_medium, no confidence", class)
+	}
+	if !class.IsDeveloped() {
+		t.Error("IsDeveloped() = false for class 23")
+	}
+
+	name, err := LandCoverClassWithContext(context.Background(), client, 45.5152, -122.6784)
+	if err != nil || name != "developed_medium" {
+		t.Errorf("LandCoverClassWithContext = %q, %v, want developed_medium", name, err)
+	}
+
+	urban, err := IsUrbanWithContext(context.Background(), client, 45.5152, -122.6784)
+	if err != nil || !urban {
+		t.Errorf("IsUrbanWithContext = %v, %v, want true", urban, err)
+	}
+}
+
+func TestLandCoverClassInfoIsDeveloped(t *testing.T) {
+	for code, want := range map[int]bool{11: false, 21: true, 24: true, 31: false, 82: false} {
+		if got := (LandCoverClassInfo{Code: code}).IsDeveloped(); got != want {
+			t.Errorf("IsDeveloped(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// maxPatchPixels is the largest patch, in pixels, that Earth Engine's
+// connectedComponents can label.
+const maxPatchPixels = 1024
+
+// LandscapeOption configures LandscapeMetrics.
+type LandscapeOption func(*landscapeConfig)
+
+type landscapeConfig struct {
+	noData        *int
+	fourConnected bool
+}
+
+// NoDataClass sets a class value that marks missing data. Its pixels are
+// excluded like masked pixels: they are not counted as a patch, in the
+// landscape area, or as an edge.
+func NoDataClass(value int) LandscapeOption {
+	return func(cfg *landscapeConfig) {
+		cfg.noData = &value
+	}
+}
+
+// FourConnected treats pixels as part of the same patch only when they share
+// an edge. By default diagonal neighbors are connected too (the 8-neighbor
+// rule used by FRAGSTATS).
+func FourConnected() LandscapeOption {
+	return func(cfg *landscapeConfig) {
+		cfg.fourConnected = true
+	}
+}
+
+// ClassMetrics holds the pattern metrics of one class.
+type ClassMetrics struct {
+	Class             int     `json:"class"`
+	Area              float64 `json:"area"`       // Square meters
+	Proportion        float64 `json:"proportion"` // Percent of the landscape area
+	PatchCount        int     `json:"patch_count"`
+	LargestPatchArea  float64 `json:"largest_patch_area"`  // Square meters
+	LargestPatchIndex float64 `json:"largest_patch_index"` // Largest patch as a percent of the landscape area
+}
+
+// LandscapeResult holds landscape pattern metrics for a classified image.
+type LandscapeResult struct {
+	Classes           []ClassMetrics `json:"classes"`             // Sorted by class value
+	TotalArea         float64        `json:"total_area"`          // Square meters of valid (not no-data) pixels
+	PatchCount        int            `json:"patch_count"`         // Patches of all classes
+	LargestPatchIndex float64        `json:"largest_patch_index"` // Largest patch of any class as a percent of TotalArea
+	EdgeLength        float64        `json:"edge_length"`         // Meters of boundary between different classes
+	EdgeDensity       float64        `json:"edge_density"`        // Meters of edge per hectare
+	Diversity         float64        `json:"diversity"`           // Shannon diversity of class areas
+}
+
+// LandscapeMetrics computes habitat-fragmentation metrics for a single-band
+// classified image within region: per-class area, patch count, and largest
+// patch index, plus landscape edge density.
+//
+// Patches are connected regions of one class (ee.Image.connectedComponents).
+// Areas are pixel counts times scale², and edge length is estimated as half
+// a pixel side per pixel bordering a different class, since each boundary
+// is seen from both sides. Masked pixels and the NoDataClass, if set, are
+// excluded, and boundaries with them are not edges.
+//
+// Earth Engine cannot label patches larger than 1024 pixels. The remaining
+// pixels of a class are counted as one more patch, so patch counts are a
+// lower bound and the largest patch area an upper bound when a class has
+// several patches that large; use a coarser scale to avoid this.
+//
+// Example:
+//
+//	landCover := client.Image("USGS/NLCD_RELEASES/2021_REL/NLCD/2021").Select("landcover")
+//	metrics, err := helpers.LandscapeMetrics(ctx, client, landCover, watershed, 30,
+//	    helpers.NoDataClass(0))
+//	for _, class := range metrics.Classes {
+//	    fmt.Printf("class %d: %d patches, LPI %.1f%%\n",
+//	        class.Class, class.PatchCount, class.LargestPatchIndex)
+//	}
+func LandscapeMetrics(ctx context.Context, client *earthengine.Client, classifiedImage *earthengine.Image, region earthengine.Geometry, scale float64, opts ...LandscapeOption) (*LandscapeResult, error) {
+	_ = client
+
+	if classifiedImage == nil {
+		return nil, fmt.Errorf("classified image cannot be nil")
+	}
+	if region == nil {
+		return nil, fmt.Errorf("region is required")
+	}
+	if scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", scale)
+	}
+
+	cfg := &landscapeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	classes := classifiedImage.Rename("class")
+	if cfg.noData != nil {
+		classes = classes.UpdateMask(classes.Neq(float64(*cfg.noData)))
+	}
+
+	// Pixels whose edge neighbors include a different class
+	edges := classes.FocalMax(1, "plus").
+		Subtract(classes.FocalMin(1, "plus")).
+		Gt(0).
+		UpdateMask(classes.Mask()).
+		Rename("edge")
+
+	values, err := classes.AddBands(edges).
+		ReduceRegion(region,
+			earthengine.ReducerFrequencyHistogram(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count class pixels: %w", err)
+	}
+
+	classCounts, err := parseClassCounts(values["class"])
+	if err != nil {
+		return nil, err
+	}
+	edgeCounts, err := parseClassCounts(values["edge"])
+	if err != nil {
+		return nil, err
+	}
+
+	pixelArea := scale * scale
+	result := &LandscapeResult{
+		Classes:   make([]ClassMetrics, 0, len(classCounts)),
+		Diversity: ShannonDiversity(classCounts),
+	}
+	for _, count := range classCounts {
+		result.TotalArea += float64(count) * pixelArea
+	}
+	if result.TotalArea == 0 {
+		return result, nil
+	}
+	result.EdgeLength = float64(edgeCounts[1]) * scale / 2
+	result.EdgeDensity = result.EdgeLength / (result.TotalArea / 10000)
+
+	classValues := make([]int, 0, len(classCounts))
+	for class, count := range classCounts {
+		if count > 0 {
+			classValues = append(classValues, class)
+		}
+	}
+	sort.Ints(classValues)
+
+	patchSizes, err := landscapePatchSizes(ctx, classes, region, scale, classValues, !cfg.fourConnected)
+	if err != nil {
+		return nil, err
+	}
+
+	largest := 0.0
+	for _, class := range classValues {
+		count := classCounts[class]
+		sizes := patchSizes[class]
+
+		metrics := ClassMetrics{
+			Class:      class,
+			Area:       float64(count) * pixelArea,
+			PatchCount: len(sizes),
+		}
+
+		labeled := 0
+		for _, size := range sizes {
+			labeled += size
+			metrics.LargestPatchArea = math.Max(metrics.LargestPatchArea, float64(size)*pixelArea)
+		}
+		// Pixels in patches too large to label; smaller shortfalls are
+		// rounding of partial pixels along the region's edge
+		if unlabeled := count - labeled; unlabeled > maxPatchPixels {
+			metrics.PatchCount++
+			metrics.LargestPatchArea = math.Max(metrics.LargestPatchArea, float64(unlabeled)*pixelArea)
+		}
+
+		metrics.Proportion = metrics.Area / result.TotalArea * 100
+		metrics.LargestPatchIndex = metrics.LargestPatchArea / result.TotalArea * 100
+		largest = math.Max(largest, metrics.LargestPatchArea)
+
+		result.PatchCount += metrics.PatchCount
+		result.Classes = append(result.Classes, metrics)
+	}
+	result.LargestPatchIndex = largest / result.TotalArea * 100
+
+	return result, nil
+}
+
+// landscapePatchSizes labels the patches of each class and returns their
+// sizes in pixels, by class.
+func landscapePatchSizes(ctx context.Context, classes *earthengine.Image, region earthengine.Geometry, scale float64, classValues []int, eightConnected bool) (map[int][]int, error) {
+	if len(classValues) == 0 {
+		return map[int][]int{}, nil
+	}
+
+	var labels *earthengine.Image
+	for _, class := range classValues {
+		band := classes.UpdateMask(classes.Eq(float64(class))).
+			ConnectedComponents(eightConnected, maxPatchPixels).
+			Select("labels").
+			Rename(patchBandName(class))
+		if labels == nil {
+			labels = band
+		} else {
+			labels = labels.AddBands(band)
+		}
+	}
+
+	values, err := labels.
+		ReduceRegion(region,
+			earthengine.ReducerFrequencyHistogram(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to label patches: %w", err)
+	}
+
+	sizes := make(map[int][]int, len(classValues))
+	for _, class := range classValues {
+		histogram, ok := values[patchBandName(class)].(map[string]interface{})
+		if !ok && values[patchBandName(class)] != nil {
+			return nil, fmt.Errorf("unexpected patch histogram for class %d: %v", class, values[patchBandName(class)])
+		}
+		for label, v := range histogram {
+			count, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid pixel count %v for patch %s of class %d", v, label, class)
+			}
+			if size := int(math.Round(count)); size > 0 {
+				sizes[class] = append(sizes[class], size)
+			}
+		}
+	}
+
+	return sizes, nil
+}
+
+// patchBandName names the patch label band of class.
+func patchBandName(class int) string {
+	return "patches_" + strconv.Itoa(class)
+}
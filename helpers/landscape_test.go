@@ -0,0 +1,121 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// newLandscapeTestClient returns a client whose server answers the class
+// count request with counts and the patch labeling request with patches,
+// recording each request body.
+func newLandscapeTestClient(t *testing.T, counts, patches string) (*earthengine.Client, func() []string) {
+	t.Helper()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, earthengine.AlgorithmImageConnectedComponents) {
+			w.Write([]byte(patches))
+			return
+		}
+		w.Write([]byte(counts))
+	})
+	return client, recorder.requests
+}
+
+func TestLandscapeMetrics(t *testing.T) {
+	// Forest (41) in two patches, water (11) in one patch too large to label
+	client, requests := newLandscapeTestClient(t,
+		`{"result": {"class": {"11": 2000, "41": 300}, "edge": {"0": 2200, "1": 100}}}`,
+		`{"result": {"patches_11": {}, "patches_41": {"5": 200, "9": 100}}}`)
+
+	region := earthengine.NewRectangle(-122.3, 44.7, -122.0, 44.9)
+	result, err := LandscapeMetrics(context.Background(), client, client.Image("test/landcover"), region, 30,
+		NoDataClass(0), FourConnected())
+	if err != nil {
+		t.Fatalf("LandscapeMetrics failed: %v", err)
+	}
+
+	pixel := 30.0 * 30.0
+	if result.TotalArea != 2300*pixel {
+		t.Errorf("TotalArea = %v, want %v", result.TotalArea, 2300*pixel)
+	}
+	if result.PatchCount != 3 {
+		t.Errorf("PatchCount = %d, want 3", result.PatchCount)
+	}
+	if result.EdgeLength != 1500 {
+		t.Errorf("EdgeLength = %v, want 1500 (100 edge pixels, half a side each)", result.EdgeLength)
+	}
+	if want := 1500 / (2300 * pixel / 10000); math.Abs(result.EdgeDensity-want) > 1e-9 {
+		t.Errorf("EdgeDensity = %v, want %v", result.EdgeDensity, want)
+	}
+	if want := 2000.0 / 2300 * 100; math.Abs(result.LargestPatchIndex-want) > 1e-9 {
+		t.Errorf("LargestPatchIndex = %v, want %v", result.LargestPatchIndex, want)
+	}
+
+	if len(result.Classes) != 2 || result.Classes[0].Class != 11 || result.Classes[1].Class != 41 {
+		t.Fatalf("Classes = %+v, want 11 and 41", result.Classes)
+	}
+	forest := result.Classes[1]
+	if forest.PatchCount != 2 || forest.LargestPatchArea != 200*pixel {
+		t.Errorf("forest = %+v, want 2 patches, largest 200 pixels", forest)
+	}
+	if want := 300.0 / 2300 * 100; math.Abs(forest.Proportion-want) > 1e-9 {
+		t.Errorf("forest Proportion = %v, want %v", forest.Proportion, want)
+	}
+	if water := result.Classes[0]; water.PatchCount != 1 || water.LargestPatchArea != 2000*pixel {
+		t.Errorf("water = %+v, want one unlabeled patch of 2000 pixels", water)
+	}
+
+	if len(requests()) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests()))
+	}
+	if !strings.Contains(requests()[0], earthengine.AlgorithmImageNeq) {
+		t.Error("no-data class is not masked")
+	}
+	if !strings.Contains(requests()[1], earthengine.AlgorithmKernelPlus) {
+		t.Error("FourConnected does not use a plus kernel")
+	}
+}
+
+func TestLandscapeMetricsEmpty(t *testing.T) {
+	client, requests := newLandscapeTestClient(t, `{"result": {"class": {}, "edge": {}}}`, `{"result": {}}`)
+
+	region := earthengine.NewRectangle(-122.3, 44.7, -122.0, 44.9)
+	result, err := LandscapeMetrics(context.Background(), client, client.Image("test/landcover"), region, 30)
+	if err != nil {
+		t.Fatalf("LandscapeMetrics failed: %v", err)
+	}
+	if result.TotalArea != 0 || result.PatchCount != 0 || len(result.Classes) != 0 {
+		t.Errorf("result = %+v, want empty", result)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("got %d requests, want only the class count", len(requests()))
+	}
+}
+
+func TestLandscapeMetricsErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newLandscapeTestClient(t, `{"result": {}}`, `{"result": {}}`)
+	image := client.Image("test/landcover")
+	region := earthengine.NewRectangle(-122.3, 44.7, -122.0, 44.9)
+
+	if _, err := LandscapeMetrics(ctx, client, nil, region, 30); err == nil {
+		t.Error("Expected error for nil image")
+	}
+	if _, err := LandscapeMetrics(ctx, client, image, nil, 30); err == nil {
+		t.Error("Expected error for nil region")
+	}
+	if _, err := LandscapeMetrics(ctx, client, image, region, 0); err == nil {
+		t.Error("Expected error for zero scale")
+	}
+	if _, err := LandscapeMetrics(ctx, client, image, region, 30); err == nil {
+		t.Error("Expected error for missing class histogram")
+	}
+}
@@ -0,0 +1,228 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalResult encodes a result, such as a *TrendResult, *ZonalStatsResult,
+// or []AnomalyResult, as indented JSON for scripts and tools like jq.
+//
+// Field names are the snake_case names in the result types' json tags, in
+// declaration order. Unlike json.Marshal, MarshalResult does not fail on
+// values JSON cannot represent:
+//   - NaN and infinite numbers (an undefined correlation, say) encode as null
+//   - errors encode as their message
+//   - map keys that are numbers, such as percentiles, encode as strings
+//
+// Images and geometries are left out; use ZonalStatsToGeoJSON for zone
+// geometries. Zone IDs encode as the JSON value of whatever the zone's ID
+// property held, so numeric IDs decode as float64.
+//
+// Example:
+//
+//	trend, err := helpers.AnalyzeTrend(series)
+//	data, err := helpers.MarshalResult(trend)
+//	os.Stdout.Write(data) // | jq '.slope'
+func MarshalResult(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeResult(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// encodeResult writes v to buf as compact JSON, following encoding/json's
+// rules except as described in MarshalResult.
+func encodeResult(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	switch {
+	case v.Type().Implements(errorType):
+		return writeJSON(buf, v.Interface().(error).Error())
+	case v.Type().Implements(jsonMarshalerType):
+		return writeJSON(buf, v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encodeResult(buf, v.Elem())
+
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, f)
+
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		if err := encodeStructFields(buf, v, &first); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for _, k := range v.MapKeys() {
+			key, err := resultMapKey(k)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			values[key] = v.MapIndex(k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encodeResult(buf, values[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeResult(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	default:
+		return writeJSON(buf, v.Interface())
+	}
+}
+
+// encodeStructFields writes the exported fields of struct v, inlining
+// untagged embedded structs as encoding/json does.
+func encodeStructFields(buf *bytes.Buffer, v reflect.Value, first *bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := encodeStructFields(buf, v.Field(i), first); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		if err := writeJSON(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := encodeResult(buf, v.Field(i)); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// resultMapKey converts a map key to a JSON object key.
+func resultMapKey(k reflect.Value) (string, error) {
+	switch k.Kind() {
+	case reflect.String:
+		return k.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(k.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported map key type %s", k.Type())
+	}
+}
+
+// writeJSON appends the encoding/json encoding of v.
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// parseFloatKeys converts the string keys of a decoded JSON object to the
+// float64 keys of percentile and histogram maps.
+func parseFloatKeys[V any](m map[string]V) (map[float64]V, error) {
+	if m == nil {
+		return nil, nil
+	}
+	parsed := make(map[float64]V, len(m))
+	for key, value := range m {
+		f, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric key %q: %w", key, err)
+		}
+		parsed[f] = value
+	}
+	return parsed, nil
+}
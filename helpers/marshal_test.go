@@ -0,0 +1,226 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalResultRoundTrip(t *testing.T) {
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	confidence := 0.9
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"AccuracyResult", &AccuracyResult{
+			Labels:            []int{1, 2},
+			Matrix:            [][]int{{5, 1}, {2, 7}},
+			OverallAccuracy:   0.8,
+			Kappa:             0.6,
+			ProducersAccuracy: []float64{0.83, 0.78},
+			ConsumersAccuracy: []float64{0.71, 0.88},
+		}},
+		{"AssetInfo", &AssetInfo{
+			Name:       "projects/p/assets/dem",
+			Type:       "IMAGE",
+			Title:      "DEM",
+			StartTime:  day,
+			EndTime:    day.AddDate(0, 1, 0),
+			UpdateTime: day,
+			SizeBytes:  1024,
+			Bands:      []BandInfo{{ID: "elevation", DataType: "int16", CRS: "EPSG:4326", Scale: 30, Width: 100, Height: 50}},
+			Bounds:     &Bounds{MinLon: -123, MinLat: 45, MaxLon: -122, MaxLat: 46},
+			Properties: map[string]interface{}{"source": "usgs", "version": 2.0},
+		}},
+		{"Summary", &Summary{Total: 4, Succeeded: 3, Failed: 1, SuccessRate: 0.75}},
+		{"ImageDifferenceResult", &ImageDifferenceResult{Band: "NDVI", Before: 0.4, After: 0.6, Difference: 0.2, PercentChange: 50}},
+		{"ImageDifferenceRegionResult", &ImageDifferenceRegionResult{Threshold: 0.1, ChangedArea: 300, IncreasedArea: 200, DecreasedArea: 100, ValidArea: 1000, PercentChanged: 30}},
+		{"ClassificationResult", &ClassificationResult{Class: 2, Probabilities: []float64{0.1, 0.2, 0.7}}},
+		{"ClusterResult", &ClusterResult{PixelCounts: map[int]int{0: 10, 1: 20}, SampledPixels: 30}},
+		{"SPIResult", &SPIResult{Months: 3, Points: []SPIPoint{{Time: day, Precipitation: 120, SPI: -1.2, Category: SPIModerateDrought}}}},
+		{"CollectionStats", &CollectionStats{Count: 2, FirstTime: day, LastTime: day.AddDate(0, 0, 5), Dates: []string{"2023-06-01", "2023-06-06"}}},
+		{"NearestImageResult", &NearestImageResult{Date: day, CloudCover: 12.5, DaysOff: 1.5}},
+		{"CompositeResult", &CompositeResult{ObservationCount: 8, DateRange: DateRange{Start: "2023-06-01", End: "2023-08-31"}, Method: MedianComposite}},
+		{"CompositeMetrics", &CompositeMetrics{MeanObservations: 6.5, MedianObservations: 6, MinObservations: 2, MaxObservations: 11, Coverage: 0.98, CloudFreePixels: 0.91}},
+		{"CompositeDifference", &CompositeDifference{MeanDifference: 0.1, MedianDifference: 0.08, StdDevDifference: 0.05, MaxDifference: 0.4, PercentChanged: 12, Threshold: 0.1}},
+		{"SeasonalDifferenceResult", &SeasonalDifferenceResult{Year: 2023, SeasonA: DateRange{Start: "2023-06-01", End: "2023-08-31"}, SeasonB: DateRange{Start: "2023-12-01", End: "2024-02-29"}, Stats: CompositeDifference{MeanDifference: 0.2, PercentChanged: 35, Threshold: 0.3}}},
+		{"TerrainMetrics", &TerrainMetrics{Elevation: 1200, Slope: 15, Aspect: 270}},
+		{"ExportSizeEstimate", &ExportSizeEstimate{Pixels: 1000000, Bands: 3, Bytes: 12000000}},
+		{"ExportResult", &ExportResult{TaskID: "ABC123"}},
+		{"GridResult", &GridResult{Result: Result{Value: 42.0, Index: 3}, Lat: 45.5, Lon: -122.6}},
+		{"NDVIAnomalyResult", &NDVIAnomalyResult{Current: 0.5, BaselineMean: 0.6, BaselineStdDev: 0.05, Anomaly: -0.1, ZScore: -2, BaselineYears: 5, BaselineObservations: 40}},
+		{"CropWaterStressResult", &CropWaterStressResult{NDVI: 0.6, NDMI: 0.1, Category: CropStressModerate}},
+		{"TasseledCapResult", &TasseledCapResult{Brightness: 0.32, Greenness: 0.17, Wetness: -0.09}},
+		{"HypsometryResult", &HypsometryResult{
+			Bands:               []ElevationBand{{Min: 150, Max: 200, Area: 300, AreaFraction: 0.3, FractionAbove: 1}},
+			TotalArea:           1000,
+			MinElevation:        150,
+			MaxElevation:        380,
+			MeanElevation:       240,
+			MedianElevation:     233.3,
+			HypsometricIntegral: 0.39,
+		}},
+		{"WaterResult", &WaterResult{Area: 5000, PixelCount: 50, Scale: 10, Threshold: 0}},
+		{"LandCoverClassInfo", &LandCoverClassInfo{Code: 42, Name: "forest_evergreen", Confidence: &confidence}},
+		{"LandCoverChangeResult", &LandCoverChangeResult{FromYear: 2001, ToYear: 2021, FromClass: "forest_evergreen", ToClass: "developed_low", FromCode: 42, ToCode: 22, Changed: true}},
+		{"LandscapeResult", &LandscapeResult{
+			Classes:           []ClassMetrics{{Class: 1, Area: 900, Proportion: 90, PatchCount: 2, LargestPatchArea: 600, LargestPatchIndex: 60}},
+			TotalArea:         1000,
+			PatchCount:        3,
+			LargestPatchIndex: 60,
+			EdgeLength:        120,
+			EdgeDensity:       1200,
+			Diversity:         0.33,
+		}},
+		{"FloodResult", &FloodResult{Area: 8000, PixelCount: 80, Scale: 10, Threshold: -3, MaxSlope: 5}},
+		{"SunPosition", &SunPosition{Azimuth: 180, Elevation: 60, Zenith: 30}},
+		{"TimeSeries", &TimeSeries{Name: "NDVI", Points: []TimeSeriesPoint{{Time: day, Value: 0.5, Index: 0}}}},
+		{"TrendResult", &TrendResult{Slope: 0.01, Intercept: 0.3, RSquared: 0.9, PValue: 0.01, TrendDirection: "increasing", ChangePercent: 20, StartValue: 0.3, EndValue: 0.36, SignificantDiff: true}},
+		{"AnomalyResults", []AnomalyResult{{Index: 4, Time: day, Value: 9, ZScore: 3.2, IsAnomaly: true, Threshold: 3, Deviation: 3.2, Baseline: 1, Spread: 2.5}}},
+		{"SeasonalDecomposition", &SeasonalDecomposition{Trend: []float64{1, 2}, Seasonal: []float64{0.5, -0.5}, Residual: []float64{0, 0.1}, Period: 2}},
+		{"ChangeDetectionResult", &ChangeDetectionResult{BeforeMean: 1, AfterMean: 2, Difference: 1, PercentDiff: 100, TValue: 4.5, PValue: 0.001, Significant: true, Direction: "increase"}},
+		{"LagCorrelations", []LagCorrelation{{Lag: 1, Period: "month", Correlation: 0.8, N: 12, Peak: true}}},
+		{"ZonalStatsResult", &ZonalStatsResult{
+			Zones: []ZonalStats{{
+				ZoneID:       "parcel-1",
+				Stats:        map[string]float64{"elevation_mean": 120},
+				PixelCount:   400,
+				Area:         360000,
+				WeightedMean: map[string]float64{"elevation": 118},
+				TotalWeight:  0.8,
+			}},
+			Statistics: []ZonalStatistic{Mean},
+			Bands:      []string{"elevation"},
+			Scale:      30,
+		}},
+		{"ZonalHistogram", &ZonalHistogram{ZoneID: 7.0, BandName: "elevation", Histogram: map[float64]int{100: 3, 112.5: 5}, BucketSize: 12.5, MinValue: 100, MaxValue: 125}},
+		{"ZonalFrequency", &ZonalFrequency{ZoneID: "a", ClassCounts: map[int]int{41: 10, 42: 5}, Dominant: 41, Diversity: 0.64}},
+		{"ZonalPercentiles", &ZonalPercentiles{ZoneID: "a", BandName: "NDVI", Percentiles: map[float64]float64{5: 0.1, 50: 0.4, 97.5: 0.8}}},
+		{"ZonalTimeSeries", &ZonalTimeSeries{ZoneID: "a", BandName: "NDVI", Series: []ZonalTimeSeriesPoint{{Time: "2023-06-01", Value: 0.5}}}},
+		{"ZonalComparison", &ZonalComparison{ZoneID: "a", BandName: "NDVI", BeforeValue: 0.4, AfterValue: 0.3, Difference: -0.1, PercentChange: -25, ChangeCategory: "decrease"}},
+		{"ZonalCrossTab", &ZonalCrossTab{
+			ZoneID:    "a",
+			Matrix:    map[string]map[string]int{"41": {"41": 8, "22": 2}},
+			FromTotal: map[string]int{"41": 10},
+			ToTotal:   map[string]int{"41": 8, "22": 2},
+		}},
+		{"ZonalCorrelation", &ZonalCorrelation{ZoneID: "a", Band1: "NDVI", Band2: "LST", Correlation: -0.7, RSquared: 0.49, Slope: -10, Intercept: 40}},
+		{"ZonalStatsSummary", &ZonalStatsSummary{TotalZones: 3, MeanValue: 10, MedianValue: 9, StdDevValue: 2, MinValue: 7, MaxValue: 14, TotalArea: 3000, TotalPixelCount: 30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalResult(tt.value)
+			if err != nil {
+				t.Fatalf("MarshalResult() error = %v", err)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(tt.value))
+			if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v\n%s", err, data)
+			}
+			if got := decoded.Elem().Interface(); !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("round trip = %#v, want %#v\n%s", got, tt.value, data)
+			}
+		})
+	}
+}
+
+func TestMarshalResultFieldNames(t *testing.T) {
+	result := &ZonalStats{
+		ZoneID:     "parcel-1",
+		Stats:      map[string]float64{"NDVI_mean": 0.5},
+		PixelCount: 10,
+		Area:       900,
+	}
+
+	data, err := MarshalResult(result)
+	if err != nil {
+		t.Fatalf("MarshalResult() error = %v", err)
+	}
+
+	got := string(data)
+	names := []string{`"zone_id"`, `"stats"`, `"pixel_count"`, `"area"`, `"weighted_mean"`, `"total_weight"`}
+	last := -1
+	for _, name := range names {
+		i := strings.Index(got, name)
+		if i < 0 {
+			t.Fatalf("output missing %s:\n%s", name, got)
+		}
+		if i < last {
+			t.Errorf("%s out of declaration order:\n%s", name, got)
+		}
+		last = i
+	}
+	if strings.Contains(got, "Geometry") || strings.Contains(got, "geometry") {
+		t.Errorf("output includes the zone geometry:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "}\n") {
+		t.Errorf("output should end with a newline, got %q", got)
+	}
+}
+
+func TestMarshalResultUnrepresentableValues(t *testing.T) {
+	correlations := []LagCorrelation{
+		{Lag: 0, Period: "month", Correlation: math.NaN(), N: 2},
+		{Lag: 1, Period: "month", Correlation: math.Inf(1), N: 2},
+	}
+
+	data, err := MarshalResult(correlations)
+	if err != nil {
+		t.Fatalf("MarshalResult() error = %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, data)
+	}
+	for i, c := range decoded {
+		if v, ok := c["correlation"]; !ok || v != nil {
+			t.Errorf("correlations[%d].correlation = %v, want null", i, v)
+		}
+	}
+}
+
+func TestMarshalResultErrorsAndEmbeddedFields(t *testing.T) {
+	results := []GridResult{
+		{Result: Result{Value: 0.5, Index: 0}, Lat: 45, Lon: -122},
+		{Result: Result{Error: errors.New("no data at point"), Index: 1}, Lat: 46, Lon: -122},
+	}
+
+	data, err := MarshalResult(results)
+	if err != nil {
+		t.Fatalf("MarshalResult() error = %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, data)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("got %d results, want 2", len(decoded))
+	}
+
+	// The embedded Result's fields sit beside lat and lon.
+	if got := decoded[0]["value"]; got != 0.5 {
+		t.Errorf("results[0].value = %v, want 0.5", got)
+	}
+	if got := decoded[0]["error"]; got != nil {
+		t.Errorf("results[0].error = %v, want null", got)
+	}
+	if got := decoded[1]["error"]; got != "no data at point" {
+		t.Errorf("results[1].error = %v, want %q", got, "no data at point")
+	}
+	if got := decoded[1]["lat"]; got != 46.0 {
+		t.Errorf("results[1].lat = %v, want 46", got)
+	}
+	if _, ok := decoded[1]["Result"]; ok {
+		t.Errorf("embedded Result should be inlined:\n%s", data)
+	}
+}
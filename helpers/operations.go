@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+// Retries of a failed operations page. Package variables so tests can shorten
+// the backoff.
+var (
+	operationPageRetries = 3
+	operationPageBackoff = time.Second
+)
+
+// OperationFilter selects operations for ListAllOperations and
+// ForEachOperation. The zero value matches every operation.
+type OperationFilter struct {
+	// Done, Running, and Failed select operations by state. An operation
+	// matches if it is in any selected state, or in any state if none is set.
+	// Done includes operations that failed or were cancelled.
+	Done    bool
+	Running bool
+	Failed  bool
+
+	// Since keeps operations whose metadata updateTime, or createTime if it
+	// has none, is at or after Since. Operations with neither are skipped.
+	// Ignored if zero.
+	Since time.Time
+
+	// PageSize is the number of operations requested per page. The server
+	// default is used if zero.
+	PageSize int
+}
+
+// matches reports whether op passes the filter.
+func (f OperationFilter) matches(op *apiv1.Operation) bool {
+	if f.Done || f.Running || f.Failed {
+		failed := operationFailed(op)
+		if !(f.Done && op.Done) && !(f.Running && !op.Done) && !(f.Failed && failed) {
+			return false
+		}
+	}
+
+	if !f.Since.IsZero() {
+		updated, ok := operationTime(op)
+		if !ok || updated.Before(f.Since) {
+			return false
+		}
+	}
+	return true
+}
+
+// operationFailed reports whether op finished with an error.
+func operationFailed(op *apiv1.Operation) bool {
+	if state, _ := op.Metadata["state"].(string); state == "FAILED" {
+		return true
+	}
+	return op.Done && op.Error != nil
+}
+
+// operationTime returns the metadata updateTime of op, or its createTime.
+func operationTime(op *apiv1.Operation) (time.Time, bool) {
+	for _, key := range []string{"updateTime", "createTime"} {
+		value, _ := op.Metadata[key].(string)
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ListAllOperations lists the project's long-running operations that match
+// filter, following page tokens until every page has been read. It is the
+// server's view of the project's exports, for dashboards or for checking an
+// earthengine.TaskManager against.
+//
+// A page that fails with a retryable error (see apiv1.IsRetryable) is
+// requested again, with backoff, before giving up; pages already read are
+// not refetched.
+//
+// Example:
+//
+//	// Exports that failed in the last day
+//	failed, err := helpers.ListAllOperations(ctx, service, "my-project", helpers.OperationFilter{
+//	    Failed: true,
+//	    Since:  time.Now().Add(-24 * time.Hour),
+//	})
+//	for _, op := range failed {
+//	    fmt.Printf("%s: %s\n", op.Name, op.Error.Message)
+//	}
+func ListAllOperations(ctx context.Context, service *apiv1.Service, projectID string, filter OperationFilter) ([]*apiv1.Operation, error) {
+	var operations []*apiv1.Operation
+	err := ForEachOperation(ctx, service, projectID, filter, func(op *apiv1.Operation) error {
+		operations = append(operations, op)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return operations, nil
+}
+
+// ForEachOperation is like ListAllOperations but passes each matching
+// operation to fn as pages arrive, so very long operation histories don't
+// need to be held in memory. Listing stops if fn returns an error, which
+// ForEachOperation returns.
+//
+// Example:
+//
+//	err := helpers.ForEachOperation(ctx, service, "my-project", helpers.OperationFilter{Running: true},
+//	    func(op *apiv1.Operation) error {
+//	        fmt.Println(op.Name, op.Metadata["state"])
+//	        return nil
+//	    })
+func ForEachOperation(ctx context.Context, service *apiv1.Service, projectID string, filter OperationFilter, fn func(*apiv1.Operation) error) error {
+	if service == nil {
+		return fmt.Errorf("service cannot be nil")
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID cannot be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	parent := projectID
+	if !strings.HasPrefix(parent, "projects/") {
+		parent = "projects/" + parent
+	}
+
+	pageToken := ""
+	for {
+		resp, err := listOperationsPage(ctx, service, parent, filter.PageSize, pageToken)
+		if err != nil {
+			return fmt.Errorf("failed to list operations in %s: %w", parent, err)
+		}
+
+		for _, op := range resp.Operations {
+			if op == nil || !filter.matches(op) {
+				continue
+			}
+			if err := fn(op); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// listOperationsPage requests one page of operations, retrying retryable
+// failures with exponential backoff.
+func listOperationsPage(ctx context.Context, service *apiv1.Service, parent string, pageSize int, pageToken string) (*apiv1.ListOperationsResponse, error) {
+	backoff := operationPageBackoff
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := service.Projects.Operations.List(ctx, parent, pageSize, pageToken, "")
+		if err == nil || attempt >= operationPageRetries || !apiv1.IsRetryable(err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+var testOperationPages = []string{
+	`{"operations": [
+		{"name": "projects/p/operations/a", "done": true, "metadata": {"state": "SUCCEEDED", "updateTime": "2024-03-01T10:00:00Z"}},
+		{"name": "projects/p/operations/b", "metadata": {"state": "RUNNING", "updateTime": "2024-03-02T10:00:00Z"}}
+	], "nextPageToken": "1"}`,
+	`{"operations": [
+		{"name": "projects/p/operations/c", "done": true, "error": {"code": 3, "message": "bad region"}, "metadata": {"state": "FAILED", "createTime": "2024-02-01T10:00:00Z"}},
+		{"name": "projects/p/operations/d", "metadata": {"state": "PENDING"}}
+	], "nextPageToken": "2"}`,
+	`{"operations": [
+		{"name": "projects/p/operations/e", "done": true, "metadata": {"state": "CANCELLED", "updateTime": "2024-03-03T10:00:00Z"}}
+	]}`,
+}
+
+// newOperationsTestService serves pages of operations in order, answering
+// the first failures requests for each page with a 503.
+func newOperationsTestService(t *testing.T, failures int, pages ...string) (*apiv1.Service, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var tokens []string
+	failed := make(map[string]int)
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/p/operations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		token := r.URL.Query().Get("pageToken")
+
+		mu.Lock()
+		tokens = append(tokens, token)
+		retry := failed[token] < failures
+		failed[token]++
+		mu.Unlock()
+
+		if retry {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		page := 0
+		if token != "" {
+			page = int(token[0] - '0')
+		}
+		if page >= len(pages) {
+			t.Errorf("unexpected page request with token %q", token)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[page]))
+	})
+
+	return service, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, tokens...)
+	}
+}
+
+func operationNames(ops []*apiv1.Operation) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = strings.TrimPrefix(op.Name, "projects/p/operations/")
+	}
+	return strings.Join(names, ",")
+}
+
+func TestListAllOperationsPagination(t *testing.T) {
+	service, tokens := newOperationsTestService(t, 0, testOperationPages...)
+
+	ops, err := ListAllOperations(context.Background(), service, "p", OperationFilter{})
+	if err != nil {
+		t.Fatalf("ListAllOperations failed: %v", err)
+	}
+
+	if got := operationNames(ops); got != "a,b,c,d,e" {
+		t.Errorf("operations = %q, want a,b,c,d,e", got)
+	}
+	if got := strings.Join(tokens(), ","); got != ",1,2" {
+		t.Errorf("page tokens = %q, want \",1,2\"", got)
+	}
+}
+
+func TestListAllOperationsFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter OperationFilter
+		want   string
+	}{
+		{"done", OperationFilter{Done: true}, "a,c,e"},
+		{"running", OperationFilter{Running: true}, "b,d"},
+		{"failed", OperationFilter{Failed: true}, "c"},
+		{"running or failed", OperationFilter{Running: true, Failed: true}, "b,c,d"},
+		{"since", OperationFilter{Since: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)}, "b,e"},
+		{"done since", OperationFilter{Done: true, Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, "a,c,e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newOperationsTestService(t, 0, testOperationPages...)
+
+			ops, err := ListAllOperations(context.Background(), service, "projects/p", tt.filter)
+			if err != nil {
+				t.Fatalf("ListAllOperations failed: %v", err)
+			}
+			if got := operationNames(ops); got != tt.want {
+				t.Errorf("operations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAllOperationsRetriesPages(t *testing.T) {
+	defer func(backoff time.Duration) { operationPageBackoff = backoff }(operationPageBackoff)
+	operationPageBackoff = time.Millisecond
+
+	service, tokens := newOperationsTestService(t, 1, testOperationPages...)
+
+	ops, err := ListAllOperations(context.Background(), service, "p", OperationFilter{})
+	if err != nil {
+		t.Fatalf("ListAllOperations failed: %v", err)
+	}
+	if len(ops) != 5 {
+		t.Errorf("len(ops) = %d, want 5", len(ops))
+	}
+	if got := strings.Join(tokens(), ","); got != ",,1,1,2,2" {
+		t.Errorf("page tokens = %q, want \",,1,1,2,2\"", got)
+	}
+
+	// Persistent failures give up after the configured retries
+	service, tokens = newOperationsTestService(t, operationPageRetries+1, testOperationPages...)
+	_, err = ListAllOperations(context.Background(), service, "p", OperationFilter{})
+	if !apiv1.IsRetryable(err) {
+		t.Errorf("err = %v, want retryable API error", err)
+	}
+	if got := len(tokens()); got != operationPageRetries+1 {
+		t.Errorf("requests = %d, want %d", got, operationPageRetries+1)
+	}
+}
+
+func TestForEachOperation(t *testing.T) {
+	service, tokens := newOperationsTestService(t, 0, testOperationPages...)
+
+	var names []string
+	stop := errors.New("stop")
+	err := ForEachOperation(context.Background(), service, "p", OperationFilter{Done: true},
+		func(op *apiv1.Operation) error {
+			names = append(names, op.Name)
+			if len(names) == 2 {
+				return stop
+			}
+			return nil
+		})
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want callback error", err)
+	}
+	if len(names) != 2 {
+		t.Errorf("callback ran %d times, want 2", len(names))
+	}
+	if got := strings.Join(tokens(), ","); got != ",1" {
+		t.Errorf("page tokens = %q, want listing to stop after page 2", got)
+	}
+}
+
+func TestListAllOperationsErrors(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newOperationsTestService(t, 0, testOperationPages...)
+
+	if _, err := ListAllOperations(ctx, nil, "p", OperationFilter{}); err == nil {
+		t.Error("expected error for nil service")
+	}
+	if _, err := ListAllOperations(ctx, service, "", OperationFilter{}); err == nil {
+		t.Error("expected error for empty project ID")
+	}
+	if err := ForEachOperation(ctx, service, "p", OperationFilter{}, nil); err == nil {
+		t.Error("expected error for nil callback")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := ListAllOperations(cancelled, service, "p", OperationFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,345 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// Sentinel-1 dataset constants
+const (
+	// Sentinel-1 C-band SAR Ground Range Detected scenes, log-scaled to dB
+	// (10m, 6-12 day revisit)
+	sentinel1DatasetID = "COPERNICUS/S1_GRD"
+
+	// defaultSARWindowDays covers one full Sentinel-1 repeat cycle
+	defaultSARWindowDays = 12
+
+	// sentinel1Scale is the pixel size of Sentinel-1 GRD scenes in meters
+	sentinel1Scale = 10
+
+	// defaultFloodThreshold is the VV backscatter drop, in dB, above which
+	// FloodExtent classifies a pixel as newly flooded
+	defaultFloodThreshold = -3.0
+
+	// defaultFloodMaxSlope is the steepest terrain, in degrees, FloodExtent
+	// maps; radar shadow on steeper slopes looks like open water
+	defaultFloodMaxSlope = 5.0
+)
+
+// Sentinel-1 polarizations: the transmit and receive polarizations of the
+// backscatter band.
+const (
+	PolarizationVV = "VV" // Co-polarized; sensitive to surface roughness and soil moisture
+	PolarizationVH = "VH" // Cross-polarized; sensitive to vegetation volume
+)
+
+// Sentinel-1 orbit directions. Backscatter depends on the look direction, so
+// compare scenes from the same orbit direction.
+const (
+	OrbitAscending  = "ASCENDING"
+	OrbitDescending = "DESCENDING"
+)
+
+// SAROption configures Sentinel-1 queries.
+type SAROption func(*sarConfig)
+
+type sarConfig struct {
+	windowDays     int
+	speckleRadius  float64
+	scale          float64
+	floodThreshold float64
+	maxSlope       float64
+	orbit          string
+}
+
+// newSARConfig returns the default Sentinel-1 configuration with opts applied.
+func newSARConfig(opts []SAROption) (*sarConfig, error) {
+	cfg := &sarConfig{
+		windowDays:     defaultSARWindowDays,
+		scale:          sentinel1Scale,
+		floodThreshold: defaultFloodThreshold,
+		maxSlope:       defaultFloodMaxSlope,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.speckleRadius < 0 {
+		return nil, fmt.Errorf("speckle filter radius cannot be negative, got %v", cfg.speckleRadius)
+	}
+	if cfg.scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", cfg.scale)
+	}
+	if cfg.floodThreshold >= 0 {
+		return nil, fmt.Errorf("flood threshold must be a negative dB change, got %v", cfg.floodThreshold)
+	}
+	if cfg.maxSlope <= 0 || cfg.maxSlope > 90 {
+		return nil, fmt.Errorf("max slope must be between 0 and 90 degrees, got %v", cfg.maxSlope)
+	}
+	cfg.orbit = strings.ToUpper(cfg.orbit)
+	if err := validateOrbit(cfg.orbit); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SARDateWindow sets the number of days, centered on the date, searched for
+// Sentinel-1 scenes (default 12, one repeat cycle).
+func SARDateWindow(days int) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.windowDays = days
+	}
+}
+
+// SpeckleFilter reduces radar speckle with a focal median over a square
+// neighborhood of radius pixels before sampling. A radius of 1 (3x3 pixels)
+// is usually enough; larger radii blur field edges.
+func SpeckleFilter(radius float64) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.speckleRadius = radius
+	}
+}
+
+// SARWithScale sets the sampling scale in meters (default 10).
+func SARWithScale(meters float64) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.scale = meters
+	}
+}
+
+// FloodThreshold sets the drop in VV backscatter, in dB, from the dry
+// reference to the flood date above which FloodExtent classifies a pixel as
+// flooded (default -3). More negative values map only the clearest flooding.
+func FloodThreshold(db float64) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.floodThreshold = db
+	}
+}
+
+// FloodMaxSlope sets the steepest terrain, in degrees of SRTM slope, that
+// FloodExtent maps (default 5). Water rarely pools on steeper ground, where
+// radar shadow is the main source of false positives.
+func FloodMaxSlope(degrees float64) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.maxSlope = degrees
+	}
+}
+
+// FloodOrbit restricts FloodExtent to one orbit direction (OrbitAscending or
+// OrbitDescending) for both dates, so backscatter changes are not caused by
+// a different look direction. Both directions are used by default.
+func FloodOrbit(orbit string) SAROption {
+	return func(cfg *sarConfig) {
+		cfg.orbit = orbit
+	}
+}
+
+// SARBackscatter returns Sentinel-1 backscatter in dB at a location, for the
+// given polarization (PolarizationVV or PolarizationVH) and orbit direction
+// (OrbitAscending, OrbitDescending, or "" for either).
+//
+// Radar sees through clouds, so this works where optical helpers like NDVI
+// find no clear scene. Only interferometric wide swath (IW) scenes are used;
+// if several fall in the date window, the most recent is sampled. Open water
+// is typically below -20 dB in VV, and wet soil is brighter than dry soil.
+//
+// Returns an error wrapping ErrNoImages if no scene covers the point in the
+// date window.
+//
+// Example:
+//
+//	vv, err := helpers.SARBackscatter(ctx, client, 45.5152, -122.6784, "2023-06-01",
+//	    helpers.PolarizationVV, helpers.OrbitDescending,
+//	    helpers.SpeckleFilter(1))
+//	flooded := vv < -18
+func SARBackscatter(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, polarization string, orbit string, opts ...SAROption) (float64, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return 0, err
+	}
+
+	polarization = strings.ToUpper(polarization)
+	if polarization != PolarizationVV && polarization != PolarizationVH {
+		return 0, fmt.Errorf("unsupported polarization %q (use VV or VH)", polarization)
+	}
+	orbit = strings.ToUpper(orbit)
+	if err := validateOrbit(orbit); err != nil {
+		return 0, err
+	}
+
+	cfg, err := newSARConfig(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	point := earthengine.NewPoint(lon, lat)
+	image, err := sentinel1Image(ctx, client, point, date, polarization, orbit, cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := image.
+		ReduceRegion(
+			point,
+			earthengine.ReducerFirst(),
+			earthengine.Scale(cfg.scale),
+		).
+		ComputeFloat(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute backscatter: %w", err)
+	}
+
+	return result, nil
+}
+
+// FloodResult contains the result of flood mapping over a region.
+type FloodResult struct {
+	Area       float64            `json:"area"`        // Flooded area in square meters
+	PixelCount int                `json:"pixel_count"` // Number of pixels classified as flooded
+	Scale      float64            `json:"scale"`       // Scale in meters used for the reduction
+	Threshold  float64            `json:"threshold"`   // VV change in dB used for classification
+	MaxSlope   float64            `json:"max_slope"`   // Steepest slope in degrees that was mapped
+	Mask       *earthengine.Image `json:"-"`           // Flood mask: 1 for flooded, masked elsewhere
+}
+
+// FloodExtent maps land newly inundated between preFloodDate, a dry
+// reference, and floodDate by thresholding the drop in Sentinel-1 VV
+// backscatter: smooth open water reflects the radar signal away from the
+// sensor, so flooded pixels become much darker.
+//
+// Pixels whose VV backscatter falls by more than the threshold (-3 dB by
+// default; see FloodThreshold) are classified as flooded, except on slopes
+// steeper than FloodMaxSlope, where radar shadow causes most false
+// positives. Permanent water is dark on both dates and is not included.
+// SpeckleFilter is recommended, since speckle alone can exceed the
+// threshold; SARDateWindow, SARWithScale, and FloodOrbit also apply.
+//
+// Returns an error wrapping ErrNoImages if either date has no Sentinel-1
+// scene over the region.
+//
+// Example:
+//
+//	valley := helpers.Bounds{MinLon: -122.35, MinLat: 46.95, MaxLon: -122.15, MaxLat: 47.05}
+//	flood, err := helpers.FloodExtent(ctx, client, valley, "2023-01-01", "2023-01-10",
+//	    helpers.SpeckleFilter(1),
+//	    helpers.FloodOrbit(helpers.OrbitDescending))
+//	fmt.Printf("Flooded: %.1f ha\n", flood.Area/10000)
+func FloodExtent(ctx context.Context, client *earthengine.Client, bounds Bounds, preFloodDate, floodDate string, opts ...SAROption) (*FloodResult, error) {
+	region, err := bounds.ToRectangle()
+	if err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+
+	cfg, err := newSARConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pre, err := time.Parse(dateLayout, preFloodDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pre-flood date %q (use YYYY-MM-DD): %w", preFloodDate, err)
+	}
+	flood, err := time.Parse(dateLayout, floodDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flood date %q (use YYYY-MM-DD): %w", floodDate, err)
+	}
+	if !pre.Before(flood) {
+		return nil, fmt.Errorf("pre-flood date %s must be before flood date %s", preFloodDate, floodDate)
+	}
+
+	before, err := sentinel1Image(ctx, client, region, preFloodDate, PolarizationVV, cfg.orbit, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("pre-flood image: %w", err)
+	}
+	after, err := sentinel1Image(ctx, client, region, floodDate, PolarizationVV, cfg.orbit, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("flood image: %w", err)
+	}
+
+	// Flooded: 1 where backscatter dropped by more than the threshold on
+	// gentle terrain, 0 elsewhere
+	slope := client.Image(srtmDatasetID).Select(srtmElevBand).Terrain(earthengine.AlgorithmTerrainSlope)
+	flooded := after.Subtract(before).Lte(cfg.floodThreshold).
+		And(slope.Lte(cfg.maxSlope)).
+		Clip(region)
+
+	// Sum flooded pixel area and flooded pixel count in one reduction
+	stack := flooded.Multiply(flooded.PixelArea()).Rename("area").
+		AddBands(flooded.Rename("flooded"))
+
+	stats, err := stack.
+		ReduceRegion(region, earthengine.ReducerSum(),
+			earthengine.Scale(cfg.scale),
+			earthengine.MaxPixels(1e9),
+		).
+		Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute flooded area: %w", err)
+	}
+
+	area, ok := stats["area"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected flooded area in result: %v", stats["area"])
+	}
+	count, ok := stats["flooded"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected flooded pixel count in result: %v", stats["flooded"])
+	}
+
+	return &FloodResult{
+		Area:       area,
+		PixelCount: int(math.Round(count)),
+		Scale:      cfg.scale,
+		Threshold:  cfg.floodThreshold,
+		MaxSlope:   cfg.maxSlope,
+		Mask:       flooded.UpdateMask(flooded),
+	}, nil
+}
+
+// sentinel1Image returns the most recent Sentinel-1 IW scene of polarization
+// over geom within the configured window around date, speckle filtered if
+// configured. orbit may be empty for either direction.
+func sentinel1Image(ctx context.Context, client *earthengine.Client, geom earthengine.Geometry, date, polarization, orbit string, cfg *sarConfig) (*earthengine.Image, error) {
+	window, err := centeredWindow(date, cfg.windowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.ImageCollection(sentinel1DatasetID).
+		FilterBounds(geom).
+		FilterDate(window.Start, window.End).
+		FilterMetadata("instrumentMode", "equals", "IW").
+		FilterListContains("transmitterReceiverPolarisation", polarization)
+	if orbit != "" {
+		collection = collection.FilterMetadata("orbitProperties_pass", "equals", orbit)
+	}
+
+	count, err := collection.Size(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Sentinel-1 scenes: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: no Sentinel-1 %s scenes between %s and %s",
+			ErrNoImages, polarization, window.Start, window.End)
+	}
+
+	// Most recent scene on top
+	image := collection.Select(polarization).Sort("system:time_start", true).Mosaic()
+	if cfg.speckleRadius > 0 {
+		image = image.FocalMedian(cfg.speckleRadius, "square")
+	}
+	return image, nil
+}
+
+// validateOrbit checks that orbit is an orbit direction or empty.
+func validateOrbit(orbit string) error {
+	if orbit != "" && orbit != OrbitAscending && orbit != OrbitDescending {
+		return fmt.Errorf("unsupported orbit %q (use ASCENDING or DESCENDING)", orbit)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// newSARTestClient returns a client whose collection size requests return
+// size and whose other requests return response, and a function returning
+// the request bodies so far.
+func newSARTestClient(t *testing.T, size int, response string) (*earthengine.Client, func() []string) {
+	t.Helper()
+
+	var recorder requestRecorder
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := recorder.record(r)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body, earthengine.AlgorithmCollectionSize) {
+			fmt.Fprintf(w, `{"result": %d}`, size)
+			return
+		}
+		w.Write([]byte(response))
+	})
+	return client, recorder.requests
+}
+
+const sarTestResponse = `{"result": {"VV": -21.4}}`
+
+func TestSARBackscatter(t *testing.T) {
+	client, requests := newSARTestClient(t, 3, sarTestResponse)
+
+	vv, err := SARBackscatter(context.Background(), client, 45.5, -122.6, "2023-06-01",
+		"vv", OrbitDescending, SpeckleFilter(1))
+	if err != nil {
+		t.Fatalf("SARBackscatter failed: %v", err)
+	}
+	if vv != -21.4 {
+		t.Errorf("backscatter = %v, want -21.4", vv)
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want 2", len(got))
+	}
+	// 12 days centered on the date
+	for _, want := range []string{sentinel1DatasetID, "2023-05-26", "2023-06-07", "IW", "DESCENDING",
+		earthengine.AlgorithmFilterListContains, earthengine.AlgorithmImageFocalMedian} {
+		if !strings.Contains(got[1], want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestSARBackscatterEitherOrbit(t *testing.T) {
+	client, requests := newSARTestClient(t, 1, sarTestResponse)
+
+	if _, err := SARBackscatter(context.Background(), client, 45.5, -122.6, "2023-06-01", PolarizationVH, ""); err != nil {
+		t.Fatalf("SARBackscatter failed: %v", err)
+	}
+	got := requests()
+	request := got[len(got)-1]
+	if strings.Contains(request, "orbitProperties_pass") {
+		t.Error("request filters by orbit although none was given")
+	}
+	if strings.Contains(request, earthengine.AlgorithmImageFocalMedian) {
+		t.Error("request filters speckle although SpeckleFilter was not given")
+	}
+}
+
+func TestSARBackscatterNoScenes(t *testing.T) {
+	client, requests := newSARTestClient(t, 0, sarTestResponse)
+
+	_, err := SARBackscatter(context.Background(), client, 45.5, -122.6, "2023-06-01", PolarizationVV, OrbitAscending)
+	if !errors.Is(err, ErrNoImages) {
+		t.Errorf("err = %v, want ErrNoImages", err)
+	}
+	if len(requests()) != 1 {
+		t.Errorf("got %d requests, want only the size request", len(requests()))
+	}
+}
+
+func TestSARBackscatterErrors(t *testing.T) {
+	client, requests := newSARTestClient(t, 1, sarTestResponse)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		lat          float64
+		date         string
+		polarization string
+		orbit        string
+		opts         []SAROption
+	}{
+		{"invalid latitude", 91, "2023-06-01", PolarizationVV, "", nil},
+		{"invalid date", 45.5, "June 1", PolarizationVV, "", nil},
+		{"unsupported polarization", 45.5, "2023-06-01", "HH", "", nil},
+		{"unsupported orbit", 45.5, "2023-06-01", PolarizationVV, "polar", nil},
+		{"negative speckle radius", 45.5, "2023-06-01", PolarizationVV, "", []SAROption{SpeckleFilter(-1)}},
+		{"empty window", 45.5, "2023-06-01", PolarizationVV, "", []SAROption{SARDateWindow(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SARBackscatter(ctx, client, tt.lat, -122.6, tt.date, tt.polarization, tt.orbit, tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if len(requests()) != 0 {
+		t.Error("invalid arguments should fail before sending a request")
+	}
+}
+
+func TestFloodExtent(t *testing.T) {
+	client, requests := newSARTestClient(t, 2, `{"result": {"area": 250000, "flooded": 2500}}`)
+
+	bounds := Bounds{MinLon: -122.35, MinLat: 46.95, MaxLon: -122.15, MaxLat: 47.05}
+	flood, err := FloodExtent(context.Background(), client, bounds, "2023-01-01", "2023-01-10",
+		SpeckleFilter(1), FloodThreshold(-4), FloodOrbit("descending"))
+	if err != nil {
+		t.Fatalf("FloodExtent failed: %v", err)
+	}
+	if flood.Area != 250000 || flood.PixelCount != 2500 {
+		t.Errorf("area = %v, pixels = %d", flood.Area, flood.PixelCount)
+	}
+	if flood.Threshold != -4 || flood.MaxSlope != defaultFloodMaxSlope || flood.Scale != sentinel1Scale {
+		t.Errorf("result = %+v", flood)
+	}
+	if flood.Mask == nil {
+		t.Error("Mask is nil")
+	}
+
+	got := requests()
+	if len(got) != 3 {
+		t.Fatalf("got %d requests, want 2 size requests and the reduction", len(got))
+	}
+	request := got[2]
+	for _, want := range []string{
+		"2022-12-26", "2023-01-07", // pre-flood window
+		"2023-01-04", "2023-01-16", // flood window
+		"DESCENDING",
+		srtmDatasetID,
+		earthengine.AlgorithmTerrainSlope,
+		earthengine.AlgorithmImageSubtract,
+		earthengine.AlgorithmImageFocalMedian,
+	} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestFloodExtentNoScenes(t *testing.T) {
+	client, _ := newSARTestClient(t, 0, `{"result": {}}`)
+
+	bounds := Bounds{MinLon: -122.35, MinLat: 46.95, MaxLon: -122.15, MaxLat: 47.05}
+	_, err := FloodExtent(context.Background(), client, bounds, "2023-01-01", "2023-01-10")
+	if !errors.Is(err, ErrNoImages) || !strings.Contains(err.Error(), "pre-flood") {
+		t.Errorf("err = %v, want ErrNoImages for the pre-flood image", err)
+	}
+}
+
+func TestFloodExtentErrors(t *testing.T) {
+	client, requests := newSARTestClient(t, 1, `{"result": {}}`)
+	ctx := context.Background()
+	bounds := Bounds{MinLon: -122.35, MinLat: 46.95, MaxLon: -122.15, MaxLat: 47.05}
+
+	tests := []struct {
+		name   string
+		bounds Bounds
+		pre    string
+		flood  string
+		opts   []SAROption
+	}{
+		{"invalid bounds", Bounds{MinLon: 1, MaxLon: 0}, "2023-01-01", "2023-01-10", nil},
+		{"invalid pre-flood date", bounds, "Jan 1", "2023-01-10", nil},
+		{"invalid flood date", bounds, "2023-01-01", "Jan 10", nil},
+		{"flood before reference", bounds, "2023-01-10", "2023-01-01", nil},
+		{"positive threshold", bounds, "2023-01-01", "2023-01-10", []SAROption{FloodThreshold(3)}},
+		{"zero max slope", bounds, "2023-01-01", "2023-01-10", []SAROption{FloodMaxSlope(0)}},
+		{"unsupported orbit", bounds, "2023-01-01", "2023-01-10", []SAROption{FloodOrbit("polar")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FloodExtent(ctx, client, tt.bounds, tt.pre, tt.flood, tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if len(requests()) != 0 {
+		t.Error("invalid arguments should fail before sending a request")
+	}
+}
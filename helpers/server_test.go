@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+// newTestClient returns a client whose requests are answered by handler on a
+// test server closed when the test ends. opts are applied after the test
+// project, HTTP client, and base URL.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...earthengine.ClientOption) *earthengine.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := earthengine.NewClient(context.Background(), append([]earthengine.ClientOption{
+		earthengine.WithProject("test-project"),
+		earthengine.WithHTTPClient(server.Client()),
+		earthengine.WithBaseURL(server.URL),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return client
+}
+
+// newTestService returns an API service whose requests are answered by
+// handler on a test server closed when the test ends.
+func newTestService(t *testing.T, handler http.HandlerFunc) *apiv1.Service {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	service, err := apiv1.NewService(context.Background(), apiv1.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	service.BasePath = server.URL + "/"
+	return service
+}
+
+// requestRecorder records the bodies of requests to a test server. Helpers
+// may send requests concurrently, so it is safe for concurrent use.
+type requestRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+// record reads and records the body of r, and returns it.
+func (rec *requestRecorder) record(r *http.Request) string {
+	body, _ := io.ReadAll(r.Body)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.bodies = append(rec.bodies, string(body))
+	return string(body)
+}
+
+// requests returns the request bodies recorded so far.
+func (rec *requestRecorder) requests() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string(nil), rec.bodies...)
+}
@@ -1,22 +1,25 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 // SunPosition represents the position of the sun in the sky.
 type SunPosition struct {
-	Azimuth   float64 // Degrees from north (0-360)
-	Elevation float64 // Degrees above horizon (-90 to 90)
-	Zenith    float64 // Degrees from zenith (0-180)
+	Azimuth   float64 `json:"azimuth"`   // Degrees from north (0-360)
+	Elevation float64 `json:"elevation"` // Degrees above horizon (-90 to 90)
+	Zenith    float64 `json:"zenith"`    // Degrees from zenith (0-180)
 }
 
 // CalculateSunPosition calculates the sun's position at a given location and time.
 //
-// Uses a simplified algorithm suitable for most applications.
-// For high-precision requirements, consider using a dedicated astronomy library.
+// It validates the coordinates and delegates to SunPositionAt; no API call is
+// made.
 //
 // Returns:
 //   - Azimuth: compass direction (0=North, 90=East, 180=South, 270=West)
@@ -33,59 +36,63 @@ func CalculateSunPosition(lat, lon float64, t time.Time) (*SunPosition, error) {
 		return nil, err
 	}
 
-	// Convert to radians
-	latRad := lat * math.Pi / 180.0
-
-	// Calculate Julian day
-	jd := julianDay(t)
-
-	// Calculate solar declination
-	n := jd - 2451545.0 // Days since J2000.0
-	L := math.Mod(280.460+0.9856474*n, 360.0)
-	g := math.Mod(357.528+0.9856003*n, 360.0) * math.Pi / 180.0
-
-	lambda := (L + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * math.Pi / 180.0
-	epsilon := 23.439 * math.Pi / 180.0
-	declination := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
-
-	// Calculate hour angle
-	utcHours := float64(t.Hour()) + float64(t.Minute())/60.0 + float64(t.Second())/3600.0
-	hourAngle := (15.0*(utcHours-12.0) + lon) * math.Pi / 180.0
-
-	// Calculate elevation
-	sinElevation := math.Sin(latRad)*math.Sin(declination) +
-		math.Cos(latRad)*math.Cos(declination)*math.Cos(hourAngle)
-	elevation := math.Asin(sinElevation) * 180.0 / math.Pi
+	pos := SunPositionAt(lat, lon, t)
+	return &pos, nil
+}
 
-	// Calculate azimuth
-	cosAzimuth := (math.Sin(declination) - math.Sin(latRad)*sinElevation) /
-		(math.Cos(latRad) * math.Cos(math.Asin(sinElevation)))
+// SunPositionAt calculates the sun's position at a given location and time
+// with the NOAA solar position algorithm, entirely in-process.
+//
+// Elevation is geometric, without a correction for atmospheric refraction.
+// The coordinates are not validated; use CalculateSunPosition for that.
+//
+// Example:
+//
+//	pos := helpers.SunPositionAt(45.5152, -122.6784, time.Now())
+//	fmt.Printf("Azimuth: %.1f°, Elevation: %.1f°\n", pos.Azimuth, pos.Elevation)
+func SunPositionAt(lat, lon float64, t time.Time) SunPosition {
+	sun := noaaSolarGeometry(t)
+	latRad := degToRad(lat)
 
-	// Clamp to avoid numerical issues
-	if cosAzimuth > 1.0 {
-		cosAzimuth = 1.0
+	// True solar time in minutes, and the hour angle in degrees
+	utc := t.UTC()
+	minutes := float64(utc.Hour())*60 + float64(utc.Minute()) +
+		(float64(utc.Second())+float64(utc.Nanosecond())/1e9)/60
+	trueSolarTime := math.Mod(minutes+sun.equationOfTime+4*lon, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
 	}
-	if cosAzimuth < -1.0 {
-		cosAzimuth = -1.0
+	hourAngle := trueSolarTime/4 - 180
+
+	// Zenith angle
+	cosZenith := math.Sin(latRad)*math.Sin(sun.declination) +
+		math.Cos(latRad)*math.Cos(sun.declination)*math.Cos(degToRad(hourAngle))
+	zenith := math.Acos(clampFloat(cosZenith, -1, 1))
+
+	// Azimuth, clockwise from north
+	azimuth := 180.0
+	if denom := math.Cos(latRad) * math.Sin(zenith); math.Abs(denom) > 1e-12 {
+		cosAzimuth := (math.Sin(latRad)*math.Cos(zenith) - math.Sin(sun.declination)) / denom
+		angle := radToDeg(math.Acos(clampFloat(cosAzimuth, -1, 1)))
+		if hourAngle > 0 {
+			azimuth = math.Mod(angle+180, 360)
+		} else {
+			azimuth = math.Mod(540-angle, 360)
+		}
 	}
 
-	azimuth := math.Acos(cosAzimuth) * 180.0 / math.Pi
-
-	// Adjust azimuth based on hour angle
-	if hourAngle > 0 {
-		azimuth = 360.0 - azimuth
-	}
-
-	return &SunPosition{
+	zenithDeg := radToDeg(zenith)
+	return SunPosition{
 		Azimuth:   azimuth,
-		Elevation: elevation,
-		Zenith:    90.0 - elevation,
-	}, nil
+		Elevation: 90.0 - zenithDeg,
+		Zenith:    zenithDeg,
+	}
 }
 
 // DayLength calculates the length of daylight at a location on a given date.
 //
-// Returns the duration of daylight (sunrise to sunset).
+// Returns the duration of daylight (sunrise to sunset). It is computed
+// locally with PhotoperiodHours; no API call is made.
 //
 // Note: This uses a simplified calculation. Atmospheric refraction and
 // the finite size of the sun's disc can affect actual sunrise/sunset times.
@@ -100,39 +107,47 @@ func DayLength(lat float64, date time.Time) (time.Duration, error) {
 		return 0, fmt.Errorf("invalid latitude: %f (must be between -90 and 90)", lat)
 	}
 
-	// Calculate solar declination for this date
-	jd := julianDay(date)
-	n := jd - 2451545.0
-	L := math.Mod(280.460+0.9856474*n, 360.0)
-	g := math.Mod(357.528+0.9856003*n, 360.0) * math.Pi / 180.0
+	return time.Duration(PhotoperiodHours(lat, date) * float64(time.Hour)), nil
+}
 
-	lambda := (L + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * math.Pi / 180.0
-	epsilon := 23.439 * math.Pi / 180.0
-	declination := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
+// PhotoperiodHours returns the hours of daylight (sunrise to sunset) at a
+// latitude on a date, from the sunset hour angle for the sun's declination.
+//
+// It is a pure calculation with no network call, suitable for tight loops.
+// During polar day it returns exactly 24, and during polar night exactly 0.
+// It returns NaN if lat is outside [-90, 90].
+//
+// Example:
+//
+//	hours := helpers.PhotoperiodHours(45.5152, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC))
+//	fmt.Printf("Photoperiod: %.2f hours\n", hours) // about 15.6
+func PhotoperiodHours(lat float64, date time.Time) float64 {
+	if lat < -90 || lat > 90 {
+		return math.NaN()
+	}
 
 	// Calculate hour angle at sunset
+	declination := degToRad(SolarDeclination(date))
 	latRad := lat * math.Pi / 180.0
 	cosHourAngle := -math.Tan(latRad) * math.Tan(declination)
 
 	// Check for polar day/night
-	if cosHourAngle > 1.0 {
+	if cosHourAngle >= 1.0 {
 		// Polar night - no sunrise
-		return 0, nil
+		return 0
 	}
-	if cosHourAngle < -1.0 {
+	if cosHourAngle <= -1.0 {
 		// Polar day - 24 hours of daylight
-		return 24 * time.Hour, nil
+		return 24
 	}
 
 	hourAngle := math.Acos(cosHourAngle)
-	daylightHours := 2.0 * hourAngle * 12.0 / math.Pi
-
-	return time.Duration(daylightHours * float64(time.Hour)), nil
+	return 2.0 * hourAngle * 12.0 / math.Pi
 }
 
 // SunriseTime calculates the sunrise time at a location on a given date.
 //
-// Returns the time of sunrise in UTC.
+// Returns the time of sunrise in UTC. See SunriseAt.
 //
 // Example:
 //
@@ -144,38 +159,16 @@ func SunriseTime(lat, lon float64, date time.Time) (time.Time, error) {
 		return time.Time{}, err
 	}
 
-	// Calculate solar declination
-	jd := julianDay(date)
-	n := jd - 2451545.0
-	L := math.Mod(280.460+0.9856474*n, 360.0)
-	g := math.Mod(357.528+0.9856003*n, 360.0) * math.Pi / 180.0
-
-	lambda := (L + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * math.Pi / 180.0
-	epsilon := 23.439 * math.Pi / 180.0
-	declination := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
-
-	// Calculate hour angle at sunrise
-	latRad := lat * math.Pi / 180.0
-	cosHourAngle := -math.Tan(latRad) * math.Tan(declination)
-
-	// Check for polar day/night
-	if cosHourAngle > 1.0 || cosHourAngle < -1.0 {
+	sunrise, ok := SunriseAt(lat, lon, date)
+	if !ok {
 		return time.Time{}, fmt.Errorf("no sunrise at this location on this date (polar day/night)")
 	}
-
-	hourAngle := math.Acos(cosHourAngle) * 180.0 / math.Pi
-
-	// Convert to UTC time
-	sunriseUTC := 12.0 - hourAngle/15.0 - lon/15.0
-	hours := int(sunriseUTC)
-	minutes := int((sunriseUTC - float64(hours)) * 60)
-
-	return time.Date(date.Year(), date.Month(), date.Day(), hours, minutes, 0, 0, time.UTC), nil
+	return sunrise, nil
 }
 
 // SunsetTime calculates the sunset time at a location on a given date.
 //
-// Returns the time of sunset in UTC.
+// Returns the time of sunset in UTC. See SunsetAt.
 //
 // Example:
 //
@@ -187,33 +180,50 @@ func SunsetTime(lat, lon float64, date time.Time) (time.Time, error) {
 		return time.Time{}, err
 	}
 
-	// Calculate solar declination
-	jd := julianDay(date)
-	n := jd - 2451545.0
-	L := math.Mod(280.460+0.9856474*n, 360.0)
-	g := math.Mod(357.528+0.9856003*n, 360.0) * math.Pi / 180.0
-
-	lambda := (L + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * math.Pi / 180.0
-	epsilon := 23.439 * math.Pi / 180.0
-	declination := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
-
-	// Calculate hour angle at sunset
-	latRad := lat * math.Pi / 180.0
-	cosHourAngle := -math.Tan(latRad) * math.Tan(declination)
-
-	// Check for polar day/night
-	if cosHourAngle > 1.0 || cosHourAngle < -1.0 {
+	sunset, ok := SunsetAt(lat, lon, date)
+	if !ok {
 		return time.Time{}, fmt.Errorf("no sunset at this location on this date (polar day/night)")
 	}
+	return sunset, nil
+}
+
+// SunriseAt calculates the sunrise time in UTC at a location on the calendar
+// day of date, with the NOAA algorithm and the standard correction for
+// refraction and the sun's disc. It returns false during polar day or night.
+//
+// Example:
+//
+//	sunrise, ok := helpers.SunriseAt(45.5152, -122.6784, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC))
+func SunriseAt(lat, lon float64, date time.Time) (time.Time, bool) {
+	return sunEvent(lat, lon, date, -1)
+}
 
-	hourAngle := math.Acos(cosHourAngle) * 180.0 / math.Pi
+// SunsetAt calculates the sunset time in UTC at a location on the calendar
+// day of date, as for SunriseAt. It returns false during polar day or night.
+//
+// Example:
+//
+//	sunset, ok := helpers.SunsetAt(45.5152, -122.6784, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC))
+func SunsetAt(lat, lon float64, date time.Time) (time.Time, bool) {
+	return sunEvent(lat, lon, date, 1)
+}
 
-	// Convert to UTC time
-	sunsetUTC := 12.0 + hourAngle/15.0 - lon/15.0
-	hours := int(sunsetUTC)
-	minutes := int((sunsetUTC - float64(hours)) * 60)
+// sunEvent returns sunrise (sign -1) or sunset (sign 1).
+func sunEvent(lat, lon float64, date time.Time, sign float64) (time.Time, bool) {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	sun := noaaSolarGeometry(SolarNoonAt(lon, date))
+
+	// Hour angle at which the sun's upper limb touches the horizon
+	latRad := degToRad(lat)
+	cosHourAngle := math.Cos(degToRad(90.833))/(math.Cos(latRad)*math.Cos(sun.declination)) -
+		math.Tan(latRad)*math.Tan(sun.declination)
+	if cosHourAngle > 1 || cosHourAngle < -1 || math.IsNaN(cosHourAngle) {
+		return time.Time{}, false
+	}
+	hourAngle := radToDeg(math.Acos(cosHourAngle))
 
-	return time.Date(date.Year(), date.Month(), date.Day(), hours, minutes, 0, 0, time.UTC), nil
+	minutes := 720 - 4*lon - sun.equationOfTime + sign*4*hourAngle
+	return midnight.Add(time.Duration(minutes * float64(time.Minute))), true
 }
 
 // IsDaytime checks if it's daytime at a given location and time.
@@ -263,7 +273,7 @@ func julianDay(t time.Time) float64 {
 
 // SolarNoon calculates the time of solar noon (when the sun is highest).
 //
-// Returns the time of solar noon in UTC.
+// Returns the time of solar noon in UTC. See SolarNoonAt.
 //
 // Example:
 //
@@ -275,22 +285,252 @@ func SolarNoon(lon float64, date time.Time) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("invalid longitude: %f (must be between -180 and 180)", lon)
 	}
 
-	// Solar noon is at 12:00 UTC + longitude correction
-	noonUTC := 12.0 - lon/15.0
+	return SolarNoonAt(lon, date), nil
+}
+
+// SolarNoonAt calculates the time of solar noon in UTC at a longitude on the
+// calendar day of date, corrected by the equation of time.
+//
+// Example:
+//
+//	noon := helpers.SolarNoonAt(-122.6784, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC))
+func SolarNoonAt(lon float64, date time.Time) time.Time {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Evaluate the equation of time near noon, then refine once
+	noon := midnight.Add(time.Duration((720 - 4*lon) * float64(time.Minute)))
+	for i := 0; i < 2; i++ {
+		minutes := 720 - 4*lon - noaaSolarGeometry(noon).equationOfTime
+		noon = midnight.Add(time.Duration(minutes * float64(time.Minute)))
+	}
+	return noon
+}
+
+// SolarDeclination returns the sun's declination in degrees at the given
+// instant: the latitude at which the sun is directly overhead, from about
+// +23.44 at the June solstice to -23.44 at the December solstice.
+//
+// Example:
+//
+//	decl := helpers.SolarDeclination(time.Date(2023, 6, 21, 12, 0, 0, 0, time.UTC)) // about 23.44
+func SolarDeclination(t time.Time) float64 {
+	return radToDeg(noaaSolarGeometry(t).declination)
+}
+
+// EquationOfTime returns the equation of time at the given instant: apparent
+// solar time minus mean solar time, between about -14 and +16 minutes over
+// the year. Add it to mean solar time to correct a sundial reading, or
+// subtract it from 12:00 local mean time for solar noon.
+//
+// Example:
+//
+//	eot := helpers.EquationOfTime(time.Date(2023, 11, 3, 12, 0, 0, 0, time.UTC)) // about +16m
+func EquationOfTime(t time.Time) time.Duration {
+	return time.Duration(noaaSolarGeometry(t).equationOfTime * float64(time.Minute))
+}
+
+// solarGeometry holds the sun's declination and the equation of time at an
+// instant.
+type solarGeometry struct {
+	declination    float64 // Radians
+	equationOfTime float64 // Minutes
+}
+
+// noaaSolarGeometry computes the solar declination and equation of time with
+// the NOAA solar calculator equations (after Meeus, Astronomical Algorithms).
+func noaaSolarGeometry(t time.Time) solarGeometry {
+	// Julian centuries since J2000.0
+	jc := (julianDay(t) - 2451545.0) / 36525.0
+
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360)
+	meanAnomaly := degToRad(357.52911 + jc*(35999.05029-0.0001537*jc))
+	eccentricity := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+
+	center := math.Sin(meanAnomaly)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(2*meanAnomaly)*(0.019993-0.000101*jc) +
+		math.Sin(3*meanAnomaly)*0.000289
+	omega := degToRad(125.04 - 1934.136*jc)
+	apparentLong := degToRad(meanLong + center - 0.00569 - 0.00478*math.Sin(omega))
+
+	meanObliquity := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliquity := degToRad(meanObliquity + 0.00256*math.Cos(omega))
+
+	y := math.Tan(obliquity / 2)
+	y *= y
+	l0 := degToRad(meanLong)
+	equationOfTime := y*math.Sin(2*l0) -
+		2*eccentricity*math.Sin(meanAnomaly) +
+		4*eccentricity*y*math.Sin(meanAnomaly)*math.Cos(2*l0) -
+		0.5*y*y*math.Sin(4*l0) -
+		1.25*eccentricity*eccentricity*math.Sin(2*meanAnomaly)
+
+	return solarGeometry{
+		declination:    math.Asin(math.Sin(obliquity) * math.Sin(apparentLong)),
+		equationOfTime: 4 * radToDeg(equationOfTime),
+	}
+}
+
+func degToRad(deg float64) float64 { return deg * math.Pi / 180 }
+
+func radToDeg(rad float64) float64 { return rad * 180 / math.Pi }
 
-	hours := int(noonUTC)
-	if hours < 0 {
-		hours += 24
+// clampFloat limits v to [lo, hi].
+func clampFloat(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// SunPositionQuery represents a deferred sun position query for batch
+// operations. It is computed locally and makes no API call.
+type SunPositionQuery struct {
+	lat float64
+	lon float64
+	t   time.Time
+}
+
+// NewSunPositionQuery creates a new sun position query for batch execution.
+// The result is a *SunPosition.
+func NewSunPositionQuery(lat, lon float64, t time.Time) Query {
+	return &SunPositionQuery{
+		lat: lat,
+		lon: lon,
+		t:   t,
 	}
-	if hours >= 24 {
-		hours -= 24
+}
+
+// Execute implements the Query interface. The client is not used.
+func (q *SunPositionQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	return CalculateSunPosition(q.lat, q.lon, q.t)
+}
+
+// Clear-sky model constants.
+const (
+	solarConstant   = 1361.0 // Mean extraterrestrial irradiance, W/m²
+	diffuseFraction = 0.1    // Clear-sky diffuse irradiance as a fraction of direct normal
+	groundAlbedo    = 0.2    // Typical ground reflectance for plane-of-array irradiance
+)
+
+// ClearSkyIrradiance estimates the global irradiance on a horizontal surface
+// under a clear sky, in W/m², at a location and time.
+//
+// Direct normal irradiance follows the Meinel model with an altitude
+// correction, using the Kasten-Young air mass and the Earth-Sun distance for
+// the date; diffuse irradiance is taken as 10% of direct normal. It returns 0
+// when the sun is below the horizon. Real irradiance is lower under clouds,
+// haze, or smoke, so treat the result as an upper bound.
+//
+// Example:
+//
+//	t := time.Date(2023, 6, 21, 20, 0, 0, 0, time.UTC)
+//	ghi := helpers.ClearSkyIrradiance(45.5152, -122.6784, t, 15)
+//	fmt.Printf("Clear-sky irradiance: %.0f W/m²\n", ghi)
+func ClearSkyIrradiance(lat, lon float64, t time.Time, elevationMeters float64) float64 {
+	pos := SunPositionAt(lat, lon, t)
+	direct, diffuse := clearSkyComponents(pos, t, elevationMeters)
+	return direct*math.Cos(degToRad(pos.Zenith)) + diffuse
+}
+
+// PlaneOfArrayIrradiance estimates the clear-sky irradiance in W/m² on a
+// panel tilted tiltDegrees from horizontal and facing azimuthDegrees
+// (clockwise from north, so 180 faces south).
+//
+// It adds the direct beam on the panel, isotropic sky diffuse, and light
+// reflected from the ground (albedo 0.2) to the model of ClearSkyIrradiance.
+//
+// Example:
+//
+//	poa := helpers.PlaneOfArrayIrradiance(45.5152, -122.6784, t, 15, 35, 180)
+func PlaneOfArrayIrradiance(lat, lon float64, t time.Time, elevationMeters, tiltDegrees, azimuthDegrees float64) float64 {
+	pos := SunPositionAt(lat, lon, t)
+	direct, diffuse := clearSkyComponents(pos, t, elevationMeters)
+	if direct == 0 {
+		return 0
+	}
+
+	zenith := degToRad(pos.Zenith)
+	tilt := degToRad(tiltDegrees)
+	global := direct*math.Cos(zenith) + diffuse
+
+	// Cosine of the angle between the sun and the panel normal
+	cosIncidence := math.Cos(zenith)*math.Cos(tilt) +
+		math.Sin(zenith)*math.Sin(tilt)*math.Cos(degToRad(pos.Azimuth-azimuthDegrees))
+
+	beam := direct * math.Max(cosIncidence, 0)
+	sky := diffuse * (1 + math.Cos(tilt)) / 2
+	ground := global * groundAlbedo * (1 - math.Cos(tilt)) / 2
+
+	return beam + sky + ground
+}
+
+// clearSkyComponents returns the clear-sky direct normal and horizontal
+// diffuse irradiance in W/m², or zeros when the sun is below the horizon.
+func clearSkyComponents(pos SunPosition, t time.Time, elevationMeters float64) (direct, diffuse float64) {
+	if pos.Elevation <= 0 {
+		return 0, 0
+	}
+
+	// Kasten-Young relative air mass
+	zenith := pos.Zenith
+	airMass := 1 / (math.Cos(degToRad(zenith)) + 0.50572*math.Pow(96.07995-zenith, -1.6364))
+
+	// Extraterrestrial irradiance, corrected for the Earth-Sun distance
+	dayOfYear := float64(t.UTC().YearDay())
+	extraterrestrial := solarConstant * (1 + 0.033*math.Cos(2*math.Pi*dayOfYear/365))
+
+	// Meinel model with altitude correction (elevation in km)
+	h := math.Max(elevationMeters, 0) / 1000
+	direct = extraterrestrial * ((1-0.14*h)*math.Pow(0.7, math.Pow(airMass, 0.678)) + 0.14*h)
+
+	return direct, direct * diffuseFraction
+}
+
+// IrradianceOption configures DailyInsolation.
+type IrradianceOption func(*irradianceConfig)
+
+type irradianceConfig struct {
+	tilt    *float64
+	azimuth float64
+}
+
+// PanelOrientation computes insolation on a panel tilted tiltDegrees from
+// horizontal and facing azimuthDegrees (clockwise from north) instead of on a
+// horizontal surface.
+func PanelOrientation(tiltDegrees, azimuthDegrees float64) IrradianceOption {
+	return func(cfg *irradianceConfig) {
+		cfg.tilt = &tiltDegrees
+		cfg.azimuth = azimuthDegrees
+	}
+}
+
+// insolationStep is the integration step of DailyInsolation.
+const insolationStep = 5 * time.Minute
+
+// DailyInsolation estimates the clear-sky solar energy received in a day, in
+// kWh/m², by integrating ClearSkyIrradiance (or PlaneOfArrayIrradiance with
+// PanelOrientation) over the 24 hours centered on solar noon of date.
+//
+// Example:
+//
+//	date := time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC)
+//	kwh := helpers.DailyInsolation(45.5152, -122.6784, date, 15,
+//	    helpers.PanelOrientation(35, 180))
+//	fmt.Printf("Clear-sky insolation: %.2f kWh/m²\n", kwh)
+func DailyInsolation(lat, lon float64, date time.Time, elevationMeters float64, opts ...IrradianceOption) float64 {
+	cfg := &irradianceConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	minutes := int((noonUTC - float64(int(noonUTC))) * 60)
-	if minutes < 0 {
-		minutes += 60
-		hours--
+	start := SolarNoonAt(lon, date).Add(-12 * time.Hour)
+	var wattHours float64
+	for t := start.Add(insolationStep / 2); t.Before(start.Add(24 * time.Hour)); t = t.Add(insolationStep) {
+		var irradiance float64
+		if cfg.tilt != nil {
+			irradiance = PlaneOfArrayIrradiance(lat, lon, t, elevationMeters, *cfg.tilt, cfg.azimuth)
+		} else {
+			irradiance = ClearSkyIrradiance(lat, lon, t, elevationMeters)
+		}
+		wattHours += irradiance * insolationStep.Hours()
 	}
 
-	return time.Date(date.Year(), date.Month(), date.Day(), hours, minutes, 0, 0, time.UTC), nil
+	return wattHours / 1000
 }
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"math"
 	"testing"
 	"time"
@@ -297,3 +298,200 @@ func ExampleIsDaytime() {
 	//     fmt.Println("It's nighttime!")
 	// }
 }
+
+func TestPhotoperiodHours(t *testing.T) {
+	summer := time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC)
+	winter := time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC)
+
+	if got := PhotoperiodHours(80, summer); got != 24 {
+		t.Errorf("PhotoperiodHours(80, summer) = %v, want exactly 24 (polar day)", got)
+	}
+	if got := PhotoperiodHours(80, winter); got != 0 {
+		t.Errorf("PhotoperiodHours(80, winter) = %v, want exactly 0 (polar night)", got)
+	}
+	if got := PhotoperiodHours(-80, winter); got != 24 {
+		t.Errorf("PhotoperiodHours(-80, winter) = %v, want 24 (southern polar day)", got)
+	}
+	if got := PhotoperiodHours(90, summer); got != 24 {
+		t.Errorf("PhotoperiodHours(90, summer) = %v, want 24 at the pole", got)
+	}
+
+	// The two hemispheres mirror each other
+	north, south := PhotoperiodHours(45, summer), PhotoperiodHours(-45, summer)
+	if math.Abs(north+south-24) > 1e-9 {
+		t.Errorf("PhotoperiodHours(45) + PhotoperiodHours(-45) = %v, want 24", north+south)
+	}
+
+	if got := PhotoperiodHours(95, summer); !math.IsNaN(got) {
+		t.Errorf("PhotoperiodHours(95) = %v, want NaN", got)
+	}
+
+	length, err := DayLength(45, summer)
+	if err != nil {
+		t.Fatalf("DayLength failed: %v", err)
+	}
+	if math.Abs(length.Hours()-north) > 1e-6 {
+		t.Errorf("DayLength = %v hours, want %v (PhotoperiodHours)", length.Hours(), north)
+	}
+}
+
+func TestSunPositionAtNOAA(t *testing.T) {
+	// Reference values from the NOAA solar calculator for Portland, OR on
+	// the 2023 summer solstice
+	lat, lon := 45.5152, -122.6784
+	date := time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC)
+
+	noon := SolarNoonAt(lon, date)
+	if want := time.Date(2023, 6, 21, 20, 12, 23, 0, time.UTC); absDuration(noon.Sub(want)) > time.Minute {
+		t.Errorf("SolarNoonAt = %s, want about %s", noon.Format(time.RFC3339), want.Format(time.RFC3339))
+	}
+
+	pos := SunPositionAt(lat, lon, noon)
+	if math.Abs(pos.Elevation-67.9) > 0.2 {
+		t.Errorf("noon elevation = %.2f, want about 67.9", pos.Elevation)
+	}
+	if math.Abs(pos.Azimuth-180) > 1 {
+		t.Errorf("noon azimuth = %.2f, want about 180", pos.Azimuth)
+	}
+
+	morning := SunPositionAt(lat, lon, time.Date(2023, 6, 21, 16, 0, 0, 0, time.UTC))
+	if morning.Azimuth < 90 || morning.Azimuth > 135 {
+		t.Errorf("morning azimuth = %.1f, want east-southeast", morning.Azimuth)
+	}
+
+	sunrise, ok := SunriseAt(lat, lon, date)
+	if want := time.Date(2023, 6, 21, 12, 21, 0, 0, time.UTC); !ok || absDuration(sunrise.Sub(want)) > 2*time.Minute {
+		t.Errorf("SunriseAt = %s, %v, want about %s", sunrise.Format(time.RFC3339), ok, want.Format(time.RFC3339))
+	}
+	sunset, ok := SunsetAt(lat, lon, date)
+	if want := time.Date(2023, 6, 22, 4, 3, 0, 0, time.UTC); !ok || absDuration(sunset.Sub(want)) > 2*time.Minute {
+		t.Errorf("SunsetAt = %s, %v, want about %s", sunset.Format(time.RFC3339), ok, want.Format(time.RFC3339))
+	}
+
+	if _, ok := SunriseAt(80, 0, date); ok {
+		t.Error("SunriseAt during polar day returned ok")
+	}
+}
+
+func TestSunPositionQuery(t *testing.T) {
+	when := time.Date(2023, 6, 21, 19, 0, 0, 0, time.UTC)
+	result, err := NewSunPositionQuery(45.5152, -122.6784, when).Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	pos, ok := result.(*SunPosition)
+	if !ok {
+		t.Fatalf("result = %T, want *SunPosition", result)
+	}
+	if want := SunPositionAt(45.5152, -122.6784, when); *pos != want {
+		t.Errorf("result = %+v, want %+v", *pos, want)
+	}
+
+	if _, err := NewSunPositionQuery(95, 0, when).Execute(context.Background(), nil); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func TestSolarDeclination(t *testing.T) {
+	// 2023 equinox and solstice instants
+	tests := []struct {
+		name string
+		t    time.Time
+		want float64
+	}{
+		{"March equinox", time.Date(2023, 3, 20, 21, 24, 0, 0, time.UTC), 0},
+		{"June solstice", time.Date(2023, 6, 21, 14, 57, 0, 0, time.UTC), 23.44},
+		{"September equinox", time.Date(2023, 9, 23, 6, 50, 0, 0, time.UTC), 0},
+		{"December solstice", time.Date(2023, 12, 22, 3, 27, 0, 0, time.UTC), -23.44},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SolarDeclination(tt.t); math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("SolarDeclination() = %.3f°, want %.2f° ± 0.5°", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquationOfTime(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want float64 // minutes
+	}{
+		{"February minimum", time.Date(2023, 2, 11, 12, 0, 0, 0, time.UTC), -14.2},
+		{"November maximum", time.Date(2023, 11, 3, 12, 0, 0, 0, time.UTC), 16.4},
+		{"mid-April zero", time.Date(2023, 4, 15, 12, 0, 0, 0, time.UTC), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EquationOfTime(tt.t).Minutes(); math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("EquationOfTime() = %.2f min, want %.1f min", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClearSkyIrradiance(t *testing.T) {
+	lat, lon := 45.5152, -122.6784
+	noon := SolarNoonAt(lon, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC))
+
+	ghi := ClearSkyIrradiance(lat, lon, noon, 15)
+	if ghi < 850 || ghi > 1050 {
+		t.Errorf("summer noon irradiance = %.0f W/m², want 850-1050", ghi)
+	}
+
+	if night := ClearSkyIrradiance(lat, lon, noon.Add(12*time.Hour), 15); night != 0 {
+		t.Errorf("midnight irradiance = %.0f W/m², want 0", night)
+	}
+
+	if high := ClearSkyIrradiance(lat, lon, noon, 3000); high <= ghi {
+		t.Errorf("irradiance at 3000 m = %.0f, want more than %.0f at sea level", high, ghi)
+	}
+
+	// A flat panel sees the same as a horizontal surface
+	if flat := PlaneOfArrayIrradiance(lat, lon, noon, 15, 0, 180); math.Abs(flat-ghi) > 1e-9 {
+		t.Errorf("flat panel irradiance = %v, want %v", flat, ghi)
+	}
+
+	// In winter, a south-facing tilted panel beats a horizontal one
+	winterNoon := SolarNoonAt(lon, time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC))
+	horizontal := ClearSkyIrradiance(lat, lon, winterNoon, 15)
+	south := PlaneOfArrayIrradiance(lat, lon, winterNoon, 15, 60, 180)
+	north := PlaneOfArrayIrradiance(lat, lon, winterNoon, 15, 60, 0)
+	if south <= horizontal || north >= horizontal {
+		t.Errorf("winter noon: south %.0f, horizontal %.0f, north %.0f W/m²", south, horizontal, north)
+	}
+}
+
+func TestDailyInsolation(t *testing.T) {
+	lat, lon := 45.5152, -122.6784
+
+	summer := DailyInsolation(lat, lon, time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC), 15)
+	winter := DailyInsolation(lat, lon, time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC), 15)
+	if summer < 7 || summer > 10 {
+		t.Errorf("summer insolation = %.2f kWh/m², want 7-10", summer)
+	}
+	if winter < 1 || winter > 3 {
+		t.Errorf("winter insolation = %.2f kWh/m², want 1-3", winter)
+	}
+
+	tilted := DailyInsolation(lat, lon, time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC), 15, PanelOrientation(60, 180))
+	if tilted <= winter {
+		t.Errorf("tilted winter insolation = %.2f, want more than horizontal %.2f", tilted, winter)
+	}
+
+	if polar := DailyInsolation(80, 0, time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC), 0); polar != 0 {
+		t.Errorf("polar night insolation = %v, want 0", polar)
+	}
+}
@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+// ThresholdMask masks image to pixels where band is within [min, max].
+//
+// Pass math.Inf(-1) for min or math.Inf(1) for max for a one-sided
+// threshold. All bands of image are kept; pixels are masked where band is
+// outside the range or already masked.
+//
+// Example:
+//
+//	// Keep pixels with NDVI above 0.6
+//	vegetation, err := helpers.ThresholdMask(ctx, client, ndvi, "NDVI",
+//	    0.6, math.Inf(1))
+func ThresholdMask(ctx context.Context, client *earthengine.Client, image *earthengine.Image, band string, min, max float64) (*earthengine.Image, error) {
+	_ = ctx
+	_ = client
+
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if band == "" {
+		return nil, fmt.Errorf("band is required")
+	}
+	if math.IsNaN(min) || math.IsNaN(max) {
+		return nil, fmt.Errorf("threshold cannot be NaN")
+	}
+	if min > max {
+		return nil, fmt.Errorf("min (%g) must not be greater than max (%g)", min, max)
+	}
+
+	// Infinite bounds can't be encoded, so are left out of the condition
+	values := image.Select(band)
+	var within *earthengine.Image
+	if !math.IsInf(min, -1) {
+		within = values.Gte(min)
+	}
+	if !math.IsInf(max, 1) {
+		below := values.Lte(max)
+		if within == nil {
+			within = below
+		} else {
+			within = within.And(below)
+		}
+	}
+	if within == nil {
+		return image, nil
+	}
+
+	return image.UpdateMask(within), nil
+}
+
+// AreaWithinThreshold returns the area in square meters of region where band
+// is within [min, max]. See ThresholdMask.
+//
+// Example:
+//
+//	// Open water: NDWI of 0.3 or more
+//	area, err := helpers.AreaWithinThreshold(ctx, client, ndwi, "NDWI",
+//	    0.3, math.Inf(1), region, 10)
+//	fmt.Printf("Water: %.1f ha\n", area/10000)
+func AreaWithinThreshold(ctx context.Context, client *earthengine.Client, image *earthengine.Image, band string, min, max float64, region earthengine.Geometry, scale float64) (float64, error) {
+	if region == nil {
+		return 0, fmt.Errorf("region cannot be nil")
+	}
+	if scale <= 0 {
+		return 0, fmt.Errorf("scale must be positive, got %g", scale)
+	}
+
+	masked, err := ThresholdMask(ctx, client, image, band, min, max)
+	if err != nil {
+		return 0, err
+	}
+
+	selected := masked.Select(band)
+	area, err := selected.PixelArea().
+		UpdateMask(selected.Mask()).
+		ReduceRegion(region, earthengine.ReducerSum(),
+			earthengine.Scale(scale),
+			earthengine.MaxPixels(1e9),
+		).
+		ComputeFloat(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute area within threshold: %w", err)
+	}
+
+	return area, nil
+}
@@ -0,0 +1,84 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func TestAreaWithinThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		min, max  float64
+		wantCalls []string
+		skipCalls []string
+	}{
+		{"range", 0.2, 0.8, []string{"Image.gte", "Image.lte", "Image.and"}, nil},
+		{"min only", 0.6, math.Inf(1), []string{"Image.gte"}, []string{"Image.lte"}},
+		{"max only", math.Inf(-1), 0, []string{"Image.lte"}, []string{"Image.gte"}},
+		{"unbounded", math.Inf(-1), math.Inf(1), nil, []string{"Image.gte", "Image.lte"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, lastRequest := newImageryTestClient(t, `{"result": {"area": 12500.5}}`)
+			region := earthengine.NewRectangle(-122.7, 45.5, -122.6, 45.6)
+
+			area, err := AreaWithinThreshold(context.Background(), client, client.Image("test"), "NDVI", tt.min, tt.max, region, 10)
+			if err != nil {
+				t.Fatalf("AreaWithinThreshold failed: %v", err)
+			}
+			if area != 12500.5 {
+				t.Errorf("area = %v, want 12500.5", area)
+			}
+
+			request := lastRequest()
+			for _, call := range append(tt.wantCalls, "Image.pixelArea", "Image.mask") {
+				if !strings.Contains(request, `"`+call+`"`) {
+					t.Errorf("request does not call %s", call)
+				}
+			}
+			for _, call := range tt.skipCalls {
+				if strings.Contains(request, `"`+call+`"`) {
+					t.Errorf("request calls %s", call)
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdMaskErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	image := client.Image("test")
+
+	tests := []struct {
+		name     string
+		image    *earthengine.Image
+		band     string
+		min, max float64
+	}{
+		{"nil image", nil, "NDVI", 0, 1},
+		{"empty band", image, "", 0, 1},
+		{"min above max", image, "NDVI", 1, 0},
+		{"NaN", image, "NDVI", math.NaN(), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ThresholdMask(context.Background(), client, tt.image, tt.band, tt.min, tt.max); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	region := earthengine.NewPoint(0, 0)
+	if _, err := AreaWithinThreshold(context.Background(), client, image, "NDVI", 0, 1, region, 0); err == nil {
+		t.Error("Expected error for zero scale")
+	}
+	if _, err := AreaWithinThreshold(context.Background(), client, image, "NDVI", 0, 1, nil, 10); err == nil {
+		t.Error("Expected error for nil region")
+	}
+}
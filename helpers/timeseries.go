@@ -2,9 +2,14 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexscott64/go-earthengine"
@@ -12,63 +17,68 @@ import (
 
 // TimeSeriesPoint represents a single data point in a time series.
 type TimeSeriesPoint struct {
-	Time  time.Time
-	Value float64
-	Index int // Original index in collection
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+	Index int       `json:"index"` // Original index in collection
 }
 
 // TimeSeries represents a collection of time-series data points.
 type TimeSeries struct {
-	Points []TimeSeriesPoint
-	Name   string
+	Points []TimeSeriesPoint `json:"points"`
+	Name   string            `json:"name"`
 }
 
 // TrendResult contains trend analysis results.
 type TrendResult struct {
-	Slope           float64 // Rate of change per day
-	Intercept       float64
-	RSquared        float64 // Coefficient of determination (0-1)
-	PValue          float64 // Statistical significance
-	TrendDirection  string  // "increasing", "decreasing", "stable"
-	ChangePercent   float64 // Total percent change over period
-	StartValue      float64
-	EndValue        float64
-	SignificantDiff bool // Whether change is statistically significant
+	Slope           float64 `json:"slope"` // Rate of change per day
+	Intercept       float64 `json:"intercept"`
+	RSquared        float64 `json:"r_squared"`       // Coefficient of determination (0-1)
+	PValue          float64 `json:"p_value"`         // Statistical significance
+	TrendDirection  string  `json:"trend_direction"` // "increasing", "decreasing", "stable"
+	ChangePercent   float64 `json:"change_percent"`  // Total percent change over period
+	StartValue      float64 `json:"start_value"`
+	EndValue        float64 `json:"end_value"`
+	SignificantDiff bool    `json:"significant_diff"` // Whether change is statistically significant
 }
 
 // AnomalyResult contains anomaly detection results.
 type AnomalyResult struct {
-	Index      int
-	Time       time.Time
-	Value      float64
-	ZScore     float64
-	IsAnomaly  bool
-	Threshold  float64 // Z-score threshold used
-	Deviation  float64 // Standard deviations from mean
+	Index     int       `json:"index"`
+	Time      time.Time `json:"time"`
+	Value     float64   `json:"value"`
+	ZScore    float64   `json:"z_score"`
+	IsAnomaly bool      `json:"is_anomaly"`
+	Threshold float64   `json:"threshold"` // Z-score threshold used
+	Deviation float64   `json:"deviation"` // Standard deviations from mean
+	Baseline  float64   `json:"baseline"`  // Center the value was compared with (mean or median)
+	Spread    float64   `json:"spread"`    // Standard deviation, or robust equivalent, around Baseline
 }
 
 // SeasonalDecomposition contains seasonal decomposition components.
 type SeasonalDecomposition struct {
-	Trend      []float64
-	Seasonal   []float64
-	Residual   []float64
-	Period     int // Seasonal period
+	Trend    []float64 `json:"trend"`
+	Seasonal []float64 `json:"seasonal"`
+	Residual []float64 `json:"residual"`
+	Period   int       `json:"period"` // Seasonal period
 }
 
 // ChangeDetectionResult contains change detection results.
 type ChangeDetectionResult struct {
-	BeforeMean  float64
-	AfterMean   float64
-	Difference  float64
-	PercentDiff float64
-	TValue      float64
-	PValue      float64
-	Significant bool
-	Direction   string // "increase", "decrease", "no change"
+	BeforeMean  float64 `json:"before_mean"`
+	AfterMean   float64 `json:"after_mean"`
+	Difference  float64 `json:"difference"`
+	PercentDiff float64 `json:"percent_diff"`
+	TValue      float64 `json:"t_value"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+	Direction   string  `json:"direction"` // "increase", "decrease", "no change"
 }
 
 // AnalyzeTrend performs linear regression trend analysis on time-series data.
 //
+// The slope is fit by ordinary least squares, which outliers can skew; use
+// AnalyzeTrendRobust for short or noisy records.
+//
 // Example:
 //
 //	trend, err := helpers.AnalyzeTrend(timeSeries)
@@ -79,30 +89,94 @@ func AnalyzeTrend(ts *TimeSeries) (*TrendResult, error) {
 		return nil, fmt.Errorf("need at least 2 points for trend analysis")
 	}
 
-	// Sort by time
-	points := make([]TimeSeriesPoint, len(ts.Points))
+	points, x, y := trendData(ts)
+
+	// Calculate linear regression
+	slope, intercept, rSquared := linearRegression(x, y)
+
+	// Calculate p-value (simplified t-test)
+	pValue := calculatePValue(x, y, slope, intercept)
+
+	return trendResult(points, y, slope, intercept, rSquared, pValue), nil
+}
+
+// AnalyzeTrendRobust is like AnalyzeTrend but fits the trend with the
+// Theil-Sen estimator, which is insensitive to outliers.
+//
+// Slope and Intercept come from TheilSenSlope, RSquared is computed for that
+// line, and PValue is from the Mann-Kendall trend test, so no assumption is
+// made about the distribution of the values.
+//
+// Example:
+//
+//	trend, err := helpers.AnalyzeTrendRobust(ndviSeries)
+//	fmt.Printf("Trend: %s (%.5f per day, p = %.3f)\n",
+//	    trend.TrendDirection, trend.Slope, trend.PValue)
+func AnalyzeTrendRobust(ts *TimeSeries) (*TrendResult, error) {
+	if len(ts.Points) < 2 {
+		return nil, fmt.Errorf("need at least 2 points for trend analysis")
+	}
+
+	points, x, y := trendData(ts)
+
+	slope, intercept, _, _ := theilSen(x, y)
+	if math.IsNaN(slope) {
+		return nil, fmt.Errorf("need at least 2 distinct times for trend analysis")
+	}
+
+	// Coefficient of determination for the Theil-Sen line
+	meanY := calculateMean(y)
+	ssRes, ssTot := 0.0, 0.0
+	for i := range x {
+		residual := y[i] - (slope*x[i] + intercept)
+		ssRes += residual * residual
+		ssTot += (y[i] - meanY) * (y[i] - meanY)
+	}
+	rSquared := 0.0
+	if ssTot > 0 {
+		rSquared = math.Max(0, 1-ssRes/ssTot)
+	}
+
+	return trendResult(points, y, slope, intercept, rSquared, mannKendallPValue(y)), nil
+}
+
+// TheilSenSlope estimates a time series' trend as the median of the slopes
+// between all pairs of points, in value units per day.
+//
+// The intercept is the median of value - slope*days, with days counted from
+// the earliest point. confLow and confHigh bound a distribution-free 95%
+// confidence interval for the slope (Sen, 1968). All four are NaN if the
+// series has fewer than 2 distinct times.
+//
+// Example:
+//
+//	slope, _, low, high := helpers.TheilSenSlope(ndviSeries)
+//	fmt.Printf("%.5f per day (95%% CI %.5f to %.5f)\n", slope, low, high)
+func TheilSenSlope(ts *TimeSeries) (slope, intercept, confLow, confHigh float64) {
+	_, x, y := trendData(ts)
+	return theilSen(x, y)
+}
+
+// trendData returns a series' points sorted by time, with x in days since the
+// first point and y the values.
+func trendData(ts *TimeSeries) (points []TimeSeriesPoint, x, y []float64) {
+	points = make([]TimeSeriesPoint, len(ts.Points))
 	copy(points, ts.Points)
 	sort.Slice(points, func(i, j int) bool {
 		return points[i].Time.Before(points[j].Time)
 	})
 
-	// Convert times to days since first point
-	n := len(points)
-	x := make([]float64, n)
-	y := make([]float64, n)
-	baseTime := points[0].Time
-
+	x = make([]float64, len(points))
+	y = make([]float64, len(points))
 	for i, p := range points {
-		x[i] = p.Time.Sub(baseTime).Hours() / 24.0 // Days
+		x[i] = p.Time.Sub(points[0].Time).Hours() / 24.0 // Days
 		y[i] = p.Value
 	}
+	return points, x, y
+}
 
-	// Calculate linear regression
-	slope, intercept, rSquared := linearRegression(x, y)
-
-	// Calculate p-value (simplified t-test)
-	pValue := calculatePValue(x, y, slope, intercept)
-
+// trendResult builds a TrendResult from a fitted line.
+func trendResult(points []TimeSeriesPoint, y []float64, slope, intercept, rSquared, pValue float64) *TrendResult {
 	// Determine trend direction
 	// Use relative threshold: 1% change per day relative to mean
 	meanValue := calculateMean(y)
@@ -122,15 +196,12 @@ func AnalyzeTrend(ts *TimeSeries) (*TrendResult, error) {
 
 	// Calculate percent change
 	startValue := points[0].Value
-	endValue := points[n-1].Value
+	endValue := points[len(points)-1].Value
 	changePercent := 0.0
 	if startValue != 0 {
 		changePercent = ((endValue - startValue) / startValue) * 100
 	}
 
-	// Significant if p-value < 0.05
-	significant := pValue < 0.05
-
 	return &TrendResult{
 		Slope:           slope,
 		Intercept:       intercept,
@@ -140,8 +211,8 @@ func AnalyzeTrend(ts *TimeSeries) (*TrendResult, error) {
 		ChangePercent:   changePercent,
 		StartValue:      startValue,
 		EndValue:        endValue,
-		SignificantDiff: significant,
-	}, nil
+		SignificantDiff: pValue < 0.05, // Significant if p-value < 0.05
+	}
 }
 
 // DetectAnomalies identifies anomalous values using z-score method.
@@ -184,7 +255,182 @@ func DetectAnomalies(ts *TimeSeries, threshold float64) []AnomalyResult {
 			IsAnomaly: math.Abs(zScore) > threshold,
 			Threshold: threshold,
 			Deviation: math.Abs(zScore),
+			Baseline:  mean,
+			Spread:    stdDev,
+		}
+	}
+
+	return results
+}
+
+// DetectAnomaliesIQR identifies anomalous values using the interquartile
+// range fence: values below Q1 - multiplier*IQR or above Q3 +
+// multiplier*IQR are anomalies. Unlike DetectAnomalies, the fence does not
+// assume normality and is not dragged by the outliers themselves, so it
+// suits skewed environmental data. A multiplier of 1.5 is conventional; 3
+// flags only extreme values.
+//
+// ZScore is the robust equivalent of a z-score, the distance from the median
+// (Baseline) in units of IQR/1.349 (Spread; the standard deviation, for
+// normal data), and Deviation is its absolute value. Threshold is the
+// multiplier.
+//
+// Example:
+//
+//	anomalies := helpers.DetectAnomaliesIQR(timeSeries, 1.5)
+func DetectAnomaliesIQR(ts *TimeSeries, multiplier float64) []AnomalyResult {
+	if len(ts.Points) < 3 {
+		return nil
+	}
+
+	values := make([]float64, len(ts.Points))
+	for i, p := range ts.Points {
+		values[i] = p.Value
+	}
+
+	median := calculateMedian(values)
+	q1 := calculateQuantile(values, 0.25)
+	q3 := calculateQuantile(values, 0.75)
+	iqr := q3 - q1
+	lower, upper := q1-multiplier*iqr, q3+multiplier*iqr
+	spread := iqr / 1.349
+
+	results := make([]AnomalyResult, len(ts.Points))
+	for i, p := range ts.Points {
+		zScore := 0.0
+		if spread > 0 {
+			zScore = (p.Value - median) / spread
+		}
+
+		results[i] = AnomalyResult{
+			Index:     i,
+			Time:      p.Time,
+			Value:     p.Value,
+			ZScore:    zScore,
+			IsAnomaly: p.Value < lower || p.Value > upper,
+			Threshold: multiplier,
+			Deviation: math.Abs(zScore),
+			Baseline:  median,
+			Spread:    spread,
+		}
+	}
+
+	return results
+}
+
+// DetectAnomaliesMAD identifies anomalous values using the median absolute
+// deviation (MAD). Each value's modified z-score, 0.6745*(value -
+// median)/MAD, is used in place of the z-score, and values whose modified
+// z-score exceeds threshold in magnitude are anomalies; 3.5 is the usual
+// threshold. The median and MAD are barely moved by outliers, so unlike
+// DetectAnomalies one extreme value does not hide another.
+//
+// When more than half the values are equal, MAD is zero and the mean
+// absolute deviation (scaled by 1.2533) is used instead.
+//
+// Example:
+//
+//	anomalies := helpers.DetectAnomaliesMAD(timeSeries, 3.5)
+func DetectAnomaliesMAD(ts *TimeSeries, threshold float64) []AnomalyResult {
+	if len(ts.Points) < 3 {
+		return nil
+	}
+
+	values := make([]float64, len(ts.Points))
+	for i, p := range ts.Points {
+		values[i] = p.Value
+	}
+
+	median := calculateMedian(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	// Modified z-score = (value - median) / scale
+	var scale float64
+	if mad := calculateMedian(deviations); mad > 0 {
+		scale = mad / 0.6745
+	} else {
+		scale = 1.2533 * calculateMean(deviations)
+	}
+
+	results := make([]AnomalyResult, len(ts.Points))
+	for i, p := range ts.Points {
+		zScore := 0.0
+		if scale > 0 {
+			zScore = (p.Value - median) / scale
+		}
+
+		results[i] = AnomalyResult{
+			Index:     i,
+			Time:      p.Time,
+			Value:     p.Value,
+			ZScore:    zScore,
+			IsAnomaly: math.Abs(zScore) > threshold,
+			Threshold: threshold,
+			Deviation: math.Abs(zScore),
+			Baseline:  median,
+			Spread:    scale,
+		}
+	}
+
+	return results
+}
+
+// DetectAnomaliesRolling identifies values that deviate from their recent
+// history: each point's z-score is computed against the mean and standard
+// deviation of the window points before it, and points whose z-score
+// exceeds threshold in magnitude are anomalies. Unlike DetectAnomalies, a
+// sustained shift is flagged only while it is new, and a spike stands out
+// from its local context even in a series with a long-term trend, which
+// suits detecting fresh disturbances in a long record.
+//
+// The first window points have too little history and are never flagged;
+// their ZScore, Baseline, and Spread are zero. For the rest, Baseline and
+// Spread are the trailing window's mean and standard deviation. A point
+// after a window of identical values has a ZScore of zero. Returns nil if
+// window is less than 2 or the series has no more than window points.
+//
+// Example:
+//
+//	// Flag values more than 3 standard deviations from the previous 10
+//	anomalies := helpers.DetectAnomaliesRolling(timeSeries, 10, 3.0)
+func DetectAnomaliesRolling(ts *TimeSeries, window int, threshold float64) []AnomalyResult {
+	if window < 2 || len(ts.Points) <= window {
+		return nil
+	}
+
+	values := make([]float64, len(ts.Points))
+	for i, p := range ts.Points {
+		values[i] = p.Value
+	}
+
+	results := make([]AnomalyResult, len(ts.Points))
+	for i, p := range ts.Points {
+		results[i] = AnomalyResult{
+			Index:     i,
+			Time:      p.Time,
+			Value:     p.Value,
+			Threshold: threshold,
+		}
+		if i < window {
+			continue // Warm-up: not enough history
+		}
+
+		history := values[i-window : i]
+		mean := calculateMean(history)
+		stdDev := calculateStdDev(history, mean)
+
+		zScore := 0.0
+		if stdDev > 0 {
+			zScore = (p.Value - mean) / stdDev
 		}
+		results[i].ZScore = zScore
+		results[i].IsAnomaly = math.Abs(zScore) > threshold
+		results[i].Deviation = math.Abs(zScore)
+		results[i].Baseline = mean
+		results[i].Spread = stdDev
 	}
 
 	return results
@@ -409,6 +655,211 @@ var (
 	}
 )
 
+// LagCorrelation is the correlation between two time series at one lag.
+type LagCorrelation struct {
+	Lag         int     `json:"lag"`         // Steps b is shifted later than a; positive means b follows a
+	Period      string  `json:"period"`      // Step the series were resampled to: "day", "week", "month", or "year"
+	Correlation float64 `json:"correlation"` // Pearson correlation, or NaN with fewer than 3 pairs
+	N           int     `json:"n"`           // Number of pairs with values in both series
+	Peak        bool    `json:"peak"`        // Whether this lag has the strongest correlation
+}
+
+// CrossCorrelation computes the correlation between a and b at each lag from
+// -maxLag to maxLag, for finding delayed responses such as NDVI greening up
+// weeks after rainfall.
+//
+// Both series are resampled onto a common grid with AggregateTimeSeries, using
+// the coarser series' typical spacing snapped to a day, week, month, or year,
+// and lags are counted in that step. The series may differ in length and
+// coverage; at each lag only steps where both have a value are paired, and NaN
+// values are ignored. The lag with the largest absolute correlation is marked
+// Peak, preferring the shortest lag on ties.
+//
+// Example:
+//
+//	lags, err := helpers.CrossCorrelation(rainfall, ndvi, 6)
+//	for _, l := range lags {
+//	    if l.Peak {
+//	        fmt.Printf("NDVI follows rainfall by %d %s(s): r = %.2f (n = %d)\n",
+//	            l.Lag, l.Period, l.Correlation, l.N)
+//	    }
+//	}
+func CrossCorrelation(a, b *TimeSeries, maxLag int) ([]LagCorrelation, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("time series cannot be nil")
+	}
+	if maxLag < 0 {
+		return nil, fmt.Errorf("max lag must be non-negative, got %d", maxLag)
+	}
+
+	period, err := commonPeriod(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resample both series onto the period grid
+	gridA, err := periodValues(a, period)
+	if err != nil {
+		return nil, err
+	}
+	gridB, err := periodValues(b, period)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]LagCorrelation, 0, 2*maxLag+1)
+	peak := -1
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		var xs, ys []float64
+		for step, x := range gridA {
+			if y, ok := gridB[step+lag]; ok {
+				xs = append(xs, x)
+				ys = append(ys, y)
+			}
+		}
+
+		r := math.NaN()
+		if len(xs) >= 3 {
+			r = pearsonCorrelation(xs, ys)
+		}
+		results = append(results, LagCorrelation{Lag: lag, Period: period, Correlation: r, N: len(xs)})
+
+		if math.IsNaN(r) {
+			continue
+		}
+		if peak < 0 || math.Abs(r) > math.Abs(results[peak].Correlation) ||
+			(math.Abs(r) == math.Abs(results[peak].Correlation) && absInt(lag) < absInt(results[peak].Lag)) {
+			peak = len(results) - 1
+		}
+	}
+
+	if peak < 0 {
+		return nil, fmt.Errorf("series overlap at fewer than 3 %ss at every lag", period)
+	}
+	results[peak].Peak = true
+
+	return results, nil
+}
+
+// commonPeriod returns the period matching the typical spacing of the coarser
+// of two series.
+func commonPeriod(a, b *TimeSeries) (string, error) {
+	stepA, err := typicalSpacing(a)
+	if err != nil {
+		return "", err
+	}
+	stepB, err := typicalSpacing(b)
+	if err != nil {
+		return "", err
+	}
+	step := math.Max(stepA, stepB)
+
+	switch {
+	case step <= 3.5:
+		return "day", nil
+	case step <= 18:
+		return "week", nil
+	case step <= 180:
+		return "month", nil
+	default:
+		return "year", nil
+	}
+}
+
+// typicalSpacing returns the median interval in days between a series' points.
+func typicalSpacing(ts *TimeSeries) (float64, error) {
+	times := make([]time.Time, len(ts.Points))
+	for i, p := range ts.Points {
+		times[i] = p.Time
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	var gaps []float64
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]).Hours() / 24; gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0, fmt.Errorf("time series %q needs at least 2 distinct times", ts.Name)
+	}
+	return calculateMedian(gaps), nil
+}
+
+// periodValues resamples ts to period means, ignoring NaN values, keyed by a
+// consecutive step number.
+func periodValues(ts *TimeSeries, period string) (map[int]float64, error) {
+	resampled, err := AggregateTimeSeries(ts, period, finiteMean)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[int]float64, len(resampled.Points))
+	for _, p := range resampled.Points {
+		if !math.IsNaN(p.Value) {
+			values[periodIndex(p.Time, period)] = p.Value
+		}
+	}
+	return values, nil
+}
+
+// finiteMean is the mean of the non-NaN values, or NaN if there are none.
+func finiteMean(values []float64) float64 {
+	sum, n := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
+
+// periodIndex numbers periods consecutively, grouping times as getPeriodKey
+// does.
+func periodIndex(t time.Time, period string) int {
+	days := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	switch period {
+	case "week":
+		// ISO weeks start on Monday
+		monday := days - (int(t.Weekday())+6)%7
+		return int(math.Floor(float64(monday) / 7))
+	case "month":
+		return t.Year()*12 + int(t.Month()) - 1
+	case "year":
+		return t.Year()
+	default:
+		return days
+	}
+}
+
+// pearsonCorrelation returns the Pearson correlation of x and y, or NaN if
+// either is constant.
+func pearsonCorrelation(x, y []float64) float64 {
+	meanX, meanY := calculateMean(x), calculateMean(y)
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // FilterTimeRange filters a time series to a specific date range.
 //
 // Example:
@@ -432,31 +883,247 @@ func FilterTimeRange(ts *TimeSeries, start, end time.Time) *TimeSeries {
 	return result
 }
 
+// ToCSV formats the time series as CSV with a "time,value" header and one
+// row per point. Times are RFC 3339 (ISO 8601) and values are written at full
+// precision, with NaN for missing values.
+//
+// Example:
+//
+//	fmt.Print(ts.ToCSV())
+//	// time,value
+//	// 2023-06-01T00:00:00Z,0.62
+func (ts *TimeSeries) ToCSV() string {
+	var b strings.Builder
+	b.WriteString("time,value\n")
+	for _, p := range ts.Points {
+		b.WriteString(p.Time.Format(time.RFC3339))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(p.Value, 'g', -1, 64))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// WriteCSV writes the time series to path in the format of ToCSV.
+//
+// Example:
+//
+//	err := ts.WriteCSV("ndvi.csv")
+func (ts *TimeSeries) WriteCSV(path string) error {
+	if err := os.WriteFile(path, []byte(ts.ToCSV()), 0644); err != nil {
+		return fmt.Errorf("failed to write time series: %w", err)
+	}
+	return nil
+}
+
+// ToGeoJSON encodes the time series as a GeoJSON point feature at lat, lon.
+//
+// The feature's "series" property holds the points in order as objects with
+// "time" (RFC 3339) and "value" members, ready for plotting; NaN and infinite
+// values are written as null. The series name is the "name" property.
+//
+// Example:
+//
+//	data, err := ts.ToGeoJSON(45.5152, -122.6784)
+//	os.WriteFile("ndvi.geojson", data, 0644)
+func (ts *TimeSeries) ToGeoJSON(lat, lon float64) ([]byte, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+
+	series := make([]map[string]interface{}, len(ts.Points))
+	for i, p := range ts.Points {
+		series[i] = map[string]interface{}{
+			"time":  p.Time.Format(time.RFC3339),
+			"value": geoJSONNumber(p.Value),
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type": "Feature",
+		"geometry": map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		},
+		"properties": map[string]interface{}{
+			"name":   ts.Name,
+			"series": series,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GeoJSON: %w", err)
+	}
+
+	return data, nil
+}
+
 // TimeSeriesFromImageCollection extracts a time series from an ImageCollection.
 //
+// The value of bandName at the point is read from every image in the
+// collection, using each image's system:time_start as its time. Images where
+// the point is masked are skipped. Points are sorted by time, and Index is the
+// image's position in the collection. The collection's native scale is used
+// unless the collection is first reprojected.
+//
 // Example:
 //
 //	ts, err := helpers.TimeSeriesFromImageCollection(ctx, client, collection,
 //	    lat, lon, "NDVI")
 func TimeSeriesFromImageCollection(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, lat, lon float64, bandName string) (*TimeSeries, error) {
-	// In a real implementation, this would:
-	// 1. Get the list of images in the collection
-	// 2. Extract the value at the point for each image
-	// 3. Get the timestamp for each image
-	// 4. Build the time series
-
-	// For now, return a placeholder
-	_ = ctx
-	_ = client
-	_ = collection
-	_ = lat
-	_ = lon
-	_ = bandName
-
-	return &TimeSeries{
+	return timeSeriesAtPoint(ctx, collection, lat, lon, bandName, defaultImageryScale)
+}
+
+// timeSeriesAtPoint reads bandName at a point from every image in collection
+// at the given scale.
+func timeSeriesAtPoint(ctx context.Context, collection *earthengine.ImageCollection, lat, lon float64, bandName string, scale float64) (*TimeSeries, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection cannot be nil")
+	}
+
+	rows, err := collection.Select(bandName).GetRegion(ctx, earthengine.NewPoint(lon, lat), scale)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &TimeSeries{
 		Name:   bandName,
 		Points: []TimeSeriesPoint{},
-	}, nil
+	}
+	if len(rows) == 0 {
+		return ts, nil
+	}
+
+	// Locate the time and band columns from the header row
+	timeCol, valueCol := -1, -1
+	for i, name := range rows[0] {
+		switch name {
+		case "time":
+			timeCol = i
+		case bandName:
+			valueCol = i
+		}
+	}
+	if timeCol < 0 || valueCol < 0 {
+		return nil, fmt.Errorf("region result missing time or %s column: %v", bandName, rows[0])
+	}
+
+	for i, row := range rows[1:] {
+		if len(row) <= timeCol || len(row) <= valueCol {
+			return nil, fmt.Errorf("region row %d has %d columns, want at least %d", i, len(row), len(rows[0]))
+		}
+		millis, ok := row[timeCol].(float64)
+		if !ok {
+			continue // Image without a timestamp
+		}
+		value, ok := row[valueCol].(float64)
+		if !ok {
+			continue // Masked at this point
+		}
+
+		ts.Points = append(ts.Points, TimeSeriesPoint{
+			Time:  time.UnixMilli(int64(millis)).UTC(),
+			Value: value,
+			Index: i,
+		})
+	}
+
+	sort.SliceStable(ts.Points, func(i, j int) bool {
+		return ts.Points[i].Time.Before(ts.Points[j].Time)
+	})
+
+	return ts, nil
+}
+
+// PointQueryFunc is a point helper that reads one value at a location on a
+// date, such as NDVIWithContext, EVIWithContext, SAVIWithContext,
+// NDWIWithContext, NDBIWithContext, NDMIWithContext, or
+// BurnSeverityWithContext.
+type PointQueryFunc func(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error)
+
+// PointTimeSeries runs fn at a location on each of dates, in parallel, and
+// returns the values as a time series for AnalyzeTrend, DetectAnomalies, and
+// the other time-series functions. opts are passed to every call of fn.
+//
+// Points are sorted by date, and Index is the date's position in dates. A
+// date whose query fails, for example because no clear scene is found, is
+// left out of the series; the series of the remaining dates is returned with
+// an error listing the failed dates. The series has no Name; set it if
+// needed.
+//
+// Example:
+//
+//	dates := []string{"2022-01-15", "2022-02-15", "2022-03-15" /* ... */}
+//	ts, err := helpers.PointTimeSeries(ctx, client, 45.5152, -122.6784, dates,
+//	    helpers.NDVIWithContext, helpers.Sentinel2())
+//	trend, err := helpers.AnalyzeTrend(ts)
+func PointTimeSeries(ctx context.Context, client *earthengine.Client, lat, lon float64, dates []string, fn PointQueryFunc, opts ...ImageryOption) (*TimeSeries, error) {
+	if err := validateCoordinates(lat, lon); err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("query function cannot be nil")
+	}
+
+	times := make([]time.Time, len(dates))
+	for i, date := range dates {
+		t, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q (use YYYY-MM-DD): %w", date, err)
+		}
+		times[i] = t
+	}
+
+	ts := &TimeSeries{Points: []TimeSeriesPoint{}}
+	if len(dates) == 0 {
+		return ts, nil
+	}
+
+	batch := NewBatch(client, 0)
+	for _, date := range dates {
+		batch.Add(pointDateQuery{lat: lat, lon: lon, date: date, fn: fn, opts: opts})
+	}
+	results, err := batch.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for i, r := range results {
+		if r.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dates[i], r.Error))
+			continue
+		}
+		ts.Points = append(ts.Points, TimeSeriesPoint{
+			Time:  times[i],
+			Value: r.Value.(float64),
+			Index: i,
+		})
+	}
+
+	sort.SliceStable(ts.Points, func(i, j int) bool {
+		return ts.Points[i].Time.Before(ts.Points[j].Time)
+	})
+
+	if len(errs) > 0 {
+		return ts, fmt.Errorf("%d of %d dates failed: %w", len(errs), len(dates), errors.Join(errs...))
+	}
+	return ts, nil
+}
+
+// pointDateQuery runs a PointQueryFunc for one date of a batch.
+type pointDateQuery struct {
+	lat, lon float64
+	date     string
+	fn       PointQueryFunc
+	opts     []ImageryOption
+}
+
+// Execute implements Query.
+func (q pointDateQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	return q.fn(ctx, client, q.lat, q.lon, q.date, q.opts...)
 }
 
 // Helper functions
@@ -557,6 +1224,85 @@ func calculatePValue(x, y []float64, slope, intercept float64) float64 {
 	return pValue
 }
 
+// theilSen returns the Theil-Sen slope and intercept of y against x, with a
+// 95% confidence interval for the slope.
+func theilSen(x, y []float64) (slope, intercept, confLow, confHigh float64) {
+	n := len(x)
+	var slopes []float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if x[j] != x[i] {
+				slopes = append(slopes, (y[j]-y[i])/(x[j]-x[i]))
+			}
+		}
+	}
+	if len(slopes) == 0 {
+		nan := math.NaN()
+		return nan, nan, nan, nan
+	}
+	sort.Float64s(slopes)
+	slope = calculateMedian(slopes)
+
+	residuals := make([]float64, n)
+	for i := range x {
+		residuals[i] = y[i] - slope*x[i]
+	}
+	intercept = calculateMedian(residuals)
+
+	// Ranks of the interval bounds among the sorted slopes, from the normal
+	// approximation to the Kendall statistic's variance
+	total := float64(len(slopes))
+	c := 1.96 * math.Sqrt(float64(n*(n-1)*(2*n+5))/18)
+	lower := int(math.Round((total - c) / 2))
+	upper := int(math.Round((total+c)/2)) + 1
+	confLow = slopes[clampInt(lower-1, 0, len(slopes)-1)]
+	confHigh = slopes[clampInt(upper-1, 0, len(slopes)-1)]
+
+	return slope, intercept, confLow, confHigh
+}
+
+// mannKendallPValue returns the two-tailed p-value of the Mann-Kendall test
+// for a monotonic trend in values, which must be in time order.
+func mannKendallPValue(values []float64) float64 {
+	n := len(values)
+	if n < 3 {
+		return 1.0
+	}
+
+	s := 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case values[j] > values[i]:
+				s++
+			case values[j] < values[i]:
+				s--
+			}
+		}
+	}
+
+	// Normal approximation with continuity correction
+	sd := math.Sqrt(float64(n*(n-1)*(2*n+5)) / 18)
+	z := 0.0
+	switch {
+	case s > 0:
+		z = (s - 1) / sd
+	case s < 0:
+		z = (s + 1) / sd
+	}
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
 func tDistributionCDF(t, df float64) float64 {
 	// Simplified approximation of t-distribution CDF
 	// For production, use a proper statistical library
@@ -596,6 +1342,24 @@ func calculateMedian(values []float64) float64 {
 	return sorted[n/2]
 }
 
+// calculateQuantile returns the q quantile (0-1) of values, interpolating
+// linearly between the nearest ranks.
+func calculateQuantile(values []float64, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
 func calculateStdDev(values []float64, mean float64) float64 {
 	if len(values) <= 1 {
 		return 0
@@ -1,9 +1,20 @@
 package helpers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/alexscott64/go-earthengine"
 )
 
 func TestAnalyzeTrend(t *testing.T) {
@@ -159,6 +170,152 @@ func TestDetectAnomaliesInsufficientData(t *testing.T) {
 	}
 }
 
+// skewedTestSeries returns a series of values around 11 with two large
+// outliers, at indices 5 and 10, that inflate its standard deviation.
+func skewedTestSeries() *TimeSeries {
+	values := []float64{10, 11, 12, 10.5, 11.5, 95, 10.2, 11.8, 10.8, 11.2, 100, 10.6}
+	points := make([]TimeSeriesPoint, len(values))
+	for i, v := range values {
+		points[i] = TimeSeriesPoint{Time: time.Date(2023, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC), Value: v}
+	}
+	return &TimeSeries{Name: "Skewed", Points: points}
+}
+
+// anomalyIndices returns the indices of the results marked as anomalies.
+func anomalyIndices(results []AnomalyResult) []int {
+	var indices []int
+	for _, r := range results {
+		if r.IsAnomaly {
+			indices = append(indices, r.Index)
+		}
+	}
+	return indices
+}
+
+func TestDetectAnomaliesIQR(t *testing.T) {
+	ts := skewedTestSeries()
+
+	// The outliers inflate the standard deviation enough to hide themselves
+	if got := anomalyIndices(DetectAnomalies(ts, 3)); len(got) != 0 {
+		t.Fatalf("z-score anomalies = %v, expected the outliers to be masked", got)
+	}
+
+	results := DetectAnomaliesIQR(ts, 1.5)
+	if len(results) != len(ts.Points) {
+		t.Fatalf("Got %d results, want %d", len(results), len(ts.Points))
+	}
+	if got := anomalyIndices(results); !reflect.DeepEqual(got, []int{5, 10}) {
+		t.Errorf("anomalies = %v, want [5 10]", got)
+	}
+
+	// Q1 = 10.575, Q3 = 11.85, median = 11.1
+	wantZ := (100 - 11.1) / (1.275 / 1.349)
+	if math.Abs(results[10].ZScore-wantZ) > 1e-9 {
+		t.Errorf("ZScore = %v, want %v", results[10].ZScore, wantZ)
+	}
+	if results[10].Deviation != math.Abs(results[10].ZScore) || results[10].Threshold != 1.5 {
+		t.Errorf("result = %+v", results[10])
+	}
+}
+
+func TestDetectAnomaliesMAD(t *testing.T) {
+	ts := skewedTestSeries()
+
+	results := DetectAnomaliesMAD(ts, 3.5)
+	if got := anomalyIndices(results); !reflect.DeepEqual(got, []int{5, 10}) {
+		t.Errorf("anomalies = %v, want [5 10]", got)
+	}
+
+	// Median 11.1, MAD 0.65
+	wantZ := 0.6745 * (10 - 11.1) / 0.65
+	if math.Abs(results[0].ZScore-wantZ) > 1e-9 {
+		t.Errorf("modified z-score = %v, want %v", results[0].ZScore, wantZ)
+	}
+}
+
+func TestDetectAnomaliesMADZeroMAD(t *testing.T) {
+	ts := &TimeSeries{
+		Name: "Mostly Constant",
+		Points: []TimeSeriesPoint{
+			{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Value: 5},
+			{Time: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Value: 5},
+			{Time: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC), Value: 5},
+			{Time: time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC), Value: 5},
+			{Time: time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC), Value: 9},
+		},
+	}
+
+	// Falls back to the mean absolute deviation, 0.8
+	if got := anomalyIndices(DetectAnomaliesMAD(ts, 3.5)); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("anomalies = %v, want [4]", got)
+	}
+}
+
+func TestRobustAnomaliesInsufficientData(t *testing.T) {
+	ts := &TimeSeries{
+		Name:   "Too Short",
+		Points: []TimeSeriesPoint{{Time: time.Now(), Value: 10.0}},
+	}
+
+	if DetectAnomaliesIQR(ts, 1.5) != nil {
+		t.Error("Expected nil IQR results for insufficient data")
+	}
+	if DetectAnomaliesMAD(ts, 3.5) != nil {
+		t.Error("Expected nil MAD results for insufficient data")
+	}
+}
+
+func TestDetectAnomaliesRolling(t *testing.T) {
+	// A noisy plateau that steps up at index 12 and spikes at index 20
+	values := []float64{10, 11, 10, 11, 10, 11, 10, 11, 10, 11, 10, 11,
+		30, 31, 30, 31, 30, 31, 30, 31, 60, 31}
+	points := make([]TimeSeriesPoint, len(values))
+	for i, v := range values {
+		points[i] = TimeSeriesPoint{Time: time.Date(2023, 1, i+1, 0, 0, 0, 0, time.UTC), Value: v}
+	}
+	ts := &TimeSeries{Name: "Step", Points: points}
+
+	results := DetectAnomaliesRolling(ts, 6, 3)
+	if len(results) != len(values) {
+		t.Fatalf("Got %d results, want %d", len(results), len(values))
+	}
+	// The step is flagged where it occurs, then joins the baseline
+	if got := anomalyIndices(results); !reflect.DeepEqual(got, []int{12, 20}) {
+		t.Errorf("anomalies = %v, want [12 20]", got)
+	}
+
+	// Warm-up points are not flagged and have no baseline
+	for _, r := range results[:6] {
+		if r.IsAnomaly || r.Baseline != 0 || r.Spread != 0 {
+			t.Errorf("warm-up result %d = %+v", r.Index, r)
+		}
+	}
+
+	// The spike is compared with the previous 6 values, 30 and 31
+	if results[20].Baseline != 30.5 {
+		t.Errorf("Baseline = %v, want 30.5", results[20].Baseline)
+	}
+	if want := calculateStdDev([]float64{31, 30, 31, 30, 31, 30}, 30.5); results[20].Spread != want {
+		t.Errorf("Spread = %v, want %v", results[20].Spread, want)
+	}
+
+	// A global z-score flags the whole plateau or nothing
+	if got := anomalyIndices(DetectAnomalies(ts, 3)); len(got) != 0 {
+		t.Errorf("global anomalies = %v, want none", got)
+	}
+}
+
+func TestDetectAnomaliesRollingInsufficientData(t *testing.T) {
+	ts := skewedTestSeries()
+
+	if DetectAnomaliesRolling(ts, len(ts.Points), 3) != nil {
+		t.Error("Expected nil when the series is no longer than the window")
+	}
+	if DetectAnomaliesRolling(ts, 1, 3) != nil {
+		t.Error("Expected nil for a window of 1")
+	}
+}
+
 func TestDecomposeTimeSeries(t *testing.T) {
 	// Create data with clear seasonal pattern
 	points := make([]TimeSeriesPoint, 24)
@@ -516,3 +673,369 @@ func TestGetPeriodKey(t *testing.T) {
 		t.Errorf("Year key = %s, want 2023", yearKey)
 	}
 }
+
+func TestTimeSeriesFromImageCollectionMissingBand(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": [["id", "longitude", "latitude", "time", "B4"]]}`)
+
+	_, err := TimeSeriesFromImageCollection(context.Background(), client, client.ImageCollection("test"), 45.0, -122.0, "NDVI")
+	if err == nil || !strings.Contains(err.Error(), "missing time or NDVI column") {
+		t.Errorf("error = %v, want missing column error", err)
+	}
+}
+
+func exportTestSeries() *TimeSeries {
+	return &TimeSeries{
+		Name: "NDVI",
+		Points: []TimeSeriesPoint{
+			{Time: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), Value: 0.62},
+			{Time: time.Date(2023, 6, 17, 18, 30, 0, 0, time.UTC), Value: math.NaN()},
+			{Time: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Value: 0.7},
+		},
+	}
+}
+
+func TestTimeSeriesToCSV(t *testing.T) {
+	want := "time,value\n" +
+		"2023-06-01T00:00:00Z,0.62\n" +
+		"2023-06-17T18:30:00Z,NaN\n" +
+		"2023-07-03T00:00:00Z,0.7\n"
+	if got := exportTestSeries().ToCSV(); got != want {
+		t.Errorf("ToCSV() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := (&TimeSeries{}).ToCSV(); got != "time,value\n" {
+		t.Errorf("empty ToCSV() = %q, want header only", got)
+	}
+}
+
+func TestTimeSeriesWriteCSV(t *testing.T) {
+	ts := exportTestSeries()
+	path := filepath.Join(t.TempDir(), "ndvi.csv")
+	if err := ts.WriteCSV(path); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != ts.ToCSV() {
+		t.Errorf("file contents = %q, want %q", data, ts.ToCSV())
+	}
+
+	if err := ts.WriteCSV(filepath.Join(t.TempDir(), "missing", "ndvi.csv")); err == nil {
+		t.Error("Expected error writing to a missing directory")
+	}
+}
+
+func TestTimeSeriesToGeoJSON(t *testing.T) {
+	data, err := exportTestSeries().ToGeoJSON(45.5152, -122.6784)
+	if err != nil {
+		t.Fatalf("ToGeoJSON failed: %v", err)
+	}
+
+	var feature struct {
+		Type     string
+		Geometry struct {
+			Type        string
+			Coordinates []float64
+		}
+		Properties struct {
+			Name   string
+			Series []struct {
+				Time  string
+				Value *float64
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &feature); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if feature.Type != "Feature" || feature.Geometry.Type != "Point" {
+		t.Errorf("type = %s, geometry type = %s", feature.Type, feature.Geometry.Type)
+	}
+	if c := feature.Geometry.Coordinates; len(c) != 2 || c[0] != -122.6784 || c[1] != 45.5152 {
+		t.Errorf("coordinates = %v, want [lon, lat]", c)
+	}
+	if feature.Properties.Name != "NDVI" {
+		t.Errorf("name = %q, want NDVI", feature.Properties.Name)
+	}
+
+	series := feature.Properties.Series
+	if len(series) != 3 {
+		t.Fatalf("len(series) = %d, want 3", len(series))
+	}
+	if series[0].Time != "2023-06-01T00:00:00Z" || series[0].Value == nil || *series[0].Value != 0.62 {
+		t.Errorf("series[0] = %+v", series[0])
+	}
+	if series[1].Value != nil {
+		t.Errorf("series[1].Value = %v, want null for NaN", *series[1].Value)
+	}
+
+	if _, err := exportTestSeries().ToGeoJSON(91, 0); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+}
+
+func TestCrossCorrelation(t *testing.T) {
+	// Monthly rainfall, and NDVI responding two months later over a shorter,
+	// gappier record
+	rain := []float64{10, 80, 30, 55, 5, 95, 40, 20, 70, 15, 60, 35, 90, 25, 50, 45, 85, 0, 65, 30}
+	a := &TimeSeries{Name: "rain"}
+	b := &TimeSeries{Name: "ndvi"}
+	for i, v := range rain {
+		a.Points = append(a.Points, TimeSeriesPoint{Time: time.Date(2020, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC), Value: v})
+		if i >= 3 && i < 18 {
+			value := 0.2 + v/200
+			if i == 10 {
+				value = math.NaN()
+			}
+			// Observed mid-month, twice for some months
+			month := time.Date(2020, time.Month(i+3), 10, 0, 0, 0, 0, time.UTC)
+			b.Points = append(b.Points, TimeSeriesPoint{Time: month, Value: value})
+			if i%4 == 0 {
+				b.Points = append(b.Points, TimeSeriesPoint{Time: month.AddDate(0, 0, 10), Value: value})
+			}
+		}
+	}
+
+	lags, err := CrossCorrelation(a, b, 4)
+	if err != nil {
+		t.Fatalf("CrossCorrelation failed: %v", err)
+	}
+
+	if len(lags) != 9 {
+		t.Fatalf("len(lags) = %d, want 9", len(lags))
+	}
+	var peaks []LagCorrelation
+	for _, l := range lags {
+		if l.Period != "month" {
+			t.Errorf("Period = %q, want month", l.Period)
+		}
+		if l.Peak {
+			peaks = append(peaks, l)
+		}
+	}
+	if len(peaks) != 1 {
+		t.Fatalf("got %d peaks, want 1", len(peaks))
+	}
+	peak := peaks[0]
+	if peak.Lag != 2 {
+		t.Errorf("peak lag = %d, want 2", peak.Lag)
+	}
+	if math.Abs(peak.Correlation-1) > 1e-9 {
+		t.Errorf("peak correlation = %v, want 1", peak.Correlation)
+	}
+	if peak.N != 14 {
+		t.Errorf("peak N = %d, want 14 (15 months less one NaN)", peak.N)
+	}
+}
+
+func TestCrossCorrelationErrors(t *testing.T) {
+	day := func(d int, v float64) TimeSeriesPoint {
+		return TimeSeriesPoint{Time: time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC), Value: v}
+	}
+	series := &TimeSeries{Points: []TimeSeriesPoint{day(1, 1), day(2, 2), day(3, 4), day(4, 3)}}
+
+	if _, err := CrossCorrelation(series, nil, 1); err == nil {
+		t.Error("Expected error for nil series")
+	}
+	if _, err := CrossCorrelation(series, series, -1); err == nil {
+		t.Error("Expected error for negative max lag")
+	}
+	if _, err := CrossCorrelation(series, &TimeSeries{Points: []TimeSeriesPoint{day(1, 1)}}, 1); err == nil {
+		t.Error("Expected error for single-point series")
+	}
+
+	later := &TimeSeries{Points: []TimeSeriesPoint{day(20, 1), day(21, 2), day(22, 4), day(23, 3)}}
+	if _, err := CrossCorrelation(series, later, 2); err == nil {
+		t.Error("Expected error for series that don't overlap")
+	}
+}
+
+func TestPeriodIndex(t *testing.T) {
+	// Sunday and the following Monday are in different ISO weeks
+	sunday := time.Date(2023, 1, 8, 12, 0, 0, 0, time.UTC)
+	monday := sunday.AddDate(0, 0, 1)
+	if periodIndex(monday, "week")-periodIndex(sunday, "week") != 1 {
+		t.Error("Monday should start a new week")
+	}
+	if periodIndex(sunday, "week") != periodIndex(sunday.AddDate(0, 0, -6), "week") {
+		t.Error("Monday through Sunday should share a week")
+	}
+	if periodIndex(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "month")-periodIndex(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), "month") != 1 {
+		t.Error("months should be consecutive across years")
+	}
+}
+
+// outlierSeries is value = 2*day + 1 over 10 days, with a spike on day 7.
+func outlierSeries() *TimeSeries {
+	ts := &TimeSeries{Name: "outlier"}
+	for day := 0; day < 10; day++ {
+		value := 2*float64(day) + 1
+		if day == 7 {
+			value = 200
+		}
+		ts.Points = append(ts.Points, TimeSeriesPoint{
+			Time:  time.Date(2023, 1, 1+day, 0, 0, 0, 0, time.UTC),
+			Value: value,
+		})
+	}
+	return ts
+}
+
+func TestTheilSenSlope(t *testing.T) {
+	slope, intercept, low, high := TheilSenSlope(outlierSeries())
+
+	if slope != 2 {
+		t.Errorf("slope = %v, want 2", slope)
+	}
+	if intercept != 1 {
+		t.Errorf("intercept = %v, want 1", intercept)
+	}
+	if low > slope || high < slope {
+		t.Errorf("confidence interval [%v, %v] does not bracket %v", low, high, slope)
+	}
+
+	// Noisy data gives a proper interval around the trend
+	noisy := &TimeSeries{}
+	for day := 0; day < 12; day++ {
+		noise := 3.0
+		if day%2 == 1 {
+			noise = -3
+		}
+		noisy.Points = append(noisy.Points, TimeSeriesPoint{
+			Time:  time.Date(2023, 1, 1+day, 0, 0, 0, 0, time.UTC),
+			Value: float64(day) + noise,
+		})
+	}
+	slope, _, low, high = TheilSenSlope(noisy)
+	if low >= high || low > slope || high < slope {
+		t.Errorf("noisy slope %v, interval [%v, %v], want an interval containing 1", slope, low, high)
+	}
+
+	// Too few distinct times
+	single := &TimeSeries{Points: outlierSeries().Points[:1]}
+	if slope, _, _, _ := TheilSenSlope(single); !math.IsNaN(slope) {
+		t.Errorf("single-point slope = %v, want NaN", slope)
+	}
+}
+
+func TestAnalyzeTrendRobust(t *testing.T) {
+	ts := outlierSeries()
+
+	ols, err := AnalyzeTrend(ts)
+	if err != nil {
+		t.Fatalf("AnalyzeTrend failed: %v", err)
+	}
+	robust, err := AnalyzeTrendRobust(ts)
+	if err != nil {
+		t.Fatalf("AnalyzeTrendRobust failed: %v", err)
+	}
+
+	if robust.Slope != 2 {
+		t.Errorf("robust slope = %v, want 2", robust.Slope)
+	}
+	if math.Abs(ols.Slope-2) < 1 {
+		t.Errorf("OLS slope = %v, expected the outlier to skew it", ols.Slope)
+	}
+	if robust.TrendDirection != "increasing" {
+		t.Errorf("TrendDirection = %s, want increasing", robust.TrendDirection)
+	}
+	if !robust.SignificantDiff || robust.PValue >= 0.05 {
+		t.Errorf("PValue = %v, want significant", robust.PValue)
+	}
+	if robust.StartValue != 1 || robust.EndValue != 19 {
+		t.Errorf("StartValue = %v, EndValue = %v, want 1, 19", robust.StartValue, robust.EndValue)
+	}
+
+	if _, err := AnalyzeTrendRobust(&TimeSeries{Points: ts.Points[:1]}); err == nil {
+		t.Error("Expected error for single point")
+	}
+	sameTime := &TimeSeries{Points: []TimeSeriesPoint{{Time: ts.Points[0].Time, Value: 1}, {Time: ts.Points[0].Time, Value: 2}}}
+	if _, err := AnalyzeTrendRobust(sameTime); err == nil {
+		t.Error("Expected error for points at one time")
+	}
+}
+
+func TestMannKendallPValue(t *testing.T) {
+	if p := mannKendallPValue([]float64{1, 2, 3, 4, 5, 6, 7, 8}); p >= 0.01 {
+		t.Errorf("monotonic p = %v, want < 0.01", p)
+	}
+	if p := mannKendallPValue([]float64{3, 1, 4, 1, 5, 9, 2, 6}); p < 0.05 {
+		t.Errorf("noisy p = %v, want >= 0.05", p)
+	}
+}
+
+func TestPointTimeSeries(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	fn := func(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+		mu.Lock()
+		seen = append(seen, date)
+		mu.Unlock()
+		if len(opts) != 1 {
+			t.Errorf("got %d options, want 1", len(opts))
+		}
+		if date == "2023-02-15" {
+			return 0, ErrNoImages
+		}
+		month, _ := strconv.Atoi(date[5:7])
+		return float64(month) / 10, nil
+	}
+
+	dates := []string{"2023-03-15", "2023-01-15", "2023-02-15"}
+	ts, err := PointTimeSeries(context.Background(), &earthengine.Client{}, 45.5, -122.6, dates, fn, Sentinel2())
+	if !errors.Is(err, ErrNoImages) || !strings.Contains(err.Error(), "2023-02-15") {
+		t.Errorf("err = %v, want failure of 2023-02-15", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("queried %d dates, want 3", len(seen))
+	}
+
+	if len(ts.Points) != 2 {
+		t.Fatalf("got %d points, want 2", len(ts.Points))
+	}
+	want := []TimeSeriesPoint{
+		{Time: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), Value: 0.1, Index: 1},
+		{Time: time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC), Value: 0.3, Index: 0},
+	}
+	for i, p := range ts.Points {
+		if !p.Time.Equal(want[i].Time) || p.Value != want[i].Value || p.Index != want[i].Index {
+			t.Errorf("point %d = %+v, want %+v", i, p, want[i])
+		}
+	}
+}
+
+func TestPointTimeSeriesNDVI(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{"result": {"nd": 0.62}}`)
+
+	ts, err := PointTimeSeries(context.Background(), client, 45.5, -122.6,
+		[]string{"2023-06-01", "2023-07-01"}, NDVIWithContext)
+	if err != nil {
+		t.Fatalf("PointTimeSeries failed: %v", err)
+	}
+	if len(ts.Points) != 2 || ts.Points[0].Value != 0.62 {
+		t.Errorf("points = %+v", ts.Points)
+	}
+}
+
+func TestPointTimeSeriesErrors(t *testing.T) {
+	fn := func(ctx context.Context, client *earthengine.Client, lat, lon float64, date string, opts ...ImageryOption) (float64, error) {
+		t.Error("query should not run")
+		return 0, nil
+	}
+	client := &earthengine.Client{}
+	ctx := context.Background()
+
+	if _, err := PointTimeSeries(ctx, client, 91, 0, []string{"2023-01-01"}, fn); err == nil {
+		t.Error("expected error for invalid latitude")
+	}
+	if _, err := PointTimeSeries(ctx, client, 45, 0, []string{"2023-01-01"}, nil); err == nil {
+		t.Error("expected error for nil query function")
+	}
+	if _, err := PointTimeSeries(ctx, client, 45, 0, []string{"2023-01-01", "Jan 2"}, fn); err == nil {
+		t.Error("expected error for invalid date")
+	}
+}
@@ -0,0 +1,190 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/alexscott64/go-earthengine"
+	"github.com/alexscott64/go-earthengine/apiv1"
+)
+
+// maxThumbnailDimension is the largest width or height computePixels allows.
+const maxThumbnailDimension = 32768
+
+// maxVideoDimension is the largest frame width or height of a video export,
+// the limit of common H.264 decoders.
+const maxVideoDimension = 4096
+
+// maxVideoFPS is the highest frame rate Earth Engine encodes.
+const maxVideoFPS = 100
+
+// VisualizationOptions controls how an image is rendered to 8-bit RGB.
+type VisualizationOptions struct {
+	// Bands to render: one band, optionally with a Palette, or three bands
+	// as red, green, and blue. All bands are rendered if empty.
+	Bands []string
+
+	// Min and Max are the values mapped to 0 and 255 in every band
+	Min float64
+	Max float64
+
+	// Palette is a list of CSS-style colors, such as "ffffff" or "#00ff00",
+	// for a single band
+	Palette []string
+
+	// Gamma correction applied to every band; 0 means no correction
+	Gamma float64
+}
+
+// Thumbnail renders an image over bounds as a PNG, width pixels wide. The
+// height follows the aspect ratio of bounds in longitude and latitude.
+//
+// Example:
+//
+//	// True color Sentinel-2
+//	png, err := helpers.Thumbnail(ctx, client, composite, bounds, 512,
+//	    helpers.VisualizationOptions{Bands: []string{"B4", "B3", "B2"}, Min: 0, Max: 3000})
+//
+//	// NDVI with a palette
+//	png, err = helpers.Thumbnail(ctx, client, ndvi, bounds, 512,
+//	    helpers.VisualizationOptions{Min: 0, Max: 1, Palette: []string{"brown", "yellow", "green"}})
+//	err = os.WriteFile("ndvi.png", png, 0o644)
+func Thumbnail(ctx context.Context, client *earthengine.Client, image *earthengine.Image, bounds Bounds, width int, vis VisualizationOptions) ([]byte, error) {
+	_ = client
+
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bounds: %w", err)
+	}
+	if width < 1 || width > maxThumbnailDimension {
+		return nil, fmt.Errorf("width must be between 1 and %d, got %d", maxThumbnailDimension, width)
+	}
+	if err := vis.validate(); err != nil {
+		return nil, err
+	}
+
+	// Keep pixels square in degrees
+	lonSpan := bounds.MaxLon - bounds.MinLon
+	latSpan := bounds.MaxLat - bounds.MinLat
+	height := int(math.Max(1, math.Round(float64(width)*latSpan/lonSpan)))
+	if height > maxThumbnailDimension {
+		return nil, fmt.Errorf("height of %d pixels exceeds %d; use a smaller width", height, maxThumbnailDimension)
+	}
+
+	grid := &apiv1.PixelGrid{
+		CrsCode: "EPSG:4326",
+		AffineTransform: &apiv1.AffineTransform{
+			ScaleX:     lonSpan / float64(width),
+			TranslateX: bounds.MinLon,
+			ScaleY:     -latSpan / float64(height),
+			TranslateY: bounds.MaxLat,
+		},
+		Dimensions: &apiv1.GridDimensions{Width: int64(width), Height: int64(height)},
+	}
+
+	params := vis.params()
+	params["grid"] = grid
+
+	png, err := image.ComputePixels(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute thumbnail: %w", err)
+	}
+
+	return png, nil
+}
+
+// GetTileURL registers image for map display and returns an XYZ tile URL
+// template containing {z}, {x}, and {y}, for use with Leaflet, Mapbox, or
+// OpenLayers.
+//
+// This is the equivalent of getMapId in the Python and JavaScript clients.
+// Tile URLs are temporary: Earth Engine expires them after a few hours, so
+// generate a new URL for each session rather than storing it. Tile requests
+// must be authorized with the same credentials as client.
+//
+// Example:
+//
+//	url, err := helpers.GetTileURL(ctx, client, ndvi,
+//	    helpers.VisualizationOptions{Min: 0, Max: 1, Palette: []string{"brown", "yellow", "green"}})
+//	// https://earthengine.googleapis.com/v1/projects/my-project/maps/.../tiles/{z}/{x}/{y}
+func GetTileURL(ctx context.Context, client *earthengine.Client, image *earthengine.Image, vis VisualizationOptions) (urlTemplate string, err error) {
+	_ = client
+
+	if image == nil {
+		return "", fmt.Errorf("image cannot be nil")
+	}
+	if err := vis.validate(); err != nil {
+		return "", err
+	}
+
+	mapID, err := image.CreateMap(ctx, vis.params())
+	if err != nil {
+		return "", fmt.Errorf("failed to create map: %w", err)
+	}
+
+	return mapID.TileURL, nil
+}
+
+// validate checks that the options can be rendered.
+func (vis VisualizationOptions) validate() error {
+	if vis.Max <= vis.Min {
+		return fmt.Errorf("max (%g) must be greater than min (%g)", vis.Max, vis.Min)
+	}
+	if len(vis.Bands) != 0 && len(vis.Bands) != 1 && len(vis.Bands) != 3 {
+		return fmt.Errorf("need 1 or 3 bands to render, got %d", len(vis.Bands))
+	}
+	if len(vis.Palette) > 0 && len(vis.Bands) > 1 {
+		return fmt.Errorf("palette requires a single band, got %d", len(vis.Bands))
+	}
+	if vis.Gamma < 0 {
+		return fmt.Errorf("gamma must not be negative, got %g", vis.Gamma)
+	}
+	return nil
+}
+
+// params returns the PNG rendering parameters for a computePixels or maps
+// request.
+func (vis VisualizationOptions) params() map[string]interface{} {
+	// Ranges and gamma are per band; a palette renders from one range
+	bandCount := len(vis.Bands)
+	if bandCount == 0 || len(vis.Palette) > 0 {
+		bandCount = 1
+	}
+	visualization := &apiv1.VisualizationOptions{Palette: vis.Palette}
+	for i := 0; i < bandCount; i++ {
+		visualization.Ranges = append(visualization.Ranges, &apiv1.ValueRange{Min: vis.Min, Max: vis.Max})
+		if vis.Gamma > 0 {
+			visualization.Gamma = append(visualization.Gamma, vis.Gamma)
+		}
+	}
+
+	params := map[string]interface{}{
+		"fileFormat":           "PNG",
+		"visualizationOptions": visualization,
+	}
+	if len(vis.Bands) > 0 {
+		params["bandIds"] = vis.Bands
+	}
+	return params
+}
+
+// visualizeParams returns the equivalent Image.visualize arguments.
+func (vis VisualizationOptions) visualizeParams() map[string]interface{} {
+	params := map[string]interface{}{
+		"min": vis.Min,
+		"max": vis.Max,
+	}
+	if len(vis.Bands) > 0 {
+		params["bands"] = vis.Bands
+	}
+	if len(vis.Palette) > 0 {
+		params["palette"] = vis.Palette
+	}
+	if vis.Gamma > 0 {
+		params["gamma"] = vis.Gamma
+	}
+	return params
+}
@@ -0,0 +1,124 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/go-earthengine"
+)
+
+func TestThumbnail(t *testing.T) {
+	var path string
+	var body struct {
+		FileFormat string   `json:"fileFormat"`
+		BandIds    []string `json:"bandIds"`
+		Grid       struct {
+			CrsCode         string             `json:"crsCode"`
+			AffineTransform map[string]float64 `json:"affineTransform"`
+			Dimensions      struct {
+				Width  string `json:"width"`
+				Height string `json:"height"`
+			} `json:"dimensions"`
+		} `json:"grid"`
+		VisualizationOptions struct {
+			Ranges  []map[string]float64 `json:"ranges"`
+			Palette []string             `json:"palette"`
+			Gamma   []float64            `json:"gamma"`
+		} `json:"visualizationOptions"`
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png-bytes"))
+	})
+
+	bounds := Bounds{MinLon: -123, MinLat: 45, MaxLon: -122, MaxLat: 45.5}
+	png, err := Thumbnail(context.Background(), client, client.Image("test"), bounds, 200,
+		VisualizationOptions{Bands: []string{"B4", "B3", "B2"}, Min: 0, Max: 3000, Gamma: 1.4})
+	if err != nil {
+		t.Fatalf("Thumbnail failed: %v", err)
+	}
+
+	if string(png) != "png-bytes" {
+		t.Errorf("Thumbnail = %q, want png-bytes", png)
+	}
+	if !strings.HasSuffix(path, "/image:computePixels") {
+		t.Errorf("path = %s, want computePixels", path)
+	}
+	if body.FileFormat != "PNG" || strings.Join(body.BandIds, ",") != "B4,B3,B2" {
+		t.Errorf("fileFormat, bandIds = %s, %v", body.FileFormat, body.BandIds)
+	}
+	if body.Grid.CrsCode != "EPSG:4326" || body.Grid.Dimensions.Width != "200" || body.Grid.Dimensions.Height != "100" {
+		t.Errorf("grid = %+v, want 200x100 in EPSG:4326", body.Grid)
+	}
+	if body.Grid.AffineTransform["scaleX"] != 0.005 || body.Grid.AffineTransform["translateY"] != 45.5 {
+		t.Errorf("affineTransform = %v", body.Grid.AffineTransform)
+	}
+	if len(body.VisualizationOptions.Ranges) != 3 || body.VisualizationOptions.Ranges[0]["max"] != 3000 {
+		t.Errorf("ranges = %v, want 3 ranges up to 3000", body.VisualizationOptions.Ranges)
+	}
+	if len(body.VisualizationOptions.Gamma) != 3 {
+		t.Errorf("gamma = %v, want 3 values", body.VisualizationOptions.Gamma)
+	}
+}
+
+func TestThumbnailErrors(t *testing.T) {
+	client, _ := newImageryTestClient(t, `{}`)
+	image := client.Image("test")
+	bounds := Bounds{MinLon: -123, MinLat: 45, MaxLon: -122, MaxLat: 45.5}
+
+	tests := []struct {
+		name   string
+		image  *earthengine.Image
+		bounds Bounds
+		width  int
+		vis    VisualizationOptions
+	}{
+		{"nil image", nil, bounds, 100, VisualizationOptions{Max: 1}},
+		{"invalid bounds", image, Bounds{MinLon: 1, MaxLon: 0, MaxLat: 1}, 100, VisualizationOptions{Max: 1}},
+		{"zero width", image, bounds, 0, VisualizationOptions{Max: 1}},
+		{"empty range", image, bounds, 100, VisualizationOptions{}},
+		{"two bands", image, bounds, 100, VisualizationOptions{Bands: []string{"a", "b"}, Max: 1}},
+		{"palette with three bands", image, bounds, 100, VisualizationOptions{Bands: []string{"a", "b", "c"}, Max: 1, Palette: []string{"000000"}}},
+		{"too tall", image, Bounds{MinLon: 0, MinLat: -80, MaxLon: 0.001, MaxLat: 80}, 1000, VisualizationOptions{Max: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Thumbnail(context.Background(), client, tt.image, tt.bounds, tt.width, tt.vis); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestGetTileURL(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"name": "projects/test-project/maps/abc123"}`)
+
+	url, err := GetTileURL(context.Background(), client, client.Image("test"),
+		VisualizationOptions{Min: -1, Max: 1, Palette: []string{"blue", "white", "green"}})
+	if err != nil {
+		t.Fatalf("GetTileURL failed: %v", err)
+	}
+
+	if !strings.HasSuffix(url, "/projects/test-project/maps/abc123/tiles/{z}/{x}/{y}") {
+		t.Errorf("url = %s, want an XYZ template", url)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{`"palette":["blue","white","green"]`, `"fileFormat":"PNG"`, `"min":-1`} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %s", want)
+		}
+	}
+
+	if _, err := GetTileURL(context.Background(), client, client.Image("test"), VisualizationOptions{Min: 1, Max: 1}); err == nil {
+		t.Error("Expected error for empty range")
+	}
+}
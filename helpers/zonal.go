@@ -2,7 +2,13 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -34,15 +40,54 @@ const (
 
 	// Variance calculates the variance
 	Variance ZonalStatistic = "variance"
+
+	// Mode finds the most common value, e.g. the majority class of a land
+	// cover raster. It is valid for continuous bands but rarely useful there,
+	// since nearly every pixel value is distinct.
+	Mode ZonalStatistic = "mode"
+
+	// FirstNonNull takes the first unmasked value. It is named "first" after
+	// the output of Earth Engine's firstNonNull reducer.
+	FirstNonNull ZonalStatistic = "first"
 )
 
+// Percentile calculates the p-th percentile (0-100). The statistic is named
+// like Earth Engine's percentile outputs, so Percentile(90) is "p90" and its
+// results are keyed "<band>_p90".
+//
+// Example:
+//
+//	config := helpers.ZonalStatsConfig{
+//	    Statistics: []helpers.ZonalStatistic{helpers.Mean, helpers.Percentile(10), helpers.Percentile(90)},
+//	}
+func Percentile(p float64) ZonalStatistic {
+	return ZonalStatistic("p" + strconv.FormatFloat(p, 'f', -1, 64))
+}
+
+// percentile returns the percentile encoded in a statistic created by
+// Percentile.
+func (s ZonalStatistic) percentile() (float64, bool) {
+	value, ok := strings.CutPrefix(string(s), "p")
+	if !ok {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(value, 64)
+	if err != nil || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
 // ZonalStats contains statistical results for a zone.
 type ZonalStats struct {
-	ZoneID     interface{}        // ID of the zone (from feature property)
-	Stats      map[string]float64 // Statistics by band name
-	PixelCount int                // Number of pixels in zone
-	Area       float64            // Area in square meters
-	Geometry   earthengine.Geometry
+	ZoneID     interface{}          `json:"zone_id"`     // ID of the zone (from feature property)
+	Stats      map[string]float64   `json:"stats"`       // Statistics by band name
+	PixelCount int                  `json:"pixel_count"` // Number of pixels in zone
+	Area       float64              `json:"area"`        // Area in square meters
+	Geometry   earthengine.Geometry `json:"-"`
+
+	WeightedMean map[string]float64 `json:"weighted_mean"` // Weighted mean by band (when weighting is configured)
+	TotalWeight  float64            `json:"total_weight"`  // Sum of weights in zone (when weighting is configured)
 }
 
 // ZonalStatsConfig configures zonal statistics calculation.
@@ -54,21 +99,55 @@ type ZonalStatsConfig struct {
 	CRS        string           // Coordinate reference system
 	MaxPixels  int64            // Maximum pixels to process
 	TileScale  float64          // Tile scale factor for large computations
+
+	ChunkSize   int // Features per reduceRegions request (default 500)
+	Concurrency int // Number of chunks processed in parallel (default 1)
+
+	// Weight is an optional single-band image (e.g. population) used to
+	// compute weighted means. When AreaWeighted is set, weights are also
+	// multiplied by pixel area in square meters, so results are true densities.
+	Weight       *earthengine.Image
+	AreaWeighted bool
+
+	// Unweighted counts each pixel whose center falls in a zone in full and
+	// ignores the rest. By default Earth Engine weights pixels on zone edges
+	// by the fraction inside the zone, so sums (and TotalWeight) include
+	// fractional pixels and PixelCount counts every pixel the zone touches.
+	// Weighting matters most for small zones, where edge pixels are a large
+	// share of the total.
+	Unweighted bool
 }
 
 // ZonalStatsResult contains results for all zones.
 type ZonalStatsResult struct {
-	Zones      []ZonalStats
-	Statistics []ZonalStatistic
-	Bands      []string
-	Scale      float64
+	Zones      []ZonalStats     `json:"zones"`
+	Statistics []ZonalStatistic `json:"statistics"`
+	Bands      []string         `json:"bands"`
+	Scale      float64          `json:"scale"`
 }
 
 // CalculateZonalStats calculates statistics for image values within polygons.
 //
+// Zones are reduced server-side with reduceRegions. Large collections are split
+// into chunks of ChunkSize features (default 500) so individual requests stay
+// within Earth Engine limits; set Concurrency to process several chunks at once.
+// Zones are returned in the same order as the input features.
+//
+// Choosing ChunkSize trades request count against request size: a ChunkSize
+// at least the number of zones reduces them all in one call, the fewest
+// requests and least quota, but that one computation can time out or run out
+// of memory, and a failure loses every zone. If chunks fail with "computation
+// timed out" or "user memory limit exceeded", lower ChunkSize or raise
+// TileScale (2, 4, ... up to 16), which splits the work into smaller tiles
+// at some cost in speed.
+//
+// Set Weight and/or AreaWeighted to also compute weighted means such as
+// population-weighted NDVI; these are returned in each zone's WeightedMean
+// alongside the zone's TotalWeight.
+//
 // Example:
 //
-//	polygons := &earthengine.FeatureCollection{} // Your polygons
+//	polygons := earthengine.NewFeatureCollection(features...) // Your polygons
 //	image := client.Image("COPERNICUS/S2_SR/20230601T...")
 //
 //	result, err := helpers.CalculateZonalStats(ctx, client, image, polygons,
@@ -77,17 +156,26 @@ type ZonalStatsResult struct {
 //	        Scale: 10,
 //	        Bands: []string{"B4", "B8"},
 //	        ZoneIDKey: "id",
+//	        ChunkSize: 250,
+//	        Concurrency: 4,
 //	    })
 //
 //	for _, zone := range result.Zones {
 //	    fmt.Printf("Zone %v: NDVI mean = %.2f\n",
 //	        zone.ZoneID, zone.Stats["B8_mean"])
 //	}
+//
+//	// Population-weighted mean NDVI
+//	result, err = helpers.CalculateZonalStats(ctx, client, ndvi, polygons,
+//	    helpers.ZonalStatsConfig{
+//	        Weight: client.Image("WorldPop/GP/100m/pop/USA_2020"),
+//	        AreaWeighted: true,
+//	    })
+//	fmt.Printf("Weighted NDVI: %.2f\n", result.Zones[0].WeightedMean["ndvi"])
 func CalculateZonalStats(ctx context.Context, client *earthengine.Client, image *earthengine.Image, zones *earthengine.FeatureCollection, config ZonalStatsConfig) (*ZonalStatsResult, error) {
-	_ = ctx
-	_ = client
-	_ = image
-	_ = zones
+	if zones == nil {
+		return nil, fmt.Errorf("zones cannot be nil")
+	}
 
 	// Set defaults
 	if config.Scale == 0 {
@@ -105,28 +193,296 @@ func CalculateZonalStats(ctx context.Context, client *earthengine.Client, image
 	if config.TileScale == 0 {
 		config.TileScale = 1
 	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaultZonalChunkSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
 
-	// In a real implementation:
-	// 1. For each feature in zones:
-	//    a. Extract the zone geometry
-	//    b. Reduce the image over the geometry using specified statistics
-	//    c. Get the zone ID from feature properties
-	// 2. Combine results for all zones
-	// 3. Return consolidated results
-
-	// Placeholder implementation
 	result := &ZonalStatsResult{
-		Zones:      make([]ZonalStats, 0),
+		Zones:      make([]ZonalStats, 0, zones.Size()),
 		Statistics: config.Statistics,
 		Bands:      config.Bands,
 		Scale:      config.Scale,
 	}
 
+	if zones.Size() == 0 {
+		return result, nil
+	}
+
+	reducer, err := zonalReducer(config.Statistics)
+	if err != nil {
+		return nil, err
+	}
+	if config.Unweighted {
+		reducer = earthengine.ReducerUnweighted(reducer)
+	}
+
+	source := image
+	if len(config.Bands) > 0 {
+		source = image.Select(config.Bands...)
+	}
+	weighted := zonalWeightedImage(source, config)
+
+	// Process chunks with controlled concurrency, stopping at the first failure
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := zones.Chunk(config.ChunkSize)
+	chunkZones := make([][]ZonalStats, len(chunks))
+	sem := make(chan struct{}, config.Concurrency)
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	offset := 0
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(index, offset int, chunk *earthengine.FeatureCollection) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			stats, err := reduceZoneChunk(ctx, source, weighted, chunk, reducer, offset, config)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("failed to process zone chunk %d: %w", index, err)
+					cancel()
+				})
+				return
+			}
+			chunkZones[index] = stats
+		}(i, offset, chunk)
+		offset += chunk.Size()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	for _, stats := range chunkZones {
+		result.Zones = append(result.Zones, stats...)
+	}
+
 	return result, nil
 }
 
+// defaultZonalChunkSize is the number of features sent in a single reduceRegions request.
+const defaultZonalChunkSize = 500
+
+// zonalReducer builds the Earth Engine reducer for the requested statistics.
+func zonalReducer(statistics []ZonalStatistic) (earthengine.Reducer, error) {
+	reducers := make([]earthengine.Reducer, 0, len(statistics))
+	for _, stat := range statistics {
+		var reducer earthengine.Reducer
+		switch stat {
+		case Mean:
+			reducer = earthengine.ReducerMean()
+		case Sum:
+			reducer = earthengine.ReducerSum()
+		case Count:
+			reducer = earthengine.ReducerCount()
+		case Min:
+			reducer = earthengine.ReducerMin()
+		case Max:
+			reducer = earthengine.ReducerMax()
+		case Median:
+			reducer = earthengine.ReducerMedian()
+		case StdDev:
+			reducer = earthengine.ReducerStdDev()
+		case Variance:
+			reducer = earthengine.ReducerVariance()
+		case Mode:
+			reducer = earthengine.ReducerMode()
+		case FirstNonNull:
+			reducer = earthengine.ReducerFirstNonNull()
+		default:
+			p, ok := stat.percentile()
+			if !ok {
+				return nil, fmt.Errorf("unsupported zonal statistic: %s", stat)
+			}
+			reducer = earthengine.ReducerPercentile(p)
+		}
+		reducers = append(reducers, reducer)
+	}
+
+	return earthengine.ReducerCombine(reducers...), nil
+}
+
+// reduceZoneChunk runs reduceRegions over a single chunk of zones and parses the results.
+//
+// When weighted is non-nil, a second reduction sums its bands to produce the
+// weighted means and total weight for each zone.
+func reduceZoneChunk(ctx context.Context, image, weighted *earthengine.Image, chunk *earthengine.FeatureCollection, reducer earthengine.Reducer, offset int, config ZonalStatsConfig) ([]ZonalStats, error) {
+	properties, err := reduceChunkProperties(ctx, image, chunk, reducer, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var weightedProperties []map[string]interface{}
+	if weighted != nil {
+		sum := earthengine.ReducerSum()
+		if config.Unweighted {
+			sum = earthengine.ReducerUnweighted(sum)
+		}
+		weightedProperties, err = reduceChunkProperties(ctx, weighted, chunk, sum, config)
+		if err != nil {
+			return nil, fmt.Errorf("weighted reduction failed: %w", err)
+		}
+	}
+
+	zones := make([]ZonalStats, len(properties))
+	for i, props := range properties {
+		input := chunk.Features[i]
+		zone := ZonalStats{
+			ZoneID:   offset + i,
+			Stats:    make(map[string]float64),
+			Geometry: input.Geometry,
+		}
+
+		if config.ZoneIDKey != "" {
+			if id, exists := props[config.ZoneIDKey]; exists {
+				zone.ZoneID = id
+			}
+		}
+
+		for key, num := range zonalOutputs(props, input) {
+			name := zonalStatKey(key, config)
+			zone.Stats[name] = num
+			if strings.HasSuffix(name, string(Count)) && zone.PixelCount == 0 {
+				zone.PixelCount = int(num)
+			}
+		}
+
+		if weightedProperties != nil {
+			sums := zonalOutputs(weightedProperties[i], input)
+			zone.TotalWeight = sums[zonalWeightBand]
+			zone.WeightedMean = make(map[string]float64)
+			for band, sum := range sums {
+				if band == zonalWeightBand || zone.TotalWeight == 0 {
+					continue
+				}
+				zone.WeightedMean[band] = sum / zone.TotalWeight
+			}
+		}
+
+		zones[i] = zone
+	}
+
+	return zones, nil
+}
+
+// reduceChunkProperties runs reduceRegions over a chunk and returns the
+// properties of each output feature, in input order.
+func reduceChunkProperties(ctx context.Context, image *earthengine.Image, chunk *earthengine.FeatureCollection, reducer earthengine.Reducer, config ZonalStatsConfig) ([]map[string]interface{}, error) {
+	fc, err := image.ReduceRegions(chunk, reducer,
+		earthengine.Scale(config.Scale),
+		earthengine.CRS(config.CRS),
+		earthengine.TileScale(config.TileScale),
+	).Compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	features, ok := fc["features"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no features in reduceRegions result")
+	}
+	if len(features) != chunk.Size() {
+		return nil, fmt.Errorf("expected %d features in result, got %d", chunk.Size(), len(features))
+	}
+
+	properties := make([]map[string]interface{}, len(features))
+	for i, f := range features {
+		feature, _ := f.(map[string]interface{})
+		props, _ := feature["properties"].(map[string]interface{})
+		properties[i] = props
+	}
+
+	return properties, nil
+}
+
+// zonalOutputs returns the numeric properties added by a reduction, skipping
+// the properties the input feature already had.
+func zonalOutputs(props map[string]interface{}, input earthengine.Feature) map[string]float64 {
+	outputs := make(map[string]float64)
+	for key, value := range props {
+		if _, original := input.Properties[key]; original {
+			continue
+		}
+		if num, ok := value.(float64); ok {
+			outputs[key] = num
+		}
+	}
+	return outputs
+}
+
+// zonalWeightBand is the band name used for the weight in weighted reductions.
+// Its name is reserved so it cannot collide with a source band named "weight".
+const zonalWeightBand = "_weight"
+
+// zonalWeightedImage builds the image summed for weighted statistics: each band
+// of source multiplied by the weight, followed by the weight itself. The
+// weight is masked wherever a band of source is masked (clouds, nodata), so
+// the total weight covers the same pixels as the weighted sums.
+//
+// It returns nil when no weighting is configured.
+func zonalWeightedImage(source *earthengine.Image, config ZonalStatsConfig) *earthengine.Image {
+	var weight *earthengine.Image
+	switch {
+	case config.Weight != nil && config.AreaWeighted:
+		weight = config.Weight.Multiply(config.Weight.PixelArea())
+	case config.Weight != nil:
+		weight = config.Weight
+	case config.AreaWeighted:
+		weight = source.PixelArea()
+	default:
+		return nil
+	}
+
+	weight = weight.UpdateMask(source.Mask().Reduce(earthengine.ReducerMin()))
+	return source.Multiply(weight).AddBands(weight.Rename(zonalWeightBand))
+}
+
+// zonalStatKey normalizes reducer output names to the "<band>_<statistic>" form.
+//
+// Earth Engine names outputs by statistic for single-band images ("mean") and
+// by band for single-reducer, multi-band reductions ("B4").
+func zonalStatKey(key string, config ZonalStatsConfig) string {
+	if len(config.Statistics) == 1 {
+		for _, band := range config.Bands {
+			if key == band {
+				return band + "_" + string(config.Statistics[0])
+			}
+		}
+	}
+	if len(config.Bands) == 1 {
+		for _, stat := range config.Statistics {
+			if key == string(stat) {
+				return config.Bands[0] + "_" + key
+			}
+		}
+	}
+	return key
+}
+
 // CalculateZonalStatsSingle calculates statistics for a single polygon.
 //
+// Statistics are named as in CalculateZonalStats: "<band>_<statistic>" for
+// multi-band images, or just the statistic for single-band images.
+//
 // Example:
 //
 //	polygon := helpers.BoundsToGeometry(bounds)
@@ -134,22 +490,67 @@ func CalculateZonalStats(ctx context.Context, client *earthengine.Client, image
 //	    []helpers.ZonalStatistic{helpers.Mean, helpers.Max}, 10)
 //	fmt.Printf("Mean: %.2f, Max: %.2f\n", stats["B4_mean"], stats["B4_max"])
 func CalculateZonalStatsSingle(ctx context.Context, client *earthengine.Client, image *earthengine.Image, geometry earthengine.Geometry, statistics []ZonalStatistic, scale float64) (map[string]float64, error) {
-	_ = ctx
 	_ = client
-	_ = image
-	_ = geometry
-	_ = statistics
 
-	if scale == 0 {
-		scale = 30
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if geometry == nil {
+		return nil, fmt.Errorf("geometry cannot be nil")
 	}
 
-	// In a real implementation:
-	// 1. Create reducer for each statistic
-	// 2. Reduce image over geometry
-	// 3. Extract results
+	config := ZonalStatsConfig{
+		Statistics: statistics,
+		Scale:      scale,
+	}
+	if config.Scale == 0 {
+		config.Scale = 30
+	}
+	if len(config.Statistics) == 0 {
+		config.Statistics = []ZonalStatistic{Mean}
+	}
+
+	reducer, err := zonalReducer(config.Statistics)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := image.ReduceRegion(geometry, reducer,
+		earthengine.Scale(config.Scale),
+		earthengine.MaxPixels(1e8),
+	).Compute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce region: %w", err)
+	}
+
+	stats := make(map[string]float64, len(result))
+	for key, value := range result {
+		if num, ok := value.(float64); ok {
+			stats[zonalStatKey(key, config)] = num
+		}
+	}
+
+	return stats, nil
+}
+
+// PointBufferStats calculates statistics over a circle of radiusMeters around
+// a point, smoothing out single-pixel noise in point samples.
+//
+// Statistics are named as in CalculateZonalStats.
+//
+// Example:
+//
+//	// Mean elevation within 100m of a point
+//	stats, err := helpers.PointBufferStats(ctx, client, client.Image("USGS/SRTMGL1_003"),
+//	    45.5152, -122.6784, 100, []helpers.ZonalStatistic{helpers.Mean}, 30)
+//	fmt.Printf("Mean elevation: %.1fm\n", stats["mean"])
+func PointBufferStats(ctx context.Context, client *earthengine.Client, image *earthengine.Image, lat, lon, radiusMeters float64, stats []ZonalStatistic, scale float64) (map[string]float64, error) {
+	circle, err := Circle(lat, lon, radiusMeters)
+	if err != nil {
+		return nil, err
+	}
 
-	return map[string]float64{}, nil
+	return CalculateZonalStatsSingle(ctx, client, image, circle, stats, scale)
 }
 
 // ZonalMean calculates mean values within polygons (convenience function).
@@ -183,12 +584,32 @@ func ZonalSum(ctx context.Context, client *earthengine.Client, image *earthengin
 //	histograms, err := helpers.CalculateZonalHistogram(ctx, client, image, polygons,
 //	    "B4", 256, 0, 10000, 30)
 type ZonalHistogram struct {
-	ZoneID     interface{}
-	BandName   string
-	Histogram  map[float64]int // Value -> count
-	BucketSize float64
-	MinValue   float64
-	MaxValue   float64
+	ZoneID     interface{}     `json:"zone_id"`
+	BandName   string          `json:"band_name"`
+	Histogram  map[float64]int `json:"histogram"` // Value -> count
+	BucketSize float64         `json:"bucket_size"`
+	MinValue   float64         `json:"min_value"`
+	MaxValue   float64         `json:"max_value"`
+}
+
+// UnmarshalJSON decodes a histogram encoded by MarshalResult, whose bucket
+// values are object keys.
+func (h *ZonalHistogram) UnmarshalJSON(data []byte) error {
+	type plain ZonalHistogram
+	aux := struct {
+		*plain
+		Histogram map[string]int `json:"histogram"`
+	}{plain: (*plain)(h)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	histogram, err := parseFloatKeys(aux.Histogram)
+	if err != nil {
+		return fmt.Errorf("histogram: %w", err)
+	}
+	h.Histogram = histogram
+	return nil
 }
 
 func CalculateZonalHistogram(ctx context.Context, client *earthengine.Client, image *earthengine.Image, zones *earthengine.FeatureCollection, bandName string, numBuckets int, minValue, maxValue, scale float64) ([]ZonalHistogram, error) {
@@ -210,62 +631,199 @@ func CalculateZonalHistogram(ctx context.Context, client *earthengine.Client, im
 	return []ZonalHistogram{}, nil
 }
 
+// ZonalFrequency holds the class frequencies of a categorical band in one zone.
+type ZonalFrequency struct {
+	ZoneID      interface{} `json:"zone_id"`
+	ClassCounts map[int]int `json:"class_counts"` // Class value -> pixel count
+	Dominant    int         `json:"dominant"`     // Most frequent class (the smallest on ties)
+	Diversity   float64     `json:"diversity"`    // Shannon diversity index (see ShannonDiversity)
+}
+
 // ZonalFrequencyTable calculates frequency tables for categorical data.
 //
+// Each zone's pixels are counted per class value of bandName, and the most
+// frequent class and Shannon diversity are derived from the counts. Zones
+// are numbered in input order, and zones with no valid pixels have no class
+// counts.
+//
 // Example:
 //
 //	// Land cover class frequencies per zone
 //	freq, err := helpers.ZonalFrequencyTable(ctx, client, landCoverImage,
 //	    watersheds, "classification", 30)
-type ZonalFrequency struct {
-	ZoneID      interface{}
-	ClassCounts map[int]int // Class value -> pixel count
-	Dominant    int         // Most frequent class
-	Diversity   float64     // Shannon diversity index
-}
-
 func ZonalFrequencyTable(ctx context.Context, client *earthengine.Client, image *earthengine.Image, zones *earthengine.FeatureCollection, bandName string, scale float64) ([]ZonalFrequency, error) {
-	_ = ctx
 	_ = client
-	_ = image
-	_ = zones
-	_ = bandName
-	_ = scale
 
-	// In a real implementation:
-	// 1. Reduce with frequency reducer
-	// 2. Calculate diversity metrics
-	// 3. Identify dominant class
+	if image == nil {
+		return nil, fmt.Errorf("image cannot be nil")
+	}
+	if zones == nil {
+		return nil, fmt.Errorf("zones cannot be nil")
+	}
+	if bandName == "" {
+		return nil, fmt.Errorf("band name is required")
+	}
+	if scale == 0 {
+		scale = 30
+	}
+
+	frequencies := make([]ZonalFrequency, 0, zones.Size())
+	if zones.Size() == 0 {
+		return frequencies, nil
+	}
+
+	config := ZonalStatsConfig{Scale: scale, CRS: "EPSG:4326", TileScale: 1}
+	source := image.Select(bandName)
+	for _, chunk := range zones.Chunk(defaultZonalChunkSize) {
+		properties, err := reduceChunkProperties(ctx, source, chunk, earthengine.ReducerFrequencyHistogram(), config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count classes: %w", err)
+		}
 
-	return []ZonalFrequency{}, nil
+		for _, props := range properties {
+			zone := ZonalFrequency{ZoneID: len(frequencies), ClassCounts: map[int]int{}}
+
+			// A single-band histogram is named after the reducer output
+			histogram, ok := props["histogram"]
+			if !ok {
+				histogram = props[bandName]
+			}
+			if histogram != nil {
+				if zone.ClassCounts, err = parseClassCounts(histogram); err != nil {
+					return nil, fmt.Errorf("zone %d: %w", len(frequencies), err)
+				}
+			}
+
+			zone.Dominant = dominantClass(zone.ClassCounts)
+			zone.Diversity = ShannonDiversity(zone.ClassCounts)
+			frequencies = append(frequencies, zone)
+		}
+	}
+
+	return frequencies, nil
+}
+
+// dominantClass returns the class with the highest count, preferring the
+// smallest class on ties, or 0 if counts is empty.
+func dominantClass(counts map[int]int) int {
+	dominant, best := 0, 0
+	for class, count := range counts {
+		if count > best || (count == best && count > 0 && class < dominant) {
+			dominant, best = class, count
+		}
+	}
+	return dominant
+}
+
+// ZonalPercentiles holds the percentiles of one band within a zone.
+type ZonalPercentiles struct {
+	ZoneID      interface{}         `json:"zone_id"`
+	BandName    string              `json:"band_name"`   // Empty for single-band images
+	Percentiles map[float64]float64 `json:"percentiles"` // Percentile -> value
+}
+
+// UnmarshalJSON decodes percentiles encoded by MarshalResult, whose
+// percentiles are object keys.
+func (p *ZonalPercentiles) UnmarshalJSON(data []byte) error {
+	type plain ZonalPercentiles
+	aux := struct {
+		*plain
+		Percentiles map[string]float64 `json:"percentiles"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	percentiles, err := parseFloatKeys(aux.Percentiles)
+	if err != nil {
+		return fmt.Errorf("percentiles: %w", err)
+	}
+	p.Percentiles = percentiles
+	return nil
 }
 
-// ZonalPercentiles calculates percentiles within zones.
+// CalculateZonalPercentiles calculates percentiles of each band within zones.
+// It is CalculateZonalStats with Percentile statistics, with the results
+// grouped by zone and band; zones are returned in input order, with one entry
+// per band sorted by band name.
 //
 // Example:
 //
 //	percentiles, err := helpers.CalculateZonalPercentiles(ctx, client, image, zones,
 //	    []float64{25, 50, 75}, 30)
-type ZonalPercentiles struct {
-	ZoneID      interface{}
-	BandName    string
-	Percentiles map[float64]float64 // Percentile -> value
-}
-
+//	for _, zone := range percentiles {
+//	    fmt.Printf("Zone %v %s: IQR %.2f\n", zone.ZoneID, zone.BandName,
+//	        zone.Percentiles[75]-zone.Percentiles[25])
+//	}
 func CalculateZonalPercentiles(ctx context.Context, client *earthengine.Client, image *earthengine.Image, zones *earthengine.FeatureCollection, percentiles []float64, scale float64) ([]ZonalPercentiles, error) {
-	_ = ctx
-	_ = client
-	_ = image
-	_ = zones
-	_ = percentiles
-	_ = scale
+	if len(percentiles) == 0 {
+		return nil, fmt.Errorf("at least one percentile is required")
+	}
+	statistics := make([]ZonalStatistic, len(percentiles))
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile must be between 0 and 100, got %v", p)
+		}
+		statistics[i] = Percentile(p)
+	}
 
-	// In a real implementation:
-	// 1. Create percentile reducer
-	// 2. Reduce over each zone
-	// 3. Extract percentile values
+	result, err := CalculateZonalStats(ctx, client, image, zones, ZonalStatsConfig{
+		Statistics: statistics,
+		Scale:      scale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]ZonalPercentiles, 0, len(result.Zones))
+	for _, zone := range result.Zones {
+		byBand := make(map[string]map[float64]float64)
+		for key, value := range zone.Stats {
+			band, p, ok := percentileStatKey(key, percentiles)
+			if !ok {
+				continue
+			}
+			if byBand[band] == nil {
+				byBand[band] = make(map[float64]float64)
+			}
+			byBand[band][p] = value
+		}
 
-	return []ZonalPercentiles{}, nil
+		bands := make([]string, 0, len(byBand))
+		for band := range byBand {
+			bands = append(bands, band)
+		}
+		sort.Strings(bands)
+		for _, band := range bands {
+			output = append(output, ZonalPercentiles{
+				ZoneID:      zone.ZoneID,
+				BandName:    band,
+				Percentiles: byBand[band],
+			})
+		}
+	}
+
+	return output, nil
+}
+
+// percentileStatKey splits a zonal statistic key into its band and
+// percentile. Keys are "p<percentile>" for single-band images and
+// "<band>_p<percentile>" otherwise; a single percentile over a multi-band
+// image is keyed by band name alone.
+func percentileStatKey(key string, percentiles []float64) (string, float64, bool) {
+	for _, p := range percentiles {
+		name := string(Percentile(p))
+		if key == name {
+			return "", p, true
+		}
+		if band, ok := strings.CutSuffix(key, "_"+name); ok {
+			return band, p, true
+		}
+	}
+	if len(percentiles) == 1 {
+		return key, percentiles[0], true
+	}
+	return "", 0, false
 }
 
 // ZonalTimeSeries calculates time series statistics for each zone.
@@ -275,14 +833,14 @@ func CalculateZonalPercentiles(ctx context.Context, client *earthengine.Client,
 //	series, err := helpers.ZonalTimeSeries(ctx, client, collection, zones,
 //	    helpers.Mean, "NDVI", 30)
 type ZonalTimeSeriesPoint struct {
-	Time  string
-	Value float64
+	Time  string  `json:"time"`
+	Value float64 `json:"value"`
 }
 
 type ZonalTimeSeries struct {
-	ZoneID   interface{}
-	BandName string
-	Series   []ZonalTimeSeriesPoint
+	ZoneID   interface{}            `json:"zone_id"`
+	BandName string                 `json:"band_name"`
+	Series   []ZonalTimeSeriesPoint `json:"series"`
 }
 
 func CalculateZonalTimeSeries(ctx context.Context, client *earthengine.Client, collection *earthengine.ImageCollection, zones *earthengine.FeatureCollection, statistic ZonalStatistic, bandName string, scale float64) ([]ZonalTimeSeries, error) {
@@ -310,13 +868,13 @@ func CalculateZonalTimeSeries(ctx context.Context, client *earthengine.Client, c
 //	comparison, err := helpers.ZonalComparison(ctx, client,
 //	    beforeImage, afterImage, zones, helpers.Mean, 30)
 type ZonalComparison struct {
-	ZoneID         interface{}
-	BandName       string
-	BeforeValue    float64
-	AfterValue     float64
-	Difference     float64
-	PercentChange  float64
-	ChangeCategory string // "increase", "decrease", "no change"
+	ZoneID         interface{} `json:"zone_id"`
+	BandName       string      `json:"band_name"`
+	BeforeValue    float64     `json:"before_value"`
+	AfterValue     float64     `json:"after_value"`
+	Difference     float64     `json:"difference"`
+	PercentChange  float64     `json:"percent_change"`
+	ChangeCategory string      `json:"change_category"` // "increase", "decrease", "no change"
 }
 
 func CalculateZonalComparison(ctx context.Context, client *earthengine.Client, imageBefore, imageAfter *earthengine.Image, zones *earthengine.FeatureCollection, statistic ZonalStatistic, scale float64) ([]ZonalComparison, error) {
@@ -344,10 +902,10 @@ func CalculateZonalComparison(ctx context.Context, client *earthengine.Client, i
 //	crosstab, err := helpers.ZonalCrossTabulation(ctx, client,
 //	    landCover2020, landCover2023, zones, 30)
 type ZonalCrossTab struct {
-	ZoneID    interface{}
-	Matrix    map[string]map[string]int // from_class -> to_class -> count
-	FromTotal map[string]int            // Total pixels per source class
-	ToTotal   map[string]int            // Total pixels per destination class
+	ZoneID    interface{}               `json:"zone_id"`
+	Matrix    map[string]map[string]int `json:"matrix"`     // from_class -> to_class -> count
+	FromTotal map[string]int            `json:"from_total"` // Total pixels per source class
+	ToTotal   map[string]int            `json:"to_total"`   // Total pixels per destination class
 }
 
 func ZonalCrossTabulation(ctx context.Context, client *earthengine.Client, image1, image2 *earthengine.Image, zones *earthengine.FeatureCollection, scale float64) ([]ZonalCrossTab, error) {
@@ -373,13 +931,13 @@ func ZonalCrossTabulation(ctx context.Context, client *earthengine.Client, image
 //	correlations, err := helpers.ZonalCorrelation(ctx, client, image, zones,
 //	    "B4", "B8", 30)
 type ZonalCorrelation struct {
-	ZoneID      interface{}
-	Band1       string
-	Band2       string
-	Correlation float64
-	RSquared    float64
-	Slope       float64
-	Intercept   float64
+	ZoneID      interface{} `json:"zone_id"`
+	Band1       string      `json:"band1"`
+	Band2       string      `json:"band2"`
+	Correlation float64     `json:"correlation"`
+	RSquared    float64     `json:"r_squared"`
+	Slope       float64     `json:"slope"`
+	Intercept   float64     `json:"intercept"`
 }
 
 func CalculateZonalCorrelation(ctx context.Context, client *earthengine.Client, image *earthengine.Image, zones *earthengine.FeatureCollection, band1, band2 string, scale float64) ([]ZonalCorrelation, error) {
@@ -423,6 +981,87 @@ func ExportZonalStatsToCSV(result *ZonalStatsResult) (string, error) {
 	return csv, nil
 }
 
+// ZonalStatsToGeoJSON exports zonal statistics as a GeoJSON FeatureCollection.
+//
+// Each zone becomes a feature with the zone geometry and its statistics as
+// properties, along with zone_id, pixel_count, area, and weighted means when
+// present. Zones without a geometry are written with a null geometry. The
+// result's scale, bands, and statistics are included as top-level members.
+//
+// Example:
+//
+//	data, err := helpers.ZonalStatsToGeoJSON(result)
+//	os.WriteFile("zonal_stats.geojson", data, 0644)
+func ZonalStatsToGeoJSON(result *ZonalStatsResult) ([]byte, error) {
+	if result == nil {
+		return nil, fmt.Errorf("result is nil")
+	}
+
+	features := make([]map[string]interface{}, 0, len(result.Zones))
+	for i, zone := range result.Zones {
+		geometry, err := geometryToGeoJSON(zone.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("zone %d: %w", i, err)
+		}
+
+		properties := make(map[string]interface{}, len(zone.Stats)+len(zone.WeightedMean)+4)
+		for stat, value := range zone.Stats {
+			properties[stat] = geoJSONNumber(value)
+		}
+		for band, value := range zone.WeightedMean {
+			properties[band+"_weighted_mean"] = geoJSONNumber(value)
+		}
+		if zone.ZoneID != nil {
+			properties["zone_id"] = zone.ZoneID
+		}
+		if zone.PixelCount > 0 {
+			properties["pixel_count"] = zone.PixelCount
+		}
+		if zone.Area > 0 {
+			properties["area"] = zone.Area
+		}
+		if zone.TotalWeight != 0 {
+			properties["total_weight"] = geoJSONNumber(zone.TotalWeight)
+		}
+
+		features = append(features, map[string]interface{}{
+			"type":       "Feature",
+			"geometry":   geometry,
+			"properties": properties,
+		})
+	}
+
+	bands := result.Bands
+	if bands == nil {
+		bands = []string{}
+	}
+	statistics := result.Statistics
+	if statistics == nil {
+		statistics = []ZonalStatistic{}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":       "FeatureCollection",
+		"scale":      result.Scale,
+		"bands":      bands,
+		"statistics": statistics,
+		"features":   features,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GeoJSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// geoJSONNumber returns value, or nil for NaN and infinities, which JSON cannot represent.
+func geoJSONNumber(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return value
+}
+
 // ZonalStatsToFeatureCollection converts zonal stats back to feature collection.
 //
 // Example:
@@ -441,37 +1080,73 @@ func ZonalStatsToFeatureCollection(result *ZonalStatsResult) (*earthengine.Featu
 	return &earthengine.FeatureCollection{}, nil
 }
 
+// maxZonalBatchConcurrency caps how many images CalculateZonalStatsBatch
+// reduces at once. Each image may issue ZonalStatsConfig.Concurrency requests
+// of its own.
+const maxZonalBatchConcurrency = 8
+
 // CalculateZonalStatsBatch processes multiple images for the same zones.
 //
+// Images are processed one at a time by default; pass WithConcurrency to
+// reduce several at once (at most 8). Other batch options such as
+// WithQueryTimeout and WithWorkloadTag apply to each image.
+//
+// Results line up with images. If an image fails or ctx is canceled, the
+// results processed so far are returned along with the first error, and
+// results for the failed and unprocessed images are nil.
+//
 // Example:
 //
 //	images := []*earthengine.Image{image1, image2, image3}
 //	results, err := helpers.CalculateZonalStatsBatch(ctx, client,
-//	    images, zones, config)
-func CalculateZonalStatsBatch(ctx context.Context, client *earthengine.Client, images []*earthengine.Image, zones *earthengine.FeatureCollection, config ZonalStatsConfig) ([]*ZonalStatsResult, error) {
-	results := make([]*ZonalStatsResult, 0, len(images))
-
+//	    images, zones, config, helpers.WithConcurrency(3))
+func CalculateZonalStatsBatch(ctx context.Context, client *earthengine.Client, images []*earthengine.Image, zones *earthengine.FeatureCollection, config ZonalStatsConfig, opts ...BatchOption) ([]*ZonalStatsResult, error) {
+	batch := NewBatch(client, 1, opts...)
+	if batch.concurrency > maxZonalBatchConcurrency {
+		batch.concurrency = maxZonalBatchConcurrency
+	}
 	for _, image := range images {
-		result, err := CalculateZonalStats(ctx, client, image, zones, config)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process image: %w", err)
+		batch.Add(zonalStatsQuery{image: image, zones: zones, config: config})
+	}
+
+	batchResults, err := batch.Execute(ctx)
+	results := make([]*ZonalStatsResult, len(images))
+	for i, r := range batchResults {
+		if r.Error == nil {
+			results[i] = r.Value.(*ZonalStatsResult)
+		} else if err == nil {
+			err = fmt.Errorf("failed to process image %d: %w", i, r.Error)
 		}
-		results = append(results, result)
 	}
 
-	return results, nil
+	return results, err
+}
+
+// zonalStatsQuery calculates zonal statistics for one image of a batch.
+type zonalStatsQuery struct {
+	image  *earthengine.Image
+	zones  *earthengine.FeatureCollection
+	config ZonalStatsConfig
+}
+
+// Execute implements Query.
+func (q zonalStatsQuery) Execute(ctx context.Context, client *earthengine.Client) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return CalculateZonalStats(ctx, client, q.image, q.zones, q.config)
 }
 
 // ZonalStatsSummary provides summary statistics across all zones.
 type ZonalStatsSummary struct {
-	TotalZones      int
-	MeanValue       float64
-	MedianValue     float64
-	StdDevValue     float64
-	MinValue        float64
-	MaxValue        float64
-	TotalArea       float64
-	TotalPixelCount int
+	TotalZones      int     `json:"total_zones"`
+	MeanValue       float64 `json:"mean_value"`
+	MedianValue     float64 `json:"median_value"`
+	StdDevValue     float64 `json:"std_dev_value"`
+	MinValue        float64 `json:"min_value"`
+	MaxValue        float64 `json:"max_value"`
+	TotalArea       float64 `json:"total_area"`
+	TotalPixelCount int     `json:"total_pixel_count"`
 }
 
 // SummarizeZonalStats calculates summary statistics across all zones.
@@ -2,8 +2,18 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/alexscott64/go-earthengine"
 )
@@ -68,18 +78,74 @@ func TestCalculateZonalStatsDefaults(t *testing.T) {
 }
 
 func TestCalculateZonalStatsSingle(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"B4_mean": 0.12, "B4_max": 0.3, "B8_mean": 0.4, "B8_max": 0.6}}`)
+	image := client.Image("COPERNICUS/S2_SR/20230601T000000_20230601T000000_T10TEQ").Select("B4", "B8")
+	polygon := BoundsToGeometry(Bounds{MinLon: -122.5, MinLat: 45.4, MaxLon: -122.3, MaxLat: 45.6})
+
+	stats, err := CalculateZonalStatsSingle(context.Background(), client, image, polygon,
+		[]ZonalStatistic{Mean, Max}, 10)
+	if err != nil {
+		t.Fatalf("CalculateZonalStatsSingle failed: %v", err)
+	}
+
+	want := map[string]float64{"B4_mean": 0.12, "B4_max": 0.3, "B8_mean": 0.4, "B8_max": 0.6}
+	if len(stats) != len(want) {
+		t.Errorf("stats = %v, want %v", stats, want)
+	}
+	for key, w := range want {
+		if stats[key] != w {
+			t.Errorf("stats[%q] = %v, want %v", key, stats[key], w)
+		}
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"Image.reduceRegion", "Reducer.mean", "Reducer.max", "GeometryConstructors.Polygon"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
+	}
+}
+
+func TestCalculateZonalStatsSingleErrors(t *testing.T) {
 	ctx := context.Background()
 	client := &earthengine.Client{}
 	image := &earthengine.Image{}
+	point := earthengine.NewPoint(-122.6784, 45.5152)
 
-	stats, err := CalculateZonalStatsSingle(ctx, client, image, nil,
-		[]ZonalStatistic{Mean, Max}, 10)
+	if _, err := CalculateZonalStatsSingle(ctx, client, image, nil, nil, 10); err == nil {
+		t.Error("Expected error for nil geometry")
+	}
+	if _, err := CalculateZonalStatsSingle(ctx, client, nil, point, nil, 10); err == nil {
+		t.Error("Expected error for nil image")
+	}
+	if _, err := CalculateZonalStatsSingle(ctx, client, image, point, []ZonalStatistic{"skewness"}, 10); err == nil {
+		t.Error("Expected error for unsupported statistic")
+	}
+}
+
+func TestPointBufferStats(t *testing.T) {
+	client, lastRequest := newImageryTestClient(t, `{"result": {"mean": 52.5, "stdDev": 1.5}}`)
+	image := client.Image("USGS/SRTMGL1_003")
+
+	stats, err := PointBufferStats(context.Background(), client, image, 45.5152, -122.6784, 100,
+		[]ZonalStatistic{Mean, StdDev}, 30)
 	if err != nil {
-		t.Fatalf("CalculateZonalStatsSingle failed: %v", err)
+		t.Fatalf("PointBufferStats failed: %v", err)
+	}
+
+	if stats["mean"] != 52.5 || stats["stdDev"] != 1.5 {
+		t.Errorf("stats = %v, want mean 52.5, stdDev 1.5", stats)
+	}
+
+	request := lastRequest()
+	for _, want := range []string{"Geometry.buffer", "GeometryConstructors.Point", "100"} {
+		if !strings.Contains(request, want) {
+			t.Errorf("request does not contain %q", want)
+		}
 	}
 
-	if stats == nil {
-		t.Fatal("Stats is nil")
+	if _, err := PointBufferStats(context.Background(), client, image, 45.5152, -122.6784, 0, nil, 30); err == nil {
+		t.Error("Expected error for zero radius")
 	}
 }
 
@@ -157,6 +223,45 @@ func TestZonalFrequencyTable(t *testing.T) {
 	}
 }
 
+func TestZonalFrequencyTableCounts(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"features": [
+			{"properties": {"histogram": {"11": 25, "41": 74.6, "82": 0.4}}},
+			{"properties": {"histogram": {}}}
+		]}}`))
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), nil),
+		earthengine.NewFeature(earthengine.NewPoint(-121, 45), nil),
+	)
+	freq, err := ZonalFrequencyTable(ctx, client, client.Image("USGS/NLCD"), zones, "landcover", 30)
+	if err != nil {
+		t.Fatalf("ZonalFrequencyTable failed: %v", err)
+	}
+	if len(freq) != 2 {
+		t.Fatalf("got %d zones, want 2", len(freq))
+	}
+
+	want := map[int]int{11: 25, 41: 75, 82: 0}
+	for class, count := range want {
+		if freq[0].ClassCounts[class] != count {
+			t.Errorf("ClassCounts[%d] = %d, want %d", class, freq[0].ClassCounts[class], count)
+		}
+	}
+	if freq[0].Dominant != 41 {
+		t.Errorf("Dominant = %d, want 41", freq[0].Dominant)
+	}
+	if freq[0].Diversity != ShannonDiversity(want) {
+		t.Errorf("Diversity = %v, want %v", freq[0].Diversity, ShannonDiversity(want))
+	}
+	if freq[1].ZoneID != 1 || len(freq[1].ClassCounts) != 0 || freq[1].Diversity != 0 {
+		t.Errorf("empty zone = %+v", freq[1])
+	}
+}
+
 func TestCalculateZonalPercentiles(t *testing.T) {
 	ctx := context.Background()
 	client := &earthengine.Client{}
@@ -174,6 +279,83 @@ func TestCalculateZonalPercentiles(t *testing.T) {
 	}
 }
 
+func TestPercentileStatistic(t *testing.T) {
+	if got := Percentile(90); got != "p90" {
+		t.Errorf("Percentile(90) = %q, want p90", got)
+	}
+	if got := Percentile(2.5); got != "p2.5" {
+		t.Errorf("Percentile(2.5) = %q, want p2.5", got)
+	}
+
+	expr := earthengine.NewExpressionBuilder()
+	reducer, err := zonalReducer([]ZonalStatistic{Mean, Percentile(10), Percentile(90)})
+	if err != nil {
+		t.Fatalf("zonalReducer failed: %v", err)
+	}
+	data, err := json.Marshal(expr.Build(reducer.NodeID(expr)))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if n := strings.Count(string(data), earthengine.AlgorithmReducerPercentile); n != 2 {
+		t.Errorf("got %d percentile reducers, want 2: %s", n, data)
+	}
+
+	for _, stat := range []ZonalStatistic{Percentile(101), Percentile(-1), "pmedian"} {
+		if _, err := zonalReducer([]ZonalStatistic{stat}); err == nil {
+			t.Errorf("zonalReducer(%q) should fail", stat)
+		}
+	}
+}
+
+func TestZonalReducerCategorical(t *testing.T) {
+	expr := earthengine.NewExpressionBuilder()
+	reducer, err := zonalReducer([]ZonalStatistic{Mode, FirstNonNull})
+	if err != nil {
+		t.Fatalf("zonalReducer failed: %v", err)
+	}
+	data, err := json.Marshal(expr.Build(reducer.NodeID(expr)))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, name := range []string{earthengine.AlgorithmReducerMode, earthengine.AlgorithmReducerFirstNonNull} {
+		if !strings.Contains(string(data), `"`+name+`"`) {
+			t.Errorf("reducer %s missing from %s", name, data)
+		}
+	}
+
+	config := ZonalStatsConfig{Statistics: []ZonalStatistic{Mode}, Bands: []string{"landcover"}}
+	if got := zonalStatKey("landcover", config); got != "landcover_mode" {
+		t.Errorf("zonalStatKey = %q, want landcover_mode", got)
+	}
+}
+
+func TestCalculateZonalPercentilesByBand(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"features": [{"properties": {"id": 7,
+			"B4_p25": 0.1, "B4_p75": 0.3, "B8_p25": 0.4, "B8_p75": 0.6}}]}}`))
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{"id": 7}))
+	result, err := CalculateZonalPercentiles(ctx, client, client.Image("test/image"), zones, []float64{25, 75}, 30)
+	if err != nil {
+		t.Fatalf("CalculateZonalPercentiles failed: %v", err)
+	}
+
+	if len(result) != 2 || result[0].BandName != "B4" || result[1].BandName != "B8" {
+		t.Fatalf("result = %+v, want B4 and B8 entries", result)
+	}
+	if result[1].Percentiles[25] != 0.4 || result[1].Percentiles[75] != 0.6 {
+		t.Errorf("B8 percentiles = %v", result[1].Percentiles)
+	}
+
+	if _, err := CalculateZonalPercentiles(ctx, client, client.Image("test/image"), zones, []float64{150}, 30); err == nil {
+		t.Error("expected error for percentile above 100")
+	}
+}
+
 func TestCalculateZonalTimeSeries(t *testing.T) {
 	ctx := context.Background()
 	client := &earthengine.Client{}
@@ -302,6 +484,93 @@ func TestExportZonalStatsToCSVEmpty(t *testing.T) {
 	}
 }
 
+func TestZonalStatsToGeoJSON(t *testing.T) {
+	result := &ZonalStatsResult{
+		Zones: []ZonalStats{
+			{
+				ZoneID:     "downtown",
+				Stats:      map[string]float64{"NDVI_mean": 0.42},
+				PixelCount: 120,
+				Geometry:   earthengine.NewPolygon([][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 0}}),
+			},
+			{
+				ZoneID: "sensor",
+				Stats:  map[string]float64{"NDVI_mean": math.NaN()},
+			},
+		},
+		Statistics: []ZonalStatistic{Mean},
+		Bands:      []string{"NDVI"},
+		Scale:      10,
+	}
+
+	data, err := ZonalStatsToGeoJSON(result)
+	if err != nil {
+		t.Fatalf("ZonalStatsToGeoJSON failed: %v", err)
+	}
+
+	var decoded struct {
+		Type       string   `json:"type"`
+		Scale      float64  `json:"scale"`
+		Bands      []string `json:"bands"`
+		Statistics []string `json:"statistics"`
+		Features   []struct {
+			Type     string `json:"type"`
+			Geometry *struct {
+				Type        string         `json:"type"`
+				Coordinates [][][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if decoded.Type != "FeatureCollection" || decoded.Scale != 10 {
+		t.Errorf("type = %q, scale = %v", decoded.Type, decoded.Scale)
+	}
+	if len(decoded.Bands) != 1 || decoded.Bands[0] != "NDVI" || len(decoded.Statistics) != 1 || decoded.Statistics[0] != "mean" {
+		t.Errorf("bands = %v, statistics = %v", decoded.Bands, decoded.Statistics)
+	}
+	if len(decoded.Features) != 2 {
+		t.Fatalf("len(features) = %d, want 2", len(decoded.Features))
+	}
+
+	zone := decoded.Features[0]
+	if zone.Geometry == nil || zone.Geometry.Type != "Polygon" || len(zone.Geometry.Coordinates[0]) != 4 {
+		t.Errorf("geometry = %+v, want 4-position polygon", zone.Geometry)
+	}
+	if zone.Properties["zone_id"] != "downtown" || zone.Properties["NDVI_mean"] != 0.42 || zone.Properties["pixel_count"] != 120.0 {
+		t.Errorf("properties = %v", zone.Properties)
+	}
+
+	point := decoded.Features[1]
+	if point.Geometry != nil {
+		t.Errorf("geometry = %+v, want null", point.Geometry)
+	}
+	if value, ok := point.Properties["NDVI_mean"]; !ok || value != nil {
+		t.Errorf("NaN statistic = %v, want null", value)
+	}
+}
+
+func TestZonalStatsToGeoJSONErrors(t *testing.T) {
+	if _, err := ZonalStatsToGeoJSON(nil); err == nil {
+		t.Error("Expected error for nil result")
+	}
+
+	result := &ZonalStatsResult{
+		Zones: []ZonalStats{{ZoneID: 1, Geometry: unsupportedGeometry{}}},
+	}
+	if _, err := ZonalStatsToGeoJSON(result); err == nil {
+		t.Error("Expected error for unsupported geometry")
+	}
+}
+
+// unsupportedGeometry is a geometry with no GeoJSON representation.
+type unsupportedGeometry struct{}
+
+func (unsupportedGeometry) NodeID(expr *earthengine.ExpressionBuilder) string { return "" }
+
 func TestZonalStatsToFeatureCollection(t *testing.T) {
 	result := &ZonalStatsResult{
 		Zones: []ZonalStats{
@@ -464,6 +733,8 @@ func TestZonalStatisticConstants(t *testing.T) {
 		Median,
 		StdDev,
 		Variance,
+		Mode,
+		FirstNonNull,
 	}
 
 	for _, stat := range stats {
@@ -607,3 +878,325 @@ func TestZonalStatsStructs(t *testing.T) {
 		t.Error("Correlation ZoneID not set correctly")
 	}
 }
+
+func TestCalculateZonalStatsChunking(t *testing.T) {
+	var requests int32
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		var body struct {
+			Expression struct {
+				Values map[string]map[string]interface{} `json:"values"`
+			} `json:"expression"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		// Echo back one feature per input zone with a "mean" statistic
+		var ids []int
+		for id, node := range body.Expression.Values {
+			call, _ := node["functionInvocationValue"].(map[string]interface{})
+			if call["functionName"] == earthengine.AlgorithmFeature {
+				n, _ := strconv.Atoi(id)
+				ids = append(ids, n)
+			}
+		}
+		sort.Ints(ids)
+
+		features := make([]interface{}, 0, len(ids))
+		for _, id := range ids {
+			call := body.Expression.Values[strconv.Itoa(id)]["functionInvocationValue"].(map[string]interface{})
+			args := call["arguments"].(map[string]interface{})
+			props := args["metadata"].(map[string]interface{})["constantValue"].(map[string]interface{})
+			props["mean"] = props["id"]
+			features = append(features, map[string]interface{}{"properties": props})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": map[string]interface{}{"features": features},
+		})
+	})
+
+	features := make([]earthengine.Feature, 2500)
+	for i := range features {
+		features[i] = earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{"id": i})
+	}
+
+	result, err := CalculateZonalStats(ctx, client, client.Image("test/image"), earthengine.NewFeatureCollection(features...),
+		ZonalStatsConfig{
+			ZoneIDKey:   "id",
+			ChunkSize:   500,
+			Concurrency: 3,
+		})
+	if err != nil {
+		t.Fatalf("CalculateZonalStats failed: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 5 {
+		t.Errorf("Made %d requests, want 5", got)
+	}
+
+	if len(result.Zones) != 2500 {
+		t.Fatalf("Got %d zones, want 2500", len(result.Zones))
+	}
+
+	for i, zone := range result.Zones {
+		if zone.Stats["mean"] != float64(i) {
+			t.Fatalf("Zone %d mean = %v, want %d", i, zone.Stats["mean"], i)
+		}
+	}
+}
+
+func TestCalculateZonalStatsNilZones(t *testing.T) {
+	_, err := CalculateZonalStats(context.Background(), &earthengine.Client{}, &earthengine.Image{}, nil, ZonalStatsConfig{})
+	if err == nil {
+		t.Error("Expected error for nil zones")
+	}
+}
+
+func TestCalculateZonalStatsWeighted(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Expression struct {
+				Result string                            `json:"result"`
+				Values map[string]map[string]interface{} `json:"values"`
+			} `json:"expression"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		values := body.Expression.Values
+		call := func(id interface{}) (string, map[string]interface{}) {
+			node, _ := values[id.(string)]["functionInvocationValue"].(map[string]interface{})
+			args, _ := node["arguments"].(map[string]interface{})
+			name, _ := node["functionName"].(string)
+			return name, args
+		}
+		ref := func(args map[string]interface{}, key string) interface{} {
+			arg, _ := args[key].(map[string]interface{})
+			return arg["valueReference"]
+		}
+
+		_, reduceArgs := call(body.Expression.Result)
+		reducerName, _ := call(ref(reduceArgs, "reducer"))
+
+		properties := map[string]interface{}{"id": "zone-1"}
+		if reducerName == earthengine.AlgorithmReducerSum {
+			// Expect addBands(source * weight, rename(weight, "_weight")) where
+			// weight = population * pixelArea
+			name, args := call(ref(reduceArgs, "image"))
+			if name != earthengine.AlgorithmImageAddBands {
+				t.Fatalf("weighted image = %s, want %s", name, earthengine.AlgorithmImageAddBands)
+			}
+			productName, productArgs := call(ref(args, "dstImg"))
+			renameName, renameArgs := call(ref(args, "srcImg"))
+			if productName != earthengine.AlgorithmImageMultiply || renameName != earthengine.AlgorithmImageRename {
+				t.Fatalf("weighted bands = %s, %s", productName, renameName)
+			}
+
+			weightID := ref(productArgs, "image2")
+			if ref(renameArgs, "input") != weightID {
+				t.Error("weight band and weighting factor should be the same image")
+			}
+			// The weight is masked where the source is masked
+			maskedName, maskedArgs := call(weightID)
+			maskName, _ := call(ref(maskedArgs, "mask"))
+			if maskedName != earthengine.AlgorithmImageUpdateMask || maskName != earthengine.AlgorithmImageReduce {
+				t.Errorf("weight = %s(mask %s), want weight masked by the source's mask", maskedName, maskName)
+			}
+			weightName, weightArgs := call(ref(maskedArgs, "image"))
+			areaName, _ := call(ref(weightArgs, "image2"))
+			popName, _ := call(ref(weightArgs, "image1"))
+			if weightName != earthengine.AlgorithmImageMultiply || areaName != earthengine.AlgorithmImagePixelArea || popName != earthengine.AlgorithmImageLoad {
+				t.Errorf("weight = %s(%s, %s), want population multiplied by pixel area", weightName, popName, areaName)
+			}
+
+			properties["B4"] = 20.0
+			properties["weight"] = 12.0
+			properties["_weight"] = 4.0
+		} else {
+			properties["mean"] = 3.0
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": map[string]interface{}{
+				"features": []interface{}{map[string]interface{}{"properties": properties}},
+			},
+		})
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{"id": "zone-1"}),
+	)
+
+	result, err := CalculateZonalStats(ctx, client, client.Image("test/image"), zones, ZonalStatsConfig{
+		Bands:        []string{"B4"},
+		ZoneIDKey:    "id",
+		Weight:       client.Image("test/population"),
+		AreaWeighted: true,
+	})
+	if err != nil {
+		t.Fatalf("CalculateZonalStats failed: %v", err)
+	}
+
+	zone := result.Zones[0]
+	if zone.Stats["B4_mean"] != 3 {
+		t.Errorf("B4_mean = %v, want 3", zone.Stats["B4_mean"])
+	}
+	if zone.TotalWeight != 4 {
+		t.Errorf("TotalWeight = %v, want 4", zone.TotalWeight)
+	}
+	if zone.WeightedMean["B4"] != 5 {
+		t.Errorf("WeightedMean[B4] = %v, want 5", zone.WeightedMean["B4"])
+	}
+	// A source band named "weight" is a weighted band, not the total weight
+	if zone.WeightedMean["weight"] != 3 {
+		t.Errorf("WeightedMean[weight] = %v, want 3", zone.WeightedMean["weight"])
+	}
+}
+
+func TestCalculateZonalStatsUnweighted(t *testing.T) {
+	var mu sync.Mutex
+	var reducers []string
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Expression struct {
+				Result string                            `json:"result"`
+				Values map[string]map[string]interface{} `json:"values"`
+			} `json:"expression"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		call := func(id interface{}) (string, map[string]interface{}) {
+			node, _ := body.Expression.Values[id.(string)]["functionInvocationValue"].(map[string]interface{})
+			args, _ := node["arguments"].(map[string]interface{})
+			name, _ := node["functionName"].(string)
+			return name, args
+		}
+		_, reduceArgs := call(body.Expression.Result)
+		reducerArg, _ := reduceArgs["reducer"].(map[string]interface{})
+		name, args := call(reducerArg["valueReference"])
+		if inner, ok := args["reducer"].(map[string]interface{}); ok {
+			innerName, _ := call(inner["valueReference"])
+			name += "(" + innerName + ")"
+		}
+		mu.Lock()
+		reducers = append(reducers, name)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"features": [{"properties": {"mean": 0.6, "_weight": 2}}]}}`))
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{}))
+	_, err := CalculateZonalStats(ctx, client, client.Image("test/ndvi"), zones, ZonalStatsConfig{
+		AreaWeighted: true,
+		Unweighted:   true,
+	})
+	if err != nil {
+		t.Fatalf("CalculateZonalStats failed: %v", err)
+	}
+
+	sort.Strings(reducers)
+	want := []string{
+		earthengine.AlgorithmReducerUnweighted + "(" + earthengine.AlgorithmReducerMean + ")",
+		earthengine.AlgorithmReducerUnweighted + "(" + earthengine.AlgorithmReducerSum + ")",
+	}
+	if strings.Join(reducers, ",") != strings.Join(want, ",") {
+		t.Errorf("reducers = %v, want %v", reducers, want)
+	}
+}
+
+func TestCalculateZonalStatsBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 2 {
+			cancel()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"features": [{"properties": {"mean": 1}}]}}`))
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{}))
+	images := make([]*earthengine.Image, 6)
+	for i := range images {
+		images[i] = client.Image(fmt.Sprintf("test/image-%d", i))
+	}
+
+	results, err := CalculateZonalStatsBatch(ctx, client, images, zones, ZonalStatsConfig{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(results) != len(images) {
+		t.Fatalf("got %d results, want %d", len(results), len(images))
+	}
+	// Images run in no particular order, so any one may have completed
+	processed := 0
+	for _, result := range results {
+		if result != nil {
+			processed++
+			if result.Zones[0].Stats["mean"] != 1 {
+				t.Errorf("partial result = %+v", result)
+			}
+		}
+	}
+	if processed == 0 {
+		t.Error("no partial results returned")
+	}
+	if processed >= len(images) || atomic.LoadInt32(&requests) >= int32(len(images)) {
+		t.Errorf("processed %d images with %d requests after cancellation, want fewer than %d",
+			processed, requests, len(images))
+	}
+}
+
+func TestCalculateZonalStatsBatchConcurrency(t *testing.T) {
+	var active, peak int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"features": [{"properties": {"mean": 1}}]}}`))
+	})
+
+	zones := earthengine.NewFeatureCollection(
+		earthengine.NewFeature(earthengine.NewPoint(-120, 45), map[string]interface{}{}))
+	images := make([]*earthengine.Image, 20)
+	for i := range images {
+		images[i] = client.Image(fmt.Sprintf("test/image-%d", i))
+	}
+
+	results, err := CalculateZonalStatsBatch(context.Background(), client, images, zones, ZonalStatsConfig{},
+		WithConcurrency(100))
+	if err != nil {
+		t.Fatalf("CalculateZonalStatsBatch failed: %v", err)
+	}
+	for i, result := range results {
+		if result == nil {
+			t.Errorf("result %d is nil", i)
+		}
+	}
+	if p := atomic.LoadInt32(&peak); p < 2 || p > maxZonalBatchConcurrency {
+		t.Errorf("peak concurrency = %d, want 2-%d", p, maxZonalBatchConcurrency)
+	}
+}
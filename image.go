@@ -2,6 +2,7 @@ package earthengine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -30,6 +31,28 @@ func (c *Client) Image(imageID string) *Image {
 	}
 }
 
+// Constant creates an image with the same value in every pixel.
+//
+// Constant images are mainly useful as operands of image math.
+//
+// Example:
+//
+//	// Scale Landsat Collection 2 surface reflectance
+//	reflectance := image.Multiply(client.Constant(0.0000275)).Add(client.Constant(-0.2))
+func (c *Client) Constant(value float64) *Image {
+	expr := NewExpressionBuilder()
+
+	return &Image{
+		client: c,
+		expr:   expr,
+		nodeID: expr.FunctionCall(AlgorithmImageConstant, map[string]interface{}{
+			"value": map[string]interface{}{
+				"constantValue": value,
+			},
+		}),
+	}
+}
+
 // Select selects specific bands from the image.
 func (img *Image) Select(bands ...string) *Image {
 	// Create band selectors array
@@ -56,20 +79,62 @@ func (img *Image) Select(bands ...string) *Image {
 	}
 }
 
+// ComputePixels computes the image as an encoded file, such as a PNG.
+// See Client.ComputePixels for params.
+//
+// Example:
+//
+//	png, err := image.ComputePixels(ctx, map[string]interface{}{
+//	    "fileFormat": "PNG",
+//	    "grid":       grid,
+//	})
+func (img *Image) ComputePixels(ctx context.Context, params map[string]interface{}) ([]byte, error) {
+	return img.client.ComputePixels(ctx, img.expr.Build(img.nodeID), params)
+}
+
+// CreateMap registers the image for tiled map display. See Client.CreateMap
+// for params.
+//
+// Example:
+//
+//	mapID, err := image.CreateMap(ctx, map[string]interface{}{"fileFormat": "PNG"})
+func (img *Image) CreateMap(ctx context.Context, params map[string]interface{}) (*MapID, error) {
+	return img.client.CreateMap(ctx, img.expr.Build(img.nodeID), params)
+}
+
+// Serialize returns the image's expression graph as JSON, in the form of the
+// REST API's Expression ({"result": ..., "values": {...}}). Use it to build
+// apiv1 requests, such as exports, for the image.
+//
+// Building the same image the same way gives the same JSON.
+func (img *Image) Serialize() ([]byte, error) {
+	expr := img.expr.Build(img.nodeID)
+	return json.Marshal(map[string]interface{}{
+		"result": expr.result,
+		"values": expr.values,
+	})
+}
+
 // ReduceRegionOperation represents a reduce region operation on an image.
 type ReduceRegionOperation struct {
-	image    *Image
-	geometry string // Node ID for geometry
-	reducer  string // Node ID for reducer
-	scale    *float64
+	image     *Image
+	geometry  string // Node ID for geometry, empty for the image's footprint
+	reducer   string // Node ID for reducer
+	scale     *float64
+	crs       string
+	tileScale *float64
+	maxPixels *int64
 }
 
-// ReduceRegion starts a reduce region operation.
+// ReduceRegion starts a reduce region operation. If geom is nil, the
+// footprint of the image's first band is reduced.
 func (img *Image) ReduceRegion(geom Geometry, reducer Reducer, opts ...ReduceRegionOption) *ReduceRegionOperation {
 	op := &ReduceRegionOperation{
-		image:    img,
-		geometry: geom.NodeID(img.expr),
-		reducer:  reducer.NodeID(img.expr),
+		image:   img,
+		reducer: reducer.NodeID(img.expr),
+	}
+	if geom != nil {
+		op.geometry = geom.NodeID(img.expr)
 	}
 
 	// Apply options
@@ -90,6 +155,51 @@ func Scale(meters float64) ReduceRegionOption {
 	}
 }
 
+// CRS sets the projection (e.g. "EPSG:4326") in which the reduction is performed.
+func CRS(crs string) ReduceRegionOption {
+	return func(op *ReduceRegionOperation) {
+		op.crs = crs
+	}
+}
+
+// TileScale sets the tile scale factor used to reduce memory usage for large reductions.
+func TileScale(factor float64) ReduceRegionOption {
+	return func(op *ReduceRegionOperation) {
+		op.tileScale = &factor
+	}
+}
+
+// MaxPixels sets the maximum number of pixels to reduce.
+func MaxPixels(n int64) ReduceRegionOption {
+	return func(op *ReduceRegionOperation) {
+		op.maxPixels = &n
+	}
+}
+
+// applyArgs adds the optional reduction parameters to a function call's arguments.
+func (op *ReduceRegionOperation) applyArgs(args map[string]interface{}) {
+	if op.scale != nil {
+		args["scale"] = map[string]interface{}{
+			"constantValue": *op.scale,
+		}
+	}
+	if op.crs != "" {
+		args["crs"] = map[string]interface{}{
+			"constantValue": op.crs,
+		}
+	}
+	if op.tileScale != nil {
+		args["tileScale"] = map[string]interface{}{
+			"constantValue": *op.tileScale,
+		}
+	}
+	if op.maxPixels != nil {
+		args["maxPixels"] = map[string]interface{}{
+			"constantValue": *op.maxPixels,
+		}
+	}
+}
+
 // Compute executes the reduce region operation and returns the result.
 func (op *ReduceRegionOperation) Compute(ctx context.Context) (map[string]interface{}, error) {
 	// Build the reduceRegion function call arguments
@@ -97,21 +207,19 @@ func (op *ReduceRegionOperation) Compute(ctx context.Context) (map[string]interf
 		"image": map[string]interface{}{
 			"valueReference": op.image.nodeID,
 		},
-		"geometry": map[string]interface{}{
-			"valueReference": op.geometry,
-		},
 		"reducer": map[string]interface{}{
 			"valueReference": op.reducer,
 		},
 	}
-
-	// Add optional scale parameter
-	if op.scale != nil {
-		args["scale"] = map[string]interface{}{
-			"constantValue": *op.scale,
+	if op.geometry != "" {
+		args["geometry"] = map[string]interface{}{
+			"valueReference": op.geometry,
 		}
 	}
 
+	// Add optional scale, crs, tileScale and maxPixels parameters
+	op.applyArgs(args)
+
 	// Create reduceRegion node
 	reduceNodeID := op.image.expr.FunctionCall(AlgorithmImageReduceRegion, args)
 
@@ -152,6 +260,76 @@ func (op *ReduceRegionOperation) ComputeFloat(ctx context.Context) (float64, err
 	return 0, fmt.Errorf("no numeric value found in result: %v", result)
 }
 
+// ReduceRegionsOperation represents a reduction of an image over every feature
+// in a FeatureCollection.
+type ReduceRegionsOperation struct {
+	image      *Image
+	collection string // Node ID for the feature collection
+	params     *ReduceRegionOperation
+}
+
+// ReduceRegions starts a reduction of the image over each feature in the collection.
+// It accepts the same options as ReduceRegion; maxPixels is not used by Earth Engine
+// for this algorithm and is ignored.
+//
+// Example:
+//
+//	fc, err := image.ReduceRegions(zones, earthengine.ReducerMean(), earthengine.Scale(30)).Compute(ctx)
+func (img *Image) ReduceRegions(collection *FeatureCollection, reducer Reducer, opts ...ReduceRegionOption) *ReduceRegionsOperation {
+	params := &ReduceRegionOperation{
+		image:   img,
+		reducer: reducer.NodeID(img.expr),
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+	params.maxPixels = nil
+
+	return &ReduceRegionsOperation{
+		image:      img,
+		collection: collection.NodeID(img.expr),
+		params:     params,
+	}
+}
+
+// Compute executes the operation and returns the resulting collection as a
+// GeoJSON-style map with a "features" list. Each feature carries its original
+// properties plus the reducer outputs.
+func (op *ReduceRegionsOperation) Compute(ctx context.Context) (map[string]interface{}, error) {
+	args := map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": op.image.nodeID,
+		},
+		"collection": map[string]interface{}{
+			"valueReference": op.collection,
+		},
+		"reducer": map[string]interface{}{
+			"valueReference": op.params.reducer,
+		},
+	}
+	op.params.applyArgs(args)
+
+	reduceNodeID := op.image.expr.FunctionCall(AlgorithmImageReduceRegions, args)
+
+	result, err := op.image.client.ComputeValue(ctx, op.image.expr.Build(reduceNodeID))
+	if err != nil {
+		return nil, err
+	}
+
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type: %T", result)
+	}
+
+	return resultMap, nil
+}
+
+// ref returns the node ID of other within this image's expression graph,
+// importing it first if other was built from a different source.
+func (img *Image) ref(other *Image) string {
+	return img.expr.Import(other.expr, other.nodeID)
+}
+
 // Add performs element-wise addition with another image.
 func (img *Image) Add(other *Image) *Image {
 	addNodeID := img.expr.FunctionCall(AlgorithmImageAdd, map[string]interface{}{
@@ -159,7 +337,7 @@ func (img *Image) Add(other *Image) *Image {
 			"valueReference": img.nodeID,
 		},
 		"image2": map[string]interface{}{
-			"valueReference": other.nodeID,
+			"valueReference": img.ref(other),
 		},
 	})
 
@@ -177,7 +355,7 @@ func (img *Image) Subtract(other *Image) *Image {
 			"valueReference": img.nodeID,
 		},
 		"image2": map[string]interface{}{
-			"valueReference": other.nodeID,
+			"valueReference": img.ref(other),
 		},
 	})
 
@@ -195,7 +373,7 @@ func (img *Image) Multiply(other *Image) *Image {
 			"valueReference": img.nodeID,
 		},
 		"image2": map[string]interface{}{
-			"valueReference": other.nodeID,
+			"valueReference": img.ref(other),
 		},
 	})
 
@@ -213,7 +391,7 @@ func (img *Image) Divide(other *Image) *Image {
 			"valueReference": img.nodeID,
 		},
 		"image2": map[string]interface{}{
-			"valueReference": other.nodeID,
+			"valueReference": img.ref(other),
 		},
 	})
 
@@ -224,6 +402,584 @@ func (img *Image) Divide(other *Image) *Image {
 	}
 }
 
+// AddBands returns an image containing all bands of this image followed by the bands of other.
+//
+// Example:
+//
+//	stack := ndvi.AddBands(elevation)
+func (img *Image) AddBands(other *Image) *Image {
+	addBandsNodeID := img.expr.FunctionCall(AlgorithmImageAddBands, map[string]interface{}{
+		"dstImg": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"srcImg": map[string]interface{}{
+			"valueReference": img.ref(other),
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: addBandsNodeID,
+	}
+}
+
+// Rename renames the bands of the image.
+//
+// Example:
+//
+//	ndvi := image.Select("B8", "B4").NormalizedDifference().Rename("ndvi")
+func (img *Image) Rename(names ...string) *Image {
+	bandNames := make([]interface{}, len(names))
+	for i, name := range names {
+		bandNames[i] = name
+	}
+
+	renameNodeID := img.expr.FunctionCall(AlgorithmImageRename, map[string]interface{}{
+		"input": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"names": map[string]interface{}{
+			"constantValue": bandNames,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: renameNodeID,
+	}
+}
+
+// RemoveBands returns the image without the named bands.
+//
+// The remaining band names are resolved server-side, so the image's bands do
+// not need to be known in advance.
+//
+// Example:
+//
+//	composite := withNDVI.QualityMosaic("NDVI").RemoveBands("NDVI")
+func (img *Image) RemoveBands(bands ...string) *Image {
+	remove := make([]interface{}, len(bands))
+	for i, band := range bands {
+		remove[i] = band
+	}
+
+	bandNamesNodeID := img.expr.FunctionCall(AlgorithmImageBandNames, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+
+	keepNodeID := img.expr.FunctionCall(AlgorithmListRemoveAll, map[string]interface{}{
+		"list": map[string]interface{}{
+			"valueReference": bandNamesNodeID,
+		},
+		"other": map[string]interface{}{
+			"constantValue": remove,
+		},
+	})
+
+	selectNodeID := img.expr.FunctionCall(AlgorithmImageSelect, map[string]interface{}{
+		"input": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"bandSelectors": map[string]interface{}{
+			"valueReference": keepNodeID,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: selectNodeID,
+	}
+}
+
+// BandNames computes the names of the image's bands, in order.
+//
+// Example:
+//
+//	bands, err := client.Image("USGS/SRTMGL1_003").BandNames(ctx)
+//	// ["elevation"]
+func (img *Image) BandNames(ctx context.Context) ([]string, error) {
+	bandNamesNodeID := img.expr.FunctionCall(AlgorithmImageBandNames, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+
+	result, err := img.client.ComputeValue(ctx, img.expr.Build(bandNamesNodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute band names: %w", err)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected band names type: %T", result)
+	}
+	names := make([]string, len(values))
+	for i, value := range values {
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected band name %v (%T)", value, value)
+		}
+		names[i] = name
+	}
+
+	return names, nil
+}
+
+// PixelType describes the data type of an image band.
+type PixelType struct {
+	Precision string  // "int", "float", or "double"
+	Min       float64 // Smallest value of an integer type
+	Max       float64 // Largest value of an integer type
+}
+
+// BandTypes computes the pixel type of each of the image's bands, keyed by
+// band name.
+//
+// Example:
+//
+//	types, err := client.Image("USGS/SRTMGL1_003").BandTypes(ctx)
+//	// map[elevation:{Precision:int Min:-32768 Max:32767}]
+func (img *Image) BandTypes(ctx context.Context) (map[string]PixelType, error) {
+	bandTypesNodeID := img.expr.FunctionCall(AlgorithmImageBandTypes, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+
+	result, err := img.client.ComputeValue(ctx, img.expr.Build(bandTypesNodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute band types: %w", err)
+	}
+
+	values, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected band types type: %T", result)
+	}
+	types := make(map[string]PixelType, len(values))
+	for band, value := range values {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected type of band %s: %v", band, value)
+		}
+		precision, _ := fields["precision"].(string)
+		min, _ := fields["min"].(float64)
+		max, _ := fields["max"].(float64)
+		types[band] = PixelType{Precision: precision, Min: min, Max: max}
+	}
+
+	return types, nil
+}
+
+// Set returns the image with the metadata property set to value.
+//
+// Example:
+//
+//	// Stamp a derived image with its acquisition time (milliseconds)
+//	mosaic = mosaic.Set("system:time_start", date.UnixMilli())
+func (img *Image) Set(property string, value interface{}) *Image {
+	setNodeID := img.expr.FunctionCall(AlgorithmElementSet, map[string]interface{}{
+		"object": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"key": map[string]interface{}{
+			"constantValue": property,
+		},
+		"value": map[string]interface{}{
+			"constantValue": value,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: setNodeID,
+	}
+}
+
+// PixelArea returns an image whose pixel values are the area of each pixel in square meters.
+//
+// The result does not depend on this image's values; it shares its expression
+// graph so the two can be combined.
+//
+// Example:
+//
+//	// Area-weighted population
+//	weight := population.Multiply(population.PixelArea())
+func (img *Image) PixelArea() *Image {
+	areaNodeID := img.expr.FunctionCall(AlgorithmImagePixelArea, map[string]interface{}{})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: areaNodeID,
+	}
+}
+
+// constant returns a constant image in this image's expression graph.
+func (img *Image) constant(value interface{}) *Image {
+	constantNodeID := img.expr.FunctionCall(AlgorithmImageConstant, map[string]interface{}{
+		"value": map[string]interface{}{
+			"constantValue": value,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: constantNodeID,
+	}
+}
+
+// binary applies a two-image algorithm with this image as image1.
+func (img *Image) binary(algorithm string, other *Image) *Image {
+	nodeID := img.expr.FunctionCall(algorithm, map[string]interface{}{
+		"image1": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"image2": map[string]interface{}{
+			"valueReference": img.ref(other),
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: nodeID,
+	}
+}
+
+// BitwiseAnd computes the bitwise AND of each pixel with value.
+// This is typically used to test bits of a quality assessment band.
+//
+// Example:
+//
+//	// Landsat QA_PIXEL bit 3 is cloud
+//	cloud := image.Select("QA_PIXEL").BitwiseAnd(1 << 3).Neq(0)
+func (img *Image) BitwiseAnd(value int64) *Image {
+	return img.binary(AlgorithmImageBitwiseAnd, img.constant(value))
+}
+
+// Eq returns 1 where the pixel value equals value and 0 elsewhere.
+func (img *Image) Eq(value float64) *Image {
+	return img.binary(AlgorithmImageEq, img.constant(value))
+}
+
+// Neq returns 1 where the pixel value does not equal value and 0 elsewhere.
+func (img *Image) Neq(value float64) *Image {
+	return img.binary(AlgorithmImageNeq, img.constant(value))
+}
+
+// Gt returns 1 where the pixel value is greater than value and 0 elsewhere.
+func (img *Image) Gt(value float64) *Image {
+	return img.binary(AlgorithmImageGt, img.constant(value))
+}
+
+// Gte returns 1 where the pixel value is greater than or equal to value and 0 elsewhere.
+func (img *Image) Gte(value float64) *Image {
+	return img.binary(AlgorithmImageGte, img.constant(value))
+}
+
+// Lte returns 1 where the pixel value is less than or equal to value and 0 elsewhere.
+func (img *Image) Lte(value float64) *Image {
+	return img.binary(AlgorithmImageLte, img.constant(value))
+}
+
+// Abs computes the absolute value of each pixel.
+func (img *Image) Abs() *Image {
+	absNodeID := img.expr.FunctionCall(AlgorithmImageAbs, map[string]interface{}{
+		"value": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: absNodeID,
+	}
+}
+
+// And returns 1 where both this image and other are non-zero and 0 elsewhere.
+func (img *Image) And(other *Image) *Image {
+	return img.binary(AlgorithmImageAnd, other)
+}
+
+// Mask returns the image's mask: 1 where pixels are valid and 0 where they
+// are masked.
+//
+// Example:
+//
+//	// Area of the valid pixels
+//	area := image.PixelArea().UpdateMask(image.Mask())
+func (img *Image) Mask() *Image {
+	maskNodeID := img.expr.FunctionCall(AlgorithmImageMask, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: maskNodeID,
+	}
+}
+
+// UpdateMask masks out pixels where mask is zero.
+//
+// Example:
+//
+//	clear := image.UpdateMask(image.Select("QA_PIXEL").BitwiseAnd(1 << 3).Eq(0))
+func (img *Image) UpdateMask(mask *Image) *Image {
+	maskNodeID := img.expr.FunctionCall(AlgorithmImageUpdateMask, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"mask": map[string]interface{}{
+			"valueReference": img.ref(mask),
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: maskNodeID,
+	}
+}
+
+// Unmask replaces masked pixels with value, so every pixel is valid.
+//
+// Example:
+//
+//	// Count masked pixels as zero
+//	filled := image.Unmask(0)
+func (img *Image) Unmask(value float64) *Image {
+	unmaskNodeID := img.expr.FunctionCall(AlgorithmImageUnmask, map[string]interface{}{
+		"input": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"value": map[string]interface{}{
+			"constantValue": value,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: unmaskNodeID,
+	}
+}
+
+// Clip clips the image to a geometry. Pixels outside the geometry are masked.
+func (img *Image) Clip(geom Geometry) *Image {
+	clipNodeID := img.expr.FunctionCall(AlgorithmImageClip, map[string]interface{}{
+		"input": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"geometry": map[string]interface{}{
+			"valueReference": geom.NodeID(img.expr),
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: clipNodeID,
+	}
+}
+
+// Reproject forces the image into crs at scale meters per pixel. Without it,
+// Earth Engine picks the projection from whatever requests the pixels.
+//
+// Example:
+//
+//	utm := image.Reproject("EPSG:32610", 30)
+func (img *Image) Reproject(crs string, scale float64) *Image {
+	reprojectNodeID := img.expr.FunctionCall(AlgorithmImageReproject, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"crs": map[string]interface{}{
+			"constantValue": crs,
+		},
+		"scale": map[string]interface{}{
+			"constantValue": scale,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: reprojectNodeID,
+	}
+}
+
+// Reduce combines the bands of each pixel with reducer, giving an image with
+// one band per reducer output (named after it, such as "mean" or "min").
+//
+// Example:
+//
+//	// 1 where every band is valid, 0 elsewhere
+//	valid := image.Mask().Reduce(earthengine.ReducerMin())
+func (img *Image) Reduce(reducer Reducer) *Image {
+	reduceNodeID := img.expr.FunctionCall(AlgorithmImageReduce, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"reducer": map[string]interface{}{
+			"valueReference": reducer.NodeID(img.expr),
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: reduceNodeID,
+	}
+}
+
+// ReduceResolution makes the image combine its pixels with reducer, instead
+// of resampling them, when it is requested at a coarser resolution.
+// maxPixels is the most input pixels combined into one output pixel.
+//
+// Example:
+//
+//	// Area-weighted 300 m mean of 30 m pixels
+//	coarse := image.ReduceResolution(earthengine.ReducerMean(), 128).ScaleProjection(10)
+func (img *Image) ReduceResolution(reducer Reducer, maxPixels int) *Image {
+	reduceNodeID := img.expr.FunctionCall(AlgorithmImageReduceResolution, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"reducer": map[string]interface{}{
+			"valueReference": reducer.NodeID(img.expr),
+		},
+		"maxPixels": map[string]interface{}{
+			"constantValue": maxPixels,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: reduceNodeID,
+	}
+}
+
+// ScaleProjection reprojects the image into its own projection with pixels
+// factor times larger (or smaller, for factor < 1) on each side. All bands
+// must share a projection.
+func (img *Image) ScaleProjection(factor float64) *Image {
+	projectionNodeID := img.expr.FunctionCall(AlgorithmImageProjection, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	})
+	scaledNodeID := img.expr.FunctionCall(AlgorithmProjectionScale, map[string]interface{}{
+		"projection": map[string]interface{}{
+			"valueReference": projectionNodeID,
+		},
+		"x": map[string]interface{}{
+			"constantValue": factor,
+		},
+		"y": map[string]interface{}{
+			"constantValue": factor,
+		},
+	})
+	reprojectNodeID := img.expr.FunctionCall(AlgorithmImageReproject, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"crs": map[string]interface{}{
+			"valueReference": scaledNodeID,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: reprojectNodeID,
+	}
+}
+
+// Resample sets the interpolation used when the image is reprojected, either
+// "bilinear" or "bicubic". Images use nearest neighbor by default.
+func (img *Image) Resample(mode string) *Image {
+	resampleNodeID := img.expr.FunctionCall(AlgorithmImageResample, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"mode": map[string]interface{}{
+			"constantValue": mode,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: resampleNodeID,
+	}
+}
+
+// ClipToBoundsAndScale clips the image to the bounding box of a geometry and
+// resamples it so that its larger side is maxDimension pixels.
+func (img *Image) ClipToBoundsAndScale(geom Geometry, maxDimension int) *Image {
+	clipNodeID := img.expr.FunctionCall(AlgorithmImageClipToBoundsAndScale, map[string]interface{}{
+		"input": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"geometry": map[string]interface{}{
+			"valueReference": geom.NodeID(img.expr),
+		},
+		"maxDimension": map[string]interface{}{
+			"constantValue": maxDimension,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: clipNodeID,
+	}
+}
+
+// Visualize renders the image as a 3-band, 8-bit RGB image. params are the
+// Image.visualize arguments, such as "bands", "min", "max", "palette", and
+// "gamma".
+//
+// Example:
+//
+//	rgb := image.Visualize(map[string]interface{}{
+//	    "bands": []string{"B4", "B3", "B2"},
+//	    "min":   0,
+//	    "max":   3000,
+//	})
+func (img *Image) Visualize(params map[string]interface{}) *Image {
+	args := map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+	}
+	for name, value := range params {
+		args[name] = map[string]interface{}{
+			"constantValue": value,
+		}
+	}
+
+	visNodeID := img.expr.FunctionCall(AlgorithmImageVisualize, args)
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: visNodeID,
+	}
+}
+
 // NormalizedDifference computes the normalized difference between two bands: (b1 - b2) / (b1 + b2).
 // This is commonly used for vegetation indices (NDVI), water indices (NDWI), etc.
 //
@@ -261,7 +1017,7 @@ func (img *Image) Expression(expression string, vars map[string]interface{}) *Im
 	for k, v := range vars {
 		if imgVar, ok := v.(*Image); ok {
 			varRefs[k] = map[string]interface{}{
-				"valueReference": imgVar.nodeID,
+				"valueReference": img.ref(imgVar),
 			}
 		} else {
 			varRefs[k] = map[string]interface{}{
@@ -287,6 +1043,128 @@ func (img *Image) Expression(expression string, vars map[string]interface{}) *Im
 	}
 }
 
+// FocalMax replaces each pixel with the maximum of its neighborhood, a
+// kernel of radius pixels. kernelType is "plus" (the pixel and its four
+// edge neighbors at radius 1) or "square".
+func (img *Image) FocalMax(radius float64, kernelType string) *Image {
+	return img.focal(AlgorithmImageFocalMax, radius, kernelType)
+}
+
+// FocalMin replaces each pixel with the minimum of its neighborhood; see
+// FocalMax.
+func (img *Image) FocalMin(radius float64, kernelType string) *Image {
+	return img.focal(AlgorithmImageFocalMin, radius, kernelType)
+}
+
+// FocalMedian replaces each pixel with the median of its neighborhood; see
+// FocalMax. It is a simple speckle filter for radar images.
+func (img *Image) FocalMedian(radius float64, kernelType string) *Image {
+	return img.focal(AlgorithmImageFocalMedian, radius, kernelType)
+}
+
+// focal applies a focal (neighborhood) algorithm with a kernel in pixels.
+func (img *Image) focal(algorithm string, radius float64, kernelType string) *Image {
+	focalNodeID := img.expr.FunctionCall(algorithm, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"radius": map[string]interface{}{
+			"constantValue": radius,
+		},
+		"kernelType": map[string]interface{}{
+			"constantValue": kernelType,
+		},
+		"units": map[string]interface{}{
+			"constantValue": "pixels",
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: focalNodeID,
+	}
+}
+
+// ConnectedComponents labels each connected region of equal-valued pixels
+// with a unique ID in a "labels" band, added to the input bands. Pixels are
+// connected through their edges, or also their corners if eightConnected is
+// set. Regions larger than maxSize pixels (at most 1024) are left unlabeled.
+//
+// Example:
+//
+//	// Forest patches, counting diagonal neighbors as connected
+//	patches := landCover.UpdateMask(landCover.Eq(41)).
+//	    ConnectedComponents(true, 1024).
+//	    Select("labels")
+func (img *Image) ConnectedComponents(eightConnected bool, maxSize int) *Image {
+	kernel := AlgorithmKernelPlus
+	if eightConnected {
+		kernel = AlgorithmKernelSquare
+	}
+	kernelNodeID := img.expr.FunctionCall(kernel, map[string]interface{}{
+		"radius": map[string]interface{}{
+			"constantValue": 1,
+		},
+		"units": map[string]interface{}{
+			"constantValue": "pixels",
+		},
+	})
+
+	componentsNodeID := img.expr.FunctionCall(AlgorithmImageConnectedComponents, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"connectedness": map[string]interface{}{
+			"valueReference": kernelNodeID,
+		},
+		"maxSize": map[string]interface{}{
+			"constantValue": maxSize,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: componentsNodeID,
+	}
+}
+
+// NeighborhoodToBands turns the square neighborhood of radius pixels around
+// each pixel into bands: each input band b becomes (2*radius+1)^2 bands named
+// "b_x_y", where x is the column (0 at the west edge) and y the row (0 at the
+// north edge) of the neighbor, so "b_<radius>_<radius>" is the pixel itself.
+//
+// Example:
+//
+//	// Elevation of each pixel's eastern neighbor
+//	east := dem.Rename("z").NeighborhoodToBands(1).Select("z_2_1")
+func (img *Image) NeighborhoodToBands(radius int) *Image {
+	kernelNodeID := img.expr.FunctionCall(AlgorithmKernelSquare, map[string]interface{}{
+		"radius": map[string]interface{}{
+			"constantValue": radius,
+		},
+		"units": map[string]interface{}{
+			"constantValue": "pixels",
+		},
+	})
+
+	neighborhoodNodeID := img.expr.FunctionCall(AlgorithmImageNeighborhoodToBands, map[string]interface{}{
+		"image": map[string]interface{}{
+			"valueReference": img.nodeID,
+		},
+		"kernel": map[string]interface{}{
+			"valueReference": kernelNodeID,
+		},
+	})
+
+	return &Image{
+		client: img.client,
+		expr:   img.expr,
+		nodeID: neighborhoodNodeID,
+	}
+}
+
 // Terrain applies a terrain algorithm to an elevation image.
 // Use AlgorithmTerrainSlope or AlgorithmTerrainAspect as the algorithm parameter.
 func (img *Image) Terrain(algorithm string) *Image {
@@ -1,6 +1,10 @@
 package earthengine
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
 
 // ImageCollection represents an Earth Engine ImageCollection with chainable operations.
 type ImageCollection struct {
@@ -29,6 +33,56 @@ func (c *Client) ImageCollection(collectionID string) *ImageCollection {
 	}
 }
 
+// ImageCollectionFromImages creates a collection of the given images, in
+// order. The collection has no ID.
+//
+// Example:
+//
+//	elevation := client.ImageCollectionFromImages(
+//	    client.Image("USGS/SRTMGL1_003"),
+//	    client.Image("USGS/3DEP/10m"),
+//	).Mosaic()
+func (c *Client) ImageCollectionFromImages(images ...*Image) *ImageCollection {
+	expr := NewExpressionBuilder()
+
+	refs := make([]interface{}, len(images))
+	for i, img := range images {
+		refs[i] = map[string]interface{}{
+			"valueReference": expr.Import(img.expr, img.nodeID),
+		}
+	}
+
+	fromNodeID := expr.FunctionCall(AlgorithmImageCollectionFromImages, map[string]interface{}{
+		"images": map[string]interface{}{
+			"arrayValue": map[string]interface{}{
+				"values": refs,
+			},
+		},
+	})
+
+	return &ImageCollection{
+		client: c,
+		expr:   expr,
+		nodeID: fromNodeID,
+	}
+}
+
+// ID returns the Earth Engine ID of the collection, e.g. "COPERNICUS/S2_SR_HARMONIZED".
+func (ic *ImageCollection) ID() string {
+	return ic.collectionID
+}
+
+// Serialize returns the collection's expression graph as JSON, in the form of
+// the REST API's Expression. Use it to build apiv1 requests, such as video
+// exports, for the collection.
+func (ic *ImageCollection) Serialize() ([]byte, error) {
+	expr := ic.expr.Build(ic.nodeID)
+	return json.Marshal(map[string]interface{}{
+		"result": expr.result,
+		"values": expr.values,
+	})
+}
+
 // First returns the first image from the collection as an Image.
 // This is useful for collections sorted by date or other criteria.
 func (ic *ImageCollection) First() *Image {
@@ -119,6 +173,73 @@ func (ic *ImageCollection) FilterMetadata(property string, operator string, valu
 	}
 }
 
+// FilterBounds filters the collection to images that intersect geom.
+//
+// Example:
+//
+//	collection := client.ImageCollection("LANDSAT/LC09/C02/T1_L2").
+//	    FilterBounds(earthengine.NewRectangle(-122.84, 45.43, -122.47, 45.65))
+func (ic *ImageCollection) FilterBounds(geom Geometry) *ImageCollection {
+	filterNodeID := ic.expr.FunctionCall(AlgorithmFilterIntersects, map[string]interface{}{
+		"leftField": map[string]interface{}{
+			"constantValue": ".geo",
+		},
+		"rightValue": map[string]interface{}{
+			"valueReference": geom.NodeID(ic.expr),
+		},
+	})
+
+	collectionNodeID := ic.expr.FunctionCall(AlgorithmCollectionFilter, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"filter": map[string]interface{}{
+			"valueReference": filterNodeID,
+		},
+	})
+
+	return &ImageCollection{
+		client:       ic.client,
+		expr:         ic.expr,
+		collectionID: ic.collectionID,
+		nodeID:       collectionNodeID,
+	}
+}
+
+// FilterListContains filters the collection to images whose list-valued
+// property contains value.
+//
+// Example:
+//
+//	collection := client.ImageCollection("COPERNICUS/S1_GRD").
+//	    FilterListContains("transmitterReceiverPolarisation", "VH")
+func (ic *ImageCollection) FilterListContains(property string, value interface{}) *ImageCollection {
+	filterNodeID := ic.expr.FunctionCall(AlgorithmFilterListContains, map[string]interface{}{
+		"leftField": map[string]interface{}{
+			"constantValue": property,
+		},
+		"rightValue": map[string]interface{}{
+			"constantValue": value,
+		},
+	})
+
+	collectionNodeID := ic.expr.FunctionCall(AlgorithmCollectionFilter, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"filter": map[string]interface{}{
+			"valueReference": filterNodeID,
+		},
+	})
+
+	return &ImageCollection{
+		client:       ic.client,
+		expr:         ic.expr,
+		collectionID: ic.collectionID,
+		nodeID:       collectionNodeID,
+	}
+}
+
 // FilterByYear filters the collection to images from a specific year.
 // This is a convenience method for NLCD and other annual datasets.
 func (ic *ImageCollection) FilterByYear(year int) *ImageCollection {
@@ -203,3 +324,236 @@ func (ic *ImageCollection) Mosaic() *Image {
 		nodeID: mosaicNodeID,
 	}
 }
+
+// Map applies fn to every image in the collection.
+//
+// fn is called once, client-side, with a placeholder image standing for each
+// element; the operations it builds are evaluated server-side per image.
+//
+// Example:
+//
+//	masked := collection.Map(func(img *earthengine.Image) *earthengine.Image {
+//	    return img.UpdateMask(img.Select("QA_PIXEL").BitwiseAnd(1 << 3).Eq(0))
+//	})
+func (ic *ImageCollection) Map(fn func(*Image) *Image) *ImageCollection {
+	argName, argNodeID := ic.expr.Argument()
+	element := &Image{
+		client: ic.client,
+		expr:   ic.expr,
+		nodeID: argNodeID,
+	}
+
+	body := fn(element)
+	functionNodeID := ic.expr.FunctionDefinition([]string{argName}, element.ref(body))
+
+	mapNodeID := ic.expr.FunctionCall(AlgorithmCollectionMap, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"baseAlgorithm": map[string]interface{}{
+			"valueReference": functionNodeID,
+		},
+	})
+
+	return &ImageCollection{
+		client:       ic.client,
+		expr:         ic.expr,
+		collectionID: ic.collectionID,
+		nodeID:       mapNodeID,
+	}
+}
+
+// Merge returns a collection containing the images of this collection
+// followed by those of other.
+//
+// The merged collection keeps this collection's ID. Images are not
+// harmonized, so both collections should share band names for most uses.
+//
+// Example:
+//
+//	landsat := l8.Merge(l9)
+func (ic *ImageCollection) Merge(other *ImageCollection) *ImageCollection {
+	mergeNodeID := ic.expr.FunctionCall(AlgorithmImageCollectionMerge, map[string]interface{}{
+		"collection1": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"collection2": map[string]interface{}{
+			"valueReference": ic.expr.Import(other.expr, other.nodeID),
+		},
+	})
+
+	return &ImageCollection{
+		client:       ic.client,
+		expr:         ic.expr,
+		collectionID: ic.collectionID,
+		nodeID:       mergeNodeID,
+	}
+}
+
+// FromImages returns a collection of the given images, typically images
+// derived from this collection such as per-date mosaics. It is
+// Client.ImageCollectionFromImages, except that the new collection keeps
+// this collection's ID, so dataset-specific helpers (cloud masking, band
+// names) still apply to it.
+//
+// Example:
+//
+//	june := collection.FilterDate("2023-06-01", "2023-07-01").Mosaic()
+//	july := collection.FilterDate("2023-07-01", "2023-08-01").Mosaic()
+//	monthly := collection.FromImages(june, july)
+func (ic *ImageCollection) FromImages(images ...*Image) *ImageCollection {
+	collection := ic.client.ImageCollectionFromImages(images...)
+	collection.collectionID = ic.collectionID
+	return collection
+}
+
+// Sort sorts the collection by a property, e.g. "system:time_start".
+func (ic *ImageCollection) Sort(property string, ascending bool) *ImageCollection {
+	sortNodeID := ic.expr.FunctionCall(AlgorithmCollectionSort, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"key": map[string]interface{}{
+			"constantValue": property,
+		},
+		"ascending": map[string]interface{}{
+			"constantValue": ascending,
+		},
+	})
+
+	return &ImageCollection{
+		client:       ic.client,
+		expr:         ic.expr,
+		collectionID: ic.collectionID,
+		nodeID:       sortNodeID,
+	}
+}
+
+// QualityMosaic composites the collection by selecting, for each pixel, the
+// value from the image with the highest value in qualityBand.
+//
+// Example:
+//
+//	// Greenest pixel composite
+//	greenest := withNDVI.QualityMosaic("ndvi")
+func (ic *ImageCollection) QualityMosaic(qualityBand string) *Image {
+	mosaicNodeID := ic.expr.FunctionCall(AlgorithmImageCollectionQualityMosaic, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"qualityBand": map[string]interface{}{
+			"constantValue": qualityBand,
+		},
+	})
+
+	return &Image{
+		client: ic.client,
+		expr:   ic.expr,
+		nodeID: mosaicNodeID,
+	}
+}
+
+// Size computes the number of images in the collection.
+//
+// Example:
+//
+//	n, err := client.ImageCollection("COPERNICUS/S2_SR_HARMONIZED").
+//	    FilterDate("2023-06-01", "2023-06-30").
+//	    Size(ctx)
+func (ic *ImageCollection) Size(ctx context.Context) (int, error) {
+	sizeNodeID := ic.expr.FunctionCall(AlgorithmCollectionSize, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+	})
+
+	result, err := ic.client.ComputeValue(ctx, ic.expr.Build(sizeNodeID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute collection size: %w", err)
+	}
+
+	size, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected collection size type: %T", result)
+	}
+
+	return int(size), nil
+}
+
+// AggregateArray returns the value of a metadata property for every image in
+// the collection, in collection order. Images without the property are
+// skipped. No pixel data is computed.
+//
+// Example:
+//
+//	times, err := client.ImageCollection("LANDSAT/LC09/C02/T1_L2").
+//	    FilterDate("2023-06-01", "2023-07-01").
+//	    AggregateArray(ctx, "system:time_start")
+func (ic *ImageCollection) AggregateArray(ctx context.Context, property string) ([]interface{}, error) {
+	arrayNodeID := ic.expr.FunctionCall(AlgorithmAggregateArray, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"property": map[string]interface{}{
+			"constantValue": property,
+		},
+	})
+
+	result, err := ic.client.ComputeValue(ctx, ic.expr.Build(arrayNodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate %s: %w", property, err)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected aggregate result type: %T", result)
+	}
+
+	return values, nil
+}
+
+// GetRegion computes the pixel values of every image in the collection within geom.
+//
+// The first row is a header: "id", "longitude", "latitude", "time", followed
+// by the band names. Each following row holds one pixel of one image, with
+// time in milliseconds since the Unix epoch. Masked values are nil.
+//
+// Example:
+//
+//	rows, err := client.ImageCollection("UCSB-CHG/CHIRPS/DAILY").
+//	    FilterDate("2023-06-01", "2023-07-01").
+//	    GetRegion(ctx, earthengine.NewPoint(-122.68, 45.52), 5000)
+func (ic *ImageCollection) GetRegion(ctx context.Context, geom Geometry, scale float64) ([][]interface{}, error) {
+	regionNodeID := ic.expr.FunctionCall(AlgorithmImageCollectionGetRegion, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"valueReference": ic.nodeID,
+		},
+		"geometry": map[string]interface{}{
+			"valueReference": geom.NodeID(ic.expr),
+		},
+		"scale": map[string]interface{}{
+			"constantValue": scale,
+		},
+	})
+
+	result, err := ic.client.ComputeValue(ctx, ic.expr.Build(regionNodeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute region: %w", err)
+	}
+
+	list, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected region result type: %T", result)
+	}
+
+	rows := make([][]interface{}, len(list))
+	for i, item := range list {
+		row, ok := item.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected region row type: %T", item)
+		}
+		rows[i] = row
+	}
+
+	return rows, nil
+}
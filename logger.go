@@ -0,0 +1,168 @@
+package earthengine
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Event names passed to Logger.Log by the client.
+const (
+	// EventRequestStart is logged before each API request, with the API
+	// "method", "request_bytes", and for compute requests the "datasets"
+	// loaded and the "scales" requested by the expression.
+	EventRequestStart = "request start"
+
+	// EventRequestEnd is logged after each API request, with the API
+	// "method", "status", "latency", "response_bytes", and "error" if the
+	// request failed.
+	EventRequestEnd = "request end"
+)
+
+// Logger receives structured diagnostic events, such as the start and end of
+// each API request. attrs alternate between string keys and values, as in
+// log/slog. Implementations must be safe for concurrent use.
+type Logger interface {
+	Log(ctx context.Context, event string, attrs ...interface{})
+}
+
+// nopLogger discards all events.
+type nopLogger struct{}
+
+func (nopLogger) Log(context.Context, string, ...interface{}) {}
+
+// NopLogger returns a Logger that discards all events. It is the default.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// slogLogger adapts a *slog.Logger to Logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that writes events to logger, at debug level
+// or at warn level for events that carry an "error".
+//
+// Example:
+//
+//	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+//	client, err := earthengine.NewClient(ctx,
+//	    earthengine.WithProject("my-project"),
+//	    earthengine.WithServiceAccountEnv(),
+//	    earthengine.WithLogger(earthengine.NewSlogLogger(slog.New(handler))),
+//	)
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) Log(ctx context.Context, event string, attrs ...interface{}) {
+	level := slog.LevelDebug
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i] == "error" {
+			level = slog.LevelWarn
+			break
+		}
+	}
+	l.logger.Log(ctx, level, event, attrs...)
+}
+
+// WithLogger sets a logger for request events. Helpers that run many queries,
+// such as helpers.Batch, log to the client's logger too.
+//
+// Example:
+//
+//	client, err := earthengine.NewClient(ctx,
+//	    earthengine.WithProject("my-project"),
+//	    earthengine.WithServiceAccountEnv(),
+//	    earthengine.WithLogger(earthengine.NewSlogLogger(slog.Default())),
+//	)
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// Logger returns the client's logger, or a no-op logger if none was set.
+func (c *Client) Logger() Logger {
+	if c.logger == nil {
+		return nopLogger{}
+	}
+	return c.logger
+}
+
+// expressionAttrs returns log attributes describing expr: the IDs of the
+// assets it loads and the scales it requests.
+func expressionAttrs(expr *Expression) []interface{} {
+	if expr == nil {
+		return nil
+	}
+
+	datasets := make(map[string]bool)
+	scales := make(map[float64]bool)
+	for _, value := range expr.values {
+		node, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		invocation, ok := node["functionInvocationValue"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := invocation["functionName"].(string)
+		args, _ := invocation["arguments"].(map[string]interface{})
+
+		if strings.HasSuffix(name, ".load") {
+			if id, ok := expr.constantArgument(args["id"]).(string); ok {
+				datasets[id] = true
+			}
+		}
+		if scale, ok := expr.constantArgument(args["scale"]).(float64); ok {
+			scales[scale] = true
+		}
+	}
+
+	var attrs []interface{}
+	if len(datasets) > 0 {
+		ids := make([]string, 0, len(datasets))
+		for id := range datasets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		attrs = append(attrs, "datasets", ids)
+	}
+	if len(scales) > 0 {
+		values := make([]float64, 0, len(scales))
+		for scale := range scales {
+			values = append(values, scale)
+		}
+		sort.Float64s(values)
+		attrs = append(attrs, "scales", values)
+	}
+	return attrs
+}
+
+// constantArgument returns the constant value of a function argument, which
+// is either a constant or a reference to a constant node, or nil.
+func (e *Expression) constantArgument(arg interface{}) interface{} {
+	node, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if ref, ok := node["valueReference"].(string); ok {
+		if node, ok = e.values[ref].(map[string]interface{}); !ok {
+			return nil
+		}
+	}
+
+	switch v := node["constantValue"].(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,142 @@
+package earthengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// loggedEvent is an event captured by recordingLogger.
+type loggedEvent struct {
+	name  string
+	attrs map[string]interface{}
+}
+
+// recordingLogger records events for inspection.
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []loggedEvent
+}
+
+func (l *recordingLogger) Log(ctx context.Context, event string, attrs ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	m := make(map[string]interface{})
+	for i := 0; i+1 < len(attrs); i += 2 {
+		m[attrs[i].(string)] = attrs[i+1]
+	}
+	l.events = append(l.events, loggedEvent{name: event, attrs: m})
+}
+
+func TestWithLogger(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"result": 12.5}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := &Client{
+		httpClient: server.Client(),
+		projectID:  "test-project",
+		baseURL:    server.URL,
+	}
+	if err := WithLogger(logger)(client); err != nil {
+		t.Fatalf("WithLogger failed: %v", err)
+	}
+
+	expr := NewExpression()
+	imageID := expr.AddFunctionCall(AlgorithmImageLoad, map[string]interface{}{
+		"id": map[string]interface{}{"constantValue": "USGS/SRTMGL1_003"},
+	})
+	scaleID := expr.AddConstant(30)
+	expr.SetResult(expr.AddFunctionCall(AlgorithmImageReduceRegion, map[string]interface{}{
+		"image": map[string]interface{}{"valueReference": imageID},
+		"scale": map[string]interface{}{"valueReference": scaleID},
+	}))
+
+	ctx := context.Background()
+	if _, err := client.ComputeValue(ctx, expr); err != nil {
+		t.Fatalf("ComputeValue failed: %v", err)
+	}
+
+	if len(logger.events) != 2 {
+		t.Fatalf("got %d events, want 2", len(logger.events))
+	}
+	start, end := logger.events[0], logger.events[1]
+	if start.name != EventRequestStart || end.name != EventRequestEnd {
+		t.Errorf("events = %q, %q, want %q, %q", start.name, end.name, EventRequestStart, EventRequestEnd)
+	}
+	if got := start.attrs["method"]; got != "value:compute" {
+		t.Errorf("method = %v, want value:compute", got)
+	}
+	if got := start.attrs["datasets"]; !reflect.DeepEqual(got, []string{"USGS/SRTMGL1_003"}) {
+		t.Errorf("datasets = %v, want [USGS/SRTMGL1_003]", got)
+	}
+	if got := start.attrs["scales"]; !reflect.DeepEqual(got, []float64{30}) {
+		t.Errorf("scales = %v, want [30]", got)
+	}
+	if got := end.attrs["status"]; got != http.StatusOK {
+		t.Errorf("status = %v, want 200", got)
+	}
+	if got := end.attrs["response_bytes"]; got != len(`{"result": 12.5}`) {
+		t.Errorf("response_bytes = %v, want %d", got, len(`{"result": 12.5}`))
+	}
+	if _, ok := end.attrs["latency"]; !ok {
+		t.Error("end event has no latency")
+	}
+	if _, ok := end.attrs["error"]; ok {
+		t.Errorf("successful request logged error %v", end.attrs["error"])
+	}
+
+	// Failed requests carry the error
+	status = http.StatusBadRequest
+	if _, err := client.ComputeValue(ctx, expr); err == nil {
+		t.Fatal("ComputeValue succeeded, want error")
+	}
+	failed := logger.events[len(logger.events)-1]
+	var apiErr *APIError
+	if err, _ := failed.attrs["error"].(error); !errors.As(err, &apiErr) {
+		t.Errorf("error = %v, want *APIError", failed.attrs["error"])
+	}
+	if got := failed.attrs["status"]; got != http.StatusBadRequest {
+		t.Errorf("status = %v, want 400", got)
+	}
+}
+
+func TestClientLoggerDefault(t *testing.T) {
+	client := &Client{}
+	if _, ok := client.Logger().(nopLogger); !ok {
+		t.Errorf("Logger() = %T, want no-op logger", client.Logger())
+	}
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := NewSlogLogger(slog.New(handler))
+
+	ctx := context.Background()
+	logger.Log(ctx, EventRequestStart, "method", "value:compute")
+	logger.Log(ctx, EventRequestEnd, "method", "value:compute", "error", errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "level=DEBUG") || !strings.Contains(lines[0], "method=value:compute") {
+		t.Errorf("start line = %q, want debug level with method", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=WARN") || !strings.Contains(lines[1], "error=boom") {
+		t.Errorf("end line = %q, want warn level with error", lines[1])
+	}
+}
@@ -50,3 +50,163 @@ func ReducerMax() Reducer {
 func ReducerCount() Reducer {
 	return SimpleReducer{algorithmName: AlgorithmReducerCount}
 }
+
+// ReducerStdDev returns a reducer that calculates the standard deviation.
+func ReducerStdDev() Reducer {
+	return SimpleReducer{algorithmName: AlgorithmReducerStdDev}
+}
+
+// ReducerVariance returns a reducer that calculates the variance.
+func ReducerVariance() Reducer {
+	return SimpleReducer{algorithmName: AlgorithmReducerVariance}
+}
+
+// ReducerFrequencyHistogram returns a reducer that counts the pixels of each
+// distinct value. The output is a dictionary from value (as a string) to count.
+func ReducerFrequencyHistogram() Reducer {
+	return SimpleReducer{algorithmName: AlgorithmReducerFrequencyHistogram}
+}
+
+// ReducerMode returns a reducer that finds the most common value, such as the
+// majority class of a categorical image.
+func ReducerMode() Reducer {
+	return SimpleReducer{algorithmName: AlgorithmReducerMode}
+}
+
+// ReducerFirstNonNull returns a reducer that gets the first value that is not
+// null. Its output is named "first".
+func ReducerFirstNonNull() Reducer {
+	return SimpleReducer{algorithmName: AlgorithmReducerFirstNonNull}
+}
+
+// PercentileReducer computes one or more percentiles.
+type PercentileReducer struct {
+	percentiles []float64
+}
+
+// ReducerPercentile returns a reducer that computes the given percentiles (0-100).
+// Outputs are named "p<percentile>" (for example "p90").
+//
+// Example:
+//
+//	reducer := earthengine.ReducerPercentile(10, 50, 90)
+func ReducerPercentile(percentiles ...float64) Reducer {
+	return PercentileReducer{percentiles: percentiles}
+}
+
+// NodeID implements the Reducer interface for PercentileReducer.
+func (r PercentileReducer) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmReducerPercentile, map[string]interface{}{
+		"percentiles": map[string]interface{}{
+			"constantValue": r.percentiles,
+		},
+	})
+}
+
+// UnweightedReducer applies a reducer with every input pixel given equal weight.
+type UnweightedReducer struct {
+	reducer Reducer
+}
+
+// ReducerUnweighted returns reducer with pixel weights ignored. By default,
+// region reductions weight each pixel by the fraction of it inside the region;
+// unweighted, a pixel counts in full if its center is inside and not at all
+// otherwise.
+//
+// Example:
+//
+//	reducer := earthengine.ReducerUnweighted(earthengine.ReducerMean())
+func ReducerUnweighted(reducer Reducer) Reducer {
+	return UnweightedReducer{reducer: reducer}
+}
+
+// NodeID implements the Reducer interface for UnweightedReducer.
+func (r UnweightedReducer) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmReducerUnweighted, map[string]interface{}{
+		"reducer": map[string]interface{}{
+			"valueReference": r.reducer.NodeID(expr),
+		},
+	})
+}
+
+// CombinedReducer runs several reducers over the same inputs in a single pass.
+type CombinedReducer struct {
+	reducers []Reducer
+}
+
+// ReducerCombine returns a reducer that applies all of the given reducers to
+// shared inputs. Outputs are named "<band>_<reducer>" (for example "B4_mean").
+//
+// Example:
+//
+//	reducer := earthengine.ReducerCombine(earthengine.ReducerMean(), earthengine.ReducerStdDev())
+func ReducerCombine(reducers ...Reducer) Reducer {
+	if len(reducers) == 1 {
+		return reducers[0]
+	}
+	return CombinedReducer{reducers: reducers}
+}
+
+// NodeID implements the Reducer interface for CombinedReducer.
+func (r CombinedReducer) NodeID(expr *ExpressionBuilder) string {
+	if len(r.reducers) == 0 {
+		return ReducerFirst().NodeID(expr)
+	}
+
+	nodeID := r.reducers[0].NodeID(expr)
+	for _, next := range r.reducers[1:] {
+		nodeID = expr.FunctionCall(AlgorithmReducerCombine, map[string]interface{}{
+			"reducer1": map[string]interface{}{
+				"valueReference": nodeID,
+			},
+			"reducer2": map[string]interface{}{
+				"valueReference": next.NodeID(expr),
+			},
+			"sharedInputs": map[string]interface{}{
+				"constantValue": true,
+			},
+		})
+	}
+
+	return nodeID
+}
+
+// GroupedReducer applies a reducer separately to each group of pixels that
+// share a value in a group band.
+type GroupedReducer struct {
+	reducer    Reducer
+	groupField int
+	groupName  string
+}
+
+// ReducerGroup returns a reducer that groups pixels by the value of input
+// groupField (the zero-based index of the band holding group values) and
+// applies reducer to the other inputs of each group. The output is a
+// "groups" list of dictionaries, each with the group value under groupName
+// and the reducer's outputs.
+//
+// Example:
+//
+//	// Area of each land cover class: {"groups": [{"class": 42, "sum": 1.2e6}, ...]}
+//	reducer := earthengine.ReducerGroup(earthengine.ReducerSum(), 1, "class")
+//	stats, err := landCover.PixelArea().AddBands(landCover).
+//	    ReduceRegion(region, reducer, earthengine.Scale(30)).
+//	    Compute(ctx)
+func ReducerGroup(reducer Reducer, groupField int, groupName string) Reducer {
+	return GroupedReducer{reducer: reducer, groupField: groupField, groupName: groupName}
+}
+
+// NodeID implements the Reducer interface for GroupedReducer.
+func (r GroupedReducer) NodeID(expr *ExpressionBuilder) string {
+	return expr.FunctionCall(AlgorithmReducerGroup, map[string]interface{}{
+		"reducer": map[string]interface{}{
+			"valueReference": r.reducer.NodeID(expr),
+		},
+		"groupField": map[string]interface{}{
+			"constantValue": r.groupField,
+		},
+		"groupName": map[string]interface{}{
+			"constantValue": r.groupName,
+		},
+	})
+}
@@ -2,9 +2,16 @@ package earthengine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 // TaskState represents the state of an async task.
@@ -29,10 +36,12 @@ type Task struct {
 	Type        string
 	Description string
 	State       TaskState
-	Progress    float64 // 0.0 to 1.0
+	Progress    float64 // Estimated, 0.0 to 1.0; see TaskProgress
 	StartTime   time.Time
 	UpdateTime  time.Time
 	Error       string
+	Stage       string                 // Operation state reported by Earth Engine, e.g. "RUNNING"
+	Metadata    map[string]interface{} // Operation metadata from the last status poll
 
 	// Internal
 	client          *Client
@@ -44,12 +53,20 @@ type Task struct {
 }
 
 // TaskProgress represents progress information for a task.
+//
+// Earth Engine does not report a reliable percentage for exports, so
+// Progress is an estimate: the operation's own progress or stage work units
+// when available, otherwise 0 while pending, 0.25 once running, and 1 on
+// success. It never decreases. Stage and Metadata carry the raw values it is
+// derived from.
 type TaskProgress struct {
 	TaskID      string
 	State       TaskState
 	Progress    float64
 	Description string
 	UpdateTime  time.Time
+	Stage       string                 // Operation state reported by Earth Engine, e.g. "RUNNING"
+	Metadata    map[string]interface{} // Operation metadata; do not modify
 }
 
 // TaskManager manages async tasks.
@@ -164,6 +181,8 @@ func (t *Task) WaitWithProgress(ctx context.Context, progressFn func(progress *T
 			progress := t.Progress
 			desc := t.Description
 			updateTime := t.UpdateTime
+			stage := t.Stage
+			metadata := t.Metadata
 			t.mu.RUnlock()
 
 			// Report progress
@@ -174,6 +193,8 @@ func (t *Task) WaitWithProgress(ctx context.Context, progressFn func(progress *T
 					Progress:    progress,
 					Description: desc,
 					UpdateTime:  updateTime,
+					Stage:       stage,
+					Metadata:    metadata,
 				})
 			}
 
@@ -193,22 +214,32 @@ func (t *Task) WaitWithProgress(ctx context.Context, progressFn func(progress *T
 }
 
 // Cancel cancels the task.
+//
+// For a task that tracks an Earth Engine operation, Cancel requests
+// cancellation and refreshes the task from the operation. Earth Engine
+// cancels asynchronously, so the task may still be running when Cancel
+// returns; use Wait, or Client.CancelOperation with waitForTerminal, to
+// confirm that it stopped. Other tasks are marked cancelled immediately.
 func (t *Task) Cancel(ctx context.Context) error {
+	return t.cancel(ctx, false)
+}
+
+// cancel cancels the task, waiting for its operation to reach a terminal
+// state if waitForTerminal is set.
+func (t *Task) cancel(ctx context.Context, waitForTerminal bool) error {
 	t.mu.Lock()
 	if t.cancelFunc != nil {
 		t.cancelFunc()
 	}
-	t.State = TaskStateCancelled
-	t.UpdateTime = time.Now()
-	t.mu.Unlock()
-
-	// Call Earth Engine API to cancel if operation name exists
-	if t.operationName != "" {
-		// Would call: t.client.Operations.Cancel(ctx, t.operationName)
-		// For now, just mark as cancelled locally
+	if t.client == nil || t.operationName == "" {
+		t.State = TaskStateCancelled
+		t.UpdateTime = time.Now()
+		t.mu.Unlock()
+		return nil
 	}
+	t.mu.Unlock()
 
-	return nil
+	return t.cancelOperation(ctx, waitForTerminal)
 }
 
 // GetProgress returns the current task progress.
@@ -222,15 +253,19 @@ func (t *Task) GetProgress() *TaskProgress {
 		Progress:    t.Progress,
 		Description: t.Description,
 		UpdateTime:  t.UpdateTime,
+		Stage:       t.Stage,
+		Metadata:    t.Metadata,
 	}
 }
 
 // updateStatus updates the task status from the server.
 func (t *Task) updateStatus(ctx context.Context) error {
-	// In a real implementation, this would call the Earth Engine API
-	// to get the current status of the operation
-	//
-	// For now, we'll simulate progress for demonstration purposes
+	if t.client != nil && t.operationName != "" {
+		return t.pollOperation(ctx)
+	}
+
+	// Tasks without an Earth Engine operation simulate progress for
+	// demonstration purposes
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -252,6 +287,352 @@ func (t *Task) updateStatus(ctx context.Context) error {
 	return nil
 }
 
+// OperationTask returns a task that tracks an Earth Engine long-running
+// operation, such as the one returned when an export is started.
+//
+// Example:
+//
+//	task := client.OperationTask("projects/my-project/operations/ABCDEF")
+//	err := task.WaitWithProgress(ctx, func(p *earthengine.TaskProgress) {
+//	    fmt.Printf("%s ~%.0f%%\n", p.Stage, p.Progress*100)
+//	})
+func (c *Client) OperationTask(operationName string) *Task {
+	now := time.Now()
+	return &Task{
+		ID:            operationName,
+		State:         TaskStatePending,
+		StartTime:     now,
+		UpdateTime:    now,
+		client:        c,
+		operationName: operationName,
+	}
+}
+
+// StartImageExport submits an image export, built for example with
+// helpers.NewExportImageRequest, and returns a task tracking the resulting
+// operation. The request's workload tag defaults to the one for ctx.
+//
+// Resubmitting a request with the same RequestId returns the original
+// operation rather than starting a second export.
+//
+// Example:
+//
+//	req, err := helpers.NewExportImageRequest(image, helpers.ExportToGCS("my-bucket", "ndvi/"))
+//	task, err := client.StartImageExport(ctx, req)
+//	err = task.Wait(ctx)
+func (c *Client) StartImageExport(ctx context.Context, req *apiv1.ExportImageRequest) (*Task, error) {
+	if req == nil {
+		return nil, fmt.Errorf("export request cannot be nil")
+	}
+
+	submitted := *req
+	if submitted.WorkloadTag == "" {
+		tag, err := c.workloadTagFor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		submitted.WorkloadTag = tag
+	}
+
+	task, err := c.startExport(ctx, "image:export", &submitted)
+	if err != nil {
+		return nil, err
+	}
+	task.Type = "EXPORT_IMAGE"
+	task.Description = req.Description
+	return task, nil
+}
+
+// StartVideoExport submits a video export, built for example with
+// helpers.ExportVideoAnimation, and returns a task tracking the resulting
+// operation. The request's workload tag defaults to the one for ctx.
+func (c *Client) StartVideoExport(ctx context.Context, req *apiv1.ExportVideoRequest) (*Task, error) {
+	if req == nil {
+		return nil, fmt.Errorf("export request cannot be nil")
+	}
+
+	submitted := *req
+	if submitted.WorkloadTag == "" {
+		tag, err := c.workloadTagFor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		submitted.WorkloadTag = tag
+	}
+
+	task, err := c.startExport(ctx, "video:export", &submitted)
+	if err != nil {
+		return nil, err
+	}
+	task.Type = "EXPORT_VIDEO"
+	task.Description = req.Description
+	return task, nil
+}
+
+// startExport posts an export request to method and returns a task for the
+// resulting operation.
+func (c *Client) startExport(ctx context.Context, method string, req interface{}) (*Task, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export request: %w", err)
+	}
+
+	body, err := c.post(ctx, fmt.Sprintf("%s/projects/%s/%s", c.baseURL, c.projectID, method), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start export: %w", err)
+	}
+
+	var op apiv1.Operation
+	if err := json.Unmarshal(body, &op); err != nil {
+		return nil, fmt.Errorf("failed to parse operation: %w", err)
+	}
+	if op.Name == "" {
+		return nil, fmt.Errorf("no operation name in export response")
+	}
+
+	task := c.OperationTask(op.Name)
+	task.applyOperation(&op)
+	return task, nil
+}
+
+// Cancellation polling for Client.CancelOperation and TaskManager.CancelAll.
+// Variables so tests can shorten them.
+var (
+	cancelPollInterval = 2 * time.Second
+	cancelTimeout      = 5 * time.Minute
+)
+
+// cancelAllConcurrency is the number of tasks TaskManager.CancelAll cancels
+// at once.
+const cancelAllConcurrency = 8
+
+// CancelOperation requests cancellation of an Earth Engine long-running
+// operation, such as an export, and returns its state.
+//
+// Earth Engine cancels asynchronously: a cancelled operation may keep running
+// for a while, or even complete, after the request is accepted. With
+// waitForTerminal, CancelOperation polls the operation until it is
+// cancelled, failed, or completed, giving up after five minutes or when ctx
+// is done, and returns the state it reached. Otherwise it returns the state
+// just after the request, usually TaskStateRunning while the operation stops.
+//
+// Cancelling an operation that has already finished is not an error; its
+// final state is returned.
+//
+// Example:
+//
+//	state, err := client.CancelOperation(ctx, "projects/my-project/operations/ABCDEF", true)
+//	if err == nil && state != earthengine.TaskStateCancelled {
+//	    fmt.Println("operation finished before it could be cancelled:", state)
+//	}
+func (c *Client) CancelOperation(ctx context.Context, operationName string, waitForTerminal bool) (TaskState, error) {
+	if operationName == "" {
+		return "", fmt.Errorf("operation name cannot be empty")
+	}
+
+	task := c.OperationTask(operationName)
+	err := task.cancelOperation(ctx, waitForTerminal)
+
+	task.mu.RLock()
+	defer task.mu.RUnlock()
+	return task.State, err
+}
+
+// cancelOperation requests cancellation of the task's operation and
+// refreshes the task from it, then polls until the operation reaches a
+// terminal state if waitForTerminal is set.
+func (t *Task) cancelOperation(ctx context.Context, waitForTerminal bool) error {
+	url := fmt.Sprintf("%s/%s:cancel", t.client.baseURL, t.operationName)
+	_, cancelErr := t.client.post(ctx, url, []byte("{}"))
+
+	// A cancel request for a finished operation fails; that is not an error
+	// if polling shows the operation stopped
+	if err := t.pollOperation(ctx); err != nil {
+		if cancelErr != nil {
+			return fmt.Errorf("failed to cancel operation: %w", cancelErr)
+		}
+		return err
+	}
+	if cancelErr != nil && !t.isTerminal() {
+		return fmt.Errorf("failed to cancel operation: %w", cancelErr)
+	}
+	if !waitForTerminal {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cancelTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cancelPollInterval)
+	defer ticker.Stop()
+
+	for !t.isTerminal() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("operation %s did not stop after cancellation: %w", t.operationName, ctx.Err())
+		case <-ticker.C:
+			if err := t.pollOperation(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isTerminal reports whether the task has completed, failed, or been
+// cancelled.
+func (t *Task) isTerminal() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	switch t.State {
+	case TaskStateCompleted, TaskStateFailed, TaskStateCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// pollOperation updates the task from its Earth Engine operation.
+func (t *Task) pollOperation(ctx context.Context) error {
+	body, err := t.client.get(ctx, fmt.Sprintf("%s/%s", t.client.baseURL, t.operationName))
+	if err != nil {
+		return fmt.Errorf("failed to get operation status: %w", err)
+	}
+
+	var op apiv1.Operation
+	if err := json.Unmarshal(body, &op); err != nil {
+		return fmt.Errorf("failed to parse operation: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.applyOperation(&op)
+	return nil
+}
+
+// applyOperation copies the state, metadata, and estimated progress of op
+// to the task. The caller must hold t.mu.
+func (t *Task) applyOperation(op *apiv1.Operation) {
+	stage, _ := op.Metadata["state"].(string)
+	t.Stage = stage
+	t.Metadata = op.Metadata
+
+	switch stage {
+	case "PENDING":
+		t.State = TaskStatePending
+	case "RUNNING", "CANCELLING":
+		t.State = TaskStateRunning
+	case "SUCCEEDED":
+		t.State = TaskStateCompleted
+	case "FAILED":
+		t.State = TaskStateFailed
+	case "CANCELLED":
+		t.State = TaskStateCancelled
+	}
+	if op.Done {
+		switch {
+		case op.Error != nil:
+			t.State = TaskStateFailed
+			t.Error = op.Error.Message
+		case stage == "":
+			t.State = TaskStateCompleted
+		}
+	}
+
+	if t.State == TaskStateCompleted {
+		t.Progress = 1.0
+	} else if progress, ok := estimateProgress(stage, op.Metadata); ok && progress > t.Progress {
+		t.Progress = progress
+	}
+
+	if desc, ok := op.Metadata["description"].(string); ok && desc != "" {
+		t.Description = desc
+	}
+	if opType, ok := op.Metadata["type"].(string); ok && opType != "" {
+		t.Type = opType
+	}
+	if start, err := time.Parse(time.RFC3339, stringValue(op.Metadata["startTime"])); err == nil {
+		t.StartTime = start
+	}
+	t.UpdateTime = time.Now()
+	if update, err := time.Parse(time.RFC3339, stringValue(op.Metadata["updateTime"])); err == nil {
+		t.UpdateTime = update
+	}
+}
+
+// estimateProgress estimates the completed fraction of an operation from its
+// state and metadata. It reports false if the state gives no estimate, for
+// example while cancelling.
+//
+// The operation's own progress is used when reported. Otherwise the work
+// units completed across its stages are scaled into 0.25-0.95, so a running
+// export never reads as finished; with neither, a running operation is at
+// 0.25.
+func estimateProgress(stage string, metadata map[string]interface{}) (float64, bool) {
+	switch stage {
+	case "PENDING":
+		return 0, true
+	case "SUCCEEDED":
+		return 1, true
+	case "RUNNING":
+	default:
+		return 0, false
+	}
+
+	if progress, ok := numberValue(metadata["progress"]); ok && progress > 0 {
+		return clampProgress(progress), true
+	}
+
+	stages, _ := metadata["stages"].([]interface{})
+	var complete, total float64
+	for _, s := range stages {
+		st, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		done, _ := numberValue(st["completeWorkUnits"])
+		units, _ := numberValue(st["totalWorkUnits"])
+		complete += done
+		total += units
+	}
+	if total > 0 {
+		return 0.25 + 0.7*clampProgress(complete/total), true
+	}
+
+	return 0.25, true
+}
+
+// numberValue returns v as a number. Earth Engine encodes 64-bit integers as
+// JSON strings.
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// stringValue returns v if it is a string, or "".
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// clampProgress limits p to 0-1.
+func clampProgress(p float64) float64 {
+	if p < 0 {
+		return 0
+	}
+	if p > 1 {
+		return 1
+	}
+	return p
+}
+
 // TaskFilter filters tasks by state.
 type TaskFilter struct {
 	States []TaskState
@@ -284,18 +665,39 @@ func (tm *TaskManager) FilterTasks(filter TaskFilter) []*Task {
 	return filtered
 }
 
-// CancelAll cancels all running tasks.
+// CancelAll cancels all pending and running tasks, a few at a time. Tasks that
+// track an Earth Engine operation are polled until the operation stops, as
+// with Client.CancelOperation, so those that did not fail are cancelled,
+// failed, or completed when CancelAll returns. All tasks are cancelled even if
+// some fail; the first error is returned.
 func (tm *TaskManager) CancelAll(ctx context.Context) error {
 	tasks := tm.FilterTasks(TaskFilter{
 		States: []TaskState{TaskStatePending, TaskStateRunning},
 	})
 
-	var firstErr error
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, cancelAllConcurrency)
 	for _, task := range tasks {
-		if err := task.Cancel(ctx); err != nil && firstErr == nil {
-			firstErr = err
-		}
+		wg.Add(1)
+		go func(task *Task) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := task.cancel(ctx, true); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("task %s: %w", task.ID, err)
+				}
+				mu.Unlock()
+			}
+		}(task)
 	}
+	wg.Wait()
 
 	return firstErr
 }
@@ -318,3 +720,164 @@ func (tm *TaskManager) Cleanup(olderThan time.Duration) {
 		}
 	}
 }
+
+// taskRecord is the saved form of a task.
+type taskRecord struct {
+	ID            string                 `json:"id"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Type          string                 `json:"type,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	State         TaskState              `json:"state"`
+	Progress      float64                `json:"progress"`
+	StartTime     time.Time              `json:"startTime"`
+	UpdateTime    time.Time              `json:"updateTime"`
+	Error         string                 `json:"error,omitempty"`
+	Stage         string                 `json:"stage,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// taskManagerState is the file format of SaveState and LoadState.
+type taskManagerState struct {
+	Tasks []taskRecord `json:"tasks"`
+}
+
+// SaveState writes every registered task, including its operation name and
+// last known state and metadata, to a JSON file at path. The file is
+// replaced atomically, so a crash never leaves it half written.
+//
+// Example:
+//
+//	// Persist tasks so exports can be tracked after a restart
+//	if err := tm.SaveState("tasks.json"); err != nil {
+//	    log.Printf("failed to save tasks: %v", err)
+//	}
+func (tm *TaskManager) SaveState(path string) error {
+	tasks := tm.ListTasks()
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	state := taskManagerState{Tasks: make([]taskRecord, len(tasks))}
+	for i, task := range tasks {
+		task.mu.RLock()
+		state.Tasks[i] = taskRecord{
+			ID:            task.ID,
+			OperationName: task.operationName,
+			Type:          task.Type,
+			Description:   task.Description,
+			State:         task.State,
+			Progress:      task.Progress,
+			StartTime:     task.StartTime,
+			UpdateTime:    task.UpdateTime,
+			Error:         task.Error,
+			Stage:         task.Stage,
+			Metadata:      task.Metadata,
+		}
+		task.mu.RUnlock()
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tasks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to save tasks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save tasks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save tasks: %w", err)
+	}
+
+	return nil
+}
+
+// LoadState registers the tasks saved by SaveState at path, replacing
+// registered tasks with the same IDs. Tasks with an operation name are
+// polled through the manager's client; call ReconcileWithServer to refresh
+// their states right away.
+//
+// A missing file is reported as an error matching os.ErrNotExist, which a
+// service starting for the first time can ignore.
+//
+// Example:
+//
+//	tm := earthengine.NewTaskManager(client)
+//	if err := tm.LoadState("tasks.json"); err != nil && !errors.Is(err, os.ErrNotExist) {
+//	    log.Fatal(err)
+//	}
+//	if err := tm.ReconcileWithServer(ctx); err != nil {
+//	    log.Printf("some tasks could not be refreshed: %v", err)
+//	}
+func (tm *TaskManager) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var state taskManagerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse task state %s: %w", path, err)
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for _, record := range state.Tasks {
+		if record.ID == "" {
+			return fmt.Errorf("failed to parse task state %s: task without an ID", path)
+		}
+		tm.tasks[record.ID] = &Task{
+			ID:            record.ID,
+			Type:          record.Type,
+			Description:   record.Description,
+			State:         record.State,
+			Progress:      record.Progress,
+			StartTime:     record.StartTime,
+			UpdateTime:    record.UpdateTime,
+			Error:         record.Error,
+			Stage:         record.Stage,
+			Metadata:      record.Metadata,
+			client:        tm.client,
+			operationName: record.OperationName,
+		}
+	}
+
+	return nil
+}
+
+// ReconcileWithServer refreshes every unfinished task that tracks an Earth
+// Engine operation from the Operations API, for example after LoadState.
+// Completed, failed, and cancelled tasks are left as they are. All tasks are
+// polled even if some fail; the first error is returned.
+func (tm *TaskManager) ReconcileWithServer(ctx context.Context) error {
+	tasks := tm.FilterTasks(TaskFilter{
+		States: []TaskState{TaskStatePending, TaskStateRunning},
+	})
+
+	var firstErr error
+	for _, task := range tasks {
+		if task.operationName == "" {
+			continue
+		}
+		if task.client == nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("task %s: no client to poll its operation", task.ID)
+			}
+			continue
+		}
+
+		if err := task.pollOperation(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("task %s: %w", task.ID, err)
+		}
+	}
+
+	return firstErr
+}
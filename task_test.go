@@ -2,8 +2,20 @@ package earthengine
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/alexscott64/go-earthengine/apiv1"
 )
 
 func TestNewTaskManager(t *testing.T) {
@@ -349,3 +361,442 @@ func TestTaskUpdateStatus(t *testing.T) {
 		t.Errorf("Task state = %s, want %s after progress complete", task.State, TaskStateCompleted)
 	}
 }
+
+func TestEstimateProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		stage    string
+		metadata map[string]interface{}
+		want     float64
+		ok       bool
+	}{
+		{"pending", "PENDING", nil, 0, true},
+		{"running without detail", "RUNNING", nil, 0.25, true},
+		{"reported progress", "RUNNING", map[string]interface{}{"progress": 0.6}, 0.6, true},
+		{"stage work units", "RUNNING", map[string]interface{}{
+			"stages": []interface{}{
+				map[string]interface{}{"completeWorkUnits": 1.0, "totalWorkUnits": "1"},
+				map[string]interface{}{"completeWorkUnits": 0.0, "totalWorkUnits": "1"},
+			},
+		}, 0.6, true},
+		{"succeeded", "SUCCEEDED", nil, 1, true},
+		{"cancelling", "CANCELLING", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := estimateProgress(tt.stage, tt.metadata)
+			if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("estimateProgress() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestOperationTaskPoll(t *testing.T) {
+	responses := []string{
+		`{"name": "projects/p/operations/OP", "metadata": {"state": "RUNNING", "description": "SRTM export",
+			"startTime": "2026-01-02T03:04:05.123Z", "progress": 0.4}}`,
+		`{"name": "projects/p/operations/OP", "metadata": {"state": "RUNNING"}}`,
+		`{"name": "projects/p/operations/OP", "done": true, "metadata": {"state": "SUCCEEDED"}}`,
+	}
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/projects/p/operations/OP" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(responses[calls]))
+		calls++
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL}
+	task := client.OperationTask("projects/p/operations/OP")
+	ctx := context.Background()
+
+	if err := task.updateStatus(ctx); err != nil {
+		t.Fatalf("updateStatus failed: %v", err)
+	}
+	progress := task.GetProgress()
+	if progress.State != TaskStateRunning || progress.Stage != "RUNNING" || progress.Progress != 0.4 {
+		t.Errorf("progress = %+v", progress)
+	}
+	if task.Description != "SRTM export" || task.StartTime.Year() != 2026 {
+		t.Errorf("Description = %q, StartTime = %v", task.Description, task.StartTime)
+	}
+
+	// Less detailed metadata must not move progress backwards
+	task.updateStatus(ctx)
+	if task.Progress != 0.4 {
+		t.Errorf("Progress = %v, want 0.4", task.Progress)
+	}
+
+	task.updateStatus(ctx)
+	if task.State != TaskStateCompleted || task.Progress != 1 {
+		t.Errorf("State = %s, Progress = %v, want completed at 1", task.State, task.Progress)
+	}
+}
+
+func TestOperationTaskFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"done": true, "metadata": {"state": "FAILED"}, "error": {"code": 3, "message": "Too many pixels"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL}
+	task := client.OperationTask("projects/p/operations/OP")
+	if err := task.updateStatus(context.Background()); err != nil {
+		t.Fatalf("updateStatus failed: %v", err)
+	}
+	if task.State != TaskStateFailed || task.Error != "Too many pixels" {
+		t.Errorf("State = %s, Error = %q", task.State, task.Error)
+	}
+}
+
+// newCancelTestClient serves operations that report "CANCELLING" for polls
+// after a cancel request and "CANCELLED" after that, or keep the state in
+// finished if set. It returns the client and a function reporting the cancel
+// requests received and the most that were in flight at once.
+func newCancelTestClient(t *testing.T, cancellingPolls int, finished map[string]string) (*Client, func() (int, int)) {
+	t.Helper()
+
+	var (
+		mu                         sync.Mutex
+		polls                      = make(map[string]int)
+		cancels, active, maxActive int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if r.Method == "POST" {
+			if !strings.HasSuffix(name, ":cancel") {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			mu.Lock()
+			cancels++
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+
+			if _, ok := finished[strings.TrimSuffix(name, ":cancel")]; ok {
+				http.Error(w, `{"error": {"code": 400, "message": "operation is done", "status": "FAILED_PRECONDITION"}}`, http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{}`))
+			return
+		}
+
+		if state, ok := finished[name]; ok {
+			fmt.Fprintf(w, `{"name": %q, "done": true, "metadata": {"state": %q}}`, name, state)
+			return
+		}
+		mu.Lock()
+		polls[name]++
+		cancelling := cancellingPolls < 0 || polls[name] <= cancellingPolls
+		mu.Unlock()
+		if cancelling {
+			fmt.Fprintf(w, `{"name": %q, "metadata": {"state": "CANCELLING"}}`, name)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q, "done": true, "metadata": {"state": "CANCELLED"}}`, name)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL}
+	return client, func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return cancels, maxActive
+	}
+}
+
+// shortenCancelPolling speeds up cancellation polling for a test.
+func shortenCancelPolling(t *testing.T, timeout time.Duration) {
+	interval, wait := cancelPollInterval, cancelTimeout
+	cancelPollInterval, cancelTimeout = time.Millisecond, timeout
+	t.Cleanup(func() { cancelPollInterval, cancelTimeout = interval, wait })
+}
+
+func TestCancelOperation(t *testing.T) {
+	shortenCancelPolling(t, time.Minute)
+	ctx := context.Background()
+	client, requests := newCancelTestClient(t, 2, map[string]string{"projects/p/operations/DONE": "SUCCEEDED"})
+
+	// Without waiting, the operation is still stopping
+	state, err := client.CancelOperation(ctx, "projects/p/operations/A", false)
+	if err != nil {
+		t.Fatalf("CancelOperation failed: %v", err)
+	}
+	if state != TaskStateRunning {
+		t.Errorf("state = %s, want %s", state, TaskStateRunning)
+	}
+
+	// Waiting polls until the operation is cancelled
+	state, err = client.CancelOperation(ctx, "projects/p/operations/B", true)
+	if err != nil {
+		t.Fatalf("CancelOperation failed: %v", err)
+	}
+	if state != TaskStateCancelled {
+		t.Errorf("state = %s, want %s", state, TaskStateCancelled)
+	}
+
+	// An operation that already finished reports its final state
+	state, err = client.CancelOperation(ctx, "projects/p/operations/DONE", true)
+	if err != nil {
+		t.Fatalf("CancelOperation of finished operation failed: %v", err)
+	}
+	if state != TaskStateCompleted {
+		t.Errorf("state = %s, want %s", state, TaskStateCompleted)
+	}
+
+	if cancels, _ := requests(); cancels != 3 {
+		t.Errorf("cancel requests = %d, want 3", cancels)
+	}
+	if _, err := client.CancelOperation(ctx, "", true); err == nil {
+		t.Error("expected error for empty operation name")
+	}
+}
+
+func TestCancelOperationTimeout(t *testing.T) {
+	shortenCancelPolling(t, 20*time.Millisecond)
+	client, _ := newCancelTestClient(t, -1, nil)
+
+	state, err := client.CancelOperation(context.Background(), "projects/p/operations/STUCK", true)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+	if state != TaskStateRunning {
+		t.Errorf("state = %s, want %s", state, TaskStateRunning)
+	}
+}
+
+func TestCancelAllConfirmsOperations(t *testing.T) {
+	shortenCancelPolling(t, time.Minute)
+	client, requests := newCancelTestClient(t, 1, map[string]string{"projects/p/operations/DONE": "SUCCEEDED"})
+	tm := NewTaskManager(client)
+
+	const count = 2 * cancelAllConcurrency
+	for i := 0; i < count; i++ {
+		task := client.OperationTask(fmt.Sprintf("projects/p/operations/OP%d", i))
+		task.State = TaskStateRunning
+		tm.RegisterTask(task)
+	}
+	done := client.OperationTask("projects/p/operations/DONE")
+	tm.RegisterTask(done)
+	local := &Task{ID: "local", State: TaskStatePending}
+	tm.RegisterTask(local)
+
+	if err := tm.CancelAll(context.Background()); err != nil {
+		t.Fatalf("CancelAll failed: %v", err)
+	}
+
+	for _, task := range tm.ListTasks() {
+		want := TaskStateCancelled
+		if task == done {
+			want = TaskStateCompleted
+		}
+		if task.State != want {
+			t.Errorf("task %s state = %s, want %s", task.ID, task.State, want)
+		}
+	}
+
+	cancels, maxActive := requests()
+	if cancels != count+1 {
+		t.Errorf("cancel requests = %d, want %d", cancels, count+1)
+	}
+	if maxActive > cancelAllConcurrency {
+		t.Errorf("%d cancels in flight, want at most %d", maxActive, cancelAllConcurrency)
+	}
+}
+
+func TestStartImageExport(t *testing.T) {
+	var got apiv1.ExportImageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/projects/p/image:export" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"name": "projects/p/operations/OP", "metadata": {"state": "PENDING"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL, workloadTag: "nightly"}
+	req := &apiv1.ExportImageRequest{Description: "SRTM export", RequestId: "req-1"}
+	task, err := client.StartImageExport(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartImageExport failed: %v", err)
+	}
+	if got.RequestId != "req-1" || got.WorkloadTag != "nightly" {
+		t.Errorf("RequestId = %q, WorkloadTag = %q", got.RequestId, got.WorkloadTag)
+	}
+	if req.WorkloadTag != "" {
+		t.Errorf("request was modified: WorkloadTag = %q", req.WorkloadTag)
+	}
+	if task.State != TaskStatePending || task.Description != "SRTM export" || task.operationName != "projects/p/operations/OP" {
+		t.Errorf("task = %+v", task)
+	}
+
+	if _, err := client.StartImageExport(context.Background(), nil); err == nil {
+		t.Error("expected error for nil request")
+	}
+}
+
+func TestStartVideoExport(t *testing.T) {
+	var got apiv1.ExportVideoRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/projects/p/video:export" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"name": "projects/p/operations/VIDEO", "metadata": {"state": "RUNNING"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL, workloadTag: "nightly"}
+	req := &apiv1.ExportVideoRequest{Description: "Time lapse", VideoOptions: &apiv1.VideoOptions{FramesPerSecond: 12}}
+	task, err := client.StartVideoExport(context.Background(), req)
+	if err != nil {
+		t.Fatalf("StartVideoExport failed: %v", err)
+	}
+	if got.WorkloadTag != "nightly" || got.VideoOptions.FramesPerSecond != 12 {
+		t.Errorf("request = %+v", got)
+	}
+	if task.Type != "EXPORT_VIDEO" || task.State != TaskStateRunning || task.operationName != "projects/p/operations/VIDEO" {
+		t.Errorf("task = %+v", task)
+	}
+}
+
+func TestTaskManagerSaveAndLoadState(t *testing.T) {
+	tm := NewTaskManager(nil)
+	client := &Client{projectID: "p"}
+
+	export := client.OperationTask("projects/p/operations/EXPORT1")
+	export.Description = "SRTM export"
+	export.State = TaskStateRunning
+	export.Progress = 0.4
+	export.Stage = "RUNNING"
+	export.Metadata = map[string]interface{}{"state": "RUNNING", "progress": 0.4}
+	tm.RegisterTask(export)
+	tm.RegisterTask(&Task{ID: "local", State: TaskStateCompleted, Progress: 1, UpdateTime: time.Now()})
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := tm.SaveState(path); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	restored := NewTaskManager(client)
+	if err := restored.LoadState(path); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if got := len(restored.ListTasks()); got != 2 {
+		t.Fatalf("got %d tasks, want 2", got)
+	}
+
+	task, err := restored.GetTask("projects/p/operations/EXPORT1")
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if task.operationName != "projects/p/operations/EXPORT1" || task.client != client {
+		t.Errorf("operation = %q, client = %p, want the saved operation polled with %p", task.operationName, task.client, client)
+	}
+	if task.Description != "SRTM export" || task.State != TaskStateRunning || task.Progress != 0.4 || task.Stage != "RUNNING" {
+		t.Errorf("task = %+v", task)
+	}
+	if task.Metadata["progress"] != 0.4 || !task.StartTime.Equal(export.StartTime) {
+		t.Errorf("Metadata = %v, StartTime = %v", task.Metadata, task.StartTime)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d files, want only the state file", len(entries))
+	}
+}
+
+func TestTaskManagerLoadStateErrors(t *testing.T) {
+	tm := NewTaskManager(nil)
+	dir := t.TempDir()
+
+	if err := tm.LoadState(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want os.ErrNotExist", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"tasks": [`), 0o644)
+	if err := tm.LoadState(invalid); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+
+	noID := filepath.Join(dir, "noid.json")
+	os.WriteFile(noID, []byte(`{"tasks": [{"state": "RUNNING"}]}`), 0o644)
+	if err := tm.LoadState(noID); err == nil {
+		t.Error("Expected error for task without an ID")
+	}
+}
+
+func TestTaskManagerReconcileWithServer(t *testing.T) {
+	var polled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polled = append(polled, r.URL.Path)
+		w.Write([]byte(`{"done": true, "metadata": {"state": "SUCCEEDED"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), projectID: "p", baseURL: server.URL}
+	tm := NewTaskManager(client)
+
+	running := client.OperationTask("projects/p/operations/RUNNING")
+	running.State = TaskStateRunning
+	tm.RegisterTask(running)
+
+	failed := client.OperationTask("projects/p/operations/FAILED")
+	failed.State = TaskStateFailed
+	tm.RegisterTask(failed)
+
+	tm.RegisterTask(&Task{ID: "local", State: TaskStatePending})
+
+	if err := tm.ReconcileWithServer(context.Background()); err != nil {
+		t.Fatalf("ReconcileWithServer failed: %v", err)
+	}
+	if len(polled) != 1 || polled[0] != "/projects/p/operations/RUNNING" {
+		t.Errorf("polled %v, want only the running operation", polled)
+	}
+	if running.State != TaskStateCompleted || failed.State != TaskStateFailed {
+		t.Errorf("states = %s, %s, want completed and unchanged failed", running.State, failed.State)
+	}
+}
+
+func TestTaskManagerConcurrentAccess(t *testing.T) {
+	tm := NewTaskManager(nil)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			tm.RegisterTask(&Task{ID: fmt.Sprintf("task-%d", i), State: TaskStateRunning})
+		}(i)
+		go func() {
+			defer wg.Done()
+			tm.ListTasks()
+		}()
+		go func() {
+			defer wg.Done()
+			if err := tm.SaveState(path); err != nil {
+				t.Errorf("SaveState failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(tm.ListTasks()); got != 20 {
+		t.Errorf("got %d tasks, want 20", got)
+	}
+}